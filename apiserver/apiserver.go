@@ -15,6 +15,7 @@ import (
 	"io/ioutil"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -32,7 +33,9 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -45,18 +48,22 @@ type CombinedBroker interface {
 
 func New(
 	conf config.Config,
+	offerings *config.OfferingStore,
+	reloader mgmtapi.ConfigReloader,
 	broker CombinedBroker,
 	componentName string,
 	mgmtapiLoggerFactory *loggerfactory.LoggerFactory,
 	serverLogger *log.Logger,
+	registry *metrics.Registry,
 ) *http.Server {
 	router := mux.NewRouter()
-	registerManagementAPI(broker, conf, mgmtapiLoggerFactory, router)
-	registerOSBAPI(broker, componentName, serverLogger, conf, router)
+	registerManagementAPI(broker, conf, offerings, reloader, mgmtapiLoggerFactory, registry, router)
+	registerOSBAPI(NewInstrumentedBroker(broker, registry, offerings), componentName, serverLogger, conf, router)
 
 	server := negroni.New(
 		negroni.NewRecovery(),
-		createNegroniLogger(serverLogger),
+		negroni.HandlerFunc(extractTraceContext),
+		createRequestLogger(serverLogger),
 		negroni.NewStatic(http.Dir("public")),
 		negroni.Wrap(router),
 	)
@@ -88,11 +95,14 @@ func registerOSBAPI(
 func registerManagementAPI(
 	broker CombinedBroker,
 	conf config.Config,
+	offerings *config.OfferingStore,
+	reloader mgmtapi.ConfigReloader,
 	mgmtapiLoggerFactory *loggerfactory.LoggerFactory,
+	registry *metrics.Registry,
 	router *mux.Router,
 ) {
 	mgmtAPIRouter := mux.NewRouter()
-	mgmtapi.AttachRoutes(mgmtAPIRouter, broker, conf.ServiceCatalog, mgmtapiLoggerFactory)
+	mgmtapi.AttachRoutes(mgmtAPIRouter, broker, offerings, reloader, registry, mgmtapiLoggerFactory)
 	authMiddleware := apiauth.NewWrapper(conf.Broker.Username, conf.Broker.Password).Wrap
 	mgmtAPIRouter.Use(authMiddleware)
 
@@ -103,7 +113,12 @@ func StartAndWait(conf config.Config, server *http.Server, logger *log.Logger, s
 	stopped := make(chan struct{})
 	signal.Notify(stopServer, os.Interrupt, syscall.SIGTERM)
 
-	go handleBrokerTerminationSignal(stopServer, conf, logger, server, stopped)
+	// requests are cancelled when the broker has finished shutting down, so
+	// that no service adapter is left running
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	server.BaseContext = func(net.Listener) context.Context { return baseCtx }
+
+	go handleBrokerTerminationSignal(stopServer, conf, logger, server, stopped, cancelRequests)
 
 	logger.Println("Listening on", server.Addr)
 	var err error
@@ -134,7 +149,7 @@ func StartAndWait(conf config.Config, server *http.Server, logger *log.Logger, s
 	return nil
 }
 
-func handleBrokerTerminationSignal(stopServer chan os.Signal, conf config.Config, logger *log.Logger, server *http.Server, stopped chan struct{}) {
+func handleBrokerTerminationSignal(stopServer chan os.Signal, conf config.Config, logger *log.Logger, server *http.Server, stopped chan struct{}, cancelRequests context.CancelFunc) {
 	<-stopServer
 
 	timeoutSecs := conf.Broker.ShutdownTimeoutSecs
@@ -147,10 +162,11 @@ func handleBrokerTerminationSignal(stopServer chan os.Signal, conf config.Config
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		logger.Printf("Error gracefully shutting down server: %v\n", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("Error gracefully shutting down server: %v\n", err)
 	} else {
 		logger.Println("Server gracefully shut down")
 	}
+	cancelRequests()
 
 	close(stopped)
 }
@@ -177,6 +193,28 @@ func CheckCertExpiry(certFile string) error {
 	return nil
 }
 
+// extractTraceContext continues the trace of the caller, when it sends one.
+func extractTraceContext(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	next(rw, r.WithContext(tracing.Extract(r.Context(), r.Header)))
+}
+
+func createRequestLogger(serverLogger *log.Logger) negroni.Handler {
+	if loggerfactory.IsStructured(serverLogger) {
+		return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+			start := time.Now()
+			next(rw, r)
+
+			logger := loggerfactory.WithFields(serverLogger, loggerfactory.Fields{
+				"method": r.Method,
+				"path":   r.URL.Path,
+				"status": rw.(negroni.ResponseWriter).Status(),
+			})
+			loggerfactory.WithDuration(logger, time.Since(start)).Println("request completed")
+		})
+	}
+	return createNegroniLogger(serverLogger)
+}
+
 func createNegroniLogger(serverLogger *log.Logger) *negroni.Logger {
 	dateFormat := "2006/01/02 15:04:05.000000"
 	logFormat := "Request {{.Method}} {{.Path}} Completed {{.Status}} in {{.Duration}} | Start Time: {{.StartTime}}"
@@ -189,6 +227,6 @@ func createNegroniLogger(serverLogger *log.Logger) *negroni.Logger {
 
 func createBrokerAPILogger(componentName string, serverLogger *log.Logger) *slog.Logger {
 	brokerAPILogger := lager.NewLogger(componentName)
-	brokerAPILogger.RegisterSink(lager.NewWriterSink(serverLogger.Writer(), lager.INFO))
+	brokerAPILogger.RegisterSink(lager.NewWriterSink(loggerfactory.Writer(serverLogger), lager.INFO))
 	return slog.New(lager.NewHandler(brokerAPILogger))
 }
@@ -0,0 +1,147 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+)
+
+// InstrumentedBroker records the duration of every OSBAPI operation and the
+// final state of the BOSH tasks reported by last operation requests. Every
+// operation is traced, as the parent of the spans of the work done for it.
+type InstrumentedBroker struct {
+	CombinedBroker
+	registry  *metrics.Registry
+	offerings *config.OfferingStore
+}
+
+func NewInstrumentedBroker(broker CombinedBroker, registry *metrics.Registry, offerings *config.OfferingStore) *InstrumentedBroker {
+	return &InstrumentedBroker{
+		CombinedBroker: broker,
+		registry:       registry,
+		offerings:      offerings,
+	}
+}
+
+func (b *InstrumentedBroker) Services(ctx context.Context) ([]domain.Service, error) {
+	ctx, done := b.observe(ctx, "catalog", time.Now())
+	services, err := b.CombinedBroker.Services(ctx)
+	done(err)
+	return services, err
+}
+
+func (b *InstrumentedBroker) Provision(ctx context.Context, instanceID string, details domain.ProvisionDetails, asyncAllowed bool) (domain.ProvisionedServiceSpec, error) {
+	ctx, done := b.observe(ctx, "provision", time.Now())
+	spec, err := b.CombinedBroker.Provision(ctx, instanceID, details, asyncAllowed)
+	done(err)
+	return spec, err
+}
+
+func (b *InstrumentedBroker) Deprovision(ctx context.Context, instanceID string, details domain.DeprovisionDetails, asyncAllowed bool) (domain.DeprovisionServiceSpec, error) {
+	ctx, done := b.observe(ctx, "deprovision", time.Now())
+	spec, err := b.CombinedBroker.Deprovision(ctx, instanceID, details, asyncAllowed)
+	done(err)
+	return spec, err
+}
+
+func (b *InstrumentedBroker) GetInstance(ctx context.Context, instanceID string, details domain.FetchInstanceDetails) (domain.GetInstanceDetailsSpec, error) {
+	ctx, done := b.observe(ctx, "get_instance", time.Now())
+	spec, err := b.CombinedBroker.GetInstance(ctx, instanceID, details)
+	done(err)
+	return spec, err
+}
+
+func (b *InstrumentedBroker) Update(ctx context.Context, instanceID string, details domain.UpdateDetails, asyncAllowed bool) (domain.UpdateServiceSpec, error) {
+	ctx, done := b.observe(ctx, "update", time.Now())
+	spec, err := b.CombinedBroker.Update(ctx, instanceID, details, asyncAllowed)
+	done(err)
+	return spec, err
+}
+
+func (b *InstrumentedBroker) LastOperation(ctx context.Context, instanceID string, details domain.PollDetails) (domain.LastOperation, error) {
+	ctx, done := b.observe(ctx, "last_operation", time.Now())
+	lastOperation, err := b.CombinedBroker.LastOperation(ctx, instanceID, details)
+	done(err)
+
+	if err == nil && lastOperation.State != domain.InProgress {
+		b.observeBoshTask(details, lastOperation.State)
+	}
+	return lastOperation, err
+}
+
+func (b *InstrumentedBroker) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, asyncAllowed bool) (domain.Binding, error) {
+	ctx, done := b.observe(ctx, "bind", time.Now())
+	binding, err := b.CombinedBroker.Bind(ctx, instanceID, bindingID, details, asyncAllowed)
+	done(err)
+	return binding, err
+}
+
+func (b *InstrumentedBroker) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, asyncAllowed bool) (domain.UnbindSpec, error) {
+	ctx, done := b.observe(ctx, "unbind", time.Now())
+	spec, err := b.CombinedBroker.Unbind(ctx, instanceID, bindingID, details, asyncAllowed)
+	done(err)
+	return spec, err
+}
+
+func (b *InstrumentedBroker) GetBinding(ctx context.Context, instanceID, bindingID string, details domain.FetchBindingDetails) (domain.GetBindingSpec, error) {
+	ctx, done := b.observe(ctx, "get_binding", time.Now())
+	spec, err := b.CombinedBroker.GetBinding(ctx, instanceID, bindingID, details)
+	done(err)
+	return spec, err
+}
+
+func (b *InstrumentedBroker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details domain.PollDetails) (domain.LastOperation, error) {
+	ctx, done := b.observe(ctx, "last_binding_operation", time.Now())
+	lastOperation, err := b.CombinedBroker.LastBindingOperation(ctx, instanceID, bindingID, details)
+	done(err)
+	return lastOperation, err
+}
+
+func (b *InstrumentedBroker) observe(ctx context.Context, operation string, start time.Time) (context.Context, func(error)) {
+	ctx, span := tracing.Start(ctx, "broker."+operation)
+	return ctx, func(err error) {
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+		}
+		b.registry.ObserveRequest(operation, outcome, time.Since(start))
+		span.End(err)
+	}
+}
+
+func (b *InstrumentedBroker) observeBoshTask(details domain.PollDetails, state domain.LastOperationState) {
+	var operationData broker.OperationData
+	if err := json.Unmarshal([]byte(details.OperationData), &operationData); err != nil || operationData.BoshTaskID == 0 {
+		return
+	}
+
+	planID := details.PlanID
+	if planID == "" {
+		planID = operationData.PlanID
+	}
+	offeringName, planName := b.names(planID)
+
+	b.registry.ObserveBoshTask(offeringName, planName, string(operationData.OperationType), string(state))
+}
+
+func (b *InstrumentedBroker) names(planID string) (string, string) {
+	for _, serviceOffering := range b.offerings.ServiceCatalogs() {
+		if plan, found := serviceOffering.FindPlanByID(planID); found {
+			return serviceOffering.Name, plan.Name
+		}
+	}
+	return "", ""
+}
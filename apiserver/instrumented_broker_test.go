@@ -0,0 +1,128 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package apiserver_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver"
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+	tracingfakes "github.com/pivotal-cf/on-demand-service-broker/tracing/fakes"
+)
+
+var _ = Describe("InstrumentedBroker", func() {
+	var (
+		fakeBroker         *fakes.FakeCombinedBroker
+		registry           *metrics.Registry
+		instrumentedBroker *apiserver.InstrumentedBroker
+		ctx                context.Context
+	)
+
+	family := func(name string) metrics.Family {
+		for _, family := range registry.Families() {
+			if family.Name == name {
+				return family
+			}
+		}
+		return metrics.Family{}
+	}
+
+	BeforeEach(func() {
+		fakeBroker = new(fakes.FakeCombinedBroker)
+		registry = metrics.NewRegistry()
+		ctx = context.Background()
+		instrumentedBroker = apiserver.NewInstrumentedBroker(fakeBroker, registry, config.NewOfferingStore([]config.OfferingConfig{{
+			ServiceCatalog: config.ServiceOffering{
+				Name:  "redis",
+				Plans: config.Plans{{ID: "small-id", Name: "small"}},
+			},
+		}}))
+	})
+
+	It("records the duration and outcome of each operation", func() {
+		fakeBroker.UnbindReturns(domain.UnbindSpec{}, errors.New("oops"))
+
+		_, err := instrumentedBroker.Provision(ctx, "some-instance", domain.ProvisionDetails{}, true)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = instrumentedBroker.Unbind(ctx, "some-instance", "some-binding", domain.UnbindDetails{}, false)
+		Expect(err).To(MatchError("oops"))
+
+		Expect(fakeBroker.ProvisionCallCount()).To(Equal(1))
+		Expect(family(metrics.RequestDurationName).Samples).To(ContainElements(
+			metrics.Sample{
+				Name:   metrics.RequestDurationName + "_count",
+				Labels: metrics.Labels{"operation": "provision", "outcome": "success"},
+				Value:  1,
+			},
+			metrics.Sample{
+				Name:   metrics.RequestDurationName + "_count",
+				Labels: metrics.Labels{"operation": "unbind", "outcome": "error"},
+				Value:  1,
+			},
+		))
+	})
+
+	It("records the final state of BOSH tasks", func() {
+		fakeBroker.LastOperationReturnsOnCall(0, domain.LastOperation{State: domain.InProgress}, nil)
+		fakeBroker.LastOperationReturnsOnCall(1, domain.LastOperation{State: domain.Failed}, nil)
+		details := domain.PollDetails{PlanID: "small-id", OperationData: `{"BoshTaskID":42,"OperationType":"create"}`}
+
+		for i := 0; i < 2; i++ {
+			_, err := instrumentedBroker.LastOperation(ctx, "some-instance", details)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(family(metrics.BoshTasksName).Samples).To(ConsistOf(metrics.Sample{
+			Name:   metrics.BoshTasksName,
+			Labels: metrics.Labels{"offering": "redis", "plan": "small", "operation": "create", "state": "failed"},
+			Value:  1,
+		}))
+	})
+
+	Describe("tracing", func() {
+		var exporter *tracingfakes.FakeExporter
+
+		BeforeEach(func() {
+			exporter = new(tracingfakes.FakeExporter)
+			tracing.SetExporter(exporter)
+		})
+
+		AfterEach(func() {
+			tracing.SetExporter(nil)
+		})
+
+		It("traces each operation as the parent of the work done for it", func() {
+			fakeBroker.BindReturns(domain.Binding{}, errors.New("oops"))
+			ctx = tracing.ContextWithSpanContext(ctx, tracing.SpanContext{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: true,
+			})
+
+			_, err := instrumentedBroker.Bind(ctx, "some-instance", "some-binding", domain.BindDetails{}, false)
+			Expect(err).To(MatchError("oops"))
+
+			Expect(exporter.ExportSpanCallCount()).To(Equal(1))
+			span := exporter.ExportSpanArgsForCall(0)
+			Expect(span.Name).To(Equal("broker.bind"))
+			Expect(span.TraceID).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(span.ParentSpanID).To(Equal("00f067aa0ba902b7"))
+			Expect(span.Error).To(Equal("oops"))
+
+			brokerCtx, _, _, _, _ := fakeBroker.BindArgsForCall(0)
+			Expect(tracing.SpanContextFromContext(brokerCtx)).To(Equal(span.SpanContext()))
+		})
+	})
+})
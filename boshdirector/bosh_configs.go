@@ -1,11 +1,14 @@
 package boshdirector
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 type BoshConfig struct {
@@ -14,7 +17,10 @@ type BoshConfig struct {
 	Content string
 }
 
-func (c *Client) GetConfigs(configName string, logger *log.Logger) ([]BoshConfig, error) {
+func (c *Client) GetConfigs(ctx context.Context, configName string, logger *log.Logger) (_ []BoshConfig, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-configs")
+	defer func() { span.End(err) }()
+
 	var configs []BoshConfig
 
 	logger.Printf("getting configs for %s\n", configName)
@@ -34,7 +40,34 @@ func (c *Client) GetConfigs(configName string, logger *log.Logger) ([]BoshConfig
 	return configs, nil
 }
 
-func (c *Client) UpdateConfig(configType, configName string, configContent []byte, logger *log.Logger) error {
+// GetConfigsOfType returns the latest config of every name with the type.
+func (c *Client) GetConfigsOfType(ctx context.Context, configType string, logger *log.Logger) (_ []BoshConfig, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-configs-of-type")
+	defer func() { span.End(err) }()
+
+	var configs []BoshConfig
+
+	logger.Printf("getting %s configs\n", configType)
+	d, err := c.Director(director.NewNoopTaskReporter())
+	if err != nil {
+		return configs, errors.Wrap(err, "Failed to build director")
+	}
+
+	boshConfigs, err := d.ListConfigs(1, director.ConfigsFilter{Type: configType})
+	if err != nil {
+		return configs, errors.Wrap(err, fmt.Sprintf(`BOSH error getting "%s" configs`, configType))
+	}
+
+	for _, config := range boshConfigs {
+		configs = append(configs, BoshConfig{Type: config.Type, Name: config.Name, Content: config.Content})
+	}
+	return configs, nil
+}
+
+func (c *Client) UpdateConfig(ctx context.Context, configType, configName string, configContent []byte, logger *log.Logger) (err error) {
+	_, span := tracing.Start(ctx, "bosh.update-config")
+	defer func() { span.End(err) }()
+
 	logger.Printf("updating %s config %s\n", configType, configName)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
@@ -47,7 +80,10 @@ func (c *Client) UpdateConfig(configType, configName string, configContent []byt
 	return nil
 }
 
-func (c *Client) DeleteConfig(configType, configName string, logger *log.Logger) (bool, error) {
+func (c *Client) DeleteConfig(ctx context.Context, configType, configName string, logger *log.Logger) (_ bool, err error) {
+	_, span := tracing.Start(ctx, "bosh.delete-config")
+	defer func() { span.End(err) }()
+
 	logger.Printf("deleting %s config %s\n", configType, configName)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
@@ -61,7 +97,10 @@ func (c *Client) DeleteConfig(configType, configName string, logger *log.Logger)
 	return found, nil
 }
 
-func (c *Client) DeleteConfigs(configName string, logger *log.Logger) error {
+func (c *Client) DeleteConfigs(ctx context.Context, configName string, logger *log.Logger) (err error) {
+	_, span := tracing.Start(ctx, "bosh.delete-configs")
+	defer func() { span.End(err) }()
+
 	logger.Printf("deleting configs for %s\n", configName)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
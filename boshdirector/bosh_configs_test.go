@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -48,7 +49,7 @@ var _ = Describe("getting bosh configs", func() {
 	Describe("GetConfigs", func() {
 		It("returns the bosh configs", func() {
 			fakeDirector.ListConfigsReturns(directorConfigs, nil)
-			boshConfigs, listConfigsErr = c.GetConfigs(configName, logger)
+			boshConfigs, listConfigsErr = c.GetConfigs(context.Background(), configName, logger)
 
 			Expect(boshConfigs).To(Equal([]boshdirector.BoshConfig{
 				{
@@ -67,7 +68,7 @@ var _ = Describe("getting bosh configs", func() {
 
 		It("lists the latest configs", func() {
 			fakeDirector.ListConfigsReturns(directorConfigs, nil)
-			c.GetConfigs(configName, logger)
+			c.GetConfigs(context.Background(), configName, logger)
 
 			limit, filter := fakeDirector.ListConfigsArgsForCall(0)
 			Expect(limit).To(Equal(1))
@@ -76,7 +77,7 @@ var _ = Describe("getting bosh configs", func() {
 
 		It("returns an error when the director can't be built", func() {
 			fakeDirectorFactory.NewReturns(nil, errors.New("can't get director"))
-			_, listConfigsErr = c.GetConfigs(configName, logger)
+			_, listConfigsErr = c.GetConfigs(context.Background(), configName, logger)
 
 			Expect(listConfigsErr).To(HaveOccurred())
 			Expect(listConfigsErr).To(MatchError(ContainSubstring("Failed to build director: can't get director")))
@@ -84,7 +85,7 @@ var _ = Describe("getting bosh configs", func() {
 
 		It("returns an error when the client cannot list configs", func() {
 			fakeDirector.ListConfigsReturns([]director.Config{}, errors.New("oops"))
-			_, listConfigsErr = c.GetConfigs(configName, logger)
+			_, listConfigsErr = c.GetConfigs(context.Background(), configName, logger)
 
 			Expect(listConfigsErr).To(HaveOccurred())
 			Expect(listConfigsErr).To(MatchError(ContainSubstring(`BOSH error getting configs for "some-config-name"`)))
@@ -92,6 +93,46 @@ var _ = Describe("getting bosh configs", func() {
 	})
 })
 
+var _ = Describe("getting bosh configs of a type", func() {
+	var (
+		configType  = "some-config-type"
+		boshConfigs []boshdirector.BoshConfig
+		listErr     error
+	)
+
+	It("returns the bosh configs of the type", func() {
+		fakeDirector.ListConfigsReturns([]director.Config{
+			{ID: "1", Type: configType, Name: "a-name", Content: "a-content"},
+			{ID: "2", Type: configType, Name: "another-name", Content: "another-content"},
+		}, nil)
+		boshConfigs, listErr = c.GetConfigsOfType(context.Background(), configType, logger)
+
+		Expect(listErr).NotTo(HaveOccurred())
+		Expect(boshConfigs).To(Equal([]boshdirector.BoshConfig{
+			{Type: configType, Name: "a-name", Content: "a-content"},
+			{Type: configType, Name: "another-name", Content: "another-content"},
+		}))
+
+		limit, filter := fakeDirector.ListConfigsArgsForCall(0)
+		Expect(limit).To(Equal(1))
+		Expect(filter).To(Equal(director.ConfigsFilter{Type: configType}))
+	})
+
+	It("returns an error when the director can't be built", func() {
+		fakeDirectorFactory.NewReturns(nil, errors.New("can't get director"))
+		_, listErr = c.GetConfigsOfType(context.Background(), configType, logger)
+
+		Expect(listErr).To(MatchError(ContainSubstring("Failed to build director: can't get director")))
+	})
+
+	It("returns an error when the client cannot list configs", func() {
+		fakeDirector.ListConfigsReturns(nil, errors.New("oops"))
+		_, listErr = c.GetConfigsOfType(context.Background(), configType, logger)
+
+		Expect(listErr).To(MatchError(ContainSubstring(`BOSH error getting "some-config-type" configs`)))
+	})
+})
+
 var _ = Describe("updating bosh config", func() {
 	var (
 		configType      = "some-config-type"
@@ -103,14 +144,14 @@ var _ = Describe("updating bosh config", func() {
 	Describe("UpdateConfig", func() {
 		It("returns the bosh config when the latest config exists", func() {
 			fakeDirector.UpdateConfigReturns(director.Config{}, nil)
-			updateConfigErr = c.UpdateConfig(configType, configName, []byte(configContent), logger)
+			updateConfigErr = c.UpdateConfig(context.Background(), configType, configName, []byte(configContent), logger)
 
 			Expect(updateConfigErr).NotTo(HaveOccurred())
 		})
 
 		It("returns an error when the director can't be built", func() {
 			fakeDirectorFactory.NewReturns(nil, errors.New("can't get director"))
-			updateConfigErr = c.UpdateConfig(configType, configName, []byte(configContent), logger)
+			updateConfigErr = c.UpdateConfig(context.Background(), configType, configName, []byte(configContent), logger)
 
 			Expect(updateConfigErr).To(HaveOccurred())
 			Expect(updateConfigErr).To(MatchError(ContainSubstring("Failed to build director: can't get director")))
@@ -118,7 +159,7 @@ var _ = Describe("updating bosh config", func() {
 
 		It("returns an error when the client cannot get the latest config", func() {
 			fakeDirector.UpdateConfigReturns(director.Config{}, errors.New("oops"))
-			updateConfigErr = c.UpdateConfig(configType, configName, []byte(configContent), logger)
+			updateConfigErr = c.UpdateConfig(context.Background(), configType, configName, []byte(configContent), logger)
 
 			Expect(updateConfigErr).To(HaveOccurred())
 			Expect(updateConfigErr).To(MatchError(ContainSubstring(`BOSH error updating "some-config-type" config "some-config-name"`)))
@@ -137,7 +178,7 @@ var _ = Describe("deleting bosh config", func() {
 	Describe("DeleteConfig", func() {
 		It("returns true when the config exists", func() {
 			fakeDirector.DeleteConfigReturns(true, nil)
-			configFound, deleteConfigErr = c.DeleteConfig(configType, configName, logger)
+			configFound, deleteConfigErr = c.DeleteConfig(context.Background(), configType, configName, logger)
 
 			Expect(configFound).To(BeTrue())
 			Expect(deleteConfigErr).NotTo(HaveOccurred())
@@ -145,7 +186,7 @@ var _ = Describe("deleting bosh config", func() {
 
 		It("returns false when the config does not exists", func() {
 			fakeDirector.DeleteConfigReturns(false, nil)
-			configFound, deleteConfigErr = c.DeleteConfig(configType, configName, logger)
+			configFound, deleteConfigErr = c.DeleteConfig(context.Background(), configType, configName, logger)
 
 			Expect(configFound).To(BeFalse())
 			Expect(deleteConfigErr).NotTo(HaveOccurred())
@@ -153,7 +194,7 @@ var _ = Describe("deleting bosh config", func() {
 
 		It("returns an error when the director can't be built", func() {
 			fakeDirectorFactory.NewReturns(nil, errors.New("can't get director"))
-			_, deleteConfigErr = c.DeleteConfig(configType, configName, logger)
+			_, deleteConfigErr = c.DeleteConfig(context.Background(), configType, configName, logger)
 
 			Expect(deleteConfigErr).To(HaveOccurred())
 			Expect(deleteConfigErr).To(MatchError(ContainSubstring("Failed to build director: can't get director")))
@@ -161,7 +202,7 @@ var _ = Describe("deleting bosh config", func() {
 
 		It("returns an error when the client cannot delete the config", func() {
 			fakeDirector.DeleteConfigReturns(false, errors.New("oops"))
-			_, deleteConfigErr = c.DeleteConfig(configType, configName, logger)
+			_, deleteConfigErr = c.DeleteConfig(context.Background(), configType, configName, logger)
 
 			Expect(deleteConfigErr).To(HaveOccurred())
 			Expect(deleteConfigErr).To(MatchError(ContainSubstring(`BOSH error deleting "some-config-type" config "some-config-name"`)))
@@ -200,7 +241,7 @@ var _ = Describe("deleting bosh configs", func() {
 	Describe("DeleteConfigs", func() {
 		It("deletes all configs", func() {
 			fakeDirector.ListConfigsReturns(directorConfigs, nil)
-			deleteConfigsErr = c.DeleteConfigs(configName, logger)
+			deleteConfigsErr = c.DeleteConfigs(context.Background(), configName, logger)
 
 			Expect(deleteConfigsErr).NotTo(HaveOccurred())
 			Expect(fakeDirector.DeleteConfigCallCount()).To(Equal(2))
@@ -208,7 +249,7 @@ var _ = Describe("deleting bosh configs", func() {
 
 		It("lists the latest configs", func() {
 			fakeDirector.ListConfigsReturns(directorConfigs, nil)
-			c.DeleteConfigs(configName, logger)
+			c.DeleteConfigs(context.Background(), configName, logger)
 
 			limit, filter := fakeDirector.ListConfigsArgsForCall(0)
 			Expect(limit).To(Equal(1))
@@ -217,7 +258,7 @@ var _ = Describe("deleting bosh configs", func() {
 
 		It("does not delete any config when there are not any bosh configs", func() {
 			fakeDirector.ListConfigsReturns([]director.Config{}, nil)
-			deleteConfigsErr = c.DeleteConfigs(configName, logger)
+			deleteConfigsErr = c.DeleteConfigs(context.Background(), configName, logger)
 
 			Expect(deleteConfigsErr).NotTo(HaveOccurred())
 			Expect(fakeDirector.DeleteConfigCallCount()).To(Equal(0))
@@ -225,7 +266,7 @@ var _ = Describe("deleting bosh configs", func() {
 
 		It("returns an error when the director can't be built", func() {
 			fakeDirectorFactory.NewReturns(nil, errors.New("can't get director"))
-			deleteConfigsErr = c.DeleteConfigs(configName, logger)
+			deleteConfigsErr = c.DeleteConfigs(context.Background(), configName, logger)
 
 			Expect(deleteConfigsErr).To(HaveOccurred())
 			Expect(deleteConfigsErr).To(MatchError(ContainSubstring("Failed to build director: can't get director")))
@@ -233,7 +274,7 @@ var _ = Describe("deleting bosh configs", func() {
 
 		It("returns an error when the client cannot list configs", func() {
 			fakeDirector.ListConfigsReturns([]director.Config{}, errors.New("oops"))
-			deleteConfigsErr = c.DeleteConfigs(configName, logger)
+			deleteConfigsErr = c.DeleteConfigs(context.Background(), configName, logger)
 
 			Expect(deleteConfigsErr).To(HaveOccurred())
 			Expect(deleteConfigsErr).To(MatchError(ContainSubstring(`BOSH error getting configs for "some-config-name`)))
@@ -242,7 +283,7 @@ var _ = Describe("deleting bosh configs", func() {
 		It("rreturns an error when the client cannot delete the config", func() {
 			fakeDirector.ListConfigsReturns(directorConfigs, nil)
 			fakeDirector.DeleteConfigReturns(false, errors.New("oops"))
-			deleteConfigsErr = c.DeleteConfigs(configName, logger)
+			deleteConfigsErr = c.DeleteConfigs(context.Background(), configName, logger)
 
 			Expect(deleteConfigsErr).To(HaveOccurred())
 			Expect(deleteConfigsErr).To(MatchError(ContainSubstring(`BOSH error deleting "some-config-type" config "some-config-name"`)))
@@ -7,6 +7,7 @@
 package boshdirector
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 type Client struct {
@@ -70,18 +72,18 @@ type CertAppender interface {
 //counterfeiter:generate -o fakes/fake_boshhttp.go . HTTP
 
 type HTTP interface {
-	RawGet(path string) (string, error)
-	RawPost(path, data, contentType string) (string, error)
-	RawDelete(path string) (string, error)
+	RawGet(ctx context.Context, path string) (string, error)
+	RawPost(ctx context.Context, path, data, contentType string) (string, error)
+	RawDelete(ctx context.Context, path string) (string, error)
 }
 
 //counterfeiter:generate -o fakes/fake_dns_retriever.go . DNSRetriever
 
 type DNSRetriever interface {
-	LinkProviderID(deploymentName, instanceGroupName, providerName string) (string, error)
-	CreateLinkConsumer(providerID string) (string, error)
-	GetLinkAddress(consumerLinkID string, azs []string, status string) (string, error)
-	DeleteLinkConsumer(consumerID string) error
+	LinkProviderID(ctx context.Context, deploymentName, instanceGroupName, providerName string) (string, error)
+	CreateLinkConsumer(ctx context.Context, providerID string) (string, error)
+	GetLinkAddress(ctx context.Context, consumerLinkID string, azs []string, status string) (string, error)
+	DeleteLinkConsumer(ctx context.Context, consumerID string) error
 }
 
 //counterfeiter:generate -o fakes/fake_dns_retriever_factory.go . DNSRetrieverFactory
@@ -101,7 +103,7 @@ func New(url string,
 	certAppender.AppendCertsFromPEM(trustedCertPEM)
 
 	noAuthClient := &Client{url: url, trustedCertPEM: trustedCertPEM, directorFactory: directorFactory}
-	boshInfo, err := noAuthClient.GetInfo(logger)
+	boshInfo, err := noAuthClient.GetInfo(context.Background(), logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "error fetching BOSH director information")
 	}
@@ -148,7 +150,10 @@ func (c *Client) directorConfig() (director.FactoryConfig, error) {
 	return directorConfig, nil
 }
 
-func (c *Client) VerifyAuth(logger *log.Logger) error {
+func (c *Client) VerifyAuth(ctx context.Context, logger *log.Logger) (err error) {
+	_, span := tracing.Start(ctx, "bosh.verify-auth")
+	defer func() { span.End(err) }()
+
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
 		return errors.Wrap(err, " to verify credentials")
@@ -192,8 +197,12 @@ type AuthenticationOptions struct {
 	URL string
 }
 
+// Deployment is a deployment as listed by the director. Releases holds the
+// version of each release by name.
 type Deployment struct {
-	Name string
+	Name             string
+	Releases         map[string]string
+	StemcellVersions []string
 }
 
 const (
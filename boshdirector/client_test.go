@@ -16,6 +16,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
@@ -109,7 +110,7 @@ var _ = Describe("New", func() {
 			Expect(client.PollingInterval).To(BeEquivalentTo(5))
 
 			By("ensuring that the client works")
-			err = client.VerifyAuth(logger)
+			err = client.VerifyAuth(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("having configured uaa")
@@ -196,7 +197,7 @@ var _ = Describe("New", func() {
 					logger,
 				)
 				Expect(err).NotTo(HaveOccurred())
-				err = client.VerifyAuth(logger)
+				err = client.VerifyAuth(context.Background(), logger)
 				Expect(err).To(MatchError(ContainSubstring("Failed to build UAA config from url")))
 			})
 
@@ -220,7 +221,7 @@ var _ = Describe("New", func() {
 					logger,
 				)
 				Expect(err).NotTo(HaveOccurred())
-				err = client.VerifyAuth(logger)
+				err = client.VerifyAuth(context.Background(), logger)
 				Expect(err).To(MatchError(ContainSubstring("Failed to build UAA config from url: Expected non-empty UAA URL")))
 			})
 
@@ -238,7 +239,7 @@ var _ = Describe("New", func() {
 					logger,
 				)
 				Expect(err).NotTo(HaveOccurred())
-				err = client.VerifyAuth(logger)
+				err = client.VerifyAuth(context.Background(), logger)
 				Expect(err).To(MatchError(ContainSubstring("Failed to build UAA client: failed to build uaa")))
 			})
 		})
@@ -302,7 +303,7 @@ var _ = Describe("New", func() {
 			Expect(client.PollingInterval).To(BeEquivalentTo(5))
 
 			By("ensuring that the client works")
-			err = client.VerifyAuth(logger)
+			err = client.VerifyAuth(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
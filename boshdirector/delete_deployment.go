@@ -7,20 +7,29 @@
 package boshdirector
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) DeleteDeployment(name, contextID string, force bool, taskReporter *AsyncTaskReporter, logger *log.Logger) (int, error) {
+func (c *Client) DeleteDeployment(ctx context.Context, name, contextID string, force bool, taskReporter *AsyncTaskReporter, logger *log.Logger) (taskID int, err error) {
+	_, span := tracing.Start(ctx, "bosh.delete-deployment")
+	defer func() {
+		span.SetAttribute("bosh_task_id", taskID)
+		span.End(err)
+	}()
+
 	logger.Printf("deleting deployment %s\n", name)
 	d, err := c.Director(taskReporter)
 	if err != nil {
 		return 0, errors.Wrap(err, "Failed to build director")
 	}
 
-	_, found, err := c.GetDeployment(name, logger)
+	_, found, err := c.GetDeployment(ctx, name, logger)
 	if err != nil {
 		return 0, errors.Wrap(err, fmt.Sprintf(`BOSH error when deleting deployment "%s"`, name))
 	}
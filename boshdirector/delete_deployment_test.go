@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -43,7 +44,7 @@ var _ = Describe("deleting bosh deployments", func() {
 	})
 
 	It("returns the bosh task ID when bosh accepts the delete request", func() {
-		taskID, deleteErr := c.DeleteDeployment(deploymentName, "delete-some-deployment", false, taskReporter, logger)
+		taskID, deleteErr := c.DeleteDeployment(context.Background(), deploymentName, "delete-some-deployment", false, taskReporter, logger)
 
 		Expect(deleteErr).NotTo(HaveOccurred())
 		Expect(taskID).To(Equal(taskId))
@@ -51,21 +52,21 @@ var _ = Describe("deleting bosh deployments", func() {
 
 	It("returns an error when the FindDeployment errors", func() {
 		fakeDirector.FindDeploymentReturns(new(fakes.FakeBOSHDeployment), errors.New("oops"))
-		_, deleteErr := c.DeleteDeployment(deploymentName, "delete-some-deployment", false, taskReporter, logger)
+		_, deleteErr := c.DeleteDeployment(context.Background(), deploymentName, "delete-some-deployment", false, taskReporter, logger)
 
 		Expect(deleteErr).To(MatchError(ContainSubstring(`BOSH error when deleting deployment "some-deployment"`)))
 	})
 
 	It("returns an error when the GetDeployment errors", func() {
 		fakeDirector.ListDeploymentsReturns(nil, errors.New("oops"))
-		_, deleteErr := c.DeleteDeployment(deploymentName, "delete-some-deployment", false, taskReporter, logger)
+		_, deleteErr := c.DeleteDeployment(context.Background(), deploymentName, "delete-some-deployment", false, taskReporter, logger)
 
 		Expect(deleteErr).To(MatchError(ContainSubstring(`BOSH error when deleting deployment "some-deployment"`)))
 	})
 
 	It("reports task started and task finished when the deployment doesn't exist", func() {
 		fakeDirector.ListDeploymentsReturns([]director.DeploymentResp{}, nil)
-		taskID, deleteErr := c.DeleteDeployment(deploymentName, "delete-some-deployment", false, taskReporter, logger)
+		taskID, deleteErr := c.DeleteDeployment(context.Background(), deploymentName, "delete-some-deployment", false, taskReporter, logger)
 
 		Expect(taskID).To(Equal(0))
 		Expect(deleteErr).NotTo(HaveOccurred())
@@ -76,7 +77,7 @@ var _ = Describe("deleting bosh deployments", func() {
 
 	It("returns an error when cannot delete the deployment", func() {
 		fakeDeployment.DeleteReturns(errors.New("oops"))
-		_, deleteErr := c.DeleteDeployment(deploymentName, "delete-some-deployment", false, taskReporter, logger)
+		_, deleteErr := c.DeleteDeployment(context.Background(), deploymentName, "delete-some-deployment", false, taskReporter, logger)
 
 		Expect(deleteErr).To(MatchError("Could not delete deployment some-deployment: oops"))
 	})
@@ -84,7 +85,7 @@ var _ = Describe("deleting bosh deployments", func() {
 	When("force flag is passed to Delete deployment", func() {
 		It("passes true when true is passed", func() {
 			expectedForceDelete := true
-			_, deleteErr := c.DeleteDeployment(deploymentName, "delete-some-deployment", expectedForceDelete, taskReporter, logger)
+			_, deleteErr := c.DeleteDeployment(context.Background(), deploymentName, "delete-some-deployment", expectedForceDelete, taskReporter, logger)
 
 			Expect(deleteErr).NotTo(HaveOccurred())
 			actualForceDelete := fakeDeployment.DeleteArgsForCall(0)
@@ -93,7 +94,7 @@ var _ = Describe("deleting bosh deployments", func() {
 
 		It("passes false when false is passed", func() {
 			expectedForceDelete := false
-			_, deleteErr := c.DeleteDeployment(deploymentName, "delete-some-deployment", expectedForceDelete, taskReporter, logger)
+			_, deleteErr := c.DeleteDeployment(context.Background(), deploymentName, "delete-some-deployment", expectedForceDelete, taskReporter, logger)
 
 			Expect(deleteErr).NotTo(HaveOccurred())
 			actualForceDelete := fakeDeployment.DeleteArgsForCall(0)
@@ -7,14 +7,23 @@
 package boshdirector
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) Deploy(manifest []byte, contextID string, logger *log.Logger, taskReporter *AsyncTaskReporter) (int, error) {
+func (c *Client) Deploy(ctx context.Context, manifest []byte, contextID string, logger *log.Logger, taskReporter *AsyncTaskReporter) (taskID int, err error) {
+	_, span := tracing.Start(ctx, "bosh.deploy")
+	defer func() {
+		span.SetAttribute("bosh_task_id", taskID)
+		span.End(err)
+	}()
+
 	name, err := fetchName(manifest)
 	if err != nil {
 		return 0, errors.Wrap(err, fmt.Sprintf("Error fetching deployment name"))
@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -51,7 +52,7 @@ var _ = Describe("deploying a manifest", func() {
 	})
 
 	It("succeeds", func() {
-		taskID, err := c.Deploy(manifest, "some-context-id", logger, asyncTaskReporter)
+		taskID, err := c.Deploy(context.Background(), manifest, "some-context-id", logger, asyncTaskReporter)
 
 		By("returning the correct task id")
 		Expect(err).NotTo(HaveOccurred())
@@ -60,26 +61,26 @@ var _ = Describe("deploying a manifest", func() {
 
 	It("returns an error if cannot update the deployment", func() {
 		fakeDeployment.UpdateReturns(errors.New("oops"))
-		_, err := c.Deploy(manifest, "some-context-id", logger, asyncTaskReporter)
+		_, err := c.Deploy(context.Background(), manifest, "some-context-id", logger, asyncTaskReporter)
 
 		Expect(err).To(MatchError(ContainSubstring("Could not update deployment bill")))
 	})
 
 	It("returns an error if cannot fetch the deployment name from the manifest", func() {
-		_, err := c.Deploy(namelessManifest(), "some-context-id", logger, asyncTaskReporter)
+		_, err := c.Deploy(context.Background(), namelessManifest(), "some-context-id", logger, asyncTaskReporter)
 
 		Expect(err).To(MatchError(ContainSubstring("Error fetching deployment name")))
 	})
 
 	It("returns an error if the manifest is invalid", func() {
-		_, err := c.Deploy([]byte("not-yaml"), "some-context-id", logger, asyncTaskReporter)
+		_, err := c.Deploy(context.Background(), []byte("not-yaml"), "some-context-id", logger, asyncTaskReporter)
 
 		Expect(err).To(MatchError(ContainSubstring("Error fetching deployment name")))
 	})
 
 	It("returns an error if cannot get the deployment", func() {
 		fakeDirector.FindDeploymentReturns(nil, errors.New("oops"))
-		_, err := c.Deploy(manifest, "some-context-id", logger, asyncTaskReporter)
+		_, err := c.Deploy(context.Background(), manifest, "some-context-id", logger, asyncTaskReporter)
 
 		Expect(err).To(MatchError(ContainSubstring("BOSH CLI error")))
 	})
@@ -2,16 +2,18 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 )
 
 type FakeHTTP struct {
-	RawDeleteStub        func(string) (string, error)
+	RawDeleteStub        func(context.Context, string) (string, error)
 	rawDeleteMutex       sync.RWMutex
 	rawDeleteArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	rawDeleteReturns struct {
 		result1 string
@@ -21,10 +23,11 @@ type FakeHTTP struct {
 		result1 string
 		result2 error
 	}
-	RawGetStub        func(string) (string, error)
+	RawGetStub        func(context.Context, string) (string, error)
 	rawGetMutex       sync.RWMutex
 	rawGetArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	rawGetReturns struct {
 		result1 string
@@ -34,12 +37,13 @@ type FakeHTTP struct {
 		result1 string
 		result2 error
 	}
-	RawPostStub        func(string, string, string) (string, error)
+	RawPostStub        func(context.Context, string, string, string) (string, error)
 	rawPostMutex       sync.RWMutex
 	rawPostArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	rawPostReturns struct {
 		result1 string
@@ -53,18 +57,19 @@ type FakeHTTP struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeHTTP) RawDelete(arg1 string) (string, error) {
+func (fake *FakeHTTP) RawDelete(arg1 context.Context, arg2 string) (string, error) {
 	fake.rawDeleteMutex.Lock()
 	ret, specificReturn := fake.rawDeleteReturnsOnCall[len(fake.rawDeleteArgsForCall)]
 	fake.rawDeleteArgsForCall = append(fake.rawDeleteArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.RawDeleteStub
 	fakeReturns := fake.rawDeleteReturns
-	fake.recordInvocation("RawDelete", []interface{}{arg1})
+	fake.recordInvocation("RawDelete", []interface{}{arg1, arg2})
 	fake.rawDeleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -78,17 +83,17 @@ func (fake *FakeHTTP) RawDeleteCallCount() int {
 	return len(fake.rawDeleteArgsForCall)
 }
 
-func (fake *FakeHTTP) RawDeleteCalls(stub func(string) (string, error)) {
+func (fake *FakeHTTP) RawDeleteCalls(stub func(context.Context, string) (string, error)) {
 	fake.rawDeleteMutex.Lock()
 	defer fake.rawDeleteMutex.Unlock()
 	fake.RawDeleteStub = stub
 }
 
-func (fake *FakeHTTP) RawDeleteArgsForCall(i int) string {
+func (fake *FakeHTTP) RawDeleteArgsForCall(i int) (context.Context, string) {
 	fake.rawDeleteMutex.RLock()
 	defer fake.rawDeleteMutex.RUnlock()
 	argsForCall := fake.rawDeleteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeHTTP) RawDeleteReturns(result1 string, result2 error) {
@@ -117,18 +122,19 @@ func (fake *FakeHTTP) RawDeleteReturnsOnCall(i int, result1 string, result2 erro
 	}{result1, result2}
 }
 
-func (fake *FakeHTTP) RawGet(arg1 string) (string, error) {
+func (fake *FakeHTTP) RawGet(arg1 context.Context, arg2 string) (string, error) {
 	fake.rawGetMutex.Lock()
 	ret, specificReturn := fake.rawGetReturnsOnCall[len(fake.rawGetArgsForCall)]
 	fake.rawGetArgsForCall = append(fake.rawGetArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.RawGetStub
 	fakeReturns := fake.rawGetReturns
-	fake.recordInvocation("RawGet", []interface{}{arg1})
+	fake.recordInvocation("RawGet", []interface{}{arg1, arg2})
 	fake.rawGetMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -142,17 +148,17 @@ func (fake *FakeHTTP) RawGetCallCount() int {
 	return len(fake.rawGetArgsForCall)
 }
 
-func (fake *FakeHTTP) RawGetCalls(stub func(string) (string, error)) {
+func (fake *FakeHTTP) RawGetCalls(stub func(context.Context, string) (string, error)) {
 	fake.rawGetMutex.Lock()
 	defer fake.rawGetMutex.Unlock()
 	fake.RawGetStub = stub
 }
 
-func (fake *FakeHTTP) RawGetArgsForCall(i int) string {
+func (fake *FakeHTTP) RawGetArgsForCall(i int) (context.Context, string) {
 	fake.rawGetMutex.RLock()
 	defer fake.rawGetMutex.RUnlock()
 	argsForCall := fake.rawGetArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeHTTP) RawGetReturns(result1 string, result2 error) {
@@ -181,20 +187,21 @@ func (fake *FakeHTTP) RawGetReturnsOnCall(i int, result1 string, result2 error)
 	}{result1, result2}
 }
 
-func (fake *FakeHTTP) RawPost(arg1 string, arg2 string, arg3 string) (string, error) {
+func (fake *FakeHTTP) RawPost(arg1 context.Context, arg2 string, arg3 string, arg4 string) (string, error) {
 	fake.rawPostMutex.Lock()
 	ret, specificReturn := fake.rawPostReturnsOnCall[len(fake.rawPostArgsForCall)]
 	fake.rawPostArgsForCall = append(fake.rawPostArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.RawPostStub
 	fakeReturns := fake.rawPostReturns
-	fake.recordInvocation("RawPost", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("RawPost", []interface{}{arg1, arg2, arg3, arg4})
 	fake.rawPostMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -208,17 +215,17 @@ func (fake *FakeHTTP) RawPostCallCount() int {
 	return len(fake.rawPostArgsForCall)
 }
 
-func (fake *FakeHTTP) RawPostCalls(stub func(string, string, string) (string, error)) {
+func (fake *FakeHTTP) RawPostCalls(stub func(context.Context, string, string, string) (string, error)) {
 	fake.rawPostMutex.Lock()
 	defer fake.rawPostMutex.Unlock()
 	fake.RawPostStub = stub
 }
 
-func (fake *FakeHTTP) RawPostArgsForCall(i int) (string, string, string) {
+func (fake *FakeHTTP) RawPostArgsForCall(i int) (context.Context, string, string, string) {
 	fake.rawPostMutex.RLock()
 	defer fake.rawPostMutex.RUnlock()
 	argsForCall := fake.rawPostArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeHTTP) RawPostReturns(result1 string, result2 error) {
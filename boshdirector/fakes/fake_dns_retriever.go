@@ -2,16 +2,18 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 )
 
 type FakeDNSRetriever struct {
-	CreateLinkConsumerStub        func(string) (string, error)
+	CreateLinkConsumerStub        func(context.Context, string) (string, error)
 	createLinkConsumerMutex       sync.RWMutex
 	createLinkConsumerArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	createLinkConsumerReturns struct {
 		result1 string
@@ -21,10 +23,11 @@ type FakeDNSRetriever struct {
 		result1 string
 		result2 error
 	}
-	DeleteLinkConsumerStub        func(string) error
+	DeleteLinkConsumerStub        func(context.Context, string) error
 	deleteLinkConsumerMutex       sync.RWMutex
 	deleteLinkConsumerArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteLinkConsumerReturns struct {
 		result1 error
@@ -32,12 +35,13 @@ type FakeDNSRetriever struct {
 	deleteLinkConsumerReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GetLinkAddressStub        func(string, []string, string) (string, error)
+	GetLinkAddressStub        func(context.Context, string, []string, string) (string, error)
 	getLinkAddressMutex       sync.RWMutex
 	getLinkAddressArgsForCall []struct {
-		arg1 string
-		arg2 []string
-		arg3 string
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 string
 	}
 	getLinkAddressReturns struct {
 		result1 string
@@ -47,12 +51,13 @@ type FakeDNSRetriever struct {
 		result1 string
 		result2 error
 	}
-	LinkProviderIDStub        func(string, string, string) (string, error)
+	LinkProviderIDStub        func(context.Context, string, string, string) (string, error)
 	linkProviderIDMutex       sync.RWMutex
 	linkProviderIDArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
+		arg4 string
 	}
 	linkProviderIDReturns struct {
 		result1 string
@@ -66,18 +71,19 @@ type FakeDNSRetriever struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDNSRetriever) CreateLinkConsumer(arg1 string) (string, error) {
+func (fake *FakeDNSRetriever) CreateLinkConsumer(arg1 context.Context, arg2 string) (string, error) {
 	fake.createLinkConsumerMutex.Lock()
 	ret, specificReturn := fake.createLinkConsumerReturnsOnCall[len(fake.createLinkConsumerArgsForCall)]
 	fake.createLinkConsumerArgsForCall = append(fake.createLinkConsumerArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.CreateLinkConsumerStub
 	fakeReturns := fake.createLinkConsumerReturns
-	fake.recordInvocation("CreateLinkConsumer", []interface{}{arg1})
+	fake.recordInvocation("CreateLinkConsumer", []interface{}{arg1, arg2})
 	fake.createLinkConsumerMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -91,17 +97,17 @@ func (fake *FakeDNSRetriever) CreateLinkConsumerCallCount() int {
 	return len(fake.createLinkConsumerArgsForCall)
 }
 
-func (fake *FakeDNSRetriever) CreateLinkConsumerCalls(stub func(string) (string, error)) {
+func (fake *FakeDNSRetriever) CreateLinkConsumerCalls(stub func(context.Context, string) (string, error)) {
 	fake.createLinkConsumerMutex.Lock()
 	defer fake.createLinkConsumerMutex.Unlock()
 	fake.CreateLinkConsumerStub = stub
 }
 
-func (fake *FakeDNSRetriever) CreateLinkConsumerArgsForCall(i int) string {
+func (fake *FakeDNSRetriever) CreateLinkConsumerArgsForCall(i int) (context.Context, string) {
 	fake.createLinkConsumerMutex.RLock()
 	defer fake.createLinkConsumerMutex.RUnlock()
 	argsForCall := fake.createLinkConsumerArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeDNSRetriever) CreateLinkConsumerReturns(result1 string, result2 error) {
@@ -130,18 +136,19 @@ func (fake *FakeDNSRetriever) CreateLinkConsumerReturnsOnCall(i int, result1 str
 	}{result1, result2}
 }
 
-func (fake *FakeDNSRetriever) DeleteLinkConsumer(arg1 string) error {
+func (fake *FakeDNSRetriever) DeleteLinkConsumer(arg1 context.Context, arg2 string) error {
 	fake.deleteLinkConsumerMutex.Lock()
 	ret, specificReturn := fake.deleteLinkConsumerReturnsOnCall[len(fake.deleteLinkConsumerArgsForCall)]
 	fake.deleteLinkConsumerArgsForCall = append(fake.deleteLinkConsumerArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteLinkConsumerStub
 	fakeReturns := fake.deleteLinkConsumerReturns
-	fake.recordInvocation("DeleteLinkConsumer", []interface{}{arg1})
+	fake.recordInvocation("DeleteLinkConsumer", []interface{}{arg1, arg2})
 	fake.deleteLinkConsumerMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -155,17 +162,17 @@ func (fake *FakeDNSRetriever) DeleteLinkConsumerCallCount() int {
 	return len(fake.deleteLinkConsumerArgsForCall)
 }
 
-func (fake *FakeDNSRetriever) DeleteLinkConsumerCalls(stub func(string) error) {
+func (fake *FakeDNSRetriever) DeleteLinkConsumerCalls(stub func(context.Context, string) error) {
 	fake.deleteLinkConsumerMutex.Lock()
 	defer fake.deleteLinkConsumerMutex.Unlock()
 	fake.DeleteLinkConsumerStub = stub
 }
 
-func (fake *FakeDNSRetriever) DeleteLinkConsumerArgsForCall(i int) string {
+func (fake *FakeDNSRetriever) DeleteLinkConsumerArgsForCall(i int) (context.Context, string) {
 	fake.deleteLinkConsumerMutex.RLock()
 	defer fake.deleteLinkConsumerMutex.RUnlock()
 	argsForCall := fake.deleteLinkConsumerArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeDNSRetriever) DeleteLinkConsumerReturns(result1 error) {
@@ -191,25 +198,26 @@ func (fake *FakeDNSRetriever) DeleteLinkConsumerReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
-func (fake *FakeDNSRetriever) GetLinkAddress(arg1 string, arg2 []string, arg3 string) (string, error) {
-	var arg2Copy []string
-	if arg2 != nil {
-		arg2Copy = make([]string, len(arg2))
-		copy(arg2Copy, arg2)
+func (fake *FakeDNSRetriever) GetLinkAddress(arg1 context.Context, arg2 string, arg3 []string, arg4 string) (string, error) {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
 	}
 	fake.getLinkAddressMutex.Lock()
 	ret, specificReturn := fake.getLinkAddressReturnsOnCall[len(fake.getLinkAddressArgsForCall)]
 	fake.getLinkAddressArgsForCall = append(fake.getLinkAddressArgsForCall, struct {
-		arg1 string
-		arg2 []string
-		arg3 string
-	}{arg1, arg2Copy, arg3})
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 string
+	}{arg1, arg2, arg3Copy, arg4})
 	stub := fake.GetLinkAddressStub
 	fakeReturns := fake.getLinkAddressReturns
-	fake.recordInvocation("GetLinkAddress", []interface{}{arg1, arg2Copy, arg3})
+	fake.recordInvocation("GetLinkAddress", []interface{}{arg1, arg2, arg3Copy, arg4})
 	fake.getLinkAddressMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -223,17 +231,17 @@ func (fake *FakeDNSRetriever) GetLinkAddressCallCount() int {
 	return len(fake.getLinkAddressArgsForCall)
 }
 
-func (fake *FakeDNSRetriever) GetLinkAddressCalls(stub func(string, []string, string) (string, error)) {
+func (fake *FakeDNSRetriever) GetLinkAddressCalls(stub func(context.Context, string, []string, string) (string, error)) {
 	fake.getLinkAddressMutex.Lock()
 	defer fake.getLinkAddressMutex.Unlock()
 	fake.GetLinkAddressStub = stub
 }
 
-func (fake *FakeDNSRetriever) GetLinkAddressArgsForCall(i int) (string, []string, string) {
+func (fake *FakeDNSRetriever) GetLinkAddressArgsForCall(i int) (context.Context, string, []string, string) {
 	fake.getLinkAddressMutex.RLock()
 	defer fake.getLinkAddressMutex.RUnlock()
 	argsForCall := fake.getLinkAddressArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeDNSRetriever) GetLinkAddressReturns(result1 string, result2 error) {
@@ -262,20 +270,21 @@ func (fake *FakeDNSRetriever) GetLinkAddressReturnsOnCall(i int, result1 string,
 	}{result1, result2}
 }
 
-func (fake *FakeDNSRetriever) LinkProviderID(arg1 string, arg2 string, arg3 string) (string, error) {
+func (fake *FakeDNSRetriever) LinkProviderID(arg1 context.Context, arg2 string, arg3 string, arg4 string) (string, error) {
 	fake.linkProviderIDMutex.Lock()
 	ret, specificReturn := fake.linkProviderIDReturnsOnCall[len(fake.linkProviderIDArgsForCall)]
 	fake.linkProviderIDArgsForCall = append(fake.linkProviderIDArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.LinkProviderIDStub
 	fakeReturns := fake.linkProviderIDReturns
-	fake.recordInvocation("LinkProviderID", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("LinkProviderID", []interface{}{arg1, arg2, arg3, arg4})
 	fake.linkProviderIDMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -289,17 +298,17 @@ func (fake *FakeDNSRetriever) LinkProviderIDCallCount() int {
 	return len(fake.linkProviderIDArgsForCall)
 }
 
-func (fake *FakeDNSRetriever) LinkProviderIDCalls(stub func(string, string, string) (string, error)) {
+func (fake *FakeDNSRetriever) LinkProviderIDCalls(stub func(context.Context, string, string, string) (string, error)) {
 	fake.linkProviderIDMutex.Lock()
 	defer fake.linkProviderIDMutex.Unlock()
 	fake.LinkProviderIDStub = stub
 }
 
-func (fake *FakeDNSRetriever) LinkProviderIDArgsForCall(i int) (string, string, string) {
+func (fake *FakeDNSRetriever) LinkProviderIDArgsForCall(i int) (context.Context, string, string, string) {
 	fake.linkProviderIDMutex.RLock()
 	defer fake.linkProviderIDMutex.RUnlock()
 	argsForCall := fake.linkProviderIDArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeDNSRetriever) LinkProviderIDReturns(result1 string, result2 error) {
@@ -7,13 +7,19 @@
 package boshdirector
 
 import (
+	"context"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) GetDeployment(name string, logger *log.Logger) ([]byte, bool, error) {
+func (c *Client) GetDeployment(ctx context.Context, name string, logger *log.Logger) (_ []byte, _ bool, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-deployment")
+	defer func() { span.End(err) }()
+
 	logger.Printf("getting manifest from bosh for deployment %s", name)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
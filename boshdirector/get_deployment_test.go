@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -42,7 +43,7 @@ var _ = Describe("getting deployment", func() {
 		fakeDirector.FindDeploymentReturns(fakeDeployment, nil)
 		fakeDeployment.ManifestReturns(string(rawManifest), nil)
 
-		manifest, deploymentFound, manifestFetchErr = c.GetDeployment(deploymentName, logger)
+		manifest, deploymentFound, manifestFetchErr = c.GetDeployment(context.Background(), deploymentName, logger)
 
 		By("finding the deployment")
 		Expect(fakeDirector.ListDeploymentsCallCount()).To(Equal(1))
@@ -57,7 +58,7 @@ var _ = Describe("getting deployment", func() {
 	})
 
 	It("returns a nil manifest and false but without an error when the deployment is not found", func() {
-		_, deploymentFound, manifestFetchErr = c.GetDeployment("some-other-name", logger)
+		_, deploymentFound, manifestFetchErr = c.GetDeployment(context.Background(), "some-other-name", logger)
 
 		Expect(deploymentFound).To(BeFalse())
 		Expect(manifestFetchErr).NotTo(HaveOccurred())
@@ -65,7 +66,7 @@ var _ = Describe("getting deployment", func() {
 
 	It("returns an error when the client cannot get the list of the deployments", func() {
 		fakeDirector.ListDeploymentsReturns(nil, errors.New("oops"))
-		_, deploymentFound, manifestFetchErr = c.GetDeployment(deploymentName, logger)
+		_, deploymentFound, manifestFetchErr = c.GetDeployment(context.Background(), deploymentName, logger)
 
 		Expect(deploymentFound).To(BeFalse())
 		Expect(manifestFetchErr).To(MatchError(ContainSubstring("Cannot get the list of deployments")))
@@ -74,7 +75,7 @@ var _ = Describe("getting deployment", func() {
 	It("returns an error when the deployment object cannot be created", func() {
 		fakeDirector.FindDeploymentReturns(nil, errors.New("cannot find deployment"))
 
-		_, _, manifestFetchErr = c.GetDeployment(deploymentName, logger)
+		_, _, manifestFetchErr = c.GetDeployment(context.Background(), deploymentName, logger)
 		Expect(manifestFetchErr).To(MatchError(ContainSubstring(`Cannot create deployment object for deployment "some-deployment"`)))
 	})
 
@@ -82,7 +83,7 @@ var _ = Describe("getting deployment", func() {
 		fakeDirector.FindDeploymentReturns(fakeDeployment, nil)
 		fakeDeployment.ManifestReturns("", errors.New("fake manifest error"))
 
-		_, _, manifestFetchErr = c.GetDeployment(deploymentName, logger)
+		_, _, manifestFetchErr = c.GetDeployment(context.Background(), deploymentName, logger)
 		Expect(manifestFetchErr).To(MatchError(ContainSubstring(`Cannot obtain manifest for deployment "some-deployment"`)))
 	})
 })
@@ -7,25 +7,42 @@
 package boshdirector
 
 import (
+	"context"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) GetDeployments(logger *log.Logger) ([]Deployment, error) {
+// GetDeployments lists the deployments with their releases and stemcells,
+// without their manifests.
+func (c *Client) GetDeployments(ctx context.Context, logger *log.Logger) (_ []Deployment, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-deployments")
+	defer func() { span.End(err) }()
+
 	logger.Println("getting deployments from bosh")
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to build director")
 	}
-	rawDeployments, err := d.Deployments()
+	rawDeployments, err := d.ListDeployments()
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot get the list of deployments")
 	}
 	deployments := make([]Deployment, len(rawDeployments))
 	for i, d := range rawDeployments {
-		deployments[i] = Deployment{Name: d.Name()}
+		deployments[i] = Deployment{Name: d.Name}
+		for _, release := range d.Releases {
+			if deployments[i].Releases == nil {
+				deployments[i].Releases = map[string]string{}
+			}
+			deployments[i].Releases[release.Name] = release.Version
+		}
+		for _, stemcell := range d.Stemcells {
+			deployments[i].StemcellVersions = append(deployments[i].StemcellVersions, stemcell.Version)
+		}
 	}
 	return deployments, nil
 }
@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -14,33 +15,40 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
-	"github.com/pivotal-cf/on-demand-service-broker/boshdirector/fakes"
 )
 
 var _ = Describe("deployments", func() {
-	var fakeDeployment *fakes.FakeBOSHDeployment
-
-	BeforeEach(func() {
-		fakeDeployment = new(fakes.FakeBOSHDeployment)
-		fakeDeployment.NameReturns("some-deployment")
-
-		fakeDirector.DeploymentsReturns([]director.Deployment{fakeDeployment}, nil)
-	})
-
 	It("fetch the deployments", func() {
 		expectedDeployments := []boshdirector.Deployment{
 			{Name: "some-deployment"},
 			{Name: "some-deployment"},
 		}
-		fakeDirector.DeploymentsReturns([]director.Deployment{fakeDeployment, fakeDeployment}, nil)
-		deployments, err := c.GetDeployments(logger)
+		fakeDirector.ListDeploymentsReturns([]director.DeploymentResp{{Name: "some-deployment"}, {Name: "some-deployment"}}, nil)
+		deployments, err := c.GetDeployments(context.Background(), logger)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(deployments).To(Equal(expectedDeployments))
 	})
 
+	It("fetches the releases and stemcells of the deployments", func() {
+		fakeDirector.ListDeploymentsReturns([]director.DeploymentResp{{
+			Name:      "some-deployment",
+			Releases:  []director.DeploymentReleaseResp{{Name: "redis", Version: "1.2.3"}, {Name: "bpm", Version: "1.1"}},
+			Stemcells: []director.DeploymentStemcellResp{{Name: "ubuntu-jammy", Version: "1.18"}},
+		}}, nil)
+
+		deployments, err := c.GetDeployments(context.Background(), logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployments).To(Equal([]boshdirector.Deployment{{
+			Name:             "some-deployment",
+			Releases:         map[string]string{"redis": "1.2.3", "bpm": "1.1"},
+			StemcellVersions: []string{"1.18"},
+		}}))
+	})
+
 	It("returns an error if cannot fetch the deployments", func() {
-		fakeDirector.DeploymentsReturns(nil, errors.New("oops"))
-		_, err := c.GetDeployments(logger)
+		fakeDirector.ListDeploymentsReturns(nil, errors.New("oops"))
+		_, err := c.GetDeployments(context.Background(), logger)
 		Expect(err).To(MatchError(ContainSubstring("Cannot get the list of deployments")))
 	})
 })
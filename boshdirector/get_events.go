@@ -1,19 +1,31 @@
 package boshdirector
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 type BoshEvent struct {
-	TaskId int
+	TaskId    int
+	Action    string
+	Timestamp time.Time
+	// ParentID is set on the event that BOSH records when a task finishes.
+	ParentID string
+	Error    string
 }
 
-func (c *Client) GetEvents(deploymentName, action string, logger *log.Logger) ([]BoshEvent, error) {
+func (c *Client) GetEvents(ctx context.Context, deploymentName, action string, logger *log.Logger) (_ []BoshEvent, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-events")
+	defer func() { span.End(err) }()
+
 	filter := director.EventsFilter{Deployment: deploymentName, Action: action, ObjectType: "deployment"}
 
 	logger.Printf("getting events for %v from bosh", filter)
@@ -34,7 +46,13 @@ func (c *Client) GetEvents(deploymentName, action string, logger *log.Logger) ([
 			return []BoshEvent{}, errors.New(fmt.Sprintf("could not convert task id %q to int", event.TaskID()))
 		}
 
-		boshEvents = append(boshEvents, BoshEvent{TaskId: taskId})
+		boshEvents = append(boshEvents, BoshEvent{
+			TaskId:    taskId,
+			Action:    event.Action(),
+			Timestamp: event.Timestamp(),
+			ParentID:  event.ParentID(),
+			Error:     event.Error(),
+		})
 	}
 
 	return boshEvents, nil
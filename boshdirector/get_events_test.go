@@ -1,6 +1,9 @@
 package boshdirector_test
 
 import (
+	"context"
+	"time"
+
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -13,18 +16,18 @@ var _ = Describe("Get Events", func() {
 	It("gets the right events", func() {
 		fakeDirector.EventsReturns([]director.Event{
 			director.NewEventFromResp(director.Client{}, director.EventResp{TaskID: "123"}),
-			director.NewEventFromResp(director.Client{}, director.EventResp{TaskID: "456"}),
+			director.NewEventFromResp(director.Client{}, director.EventResp{TaskID: "456", Action: "delete", Timestamp: 1500000000, ParentID: "12", Error: "boom"}),
 		}, nil)
 
-		events, err := c.GetEvents("deployment-name", "foo", logger)
+		events, err := c.GetEvents(context.Background(), "deployment-name", "foo", logger)
 
 		actualEventsFilter := fakeDirector.EventsArgsForCall(0)
 
 		Expect(actualEventsFilter).To(Equal(director.EventsFilter{Deployment: "deployment-name", Action: "foo", ObjectType: "deployment"}))
 		Expect(err).To(Not(HaveOccurred()))
 		Expect(events).To(SatisfyAll(
-			ContainElement(boshdirector.BoshEvent{TaskId: 123}),
-			ContainElement(boshdirector.BoshEvent{TaskId: 456}),
+			ContainElement(boshdirector.BoshEvent{TaskId: 123, Timestamp: time.Unix(0, 0).UTC()}),
+			ContainElement(boshdirector.BoshEvent{TaskId: 456, Action: "delete", Timestamp: time.Unix(1500000000, 0).UTC(), ParentID: "12", Error: "boom"}),
 		))
 	})
 
@@ -32,7 +35,7 @@ var _ = Describe("Get Events", func() {
 		errorMessage := "failed to get events"
 		fakeDirector.EventsReturns([]director.Event{}, errors.New(errorMessage))
 
-		events, err := c.GetEvents("not-necessary", "update", logger)
+		events, err := c.GetEvents(context.Background(), "not-necessary", "update", logger)
 
 		Expect(err).To(MatchError(ContainSubstring("Failed to get the events using the director")))
 		Expect(events).To(BeEmpty())
@@ -41,7 +44,7 @@ var _ = Describe("Get Events", func() {
 	It("forwards the error when it fails to build the director", func() {
 		fakeDirectorFactory.NewReturns(fakeDirector, errors.New("fail"))
 
-		events, err := c.GetEvents("deployment-name", "update", logger)
+		events, err := c.GetEvents(context.Background(), "deployment-name", "update", logger)
 
 		Expect(err).To(MatchError(ContainSubstring("Failed to build director")))
 		Expect(fakeDirector.EventsCallCount()).To(BeZero())
@@ -53,7 +56,7 @@ var _ = Describe("Get Events", func() {
 			director.NewEventFromResp(director.Client{}, director.EventResp{TaskID: ""}),
 		}, nil)
 
-		events, err := c.GetEvents("deployment-name", "update", logger)
+		events, err := c.GetEvents(context.Background(), "deployment-name", "update", logger)
 
 		Expect(err).To(HaveOccurred())
 		Expect(err).To(MatchError(`could not convert task id "" to int`))
@@ -7,13 +7,19 @@
 package boshdirector
 
 import (
+	"context"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) GetTask(taskID int, logger *log.Logger) (BoshTask, error) {
+func (c *Client) GetTask(ctx context.Context, taskID int, logger *log.Logger) (_ BoshTask, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-task")
+	defer func() { span.End(err) }()
+
 	logger.Printf("getting task %d from bosh\n", taskID)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
@@ -38,7 +44,10 @@ type BoshTaskOutput struct {
 	StdErr   string `json:"stderr"`
 }
 
-func (c *Client) GetTaskOutput(taskID int, logger *log.Logger) (BoshTaskOutput, error) {
+func (c *Client) GetTaskOutput(ctx context.Context, taskID int, logger *log.Logger) (_ BoshTaskOutput, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-task-output")
+	defer func() { span.End(err) }()
+
 	logger.Printf("getting task output for task %d from bosh\n", taskID)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
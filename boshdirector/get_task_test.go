@@ -8,6 +8,7 @@ package boshdirector_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -44,7 +45,7 @@ var _ = Describe("Bosh Tasks", func() {
 
 	Describe("GetTask", func() {
 		It("gets the task state", func() {
-			taskState, err := c.GetTask(taskID, logger)
+			taskState, err := c.GetTask(context.Background(), taskID, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(taskState).To(Equal(boshdirector.BoshTask{
 				ID:          taskID,
@@ -56,7 +57,7 @@ var _ = Describe("Bosh Tasks", func() {
 		})
 
 		It("returns an error if the getting the task fails", func() {
-			_, err := c.GetTask(-1, logger)
+			_, err := c.GetTask(context.Background(), -1, logger)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(ContainSubstring("Cannot find task with ID: -1")))
 		})
@@ -81,7 +82,7 @@ var _ = Describe("Bosh Tasks", func() {
 		})
 
 		It("gets the task result", func() {
-			taskOutput, err := c.GetTaskOutput(taskID, logger)
+			taskOutput, err := c.GetTaskOutput(context.Background(), taskID, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("calling ResultOutput")
@@ -95,7 +96,7 @@ var _ = Describe("Bosh Tasks", func() {
 		It("returns empty when the task doesn't have output", func() {
 			fakeTask.ResultOutputReturns(nil)
 
-			taskOutput, err := c.GetTaskOutput(taskID, logger)
+			taskOutput, err := c.GetTaskOutput(context.Background(), taskID, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(taskOutput).To(Equal(boshdirector.BoshTaskOutput{}))
@@ -103,7 +104,7 @@ var _ = Describe("Bosh Tasks", func() {
 
 		It("errors when it fails to fetch the task", func() {
 			fakeDirector.FindTaskReturns(nil, errors.New("boom"))
-			taskOutput, err := c.GetTaskOutput(taskID, logger)
+			taskOutput, err := c.GetTaskOutput(context.Background(), taskID, logger)
 
 			Expect(taskOutput).To(Equal(boshdirector.BoshTaskOutput{}))
 			Expect(err).To(MatchError(fmt.Sprintf("Could not fetch task with id %d: boom", taskID)))
@@ -111,7 +112,7 @@ var _ = Describe("Bosh Tasks", func() {
 
 		It("errors when it fails to fetch the output", func() {
 			fakeTask.ResultOutputReturns(errors.New("boom"))
-			taskOutput, err := c.GetTaskOutput(taskID, logger)
+			taskOutput, err := c.GetTaskOutput(context.Background(), taskID, logger)
 
 			Expect(taskOutput).To(Equal(boshdirector.BoshTaskOutput{}))
 			Expect(err).To(MatchError("Could not fetch task output: boom"))
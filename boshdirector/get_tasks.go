@@ -7,13 +7,19 @@
 package boshdirector
 
 import (
+	"context"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) GetTasksInProgress(deploymentName string, logger *log.Logger) (BoshTasks, error) {
+func (c *Client) GetTasksInProgress(ctx context.Context, deploymentName string, logger *log.Logger) (_ BoshTasks, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-tasks-in-progress")
+	defer func() { span.End(err) }()
+
 	logger.Printf("getting current tasks for deployment %s from bosh\n", deploymentName)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
@@ -41,7 +47,10 @@ func (c *Client) GetTasksInProgress(deploymentName string, logger *log.Logger) (
 	return boshTasks, nil
 }
 
-func (c *Client) GetNormalisedTasksByContext(deploymentName, contextID string, logger *log.Logger) (BoshTasks, error) {
+func (c *Client) GetNormalisedTasksByContext(ctx context.Context, deploymentName, contextID string, logger *log.Logger) (_ BoshTasks, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-normalised-tasks-by-context")
+	defer func() { span.End(err) }()
+
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to build director")
@@ -54,7 +63,7 @@ func (c *Client) GetNormalisedTasksByContext(deploymentName, contextID string, l
 	var boshTasks BoshTasks
 	for _, task := range tasks {
 		if task.DeploymentName() == deploymentName {
-			taskState, err := c.fetchTaskState(task, logger)
+			taskState, err := c.fetchTaskState(ctx, task, logger)
 			if err != nil {
 				return nil, errors.Wrap(err, "Could not retrieve task output")
 			}
@@ -72,9 +81,9 @@ func (c *Client) GetNormalisedTasksByContext(deploymentName, contextID string, l
 
 // bosh status for failed errands is 'done', not 'error'
 // https://github.com/cloudfoundry/bosh/issues/1592
-func (c *Client) fetchTaskState(task director.Task, logger *log.Logger) (string, error) {
+func (c *Client) fetchTaskState(ctx context.Context, task director.Task, logger *log.Logger) (string, error) {
 	if task.State() == TaskDone {
-		taskOutput, err := c.GetTaskOutput(task.ID(), logger)
+		taskOutput, err := c.GetTaskOutput(ctx, task.ID(), logger)
 		if err != nil {
 			return "", err
 		}
@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -65,7 +66,7 @@ var _ = Describe("getting tasks", func() {
 		})
 
 		It("returns the tasks", func() {
-			actualTasks, err := c.GetTasksInProgress(deploymentName, logger)
+			actualTasks, err := c.GetTasksInProgress(context.Background(), deploymentName, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("fetching all tasks")
@@ -79,7 +80,7 @@ var _ = Describe("getting tasks", func() {
 		It("wraps the error when fetching current tasks fails", func() {
 			fakeDirector.CurrentTasksReturns([]director.Task{}, errors.New("boom"))
 
-			_, err := c.GetTasksInProgress(deploymentName, logger)
+			_, err := c.GetTasksInProgress(context.Background(), deploymentName, logger)
 			Expect(err).To(MatchError(fmt.Sprintf("Could not fetch current tasks for deployment %s: boom", deploymentName)))
 		})
 	})
@@ -121,7 +122,7 @@ var _ = Describe("getting tasks", func() {
 		})
 
 		It("returns no tasks when there are no tasks with the context id", func() {
-			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, "some-id", logger)
+			actualTasks, err := c.GetNormalisedTasksByContext(context.Background(), deploymentName, "some-id", logger)
 			Expect(actualTasks).To(HaveLen(0))
 			Expect(err).NotTo(HaveOccurred())
 		})
@@ -130,7 +131,7 @@ var _ = Describe("getting tasks", func() {
 			processingTask.ContextIDReturns(singleTaskContextID)
 			expectedTask := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "snapshot deployment", Result: "result-1", ContextID: singleTaskContextID}
 
-			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, singleTaskContextID, logger)
+			actualTasks, err := c.GetNormalisedTasksByContext(context.Background(), deploymentName, singleTaskContextID, logger)
 			Expect(actualTasks).To(HaveLen(1))
 			Expect(actualTasks[0]).To(Equal(expectedTask))
 			Expect(err).NotTo(HaveOccurred())
@@ -148,14 +149,14 @@ var _ = Describe("getting tasks", func() {
 
 			fakeDirector.FindTasksByContextIdReturns([]director.Task{processingTask, doneTask, otherDeploymentTask}, nil)
 
-			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, "some-context", logger)
+			actualTasks, err := c.GetNormalisedTasksByContext(context.Background(), deploymentName, "some-context", logger)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualTasks).To(Equal(expectedTasksWithID("some-context")))
 		})
 
 		It("returns the correct tasks when there are many tasks with the context id", func() {
-			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, multipleTaskContextID, logger)
+			actualTasks, err := c.GetNormalisedTasksByContext(context.Background(), deploymentName, multipleTaskContextID, logger)
 			Expect(actualTasks).To(HaveLen(2))
 			Expect(actualTasks).To(Equal(expectedTasksWithID(multipleTaskContextID)))
 			Expect(err).To(Not(HaveOccurred()))
@@ -164,7 +165,7 @@ var _ = Describe("getting tasks", func() {
 		It("wraps the error when it fails to fetch the tasks", func() {
 			fakeDirector.FindTasksByContextIdReturns(nil, errors.New("some error"))
 
-			_, err := c.GetNormalisedTasksByContext(deploymentName, "context-id", logger)
+			_, err := c.GetNormalisedTasksByContext(context.Background(), deploymentName, "context-id", logger)
 			Expect(err).To(MatchError(fmt.Sprintf("Could not fetch tasks for deployment %s with context id context-id: some error", deploymentName)))
 		})
 
@@ -184,7 +185,7 @@ var _ = Describe("getting tasks", func() {
 				ContextID:   errandTaskContextID,
 			}
 
-			actualTasks, err := c.GetNormalisedTasksByContext(deploymentName, errandTaskContextID, logger)
+			actualTasks, err := c.GetNormalisedTasksByContext(context.Background(), deploymentName, errandTaskContextID, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualTasks).To(Equal(boshdirector.BoshTasks{expectedTask}))
 		})
@@ -193,7 +194,7 @@ var _ = Describe("getting tasks", func() {
 			fakeDirector.FindTaskReturns(errandTask, nil)
 			errandTask.ResultOutputReturns(errors.New("some problem"))
 
-			_, err := c.GetNormalisedTasksByContext(deploymentName, errandTaskContextID, logger)
+			_, err := c.GetNormalisedTasksByContext(context.Background(), deploymentName, errandTaskContextID, logger)
 			Expect(err).To(MatchError(ContainSubstring("Could not retrieve task output")))
 		})
 	})
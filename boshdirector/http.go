@@ -2,6 +2,7 @@ package boshdirector
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -10,6 +11,8 @@ import (
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/cloudfoundry/bosh-utils/httpclient"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 type BoshHTTP struct {
@@ -26,7 +29,7 @@ func NewBoshHTTP(client *Client) HTTP {
 	}
 }
 
-func (b *BoshHTTP) RawGet(path string) (string, error) {
+func (b *BoshHTTP) RawGet(ctx context.Context, path string) (string, error) {
 	fileReporter := director.NewNoopFileReporter()
 	logger := boshlog.NewLogger(boshlog.LevelError)
 	config, err := b.client.directorConfig()
@@ -34,7 +37,7 @@ func (b *BoshHTTP) RawGet(path string) (string, error) {
 		return "", nil
 	}
 
-	hc, err := b.httpClient(config, logger)
+	hc, err := b.httpClient(ctx, config, logger)
 	if err != nil {
 		return "", err
 	}
@@ -48,7 +51,7 @@ func (b *BoshHTTP) RawGet(path string) (string, error) {
 	return string(w.Bytes()), nil
 }
 
-func (b *BoshHTTP) RawPost(path, data, contentType string) (string, error) {
+func (b *BoshHTTP) RawPost(ctx context.Context, path, data, contentType string) (string, error) {
 	fileReporter := director.NewNoopFileReporter()
 	logger := boshlog.NewLogger(boshlog.LevelError)
 	config, err := b.client.directorConfig()
@@ -56,7 +59,7 @@ func (b *BoshHTTP) RawPost(path, data, contentType string) (string, error) {
 		return "", nil
 	}
 
-	hc, err := b.httpClient(config, logger)
+	hc, err := b.httpClient(ctx, config, logger)
 	if err != nil {
 		return "", err
 	}
@@ -76,7 +79,7 @@ func (b *BoshHTTP) RawPost(path, data, contentType string) (string, error) {
 	return string(w), nil
 }
 
-func (b *BoshHTTP) RawDelete(path string) (string, error) {
+func (b *BoshHTTP) RawDelete(ctx context.Context, path string) (string, error) {
 	fileReporter := director.NewNoopFileReporter()
 	logger := boshlog.NewLogger(boshlog.LevelError)
 	config, err := b.client.directorConfig()
@@ -84,7 +87,7 @@ func (b *BoshHTTP) RawDelete(path string) (string, error) {
 		return "", nil
 	}
 
-	hc, err := b.httpClient(config, logger)
+	hc, err := b.httpClient(ctx, config, logger)
 	if err != nil {
 		return "", err
 	}
@@ -97,7 +100,7 @@ func (b *BoshHTTP) RawDelete(path string) (string, error) {
 	return string(r), nil
 }
 
-func (b *BoshHTTP) httpClient(config director.FactoryConfig, logger boshlog.Logger) (*httpclient.HTTPClient, error) {
+func (b *BoshHTTP) httpClient(ctx context.Context, config director.FactoryConfig, logger boshlog.Logger) (*httpclient.HTTPClient, error) {
 	certPool, err := config.CACertPool()
 	if err != nil {
 		return nil, err
@@ -123,7 +126,19 @@ func (b *BoshHTTP) httpClient(config director.FactoryConfig, logger boshlog.Logg
 	authedClient := director.NewAdjustableClient(retryClient, authAdjustment)
 
 	httpOpts := httpclient.Opts{NoRedactUrlQuery: true}
-	httpClient := httpclient.NewHTTPClientOpts(authedClient, logger, httpOpts)
+	httpClient := httpclient.NewHTTPClientOpts(tracingClient{client: authedClient, ctx: ctx}, logger, httpOpts)
 
 	return httpClient, nil
 }
+
+// tracingClient sets the traceparent header of the span in ctx on every
+// request, so that the director can continue the trace of the broker request.
+type tracingClient struct {
+	client httpclient.Client
+	ctx    context.Context
+}
+
+func (t tracingClient) Do(req *http.Request) (*http.Response, error) {
+	tracing.Inject(t.ctx, req.Header)
+	return t.client.Do(req)
+}
@@ -7,6 +7,7 @@
 package boshdirector
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -14,6 +15,8 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 const (
@@ -23,7 +26,10 @@ const (
 	uaaTypeString           = "uaa"
 )
 
-func (c *Client) GetInfo(logger *log.Logger) (Info, error) {
+func (c *Client) GetInfo(ctx context.Context, logger *log.Logger) (_ Info, err error) {
+	_, span := tracing.Start(ctx, "bosh.get-info")
+	defer func() { span.End(err) }()
+
 	var boshInfo Info
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/blang/semver/v4"
@@ -30,7 +31,7 @@ var _ = Describe("info", func() {
 				},
 			}, nil)
 
-			info, err := c.GetInfo(logger)
+			info, err := c.GetInfo(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 			expectedInfo := boshdirector.Info{
 				Version: "1.3262.0.0 (00000000)",
@@ -45,7 +46,7 @@ var _ = Describe("info", func() {
 
 		It("returns an error if the request fails", func() {
 			fakeDirector.InfoReturns(boshdir.Info{}, errors.New("oops"))
-			_, err := c.GetInfo(logger)
+			_, err := c.GetInfo(context.Background(), logger)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -53,7 +54,7 @@ var _ = Describe("info", func() {
 			fakeDirector.InfoReturns(boshdir.Info{
 				Version: "1.3262.0.0 (00000000)",
 			}, nil)
-			_, err := c.GetInfo(logger)
+			_, err := c.GetInfo(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -65,7 +66,7 @@ var _ = Describe("info", func() {
 					Options: map[string]interface{}{},
 				},
 			}, nil)
-			info, err := c.GetInfo(logger)
+			info, err := c.GetInfo(context.Background(), logger)
 			Expect(info).To(Equal(boshdirector.Info{}))
 			Expect(err).To(MatchError(ContainSubstring("Cannot retrieve UAA URL from info endpoint")))
 		})
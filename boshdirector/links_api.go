@@ -15,27 +15,32 @@
 package boshdirector
 
 import (
+	"context"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) GetDNSAddresses(deploymentName string, dnsRequest []config.BindingDNS) (map[string]string, error) {
+func (c *Client) GetDNSAddresses(ctx context.Context, deploymentName string, dnsRequest []config.BindingDNS) (_ map[string]string, err error) {
+	ctx, span := tracing.Start(ctx, "bosh.get-dns-addresses")
+	defer func() { span.End(err) }()
+
 	addresses := map[string]string{}
 	for _, req := range dnsRequest {
-		providerId, err := c.dnsRetriever.LinkProviderID(deploymentName, req.InstanceGroup, req.LinkProvider)
+		providerId, err := c.dnsRetriever.LinkProviderID(ctx, deploymentName, req.InstanceGroup, req.LinkProvider)
 		if err != nil {
 			return nil, err
 		}
-		consumerId, err := c.dnsRetriever.CreateLinkConsumer(providerId)
+		consumerId, err := c.dnsRetriever.CreateLinkConsumer(ctx, providerId)
 		if err != nil {
 			return nil, err
 		}
 
-		addr, err := c.dnsRetriever.GetLinkAddress(consumerId, req.Properties.AZS, req.Properties.Status)
+		addr, err := c.dnsRetriever.GetLinkAddress(ctx, consumerId, req.Properties.AZS, req.Properties.Status)
 		if err != nil {
 			return nil, err
 		}
 
-		c.dnsRetriever.DeleteLinkConsumer(consumerId)
+		c.dnsRetriever.DeleteLinkConsumer(ctx, consumerId)
 		addresses[req.Name] = addr
 	}
 	return addresses, nil
@@ -1,6 +1,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -31,7 +32,7 @@ var _ = Describe("LinksApi", func() {
 		})
 
 		It("returns a map of dns addresses", func() {
-			boshDnsAddresses, err := c.GetDNSAddresses("cf", []config.BindingDNS{
+			boshDnsAddresses, err := c.GetDNSAddresses(context.Background(), "cf", []config.BindingDNS{
 				{
 					Name:          "config-1",
 					LinkProvider:  "linker",
@@ -48,18 +49,20 @@ var _ = Describe("LinksApi", func() {
 			Expect(fakeDNSRetriever.GetLinkAddressCallCount()).To(Equal(1))
 			Expect(fakeDNSRetriever.DeleteLinkConsumerCallCount()).To(Equal(1))
 
-			deploymentName, instanceGroup, linkProvider := fakeDNSRetriever.LinkProviderIDArgsForCall(0)
+			_, deploymentName, instanceGroup, linkProvider := fakeDNSRetriever.LinkProviderIDArgsForCall(0)
 			Expect(deploymentName).To(Equal("cf"))
 			Expect(instanceGroup).To(Equal("doppler"))
 			Expect(linkProvider).To(Equal("linker"))
 
-			Expect(fakeDNSRetriever.CreateLinkConsumerArgsForCall(0)).To(Equal(providerID))
-			actualConsumerID, actualAzs, actualStatus := fakeDNSRetriever.GetLinkAddressArgsForCall(0)
+			_, actualProviderID := fakeDNSRetriever.CreateLinkConsumerArgsForCall(0)
+			Expect(actualProviderID).To(Equal(providerID))
+			_, actualConsumerID, actualAzs, actualStatus := fakeDNSRetriever.GetLinkAddressArgsForCall(0)
 			Expect(actualConsumerID).To(Equal(consumerID))
 			Expect(actualAzs).To(Equal(azs))
 			Expect(actualStatus).To(Equal(status))
 
-			Expect(fakeDNSRetriever.DeleteLinkConsumerArgsForCall(0)).To(Equal(consumerID))
+			_, deletedConsumerID := fakeDNSRetriever.DeleteLinkConsumerArgsForCall(0)
+			Expect(deletedConsumerID).To(Equal(consumerID))
 
 			Expect(boshDnsAddresses).To(Equal(map[string]string{"config-1": dopplerAddress}))
 		})
@@ -67,28 +70,28 @@ var _ = Describe("LinksApi", func() {
 		It("errors when requesting the provider id errors", func() {
 			fakeDNSRetriever.LinkProviderIDReturns("", errors.New("boom"))
 
-			_, err := c.GetDNSAddresses("cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
+			_, err := c.GetDNSAddresses(context.Background(), "cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
 			Expect(err).To(MatchError(ContainSubstring("boom")))
 		})
 
 		It("errors when creating the consumer errors", func() {
 			fakeDNSRetriever.CreateLinkConsumerReturns("", errors.New("pow"))
 
-			_, err := c.GetDNSAddresses("cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
+			_, err := c.GetDNSAddresses(context.Background(), "cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
 			Expect(err).To(MatchError(ContainSubstring("pow")))
 		})
 
 		It("errors when requesting the link address errors", func() {
 			fakeDNSRetriever.GetLinkAddressReturns("", errors.New("smash"))
 
-			_, err := c.GetDNSAddresses("cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
+			_, err := c.GetDNSAddresses(context.Background(), "cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
 			Expect(err).To(MatchError(ContainSubstring("smash")))
 		})
 
 		It("ignores errors when deleting the link fails", func() {
 			fakeDNSRetriever.DeleteLinkConsumerReturns(errors.New("kaboom"))
 
-			_, err := c.GetDNSAddresses("cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
+			_, err := c.GetDNSAddresses(context.Background(), "cf", []config.BindingDNS{{Name: "config-1", LinkProvider: "doppler", InstanceGroup: "doppler"}})
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
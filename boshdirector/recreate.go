@@ -1,14 +1,23 @@
 package boshdirector
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) Recreate(deploymentName, contextID string, logger *log.Logger, taskReporter *AsyncTaskReporter) (int, error) {
+func (c *Client) Recreate(ctx context.Context, deploymentName, contextID string, logger *log.Logger, taskReporter *AsyncTaskReporter) (taskID int, err error) {
+	_, span := tracing.Start(ctx, "bosh.recreate")
+	defer func() {
+		span.SetAttribute("bosh_task_id", taskID)
+		span.End(err)
+	}()
+
 	myDirector, err := c.Director(taskReporter)
 	if err != nil {
 		return 0, errors.Wrap(err, "Failed to build director")
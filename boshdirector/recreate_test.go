@@ -1,6 +1,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -38,7 +39,7 @@ var _ = Describe("recreating a deployment", func() {
 	})
 
 	It("calls recreate on the real bosh client lib", func() {
-		taskID, err := c.Recreate(deploymentName, contextID, logger, taskReporter)
+		taskID, err := c.Recreate(context.Background(), deploymentName, contextID, logger, taskReporter)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(taskID).To(Equal(41))
@@ -49,7 +50,7 @@ var _ = Describe("recreating a deployment", func() {
 
 	It("returns an error when the deployment cannot be found", func() {
 		fakeDirector.FindDeploymentReturns(nil, errors.New("cannot find that deployment"))
-		_, err := c.Recreate(deploymentName, contextID, logger, taskReporter)
+		_, err := c.Recreate(context.Background(), deploymentName, contextID, logger, taskReporter)
 
 		Expect(err.Error()).To(ContainSubstring("cannot find that deployment"))
 		Expect(err.Error()).To(ContainSubstring("BOSH CLI error"))
@@ -57,7 +58,7 @@ var _ = Describe("recreating a deployment", func() {
 
 	It("returns an error when the recreate cannot be started", func() {
 		fakeDeployment.RecreateReturns(errors.New("unable to recreate that deployment"))
-		_, err := c.Recreate(deploymentName, contextID, logger, taskReporter)
+		_, err := c.Recreate(context.Background(), deploymentName, contextID, logger, taskReporter)
 
 		Expect(err.Error()).To(ContainSubstring("unable to recreate that deployment"))
 		Expect(err.Error()).To(ContainSubstring("Could not recreate deployment"))
@@ -71,7 +72,7 @@ var _ = Describe("recreating a deployment", func() {
 		}
 		exited := make(chan bool)
 		go func() {
-			c.Recreate(deploymentName, contextID, logger, taskReporter)
+			c.Recreate(context.Background(), deploymentName, contextID, logger, taskReporter)
 			exited <- true
 		}()
 
@@ -7,10 +7,13 @@
 package boshdirector
 
 import (
+	"context"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 type Instance struct {
@@ -18,7 +21,13 @@ type Instance struct {
 	ID    string `json:"id,omitempty"`
 }
 
-func (c *Client) RunErrand(deploymentName, errandName string, errandInstances []string, contextID string, logger *log.Logger, taskReporter *AsyncTaskReporter) (int, error) {
+func (c *Client) RunErrand(ctx context.Context, deploymentName, errandName string, errandInstances []string, contextID string, logger *log.Logger, taskReporter *AsyncTaskReporter) (taskID int, err error) {
+	_, span := tracing.Start(ctx, "bosh.run-errand")
+	defer func() {
+		span.SetAttribute("bosh_task_id", taskID)
+		span.End(err)
+	}()
+
 	logger.Printf("running errand %s on instances %v from deployment %s\n", errandName, errandInstances, deploymentName)
 	d, err := c.Director(taskReporter)
 	if err != nil {
@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -41,7 +42,7 @@ var _ = Describe("running errands", func() {
 	})
 
 	It("invokes BOSH to queue up an errand", func() {
-		actualTaskID, actualErr := c.RunErrand(deploymentName, errandName, nil, contextID, logger, taskReporter)
+		actualTaskID, actualErr := c.RunErrand(context.Background(), deploymentName, errandName, nil, contextID, logger, taskReporter)
 		Expect(actualTaskID).To(Equal(taskId))
 		Expect(actualErr).NotTo(HaveOccurred())
 
@@ -56,7 +57,7 @@ var _ = Describe("running errands", func() {
 
 	It("invokes BOSH to queue up an errand with instances with group and ID when a specific instance is configured", func() {
 		errandInstances := []string{"errand_instance/4529480d-9770-4c32-b9bb-d936c0a908ca"}
-		actualTaskID, actualErr := c.RunErrand(deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
+		actualTaskID, actualErr := c.RunErrand(context.Background(), deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
 		Expect(actualTaskID).To(Equal(taskId))
 		Expect(actualErr).NotTo(HaveOccurred())
 
@@ -71,7 +72,7 @@ var _ = Describe("running errands", func() {
 
 	It("invokes BOSH to queue up an errand with instances with group only when an instance group is configured", func() {
 		errandInstances := []string{"errand_instance"}
-		actualTaskID, actualErr := c.RunErrand(deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
+		actualTaskID, actualErr := c.RunErrand(context.Background(), deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
 		Expect(actualTaskID).To(Equal(taskId))
 		Expect(actualErr).NotTo(HaveOccurred())
 
@@ -87,19 +88,19 @@ var _ = Describe("running errands", func() {
 	It("returns an error when the errandInstance names are invalid", func() {
 		errandInstances := []string{"some/invalid/errand"}
 
-		_, actualErr := c.RunErrand(deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
+		_, actualErr := c.RunErrand(context.Background(), deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
 		Expect(actualErr).To(MatchError(ContainSubstring("Invalid instance name")))
 	})
 
 	It("errors when finding deployment fails", func() {
 		fakeDirector.FindDeploymentReturns(nil, errors.New("some failure"))
-		_, actualErr := c.RunErrand("", errandName, nil, contextID, logger, taskReporter)
+		_, actualErr := c.RunErrand(context.Background(), "", errandName, nil, contextID, logger, taskReporter)
 		Expect(actualErr).To(MatchError(ContainSubstring("Could not find deployment")))
 	})
 
 	It("returns the error when bosh fails to queue up an errand", func() {
 		fakeDeployment.RunErrandReturns([]director.ErrandResult{}, errors.New("some errand failure"))
-		_, err := c.RunErrand(deploymentName, errandName, nil, contextID, logger, taskReporter)
+		_, err := c.RunErrand(context.Background(), deploymentName, errandName, nil, contextID, logger, taskReporter)
 
 		Expect(err).To(MatchError(ContainSubstring("Could not run errand")))
 	})
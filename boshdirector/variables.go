@@ -15,13 +15,19 @@
 package boshdirector
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) Variables(deploymentName string, logger *log.Logger) ([]Variable, error) {
+func (c *Client) Variables(ctx context.Context, deploymentName string, logger *log.Logger) (_ []Variable, err error) {
+	_, span := tracing.Start(ctx, "bosh.variables")
+	defer func() { span.End(err) }()
+
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
 		return nil, fmt.Errorf("failed to build director: %s", err)
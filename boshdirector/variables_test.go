@@ -15,6 +15,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -39,7 +40,7 @@ var _ = Describe("Variables", func() {
 			{Name: "bennett", ID: "456"},
 		}, nil)
 
-		variables, err := c.Variables("some-deployment", logger)
+		variables, err := c.Variables(context.Background(), "some-deployment", logger)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(variables).To(Equal([]boshdirector.Variable{
@@ -51,14 +52,14 @@ var _ = Describe("Variables", func() {
 	Describe("error handling", func() {
 		It("fails when the director can't be built", func() {
 			fakeDirectorFactory.NewReturns(nil, errors.New("boom"))
-			_, err := c.Variables("some-deployment", logger)
+			_, err := c.Variables(context.Background(), "some-deployment", logger)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError("failed to build director: boom"))
 		})
 
 		It("fails when the deployment can't be found", func() {
 			fakeDirector.FindDeploymentReturns(fakeDeployment, errors.New("boom"))
-			_, err := c.Variables("some-deployment", logger)
+			_, err := c.Variables(context.Background(), "some-deployment", logger)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError("can't find deployment with name 'some-deployment': boom"))
 		})
@@ -66,7 +67,7 @@ var _ = Describe("Variables", func() {
 		It("fails when the variables can't be retrieved", func() {
 			fakeDirector.FindDeploymentReturns(fakeDeployment, nil)
 			fakeDeployment.VariablesReturns(nil, errors.New("kaboom"))
-			_, err := c.Variables("some-deployment", logger)
+			_, err := c.Variables(context.Background(), "some-deployment", logger)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError("can't retrieve variables for deployment 'some-deployment': kaboom"))
 		})
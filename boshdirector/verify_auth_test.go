@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -38,7 +39,7 @@ var _ = Describe("verifying authentication credentials are correct", func() {
 	It("doesn't produce error when the credentials are correct", func() {
 		fakeDirector.IsAuthenticatedReturns(true, nil)
 
-		authErr := directorClient.VerifyAuth(logger)
+		authErr := directorClient.VerifyAuth(context.Background(), logger)
 
 		Expect(authErr).NotTo(HaveOccurred())
 	})
@@ -46,7 +47,7 @@ var _ = Describe("verifying authentication credentials are correct", func() {
 	It("produces an error when the credentials are incorrect", func() {
 		fakeDirector.IsAuthenticatedReturns(false, nil)
 
-		authErr := directorClient.VerifyAuth(logger)
+		authErr := directorClient.VerifyAuth(context.Background(), logger)
 
 		Expect(authErr).To(MatchError("not authenticated"))
 	})
@@ -55,7 +56,7 @@ var _ = Describe("verifying authentication credentials are correct", func() {
 		errMsg := "/info endpoint unreachable"
 		fakeDirector.IsAuthenticatedReturns(false, errors.New(errMsg))
 
-		err := directorClient.VerifyAuth(logger)
+		err := directorClient.VerifyAuth(context.Background(), logger)
 
 		Expect(err).To(MatchError(ContainSubstring(errMsg)))
 	})
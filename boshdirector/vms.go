@@ -7,14 +7,20 @@
 package boshdirector
 
 import (
+	"context"
 	"log"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) VMs(deploymentName string, logger *log.Logger) (bosh.BoshVMs, error) {
+func (c *Client) VMs(ctx context.Context, deploymentName string, logger *log.Logger) (_ bosh.BoshVMs, err error) {
+	_, span := tracing.Start(ctx, "bosh.v-ms")
+	defer func() { span.End(err) }()
+
 	logger.Printf("retrieving VMs for deployment %s from bosh\n", deploymentName)
 	d, err := c.Director(director.NewNoopTaskReporter())
 	if err != nil {
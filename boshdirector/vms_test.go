@@ -7,6 +7,7 @@
 package boshdirector_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-cli/v7/director"
@@ -33,7 +34,7 @@ var _ = Describe("Retriving VM Info", func() {
 	})
 
 	It("returns the vms for a particular deployment", func() {
-		vms, err := c.VMs(deploymentName, logger)
+		vms, err := c.VMs(context.Background(), deploymentName, logger)
 
 		By("finding the deployment")
 		Expect(fakeDirector.FindDeploymentCallCount()).To(Equal(1))
@@ -53,7 +54,7 @@ var _ = Describe("Retriving VM Info", func() {
 			{AgentID: "1", JobName: "other-instance-group", ID: "some-id", Index: &vmIndex[2], IPs: []string{"ip3"}},
 		}, nil)
 
-		vms, err := c.VMs(deploymentName, logger)
+		vms, err := c.VMs(context.Background(), deploymentName, logger)
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(vms).To(HaveLen(2))
@@ -63,13 +64,13 @@ var _ = Describe("Retriving VM Info", func() {
 
 	It("errors when finding deployment fails", func() {
 		fakeDirector.FindDeploymentReturns(nil, errors.New("some failure"))
-		_, err := c.VMs(deploymentName, logger)
+		_, err := c.VMs(context.Background(), deploymentName, logger)
 		Expect(err).To(MatchError(ContainSubstring("Could not find deployment")))
 	})
 
 	It("errors when fetching vm info fails", func() {
 		fakeDeployment.VMInfosReturns(nil, errors.New("some vm info error"))
-		_, err := c.VMs(deploymentName, logger)
+		_, err := c.VMs(context.Background(), deploymentName, logger)
 		Expect(err).To(MatchError(ContainSubstring("Could not fetch VMs info for deployment")))
 	})
 })
@@ -1,6 +1,7 @@
 package boshlinks
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -20,7 +21,7 @@ func NewDNSRetriever(boshHTTP boshdirector.HTTP) boshdirector.DNSRetriever {
 	}
 }
 
-func (d *DNSRetriever) GetLinkAddress(consumerLinkID string, azs []string, status string) (string, error) {
+func (d *DNSRetriever) GetLinkAddress(ctx context.Context, consumerLinkID string, azs []string, status string) (string, error) {
 	path := fmt.Sprintf("/link_address?link_id=%s", consumerLinkID)
 	for _, az := range azs {
 		path += "&azs[]=" + url.PathEscape(az)
@@ -34,7 +35,7 @@ func (d *DNSRetriever) GetLinkAddress(consumerLinkID string, azs []string, statu
 		path = fmt.Sprintf("%s&status=%s", path, status)
 	}
 
-	response, err := d.httpClient.RawGet(path)
+	response, err := d.httpClient.RawGet(ctx, path)
 	if err != nil {
 		return "", errors.Wrap(err, fmt.Sprintf("HTTP GET on %s endpoint failed: %s", path, response))
 	}
@@ -50,9 +51,9 @@ func (d *DNSRetriever) GetLinkAddress(consumerLinkID string, azs []string, statu
 	return respObj.Address, nil
 }
 
-func (d *DNSRetriever) LinkProviderID(deploymentName, instanceGroupName, providerName string) (string, error) {
+func (d *DNSRetriever) LinkProviderID(ctx context.Context, deploymentName, instanceGroupName, providerName string) (string, error) {
 	path := fmt.Sprintf("/link_providers?deployment=%s", deploymentName)
-	response, err := d.httpClient.RawGet(path)
+	response, err := d.httpClient.RawGet(ctx, path)
 	if err != nil {
 		return "", errors.Wrap(err, fmt.Sprintf("HTTP GET on %s endpoint failed: %s", path, response))
 	}
@@ -83,7 +84,7 @@ func (d *DNSRetriever) LinkProviderID(deploymentName, instanceGroupName, provide
 		deploymentName, instanceGroupName, providerName)
 }
 
-func (d *DNSRetriever) CreateLinkConsumer(providerID string) (string, error) {
+func (d *DNSRetriever) CreateLinkConsumer(ctx context.Context, providerID string) (string, error) {
 	payload := fmt.Sprintf(`
 	{
 		"link_provider_id":"%s",
@@ -96,7 +97,7 @@ func (d *DNSRetriever) CreateLinkConsumer(providerID string) (string, error) {
 	}
 	`, providerID)
 
-	response, err := d.httpClient.RawPost("/links", payload, "application/json")
+	response, err := d.httpClient.RawPost(ctx, "/links", payload, "application/json")
 	if err != nil {
 		return "", errors.Wrap(err, "HTTP POST on /links endpoint failed")
 	}
@@ -113,8 +114,8 @@ func (d *DNSRetriever) CreateLinkConsumer(providerID string) (string, error) {
 	return respObj.ID, nil
 }
 
-func (d *DNSRetriever) DeleteLinkConsumer(consumerID string) error {
-	response, err := d.httpClient.RawDelete(fmt.Sprintf("/links/%s", consumerID))
+func (d *DNSRetriever) DeleteLinkConsumer(ctx context.Context, consumerID string) error {
+	response, err := d.httpClient.RawDelete(ctx, fmt.Sprintf("/links/%s", consumerID))
 	return errors.Wrap(err, fmt.Sprintf("HTTP DELETE on /links/:id endpoint failed: %s", response))
 }
 
@@ -1,6 +1,7 @@
 package boshlinks_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -101,7 +102,7 @@ var _ = Describe("Boshlinks", func() {
 			providerName := "reverse_log_proxy"
 			linkId := "85"
 			fakeBoshHTTP.RawGetReturns(providerLinkListJSON, nil)
-			actualLinkId, err := subject.LinkProviderID(deploymentName, instanceGroupName, providerName)
+			actualLinkId, err := subject.LinkProviderID(context.Background(), deploymentName, instanceGroupName, providerName)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualLinkId).To(Equal(linkId))
 		})
@@ -111,7 +112,7 @@ var _ = Describe("Boshlinks", func() {
 			instanceGroupName := "log-api"
 			providerName := "not-there"
 			fakeBoshHTTP.RawGetReturns(providerLinkListJSON, nil)
-			_, err := subject.LinkProviderID(deploymentName, instanceGroupName, providerName)
+			_, err := subject.LinkProviderID(context.Background(), deploymentName, instanceGroupName, providerName)
 			Expect(err).To(MatchError(ContainSubstring("could not find link provider matching")))
 		})
 
@@ -120,7 +121,7 @@ var _ = Describe("Boshlinks", func() {
 			instanceGroupName := "credhub"
 			providerName := "private_link"
 			fakeBoshHTTP.RawGetReturns(providerLinkListJSON, nil)
-			_, err := subject.LinkProviderID(deploymentName, instanceGroupName, providerName)
+			_, err := subject.LinkProviderID(context.Background(), deploymentName, instanceGroupName, providerName)
 			Expect(err).To(MatchError(ContainSubstring("could not find link provider matching")))
 		})
 
@@ -129,7 +130,7 @@ var _ = Describe("Boshlinks", func() {
 			instanceGroupName := "log-api"
 			providerName := "reverse_log_proxy"
 			fakeBoshHTTP.RawGetReturns(`{}`, errors.New("something went wrong"))
-			_, err := subject.LinkProviderID(deploymentName, instanceGroupName, providerName)
+			_, err := subject.LinkProviderID(context.Background(), deploymentName, instanceGroupName, providerName)
 			Expect(err).To(MatchError(ContainSubstring("HTTP GET on /link_providers?deployment=cf endpoint failed")))
 		})
 
@@ -138,7 +139,7 @@ var _ = Describe("Boshlinks", func() {
 			instanceGroupName := "log-api"
 			providerName := "reverse_log_proxy"
 			fakeBoshHTTP.RawGetReturns(`{"a":"b"}`, nil)
-			_, err := subject.LinkProviderID(deploymentName, instanceGroupName, providerName)
+			_, err := subject.LinkProviderID(context.Background(), deploymentName, instanceGroupName, providerName)
 			Expect(err).To(MatchError(ContainSubstring("cannot unmarshal links provider JSON")))
 		})
 	})
@@ -155,20 +156,20 @@ var _ = Describe("Boshlinks", func() {
 
 			fakeBoshHTTP.RawPostReturns(consumerJSON, nil)
 			providerID := "2077"
-			actualLinkId, err := subject.CreateLinkConsumer(providerID)
+			actualLinkId, err := subject.CreateLinkConsumer(context.Background(), providerID)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualLinkId).To(Equal("3808"))
 		})
 
 		It("returns an error when the response is invalid JSON", func() {
 			fakeBoshHTTP.RawPostReturns(`[]`, nil)
-			_, err := subject.CreateLinkConsumer("123")
+			_, err := subject.CreateLinkConsumer(context.Background(), "123")
 			Expect(err).To(MatchError(ContainSubstring("cannot unmarshal create link consumer response")))
 		})
 
 		It("returns an error when RawPost errors", func() {
 			fakeBoshHTTP.RawPostReturns(`{}`, errors.New("something failed"))
-			_, err := subject.CreateLinkConsumer("123")
+			_, err := subject.CreateLinkConsumer(context.Background(), "123")
 			Expect(err).To(MatchError(ContainSubstring("HTTP POST on /links endpoint failed")))
 		})
 	})
@@ -176,13 +177,13 @@ var _ = Describe("Boshlinks", func() {
 	Describe("DeleteLinkConsumer", func() {
 		It("succeeds when the consumer link id exists", func() {
 			fakeBoshHTTP.RawDeleteReturns("", nil)
-			Expect(subject.DeleteLinkConsumer("3808")).To(Succeed())
+			Expect(subject.DeleteLinkConsumer(context.Background(), "3808")).To(Succeed())
 		})
 
 		It("returns an error when RawDelete errors", func() {
 			errResponse := `{"code": 810000, "description": "invalid link id 123"}`
 			fakeBoshHTTP.RawDeleteReturns(errResponse, errors.New("something failed"))
-			err := subject.DeleteLinkConsumer("123")
+			err := subject.DeleteLinkConsumer(context.Background(), "123")
 			Expect(err).To(MatchError(ContainSubstring("HTTP DELETE on /links/:id endpoint failed: " + errResponse)))
 		})
 	})
@@ -197,58 +198,58 @@ var _ = Describe("Boshlinks", func() {
 		It("returns the address when bosh get call is successful", func() {
 			azs := []string{"europe1"}
 			consumerLinkID := "123"
-			addr, err := subject.GetLinkAddress(consumerLinkID, azs, "healthy")
+			addr, err := subject.GetLinkAddress(context.Background(), consumerLinkID, azs, "healthy")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(addr).To(Equal("q-s0.dummy.default.dep-with-link.bosh"))
 
-			path := fakeBoshHTTP.RawGetArgsForCall(0)
+			_, path := fakeBoshHTTP.RawGetArgsForCall(0)
 			Expect(path).To(Equal("/link_address?link_id=123&azs[]=europe1&status=healthy"))
 		})
 
 		It("correctly supports multi-azs with special characters", func() {
 			azs := []string{"europe1", "europe/2"}
 			consumerLinkID := "123"
-			_, err := subject.GetLinkAddress(consumerLinkID, azs, "")
+			_, err := subject.GetLinkAddress(context.Background(), consumerLinkID, azs, "")
 			Expect(err).NotTo(HaveOccurred())
 
-			path := fakeBoshHTTP.RawGetArgsForCall(0)
+			_, path := fakeBoshHTTP.RawGetArgsForCall(0)
 			Expect(path).To(Equal("/link_address?link_id=123&azs[]=europe1&azs[]=europe%2F2"))
 		})
 
 		It("calls the api without azs when no azs are supplied", func() {
 			consumerLinkID := "123"
-			_, err := subject.GetLinkAddress(consumerLinkID, []string{}, "")
+			_, err := subject.GetLinkAddress(context.Background(), consumerLinkID, []string{}, "")
 			Expect(err).NotTo(HaveOccurred())
 
-			path := fakeBoshHTTP.RawGetArgsForCall(0)
+			_, path := fakeBoshHTTP.RawGetArgsForCall(0)
 			Expect(path).To(Equal("/link_address?link_id=123"))
 		})
 
 		It("calls the api without status when no status is supplied", func() {
 			consumerLinkID := "123"
-			_, err := subject.GetLinkAddress(consumerLinkID, []string{}, "")
+			_, err := subject.GetLinkAddress(context.Background(), consumerLinkID, []string{}, "")
 			Expect(err).NotTo(HaveOccurred())
 
-			path := fakeBoshHTTP.RawGetArgsForCall(0)
+			_, path := fakeBoshHTTP.RawGetArgsForCall(0)
 			Expect(path).To(Equal("/link_address?link_id=123"))
 		})
 
 		It("returns an error if status option is invalid", func() {
 			consumerLinkID := "123"
-			_, err := subject.GetLinkAddress(consumerLinkID, []string{}, "invalid-option")
+			_, err := subject.GetLinkAddress(context.Background(), consumerLinkID, []string{}, "invalid-option")
 
 			Expect(err).To(MatchError(ContainSubstring("status must be one of the following options: <default | healthy | unhealthy | all>")))
 		})
 
 		It("returns an error when RawGet errors", func() {
 			fakeBoshHTTP.RawGetReturns(`{}`, errors.New("something went wrong"))
-			_, err := subject.GetLinkAddress("123", nil, "healthy")
+			_, err := subject.GetLinkAddress(context.Background(), "123", nil, "healthy")
 			Expect(err).To(MatchError(ContainSubstring("HTTP GET on /link_address?link_id=123&status=healthy endpoint failed")))
 		})
 
 		It("returns an error when the response is not marshalable to obj", func() {
 			fakeBoshHTTP.RawGetReturns(`[]`, nil)
-			_, err := subject.GetLinkAddress("123", nil, "")
+			_, err := subject.GetLinkAddress(context.Background(), "123", nil, "")
 			Expect(err).To(MatchError(ContainSubstring("cannot unmarshal link address JSON")))
 		})
 	})
@@ -8,15 +8,20 @@ package broker
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
 	"github.com/pborman/uuid"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
@@ -27,67 +32,214 @@ func (b *Broker) Bind(
 	details domain.BindDetails,
 	asyncAllowed bool,
 ) (domain.Binding, error) {
-	b.bindLock.Lock()
-	defer b.bindLock.Unlock()
-
 	requestID := uuid.New()
 	if len(brokercontext.GetReqID(ctx)) > 0 {
 		requestID = brokercontext.GetReqID(ctx)
 	}
 
-	ctx = brokercontext.New(ctx, string(OperationTypeBind), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeBind), requestID, b.offering().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
-	if details.BindResource.BackupAgent && !b.SupportBackupAgentBinding {
-		return domain.Binding{}, b.processError(apiresponses.NewFailureResponse(
+	if b.EnableAsyncBinding && asyncAllowed {
+		return b.bindAsync(ctx, instanceID, bindingID, details, logger)
+	}
+
+	defer b.bindLocks.lock(instanceID)()
+
+	request, err := b.prepareBinding(ctx, instanceID, details, logger)
+	if err != nil {
+		return domain.Binding{}, b.processError(err, logger)
+	}
+
+	binding, err := b.createBinding(ctx, instanceID, bindingID, request, logger)
+	if err != nil {
+		b.notifyBinding(ctx, OperationTypeBind, LifecycleStateFailed, instanceID, bindingID, details.PlanID)
+		return domain.Binding{}, b.processError(err, logger)
+	}
+
+	if b.EnableAsyncBinding {
+		// the credentials are stored in CredHub by the broker wrapping this one
+		var credentialsRef string
+		if binding.Credentials != nil {
+			credentialsRef = BindingCredentialsKey(details.ServiceID, instanceID, bindingID)
+		}
+		record := succeededBinding(binding, credentialsRef, request.parameters)
+		if err := b.saveBinding(ctx, instanceID, bindingID, record, logger); err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error recording binding %s for instance %s: %s\n", bindingID, instanceID, err)
+		}
+	}
+	b.notifyBinding(ctx, OperationTypeBind, LifecycleStateSucceeded, instanceID, bindingID, details.PlanID)
+
+	return binding, nil
+}
+
+// bindAsync validates the request straight away, but leaves the call to the
+// service adapter to a background routine whose progress is reported by
+// LastBindingOperation. The binding is recorded in a BOSH config, and its
+// credentials stored in CredHub, so that it can be fetched later.
+func (b *Broker) bindAsync(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, logger *log.Logger) (domain.Binding, error) {
+	actor, err := BindingActor(details)
+	if err != nil {
+		return domain.Binding{}, b.processError(NewDisplayableError(err, err), logger)
+	}
+
+	request, err := b.prepareBinding(ctx, instanceID, details, logger)
+	if err != nil {
+		return domain.Binding{}, b.processError(err, logger)
+	}
+
+	operationData, err := json.Marshal(OperationData{OperationType: OperationTypeBind})
+	if err != nil {
+		return domain.Binding{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+
+	if err := b.startBinding(ctx, instanceID, bindingID, request.parameters, logger); err != nil {
+		return domain.Binding{}, b.processError(err, logger)
+	}
+	b.notifyBinding(ctx, OperationTypeBind, LifecycleStateStarted, instanceID, bindingID, details.PlanID)
+
+	// the binding outlives the request, so must not be cancelled with it
+	bindCtx := context.WithoutCancel(ctx)
+	bindLogger := b.loggerFactory.NewWithContext(bindCtx)
+	go func() {
+		defer b.bindLocks.lock(instanceID)()
+
+		record, err := b.createBindingInBackground(bindCtx, instanceID, bindingID, actor, details, request, bindLogger)
+		if err != nil {
+			record = bindingRecord{State: domain.Failed, Description: b.processError(err, bindLogger).Error()}
+		}
+		if err := b.saveBinding(bindCtx, instanceID, bindingID, record, bindLogger); err != nil {
+			loggerfactory.WithLevel(bindLogger, loggerfactory.ErrorLevel).Printf("error recording binding %s for instance %s: %s\n", bindingID, instanceID, err)
+		}
+
+		if record.State == domain.Failed {
+			b.notifyBinding(bindCtx, OperationTypeBind, LifecycleStateFailed, instanceID, bindingID, details.PlanID)
+			return
+		}
+		bindLogger.Printf("binding %s for instance %s created\n", bindingID, instanceID)
+		b.notifyBinding(bindCtx, OperationTypeBind, LifecycleStateSucceeded, instanceID, bindingID, details.PlanID)
+	}()
+
+	return domain.Binding{
+		IsAsync:       true,
+		OperationData: string(operationData),
+	}, nil
+}
+
+// startBinding records a new binding in progress, unless one is already in
+// progress for the same binding.
+func (b *Broker) startBinding(ctx context.Context, instanceID, bindingID string, parameters interface{}, logger *log.Logger) error {
+	defer b.bindLocks.lock(instanceID)()
+
+	record, found, err := b.getBinding(ctx, instanceID, bindingID, logger)
+	if err != nil {
+		return NewGenericError(ctx, err)
+	}
+	if found && record.State == domain.InProgress {
+		return NewDisplayableError(
+			apiresponses.ErrConcurrentInstanceAccess,
+			fmt.Errorf("binding %s for instance %s is already in progress", bindingID, instanceID),
+		)
+	}
+
+	record = bindingRecord{
+		State:       domain.InProgress,
+		Description: "Instance binding in progress",
+		Parameters:  parameters,
+		StartedAt:   time.Now().UTC(),
+	}
+	if err := b.saveBinding(ctx, instanceID, bindingID, record, logger); err != nil {
+		return NewGenericError(ctx, err)
+	}
+	return nil
+}
+
+func (b *Broker) createBindingInBackground(ctx context.Context, instanceID, bindingID, actor string, details domain.BindDetails, request bindingRequest, logger *log.Logger) (bindingRecord, error) {
+	binding, err := b.createBinding(ctx, instanceID, bindingID, request, logger)
+	if err != nil {
+		return bindingRecord{}, err
+	}
+
+	key := BindingCredentialsKey(details.ServiceID, instanceID, bindingID)
+	credentialsRef, err := b.storeBindingCredentials(key, actor, binding.Credentials, logger)
+	if err != nil {
+		return bindingRecord{}, NewGenericError(ctx, err)
+	}
+	return succeededBinding(binding, credentialsRef, request.parameters), nil
+}
+
+func succeededBinding(binding domain.Binding, credentialsRef string, parameters interface{}) bindingRecord {
+	return bindingRecord{
+		State:           domain.Succeeded,
+		Description:     "Instance binding completed",
+		CredentialsRef:  credentialsRef,
+		SyslogDrainURL:  binding.SyslogDrainURL,
+		RouteServiceURL: binding.RouteServiceURL,
+		VolumeMounts:    binding.VolumeMounts,
+		Parameters:      parameters,
+		StartedAt:       time.Now().UTC(),
+	}
+}
+
+type bindingRequest struct {
+	manifest     []byte
+	vms          bosh.BoshVMs
+	params       map[string]interface{}
+	parameters   interface{}
+	secretsMap   map[string]string
+	dnsAddresses map[string]string
+}
+
+func (b *Broker) prepareBinding(ctx context.Context, instanceID string, details domain.BindDetails, logger *log.Logger) (bindingRequest, error) {
+	if details.BindResource != nil && details.BindResource.BackupAgent && !b.SupportBackupAgentBinding {
+		return bindingRequest{}, apiresponses.NewFailureResponse(
 			errors.New("service does not support backup agent binding"),
 			http.StatusUnprocessableEntity,
 			"unsupported-binding-type",
-		), logger)
+		)
 	}
 
 	manifest, vms, deploymentErr := b.getDeploymentInfo(instanceID, ctx, "bind", logger)
 	if deploymentErr != nil {
-		return domain.Binding{}, b.processError(deploymentErr, logger)
+		return bindingRequest{}, deploymentErr
 	}
 
-	deploymentVariables, err := b.boshClient.Variables(deploymentName(instanceID), logger)
+	deploymentVariables, err := b.boshClient.Variables(ctx, deploymentName(instanceID), logger)
 	if err != nil {
-		logger.Printf("failed to retrieve deployment variables for deployment '%s': %s", deploymentName(instanceID), err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to retrieve deployment variables for deployment '%s': %s", deploymentName(instanceID), err)
 	}
 
-	secretsMap, err := b.secretManager.ResolveManifestSecrets(manifest, deploymentVariables, logger)
+	secretsMap, err := b.secretManager.ResolveManifestSecrets(ctx, manifest, deploymentVariables, logger)
 	if err != nil {
-		logger.Printf("failed to resolve manifest secrets: %s", err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to resolve manifest secrets: %s", err.Error())
 	}
 
-	logger.Printf("service adapter will create binding with ID %s for instance %s\n", bindingID, instanceID)
-
 	detailsWithRawParameters := domain.DetailsWithRawParameters(details)
 
 	mappedParams, err := convertDetailsToMap(detailsWithRawParameters)
 	if err != nil {
-		return domain.Binding{}, b.processError(NewGenericError(ctx, fmt.Errorf("converting to map %s", err)), logger)
+		return bindingRequest{}, NewGenericError(ctx, fmt.Errorf("converting to map %s", err))
 	}
 
-	plan, planFound := b.serviceOffering.FindPlanByID(details.PlanID)
+	serviceOffering := b.offering()
+	plan, planFound := serviceOffering.FindPlanByID(details.PlanID)
 
 	if b.EnablePlanSchemas {
 		if !planFound {
-			return domain.Binding{}, b.processError(NewDisplayableError(
+			return bindingRequest{}, NewDisplayableError(
 				fmt.Errorf("plan %s not found", details.PlanID),
 				fmt.Errorf("finding plan ID %s", details.PlanID),
-			), logger)
+			)
 		}
 
-		schemas, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		schemas, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(serviceOffering.GlobalProperties), logger)
 		if err != nil {
 			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
-				return domain.Binding{}, b.processError(err, logger)
+				return bindingRequest{}, adapterError(ctx, err, err)
 			}
 
 			logger.Println("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas")
-			return domain.Binding{}, b.processError(fmt.Errorf("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas"), logger)
+			return bindingRequest{}, fmt.Errorf("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas")
 		}
 
 		bindingCreateSchema := schemas.Binding.Create
@@ -95,22 +247,34 @@ func (b *Broker) Bind(
 
 		params, ok := mappedParams["parameters"].(map[string]interface{})
 		if !ok {
-			return domain.Binding{}, b.processError(NewGenericError(ctx, errors.New("converting parameters to map failed")), logger)
+			return bindingRequest{}, NewGenericError(ctx, errors.New("converting parameters to map failed"))
 		}
 
 		err = validator.ValidateParams(params)
 		if err != nil {
-			failureResp := apiresponses.NewFailureResponseBuilder(err, http.StatusBadRequest, "params-validation-failed").Build()
-			return domain.Binding{}, b.processError(failureResp, logger)
+			return bindingRequest{}, apiresponses.NewFailureResponseBuilder(err, http.StatusBadRequest, "params-validation-failed").Build()
 		}
 	}
 
-	dnsAddresses, err := b.boshClient.GetDNSAddresses(deploymentName(instanceID), plan.BindingWithDNS)
+	dnsAddresses, err := b.boshClient.GetDNSAddresses(ctx, deploymentName(instanceID), plan.BindingWithDNS)
 	if err != nil {
-		return domain.Binding{}, b.processError(NewGenericError(ctx, fmt.Errorf("failed to get required DNS info: %s", err)), logger)
+		return bindingRequest{}, NewGenericError(ctx, fmt.Errorf("failed to get required DNS info: %s", err))
 	}
 
-	binding, createBindingErr := b.adapterClient.CreateBinding(bindingID, vms, manifest, mappedParams, secretsMap, dnsAddresses, logger)
+	return bindingRequest{
+		manifest:     manifest,
+		vms:          vms,
+		params:       mappedParams,
+		parameters:   mappedParams["parameters"],
+		secretsMap:   secretsMap,
+		dnsAddresses: dnsAddresses,
+	}, nil
+}
+
+func (b *Broker) createBinding(ctx context.Context, instanceID, bindingID string, request bindingRequest, logger *log.Logger) (domain.Binding, error) {
+	logger.Printf("service adapter will create binding with ID %s for instance %s\n", bindingID, instanceID)
+
+	binding, createBindingErr := b.adapterClient.CreateBinding(ctx, bindingID, request.vms, request.manifest, request.params, request.secretsMap, request.dnsAddresses, logger)
 	if createBindingErr != nil {
 		if !b.EnableSecureManifests {
 			logger.Printf("broker.resolve_secrets_at_bind was: false ")
@@ -120,7 +284,7 @@ func (b *Broker) Bind(
 	}
 
 	if err := adapterToAPIError(ctx, createBindingErr); err != nil {
-		return domain.Binding{}, b.processError(err, logger)
+		return domain.Binding{}, err
 	}
 
 	return domain.Binding{
@@ -11,7 +11,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
@@ -118,7 +121,7 @@ var _ = Describe("Bind", func() {
 
 			bindResult, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, false)
 			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
-			passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
+			_, passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
 			Expect(passedBindingID).To(Equal(bindingID))
 			Expect(passedVms).To(Equal(boshVms))
 			Expect(passedManifest).To(Equal(actualManifest))
@@ -151,13 +154,13 @@ var _ = Describe("Bind", func() {
 
 		It("asks bosh for VMs from a deployment named by the manifest generator", func() {
 			Expect(boshClient.VMsCallCount()).To(Equal(1))
-			actualServiceDeploymentName, _ := boshClient.VMsArgsForCall(0)
+			_, actualServiceDeploymentName, _ := boshClient.VMsArgsForCall(0)
 			Expect(actualServiceDeploymentName).To(Equal(serviceDeploymentName))
 		})
 
 		It("creates the binding using the bosh topology, admin credentials and bosh dns addresses", func() {
 			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
-			passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
+			_, passedBindingID, passedVms, passedManifest, passedRequestParameters, _, passedDNSAddresses, _ := serviceAdapter.CreateBindingArgsForCall(0)
 			Expect(passedBindingID).To(Equal(bindingID))
 			Expect(passedVms).To(Equal(boshVms))
 			Expect(passedManifest).To(Equal(actualManifest))
@@ -559,7 +562,7 @@ var _ = Describe("Bind", func() {
 			bindResult, bindErr = broker.Bind(context.Background(), instanceID, bindingID, bindRequest, false)
 			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
 			Expect(fakeSecretManager.ResolveManifestSecretsCallCount()).To(Equal(1))
-			manifest, deploymentVariables, _ := fakeSecretManager.ResolveManifestSecretsArgsForCall(0)
+			_, manifest, deploymentVariables, _ := fakeSecretManager.ResolveManifestSecretsArgsForCall(0)
 			Expect(manifest).To(Equal(actualManifest))
 			Expect(deploymentVariables).To(Equal([]boshdirector.Variable{
 				{Path: "/foo/bar", ID: "123asd"},
@@ -639,8 +642,268 @@ var _ = Describe("Bind", func() {
 			})
 		})
 	})
+
+	When("async binding is enabled", func() {
+		var (
+			fakeCredentialStore *brokerfakes.FakeBindingCredentialStore
+			boshConfigs         *configStore
+		)
+
+		lastBindingOperation := func() domain.LastOperation {
+			lastOperation, err := b.LastBindingOperation(context.Background(), instanceID, bindingID, domain.PollDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			return lastOperation
+		}
+
+		BeforeEach(func() {
+			boshConfigs = newConfigStore(boshClient)
+			fakeCredentialStore = new(brokerfakes.FakeBindingCredentialStore)
+			brokerConfig.EnableAsyncBinding = true
+			b = createDefaultBroker()
+			b.SetBindingCredentialStore(fakeCredentialStore)
+		})
+
+		It("creates the binding in the background", func() {
+			bindResult, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).NotTo(HaveOccurred())
+
+			Expect(bindResult.IsAsync).To(BeTrue())
+			Expect(bindResult.Credentials).To(BeNil())
+			var operationData broker.OperationData
+			Expect(json.Unmarshal([]byte(bindResult.OperationData), &operationData)).To(Succeed())
+			Expect(operationData.OperationType).To(Equal(broker.OperationTypeBind))
+
+			Eventually(lastBindingOperation).Should(Equal(domain.LastOperation{
+				State:       domain.Succeeded,
+				Description: "Instance binding completed",
+			}))
+			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
+
+			credentialsRef := "/c/service_id/a-very-impressive-instance/binding-id/credentials"
+			By("storing the credentials in CredHub")
+			Expect(fakeCredentialStore.SetCallCount()).To(Equal(1))
+			key, value := fakeCredentialStore.SetArgsForCall(0)
+			Expect(key).To(Equal(credentialsRef))
+			Expect(value).To(Equal(adapterBindingResponse.Credentials))
+			Expect(fakeCredentialStore.AddPermissionCallCount()).To(Equal(1))
+			_, actor, ops := fakeCredentialStore.AddPermissionArgsForCall(0)
+			Expect(actor).To(Equal("mtls-app:app_guid"))
+			Expect(ops).To(Equal([]string{"read"}))
+
+			By("recording the binding in a BOSH config, without the credentials")
+			content, found := boshConfigs.get(broker.BindingsConfigType, serviceDeploymentName)
+			Expect(found).To(BeTrue())
+			Expect(content).To(ContainSubstring(credentialsRef))
+			Expect(content).NotTo(ContainSubstring(`"foo"`))
+
+			spec, err := b.GetBinding(context.Background(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec).To(Equal(domain.GetBindingSpec{
+				Credentials:     map[string]string{"credhub-ref": credentialsRef},
+				SyslogDrainURL:  adapterBindingResponse.SyslogDrainURL,
+				RouteServiceURL: adapterBindingResponse.RouteServiceURL,
+				Parameters:      arbitraryParameters,
+			}))
+		})
+
+		It("serves the binding from a broker that did not create it", func() {
+			bindResult, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).NotTo(HaveOccurred())
+			Eventually(lastBindingOperation).Should(HaveField("State", domain.Succeeded))
+
+			b = createDefaultBroker()
+
+			Expect(lastBindingOperation().State).To(Equal(domain.Succeeded))
+			spec, err := b.GetBinding(context.Background(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Credentials).To(HaveKey("credhub-ref"))
+		})
+
+		It("reports the binding as in progress until the adapter returns", func() {
+			adapterCalled := make(chan struct{})
+			serviceAdapter.CreateBindingStub = func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (sdk.Binding, error) {
+				<-adapterCalled
+				return adapterBindingResponse, nil
+			}
+
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).NotTo(HaveOccurred())
+
+			Expect(lastBindingOperation().State).To(Equal(domain.InProgress))
+
+			By("not retrieving the binding yet")
+			_, err := b.GetBinding(context.Background(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).To(Equal(apiresponses.ErrBindingNotFound))
+
+			By("refusing to start the same binding twice")
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).To(Equal(apiresponses.ErrConcurrentInstanceAccess))
+
+			close(adapterCalled)
+			Eventually(lastBindingOperation).Should(HaveField("State", domain.Succeeded))
+		})
+
+		It("records the binding after the request that started it is cancelled", func() {
+			saveConfig := boshClient.UpdateConfigStub
+			boshClient.UpdateConfigStub = func(ctx context.Context, configType, name string, content []byte, logger *log.Logger) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				return saveConfig(ctx, configType, name, content, logger)
+			}
+			adapterCalled := make(chan struct{})
+			serviceAdapter.CreateBindingStub = func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (sdk.Binding, error) {
+				<-adapterCalled
+				return adapterBindingResponse, nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			_, bindErr = b.Bind(ctx, instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).NotTo(HaveOccurred())
+			cancel()
+			close(adapterCalled)
+
+			Eventually(lastBindingOperation).Should(HaveField("State", domain.Succeeded))
+			_, err := b.GetBinding(context.Background(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("refuses to delete a binding in progress", func() {
+			boshConfigs.set(broker.BindingsConfigType, serviceDeploymentName, fmt.Sprintf(
+				`{"binding-id":{"state":"in progress","description":"Instance binding in progress","started_at":%q}}`,
+				time.Now().UTC().Format(time.RFC3339),
+			))
+
+			_, err := b.Unbind(context.Background(), instanceID, bindingID, domain.UnbindDetails{PlanID: existingPlanID}, true)
+			Expect(err).To(Equal(apiresponses.ErrConcurrentInstanceAccess))
+		})
+
+		It("reports a binding interrupted by a restart as failed", func() {
+			boshConfigs.set(broker.BindingsConfigType, serviceDeploymentName, fmt.Sprintf(
+				`{"binding-id":{"state":"in progress","description":"Instance binding in progress","started_at":%q}}`,
+				time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339),
+			))
+
+			Expect(lastBindingOperation()).To(Equal(domain.LastOperation{
+				State:       domain.Failed,
+				Description: "Instance binding was interrupted",
+			}))
+		})
+
+		It("reports a failure when the adapter fails", func() {
+			serviceAdapter.CreateBindingReturns(sdk.Binding{}, serviceadapter.NewUnknownFailureError("the cluster is on fire"))
+
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).NotTo(HaveOccurred())
+
+			Eventually(lastBindingOperation).Should(Equal(domain.LastOperation{
+				State:       domain.Failed,
+				Description: "the cluster is on fire",
+			}))
+
+			_, err := b.GetBinding(context.Background(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).To(Equal(apiresponses.ErrBindingNotFound))
+		})
+
+		It("reports a failure when the credentials cannot be stored", func() {
+			fakeCredentialStore.SetReturns(errors.New("credhub is down"))
+
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).NotTo(HaveOccurred())
+
+			Eventually(lastBindingOperation).Should(HaveField("State", domain.Failed))
+			Expect(lastBindingOperation().Description).To(ContainSubstring("There was a problem completing your request"))
+		})
+
+		It("fails straight away when the instance does not exist", func() {
+			boshClient.GetDeploymentReturns(nil, false, nil)
+
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, true)
+			Expect(bindErr).To(Equal(apiresponses.ErrInstanceDoesNotExist))
+			Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(0))
+
+			_, err := b.LastBindingOperation(context.Background(), instanceID, bindingID, domain.PollDetails{})
+			Expect(err).To(Equal(apiresponses.ErrBindingNotFound))
+		})
+
+		It("records bindings made synchronously, leaving their credentials to the CredHub broker", func() {
+			bindResult, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, false)
+			Expect(bindErr).NotTo(HaveOccurred())
+			Expect(bindResult.IsAsync).To(BeFalse())
+			Expect(bindResult.Credentials).To(Equal(adapterBindingResponse.Credentials))
+			Expect(fakeCredentialStore.SetCallCount()).To(Equal(0))
+
+			spec, err := b.GetBinding(context.Background(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Credentials).To(Equal(map[string]string{"credhub-ref": "/c/service_id/a-very-impressive-instance/binding-id/credentials"}))
+		})
+
+		It("forgets the binding once it is deleted", func() {
+			_, bindErr = b.Bind(context.Background(), instanceID, bindingID, bindRequest, false)
+			Expect(bindErr).NotTo(HaveOccurred())
+
+			_, err := b.Unbind(context.Background(), instanceID, bindingID, domain.UnbindDetails{PlanID: existingPlanID}, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = b.GetBinding(context.Background(), instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).To(Equal(apiresponses.ErrBindingNotFound))
+			_, found := boshConfigs.get(broker.BindingsConfigType, serviceDeploymentName)
+			Expect(found).To(BeFalse())
+		})
+	})
 })
 
+// configStore keeps the configs the fake BOSH client is sent, so that they can
+// be read back.
+type configStore struct {
+	lock    sync.Mutex
+	configs map[string]boshdirector.BoshConfig
+}
+
+func newConfigStore(boshClient *brokerfakes.FakeBoshClient) *configStore {
+	store := &configStore{configs: map[string]boshdirector.BoshConfig{}}
+	boshClient.GetConfigsStub = func(_ context.Context, name string, _ *log.Logger) ([]boshdirector.BoshConfig, error) {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+
+		var configs []boshdirector.BoshConfig
+		for _, c := range store.configs {
+			if c.Name == name {
+				configs = append(configs, c)
+			}
+		}
+		return configs, nil
+	}
+	boshClient.UpdateConfigStub = func(_ context.Context, configType, name string, content []byte, _ *log.Logger) error {
+		store.set(configType, name, string(content))
+		return nil
+	}
+	boshClient.DeleteConfigStub = func(_ context.Context, configType, name string, _ *log.Logger) (bool, error) {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+
+		_, found := store.configs[configType+"/"+name]
+		delete(store.configs, configType+"/"+name)
+		return found, nil
+	}
+	return store
+}
+
+func (s *configStore) get(configType, name string) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c, found := s.configs[configType+"/"+name]
+	return c.Content, found
+}
+
+func (s *configStore) set(configType, name, content string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.configs[configType+"/"+name] = boshdirector.BoshConfig{Type: configType, Name: name, Content: content}
+}
+
 func generateBindRequestWithParams(params map[string]interface{}) domain.BindDetails {
 	serialisedArbitraryParameters, err := json.Marshal(params)
 	Expect(err).NotTo(HaveOccurred())
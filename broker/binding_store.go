@@ -0,0 +1,183 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// BindingsConfigType is the BOSH config type under which the broker records
+// the bindings created while async binding is enabled, so that any broker
+// process can report their progress and return them, including after a
+// restart. Credentials are not recorded: they are stored in CredHub, and the
+// bindings refer to them. The config is named after the deployment, so it is
+// removed together with the instance.
+const BindingsConfigType = "odb-bindings"
+
+// bindingExpiry bounds how long a binding is reported as in progress, as a
+// binding whose broker process stopped before it completed never will.
+const bindingExpiry = time.Hour
+
+// BindingCredentialStore is where the credentials of bindings created in the
+// background are stored, so that the platform can fetch them by reference.
+//
+//counterfeiter:generate -o fakes/fake_binding_credential_store.go . BindingCredentialStore
+type BindingCredentialStore interface {
+	Set(key string, value interface{}) error
+	AddPermission(credentialName, actor string, ops []string) (*permissions.Permission, error)
+}
+
+type bindingRecord struct {
+	State           domain.LastOperationState `json:"state"`
+	Description     string                    `json:"description"`
+	CredentialsRef  string                    `json:"credentials_ref,omitempty"`
+	SyslogDrainURL  string                    `json:"syslog_drain_url,omitempty"`
+	RouteServiceURL string                    `json:"route_service_url,omitempty"`
+	VolumeMounts    []domain.VolumeMount      `json:"volume_mounts,omitempty"`
+	Parameters      interface{}               `json:"parameters,omitempty"`
+	StartedAt       time.Time                 `json:"started_at"`
+}
+
+// SetBindingCredentialStore sets where the credentials of bindings created in
+// the background are stored. It is required when async binding is enabled.
+func (b *Broker) SetBindingCredentialStore(store BindingCredentialStore) {
+	b.bindingCredentials = store
+}
+
+// BindingCredentialsKey is the CredHub path of the credentials of a binding.
+func BindingCredentialsKey(serviceID, instanceID, bindingID string) string {
+	return fmt.Sprintf("/c/%s/%s/%s/credentials", serviceID, instanceID, bindingID)
+}
+
+// BindingActor is who is given access to the credentials of a binding in
+// CredHub: the app, or the UAA client, it is for.
+func BindingActor(details domain.BindDetails) (string, error) {
+	switch {
+	case details.AppGUID != "":
+		return fmt.Sprintf("mtls-app:%s", details.AppGUID), nil
+	case details.BindResource != nil && details.BindResource.AppGuid != "":
+		return fmt.Sprintf("mtls-app:%s", details.BindResource.AppGuid), nil
+	case details.BindResource != nil && details.BindResource.CredentialClientID != "":
+		return fmt.Sprintf("uaa-client:%s", details.BindResource.CredentialClientID), nil
+	default:
+		return "", errors.New("No app-guid or credential client ID were provided in the binding request, you must configure one of these")
+	}
+}
+
+func (b *Broker) getBindings(ctx context.Context, instanceID string, logger *log.Logger) (map[string]bindingRecord, error) {
+	configs, err := b.boshClient.GetConfigs(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := map[string]bindingRecord{}
+	for _, c := range configs {
+		if c.Type != BindingsConfigType {
+			continue
+		}
+		if err := json.Unmarshal([]byte(c.Content), &bindings); err != nil {
+			return nil, fmt.Errorf("error parsing the bindings of %s: %s", instanceID, err)
+		}
+	}
+	return bindings, nil
+}
+
+// getBinding returns the record of a binding. A binding that has been in
+// progress for longer than bindingExpiry is reported as failed.
+func (b *Broker) getBinding(ctx context.Context, instanceID, bindingID string, logger *log.Logger) (bindingRecord, bool, error) {
+	bindings, err := b.getBindings(ctx, instanceID, logger)
+	if err != nil {
+		return bindingRecord{}, false, err
+	}
+
+	record, found := bindings[bindingID]
+	if found && record.State == domain.InProgress && time.Since(record.StartedAt) > bindingExpiry {
+		record.State = domain.Failed
+		record.Description = "Instance binding was interrupted"
+	}
+	return record, found, nil
+}
+
+// saveBinding records a binding. Callers hold the bind lock of the instance,
+// as the bindings of an instance share a config.
+func (b *Broker) saveBinding(ctx context.Context, instanceID, bindingID string, record bindingRecord, logger *log.Logger) error {
+	bindings, err := b.getBindings(ctx, instanceID, logger)
+	if err != nil {
+		return err
+	}
+	bindings[bindingID] = record
+	return b.saveBindings(ctx, instanceID, bindings, logger)
+}
+
+// deleteBinding removes the record of a binding, and the config once no
+// bindings are left. Callers hold the bind lock of the instance.
+func (b *Broker) deleteBinding(ctx context.Context, instanceID, bindingID string, logger *log.Logger) error {
+	bindings, err := b.getBindings(ctx, instanceID, logger)
+	if err != nil {
+		return err
+	}
+	if _, found := bindings[bindingID]; !found {
+		return nil
+	}
+
+	delete(bindings, bindingID)
+	if len(bindings) == 0 {
+		_, err := b.boshClient.DeleteConfig(ctx, BindingsConfigType, deploymentName(instanceID), logger)
+		return err
+	}
+	return b.saveBindings(ctx, instanceID, bindings, logger)
+}
+
+func (b *Broker) saveBindings(ctx context.Context, instanceID string, bindings map[string]bindingRecord, logger *log.Logger) error {
+	content, err := json.Marshal(bindings)
+	if err != nil {
+		return err
+	}
+	return b.boshClient.UpdateConfig(ctx, BindingsConfigType, deploymentName(instanceID), content, logger)
+}
+
+// storeBindingCredentials stores the credentials of a binding in CredHub,
+// readable by actor, and returns the reference to them.
+func (b *Broker) storeBindingCredentials(key, actor string, credentials interface{}, logger *log.Logger) (string, error) {
+	if credentials == nil {
+		return "", nil
+	}
+	if b.bindingCredentials == nil {
+		return "", errors.New("no credential store is configured for asynchronous bindings")
+	}
+
+	if err := b.bindingCredentials.Set(key, credentials); err != nil {
+		return "", fmt.Errorf("failed to set credentials in credential store: %v", err)
+	}
+	if _, err := b.bindingCredentials.AddPermission(key, actor, []string{"read"}); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to grant %s access to credentials %s: %s\n", actor, key, err)
+	}
+	return key, nil
+}
+
+func (r bindingRecord) bindingSpec() domain.GetBindingSpec {
+	spec := domain.GetBindingSpec{
+		SyslogDrainURL:  r.SyslogDrainURL,
+		RouteServiceURL: r.RouteServiceURL,
+		VolumeMounts:    r.VolumeMounts,
+		Parameters:      r.Parameters,
+	}
+	if r.CredentialsRef != "" {
+		spec.Credentials = map[string]string{"credhub-ref": r.CredentialsRef}
+	}
+	return spec
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+// BoshInstanceCounter counts the service instances of an offering from the
+// BOSH deployments of the broker and the plans recorded in their instance
+// metadata, so that quotas and metrics work on platforms other than CF.
+// Deployments with no recorded plan, as those created before the broker
+// recorded instance metadata, are not counted.
+type BoshInstanceCounter struct {
+	boshClient BoshClient
+	offerings  *config.OfferingStore
+}
+
+func NewBoshInstanceCounter(boshClient BoshClient, offerings *config.OfferingStore) *BoshInstanceCounter {
+	return &BoshInstanceCounter{boshClient: boshClient, offerings: offerings}
+}
+
+func (c *BoshInstanceCounter) CountInstancesOfServiceOffering(ctx context.Context, serviceOfferingID string, logger *log.Logger) (map[cf.ServicePlan]int, error) {
+	serviceOffering, err := c.serviceOffering(serviceOfferingID)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := c.instances(ctx, serviceOffering, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := emptyCounts(serviceOffering)
+	for _, metadata := range instances {
+		counts[cfServicePlan(serviceOffering, metadata.PlanID)]++
+	}
+	return counts, nil
+}
+
+func (c *BoshInstanceCounter) CountInstancesOfServiceOfferingByOrgAndSpace(ctx context.Context, serviceOfferingID string, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	serviceOffering, err := c.serviceOffering(serviceOfferingID)
+	if err != nil {
+		return cf.OrgAndSpaceInstanceCounts{}, err
+	}
+	instances, err := c.instances(ctx, serviceOffering, logger)
+	if err != nil {
+		return cf.OrgAndSpaceInstanceCounts{}, err
+	}
+
+	counts := cf.OrgAndSpaceInstanceCounts{
+		Orgs:   map[string]map[cf.ServicePlan]int{},
+		Spaces: map[string]map[cf.ServicePlan]int{},
+	}
+	for _, metadata := range instances {
+		if metadata.OrgGUID != "" {
+			if counts.Orgs[metadata.OrgGUID] == nil {
+				counts.Orgs[metadata.OrgGUID] = map[cf.ServicePlan]int{}
+			}
+			counts.Orgs[metadata.OrgGUID][cfServicePlan(serviceOffering, metadata.PlanID)]++
+		}
+		if metadata.SpaceGUID != "" {
+			if counts.Spaces[metadata.SpaceGUID] == nil {
+				counts.Spaces[metadata.SpaceGUID] = map[cf.ServicePlan]int{}
+			}
+			counts.Spaces[metadata.SpaceGUID][cfServicePlan(serviceOffering, metadata.PlanID)]++
+		}
+	}
+	return counts, nil
+}
+
+func (c *BoshInstanceCounter) serviceOffering(serviceOfferingID string) (config.ServiceOffering, error) {
+	offering := c.offerings.Offering(serviceOfferingID)
+	if offering == nil {
+		return config.ServiceOffering{}, fmt.Errorf("no service offering with ID %s", serviceOfferingID)
+	}
+	return offering.ServiceCatalog, nil
+}
+
+// instances returns the metadata of the deployed instances of the offering.
+func (c *BoshInstanceCounter) instances(ctx context.Context, serviceOffering config.ServiceOffering, logger *log.Logger) ([]InstanceMetadata, error) {
+	deployments, err := c.boshClient.GetDeployments(ctx, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := c.boshClient.GetConfigsOfType(ctx, InstanceMetadataConfigType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataByDeployment := map[string]InstanceMetadata{}
+	for _, boshConfig := range configs {
+		var metadata InstanceMetadata
+		if err := json.Unmarshal([]byte(boshConfig.Content), &metadata); err != nil {
+			logger.Printf("ignoring instance metadata of %s that cannot be parsed: %s\n", boshConfig.Name, err)
+			continue
+		}
+		metadataByDeployment[boshConfig.Name] = metadata
+	}
+
+	var instances []InstanceMetadata
+	for _, deployment := range deployments {
+		if !strings.HasPrefix(deployment.Name, InstancePrefix) {
+			continue
+		}
+		metadata, found := metadataByDeployment[deployment.Name]
+		if !found || metadata.PlanID == "" {
+			logger.Printf("no plan recorded for deployment %s, it is not counted\n", deployment.Name)
+			continue
+		}
+		// deployments of the other offerings of the broker share the director
+		if _, found := serviceOffering.FindPlanByID(metadata.PlanID); !found {
+			continue
+		}
+		instances = append(instances, metadata)
+	}
+	return instances, nil
+}
+
+// emptyCounts has every plan of the offering, as the counts of the CF API do.
+func emptyCounts(serviceOffering config.ServiceOffering) map[cf.ServicePlan]int {
+	counts := map[cf.ServicePlan]int{}
+	for _, plan := range serviceOffering.Plans {
+		counts[cfServicePlan(serviceOffering, plan.ID)] = 0
+	}
+	return counts
+}
+
+func cfServicePlan(serviceOffering config.ServiceOffering, planID string) cf.ServicePlan {
+	plan, _ := serviceOffering.FindPlanByID(planID)
+	return cf.ServicePlan{ServicePlanEntity: cf.ServicePlanEntity{UniqueID: plan.ID, Name: plan.Name}}
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+var _ = Describe("BoshInstanceCounter", func() {
+	var (
+		counter *broker.BoshInstanceCounter
+		logger  *log.Logger
+	)
+
+	plan := func(id, name string) cf.ServicePlan {
+		return cf.ServicePlan{ServicePlanEntity: cf.ServicePlanEntity{UniqueID: id, Name: name}}
+	}
+
+	metadataConfig := func(deployment string, metadata broker.InstanceMetadata) boshdirector.BoshConfig {
+		content, err := json.Marshal(metadata)
+		Expect(err).NotTo(HaveOccurred())
+		return boshdirector.BoshConfig{Type: broker.InstanceMetadataConfigType, Name: deployment, Content: string(content)}
+	}
+
+	BeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		counter = broker.NewBoshInstanceCounter(boshClient, config.NewOfferingStore([]config.OfferingConfig{{ServiceCatalog: serviceCatalog}}))
+
+		boshClient.GetDeploymentsReturns([]boshdirector.Deployment{
+			{Name: "service-instance_one"},
+			{Name: "service-instance_two"},
+			{Name: "service-instance_three"},
+			{Name: "service-instance_of-another-offering"},
+			{Name: "service-instance_without-metadata"},
+			{Name: "cf"},
+		}, nil)
+		boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+			metadataConfig("service-instance_one", broker.InstanceMetadata{PlanID: existingPlanID, OrgGUID: "an-org", SpaceGUID: "a-space"}),
+			metadataConfig("service-instance_two", broker.InstanceMetadata{PlanID: existingPlanID, OrgGUID: "an-org", SpaceGUID: "another-space"}),
+			metadataConfig("service-instance_three", broker.InstanceMetadata{PlanID: secondPlanID}),
+			metadataConfig("service-instance_of-another-offering", broker.InstanceMetadata{PlanID: "a-plan-of-another-offering"}),
+			metadataConfig("service-instance_deleted", broker.InstanceMetadata{PlanID: existingPlanID}),
+		}, nil)
+	})
+
+	It("counts the deployed instances of each plan from their metadata", func() {
+		counts, err := counter.CountInstancesOfServiceOffering(context.Background(), serviceOfferingID, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(counts).To(HaveKeyWithValue(plan(existingPlanID, existingPlanName), 2))
+		Expect(counts).To(HaveKeyWithValue(plan(secondPlanID, secondPlan.Name), 1))
+		Expect(counts).To(HaveLen(len(serviceCatalog.Plans)))
+		for _, p := range serviceCatalog.Plans {
+			Expect(counts).To(HaveKey(plan(p.ID, p.Name)))
+		}
+
+		_, configType, _ := boshClient.GetConfigsOfTypeArgsForCall(0)
+		Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+		Expect(logBuffer.String()).To(ContainSubstring("no plan recorded for deployment service-instance_without-metadata, it is not counted"))
+	})
+
+	It("counts the deployed instances by org and space", func() {
+		counts, err := counter.CountInstancesOfServiceOfferingByOrgAndSpace(context.Background(), serviceOfferingID, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		existing := plan(existingPlanID, existingPlanName)
+		Expect(counts.Orgs).To(Equal(map[string]map[cf.ServicePlan]int{"an-org": {existing: 2}}))
+		Expect(counts.Spaces).To(Equal(map[string]map[cf.ServicePlan]int{
+			"a-space":       {existing: 1},
+			"another-space": {existing: 1},
+		}))
+	})
+
+	It("returns an error when the deployments cannot be listed", func() {
+		boshClient.GetDeploymentsReturns(nil, errors.New("no deployments"))
+
+		_, err := counter.CountInstancesOfServiceOffering(context.Background(), serviceOfferingID, logger)
+		Expect(err).To(MatchError("no deployments"))
+	})
+
+	It("returns an error when the instance metadata cannot be listed", func() {
+		boshClient.GetConfigsOfTypeReturns(nil, errors.New("no configs"))
+
+		_, err := counter.CountInstancesOfServiceOfferingByOrgAndSpace(context.Background(), serviceOfferingID, logger)
+		Expect(err).To(MatchError("no configs"))
+	})
+})
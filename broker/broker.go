@@ -7,6 +7,7 @@
 package broker
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -26,27 +27,35 @@ import (
 )
 
 type Broker struct {
-	boshClient     BoshClient
-	cfClient       CloudFoundryClient
-	adapterClient  ServiceAdapterClient
-	deployer       Deployer
-	secretManager  ManifestSecretManager
-	instanceLister service.InstanceLister
-	hasher         Hasher
-	deploymentLock *sync.Mutex
-	bindLock       *sync.Mutex
-
-	serviceOffering           config.ServiceOffering
+	boshClient         BoshClient
+	cfClient           CloudFoundryClient
+	instanceCounter    InstanceCounter
+	adapterClient      ServiceAdapterClient
+	deployer           Deployer
+	secretManager      ManifestSecretManager
+	instanceLister     service.InstanceLister
+	hasher             Hasher
+	deploymentLocks    *instanceLocker
+	bindLocks          *instanceLocker
+	adapterLimiter     *AdapterLimiter
+	bindingCredentials BindingCredentialStore
+
+	offerings                 *config.OfferingStore
+	serviceOfferingID         string
 	ExposeOperationalErrors   bool
 	EnablePlanSchemas         bool
 	EnableSecureManifests     bool
 	SupportBackupAgentBinding bool
+	EnableAsyncBinding        bool
 	DisableBoshConfigs        bool
 
 	loggerFactory   *loggerfactory.LoggerFactory
 	telemetryLogger TelemetryLogger
+	eventNotifier   EventNotifier
 	catalogLock     sync.Mutex
 	cachedCatalog   []domain.Service
+	catalogOffering *config.OfferingConfig
+	quotaLock       sync.Mutex
 
 	decider Decider
 
@@ -68,28 +77,39 @@ func New(
 	telemetryLogger TelemetryLogger,
 	decider Decider,
 ) (*Broker, error) {
+	limiter := NewAdapterLimiter(brokerConfig.MaxConcurrentAdapterCalls)
 	b := &Broker{
 		boshClient:                boshClient,
 		cfClient:                  cfClient,
-		adapterClient:             serviceAdapter,
-		deployer:                  deployer,
-		deploymentLock:            &sync.Mutex{},
-		bindLock:                  &sync.Mutex{},
-		serviceOffering:           serviceOffering,
+		instanceCounter:           cfClient,
+		adapterClient:             limitedAdapterClient{ServiceAdapterClient: serviceAdapter, limiter: limiter},
+		deployer:                  limitedDeployer{Deployer: deployer, limiter: limiter},
+		deploymentLocks:           newInstanceLocker(),
+		bindLocks:                 newInstanceLocker(),
+		adapterLimiter:            limiter,
 		ExposeOperationalErrors:   brokerConfig.ExposeOperationalErrors,
 		EnablePlanSchemas:         brokerConfig.EnablePlanSchemas,
 		EnableSecureManifests:     brokerConfig.EnableSecureManifests,
 		DisableBoshConfigs:        brokerConfig.DisableBoshConfigs,
 		SupportBackupAgentBinding: brokerConfig.SupportBackupAgentBinding,
+		EnableAsyncBinding:        brokerConfig.EnableAsyncBinding,
 		secretManager:             manifestSecretManager,
 		instanceLister:            instanceLister,
 		hasher:                    hasher,
 		loggerFactory:             loggerFactory,
 		telemetryLogger:           telemetryLogger,
+		eventNotifier:             noopEventNotifier{},
 		decider:                   decider,
 		uaaClient:                 &uaa.Client{},
 	}
 
+	b.offerings = config.NewOfferingStore([]config.OfferingConfig{{ServiceCatalog: serviceOffering}})
+	b.serviceOfferingID = serviceOffering.ID
+
+	if brokerConfig.InstanceCounter == config.InstanceCounterBOSH {
+		b.instanceCounter = NewBoshInstanceCounter(boshClient, b.offerings)
+	}
+
 	var startupCheckErrMessages []string
 
 	for _, checker := range startupCheckers {
@@ -105,9 +125,26 @@ func New(
 	return b, nil
 }
 
+// offering returns the service offering the broker currently serves. Requests
+// load it once and pass it down, so that they see a single configuration when
+// it is reloaded while they are in progress.
+func (b *Broker) offering() config.ServiceOffering {
+	return b.offerings.Offering(b.serviceOfferingID).ServiceCatalog
+}
+
+// SetOfferingStore makes the broker serve the offering with its ID in
+// offerings, so that it is reloaded with the other components that share them.
+// It must be called before the broker serves requests.
+func (b *Broker) SetOfferingStore(offerings *config.OfferingStore) {
+	b.offerings = offerings
+	if counter, ok := b.instanceCounter.(*BoshInstanceCounter); ok {
+		counter.offerings = offerings
+	}
+}
+
 func (b *Broker) processError(err error, logger *log.Logger) error {
 	if err != nil {
-		logger.Println(err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Println(err)
 	}
 	switch processedError := err.(type) {
 	case DisplayableError:
@@ -127,14 +164,16 @@ func (b *Broker) SetUAAClient(uaaClient UAAClient) {
 const (
 	ComponentName = "on-demand-service-broker"
 
-	OperationTypeCreate      = OperationType("create")
-	OperationTypeUpdate      = OperationType("update")
-	OperationTypeUpgrade     = OperationType("upgrade")
-	OperationTypeRecreate    = OperationType("recreate")
-	OperationTypeDelete      = OperationType("delete")
-	OperationTypeForceDelete = OperationType("force-delete")
-	OperationTypeBind        = OperationType("bind")
-	OperationTypeUnbind      = OperationType("unbind")
+	OperationTypeCreate        = OperationType("create")
+	OperationTypeUpdate        = OperationType("update")
+	OperationTypeUpgrade       = OperationType("upgrade")
+	OperationTypeRecreate      = OperationType("recreate")
+	OperationTypeRollback      = OperationType("rollback")
+	OperationTypeRotateSecrets = OperationType("rotate-secrets")
+	OperationTypeDelete        = OperationType("delete")
+	OperationTypeForceDelete   = OperationType("force-delete")
+	OperationTypeBind          = OperationType("bind")
+	OperationTypeUnbind        = OperationType("unbind")
 
 	MinimumCFVersion                                     = "2.57.0"
 	MinimumMajorStemcellDirectorVersionForODB            = 3262
@@ -151,6 +190,7 @@ type OperationData struct {
 	PostDeployErrand PostDeployErrand // DEPRECATED: only needed for compatibility with ODB 0.20.x
 	PreDeleteErrand  PreDeleteErrand  // DEPRECATED: only needed for compatibility with ODB 0.20.x
 	Errands          []config.Errand  `json:",omitempty"`
+	QuotaReserved    bool             `json:",omitempty"`
 }
 
 type Errand struct {
@@ -201,54 +241,80 @@ type StartupChecker interface {
 
 //counterfeiter:generate -o fakes/fake_deployer.go . Deployer
 type Deployer interface {
-	Create(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error)
-	Update(deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, secretsMap, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error)
-	Upgrade(deploymentName string, plan config.Plan, requestParams map[string]interface{}, boshContextID string, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error)
-	Recreate(deploymentName, planID, boshContextID string, logger *log.Logger) (int, error)
+	Create(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error)
+	Update(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, secretsMap, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error)
+	Upgrade(ctx context.Context, deploymentName string, plan config.Plan, requestParams map[string]interface{}, boshContextID string, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error)
+	Recreate(ctx context.Context, deploymentName, planID, boshContextID string, logger *log.Logger) (int, error)
+	PreviewUpdate(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, secretsMap, uaaClient map[string]string, logger *log.Logger) (ManifestDiff, error)
+	PreviewUpgrade(ctx context.Context, deploymentName string, plan config.Plan, requestParams map[string]interface{}, uaaClient map[string]string, logger *log.Logger) (ManifestDiff, error)
+	Rollback(ctx context.Context, deploymentName string, manifest []byte, configs map[string]string, boshContextID string, logger *log.Logger) (int, error)
+	CheckDeployable(ctx context.Context, deploymentName, deployedPlanID string, secretsMap map[string]string, logger *log.Logger) error
+	RotateSecrets(ctx context.Context, deploymentName string, plan config.Plan, requestParams map[string]interface{}, boshContextID string, secretsMap, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error)
 }
 
 //counterfeiter:generate -o fakes/fake_service_adapter_client.go . ServiceAdapterClient
 type ServiceAdapterClient interface {
-	CreateBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error)
-	DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) error
-	GenerateDashboardUrl(instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error)
-	GeneratePlanSchema(plan serviceadapter.Plan, logger *log.Logger) (domain.ServiceSchemas, error)
+	CreateBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error)
+	DeleteBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) error
+	GenerateDashboardUrl(ctx context.Context, instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error)
+	GeneratePlanSchema(ctx context.Context, plan serviceadapter.Plan, logger *log.Logger) (domain.ServiceSchemas, error)
 }
 
 //counterfeiter:generate -o fakes/fake_bosh_client.go . BoshClient
 type BoshClient interface {
-	GetTask(taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
-	GetTasksInProgress(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
-	GetNormalisedTasksByContext(deploymentName, contextID string, logger *log.Logger) (boshdirector.BoshTasks, error)
-	VMs(deploymentName string, logger *log.Logger) (bosh.BoshVMs, error)
-	GetDeployment(name string, logger *log.Logger) ([]byte, bool, error)
-	GetDeployments(logger *log.Logger) ([]boshdirector.Deployment, error)
-	DeleteDeployment(name, contextID string, force bool, taskReporter *boshdirector.AsyncTaskReporter, logger *log.Logger) (int, error)
-	GetInfo(logger *log.Logger) (boshdirector.Info, error)
-	RunErrand(deploymentName, errandName string, errandInstances []string, contextID string, logger *log.Logger, taskReporter *boshdirector.AsyncTaskReporter) (int, error)
-	Variables(deploymentName string, logger *log.Logger) ([]boshdirector.Variable, error)
-	VerifyAuth(logger *log.Logger) error
-	GetDNSAddresses(deploymentName string, requestedDNS []config.BindingDNS) (map[string]string, error)
-	Deploy(manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error)
-	Recreate(deploymentName, contextID string, logger *log.Logger, taskReporter *boshdirector.AsyncTaskReporter) (int, error)
-	GetConfigs(configName string, logger *log.Logger) ([]boshdirector.BoshConfig, error)
-	DeleteConfig(configType, configName string, logger *log.Logger) (bool, error)
-	DeleteConfigs(configName string, logger *log.Logger) error
+	GetTask(ctx context.Context, taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
+	GetTasksInProgress(ctx context.Context, deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
+	GetNormalisedTasksByContext(ctx context.Context, deploymentName, contextID string, logger *log.Logger) (boshdirector.BoshTasks, error)
+	GetEvents(ctx context.Context, deploymentName, action string, logger *log.Logger) ([]boshdirector.BoshEvent, error)
+	VMs(ctx context.Context, deploymentName string, logger *log.Logger) (bosh.BoshVMs, error)
+	GetDeployment(ctx context.Context, name string, logger *log.Logger) ([]byte, bool, error)
+	GetDeployments(ctx context.Context, logger *log.Logger) ([]boshdirector.Deployment, error)
+	DeleteDeployment(ctx context.Context, name, contextID string, force bool, taskReporter *boshdirector.AsyncTaskReporter, logger *log.Logger) (int, error)
+	GetInfo(ctx context.Context, logger *log.Logger) (boshdirector.Info, error)
+	RunErrand(ctx context.Context, deploymentName, errandName string, errandInstances []string, contextID string, logger *log.Logger, taskReporter *boshdirector.AsyncTaskReporter) (int, error)
+	Variables(ctx context.Context, deploymentName string, logger *log.Logger) ([]boshdirector.Variable, error)
+	VerifyAuth(ctx context.Context, logger *log.Logger) error
+	GetDNSAddresses(ctx context.Context, deploymentName string, requestedDNS []config.BindingDNS) (map[string]string, error)
+	Deploy(ctx context.Context, manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error)
+	Recreate(ctx context.Context, deploymentName, contextID string, logger *log.Logger, taskReporter *boshdirector.AsyncTaskReporter) (int, error)
+	GetConfigs(ctx context.Context, configName string, logger *log.Logger) ([]boshdirector.BoshConfig, error)
+	GetConfigsOfType(ctx context.Context, configType string, logger *log.Logger) ([]boshdirector.BoshConfig, error)
+	UpdateConfig(ctx context.Context, configType, configName string, configContent []byte, logger *log.Logger) error
+	DeleteConfig(ctx context.Context, configType, configName string, logger *log.Logger) (bool, error)
+	DeleteConfigs(ctx context.Context, configName string, logger *log.Logger) error
 }
 
 //counterfeiter:generate -o fakes/fake_cloud_foundry_client.go . CloudFoundryClient
 type CloudFoundryClient interface {
 	GetAPIVersion(logger *log.Logger) (string, error)
 	CountInstancesOfPlan(serviceOfferingID, planID string, logger *log.Logger) (int, error)
-	CountInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
+	CountInstancesOfServiceOffering(ctx context.Context, serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
+	CountInstancesOfServiceOfferingByOrgAndSpace(ctx context.Context, serviceOfferingID string, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)
 	GetServiceInstances(filter cf.GetInstancesFilter, logger *log.Logger) ([]cf.Instance, error)
 }
 
+// InstanceCounter counts the service instances of an offering by plan, for
+// quotas and metrics.
+//
+//counterfeiter:generate -o fakes/fake_instance_counter.go . InstanceCounter
+type InstanceCounter interface {
+	CountInstancesOfServiceOffering(ctx context.Context, serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
+	CountInstancesOfServiceOfferingByOrgAndSpace(ctx context.Context, serviceOfferingID string, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)
+}
+
 //counterfeiter:generate -o fakes/fake_telemetry_logger.go . TelemetryLogger
 type TelemetryLogger interface {
 	LogInstances(instanceLister service.InstanceLister, item, operation string)
 }
 
+// EventNotifier is sent the lifecycle events of the instances and bindings of
+// the broker. Notify must not block the request that caused the event.
+//
+//counterfeiter:generate -o fakes/fake_event_notifier.go . EventNotifier
+type EventNotifier interface {
+	Notify(event LifecycleEvent)
+}
+
 //counterfeiter:generate -o fakes/fake_map_hasher.go . Hasher
 type Hasher interface {
 	Hash(m map[string]string) string
@@ -18,7 +18,7 @@ import (
 )
 
 func (b *Broker) getDeploymentInfo(instanceID string, ctx context.Context, action string, logger *log.Logger) ([]byte, bosh.BoshVMs, BrokerError) {
-	manifest, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	manifest, found, err := b.boshClient.GetDeployment(ctx, deploymentName(instanceID), logger)
 	if err != nil {
 		return nil, nil, NewGenericError(ctx, fmt.Errorf("gathering deployment list %s", err))
 	}
@@ -26,7 +26,7 @@ func (b *Broker) getDeploymentInfo(instanceID string, ctx context.Context, actio
 		return nil, nil, NewDisplayableError(apiresponses.ErrInstanceDoesNotExist, fmt.Errorf("error %sing: instance %s, not found", action, instanceID))
 	}
 
-	vms, err := b.boshClient.VMs(deploymentName(instanceID), logger)
+	vms, err := b.boshClient.VMs(ctx, deploymentName(instanceID), logger)
 	if err != nil {
 		return nil, nil, NewGenericError(ctx, fmt.Errorf("gathering %sing info %s", action, err))
 	}
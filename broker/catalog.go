@@ -22,15 +22,19 @@ func (b *Broker) Services(ctx context.Context) ([]domain.Service, error) {
 	b.catalogLock.Lock()
 	defer b.catalogLock.Unlock()
 
-	if b.cachedCatalog != nil {
+	// the catalog is generated again once the offering is reloaded
+	offering := b.offerings.Offering(b.serviceOfferingID)
+	if b.cachedCatalog != nil && b.catalogOffering == offering {
 		return b.cachedCatalog, nil
 	}
 
 	logger := b.loggerFactory.NewWithContext(ctx)
 
+	serviceOffering := offering.ServiceCatalog
+
 	var servicePlans []domain.ServicePlan
-	for _, plan := range b.serviceOffering.Plans {
-		servicePlan, err := b.generateServicePlan(plan, logger)
+	for _, plan := range serviceOffering.Plans {
+		servicePlan, err := b.generateServicePlan(ctx, serviceOffering, plan, logger)
 		if err != nil {
 			return []domain.Service{}, err
 		}
@@ -39,51 +43,54 @@ func (b *Broker) Services(ctx context.Context) ([]domain.Service, error) {
 
 	b.cachedCatalog = []domain.Service{
 		{
-			ID:            b.serviceOffering.ID,
-			Name:          b.serviceOffering.Name,
-			Description:   b.serviceOffering.Description,
-			Bindable:      b.serviceOffering.Bindable,
-			PlanUpdatable: b.serviceOffering.PlanUpdatable,
-			Plans:         servicePlans,
+			ID:                   serviceOffering.ID,
+			Name:                 serviceOffering.Name,
+			Description:          serviceOffering.Description,
+			Bindable:             serviceOffering.Bindable,
+			PlanUpdatable:        serviceOffering.PlanUpdatable,
+			InstancesRetrievable: true,
+			BindingsRetrievable:  b.EnableAsyncBinding,
+			Plans:                servicePlans,
 			Metadata: &domain.ServiceMetadata{
-				DisplayName:         b.serviceOffering.Metadata.DisplayName,
-				ImageUrl:            b.serviceOffering.Metadata.ImageURL,
-				LongDescription:     b.serviceOffering.Metadata.LongDescription,
-				ProviderDisplayName: b.serviceOffering.Metadata.ProviderDisplayName,
-				DocumentationUrl:    b.serviceOffering.Metadata.DocumentationURL,
-				SupportUrl:          b.serviceOffering.Metadata.SupportURL,
-				Shareable:           &b.serviceOffering.Metadata.Shareable,
-				AdditionalMetadata:  b.serviceOffering.Metadata.AdditionalMetadata,
+				DisplayName:         serviceOffering.Metadata.DisplayName,
+				ImageUrl:            serviceOffering.Metadata.ImageURL,
+				LongDescription:     serviceOffering.Metadata.LongDescription,
+				ProviderDisplayName: serviceOffering.Metadata.ProviderDisplayName,
+				DocumentationUrl:    serviceOffering.Metadata.DocumentationURL,
+				SupportUrl:          serviceOffering.Metadata.SupportURL,
+				Shareable:           &serviceOffering.Metadata.Shareable,
+				AdditionalMetadata:  serviceOffering.Metadata.AdditionalMetadata,
 			},
-			DashboardClient: b.generateDashboardClient(),
-			Requires:        requiredPermissions(b.serviceOffering.Requires),
-			Tags:            b.serviceOffering.Tags,
+			DashboardClient: generateDashboardClient(serviceOffering.DashboardClient),
+			Requires:        requiredPermissions(serviceOffering.Requires),
+			Tags:            serviceOffering.Tags,
 		},
 	}
+	b.catalogOffering = offering
 	return b.cachedCatalog, nil
 }
 
-func (b *Broker) generateDashboardClient() *domain.ServiceDashboardClient {
+func generateDashboardClient(client *config.DashboardClient) *domain.ServiceDashboardClient {
 	var dashboardClient *domain.ServiceDashboardClient
-	if b.serviceOffering.DashboardClient != nil {
+	if client != nil {
 		dashboardClient = &domain.ServiceDashboardClient{
-			ID:          b.serviceOffering.DashboardClient.ID,
-			Secret:      b.serviceOffering.DashboardClient.Secret,
-			RedirectURI: b.serviceOffering.DashboardClient.RedirectUri,
+			ID:          client.ID,
+			Secret:      client.Secret,
+			RedirectURI: client.RedirectUri,
 		}
 	}
 	return dashboardClient
 }
 
-func (b *Broker) generateServicePlan(plan config.Plan, logger *log.Logger) (domain.ServicePlan, error) {
-	maintenanceInfo := b.generateMaintenanceInfo(plan)
+func (b *Broker) generateServicePlan(ctx context.Context, serviceOffering config.ServiceOffering, plan config.Plan, logger *log.Logger) (domain.ServicePlan, error) {
+	maintenanceInfo := b.generateMaintenanceInfo(serviceOffering, plan)
 
 	var planCosts []domain.ServicePlanCost
 	for _, cost := range plan.Metadata.Costs {
 		planCosts = append(planCosts, domain.ServicePlanCost{Amount: cost.Amount, Unit: cost.Unit})
 	}
 
-	planSchema, err := b.generatePlanSchemas(plan, logger)
+	planSchema, err := b.generatePlanSchemas(ctx, serviceOffering, plan, logger)
 	if err != nil {
 		return domain.ServicePlan{}, err
 	}
@@ -105,26 +112,41 @@ func (b *Broker) generateServicePlan(plan config.Plan, logger *log.Logger) (doma
 	}, nil
 }
 
-func (b *Broker) generateMaintenanceInfo(plan config.Plan) *domain.MaintenanceInfo {
-	var maintenanceInfo *domain.MaintenanceInfo
-	mergedPublic, mergedPrivate := mergeMaintenanceInfo(b.serviceOffering.MaintenanceInfo, plan.MaintenanceInfo)
-	version := getMaintenanceInfoVersion(b.serviceOffering.MaintenanceInfo, plan.MaintenanceInfo)
-	description := getMaintenanceInfoDescription(b.serviceOffering.MaintenanceInfo, plan.MaintenanceInfo)
-
-	if mergedPublic != nil || mergedPrivate != nil || version != "" {
-		maintenanceInfo = &domain.MaintenanceInfo{
-			Public:      mergedPublic,
-			Private:     b.hasher.Hash(mergedPrivate),
-			Version:     version,
-			Description: description,
-		}
+func (b *Broker) generateMaintenanceInfo(serviceOffering config.ServiceOffering, plan config.Plan) *domain.MaintenanceInfo {
+	maintenanceInfo := PlanMaintenanceInfo(serviceOffering, plan)
+	if maintenanceInfo == nil {
+		return nil
+	}
+
+	return &domain.MaintenanceInfo{
+		Public:      maintenanceInfo.Public,
+		Private:     b.hasher.Hash(maintenanceInfo.Private),
+		Version:     maintenanceInfo.Version,
+		Description: maintenanceInfo.Description,
+	}
+}
+
+// PlanMaintenanceInfo is the maintenance_info of the service offering with
+// that of the plan merged over it, before private values are hashed for the
+// catalog. It is nil when neither sets any values or version.
+func PlanMaintenanceInfo(serviceOffering config.ServiceOffering, plan config.Plan) *config.MaintenanceInfo {
+	mergedPublic, mergedPrivate := mergeMaintenanceInfo(serviceOffering.MaintenanceInfo, plan.MaintenanceInfo)
+	version := getMaintenanceInfoVersion(serviceOffering.MaintenanceInfo, plan.MaintenanceInfo)
+	if mergedPublic == nil && mergedPrivate == nil && version == "" {
+		return nil
+	}
+
+	return &config.MaintenanceInfo{
+		Public:      mergedPublic,
+		Private:     mergedPrivate,
+		Version:     version,
+		Description: getMaintenanceInfoDescription(serviceOffering.MaintenanceInfo, plan.MaintenanceInfo),
 	}
-	return maintenanceInfo
 }
 
-func (b *Broker) generatePlanSchemas(plan config.Plan, logger *log.Logger) (*domain.ServiceSchemas, error) {
+func (b *Broker) generatePlanSchemas(ctx context.Context, serviceOffering config.ServiceOffering, plan config.Plan, logger *log.Logger) (*domain.ServiceSchemas, error) {
 	if b.EnablePlanSchemas {
-		planSchema, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		planSchema, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(serviceOffering.GlobalProperties), logger)
 		if err != nil {
 			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
 				return nil, err
@@ -133,7 +155,7 @@ func (b *Broker) generatePlanSchemas(plan config.Plan, logger *log.Logger) (*dom
 			return nil, fmt.Errorf("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas")
 		}
 
-		err = validatePlanSchemas(planSchema)
+		err = ValidatePlanSchemas(planSchema)
 		if err != nil {
 			logger.Println(fmt.Sprintf("Invalid JSON Schema for plan %s: %s\n", plan.Name, err.Error()))
 			return nil, errors.Wrap(err, "Invalid JSON Schema for plan "+plan.Name)
@@ -209,7 +231,9 @@ func requiredPermissions(permissions []string) []domain.RequiredPermission {
 	return brokerPermissions
 }
 
-func validatePlanSchemas(planSchema domain.ServiceSchemas) error {
+// ValidatePlanSchemas checks that the schemas generated by the service adapter
+// for a plan are valid JSON schemas.
+func ValidatePlanSchemas(planSchema domain.ServiceSchemas) error {
 	labels := []string{"instance create", "instance update", "binding create"}
 	for i, schema := range []map[string]interface{}{
 		planSchema.Instance.Create.Parameters,
@@ -134,11 +134,12 @@ var _ = Describe("Catalog", func() {
 
 		Expect(services).To(Equal([]domain.Service{
 			{
-				ID:            serviceCatalog.ID,
-				Name:          serviceCatalog.Name,
-				Description:   serviceCatalog.Description,
-				Bindable:      serviceCatalog.Bindable,
-				PlanUpdatable: serviceCatalog.PlanUpdatable,
+				ID:                   serviceCatalog.ID,
+				Name:                 serviceCatalog.Name,
+				Description:          serviceCatalog.Description,
+				Bindable:             serviceCatalog.Bindable,
+				PlanUpdatable:        serviceCatalog.PlanUpdatable,
+				InstancesRetrievable: true,
 				Metadata: &domain.ServiceMetadata{
 					DisplayName:         serviceCatalog.Metadata.DisplayName,
 					ImageUrl:            serviceCatalog.Metadata.DisplayName,
@@ -157,6 +158,18 @@ var _ = Describe("Catalog", func() {
 		Expect(serviceAdapter.GeneratePlanSchemaCallCount()).To(BeZero())
 	})
 
+	It("advertises bindings as retrievable when async binding is enabled", func() {
+		brokerConfig.EnableAsyncBinding = true
+		b, brokerCreationErr = createBroker([]broker.StartupChecker{}, noopservicescontroller.New())
+		Expect(brokerCreationErr).NotTo(HaveOccurred())
+
+		services, err := b.Services(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(services[0].BindingsRetrievable).To(BeTrue())
+		Expect(services[0].InstancesRetrievable).To(BeTrue())
+	})
+
 	It("includes the plan cost", func() {
 		serviceCatalog.Plans[0].Metadata.Costs = []config.PlanCost{
 			{Unit: "dogecoins", Amount: map[string]float64{"value": 1.65}},
@@ -372,6 +385,28 @@ var _ = Describe("Catalog", func() {
 		})
 	})
 
+	It("regenerates the catalog when the service offering is reloaded", func() {
+		serviceAdapter.GeneratePlanSchemaReturns(domain.ServiceSchemas{}, serviceadapter.NewNotImplementedError("not implemented"))
+		b, brokerCreationErr = createBroker([]broker.StartupChecker{}, noopservicescontroller.New())
+		Expect(brokerCreationErr).NotTo(HaveOccurred())
+		offerings := config.NewOfferingStore([]config.OfferingConfig{{ServiceCatalog: serviceCatalog}})
+		b.SetOfferingStore(offerings)
+
+		_, err := b.Services(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		reloadedCatalog := serviceCatalog
+		reloadedCatalog.Description = "a reloaded description"
+		reloadedCatalog.Plans = append(config.Plans{}, serviceCatalog.Plans[0])
+		offerings.SetOfferings([]config.OfferingConfig{{ServiceCatalog: reloadedCatalog}})
+
+		services, err := b.Services(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(services[0].Description).To(Equal("a reloaded description"))
+		Expect(services[0].Plans).To(HaveLen(1))
+		Expect(services[0].Plans[0].ID).To(Equal(serviceCatalog.Plans[0].ID))
+	})
+
 	DescribeTable("when the generated schema is invalid",
 		func(create, update, binding domain.Schema, errorLabel string) {
 			planSchema := domain.ServiceSchemas{
@@ -0,0 +1,279 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+// Contention reports how often callers had to wait for a lock, and for how
+// long.
+type Contention struct {
+	Acquisitions int
+	Contended    int
+	Waiting      int
+	WaitTime     time.Duration
+}
+
+func (c Contention) Add(other Contention) Contention {
+	return Contention{
+		Acquisitions: c.Acquisitions + other.Acquisitions,
+		Contended:    c.Contended + other.Contended,
+		Waiting:      c.Waiting + other.Waiting,
+		WaitTime:     c.WaitTime + other.WaitTime,
+	}
+}
+
+// ContentionStats groups the contention of the locks taken by the broker.
+type ContentionStats struct {
+	InstanceLocks Contention
+	AdapterCalls  Contention
+}
+
+func (s ContentionStats) Add(other ContentionStats) ContentionStats {
+	return ContentionStats{
+		InstanceLocks: s.InstanceLocks.Add(other.InstanceLocks),
+		AdapterCalls:  s.AdapterCalls.Add(other.AdapterCalls),
+	}
+}
+
+func (b *Broker) ContentionStats() ContentionStats {
+	return ContentionStats{
+		InstanceLocks: b.deploymentLocks.contention().Add(b.bindLocks.contention()),
+		AdapterCalls:  b.adapterLimiter.Contention(),
+	}
+}
+
+// SetAdapterLimiter sets the limiter bounding the concurrent service adapter
+// calls, so that the brokers of several offerings can share one bound. Each
+// broker has its own limiter, bounded by max_concurrent_adapter_calls, by
+// default.
+func (b *Broker) SetAdapterLimiter(limiter *AdapterLimiter) {
+	b.adapterLimiter = limiter
+	if adapterClient, ok := b.adapterClient.(limitedAdapterClient); ok {
+		adapterClient.limiter = limiter
+		b.adapterClient = adapterClient
+	}
+	if deployer, ok := b.deployer.(limitedDeployer); ok {
+		deployer.limiter = limiter
+		b.deployer = deployer
+	}
+}
+
+type contentionCounter struct {
+	lock  sync.Mutex
+	stats Contention
+}
+
+// wait records a caller about to block, and returns a function to call once it
+// has been let through.
+func (c *contentionCounter) wait(contended bool) func() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.stats.Acquisitions++
+	if !contended {
+		return func() {}
+	}
+
+	c.stats.Contended++
+	c.stats.Waiting++
+	start := time.Now()
+	return func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		c.stats.Waiting--
+		c.stats.WaitTime += time.Since(start)
+	}
+}
+
+func (c *contentionCounter) contention() Contention {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.stats
+}
+
+// instanceLocker serialises the operations on each service instance, while
+// letting the operations on different instances run in parallel.
+type instanceLocker struct {
+	contentionCounter
+
+	locksLock sync.Mutex
+	locks     map[string]*instanceLock
+}
+
+type instanceLock struct {
+	sync.Mutex
+	references int
+}
+
+func newInstanceLocker() *instanceLocker {
+	return &instanceLocker{locks: map[string]*instanceLock{}}
+}
+
+// lock blocks until no other operation holds the lock for the instance, and
+// returns the function releasing it.
+func (l *instanceLocker) lock(instanceID string) func() {
+	l.locksLock.Lock()
+	lock, found := l.locks[instanceID]
+	if !found {
+		lock = &instanceLock{}
+		l.locks[instanceID] = lock
+	}
+	lock.references++
+	contended := lock.references > 1
+	l.locksLock.Unlock()
+
+	done := l.wait(contended)
+	lock.Lock()
+	done()
+
+	return func() {
+		lock.Unlock()
+
+		l.locksLock.Lock()
+		defer l.locksLock.Unlock()
+
+		lock.references--
+		if lock.references == 0 {
+			delete(l.locks, instanceID)
+		}
+	}
+}
+
+// AdapterLimiter bounds the number of concurrent invocations of the service
+// adapter. A limiter without slots does not bound them.
+type AdapterLimiter struct {
+	contentionCounter
+
+	slots chan struct{}
+}
+
+func NewAdapterLimiter(maxConcurrentCalls int) *AdapterLimiter {
+	limiter := &AdapterLimiter{}
+	if maxConcurrentCalls > 0 {
+		limiter.slots = make(chan struct{}, maxConcurrentCalls)
+	}
+	return limiter
+}
+
+func (l *AdapterLimiter) Contention() Contention {
+	return l.contention()
+}
+
+// acquire blocks until a slot is free, and returns the function releasing it.
+// It gives up without a slot once ctx is done.
+func (l *AdapterLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots == nil {
+		l.wait(false)
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.wait(false)
+	default:
+		done := l.wait(true)
+		defer done()
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		<-l.slots
+	}, nil
+}
+
+// limitedDeployer takes an adapter slot around the deployer calls, each of
+// which runs the service adapter to generate the manifest.
+type limitedDeployer struct {
+	Deployer
+	limiter *AdapterLimiter
+}
+
+func (d limitedDeployer) Create(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error) {
+	release, err := d.limiter.acquire(ctx)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer release()
+	return d.Deployer.Create(ctx, deploymentName, planID, requestParams, boshContextID, uaaClient, logger)
+}
+
+func (d limitedDeployer) Update(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, secretsMap, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error) {
+	release, err := d.limiter.acquire(ctx)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer release()
+	return d.Deployer.Update(ctx, deploymentName, planID, requestParams, previousPlanID, boshContextID, secretsMap, uaaClient, logger)
+}
+
+func (d limitedDeployer) Upgrade(ctx context.Context, deploymentName string, plan config.Plan, requestParams map[string]interface{}, boshContextID string, uaaClient map[string]string, logger *log.Logger) (int, []byte, map[string]any, error) {
+	release, err := d.limiter.acquire(ctx)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer release()
+	return d.Deployer.Upgrade(ctx, deploymentName, plan, requestParams, boshContextID, uaaClient, logger)
+}
+
+// limitedAdapterClient takes an adapter slot around every service adapter call.
+type limitedAdapterClient struct {
+	ServiceAdapterClient
+	limiter *AdapterLimiter
+}
+
+func (c limitedAdapterClient) CreateBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) (serviceadapter.Binding, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return serviceadapter.Binding{}, err
+	}
+	defer release()
+	return c.ServiceAdapterClient.CreateBinding(ctx, bindingID, deploymentTopology, manifest, requestParams, secretsMap, dnsAddresses, logger)
+}
+
+func (c limitedAdapterClient) DeleteBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, secretsMap, dnsAddresses map[string]string, logger *log.Logger) error {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.ServiceAdapterClient.DeleteBinding(ctx, bindingID, deploymentTopology, manifest, requestParams, secretsMap, dnsAddresses, logger)
+}
+
+func (c limitedAdapterClient) GenerateDashboardUrl(ctx context.Context, instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return c.ServiceAdapterClient.GenerateDashboardUrl(ctx, instanceID, plan, manifest, logger)
+}
+
+func (c limitedAdapterClient) GeneratePlanSchema(ctx context.Context, plan serviceadapter.Plan, logger *log.Logger) (domain.ServiceSchemas, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return domain.ServiceSchemas{}, err
+	}
+	defer release()
+	return c.ServiceAdapterClient.GeneratePlanSchema(ctx, plan, logger)
+}
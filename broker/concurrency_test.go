@@ -0,0 +1,151 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"log"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("Concurrency", func() {
+	var (
+		provisionDetails domain.ProvisionDetails
+		releaseDeploy    chan struct{}
+	)
+
+	provisionInBackground := func(instanceID string) chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := b.Provision(context.Background(), instanceID, provisionDetails, true)
+			done <- err
+		}()
+		return done
+	}
+
+	BeforeEach(func() {
+		provisionDetails = domain.ProvisionDetails{ServiceID: serviceOfferingID, PlanID: existingPlanID}
+		releaseDeploy = make(chan struct{})
+		fakeDeployer.CreateStub = func(_ context.Context, deploymentName, _ string, _ map[string]interface{}, _ string, _ map[string]string, _ *log.Logger) (int, []byte, map[string]any, error) {
+			if deploymentName == "service-instance_blocked-instance" {
+				<-releaseDeploy
+			}
+			return 42, []byte("manifest"), nil, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		b = createDefaultBroker()
+	})
+
+	It("runs operations on different instances in parallel", func() {
+		blocked := provisionInBackground("blocked-instance")
+		Eventually(fakeDeployer.CreateCallCount).Should(Equal(1))
+
+		Eventually(provisionInBackground("another-instance")).Should(Receive(BeNil()))
+
+		close(releaseDeploy)
+		Eventually(blocked).Should(Receive(BeNil()))
+		Expect(b.ContentionStats().InstanceLocks.Contended).To(Equal(0))
+	})
+
+	It("serialises operations on the same instance and reports the contention", func() {
+		first := provisionInBackground("blocked-instance")
+		Eventually(fakeDeployer.CreateCallCount).Should(Equal(1))
+
+		second := provisionInBackground("blocked-instance")
+		Eventually(func() int { return b.ContentionStats().InstanceLocks.Waiting }).Should(Equal(1))
+		Consistently(second).ShouldNot(Receive())
+		Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
+
+		close(releaseDeploy)
+		Eventually(first).Should(Receive(BeNil()))
+		Eventually(second).Should(Receive())
+
+		stats := b.ContentionStats().InstanceLocks
+		Expect(stats.Acquisitions).To(Equal(2))
+		Expect(stats.Contended).To(Equal(1))
+		Expect(stats.Waiting).To(Equal(0))
+	})
+
+	When("the adapter concurrency is bounded", func() {
+		BeforeEach(func() {
+			brokerConfig.MaxConcurrentAdapterCalls = 1
+		})
+
+		It("waits for a free adapter slot", func() {
+			blocked := provisionInBackground("blocked-instance")
+			Eventually(fakeDeployer.CreateCallCount).Should(Equal(1))
+
+			waiting := provisionInBackground("another-instance")
+			Eventually(func() int { return b.ContentionStats().AdapterCalls.Waiting }).Should(Equal(1))
+			Consistently(waiting).ShouldNot(Receive())
+
+			close(releaseDeploy)
+			Eventually(blocked).Should(Receive(BeNil()))
+			Eventually(waiting).Should(Receive(BeNil()))
+
+			stats := b.ContentionStats().AdapterCalls
+			Expect(stats.Contended).To(BeNumerically(">=", 1))
+			Expect(stats.Waiting).To(Equal(0))
+		})
+
+		It("stops waiting for an adapter slot once the request is cancelled", func() {
+			blocked := provisionInBackground("blocked-instance")
+			Eventually(fakeDeployer.CreateCallCount).Should(Equal(1))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			waiting := make(chan error, 1)
+			go func() {
+				_, err := b.Provision(ctx, "another-instance", provisionDetails, true)
+				waiting <- err
+			}()
+			Eventually(func() int { return b.ContentionStats().AdapterCalls.Waiting }).Should(Equal(1))
+
+			cancel()
+			Eventually(waiting).Should(Receive(HaveOccurred()))
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
+			Expect(b.ContentionStats().AdapterCalls.Waiting).To(Equal(0))
+
+			By("leaving the slot to the next caller")
+			close(releaseDeploy)
+			Eventually(blocked).Should(Receive(BeNil()))
+			Eventually(provisionInBackground("third-instance")).Should(Receive(BeNil()))
+		})
+	})
+
+	When("brokers share an adapter limiter", func() {
+		It("bounds the adapter calls of all of them", func() {
+			limiter := broker.NewAdapterLimiter(1)
+			b.SetAdapterLimiter(limiter)
+			otherBroker, err := createBroker([]broker.StartupChecker{})
+			Expect(err).NotTo(HaveOccurred())
+			otherBroker.SetAdapterLimiter(limiter)
+
+			blocked := provisionInBackground("blocked-instance")
+			Eventually(fakeDeployer.CreateCallCount).Should(Equal(1))
+
+			waiting := make(chan error, 1)
+			go func() {
+				_, err := otherBroker.Provision(context.Background(), "another-instance", provisionDetails, true)
+				waiting <- err
+			}()
+			Eventually(func() int { return limiter.Contention().Waiting }).Should(Equal(1))
+			Consistently(waiting).ShouldNot(Receive())
+
+			close(releaseDeploy)
+			Eventually(blocked).Should(Receive(BeNil()))
+			Eventually(waiting).Should(Receive(BeNil()))
+			Expect(b.ContentionStats().AdapterCalls).To(Equal(otherBroker.ContentionStats().AdapterCalls))
+		})
+	})
+})
@@ -7,11 +7,18 @@
 package broker
 
 import (
+	"context"
 	"log"
 
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 )
 
-func (b *Broker) CountInstancesOfPlans(logger *log.Logger) (map[cf.ServicePlan]int, error) {
-	return b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+func (b *Broker) CountInstancesOfPlans(ctx context.Context, logger *log.Logger) (map[cf.ServicePlan]int, error) {
+	return b.instanceCounter.CountInstancesOfServiceOffering(ctx, b.offering().ID, logger)
+}
+
+// CountInstancesOfPlansByOrgAndSpace returns the instance counts of each org
+// and each space that has instances of the service offering.
+func (b *Broker) CountInstancesOfPlansByOrgAndSpace(ctx context.Context, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	return b.instanceCounter.CountInstancesOfServiceOfferingByOrgAndSpace(ctx, b.offering().ID, logger)
 }
@@ -7,12 +7,16 @@
 package broker_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 )
 
 var _ = Describe("counting instances of a service offering by plan", func() {
@@ -24,10 +28,10 @@ var _ = Describe("counting instances of a service offering by plan", func() {
 		cfClient.CountInstancesOfServiceOfferingReturns(expectedCounts, nil)
 		b = createDefaultBroker()
 		logger := loggerFactory.NewWithRequestID()
-		counts, err := b.CountInstancesOfPlans(logger)
+		counts, err := b.CountInstancesOfPlans(context.Background(), logger)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(counts).To(Equal(expectedCounts))
-		instanceID, _ := cfClient.CountInstancesOfServiceOfferingArgsForCall(0)
+		_, instanceID, _ := cfClient.CountInstancesOfServiceOfferingArgsForCall(0)
 		Expect(instanceID).To(Equal(serviceOfferingID))
 	})
 
@@ -35,7 +39,25 @@ var _ = Describe("counting instances of a service offering by plan", func() {
 		cfClient.CountInstancesOfServiceOfferingReturns(nil, errors.New("Something bad happened"))
 		b = createDefaultBroker()
 		logger := loggerFactory.NewWithRequestID()
-		_, err := b.CountInstancesOfPlans(logger)
+		_, err := b.CountInstancesOfPlans(context.Background(), logger)
 		Expect(err).To(MatchError("Something bad happened"))
 	})
 })
+
+var _ = Describe("counting instances with the BOSH instance counter", func() {
+	It("counts from the BOSH deployments rather than CF", func() {
+		brokerConfig.InstanceCounter = config.InstanceCounterBOSH
+		boshClient.GetDeploymentsReturns([]boshdirector.Deployment{{Name: "service-instance_one"}}, nil)
+		boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{{
+			Type:    broker.InstanceMetadataConfigType,
+			Name:    "service-instance_one",
+			Content: `{"plan_id":"` + existingPlanID + `"}`,
+		}}, nil)
+		b = createDefaultBroker()
+
+		counts, err := b.CountInstancesOfPlans(context.Background(), loggerFactory.NewWithRequestID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(HaveKeyWithValue(cf.ServicePlan{ServicePlanEntity: cf.ServicePlanEntity{UniqueID: existingPlanID, Name: existingPlanName}}, 1))
+		Expect(cfClient.CountInstancesOfServiceOfferingCallCount()).To(BeZero())
+	})
+})
@@ -9,6 +9,8 @@ import (
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 )
 
 var errInstanceMustBeUpgradedFirst = apiresponses.NewFailureResponseBuilder(
@@ -80,7 +82,7 @@ func validateMaintenanceInfo(catalog []domain.Service, planID string, maintenanc
 
 	if maintenanceInfoConflict(maintenanceInfo, planMaintenanceInfo) {
 		if maintenanceInfo == nil {
-			logger.Printf("warning: %s\n", warningMaintenanceInfoNilInTheRequest)
+			loggerfactory.WithLevel(logger, loggerfactory.WarnLevel).Printf("warning: %s\n", warningMaintenanceInfoNilInTheRequest)
 			return warningMaintenanceInfoNilInTheRequest
 		}
 
@@ -20,6 +20,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 )
 
 func (b *Broker) Deprovision(
@@ -27,18 +28,27 @@ func (b *Broker) Deprovision(
 	instanceID string,
 	deprovisionDetails domain.DeprovisionDetails,
 	asyncAllowed bool,
-) (domain.DeprovisionServiceSpec, error) {
-	b.deploymentLock.Lock()
-	defer b.deploymentLock.Unlock()
+) (_ domain.DeprovisionServiceSpec, err error) {
+	defer b.deploymentLocks.lock(instanceID)()
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeDelete), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeDelete), requestID, b.offering().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
+	// an instance that is not found is gone, as the platform treats it, so
+	// there is no failure to notify
+	operationType := b.getOperationType(deprovisionDetails.Force)
+	instanceGone := false
+	defer func() {
+		if err != nil && !instanceGone {
+			b.notifyRequestFailed(ctx, operationType, instanceID, deprovisionDetails.PlanID, err)
+		}
+	}()
+
 	if !asyncAllowed {
 		return domain.DeprovisionServiceSpec{}, b.processError(apiresponses.ErrAsyncRequired, logger)
 	}
 
-	_, err := b.boshClient.GetInfo(logger)
+	_, err = b.boshClient.GetInfo(ctx, logger)
 	if err != nil {
 		return domain.DeprovisionServiceSpec{IsAsync: true}, b.processError(NewBoshRequestError("delete", err), logger)
 	}
@@ -49,6 +59,7 @@ func (b *Broker) Deprovision(
 	}
 
 	if !deploymentExists {
+		instanceGone = true
 		var err error
 		err = NewDisplayableError(
 			apiresponses.ErrInstanceDoesNotExist,
@@ -70,31 +81,51 @@ func (b *Broker) Deprovision(
 		return domain.DeprovisionServiceSpec{IsAsync: true}, b.processError(err, logger)
 	}
 
+	metadata, _, err := b.getInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error reading instance metadata for %s: %s\n", instanceID, err)
+	}
+
+	plan, found := b.offering().FindPlanByID(deprovisionDetails.PlanID)
+	if operationData, ok := b.deleteInProgress(ctx, instanceID, metadata, plan, logger); ok {
+		operationDataJSON, err := json.Marshal(operationData)
+		if err != nil {
+			return domain.DeprovisionServiceSpec{IsAsync: true}, b.processError(NewGenericError(ctx, err), logger)
+		}
+		return domain.DeprovisionServiceSpec{IsAsync: true, OperationData: string(operationDataJSON)}, nil
+	}
+
 	if err := b.assertNoOperationsInProgress(ctx, instanceID, logger); err != nil {
 		return domain.DeprovisionServiceSpec{IsAsync: true}, b.processError(err, logger)
 	}
 
-	operationType := b.getOperationType(deprovisionDetails.Force)
-	plan, found := b.serviceOffering.FindPlanByID(deprovisionDetails.PlanID)
-	if found {
+	serviceSpec, err := b.startDelete(ctx, instanceID, metadata, plan, found, operationType, logger)
+	return serviceSpec, b.processError(err, logger)
+}
+
+// startDelete runs the pre-delete errands of the plan when it has any, or
+// deletes the deployment of the instance otherwise. After the errands, the
+// deployment is deleted when the operation is polled. The operation is recorded
+// in the instance metadata the caller read.
+func (b *Broker) startDelete(ctx context.Context, instanceID string, metadata InstanceMetadata, plan config.Plan, planFound bool, operationType OperationType, logger *log.Logger) (domain.DeprovisionServiceSpec, error) {
+	if planFound {
 		if errands := plan.PreDeleteErrands(); len(errands) != 0 {
-			serviceSpec, err := b.runPreDeleteErrands(ctx, instanceID, errands, operationType, logger)
-			return serviceSpec, b.processError(err, logger)
+			return b.runPreDeleteErrands(ctx, instanceID, metadata, plan, errands, operationType, logger)
 		}
 	}
 
-	serviceSpec, err := b.deleteInstance(ctx, instanceID, plan, operationType, logger)
+	serviceSpec, err := b.deleteInstance(ctx, instanceID, metadata, plan, operationType, logger)
 
 	clientErr := b.uaaClient.DeleteClient(instanceID)
 	if clientErr != nil {
-		logger.Printf("failed to delete UAA client associated with service instance %s\n", instanceID)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to delete UAA client associated with service instance %s\n", instanceID)
 	}
 
-	return serviceSpec, b.processError(err, logger)
+	return serviceSpec, err
 }
 
 func (b *Broker) deleteConfigsForNotFoundInstance(ctx context.Context, instanceID string, logger *log.Logger) error {
-	if err := b.boshClient.DeleteConfigs(deploymentName(instanceID), logger); err != nil {
+	if err := b.deleteInstanceConfigs(ctx, instanceID, logger); err != nil {
 		operatorError := NewGenericError(
 			ctx,
 			fmt.Errorf("error deprovisioning: failed to delete configs for instance %s: %s", deploymentName(instanceID), err),
@@ -106,7 +137,7 @@ func (b *Broker) deleteConfigsForNotFoundInstance(ctx context.Context, instanceI
 }
 
 func (b *Broker) clearSecretsForNotFoundInstance(ctx context.Context, instanceID string, logger *log.Logger) error {
-	if err := b.secretManager.DeleteSecretsForInstance(instanceID, logger); err != nil {
+	if err := b.secretManager.DeleteSecretsForInstance(ctx, instanceID, logger); err != nil {
 		userError := errors.New("Unable to delete service. Please try again later or contact your operator.")
 		operatorError := NewGenericError(
 			ctx,
@@ -118,7 +149,7 @@ func (b *Broker) clearSecretsForNotFoundInstance(ctx context.Context, instanceID
 }
 
 func (b *Broker) assertDeploymentExists(ctx context.Context, instanceID string, logger *log.Logger) (bool, error) {
-	_, deploymentFound, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	_, deploymentFound, err := b.boshClient.GetDeployment(ctx, deploymentName(instanceID), logger)
 
 	switch err.(type) {
 	case boshdirector.RequestError:
@@ -134,7 +165,7 @@ func (b *Broker) assertDeploymentExists(ctx context.Context, instanceID string,
 }
 
 func (b *Broker) assertNoOperationsInProgress(ctx context.Context, instanceID string, logger *log.Logger) error {
-	incompleteTasks, err := b.boshClient.GetTasksInProgress(deploymentName(instanceID), logger)
+	incompleteTasks, err := b.boshClient.GetTasksInProgress(ctx, deploymentName(instanceID), logger)
 	switch err.(type) {
 	case boshdirector.RequestError:
 		return NewBoshRequestError("delete", err)
@@ -158,12 +189,13 @@ func (b *Broker) assertNoOperationsInProgress(ctx context.Context, instanceID st
 	return nil
 }
 
-func (b *Broker) runPreDeleteErrands(ctx context.Context, instanceID string, preDeleteErrands []config.Errand, operationType OperationType, logger *log.Logger) (domain.DeprovisionServiceSpec, error) {
+func (b *Broker) runPreDeleteErrands(ctx context.Context, instanceID string, metadata InstanceMetadata, plan config.Plan, preDeleteErrands []config.Errand, operationType OperationType, logger *log.Logger) (domain.DeprovisionServiceSpec, error) {
 	logger.Printf("running pre-delete errand for instance %s\n", instanceID)
 
 	boshContextID := uuid.New()
 
 	taskID, err := b.boshClient.RunErrand(
+		ctx,
 		deploymentName(instanceID),
 		preDeleteErrands[0].Name,
 		preDeleteErrands[0].Instances,
@@ -175,6 +207,10 @@ func (b *Broker) runPreDeleteErrands(ctx context.Context, instanceID string, pre
 		return domain.DeprovisionServiceSpec{IsAsync: true}, NewGenericError(ctx, err)
 	}
 
+	record := newOperationRecord(ctx, operationType, plan.ID, "", taskID, boshContextID)
+	b.recordOperationIn(ctx, instanceID, metadata, plan.ID, record, logger)
+	b.notifyOperationStarted(instanceID, record)
+
 	operationData, err := json.Marshal(OperationData{
 		OperationType: operationType,
 		BoshTaskID:    taskID,
@@ -188,10 +224,11 @@ func (b *Broker) runPreDeleteErrands(ctx context.Context, instanceID string, pre
 	return domain.DeprovisionServiceSpec{IsAsync: true, OperationData: string(operationData)}, nil
 }
 
-func (b *Broker) deleteInstance(ctx context.Context, instanceID string, planConfig config.Plan, operationType OperationType, logger *log.Logger) (domain.DeprovisionServiceSpec, error) {
+func (b *Broker) deleteInstance(ctx context.Context, instanceID string, metadata InstanceMetadata, planConfig config.Plan, operationType OperationType, logger *log.Logger) (domain.DeprovisionServiceSpec, error) {
 	logger.Printf("removing deployment for instance %s as part of operation %q\n", instanceID, operationType)
 
 	taskID, err := b.boshClient.DeleteDeployment(
+		ctx,
 		deploymentName(instanceID),
 		fmt.Sprintf("delete-%s", instanceID),
 		operationType == OperationTypeForceDelete,
@@ -211,6 +248,10 @@ func (b *Broker) deleteInstance(ctx context.Context, instanceID string, planConf
 	logger.Printf("Bosh task id is %d for operation %q of instance %s\n", taskID, operationType, instanceID)
 	ctx = brokercontext.WithBoshTaskID(ctx, taskID)
 
+	record := newOperationRecord(ctx, operationType, planConfig.ID, "", taskID, "")
+	b.recordOperationIn(ctx, instanceID, metadata, planConfig.ID, record, logger)
+	b.notifyOperationStarted(instanceID, record)
+
 	operationData, err := b.generateOperationData(operationType, err, taskID)
 	if err != nil {
 		return domain.DeprovisionServiceSpec{IsAsync: true}, NewGenericError(ctx, err)
@@ -103,9 +103,20 @@ var _ = Describe("deprovisioning instances", func() {
 				OperationType: broker.OperationTypeDelete,
 			}))
 
+			By("recording the delete operation")
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+			_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(0)
+			Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+			Expect(configName).To(Equal(deploymentName(instanceID)))
+			var metadata broker.InstanceMetadata
+			Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+			Expect(metadata.Operations).To(HaveLen(1))
+			Expect(metadata.Operations[0].Type).To(Equal(broker.OperationTypeDelete))
+			Expect(metadata.Operations[0].BoshTaskID).To(Equal(deleteTaskID))
+
 			By("validating DeleteDeployment args")
 			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1))
-			actualInstanceID, _, force, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+			_, actualInstanceID, _, force, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
 			Expect(actualInstanceID).To(Equal(deploymentName(instanceID)))
 			Expect(force).To(BeFalse())
 
@@ -146,7 +157,7 @@ var _ = Describe("deprovisioning instances", func() {
 
 			By("validating DeleteDeployment args")
 			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1))
-			actualInstanceID, _, force, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+			_, actualInstanceID, _, force, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
 			Expect(actualInstanceID).To(Equal(deploymentName(instanceID)))
 			Expect(force).To(Equal(forceDeprovision))
 			Expect(logBuffer.String()).To(SatisfyAll(
@@ -253,6 +264,34 @@ var _ = Describe("deprovisioning instances", func() {
 						fmt.Sprintf("error deprovisioning: instance %s, not found.", instanceID),
 					))
 				})
+
+				It("retains the instance metadata marked as deleted and removes expired metadata", func() {
+					boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+						Type:    broker.InstanceMetadataConfigType,
+						Name:    deploymentName(instanceID),
+						Content: `{"plan_id":"some-plan-id"}`,
+					}}, nil)
+					boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+						{Type: broker.InstanceMetadataConfigType, Name: "service-instance_expired", Content: `{"plan_id":"some-plan-id","deleted_at":"2020-01-01T00:00:00Z"}`},
+						{Type: broker.InstanceMetadataConfigType, Name: "service-instance_live", Content: `{"plan_id":"some-plan-id"}`},
+					}, nil)
+
+					b.Deprovision(context.Background(), instanceID, deprovisionDetails, asyncAllowed)
+
+					Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+					_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(0)
+					Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+					Expect(configName).To(Equal(deploymentName(instanceID)))
+					var metadata broker.InstanceMetadata
+					Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+					Expect(metadata.PlanID).To(Equal("some-plan-id"))
+					Expect(metadata.DeletedAt).NotTo(BeNil())
+
+					Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+					_, configType, configName, _ = boshClient.DeleteConfigArgsForCall(0)
+					Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+					Expect(configName).To(Equal("service-instance_expired"))
+				})
 			})
 
 			Context("deleting bosh configs fails", func() {
@@ -342,7 +381,7 @@ var _ = Describe("deprovisioning instances", func() {
 
 			By("executing errand")
 			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
-			argDeploymentName, argErrandName, _, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
+			_, argDeploymentName, argErrandName, _, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
 			Expect(argDeploymentName).To(Equal(broker.InstancePrefix + instanceID))
 			Expect(argErrandName).To(Equal("cleanup-resources"))
 			Expect(contextID).To(MatchRegexp(
@@ -352,7 +391,7 @@ var _ = Describe("deprovisioning instances", func() {
 			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
 
 			var operationData broker.OperationData
-			_, _, _, contextID, _, _ = boshClient.RunErrandArgsForCall(0)
+			_, _, _, _, contextID, _, _ = boshClient.RunErrandArgsForCall(0)
 
 			Expect(json.Unmarshal([]byte(deprovisionSpec.OperationData), &operationData)).To(Succeed())
 			Expect(operationData).To(Equal(broker.OperationData{
@@ -405,7 +444,7 @@ var _ = Describe("deprovisioning instances", func() {
 				)
 
 				var data broker.OperationData
-				_, errandName, _, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
+				_, _, errandName, _, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
 				Expect(json.Unmarshal([]byte(deprovisionSpec.OperationData), &data)).To(Succeed())
 				Expect(data.BoshContextID).To(Equal(contextID))
 				Expect(data.OperationType).To(Equal(broker.OperationTypeDelete))
@@ -449,7 +488,7 @@ var _ = Describe("deprovisioning instances", func() {
 				Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
 
 				var operationData broker.OperationData
-				_, _, _, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
+				_, _, _, _, contextID, _, _ := boshClient.RunErrandArgsForCall(0)
 
 				Expect(json.Unmarshal([]byte(deprovisionSpec.OperationData), &operationData)).To(Succeed())
 				Expect(operationData).To(Equal(broker.OperationData{
@@ -528,6 +567,36 @@ var _ = Describe("deprovisioning instances", func() {
 				Expect(deprovisionErr).To(MatchError("An operation is in progress for your service instance. Please try again later."))
 				Expect(logBuffer.String()).To(ContainSubstring(fmt.Sprintf("error deprovisioning: deployment %s is still in progress: tasks %s\n", deploymentName(instanceID), incompleteTasks.ToLog())))
 			})
+
+			When("the task is the delete of an earlier deprovision", func() {
+				BeforeEach(func() {
+					boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+						Type:    broker.InstanceMetadataConfigType,
+						Name:    deploymentName(instanceID),
+						Content: `{"plan_id":"some-plan","operations":[{"type":"create","bosh_task_id":1},{"type":"delete","bosh_task_id":1337}]}`,
+					}}, nil)
+					boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 1337, State: boshdirector.TaskProcessing}, nil)
+				})
+
+				It("returns the operation of the delete in progress", func() {
+					deprovisionSpec, deprovisionErr = b.Deprovision(
+						context.Background(),
+						instanceID,
+						deprovisionDetails,
+						asyncAllowed,
+					)
+
+					Expect(deprovisionErr).NotTo(HaveOccurred())
+					Expect(deprovisionSpec.IsAsync).To(BeTrue())
+					var operationData broker.OperationData
+					Expect(json.Unmarshal([]byte(deprovisionSpec.OperationData), &operationData)).To(Succeed())
+					Expect(operationData).To(Equal(broker.OperationData{
+						BoshTaskID:    1337,
+						OperationType: broker.OperationTypeDelete,
+					}))
+					Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+				})
+			})
 		})
 
 		Context("request error", func() {
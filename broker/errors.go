@@ -103,18 +103,45 @@ func NewGenericError(ctx context.Context, err error) DisplayableError {
 	}
 }
 
+// NewAdapterTimeoutError tells the operator which service adapter subcommand
+// timed out, while the CF user is told to try again later.
+func NewAdapterTimeoutError(ctx context.Context, err serviceadapter.TimeoutError) DisplayableError {
+	message := fmt.Sprintf(
+		"Timed out waiting for the service adapter, please try again later. service: %s, service-instance-guid: %s, broker-request-id: %s",
+		brokercontext.GetServiceName(ctx),
+		brokercontext.GetInstanceID(ctx),
+		brokercontext.GetReqID(ctx),
+	)
+
+	return DisplayableError{
+		errorForCFUser:   errors.New(message),
+		errorForOperator: err,
+	}
+}
+
+// adapterError returns the DisplayableError for err when the service adapter
+// timed out, and otherwise for any other failure.
+func adapterError(ctx context.Context, err, otherwise error) error {
+	if timeoutErr, ok := err.(serviceadapter.TimeoutError); ok {
+		return NewAdapterTimeoutError(ctx, timeoutErr)
+	}
+	return otherwise
+}
+
 func adapterToAPIError(ctx context.Context, err error) error {
 	if err == nil {
 		return nil
 	}
 
-	switch err.(type) {
+	switch e := err.(type) {
 	case serviceadapter.BindingAlreadyExistsError:
 		return apiresponses.ErrBindingAlreadyExists
 	case serviceadapter.BindingNotFoundError:
 		return apiresponses.ErrBindingDoesNotExist
 	case serviceadapter.AppGuidNotProvidedError:
 		return apiresponses.ErrAppGuidNotProvided
+	case serviceadapter.TimeoutError:
+		return NewAdapterTimeoutError(ctx, e)
 	case serviceadapter.UnknownFailureError:
 		if err.Error() == "" {
 			// Adapter returns an unknown error with no message
@@ -174,3 +201,19 @@ type OperationAlreadyCompletedError struct {
 func NewOperationAlreadyCompletedError(e error) error {
 	return OperationAlreadyCompletedError{error: e}
 }
+
+type NoRollbackSnapshotError struct {
+	error
+}
+
+func NewNoRollbackSnapshotError(e error) error {
+	return NoRollbackSnapshotError{error: e}
+}
+
+type SecretsNotFoundError struct {
+	error
+}
+
+func NewSecretsNotFoundError(e error) error {
+	return SecretsNotFoundError{error: e}
+}
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -12,12 +13,13 @@ import (
 )
 
 type FakeBoshClient struct {
-	DeleteConfigStub        func(string, string, *log.Logger) (bool, error)
+	DeleteConfigStub        func(context.Context, string, string, *log.Logger) (bool, error)
 	deleteConfigMutex       sync.RWMutex
 	deleteConfigArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
+		arg3 string
+		arg4 *log.Logger
 	}
 	deleteConfigReturns struct {
 		result1 bool
@@ -27,11 +29,12 @@ type FakeBoshClient struct {
 		result1 bool
 		result2 error
 	}
-	DeleteConfigsStub        func(string, *log.Logger) error
+	DeleteConfigsStub        func(context.Context, string, *log.Logger) error
 	deleteConfigsMutex       sync.RWMutex
 	deleteConfigsArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	deleteConfigsReturns struct {
 		result1 error
@@ -39,14 +42,15 @@ type FakeBoshClient struct {
 	deleteConfigsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteDeploymentStub        func(string, string, bool, *boshdirector.AsyncTaskReporter, *log.Logger) (int, error)
+	DeleteDeploymentStub        func(context.Context, string, string, bool, *boshdirector.AsyncTaskReporter, *log.Logger) (int, error)
 	deleteDeploymentMutex       sync.RWMutex
 	deleteDeploymentArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 bool
-		arg4 *boshdirector.AsyncTaskReporter
-		arg5 *log.Logger
+		arg3 string
+		arg4 bool
+		arg5 *boshdirector.AsyncTaskReporter
+		arg6 *log.Logger
 	}
 	deleteDeploymentReturns struct {
 		result1 int
@@ -56,13 +60,14 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
-	DeployStub        func([]byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
+	DeployStub        func(context.Context, []byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
 	deployMutex       sync.RWMutex
 	deployArgsForCall []struct {
-		arg1 []byte
-		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
+		arg1 context.Context
+		arg2 []byte
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
 	}
 	deployReturns struct {
 		result1 int
@@ -72,11 +77,12 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
-	GetConfigsStub        func(string, *log.Logger) ([]boshdirector.BoshConfig, error)
+	GetConfigsStub        func(context.Context, string, *log.Logger) ([]boshdirector.BoshConfig, error)
 	getConfigsMutex       sync.RWMutex
 	getConfigsArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	getConfigsReturns struct {
 		result1 []boshdirector.BoshConfig
@@ -86,11 +92,27 @@ type FakeBoshClient struct {
 		result1 []boshdirector.BoshConfig
 		result2 error
 	}
-	GetDNSAddressesStub        func(string, []config.BindingDNS) (map[string]string, error)
+	GetConfigsOfTypeStub        func(context.Context, string, *log.Logger) ([]boshdirector.BoshConfig, error)
+	getConfigsOfTypeMutex       sync.RWMutex
+	getConfigsOfTypeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}
+	getConfigsOfTypeReturns struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}
+	getConfigsOfTypeReturnsOnCall map[int]struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}
+	GetDNSAddressesStub        func(context.Context, string, []config.BindingDNS) (map[string]string, error)
 	getDNSAddressesMutex       sync.RWMutex
 	getDNSAddressesArgsForCall []struct {
-		arg1 string
-		arg2 []config.BindingDNS
+		arg1 context.Context
+		arg2 string
+		arg3 []config.BindingDNS
 	}
 	getDNSAddressesReturns struct {
 		result1 map[string]string
@@ -100,11 +122,12 @@ type FakeBoshClient struct {
 		result1 map[string]string
 		result2 error
 	}
-	GetDeploymentStub        func(string, *log.Logger) ([]byte, bool, error)
+	GetDeploymentStub        func(context.Context, string, *log.Logger) ([]byte, bool, error)
 	getDeploymentMutex       sync.RWMutex
 	getDeploymentArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	getDeploymentReturns struct {
 		result1 []byte
@@ -116,10 +139,11 @@ type FakeBoshClient struct {
 		result2 bool
 		result3 error
 	}
-	GetDeploymentsStub        func(*log.Logger) ([]boshdirector.Deployment, error)
+	GetDeploymentsStub        func(context.Context, *log.Logger) ([]boshdirector.Deployment, error)
 	getDeploymentsMutex       sync.RWMutex
 	getDeploymentsArgsForCall []struct {
-		arg1 *log.Logger
+		arg1 context.Context
+		arg2 *log.Logger
 	}
 	getDeploymentsReturns struct {
 		result1 []boshdirector.Deployment
@@ -129,10 +153,27 @@ type FakeBoshClient struct {
 		result1 []boshdirector.Deployment
 		result2 error
 	}
-	GetInfoStub        func(*log.Logger) (boshdirector.Info, error)
+	GetEventsStub        func(context.Context, string, string, *log.Logger) ([]boshdirector.BoshEvent, error)
+	getEventsMutex       sync.RWMutex
+	getEventsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
+	}
+	getEventsReturns struct {
+		result1 []boshdirector.BoshEvent
+		result2 error
+	}
+	getEventsReturnsOnCall map[int]struct {
+		result1 []boshdirector.BoshEvent
+		result2 error
+	}
+	GetInfoStub        func(context.Context, *log.Logger) (boshdirector.Info, error)
 	getInfoMutex       sync.RWMutex
 	getInfoArgsForCall []struct {
-		arg1 *log.Logger
+		arg1 context.Context
+		arg2 *log.Logger
 	}
 	getInfoReturns struct {
 		result1 boshdirector.Info
@@ -142,12 +183,13 @@ type FakeBoshClient struct {
 		result1 boshdirector.Info
 		result2 error
 	}
-	GetNormalisedTasksByContextStub        func(string, string, *log.Logger) (boshdirector.BoshTasks, error)
+	GetNormalisedTasksByContextStub        func(context.Context, string, string, *log.Logger) (boshdirector.BoshTasks, error)
 	getNormalisedTasksByContextMutex       sync.RWMutex
 	getNormalisedTasksByContextArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
+		arg3 string
+		arg4 *log.Logger
 	}
 	getNormalisedTasksByContextReturns struct {
 		result1 boshdirector.BoshTasks
@@ -157,11 +199,12 @@ type FakeBoshClient struct {
 		result1 boshdirector.BoshTasks
 		result2 error
 	}
-	GetTaskStub        func(int, *log.Logger) (boshdirector.BoshTask, error)
+	GetTaskStub        func(context.Context, int, *log.Logger) (boshdirector.BoshTask, error)
 	getTaskMutex       sync.RWMutex
 	getTaskArgsForCall []struct {
-		arg1 int
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 int
+		arg3 *log.Logger
 	}
 	getTaskReturns struct {
 		result1 boshdirector.BoshTask
@@ -171,11 +214,12 @@ type FakeBoshClient struct {
 		result1 boshdirector.BoshTask
 		result2 error
 	}
-	GetTasksInProgressStub        func(string, *log.Logger) (boshdirector.BoshTasks, error)
+	GetTasksInProgressStub        func(context.Context, string, *log.Logger) (boshdirector.BoshTasks, error)
 	getTasksInProgressMutex       sync.RWMutex
 	getTasksInProgressArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	getTasksInProgressReturns struct {
 		result1 boshdirector.BoshTasks
@@ -185,13 +229,14 @@ type FakeBoshClient struct {
 		result1 boshdirector.BoshTasks
 		result2 error
 	}
-	RecreateStub        func(string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
+	RecreateStub        func(context.Context, string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
 	recreateMutex       sync.RWMutex
 	recreateArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
 	}
 	recreateReturns struct {
 		result1 int
@@ -201,15 +246,16 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
-	RunErrandStub        func(string, string, []string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
+	RunErrandStub        func(context.Context, string, string, []string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
 	runErrandMutex       sync.RWMutex
 	runErrandArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 []string
-		arg4 string
-		arg5 *log.Logger
-		arg6 *boshdirector.AsyncTaskReporter
+		arg3 string
+		arg4 []string
+		arg5 string
+		arg6 *log.Logger
+		arg7 *boshdirector.AsyncTaskReporter
 	}
 	runErrandReturns struct {
 		result1 int
@@ -219,11 +265,27 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
-	VMsStub        func(string, *log.Logger) (bosh.BoshVMs, error)
+	UpdateConfigStub        func(context.Context, string, string, []byte, *log.Logger) error
+	updateConfigMutex       sync.RWMutex
+	updateConfigArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 []byte
+		arg5 *log.Logger
+	}
+	updateConfigReturns struct {
+		result1 error
+	}
+	updateConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	VMsStub        func(context.Context, string, *log.Logger) (bosh.BoshVMs, error)
 	vMsMutex       sync.RWMutex
 	vMsArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	vMsReturns struct {
 		result1 bosh.BoshVMs
@@ -233,11 +295,12 @@ type FakeBoshClient struct {
 		result1 bosh.BoshVMs
 		result2 error
 	}
-	VariablesStub        func(string, *log.Logger) ([]boshdirector.Variable, error)
+	VariablesStub        func(context.Context, string, *log.Logger) ([]boshdirector.Variable, error)
 	variablesMutex       sync.RWMutex
 	variablesArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	variablesReturns struct {
 		result1 []boshdirector.Variable
@@ -247,10 +310,11 @@ type FakeBoshClient struct {
 		result1 []boshdirector.Variable
 		result2 error
 	}
-	VerifyAuthStub        func(*log.Logger) error
+	VerifyAuthStub        func(context.Context, *log.Logger) error
 	verifyAuthMutex       sync.RWMutex
 	verifyAuthArgsForCall []struct {
-		arg1 *log.Logger
+		arg1 context.Context
+		arg2 *log.Logger
 	}
 	verifyAuthReturns struct {
 		result1 error
@@ -262,20 +326,21 @@ type FakeBoshClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBoshClient) DeleteConfig(arg1 string, arg2 string, arg3 *log.Logger) (bool, error) {
+func (fake *FakeBoshClient) DeleteConfig(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger) (bool, error) {
 	fake.deleteConfigMutex.Lock()
 	ret, specificReturn := fake.deleteConfigReturnsOnCall[len(fake.deleteConfigArgsForCall)]
 	fake.deleteConfigArgsForCall = append(fake.deleteConfigArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.DeleteConfigStub
 	fakeReturns := fake.deleteConfigReturns
-	fake.recordInvocation("DeleteConfig", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteConfig", []interface{}{arg1, arg2, arg3, arg4})
 	fake.deleteConfigMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -289,17 +354,17 @@ func (fake *FakeBoshClient) DeleteConfigCallCount() int {
 	return len(fake.deleteConfigArgsForCall)
 }
 
-func (fake *FakeBoshClient) DeleteConfigCalls(stub func(string, string, *log.Logger) (bool, error)) {
+func (fake *FakeBoshClient) DeleteConfigCalls(stub func(context.Context, string, string, *log.Logger) (bool, error)) {
 	fake.deleteConfigMutex.Lock()
 	defer fake.deleteConfigMutex.Unlock()
 	fake.DeleteConfigStub = stub
 }
 
-func (fake *FakeBoshClient) DeleteConfigArgsForCall(i int) (string, string, *log.Logger) {
+func (fake *FakeBoshClient) DeleteConfigArgsForCall(i int) (context.Context, string, string, *log.Logger) {
 	fake.deleteConfigMutex.RLock()
 	defer fake.deleteConfigMutex.RUnlock()
 	argsForCall := fake.deleteConfigArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeBoshClient) DeleteConfigReturns(result1 bool, result2 error) {
@@ -328,19 +393,20 @@ func (fake *FakeBoshClient) DeleteConfigReturnsOnCall(i int, result1 bool, resul
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) DeleteConfigs(arg1 string, arg2 *log.Logger) error {
+func (fake *FakeBoshClient) DeleteConfigs(arg1 context.Context, arg2 string, arg3 *log.Logger) error {
 	fake.deleteConfigsMutex.Lock()
 	ret, specificReturn := fake.deleteConfigsReturnsOnCall[len(fake.deleteConfigsArgsForCall)]
 	fake.deleteConfigsArgsForCall = append(fake.deleteConfigsArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteConfigsStub
 	fakeReturns := fake.deleteConfigsReturns
-	fake.recordInvocation("DeleteConfigs", []interface{}{arg1, arg2})
+	fake.recordInvocation("DeleteConfigs", []interface{}{arg1, arg2, arg3})
 	fake.deleteConfigsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -354,17 +420,17 @@ func (fake *FakeBoshClient) DeleteConfigsCallCount() int {
 	return len(fake.deleteConfigsArgsForCall)
 }
 
-func (fake *FakeBoshClient) DeleteConfigsCalls(stub func(string, *log.Logger) error) {
+func (fake *FakeBoshClient) DeleteConfigsCalls(stub func(context.Context, string, *log.Logger) error) {
 	fake.deleteConfigsMutex.Lock()
 	defer fake.deleteConfigsMutex.Unlock()
 	fake.DeleteConfigsStub = stub
 }
 
-func (fake *FakeBoshClient) DeleteConfigsArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) DeleteConfigsArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.deleteConfigsMutex.RLock()
 	defer fake.deleteConfigsMutex.RUnlock()
 	argsForCall := fake.deleteConfigsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) DeleteConfigsReturns(result1 error) {
@@ -390,22 +456,23 @@ func (fake *FakeBoshClient) DeleteConfigsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeBoshClient) DeleteDeployment(arg1 string, arg2 string, arg3 bool, arg4 *boshdirector.AsyncTaskReporter, arg5 *log.Logger) (int, error) {
+func (fake *FakeBoshClient) DeleteDeployment(arg1 context.Context, arg2 string, arg3 string, arg4 bool, arg5 *boshdirector.AsyncTaskReporter, arg6 *log.Logger) (int, error) {
 	fake.deleteDeploymentMutex.Lock()
 	ret, specificReturn := fake.deleteDeploymentReturnsOnCall[len(fake.deleteDeploymentArgsForCall)]
 	fake.deleteDeploymentArgsForCall = append(fake.deleteDeploymentArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 bool
-		arg4 *boshdirector.AsyncTaskReporter
-		arg5 *log.Logger
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg3 string
+		arg4 bool
+		arg5 *boshdirector.AsyncTaskReporter
+		arg6 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.DeleteDeploymentStub
 	fakeReturns := fake.deleteDeploymentReturns
-	fake.recordInvocation("DeleteDeployment", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("DeleteDeployment", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.deleteDeploymentMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -419,17 +486,17 @@ func (fake *FakeBoshClient) DeleteDeploymentCallCount() int {
 	return len(fake.deleteDeploymentArgsForCall)
 }
 
-func (fake *FakeBoshClient) DeleteDeploymentCalls(stub func(string, string, bool, *boshdirector.AsyncTaskReporter, *log.Logger) (int, error)) {
+func (fake *FakeBoshClient) DeleteDeploymentCalls(stub func(context.Context, string, string, bool, *boshdirector.AsyncTaskReporter, *log.Logger) (int, error)) {
 	fake.deleteDeploymentMutex.Lock()
 	defer fake.deleteDeploymentMutex.Unlock()
 	fake.DeleteDeploymentStub = stub
 }
 
-func (fake *FakeBoshClient) DeleteDeploymentArgsForCall(i int) (string, string, bool, *boshdirector.AsyncTaskReporter, *log.Logger) {
+func (fake *FakeBoshClient) DeleteDeploymentArgsForCall(i int) (context.Context, string, string, bool, *boshdirector.AsyncTaskReporter, *log.Logger) {
 	fake.deleteDeploymentMutex.RLock()
 	defer fake.deleteDeploymentMutex.RUnlock()
 	argsForCall := fake.deleteDeploymentArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeBoshClient) DeleteDeploymentReturns(result1 int, result2 error) {
@@ -458,26 +525,27 @@ func (fake *FakeBoshClient) DeleteDeploymentReturnsOnCall(i int, result1 int, re
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) Deploy(arg1 []byte, arg2 string, arg3 *log.Logger, arg4 *boshdirector.AsyncTaskReporter) (int, error) {
-	var arg1Copy []byte
-	if arg1 != nil {
-		arg1Copy = make([]byte, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeBoshClient) Deploy(arg1 context.Context, arg2 []byte, arg3 string, arg4 *log.Logger, arg5 *boshdirector.AsyncTaskReporter) (int, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
 	}
 	fake.deployMutex.Lock()
 	ret, specificReturn := fake.deployReturnsOnCall[len(fake.deployArgsForCall)]
 	fake.deployArgsForCall = append(fake.deployArgsForCall, struct {
-		arg1 []byte
-		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
-	}{arg1Copy, arg2, arg3, arg4})
+		arg1 context.Context
+		arg2 []byte
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
+	}{arg1, arg2Copy, arg3, arg4, arg5})
 	stub := fake.DeployStub
 	fakeReturns := fake.deployReturns
-	fake.recordInvocation("Deploy", []interface{}{arg1Copy, arg2, arg3, arg4})
+	fake.recordInvocation("Deploy", []interface{}{arg1, arg2Copy, arg3, arg4, arg5})
 	fake.deployMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -491,17 +559,17 @@ func (fake *FakeBoshClient) DeployCallCount() int {
 	return len(fake.deployArgsForCall)
 }
 
-func (fake *FakeBoshClient) DeployCalls(stub func([]byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
+func (fake *FakeBoshClient) DeployCalls(stub func(context.Context, []byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
 	fake.deployMutex.Lock()
 	defer fake.deployMutex.Unlock()
 	fake.DeployStub = stub
 }
 
-func (fake *FakeBoshClient) DeployArgsForCall(i int) ([]byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
+func (fake *FakeBoshClient) DeployArgsForCall(i int) (context.Context, []byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
 	fake.deployMutex.RLock()
 	defer fake.deployMutex.RUnlock()
 	argsForCall := fake.deployArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeBoshClient) DeployReturns(result1 int, result2 error) {
@@ -530,19 +598,20 @@ func (fake *FakeBoshClient) DeployReturnsOnCall(i int, result1 int, result2 erro
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetConfigs(arg1 string, arg2 *log.Logger) ([]boshdirector.BoshConfig, error) {
+func (fake *FakeBoshClient) GetConfigs(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]boshdirector.BoshConfig, error) {
 	fake.getConfigsMutex.Lock()
 	ret, specificReturn := fake.getConfigsReturnsOnCall[len(fake.getConfigsArgsForCall)]
 	fake.getConfigsArgsForCall = append(fake.getConfigsArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetConfigsStub
 	fakeReturns := fake.getConfigsReturns
-	fake.recordInvocation("GetConfigs", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetConfigs", []interface{}{arg1, arg2, arg3})
 	fake.getConfigsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -556,17 +625,17 @@ func (fake *FakeBoshClient) GetConfigsCallCount() int {
 	return len(fake.getConfigsArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetConfigsCalls(stub func(string, *log.Logger) ([]boshdirector.BoshConfig, error)) {
+func (fake *FakeBoshClient) GetConfigsCalls(stub func(context.Context, string, *log.Logger) ([]boshdirector.BoshConfig, error)) {
 	fake.getConfigsMutex.Lock()
 	defer fake.getConfigsMutex.Unlock()
 	fake.GetConfigsStub = stub
 }
 
-func (fake *FakeBoshClient) GetConfigsArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) GetConfigsArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.getConfigsMutex.RLock()
 	defer fake.getConfigsMutex.RUnlock()
 	argsForCall := fake.getConfigsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetConfigsReturns(result1 []boshdirector.BoshConfig, result2 error) {
@@ -595,24 +664,91 @@ func (fake *FakeBoshClient) GetConfigsReturnsOnCall(i int, result1 []boshdirecto
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetDNSAddresses(arg1 string, arg2 []config.BindingDNS) (map[string]string, error) {
-	var arg2Copy []config.BindingDNS
-	if arg2 != nil {
-		arg2Copy = make([]config.BindingDNS, len(arg2))
-		copy(arg2Copy, arg2)
+func (fake *FakeBoshClient) GetConfigsOfType(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]boshdirector.BoshConfig, error) {
+	fake.getConfigsOfTypeMutex.Lock()
+	ret, specificReturn := fake.getConfigsOfTypeReturnsOnCall[len(fake.getConfigsOfTypeArgsForCall)]
+	fake.getConfigsOfTypeArgsForCall = append(fake.getConfigsOfTypeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.GetConfigsOfTypeStub
+	fakeReturns := fake.getConfigsOfTypeReturns
+	fake.recordInvocation("GetConfigsOfType", []interface{}{arg1, arg2, arg3})
+	fake.getConfigsOfTypeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBoshClient) GetConfigsOfTypeCallCount() int {
+	fake.getConfigsOfTypeMutex.RLock()
+	defer fake.getConfigsOfTypeMutex.RUnlock()
+	return len(fake.getConfigsOfTypeArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetConfigsOfTypeCalls(stub func(context.Context, string, *log.Logger) ([]boshdirector.BoshConfig, error)) {
+	fake.getConfigsOfTypeMutex.Lock()
+	defer fake.getConfigsOfTypeMutex.Unlock()
+	fake.GetConfigsOfTypeStub = stub
+}
+
+func (fake *FakeBoshClient) GetConfigsOfTypeArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.getConfigsOfTypeMutex.RLock()
+	defer fake.getConfigsOfTypeMutex.RUnlock()
+	argsForCall := fake.getConfigsOfTypeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeBoshClient) GetConfigsOfTypeReturns(result1 []boshdirector.BoshConfig, result2 error) {
+	fake.getConfigsOfTypeMutex.Lock()
+	defer fake.getConfigsOfTypeMutex.Unlock()
+	fake.GetConfigsOfTypeStub = nil
+	fake.getConfigsOfTypeReturns = struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetConfigsOfTypeReturnsOnCall(i int, result1 []boshdirector.BoshConfig, result2 error) {
+	fake.getConfigsOfTypeMutex.Lock()
+	defer fake.getConfigsOfTypeMutex.Unlock()
+	fake.GetConfigsOfTypeStub = nil
+	if fake.getConfigsOfTypeReturnsOnCall == nil {
+		fake.getConfigsOfTypeReturnsOnCall = make(map[int]struct {
+			result1 []boshdirector.BoshConfig
+			result2 error
+		})
+	}
+	fake.getConfigsOfTypeReturnsOnCall[i] = struct {
+		result1 []boshdirector.BoshConfig
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetDNSAddresses(arg1 context.Context, arg2 string, arg3 []config.BindingDNS) (map[string]string, error) {
+	var arg3Copy []config.BindingDNS
+	if arg3 != nil {
+		arg3Copy = make([]config.BindingDNS, len(arg3))
+		copy(arg3Copy, arg3)
 	}
 	fake.getDNSAddressesMutex.Lock()
 	ret, specificReturn := fake.getDNSAddressesReturnsOnCall[len(fake.getDNSAddressesArgsForCall)]
 	fake.getDNSAddressesArgsForCall = append(fake.getDNSAddressesArgsForCall, struct {
-		arg1 string
-		arg2 []config.BindingDNS
-	}{arg1, arg2Copy})
+		arg1 context.Context
+		arg2 string
+		arg3 []config.BindingDNS
+	}{arg1, arg2, arg3Copy})
 	stub := fake.GetDNSAddressesStub
 	fakeReturns := fake.getDNSAddressesReturns
-	fake.recordInvocation("GetDNSAddresses", []interface{}{arg1, arg2Copy})
+	fake.recordInvocation("GetDNSAddresses", []interface{}{arg1, arg2, arg3Copy})
 	fake.getDNSAddressesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -626,17 +762,17 @@ func (fake *FakeBoshClient) GetDNSAddressesCallCount() int {
 	return len(fake.getDNSAddressesArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetDNSAddressesCalls(stub func(string, []config.BindingDNS) (map[string]string, error)) {
+func (fake *FakeBoshClient) GetDNSAddressesCalls(stub func(context.Context, string, []config.BindingDNS) (map[string]string, error)) {
 	fake.getDNSAddressesMutex.Lock()
 	defer fake.getDNSAddressesMutex.Unlock()
 	fake.GetDNSAddressesStub = stub
 }
 
-func (fake *FakeBoshClient) GetDNSAddressesArgsForCall(i int) (string, []config.BindingDNS) {
+func (fake *FakeBoshClient) GetDNSAddressesArgsForCall(i int) (context.Context, string, []config.BindingDNS) {
 	fake.getDNSAddressesMutex.RLock()
 	defer fake.getDNSAddressesMutex.RUnlock()
 	argsForCall := fake.getDNSAddressesArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetDNSAddressesReturns(result1 map[string]string, result2 error) {
@@ -665,19 +801,20 @@ func (fake *FakeBoshClient) GetDNSAddressesReturnsOnCall(i int, result1 map[stri
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetDeployment(arg1 string, arg2 *log.Logger) ([]byte, bool, error) {
+func (fake *FakeBoshClient) GetDeployment(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]byte, bool, error) {
 	fake.getDeploymentMutex.Lock()
 	ret, specificReturn := fake.getDeploymentReturnsOnCall[len(fake.getDeploymentArgsForCall)]
 	fake.getDeploymentArgsForCall = append(fake.getDeploymentArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetDeploymentStub
 	fakeReturns := fake.getDeploymentReturns
-	fake.recordInvocation("GetDeployment", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetDeployment", []interface{}{arg1, arg2, arg3})
 	fake.getDeploymentMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -691,17 +828,17 @@ func (fake *FakeBoshClient) GetDeploymentCallCount() int {
 	return len(fake.getDeploymentArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetDeploymentCalls(stub func(string, *log.Logger) ([]byte, bool, error)) {
+func (fake *FakeBoshClient) GetDeploymentCalls(stub func(context.Context, string, *log.Logger) ([]byte, bool, error)) {
 	fake.getDeploymentMutex.Lock()
 	defer fake.getDeploymentMutex.Unlock()
 	fake.GetDeploymentStub = stub
 }
 
-func (fake *FakeBoshClient) GetDeploymentArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) GetDeploymentArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.getDeploymentMutex.RLock()
 	defer fake.getDeploymentMutex.RUnlock()
 	argsForCall := fake.getDeploymentArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetDeploymentReturns(result1 []byte, result2 bool, result3 error) {
@@ -733,18 +870,19 @@ func (fake *FakeBoshClient) GetDeploymentReturnsOnCall(i int, result1 []byte, re
 	}{result1, result2, result3}
 }
 
-func (fake *FakeBoshClient) GetDeployments(arg1 *log.Logger) ([]boshdirector.Deployment, error) {
+func (fake *FakeBoshClient) GetDeployments(arg1 context.Context, arg2 *log.Logger) ([]boshdirector.Deployment, error) {
 	fake.getDeploymentsMutex.Lock()
 	ret, specificReturn := fake.getDeploymentsReturnsOnCall[len(fake.getDeploymentsArgsForCall)]
 	fake.getDeploymentsArgsForCall = append(fake.getDeploymentsArgsForCall, struct {
-		arg1 *log.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 *log.Logger
+	}{arg1, arg2})
 	stub := fake.GetDeploymentsStub
 	fakeReturns := fake.getDeploymentsReturns
-	fake.recordInvocation("GetDeployments", []interface{}{arg1})
+	fake.recordInvocation("GetDeployments", []interface{}{arg1, arg2})
 	fake.getDeploymentsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -758,17 +896,17 @@ func (fake *FakeBoshClient) GetDeploymentsCallCount() int {
 	return len(fake.getDeploymentsArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetDeploymentsCalls(stub func(*log.Logger) ([]boshdirector.Deployment, error)) {
+func (fake *FakeBoshClient) GetDeploymentsCalls(stub func(context.Context, *log.Logger) ([]boshdirector.Deployment, error)) {
 	fake.getDeploymentsMutex.Lock()
 	defer fake.getDeploymentsMutex.Unlock()
 	fake.GetDeploymentsStub = stub
 }
 
-func (fake *FakeBoshClient) GetDeploymentsArgsForCall(i int) *log.Logger {
+func (fake *FakeBoshClient) GetDeploymentsArgsForCall(i int) (context.Context, *log.Logger) {
 	fake.getDeploymentsMutex.RLock()
 	defer fake.getDeploymentsMutex.RUnlock()
 	argsForCall := fake.getDeploymentsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBoshClient) GetDeploymentsReturns(result1 []boshdirector.Deployment, result2 error) {
@@ -797,18 +935,86 @@ func (fake *FakeBoshClient) GetDeploymentsReturnsOnCall(i int, result1 []boshdir
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetInfo(arg1 *log.Logger) (boshdirector.Info, error) {
+func (fake *FakeBoshClient) GetEvents(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger) ([]boshdirector.BoshEvent, error) {
+	fake.getEventsMutex.Lock()
+	ret, specificReturn := fake.getEventsReturnsOnCall[len(fake.getEventsArgsForCall)]
+	fake.getEventsArgsForCall = append(fake.getEventsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetEventsStub
+	fakeReturns := fake.getEventsReturns
+	fake.recordInvocation("GetEvents", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getEventsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBoshClient) GetEventsCallCount() int {
+	fake.getEventsMutex.RLock()
+	defer fake.getEventsMutex.RUnlock()
+	return len(fake.getEventsArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetEventsCalls(stub func(context.Context, string, string, *log.Logger) ([]boshdirector.BoshEvent, error)) {
+	fake.getEventsMutex.Lock()
+	defer fake.getEventsMutex.Unlock()
+	fake.GetEventsStub = stub
+}
+
+func (fake *FakeBoshClient) GetEventsArgsForCall(i int) (context.Context, string, string, *log.Logger) {
+	fake.getEventsMutex.RLock()
+	defer fake.getEventsMutex.RUnlock()
+	argsForCall := fake.getEventsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBoshClient) GetEventsReturns(result1 []boshdirector.BoshEvent, result2 error) {
+	fake.getEventsMutex.Lock()
+	defer fake.getEventsMutex.Unlock()
+	fake.GetEventsStub = nil
+	fake.getEventsReturns = struct {
+		result1 []boshdirector.BoshEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetEventsReturnsOnCall(i int, result1 []boshdirector.BoshEvent, result2 error) {
+	fake.getEventsMutex.Lock()
+	defer fake.getEventsMutex.Unlock()
+	fake.GetEventsStub = nil
+	if fake.getEventsReturnsOnCall == nil {
+		fake.getEventsReturnsOnCall = make(map[int]struct {
+			result1 []boshdirector.BoshEvent
+			result2 error
+		})
+	}
+	fake.getEventsReturnsOnCall[i] = struct {
+		result1 []boshdirector.BoshEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetInfo(arg1 context.Context, arg2 *log.Logger) (boshdirector.Info, error) {
 	fake.getInfoMutex.Lock()
 	ret, specificReturn := fake.getInfoReturnsOnCall[len(fake.getInfoArgsForCall)]
 	fake.getInfoArgsForCall = append(fake.getInfoArgsForCall, struct {
-		arg1 *log.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 *log.Logger
+	}{arg1, arg2})
 	stub := fake.GetInfoStub
 	fakeReturns := fake.getInfoReturns
-	fake.recordInvocation("GetInfo", []interface{}{arg1})
+	fake.recordInvocation("GetInfo", []interface{}{arg1, arg2})
 	fake.getInfoMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -822,17 +1028,17 @@ func (fake *FakeBoshClient) GetInfoCallCount() int {
 	return len(fake.getInfoArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetInfoCalls(stub func(*log.Logger) (boshdirector.Info, error)) {
+func (fake *FakeBoshClient) GetInfoCalls(stub func(context.Context, *log.Logger) (boshdirector.Info, error)) {
 	fake.getInfoMutex.Lock()
 	defer fake.getInfoMutex.Unlock()
 	fake.GetInfoStub = stub
 }
 
-func (fake *FakeBoshClient) GetInfoArgsForCall(i int) *log.Logger {
+func (fake *FakeBoshClient) GetInfoArgsForCall(i int) (context.Context, *log.Logger) {
 	fake.getInfoMutex.RLock()
 	defer fake.getInfoMutex.RUnlock()
 	argsForCall := fake.getInfoArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBoshClient) GetInfoReturns(result1 boshdirector.Info, result2 error) {
@@ -861,20 +1067,21 @@ func (fake *FakeBoshClient) GetInfoReturnsOnCall(i int, result1 boshdirector.Inf
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetNormalisedTasksByContext(arg1 string, arg2 string, arg3 *log.Logger) (boshdirector.BoshTasks, error) {
+func (fake *FakeBoshClient) GetNormalisedTasksByContext(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger) (boshdirector.BoshTasks, error) {
 	fake.getNormalisedTasksByContextMutex.Lock()
 	ret, specificReturn := fake.getNormalisedTasksByContextReturnsOnCall[len(fake.getNormalisedTasksByContextArgsForCall)]
 	fake.getNormalisedTasksByContextArgsForCall = append(fake.getNormalisedTasksByContextArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.GetNormalisedTasksByContextStub
 	fakeReturns := fake.getNormalisedTasksByContextReturns
-	fake.recordInvocation("GetNormalisedTasksByContext", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("GetNormalisedTasksByContext", []interface{}{arg1, arg2, arg3, arg4})
 	fake.getNormalisedTasksByContextMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -888,17 +1095,17 @@ func (fake *FakeBoshClient) GetNormalisedTasksByContextCallCount() int {
 	return len(fake.getNormalisedTasksByContextArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetNormalisedTasksByContextCalls(stub func(string, string, *log.Logger) (boshdirector.BoshTasks, error)) {
+func (fake *FakeBoshClient) GetNormalisedTasksByContextCalls(stub func(context.Context, string, string, *log.Logger) (boshdirector.BoshTasks, error)) {
 	fake.getNormalisedTasksByContextMutex.Lock()
 	defer fake.getNormalisedTasksByContextMutex.Unlock()
 	fake.GetNormalisedTasksByContextStub = stub
 }
 
-func (fake *FakeBoshClient) GetNormalisedTasksByContextArgsForCall(i int) (string, string, *log.Logger) {
+func (fake *FakeBoshClient) GetNormalisedTasksByContextArgsForCall(i int) (context.Context, string, string, *log.Logger) {
 	fake.getNormalisedTasksByContextMutex.RLock()
 	defer fake.getNormalisedTasksByContextMutex.RUnlock()
 	argsForCall := fake.getNormalisedTasksByContextArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeBoshClient) GetNormalisedTasksByContextReturns(result1 boshdirector.BoshTasks, result2 error) {
@@ -927,19 +1134,20 @@ func (fake *FakeBoshClient) GetNormalisedTasksByContextReturnsOnCall(i int, resu
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetTask(arg1 int, arg2 *log.Logger) (boshdirector.BoshTask, error) {
+func (fake *FakeBoshClient) GetTask(arg1 context.Context, arg2 int, arg3 *log.Logger) (boshdirector.BoshTask, error) {
 	fake.getTaskMutex.Lock()
 	ret, specificReturn := fake.getTaskReturnsOnCall[len(fake.getTaskArgsForCall)]
 	fake.getTaskArgsForCall = append(fake.getTaskArgsForCall, struct {
-		arg1 int
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 int
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetTaskStub
 	fakeReturns := fake.getTaskReturns
-	fake.recordInvocation("GetTask", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetTask", []interface{}{arg1, arg2, arg3})
 	fake.getTaskMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -953,17 +1161,17 @@ func (fake *FakeBoshClient) GetTaskCallCount() int {
 	return len(fake.getTaskArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetTaskCalls(stub func(int, *log.Logger) (boshdirector.BoshTask, error)) {
+func (fake *FakeBoshClient) GetTaskCalls(stub func(context.Context, int, *log.Logger) (boshdirector.BoshTask, error)) {
 	fake.getTaskMutex.Lock()
 	defer fake.getTaskMutex.Unlock()
 	fake.GetTaskStub = stub
 }
 
-func (fake *FakeBoshClient) GetTaskArgsForCall(i int) (int, *log.Logger) {
+func (fake *FakeBoshClient) GetTaskArgsForCall(i int) (context.Context, int, *log.Logger) {
 	fake.getTaskMutex.RLock()
 	defer fake.getTaskMutex.RUnlock()
 	argsForCall := fake.getTaskArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetTaskReturns(result1 boshdirector.BoshTask, result2 error) {
@@ -992,19 +1200,20 @@ func (fake *FakeBoshClient) GetTaskReturnsOnCall(i int, result1 boshdirector.Bos
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetTasksInProgress(arg1 string, arg2 *log.Logger) (boshdirector.BoshTasks, error) {
+func (fake *FakeBoshClient) GetTasksInProgress(arg1 context.Context, arg2 string, arg3 *log.Logger) (boshdirector.BoshTasks, error) {
 	fake.getTasksInProgressMutex.Lock()
 	ret, specificReturn := fake.getTasksInProgressReturnsOnCall[len(fake.getTasksInProgressArgsForCall)]
 	fake.getTasksInProgressArgsForCall = append(fake.getTasksInProgressArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetTasksInProgressStub
 	fakeReturns := fake.getTasksInProgressReturns
-	fake.recordInvocation("GetTasksInProgress", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetTasksInProgress", []interface{}{arg1, arg2, arg3})
 	fake.getTasksInProgressMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1018,17 +1227,17 @@ func (fake *FakeBoshClient) GetTasksInProgressCallCount() int {
 	return len(fake.getTasksInProgressArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetTasksInProgressCalls(stub func(string, *log.Logger) (boshdirector.BoshTasks, error)) {
+func (fake *FakeBoshClient) GetTasksInProgressCalls(stub func(context.Context, string, *log.Logger) (boshdirector.BoshTasks, error)) {
 	fake.getTasksInProgressMutex.Lock()
 	defer fake.getTasksInProgressMutex.Unlock()
 	fake.GetTasksInProgressStub = stub
 }
 
-func (fake *FakeBoshClient) GetTasksInProgressArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) GetTasksInProgressArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.getTasksInProgressMutex.RLock()
 	defer fake.getTasksInProgressMutex.RUnlock()
 	argsForCall := fake.getTasksInProgressArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetTasksInProgressReturns(result1 boshdirector.BoshTasks, result2 error) {
@@ -1057,21 +1266,22 @@ func (fake *FakeBoshClient) GetTasksInProgressReturnsOnCall(i int, result1 boshd
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) Recreate(arg1 string, arg2 string, arg3 *log.Logger, arg4 *boshdirector.AsyncTaskReporter) (int, error) {
+func (fake *FakeBoshClient) Recreate(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger, arg5 *boshdirector.AsyncTaskReporter) (int, error) {
 	fake.recreateMutex.Lock()
 	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
 	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
-	}{arg1, arg2, arg3, arg4})
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.RecreateStub
 	fakeReturns := fake.recreateReturns
-	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.recreateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1085,17 +1295,17 @@ func (fake *FakeBoshClient) RecreateCallCount() int {
 	return len(fake.recreateArgsForCall)
 }
 
-func (fake *FakeBoshClient) RecreateCalls(stub func(string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
+func (fake *FakeBoshClient) RecreateCalls(stub func(context.Context, string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
 	fake.recreateMutex.Lock()
 	defer fake.recreateMutex.Unlock()
 	fake.RecreateStub = stub
 }
 
-func (fake *FakeBoshClient) RecreateArgsForCall(i int) (string, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
+func (fake *FakeBoshClient) RecreateArgsForCall(i int) (context.Context, string, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
 	argsForCall := fake.recreateArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeBoshClient) RecreateReturns(result1 int, result2 error) {
@@ -1124,28 +1334,29 @@ func (fake *FakeBoshClient) RecreateReturnsOnCall(i int, result1 int, result2 er
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) RunErrand(arg1 string, arg2 string, arg3 []string, arg4 string, arg5 *log.Logger, arg6 *boshdirector.AsyncTaskReporter) (int, error) {
-	var arg3Copy []string
-	if arg3 != nil {
-		arg3Copy = make([]string, len(arg3))
-		copy(arg3Copy, arg3)
+func (fake *FakeBoshClient) RunErrand(arg1 context.Context, arg2 string, arg3 string, arg4 []string, arg5 string, arg6 *log.Logger, arg7 *boshdirector.AsyncTaskReporter) (int, error) {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.runErrandMutex.Lock()
 	ret, specificReturn := fake.runErrandReturnsOnCall[len(fake.runErrandArgsForCall)]
 	fake.runErrandArgsForCall = append(fake.runErrandArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 []string
-		arg4 string
-		arg5 *log.Logger
-		arg6 *boshdirector.AsyncTaskReporter
-	}{arg1, arg2, arg3Copy, arg4, arg5, arg6})
+		arg3 string
+		arg4 []string
+		arg5 string
+		arg6 *log.Logger
+		arg7 *boshdirector.AsyncTaskReporter
+	}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7})
 	stub := fake.RunErrandStub
 	fakeReturns := fake.runErrandReturns
-	fake.recordInvocation("RunErrand", []interface{}{arg1, arg2, arg3Copy, arg4, arg5, arg6})
+	fake.recordInvocation("RunErrand", []interface{}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7})
 	fake.runErrandMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1159,17 +1370,17 @@ func (fake *FakeBoshClient) RunErrandCallCount() int {
 	return len(fake.runErrandArgsForCall)
 }
 
-func (fake *FakeBoshClient) RunErrandCalls(stub func(string, string, []string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
+func (fake *FakeBoshClient) RunErrandCalls(stub func(context.Context, string, string, []string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
 	fake.runErrandMutex.Lock()
 	defer fake.runErrandMutex.Unlock()
 	fake.RunErrandStub = stub
 }
 
-func (fake *FakeBoshClient) RunErrandArgsForCall(i int) (string, string, []string, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
+func (fake *FakeBoshClient) RunErrandArgsForCall(i int) (context.Context, string, string, []string, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
 	fake.runErrandMutex.RLock()
 	defer fake.runErrandMutex.RUnlock()
 	argsForCall := fake.runErrandArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
 func (fake *FakeBoshClient) RunErrandReturns(result1 int, result2 error) {
@@ -1198,19 +1409,90 @@ func (fake *FakeBoshClient) RunErrandReturnsOnCall(i int, result1 int, result2 e
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) VMs(arg1 string, arg2 *log.Logger) (bosh.BoshVMs, error) {
+func (fake *FakeBoshClient) UpdateConfig(arg1 context.Context, arg2 string, arg3 string, arg4 []byte, arg5 *log.Logger) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.updateConfigMutex.Lock()
+	ret, specificReturn := fake.updateConfigReturnsOnCall[len(fake.updateConfigArgsForCall)]
+	fake.updateConfigArgsForCall = append(fake.updateConfigArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 []byte
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5})
+	stub := fake.UpdateConfigStub
+	fakeReturns := fake.updateConfigReturns
+	fake.recordInvocation("UpdateConfig", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
+	fake.updateConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBoshClient) UpdateConfigCallCount() int {
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
+	return len(fake.updateConfigArgsForCall)
+}
+
+func (fake *FakeBoshClient) UpdateConfigCalls(stub func(context.Context, string, string, []byte, *log.Logger) error) {
+	fake.updateConfigMutex.Lock()
+	defer fake.updateConfigMutex.Unlock()
+	fake.UpdateConfigStub = stub
+}
+
+func (fake *FakeBoshClient) UpdateConfigArgsForCall(i int) (context.Context, string, string, []byte, *log.Logger) {
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
+	argsForCall := fake.updateConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeBoshClient) UpdateConfigReturns(result1 error) {
+	fake.updateConfigMutex.Lock()
+	defer fake.updateConfigMutex.Unlock()
+	fake.UpdateConfigStub = nil
+	fake.updateConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) UpdateConfigReturnsOnCall(i int, result1 error) {
+	fake.updateConfigMutex.Lock()
+	defer fake.updateConfigMutex.Unlock()
+	fake.UpdateConfigStub = nil
+	if fake.updateConfigReturnsOnCall == nil {
+		fake.updateConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) VMs(arg1 context.Context, arg2 string, arg3 *log.Logger) (bosh.BoshVMs, error) {
 	fake.vMsMutex.Lock()
 	ret, specificReturn := fake.vMsReturnsOnCall[len(fake.vMsArgsForCall)]
 	fake.vMsArgsForCall = append(fake.vMsArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.VMsStub
 	fakeReturns := fake.vMsReturns
-	fake.recordInvocation("VMs", []interface{}{arg1, arg2})
+	fake.recordInvocation("VMs", []interface{}{arg1, arg2, arg3})
 	fake.vMsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1224,17 +1506,17 @@ func (fake *FakeBoshClient) VMsCallCount() int {
 	return len(fake.vMsArgsForCall)
 }
 
-func (fake *FakeBoshClient) VMsCalls(stub func(string, *log.Logger) (bosh.BoshVMs, error)) {
+func (fake *FakeBoshClient) VMsCalls(stub func(context.Context, string, *log.Logger) (bosh.BoshVMs, error)) {
 	fake.vMsMutex.Lock()
 	defer fake.vMsMutex.Unlock()
 	fake.VMsStub = stub
 }
 
-func (fake *FakeBoshClient) VMsArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) VMsArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.vMsMutex.RLock()
 	defer fake.vMsMutex.RUnlock()
 	argsForCall := fake.vMsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) VMsReturns(result1 bosh.BoshVMs, result2 error) {
@@ -1263,19 +1545,20 @@ func (fake *FakeBoshClient) VMsReturnsOnCall(i int, result1 bosh.BoshVMs, result
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) Variables(arg1 string, arg2 *log.Logger) ([]boshdirector.Variable, error) {
+func (fake *FakeBoshClient) Variables(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]boshdirector.Variable, error) {
 	fake.variablesMutex.Lock()
 	ret, specificReturn := fake.variablesReturnsOnCall[len(fake.variablesArgsForCall)]
 	fake.variablesArgsForCall = append(fake.variablesArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.VariablesStub
 	fakeReturns := fake.variablesReturns
-	fake.recordInvocation("Variables", []interface{}{arg1, arg2})
+	fake.recordInvocation("Variables", []interface{}{arg1, arg2, arg3})
 	fake.variablesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1289,17 +1572,17 @@ func (fake *FakeBoshClient) VariablesCallCount() int {
 	return len(fake.variablesArgsForCall)
 }
 
-func (fake *FakeBoshClient) VariablesCalls(stub func(string, *log.Logger) ([]boshdirector.Variable, error)) {
+func (fake *FakeBoshClient) VariablesCalls(stub func(context.Context, string, *log.Logger) ([]boshdirector.Variable, error)) {
 	fake.variablesMutex.Lock()
 	defer fake.variablesMutex.Unlock()
 	fake.VariablesStub = stub
 }
 
-func (fake *FakeBoshClient) VariablesArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) VariablesArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.variablesMutex.RLock()
 	defer fake.variablesMutex.RUnlock()
 	argsForCall := fake.variablesArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) VariablesReturns(result1 []boshdirector.Variable, result2 error) {
@@ -1328,18 +1611,19 @@ func (fake *FakeBoshClient) VariablesReturnsOnCall(i int, result1 []boshdirector
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) VerifyAuth(arg1 *log.Logger) error {
+func (fake *FakeBoshClient) VerifyAuth(arg1 context.Context, arg2 *log.Logger) error {
 	fake.verifyAuthMutex.Lock()
 	ret, specificReturn := fake.verifyAuthReturnsOnCall[len(fake.verifyAuthArgsForCall)]
 	fake.verifyAuthArgsForCall = append(fake.verifyAuthArgsForCall, struct {
-		arg1 *log.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 *log.Logger
+	}{arg1, arg2})
 	stub := fake.VerifyAuthStub
 	fakeReturns := fake.verifyAuthReturns
-	fake.recordInvocation("VerifyAuth", []interface{}{arg1})
+	fake.recordInvocation("VerifyAuth", []interface{}{arg1, arg2})
 	fake.verifyAuthMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1353,17 +1637,17 @@ func (fake *FakeBoshClient) VerifyAuthCallCount() int {
 	return len(fake.verifyAuthArgsForCall)
 }
 
-func (fake *FakeBoshClient) VerifyAuthCalls(stub func(*log.Logger) error) {
+func (fake *FakeBoshClient) VerifyAuthCalls(stub func(context.Context, *log.Logger) error) {
 	fake.verifyAuthMutex.Lock()
 	defer fake.verifyAuthMutex.Unlock()
 	fake.VerifyAuthStub = stub
 }
 
-func (fake *FakeBoshClient) VerifyAuthArgsForCall(i int) *log.Logger {
+func (fake *FakeBoshClient) VerifyAuthArgsForCall(i int) (context.Context, *log.Logger) {
 	fake.verifyAuthMutex.RLock()
 	defer fake.verifyAuthMutex.RUnlock()
 	argsForCall := fake.verifyAuthArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBoshClient) VerifyAuthReturns(result1 error) {
@@ -1402,12 +1686,16 @@ func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	defer fake.deployMutex.RUnlock()
 	fake.getConfigsMutex.RLock()
 	defer fake.getConfigsMutex.RUnlock()
+	fake.getConfigsOfTypeMutex.RLock()
+	defer fake.getConfigsOfTypeMutex.RUnlock()
 	fake.getDNSAddressesMutex.RLock()
 	defer fake.getDNSAddressesMutex.RUnlock()
 	fake.getDeploymentMutex.RLock()
 	defer fake.getDeploymentMutex.RUnlock()
 	fake.getDeploymentsMutex.RLock()
 	defer fake.getDeploymentsMutex.RUnlock()
+	fake.getEventsMutex.RLock()
+	defer fake.getEventsMutex.RUnlock()
 	fake.getInfoMutex.RLock()
 	defer fake.getInfoMutex.RUnlock()
 	fake.getNormalisedTasksByContextMutex.RLock()
@@ -1420,6 +1708,8 @@ func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	defer fake.recreateMutex.RUnlock()
 	fake.runErrandMutex.RLock()
 	defer fake.runErrandMutex.RUnlock()
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
 	fake.vMsMutex.RLock()
 	defer fake.vMsMutex.RUnlock()
 	fake.variablesMutex.RLock()
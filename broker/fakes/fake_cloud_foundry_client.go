@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -25,11 +26,12 @@ type FakeCloudFoundryClient struct {
 		result1 int
 		result2 error
 	}
-	CountInstancesOfServiceOfferingStub        func(string, *log.Logger) (map[cf.ServicePlan]int, error)
+	CountInstancesOfServiceOfferingStub        func(context.Context, string, *log.Logger) (map[cf.ServicePlan]int, error)
 	countInstancesOfServiceOfferingMutex       sync.RWMutex
 	countInstancesOfServiceOfferingArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	countInstancesOfServiceOfferingReturns struct {
 		result1 map[cf.ServicePlan]int
@@ -39,6 +41,21 @@ type FakeCloudFoundryClient struct {
 		result1 map[cf.ServicePlan]int
 		result2 error
 	}
+	CountInstancesOfServiceOfferingByOrgAndSpaceStub        func(context.Context, string, *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)
+	countInstancesOfServiceOfferingByOrgAndSpaceMutex       sync.RWMutex
+	countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}
+	countInstancesOfServiceOfferingByOrgAndSpaceReturns struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}
+	countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall map[int]struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}
 	GetAPIVersionStub        func(*log.Logger) (string, error)
 	getAPIVersionMutex       sync.RWMutex
 	getAPIVersionArgsForCall []struct {
@@ -136,19 +153,20 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfPlanReturnsOnCall(i int, res
 	}{result1, result2}
 }
 
-func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOffering(arg1 string, arg2 *log.Logger) (map[cf.ServicePlan]int, error) {
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOffering(arg1 context.Context, arg2 string, arg3 *log.Logger) (map[cf.ServicePlan]int, error) {
 	fake.countInstancesOfServiceOfferingMutex.Lock()
 	ret, specificReturn := fake.countInstancesOfServiceOfferingReturnsOnCall[len(fake.countInstancesOfServiceOfferingArgsForCall)]
 	fake.countInstancesOfServiceOfferingArgsForCall = append(fake.countInstancesOfServiceOfferingArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.CountInstancesOfServiceOfferingStub
 	fakeReturns := fake.countInstancesOfServiceOfferingReturns
-	fake.recordInvocation("CountInstancesOfServiceOffering", []interface{}{arg1, arg2})
+	fake.recordInvocation("CountInstancesOfServiceOffering", []interface{}{arg1, arg2, arg3})
 	fake.countInstancesOfServiceOfferingMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -162,17 +180,17 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingCallCount() i
 	return len(fake.countInstancesOfServiceOfferingArgsForCall)
 }
 
-func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingCalls(stub func(string, *log.Logger) (map[cf.ServicePlan]int, error)) {
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingCalls(stub func(context.Context, string, *log.Logger) (map[cf.ServicePlan]int, error)) {
 	fake.countInstancesOfServiceOfferingMutex.Lock()
 	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
 	fake.CountInstancesOfServiceOfferingStub = stub
 }
 
-func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.countInstancesOfServiceOfferingMutex.RLock()
 	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
 	argsForCall := fake.countInstancesOfServiceOfferingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingReturns(result1 map[cf.ServicePlan]int, result2 error) {
@@ -201,6 +219,72 @@ func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingReturnsOnCall
 	}{result1, result2}
 }
 
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingByOrgAndSpace(arg1 context.Context, arg2 string, arg3 *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	ret, specificReturn := fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall[len(fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall)]
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall = append(fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub
+	fakeReturns := fake.countInstancesOfServiceOfferingByOrgAndSpaceReturns
+	fake.recordInvocation("CountInstancesOfServiceOfferingByOrgAndSpace", []interface{}{arg1, arg2, arg3})
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingByOrgAndSpaceCallCount() int {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RUnlock()
+	return len(fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall)
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingByOrgAndSpaceCalls(stub func(context.Context, string, *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub = stub
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingByOrgAndSpaceArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RUnlock()
+	argsForCall := fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingByOrgAndSpaceReturns(result1 cf.OrgAndSpaceInstanceCounts, result2 error) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub = nil
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceReturns = struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) CountInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall(i int, result1 cf.OrgAndSpaceInstanceCounts, result2 error) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub = nil
+	if fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall == nil {
+		fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall = make(map[int]struct {
+			result1 cf.OrgAndSpaceInstanceCounts
+			result2 error
+		})
+	}
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall[i] = struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCloudFoundryClient) GetAPIVersion(arg1 *log.Logger) (string, error) {
 	fake.getAPIVersionMutex.Lock()
 	ret, specificReturn := fake.getAPIVersionReturnsOnCall[len(fake.getAPIVersionArgsForCall)]
@@ -337,6 +421,8 @@ func (fake *FakeCloudFoundryClient) Invocations() map[string][][]interface{} {
 	defer fake.countInstancesOfPlanMutex.RUnlock()
 	fake.countInstancesOfServiceOfferingMutex.RLock()
 	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RUnlock()
 	fake.getAPIVersionMutex.RLock()
 	defer fake.getAPIVersionMutex.RUnlock()
 	fake.getServiceInstancesMutex.RLock()
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -10,15 +11,31 @@ import (
 )
 
 type FakeDeployer struct {
-	CreateStub        func(string, string, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)
+	CheckDeployableStub        func(context.Context, string, string, map[string]string, *log.Logger) error
+	checkDeployableMutex       sync.RWMutex
+	checkDeployableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]string
+		arg5 *log.Logger
+	}
+	checkDeployableReturns struct {
+		result1 error
+	}
+	checkDeployableReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateStub        func(context.Context, string, string, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 map[string]interface{}
-		arg4 string
-		arg5 map[string]string
-		arg6 *log.Logger
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 map[string]string
+		arg7 *log.Logger
 	}
 	createReturns struct {
 		result1 int
@@ -32,13 +49,52 @@ type FakeDeployer struct {
 		result3 map[string]any
 		result4 error
 	}
-	RecreateStub        func(string, string, string, *log.Logger) (int, error)
+	PreviewUpdateStub        func(context.Context, string, string, map[string]interface{}, *string, map[string]string, map[string]string, *log.Logger) (broker.ManifestDiff, error)
+	previewUpdateMutex       sync.RWMutex
+	previewUpdateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 *string
+		arg6 map[string]string
+		arg7 map[string]string
+		arg8 *log.Logger
+	}
+	previewUpdateReturns struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
+	previewUpdateReturnsOnCall map[int]struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
+	PreviewUpgradeStub        func(context.Context, string, config.Plan, map[string]interface{}, map[string]string, *log.Logger) (broker.ManifestDiff, error)
+	previewUpgradeMutex       sync.RWMutex
+	previewUpgradeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 config.Plan
+		arg4 map[string]interface{}
+		arg5 map[string]string
+		arg6 *log.Logger
+	}
+	previewUpgradeReturns struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
+	previewUpgradeReturnsOnCall map[int]struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
+	RecreateStub        func(context.Context, string, string, string, *log.Logger) (int, error)
 	recreateMutex       sync.RWMutex
 	recreateArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
-		arg4 *log.Logger
+		arg4 string
+		arg5 *log.Logger
 	}
 	recreateReturns struct {
 		result1 int
@@ -48,18 +104,61 @@ type FakeDeployer struct {
 		result1 int
 		result2 error
 	}
-	UpdateStub        func(string, string, map[string]interface{}, *string, string, map[string]string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)
-	updateMutex       sync.RWMutex
-	updateArgsForCall []struct {
-		arg1 string
+	RollbackStub        func(context.Context, string, []byte, map[string]string, string, *log.Logger) (int, error)
+	rollbackMutex       sync.RWMutex
+	rollbackArgsForCall []struct {
+		arg1 context.Context
 		arg2 string
-		arg3 map[string]interface{}
-		arg4 *string
+		arg3 []byte
+		arg4 map[string]string
+		arg5 string
+		arg6 *log.Logger
+	}
+	rollbackReturns struct {
+		result1 int
+		result2 error
+	}
+	rollbackReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	RotateSecretsStub        func(context.Context, string, config.Plan, map[string]interface{}, string, map[string]string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)
+	rotateSecretsMutex       sync.RWMutex
+	rotateSecretsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 config.Plan
+		arg4 map[string]interface{}
 		arg5 string
 		arg6 map[string]string
 		arg7 map[string]string
 		arg8 *log.Logger
 	}
+	rotateSecretsReturns struct {
+		result1 int
+		result2 []byte
+		result3 map[string]any
+		result4 error
+	}
+	rotateSecretsReturnsOnCall map[int]struct {
+		result1 int
+		result2 []byte
+		result3 map[string]any
+		result4 error
+	}
+	UpdateStub        func(context.Context, string, string, map[string]interface{}, *string, string, map[string]string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 *string
+		arg6 string
+		arg7 map[string]string
+		arg8 map[string]string
+		arg9 *log.Logger
+	}
 	updateReturns struct {
 		result1 int
 		result2 []byte
@@ -72,15 +171,16 @@ type FakeDeployer struct {
 		result3 map[string]any
 		result4 error
 	}
-	UpgradeStub        func(string, config.Plan, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)
+	UpgradeStub        func(context.Context, string, config.Plan, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)
 	upgradeMutex       sync.RWMutex
 	upgradeArgsForCall []struct {
-		arg1 string
-		arg2 config.Plan
-		arg3 map[string]interface{}
-		arg4 string
-		arg5 map[string]string
-		arg6 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 config.Plan
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 map[string]string
+		arg7 *log.Logger
 	}
 	upgradeReturns struct {
 		result1 int
@@ -98,23 +198,89 @@ type FakeDeployer struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDeployer) Create(arg1 string, arg2 string, arg3 map[string]interface{}, arg4 string, arg5 map[string]string, arg6 *log.Logger) (int, []byte, map[string]any, error) {
+func (fake *FakeDeployer) CheckDeployable(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]string, arg5 *log.Logger) error {
+	fake.checkDeployableMutex.Lock()
+	ret, specificReturn := fake.checkDeployableReturnsOnCall[len(fake.checkDeployableArgsForCall)]
+	fake.checkDeployableArgsForCall = append(fake.checkDeployableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]string
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.CheckDeployableStub
+	fakeReturns := fake.checkDeployableReturns
+	fake.recordInvocation("CheckDeployable", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.checkDeployableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeDeployer) CheckDeployableCallCount() int {
+	fake.checkDeployableMutex.RLock()
+	defer fake.checkDeployableMutex.RUnlock()
+	return len(fake.checkDeployableArgsForCall)
+}
+
+func (fake *FakeDeployer) CheckDeployableCalls(stub func(context.Context, string, string, map[string]string, *log.Logger) error) {
+	fake.checkDeployableMutex.Lock()
+	defer fake.checkDeployableMutex.Unlock()
+	fake.CheckDeployableStub = stub
+}
+
+func (fake *FakeDeployer) CheckDeployableArgsForCall(i int) (context.Context, string, string, map[string]string, *log.Logger) {
+	fake.checkDeployableMutex.RLock()
+	defer fake.checkDeployableMutex.RUnlock()
+	argsForCall := fake.checkDeployableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeDeployer) CheckDeployableReturns(result1 error) {
+	fake.checkDeployableMutex.Lock()
+	defer fake.checkDeployableMutex.Unlock()
+	fake.CheckDeployableStub = nil
+	fake.checkDeployableReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployer) CheckDeployableReturnsOnCall(i int, result1 error) {
+	fake.checkDeployableMutex.Lock()
+	defer fake.checkDeployableMutex.Unlock()
+	fake.CheckDeployableStub = nil
+	if fake.checkDeployableReturnsOnCall == nil {
+		fake.checkDeployableReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkDeployableReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDeployer) Create(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 string, arg6 map[string]string, arg7 *log.Logger) (int, []byte, map[string]any, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 map[string]interface{}
-		arg4 string
-		arg5 map[string]string
-		arg6 *log.Logger
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 map[string]string
+		arg7 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
 	stub := fake.CreateStub
 	fakeReturns := fake.createReturns
-	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
 	fake.createMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -128,17 +294,17 @@ func (fake *FakeDeployer) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeDeployer) CreateCalls(stub func(string, string, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)) {
+func (fake *FakeDeployer) CreateCalls(stub func(context.Context, string, string, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
 }
 
-func (fake *FakeDeployer) CreateArgsForCall(i int) (string, string, map[string]interface{}, string, map[string]string, *log.Logger) {
+func (fake *FakeDeployer) CreateArgsForCall(i int) (context.Context, string, string, map[string]interface{}, string, map[string]string, *log.Logger) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	argsForCall := fake.createArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
 func (fake *FakeDeployer) CreateReturns(result1 int, result2 []byte, result3 map[string]any, result4 error) {
@@ -173,21 +339,162 @@ func (fake *FakeDeployer) CreateReturnsOnCall(i int, result1 int, result2 []byte
 	}{result1, result2, result3, result4}
 }
 
-func (fake *FakeDeployer) Recreate(arg1 string, arg2 string, arg3 string, arg4 *log.Logger) (int, error) {
+func (fake *FakeDeployer) PreviewUpdate(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 *string, arg6 map[string]string, arg7 map[string]string, arg8 *log.Logger) (broker.ManifestDiff, error) {
+	fake.previewUpdateMutex.Lock()
+	ret, specificReturn := fake.previewUpdateReturnsOnCall[len(fake.previewUpdateArgsForCall)]
+	fake.previewUpdateArgsForCall = append(fake.previewUpdateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 *string
+		arg6 map[string]string
+		arg7 map[string]string
+		arg8 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	stub := fake.PreviewUpdateStub
+	fakeReturns := fake.previewUpdateReturns
+	fake.recordInvocation("PreviewUpdate", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.previewUpdateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeployer) PreviewUpdateCallCount() int {
+	fake.previewUpdateMutex.RLock()
+	defer fake.previewUpdateMutex.RUnlock()
+	return len(fake.previewUpdateArgsForCall)
+}
+
+func (fake *FakeDeployer) PreviewUpdateCalls(stub func(context.Context, string, string, map[string]interface{}, *string, map[string]string, map[string]string, *log.Logger) (broker.ManifestDiff, error)) {
+	fake.previewUpdateMutex.Lock()
+	defer fake.previewUpdateMutex.Unlock()
+	fake.PreviewUpdateStub = stub
+}
+
+func (fake *FakeDeployer) PreviewUpdateArgsForCall(i int) (context.Context, string, string, map[string]interface{}, *string, map[string]string, map[string]string, *log.Logger) {
+	fake.previewUpdateMutex.RLock()
+	defer fake.previewUpdateMutex.RUnlock()
+	argsForCall := fake.previewUpdateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
+}
+
+func (fake *FakeDeployer) PreviewUpdateReturns(result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpdateMutex.Lock()
+	defer fake.previewUpdateMutex.Unlock()
+	fake.PreviewUpdateStub = nil
+	fake.previewUpdateReturns = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) PreviewUpdateReturnsOnCall(i int, result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpdateMutex.Lock()
+	defer fake.previewUpdateMutex.Unlock()
+	fake.PreviewUpdateStub = nil
+	if fake.previewUpdateReturnsOnCall == nil {
+		fake.previewUpdateReturnsOnCall = make(map[int]struct {
+			result1 broker.ManifestDiff
+			result2 error
+		})
+	}
+	fake.previewUpdateReturnsOnCall[i] = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) PreviewUpgrade(arg1 context.Context, arg2 string, arg3 config.Plan, arg4 map[string]interface{}, arg5 map[string]string, arg6 *log.Logger) (broker.ManifestDiff, error) {
+	fake.previewUpgradeMutex.Lock()
+	ret, specificReturn := fake.previewUpgradeReturnsOnCall[len(fake.previewUpgradeArgsForCall)]
+	fake.previewUpgradeArgsForCall = append(fake.previewUpgradeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 config.Plan
+		arg4 map[string]interface{}
+		arg5 map[string]string
+		arg6 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.PreviewUpgradeStub
+	fakeReturns := fake.previewUpgradeReturns
+	fake.recordInvocation("PreviewUpgrade", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.previewUpgradeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeployer) PreviewUpgradeCallCount() int {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	return len(fake.previewUpgradeArgsForCall)
+}
+
+func (fake *FakeDeployer) PreviewUpgradeCalls(stub func(context.Context, string, config.Plan, map[string]interface{}, map[string]string, *log.Logger) (broker.ManifestDiff, error)) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = stub
+}
+
+func (fake *FakeDeployer) PreviewUpgradeArgsForCall(i int) (context.Context, string, config.Plan, map[string]interface{}, map[string]string, *log.Logger) {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	argsForCall := fake.previewUpgradeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeDeployer) PreviewUpgradeReturns(result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	fake.previewUpgradeReturns = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) PreviewUpgradeReturnsOnCall(i int, result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	if fake.previewUpgradeReturnsOnCall == nil {
+		fake.previewUpgradeReturnsOnCall = make(map[int]struct {
+			result1 broker.ManifestDiff
+			result2 error
+		})
+	}
+	fake.previewUpgradeReturnsOnCall[i] = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) Recreate(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 *log.Logger) (int, error) {
 	fake.recreateMutex.Lock()
 	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
 	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
-		arg4 *log.Logger
-	}{arg1, arg2, arg3, arg4})
+		arg4 string
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.RecreateStub
 	fakeReturns := fake.recreateReturns
-	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.recreateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -201,17 +508,17 @@ func (fake *FakeDeployer) RecreateCallCount() int {
 	return len(fake.recreateArgsForCall)
 }
 
-func (fake *FakeDeployer) RecreateCalls(stub func(string, string, string, *log.Logger) (int, error)) {
+func (fake *FakeDeployer) RecreateCalls(stub func(context.Context, string, string, string, *log.Logger) (int, error)) {
 	fake.recreateMutex.Lock()
 	defer fake.recreateMutex.Unlock()
 	fake.RecreateStub = stub
 }
 
-func (fake *FakeDeployer) RecreateArgsForCall(i int) (string, string, string, *log.Logger) {
+func (fake *FakeDeployer) RecreateArgsForCall(i int) (context.Context, string, string, string, *log.Logger) {
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
 	argsForCall := fake.recreateArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeDeployer) RecreateReturns(result1 int, result2 error) {
@@ -240,25 +547,177 @@ func (fake *FakeDeployer) RecreateReturnsOnCall(i int, result1 int, result2 erro
 	}{result1, result2}
 }
 
-func (fake *FakeDeployer) Update(arg1 string, arg2 string, arg3 map[string]interface{}, arg4 *string, arg5 string, arg6 map[string]string, arg7 map[string]string, arg8 *log.Logger) (int, []byte, map[string]any, error) {
-	fake.updateMutex.Lock()
-	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
-	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
-		arg1 string
+func (fake *FakeDeployer) Rollback(arg1 context.Context, arg2 string, arg3 []byte, arg4 map[string]string, arg5 string, arg6 *log.Logger) (int, error) {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.rollbackMutex.Lock()
+	ret, specificReturn := fake.rollbackReturnsOnCall[len(fake.rollbackArgsForCall)]
+	fake.rollbackArgsForCall = append(fake.rollbackArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []byte
+		arg4 map[string]string
+		arg5 string
+		arg6 *log.Logger
+	}{arg1, arg2, arg3Copy, arg4, arg5, arg6})
+	stub := fake.RollbackStub
+	fakeReturns := fake.rollbackReturns
+	fake.recordInvocation("Rollback", []interface{}{arg1, arg2, arg3Copy, arg4, arg5, arg6})
+	fake.rollbackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDeployer) RollbackCallCount() int {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return len(fake.rollbackArgsForCall)
+}
+
+func (fake *FakeDeployer) RollbackCalls(stub func(context.Context, string, []byte, map[string]string, string, *log.Logger) (int, error)) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = stub
+}
+
+func (fake *FakeDeployer) RollbackArgsForCall(i int) (context.Context, string, []byte, map[string]string, string, *log.Logger) {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	argsForCall := fake.rollbackArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeDeployer) RollbackReturns(result1 int, result2 error) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = nil
+	fake.rollbackReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) RollbackReturnsOnCall(i int, result1 int, result2 error) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = nil
+	if fake.rollbackReturnsOnCall == nil {
+		fake.rollbackReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.rollbackReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) RotateSecrets(arg1 context.Context, arg2 string, arg3 config.Plan, arg4 map[string]interface{}, arg5 string, arg6 map[string]string, arg7 map[string]string, arg8 *log.Logger) (int, []byte, map[string]any, error) {
+	fake.rotateSecretsMutex.Lock()
+	ret, specificReturn := fake.rotateSecretsReturnsOnCall[len(fake.rotateSecretsArgsForCall)]
+	fake.rotateSecretsArgsForCall = append(fake.rotateSecretsArgsForCall, struct {
+		arg1 context.Context
 		arg2 string
-		arg3 map[string]interface{}
-		arg4 *string
+		arg3 config.Plan
+		arg4 map[string]interface{}
 		arg5 string
 		arg6 map[string]string
 		arg7 map[string]string
 		arg8 *log.Logger
 	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	stub := fake.RotateSecretsStub
+	fakeReturns := fake.rotateSecretsReturns
+	fake.recordInvocation("RotateSecrets", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.rotateSecretsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3, ret.result4
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3, fakeReturns.result4
+}
+
+func (fake *FakeDeployer) RotateSecretsCallCount() int {
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	return len(fake.rotateSecretsArgsForCall)
+}
+
+func (fake *FakeDeployer) RotateSecretsCalls(stub func(context.Context, string, config.Plan, map[string]interface{}, string, map[string]string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = stub
+}
+
+func (fake *FakeDeployer) RotateSecretsArgsForCall(i int) (context.Context, string, config.Plan, map[string]interface{}, string, map[string]string, map[string]string, *log.Logger) {
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	argsForCall := fake.rotateSecretsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
+}
+
+func (fake *FakeDeployer) RotateSecretsReturns(result1 int, result2 []byte, result3 map[string]any, result4 error) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = nil
+	fake.rotateSecretsReturns = struct {
+		result1 int
+		result2 []byte
+		result3 map[string]any
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeDeployer) RotateSecretsReturnsOnCall(i int, result1 int, result2 []byte, result3 map[string]any, result4 error) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = nil
+	if fake.rotateSecretsReturnsOnCall == nil {
+		fake.rotateSecretsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 []byte
+			result3 map[string]any
+			result4 error
+		})
+	}
+	fake.rotateSecretsReturnsOnCall[i] = struct {
+		result1 int
+		result2 []byte
+		result3 map[string]any
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeDeployer) Update(arg1 context.Context, arg2 string, arg3 string, arg4 map[string]interface{}, arg5 *string, arg6 string, arg7 map[string]string, arg8 map[string]string, arg9 *log.Logger) (int, []byte, map[string]any, error) {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 map[string]interface{}
+		arg5 *string
+		arg6 string
+		arg7 map[string]string
+		arg8 map[string]string
+		arg9 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9})
 	stub := fake.UpdateStub
 	fakeReturns := fake.updateReturns
-	fake.recordInvocation("Update", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.recordInvocation("Update", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9})
 	fake.updateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -272,17 +731,17 @@ func (fake *FakeDeployer) UpdateCallCount() int {
 	return len(fake.updateArgsForCall)
 }
 
-func (fake *FakeDeployer) UpdateCalls(stub func(string, string, map[string]interface{}, *string, string, map[string]string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)) {
+func (fake *FakeDeployer) UpdateCalls(stub func(context.Context, string, string, map[string]interface{}, *string, string, map[string]string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)) {
 	fake.updateMutex.Lock()
 	defer fake.updateMutex.Unlock()
 	fake.UpdateStub = stub
 }
 
-func (fake *FakeDeployer) UpdateArgsForCall(i int) (string, string, map[string]interface{}, *string, string, map[string]string, map[string]string, *log.Logger) {
+func (fake *FakeDeployer) UpdateArgsForCall(i int) (context.Context, string, string, map[string]interface{}, *string, string, map[string]string, map[string]string, *log.Logger) {
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
 	argsForCall := fake.updateArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8, argsForCall.arg9
 }
 
 func (fake *FakeDeployer) UpdateReturns(result1 int, result2 []byte, result3 map[string]any, result4 error) {
@@ -317,23 +776,24 @@ func (fake *FakeDeployer) UpdateReturnsOnCall(i int, result1 int, result2 []byte
 	}{result1, result2, result3, result4}
 }
 
-func (fake *FakeDeployer) Upgrade(arg1 string, arg2 config.Plan, arg3 map[string]interface{}, arg4 string, arg5 map[string]string, arg6 *log.Logger) (int, []byte, map[string]any, error) {
+func (fake *FakeDeployer) Upgrade(arg1 context.Context, arg2 string, arg3 config.Plan, arg4 map[string]interface{}, arg5 string, arg6 map[string]string, arg7 *log.Logger) (int, []byte, map[string]any, error) {
 	fake.upgradeMutex.Lock()
 	ret, specificReturn := fake.upgradeReturnsOnCall[len(fake.upgradeArgsForCall)]
 	fake.upgradeArgsForCall = append(fake.upgradeArgsForCall, struct {
-		arg1 string
-		arg2 config.Plan
-		arg3 map[string]interface{}
-		arg4 string
-		arg5 map[string]string
-		arg6 *log.Logger
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
+		arg1 context.Context
+		arg2 string
+		arg3 config.Plan
+		arg4 map[string]interface{}
+		arg5 string
+		arg6 map[string]string
+		arg7 *log.Logger
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
 	stub := fake.UpgradeStub
 	fakeReturns := fake.upgradeReturns
-	fake.recordInvocation("Upgrade", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("Upgrade", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
 	fake.upgradeMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -347,17 +807,17 @@ func (fake *FakeDeployer) UpgradeCallCount() int {
 	return len(fake.upgradeArgsForCall)
 }
 
-func (fake *FakeDeployer) UpgradeCalls(stub func(string, config.Plan, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)) {
+func (fake *FakeDeployer) UpgradeCalls(stub func(context.Context, string, config.Plan, map[string]interface{}, string, map[string]string, *log.Logger) (int, []byte, map[string]any, error)) {
 	fake.upgradeMutex.Lock()
 	defer fake.upgradeMutex.Unlock()
 	fake.UpgradeStub = stub
 }
 
-func (fake *FakeDeployer) UpgradeArgsForCall(i int) (string, config.Plan, map[string]interface{}, string, map[string]string, *log.Logger) {
+func (fake *FakeDeployer) UpgradeArgsForCall(i int) (context.Context, string, config.Plan, map[string]interface{}, string, map[string]string, *log.Logger) {
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
 	argsForCall := fake.upgradeArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
 func (fake *FakeDeployer) UpgradeReturns(result1 int, result2 []byte, result3 map[string]any, result4 error) {
@@ -395,6 +855,24 @@ func (fake *FakeDeployer) UpgradeReturnsOnCall(i int, result1 int, result2 []byt
 func (fake *FakeDeployer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.checkDeployableMutex.RLock()
+	defer fake.checkDeployableMutex.RUnlock()
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	fake.previewUpdateMutex.RLock()
+	defer fake.previewUpdateMutex.RUnlock()
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	fake.recreateMutex.RLock()
+	defer fake.recreateMutex.RUnlock()
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	fake.upgradeMutex.RLock()
+	defer fake.upgradeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,206 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+)
+
+type FakeInstanceCounter struct {
+	CountInstancesOfServiceOfferingStub        func(context.Context, string, *log.Logger) (map[cf.ServicePlan]int, error)
+	countInstancesOfServiceOfferingMutex       sync.RWMutex
+	countInstancesOfServiceOfferingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}
+	countInstancesOfServiceOfferingReturns struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}
+	countInstancesOfServiceOfferingReturnsOnCall map[int]struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}
+	CountInstancesOfServiceOfferingByOrgAndSpaceStub        func(context.Context, string, *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)
+	countInstancesOfServiceOfferingByOrgAndSpaceMutex       sync.RWMutex
+	countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}
+	countInstancesOfServiceOfferingByOrgAndSpaceReturns struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}
+	countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall map[int]struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOffering(arg1 context.Context, arg2 string, arg3 *log.Logger) (map[cf.ServicePlan]int, error) {
+	fake.countInstancesOfServiceOfferingMutex.Lock()
+	ret, specificReturn := fake.countInstancesOfServiceOfferingReturnsOnCall[len(fake.countInstancesOfServiceOfferingArgsForCall)]
+	fake.countInstancesOfServiceOfferingArgsForCall = append(fake.countInstancesOfServiceOfferingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.CountInstancesOfServiceOfferingStub
+	fakeReturns := fake.countInstancesOfServiceOfferingReturns
+	fake.recordInvocation("CountInstancesOfServiceOffering", []interface{}{arg1, arg2, arg3})
+	fake.countInstancesOfServiceOfferingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingCallCount() int {
+	fake.countInstancesOfServiceOfferingMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
+	return len(fake.countInstancesOfServiceOfferingArgsForCall)
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingCalls(stub func(context.Context, string, *log.Logger) (map[cf.ServicePlan]int, error)) {
+	fake.countInstancesOfServiceOfferingMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingStub = stub
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.countInstancesOfServiceOfferingMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
+	argsForCall := fake.countInstancesOfServiceOfferingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingReturns(result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingStub = nil
+	fake.countInstancesOfServiceOfferingReturns = struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingReturnsOnCall(i int, result1 map[cf.ServicePlan]int, result2 error) {
+	fake.countInstancesOfServiceOfferingMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingStub = nil
+	if fake.countInstancesOfServiceOfferingReturnsOnCall == nil {
+		fake.countInstancesOfServiceOfferingReturnsOnCall = make(map[int]struct {
+			result1 map[cf.ServicePlan]int
+			result2 error
+		})
+	}
+	fake.countInstancesOfServiceOfferingReturnsOnCall[i] = struct {
+		result1 map[cf.ServicePlan]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingByOrgAndSpace(arg1 context.Context, arg2 string, arg3 *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	ret, specificReturn := fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall[len(fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall)]
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall = append(fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub
+	fakeReturns := fake.countInstancesOfServiceOfferingByOrgAndSpaceReturns
+	fake.recordInvocation("CountInstancesOfServiceOfferingByOrgAndSpace", []interface{}{arg1, arg2, arg3})
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingByOrgAndSpaceCallCount() int {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RUnlock()
+	return len(fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall)
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingByOrgAndSpaceCalls(stub func(context.Context, string, *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub = stub
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingByOrgAndSpaceArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RUnlock()
+	argsForCall := fake.countInstancesOfServiceOfferingByOrgAndSpaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingByOrgAndSpaceReturns(result1 cf.OrgAndSpaceInstanceCounts, result2 error) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub = nil
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceReturns = struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstanceCounter) CountInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall(i int, result1 cf.OrgAndSpaceInstanceCounts, result2 error) {
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfServiceOfferingByOrgAndSpaceStub = nil
+	if fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall == nil {
+		fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall = make(map[int]struct {
+			result1 cf.OrgAndSpaceInstanceCounts
+			result2 error
+		})
+	}
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceReturnsOnCall[i] = struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstanceCounter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.countInstancesOfServiceOfferingMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
+	fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfServiceOfferingByOrgAndSpaceMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeInstanceCounter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ broker.InstanceCounter = new(FakeInstanceCounter)
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -10,11 +11,12 @@ import (
 )
 
 type FakeManifestSecretManager struct {
-	DeleteSecretsForInstanceStub        func(string, *log.Logger) error
+	DeleteSecretsForInstanceStub        func(context.Context, string, *log.Logger) error
 	deleteSecretsForInstanceMutex       sync.RWMutex
 	deleteSecretsForInstanceArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	deleteSecretsForInstanceReturns struct {
 		result1 error
@@ -22,12 +24,26 @@ type FakeManifestSecretManager struct {
 	deleteSecretsForInstanceReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ResolveManifestSecretsStub        func([]byte, []boshdirector.Variable, *log.Logger) (map[string]string, error)
+	RegenerateSecretsStub        func(context.Context, []string, *log.Logger) error
+	regenerateSecretsMutex       sync.RWMutex
+	regenerateSecretsArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 *log.Logger
+	}
+	regenerateSecretsReturns struct {
+		result1 error
+	}
+	regenerateSecretsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResolveManifestSecretsStub        func(context.Context, []byte, []boshdirector.Variable, *log.Logger) (map[string]string, error)
 	resolveManifestSecretsMutex       sync.RWMutex
 	resolveManifestSecretsArgsForCall []struct {
-		arg1 []byte
-		arg2 []boshdirector.Variable
-		arg3 *log.Logger
+		arg1 context.Context
+		arg2 []byte
+		arg3 []boshdirector.Variable
+		arg4 *log.Logger
 	}
 	resolveManifestSecretsReturns struct {
 		result1 map[string]string
@@ -37,23 +53,37 @@ type FakeManifestSecretManager struct {
 		result1 map[string]string
 		result2 error
 	}
+	RestoreSecretsStub        func(context.Context, []boshdirector.Variable, *log.Logger) error
+	restoreSecretsMutex       sync.RWMutex
+	restoreSecretsArgsForCall []struct {
+		arg1 context.Context
+		arg2 []boshdirector.Variable
+		arg3 *log.Logger
+	}
+	restoreSecretsReturns struct {
+		result1 error
+	}
+	restoreSecretsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeManifestSecretManager) DeleteSecretsForInstance(arg1 string, arg2 *log.Logger) error {
+func (fake *FakeManifestSecretManager) DeleteSecretsForInstance(arg1 context.Context, arg2 string, arg3 *log.Logger) error {
 	fake.deleteSecretsForInstanceMutex.Lock()
 	ret, specificReturn := fake.deleteSecretsForInstanceReturnsOnCall[len(fake.deleteSecretsForInstanceArgsForCall)]
 	fake.deleteSecretsForInstanceArgsForCall = append(fake.deleteSecretsForInstanceArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteSecretsForInstanceStub
 	fakeReturns := fake.deleteSecretsForInstanceReturns
-	fake.recordInvocation("DeleteSecretsForInstance", []interface{}{arg1, arg2})
+	fake.recordInvocation("DeleteSecretsForInstance", []interface{}{arg1, arg2, arg3})
 	fake.deleteSecretsForInstanceMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -67,17 +97,17 @@ func (fake *FakeManifestSecretManager) DeleteSecretsForInstanceCallCount() int {
 	return len(fake.deleteSecretsForInstanceArgsForCall)
 }
 
-func (fake *FakeManifestSecretManager) DeleteSecretsForInstanceCalls(stub func(string, *log.Logger) error) {
+func (fake *FakeManifestSecretManager) DeleteSecretsForInstanceCalls(stub func(context.Context, string, *log.Logger) error) {
 	fake.deleteSecretsForInstanceMutex.Lock()
 	defer fake.deleteSecretsForInstanceMutex.Unlock()
 	fake.DeleteSecretsForInstanceStub = stub
 }
 
-func (fake *FakeManifestSecretManager) DeleteSecretsForInstanceArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeManifestSecretManager) DeleteSecretsForInstanceArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.deleteSecretsForInstanceMutex.RLock()
 	defer fake.deleteSecretsForInstanceMutex.RUnlock()
 	argsForCall := fake.deleteSecretsForInstanceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeManifestSecretManager) DeleteSecretsForInstanceReturns(result1 error) {
@@ -103,30 +133,99 @@ func (fake *FakeManifestSecretManager) DeleteSecretsForInstanceReturnsOnCall(i i
 	}{result1}
 }
 
-func (fake *FakeManifestSecretManager) ResolveManifestSecrets(arg1 []byte, arg2 []boshdirector.Variable, arg3 *log.Logger) (map[string]string, error) {
-	var arg1Copy []byte
-	if arg1 != nil {
-		arg1Copy = make([]byte, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeManifestSecretManager) RegenerateSecrets(arg1 context.Context, arg2 []string, arg3 *log.Logger) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
 	}
-	var arg2Copy []boshdirector.Variable
+	fake.regenerateSecretsMutex.Lock()
+	ret, specificReturn := fake.regenerateSecretsReturnsOnCall[len(fake.regenerateSecretsArgsForCall)]
+	fake.regenerateSecretsArgsForCall = append(fake.regenerateSecretsArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 *log.Logger
+	}{arg1, arg2Copy, arg3})
+	stub := fake.RegenerateSecretsStub
+	fakeReturns := fake.regenerateSecretsReturns
+	fake.recordInvocation("RegenerateSecrets", []interface{}{arg1, arg2Copy, arg3})
+	fake.regenerateSecretsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeManifestSecretManager) RegenerateSecretsCallCount() int {
+	fake.regenerateSecretsMutex.RLock()
+	defer fake.regenerateSecretsMutex.RUnlock()
+	return len(fake.regenerateSecretsArgsForCall)
+}
+
+func (fake *FakeManifestSecretManager) RegenerateSecretsCalls(stub func(context.Context, []string, *log.Logger) error) {
+	fake.regenerateSecretsMutex.Lock()
+	defer fake.regenerateSecretsMutex.Unlock()
+	fake.RegenerateSecretsStub = stub
+}
+
+func (fake *FakeManifestSecretManager) RegenerateSecretsArgsForCall(i int) (context.Context, []string, *log.Logger) {
+	fake.regenerateSecretsMutex.RLock()
+	defer fake.regenerateSecretsMutex.RUnlock()
+	argsForCall := fake.regenerateSecretsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeManifestSecretManager) RegenerateSecretsReturns(result1 error) {
+	fake.regenerateSecretsMutex.Lock()
+	defer fake.regenerateSecretsMutex.Unlock()
+	fake.RegenerateSecretsStub = nil
+	fake.regenerateSecretsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManifestSecretManager) RegenerateSecretsReturnsOnCall(i int, result1 error) {
+	fake.regenerateSecretsMutex.Lock()
+	defer fake.regenerateSecretsMutex.Unlock()
+	fake.RegenerateSecretsStub = nil
+	if fake.regenerateSecretsReturnsOnCall == nil {
+		fake.regenerateSecretsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.regenerateSecretsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManifestSecretManager) ResolveManifestSecrets(arg1 context.Context, arg2 []byte, arg3 []boshdirector.Variable, arg4 *log.Logger) (map[string]string, error) {
+	var arg2Copy []byte
 	if arg2 != nil {
-		arg2Copy = make([]boshdirector.Variable, len(arg2))
+		arg2Copy = make([]byte, len(arg2))
 		copy(arg2Copy, arg2)
 	}
+	var arg3Copy []boshdirector.Variable
+	if arg3 != nil {
+		arg3Copy = make([]boshdirector.Variable, len(arg3))
+		copy(arg3Copy, arg3)
+	}
 	fake.resolveManifestSecretsMutex.Lock()
 	ret, specificReturn := fake.resolveManifestSecretsReturnsOnCall[len(fake.resolveManifestSecretsArgsForCall)]
 	fake.resolveManifestSecretsArgsForCall = append(fake.resolveManifestSecretsArgsForCall, struct {
-		arg1 []byte
-		arg2 []boshdirector.Variable
-		arg3 *log.Logger
-	}{arg1Copy, arg2Copy, arg3})
+		arg1 context.Context
+		arg2 []byte
+		arg3 []boshdirector.Variable
+		arg4 *log.Logger
+	}{arg1, arg2Copy, arg3Copy, arg4})
 	stub := fake.ResolveManifestSecretsStub
 	fakeReturns := fake.resolveManifestSecretsReturns
-	fake.recordInvocation("ResolveManifestSecrets", []interface{}{arg1Copy, arg2Copy, arg3})
+	fake.recordInvocation("ResolveManifestSecrets", []interface{}{arg1, arg2Copy, arg3Copy, arg4})
 	fake.resolveManifestSecretsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -140,17 +239,17 @@ func (fake *FakeManifestSecretManager) ResolveManifestSecretsCallCount() int {
 	return len(fake.resolveManifestSecretsArgsForCall)
 }
 
-func (fake *FakeManifestSecretManager) ResolveManifestSecretsCalls(stub func([]byte, []boshdirector.Variable, *log.Logger) (map[string]string, error)) {
+func (fake *FakeManifestSecretManager) ResolveManifestSecretsCalls(stub func(context.Context, []byte, []boshdirector.Variable, *log.Logger) (map[string]string, error)) {
 	fake.resolveManifestSecretsMutex.Lock()
 	defer fake.resolveManifestSecretsMutex.Unlock()
 	fake.ResolveManifestSecretsStub = stub
 }
 
-func (fake *FakeManifestSecretManager) ResolveManifestSecretsArgsForCall(i int) ([]byte, []boshdirector.Variable, *log.Logger) {
+func (fake *FakeManifestSecretManager) ResolveManifestSecretsArgsForCall(i int) (context.Context, []byte, []boshdirector.Variable, *log.Logger) {
 	fake.resolveManifestSecretsMutex.RLock()
 	defer fake.resolveManifestSecretsMutex.RUnlock()
 	argsForCall := fake.resolveManifestSecretsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeManifestSecretManager) ResolveManifestSecretsReturns(result1 map[string]string, result2 error) {
@@ -179,13 +278,85 @@ func (fake *FakeManifestSecretManager) ResolveManifestSecretsReturnsOnCall(i int
 	}{result1, result2}
 }
 
+func (fake *FakeManifestSecretManager) RestoreSecrets(arg1 context.Context, arg2 []boshdirector.Variable, arg3 *log.Logger) error {
+	var arg2Copy []boshdirector.Variable
+	if arg2 != nil {
+		arg2Copy = make([]boshdirector.Variable, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.restoreSecretsMutex.Lock()
+	ret, specificReturn := fake.restoreSecretsReturnsOnCall[len(fake.restoreSecretsArgsForCall)]
+	fake.restoreSecretsArgsForCall = append(fake.restoreSecretsArgsForCall, struct {
+		arg1 context.Context
+		arg2 []boshdirector.Variable
+		arg3 *log.Logger
+	}{arg1, arg2Copy, arg3})
+	stub := fake.RestoreSecretsStub
+	fakeReturns := fake.restoreSecretsReturns
+	fake.recordInvocation("RestoreSecrets", []interface{}{arg1, arg2Copy, arg3})
+	fake.restoreSecretsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeManifestSecretManager) RestoreSecretsCallCount() int {
+	fake.restoreSecretsMutex.RLock()
+	defer fake.restoreSecretsMutex.RUnlock()
+	return len(fake.restoreSecretsArgsForCall)
+}
+
+func (fake *FakeManifestSecretManager) RestoreSecretsCalls(stub func(context.Context, []boshdirector.Variable, *log.Logger) error) {
+	fake.restoreSecretsMutex.Lock()
+	defer fake.restoreSecretsMutex.Unlock()
+	fake.RestoreSecretsStub = stub
+}
+
+func (fake *FakeManifestSecretManager) RestoreSecretsArgsForCall(i int) (context.Context, []boshdirector.Variable, *log.Logger) {
+	fake.restoreSecretsMutex.RLock()
+	defer fake.restoreSecretsMutex.RUnlock()
+	argsForCall := fake.restoreSecretsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeManifestSecretManager) RestoreSecretsReturns(result1 error) {
+	fake.restoreSecretsMutex.Lock()
+	defer fake.restoreSecretsMutex.Unlock()
+	fake.RestoreSecretsStub = nil
+	fake.restoreSecretsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManifestSecretManager) RestoreSecretsReturnsOnCall(i int, result1 error) {
+	fake.restoreSecretsMutex.Lock()
+	defer fake.restoreSecretsMutex.Unlock()
+	fake.RestoreSecretsStub = nil
+	if fake.restoreSecretsReturnsOnCall == nil {
+		fake.restoreSecretsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreSecretsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManifestSecretManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.deleteSecretsForInstanceMutex.RLock()
 	defer fake.deleteSecretsForInstanceMutex.RUnlock()
+	fake.regenerateSecretsMutex.RLock()
+	defer fake.regenerateSecretsMutex.RUnlock()
 	fake.resolveManifestSecretsMutex.RLock()
 	defer fake.resolveManifestSecretsMutex.RUnlock()
+	fake.restoreSecretsMutex.RLock()
+	defer fake.restoreSecretsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -12,16 +13,17 @@ import (
 )
 
 type FakeServiceAdapterClient struct {
-	CreateBindingStub        func(string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)
+	CreateBindingStub        func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)
 	createBindingMutex       sync.RWMutex
 	createBindingArgsForCall []struct {
-		arg1 string
-		arg2 bosh.BoshVMs
-		arg3 []byte
-		arg4 map[string]interface{}
-		arg5 map[string]string
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
 		arg6 map[string]string
-		arg7 *log.Logger
+		arg7 map[string]string
+		arg8 *log.Logger
 	}
 	createBindingReturns struct {
 		result1 serviceadapter.Binding
@@ -31,16 +33,17 @@ type FakeServiceAdapterClient struct {
 		result1 serviceadapter.Binding
 		result2 error
 	}
-	DeleteBindingStub        func(string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) error
+	DeleteBindingStub        func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) error
 	deleteBindingMutex       sync.RWMutex
 	deleteBindingArgsForCall []struct {
-		arg1 string
-		arg2 bosh.BoshVMs
-		arg3 []byte
-		arg4 map[string]interface{}
-		arg5 map[string]string
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
 		arg6 map[string]string
-		arg7 *log.Logger
+		arg7 map[string]string
+		arg8 *log.Logger
 	}
 	deleteBindingReturns struct {
 		result1 error
@@ -48,13 +51,14 @@ type FakeServiceAdapterClient struct {
 	deleteBindingReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GenerateDashboardUrlStub        func(string, serviceadapter.Plan, []byte, *log.Logger) (string, error)
+	GenerateDashboardUrlStub        func(context.Context, string, serviceadapter.Plan, []byte, *log.Logger) (string, error)
 	generateDashboardUrlMutex       sync.RWMutex
 	generateDashboardUrlArgsForCall []struct {
-		arg1 string
-		arg2 serviceadapter.Plan
-		arg3 []byte
-		arg4 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 serviceadapter.Plan
+		arg4 []byte
+		arg5 *log.Logger
 	}
 	generateDashboardUrlReturns struct {
 		result1 string
@@ -64,11 +68,12 @@ type FakeServiceAdapterClient struct {
 		result1 string
 		result2 error
 	}
-	GeneratePlanSchemaStub        func(serviceadapter.Plan, *log.Logger) (domain.ServiceSchemas, error)
+	GeneratePlanSchemaStub        func(context.Context, serviceadapter.Plan, *log.Logger) (domain.ServiceSchemas, error)
 	generatePlanSchemaMutex       sync.RWMutex
 	generatePlanSchemaArgsForCall []struct {
-		arg1 serviceadapter.Plan
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 serviceadapter.Plan
+		arg3 *log.Logger
 	}
 	generatePlanSchemaReturns struct {
 		result1 domain.ServiceSchemas
@@ -82,29 +87,30 @@ type FakeServiceAdapterClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeServiceAdapterClient) CreateBinding(arg1 string, arg2 bosh.BoshVMs, arg3 []byte, arg4 map[string]interface{}, arg5 map[string]string, arg6 map[string]string, arg7 *log.Logger) (serviceadapter.Binding, error) {
-	var arg3Copy []byte
-	if arg3 != nil {
-		arg3Copy = make([]byte, len(arg3))
-		copy(arg3Copy, arg3)
+func (fake *FakeServiceAdapterClient) CreateBinding(arg1 context.Context, arg2 string, arg3 bosh.BoshVMs, arg4 []byte, arg5 map[string]interface{}, arg6 map[string]string, arg7 map[string]string, arg8 *log.Logger) (serviceadapter.Binding, error) {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.createBindingMutex.Lock()
 	ret, specificReturn := fake.createBindingReturnsOnCall[len(fake.createBindingArgsForCall)]
 	fake.createBindingArgsForCall = append(fake.createBindingArgsForCall, struct {
-		arg1 string
-		arg2 bosh.BoshVMs
-		arg3 []byte
-		arg4 map[string]interface{}
-		arg5 map[string]string
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
 		arg6 map[string]string
-		arg7 *log.Logger
-	}{arg1, arg2, arg3Copy, arg4, arg5, arg6, arg7})
+		arg7 map[string]string
+		arg8 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
 	stub := fake.CreateBindingStub
 	fakeReturns := fake.createBindingReturns
-	fake.recordInvocation("CreateBinding", []interface{}{arg1, arg2, arg3Copy, arg4, arg5, arg6, arg7})
+	fake.recordInvocation("CreateBinding", []interface{}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
 	fake.createBindingMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -118,17 +124,17 @@ func (fake *FakeServiceAdapterClient) CreateBindingCallCount() int {
 	return len(fake.createBindingArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) CreateBindingCalls(stub func(string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)) {
+func (fake *FakeServiceAdapterClient) CreateBindingCalls(stub func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) (serviceadapter.Binding, error)) {
 	fake.createBindingMutex.Lock()
 	defer fake.createBindingMutex.Unlock()
 	fake.CreateBindingStub = stub
 }
 
-func (fake *FakeServiceAdapterClient) CreateBindingArgsForCall(i int) (string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) {
+func (fake *FakeServiceAdapterClient) CreateBindingArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) {
 	fake.createBindingMutex.RLock()
 	defer fake.createBindingMutex.RUnlock()
 	argsForCall := fake.createBindingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeServiceAdapterClient) CreateBindingReturns(result1 serviceadapter.Binding, result2 error) {
@@ -157,29 +163,30 @@ func (fake *FakeServiceAdapterClient) CreateBindingReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeServiceAdapterClient) DeleteBinding(arg1 string, arg2 bosh.BoshVMs, arg3 []byte, arg4 map[string]interface{}, arg5 map[string]string, arg6 map[string]string, arg7 *log.Logger) error {
-	var arg3Copy []byte
-	if arg3 != nil {
-		arg3Copy = make([]byte, len(arg3))
-		copy(arg3Copy, arg3)
+func (fake *FakeServiceAdapterClient) DeleteBinding(arg1 context.Context, arg2 string, arg3 bosh.BoshVMs, arg4 []byte, arg5 map[string]interface{}, arg6 map[string]string, arg7 map[string]string, arg8 *log.Logger) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.deleteBindingMutex.Lock()
 	ret, specificReturn := fake.deleteBindingReturnsOnCall[len(fake.deleteBindingArgsForCall)]
 	fake.deleteBindingArgsForCall = append(fake.deleteBindingArgsForCall, struct {
-		arg1 string
-		arg2 bosh.BoshVMs
-		arg3 []byte
-		arg4 map[string]interface{}
-		arg5 map[string]string
+		arg1 context.Context
+		arg2 string
+		arg3 bosh.BoshVMs
+		arg4 []byte
+		arg5 map[string]interface{}
 		arg6 map[string]string
-		arg7 *log.Logger
-	}{arg1, arg2, arg3Copy, arg4, arg5, arg6, arg7})
+		arg7 map[string]string
+		arg8 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
 	stub := fake.DeleteBindingStub
 	fakeReturns := fake.deleteBindingReturns
-	fake.recordInvocation("DeleteBinding", []interface{}{arg1, arg2, arg3Copy, arg4, arg5, arg6, arg7})
+	fake.recordInvocation("DeleteBinding", []interface{}{arg1, arg2, arg3, arg4Copy, arg5, arg6, arg7, arg8})
 	fake.deleteBindingMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1
@@ -193,17 +200,17 @@ func (fake *FakeServiceAdapterClient) DeleteBindingCallCount() int {
 	return len(fake.deleteBindingArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) DeleteBindingCalls(stub func(string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) error) {
+func (fake *FakeServiceAdapterClient) DeleteBindingCalls(stub func(context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) error) {
 	fake.deleteBindingMutex.Lock()
 	defer fake.deleteBindingMutex.Unlock()
 	fake.DeleteBindingStub = stub
 }
 
-func (fake *FakeServiceAdapterClient) DeleteBindingArgsForCall(i int) (string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) {
+func (fake *FakeServiceAdapterClient) DeleteBindingArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, map[string]string, map[string]string, *log.Logger) {
 	fake.deleteBindingMutex.RLock()
 	defer fake.deleteBindingMutex.RUnlock()
 	argsForCall := fake.deleteBindingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeServiceAdapterClient) DeleteBindingReturns(result1 error) {
@@ -229,26 +236,27 @@ func (fake *FakeServiceAdapterClient) DeleteBindingReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakeServiceAdapterClient) GenerateDashboardUrl(arg1 string, arg2 serviceadapter.Plan, arg3 []byte, arg4 *log.Logger) (string, error) {
-	var arg3Copy []byte
-	if arg3 != nil {
-		arg3Copy = make([]byte, len(arg3))
-		copy(arg3Copy, arg3)
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrl(arg1 context.Context, arg2 string, arg3 serviceadapter.Plan, arg4 []byte, arg5 *log.Logger) (string, error) {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.generateDashboardUrlMutex.Lock()
 	ret, specificReturn := fake.generateDashboardUrlReturnsOnCall[len(fake.generateDashboardUrlArgsForCall)]
 	fake.generateDashboardUrlArgsForCall = append(fake.generateDashboardUrlArgsForCall, struct {
-		arg1 string
-		arg2 serviceadapter.Plan
-		arg3 []byte
-		arg4 *log.Logger
-	}{arg1, arg2, arg3Copy, arg4})
+		arg1 context.Context
+		arg2 string
+		arg3 serviceadapter.Plan
+		arg4 []byte
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5})
 	stub := fake.GenerateDashboardUrlStub
 	fakeReturns := fake.generateDashboardUrlReturns
-	fake.recordInvocation("GenerateDashboardUrl", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.recordInvocation("GenerateDashboardUrl", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
 	fake.generateDashboardUrlMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -262,17 +270,17 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrlCallCount() int {
 	return len(fake.generateDashboardUrlArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) GenerateDashboardUrlCalls(stub func(string, serviceadapter.Plan, []byte, *log.Logger) (string, error)) {
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrlCalls(stub func(context.Context, string, serviceadapter.Plan, []byte, *log.Logger) (string, error)) {
 	fake.generateDashboardUrlMutex.Lock()
 	defer fake.generateDashboardUrlMutex.Unlock()
 	fake.GenerateDashboardUrlStub = stub
 }
 
-func (fake *FakeServiceAdapterClient) GenerateDashboardUrlArgsForCall(i int) (string, serviceadapter.Plan, []byte, *log.Logger) {
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrlArgsForCall(i int) (context.Context, string, serviceadapter.Plan, []byte, *log.Logger) {
 	fake.generateDashboardUrlMutex.RLock()
 	defer fake.generateDashboardUrlMutex.RUnlock()
 	argsForCall := fake.generateDashboardUrlArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturns(result1 string, result2 error) {
@@ -301,19 +309,20 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
-func (fake *FakeServiceAdapterClient) GeneratePlanSchema(arg1 serviceadapter.Plan, arg2 *log.Logger) (domain.ServiceSchemas, error) {
+func (fake *FakeServiceAdapterClient) GeneratePlanSchema(arg1 context.Context, arg2 serviceadapter.Plan, arg3 *log.Logger) (domain.ServiceSchemas, error) {
 	fake.generatePlanSchemaMutex.Lock()
 	ret, specificReturn := fake.generatePlanSchemaReturnsOnCall[len(fake.generatePlanSchemaArgsForCall)]
 	fake.generatePlanSchemaArgsForCall = append(fake.generatePlanSchemaArgsForCall, struct {
-		arg1 serviceadapter.Plan
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 serviceadapter.Plan
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GeneratePlanSchemaStub
 	fakeReturns := fake.generatePlanSchemaReturns
-	fake.recordInvocation("GeneratePlanSchema", []interface{}{arg1, arg2})
+	fake.recordInvocation("GeneratePlanSchema", []interface{}{arg1, arg2, arg3})
 	fake.generatePlanSchemaMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -327,17 +336,17 @@ func (fake *FakeServiceAdapterClient) GeneratePlanSchemaCallCount() int {
 	return len(fake.generatePlanSchemaArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) GeneratePlanSchemaCalls(stub func(serviceadapter.Plan, *log.Logger) (domain.ServiceSchemas, error)) {
+func (fake *FakeServiceAdapterClient) GeneratePlanSchemaCalls(stub func(context.Context, serviceadapter.Plan, *log.Logger) (domain.ServiceSchemas, error)) {
 	fake.generatePlanSchemaMutex.Lock()
 	defer fake.generatePlanSchemaMutex.Unlock()
 	fake.GeneratePlanSchemaStub = stub
 }
 
-func (fake *FakeServiceAdapterClient) GeneratePlanSchemaArgsForCall(i int) (serviceadapter.Plan, *log.Logger) {
+func (fake *FakeServiceAdapterClient) GeneratePlanSchemaArgsForCall(i int) (context.Context, serviceadapter.Plan, *log.Logger) {
 	fake.generatePlanSchemaMutex.RLock()
 	defer fake.generatePlanSchemaMutex.RUnlock()
 	argsForCall := fake.generatePlanSchemaArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeServiceAdapterClient) GeneratePlanSchemaReturns(result1 domain.ServiceSchemas, result2 error) {
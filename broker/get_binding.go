@@ -2,13 +2,34 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"github.com/pborman/uuid"
+
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 )
 
 func (b *Broker) GetBinding(ctx context.Context, instanceID, bindingID string, bindingsDetails domain.FetchBindingDetails) (domain.GetBindingSpec, error) {
-	err := apiresponses.NewFailureResponse(errors.New("GetBinding Not Implemented"), 404, "")
-	return domain.GetBindingSpec{}, err
+	requestID := uuid.New()
+	if len(brokercontext.GetReqID(ctx)) > 0 {
+		requestID = brokercontext.GetReqID(ctx)
+	}
+
+	ctx = brokercontext.New(ctx, "get-binding", requestID, b.offering().Name, instanceID)
+	logger := b.loggerFactory.NewWithContext(ctx)
+
+	record, found, err := b.getBinding(ctx, instanceID, bindingID, logger)
+	if err != nil {
+		return domain.GetBindingSpec{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+	if !found || record.State != domain.Succeeded {
+		return domain.GetBindingSpec{}, b.processError(NewDisplayableError(
+			apiresponses.ErrBindingNotFound,
+			fmt.Errorf("fetching binding %s for instance %s: binding not found", bindingID, instanceID),
+		), logger)
+	}
+
+	return record.bindingSpec(), nil
 }
@@ -12,7 +12,11 @@ import (
 )
 
 var _ = Describe("GetBinding", func() {
-	It("returns an error", func() {
+	BeforeEach(func() {
+		b = createDefaultBroker()
+	})
+
+	It("returns a not found error for an unknown binding", func() {
 		_, err := b.GetBinding(context.Background(), "instanceID", "bID", domain.FetchBindingDetails{ServiceID: "test service", PlanID: "test plan"})
 		fresp, ok := err.(*apiresponses.FailureResponse)
 		Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
@@ -2,13 +2,69 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"github.com/pborman/uuid"
+
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 func (b *Broker) GetInstance(ctx context.Context, instanceID string, instanceDetails domain.FetchInstanceDetails) (domain.GetInstanceDetailsSpec, error) {
-	err := apiresponses.NewFailureResponse(errors.New("GetInstance Not Implemented"), 404, "")
-	return domain.GetInstanceDetailsSpec{}, err
+	requestID := uuid.New()
+	ctx = brokercontext.New(ctx, "get-instance", requestID, b.offering().Name, instanceID)
+	logger := b.loggerFactory.NewWithContext(ctx)
+
+	manifest, found, err := b.boshClient.GetDeployment(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return domain.GetInstanceDetailsSpec{}, b.processError(NewGenericError(ctx, fmt.Errorf("could not get manifest: %s", err)), logger)
+	}
+	if !found {
+		return domain.GetInstanceDetailsSpec{}, b.processError(NewDisplayableError(
+			apiresponses.ErrInstanceNotFound,
+			fmt.Errorf("fetching instance %s: deployment not found", instanceID),
+		), logger)
+	}
+
+	// The instance is described by the metadata recorded for it, so it is
+	// returned even while a BOSH task is changing the deployment.
+	metadata, _, err := b.getInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		return domain.GetInstanceDetailsSpec{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+
+	planID := metadata.PlanID
+	if planID == "" {
+		planID = instanceDetails.PlanID
+	}
+	if planID == "" {
+		return domain.GetInstanceDetailsSpec{}, b.processError(NewGenericError(
+			ctx,
+			fmt.Errorf("could not determine plan for instance %s", instanceID),
+		), logger)
+	}
+
+	serviceOffering := b.offering()
+	var dashboardURL string
+	if plan, found := serviceOffering.FindPlanByID(planID); found {
+		abridgedPlan := plan.AdapterPlan(serviceOffering.GlobalProperties)
+		dashboardURL, err = b.adapterClient.GenerateDashboardUrl(ctx, instanceID, abridgedPlan, manifest, logger)
+		if err != nil {
+			// Dashboard url optional
+			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
+				return domain.GetInstanceDetailsSpec{}, b.processError(adapterToAPIError(ctx, err), logger)
+			}
+		}
+	} else {
+		logger.Printf("plan %s of instance %s is not in the catalog, skipping dashboard url\n", planID, instanceID)
+	}
+
+	return domain.GetInstanceDetailsSpec{
+		ServiceID:    serviceOffering.ID,
+		PlanID:       planID,
+		DashboardURL: dashboardURL,
+		Parameters:   metadata.Parameters,
+	}, nil
 }
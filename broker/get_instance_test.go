@@ -2,6 +2,7 @@ package broker_test
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
@@ -9,14 +10,152 @@ import (
 	"code.cloudfoundry.org/lager/v3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 var _ = Describe("GetInstance", func() {
-	It("returns an appropriate error", func() {
-		_, err := b.GetInstance(context.Background(), "some-instance-id", domain.FetchInstanceDetails{PlanID: "test plan", ServiceID: "test service"})
+	var (
+		instanceID = "some-instance-id"
+		details    domain.FetchInstanceDetails
+		manifest   = []byte("name: service-instance_some-instance-id")
+	)
+
+	BeforeEach(func() {
+		details = domain.FetchInstanceDetails{}
+		boshClient.GetDeploymentReturns(manifest, true, nil)
+		boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+			{Type: "some-adapter-config", Name: "service-instance_some-instance-id", Content: "foo: bar"},
+			{
+				Type:    broker.InstanceMetadataConfigType,
+				Name:    "service-instance_some-instance-id",
+				Content: `{"plan_id":"some-plan-id","parameters":{"foo":"bar"}}`,
+			},
+		}, nil)
+		serviceAdapter.GenerateDashboardUrlReturns("https://dashboard.example.com", nil)
+	})
+
+	JustBeforeEach(func() {
+		b = createDefaultBroker()
+	})
+
+	It("returns the instance reconstructed from the deployment and its metadata", func() {
+		spec, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(spec).To(Equal(domain.GetInstanceDetailsSpec{
+			ServiceID:    serviceOfferingID,
+			PlanID:       existingPlanID,
+			DashboardURL: "https://dashboard.example.com",
+			Parameters:   map[string]interface{}{"foo": "bar"},
+		}))
+
+		Expect(boshClient.GetDeploymentCallCount()).To(Equal(1))
+		_, deploymentName, _ := boshClient.GetDeploymentArgsForCall(0)
+		Expect(deploymentName).To(Equal("service-instance_some-instance-id"))
+
+		Expect(serviceAdapter.GenerateDashboardUrlCallCount()).To(Equal(1))
+		_, actualInstanceID, actualPlan, actualManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
+		Expect(actualInstanceID).To(Equal(instanceID))
+		Expect(actualPlan).To(Equal(existingPlan.AdapterPlan(serviceCatalog.GlobalProperties)))
+		Expect(actualManifest).To(Equal(manifest))
+	})
+
+	It("falls back to the plan in the request when no metadata has been stored", func() {
+		boshClient.GetConfigsReturns(nil, nil)
+		details.PlanID = secondPlanID
+
+		spec, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.PlanID).To(Equal(secondPlanID))
+		Expect(spec.Parameters).To(BeNil())
+	})
+
+	It("does not read the metadata when bosh configs are disabled", func() {
+		brokerConfig.DisableBoshConfigs = true
+		b = createDefaultBroker()
+		details.PlanID = secondPlanID
+
+		spec, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.PlanID).To(Equal(secondPlanID))
+		Expect(boshClient.GetConfigsCallCount()).To(Equal(0))
+	})
+
+	It("omits the dashboard url when the adapter does not implement it", func() {
+		serviceAdapter.GenerateDashboardUrlReturns("", serviceadapter.NewNotImplementedError("not implemented"))
+
+		spec, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.DashboardURL).To(BeEmpty())
+	})
+
+	It("returns an error when the adapter fails to generate the dashboard url", func() {
+		serviceAdapter.GenerateDashboardUrlReturns("", errors.New("oops"))
+
+		_, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns a 404 when the deployment does not exist", func() {
+		boshClient.GetDeploymentReturns(nil, false, nil)
+
+		_, err := b.GetInstance(context.Background(), instanceID, details)
 		fresp, ok := err.(*apiresponses.FailureResponse)
 		Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
 		logger := lager.NewLogger("test")
 		Expect(fresp.ValidatedStatusCode(slog.New(lager.NewHandler(logger)))).To(Equal(404))
 	})
+
+	It("returns the instance from its metadata while a bosh task is in progress", func() {
+		boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{{ID: 42, State: boshdirector.TaskProcessing}}, nil)
+
+		spec, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.PlanID).To(Equal(existingPlanID))
+		Expect(spec.Parameters).To(Equal(map[string]interface{}{"foo": "bar"}))
+	})
+
+	It("returns an error when the deployment cannot be fetched", func() {
+		boshClient.GetDeploymentReturns(nil, false, errors.New("director unreachable"))
+
+		_, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).To(MatchError(ContainSubstring("There was a problem completing your request")))
+	})
+
+	It("returns an error when the plan cannot be determined", func() {
+		boshClient.GetConfigsReturns(nil, nil)
+
+		_, err := b.GetInstance(context.Background(), instanceID, details)
+		Expect(err).To(HaveOccurred())
+		Expect(logBuffer.String()).To(ContainSubstring("could not determine plan for instance some-instance-id"))
+	})
+
+	Describe("InstancePlanID", func() {
+		It("returns the plan recorded in the instance metadata", func() {
+			planID, err := b.InstancePlanID(context.Background(), instanceID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(planID).To(Equal(existingPlanID))
+
+			_, configName, _ := boshClient.GetConfigsArgsForCall(0)
+			Expect(configName).To(Equal("service-instance_some-instance-id"))
+		})
+
+		It("returns no plan when no metadata has been stored", func() {
+			boshClient.GetConfigsReturns(nil, nil)
+
+			planID, err := b.InstancePlanID(context.Background(), instanceID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(planID).To(BeEmpty())
+		})
+
+		It("returns an error when the metadata cannot be read", func() {
+			boshClient.GetConfigsReturns(nil, errors.New("director unreachable"))
+
+			_, err := b.InstancePlanID(context.Background(), instanceID)
+			Expect(err).To(MatchError("director unreachable"))
+		})
+	})
 })
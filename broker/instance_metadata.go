@@ -0,0 +1,280 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// InstanceMetadataConfigType is the BOSH config type under which the broker
+// records what it knows about a service instance. The config is named after the
+// deployment. When the instance is deleted, the config is kept, marked as
+// deleted, for deletedInstanceRetention, so that the operations run on the
+// instance can still be audited; the broker treats the instance as gone.
+const InstanceMetadataConfigType = "odb-instance-metadata"
+
+// maxRecordedOperations bounds the operations kept in the instance metadata,
+// so that the config stays small for long-lived instances.
+const maxRecordedOperations = 50
+
+// deletedInstanceRetention is how long the metadata, and so the operation
+// history, of a deleted instance is kept.
+const deletedInstanceRetention = 30 * 24 * time.Hour
+
+type InstanceMetadata struct {
+	PlanID     string                 `json:"plan_id"`
+	OrgGUID    string                 `json:"organization_guid,omitempty"`
+	SpaceGUID  string                 `json:"space_guid,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Labels     map[string]string      `json:"labels,omitempty"`
+	Operations []OperationRecord      `json:"operations,omitempty"`
+	CreatedAt  *time.Time             `json:"created_at,omitempty"`
+	DeletedAt  *time.Time             `json:"deleted_at,omitempty"`
+}
+
+// getInstanceMetadata returns the metadata of an instance that exists. The
+// metadata of a deleted instance is not found.
+func (b *Broker) getInstanceMetadata(ctx context.Context, instanceID string, logger *log.Logger) (InstanceMetadata, bool, error) {
+	metadata, found, err := b.getRetainedInstanceMetadata(ctx, instanceID, logger)
+	if err != nil || !found || metadata.DeletedAt != nil {
+		return InstanceMetadata{}, false, err
+	}
+	return metadata, true, nil
+}
+
+// getRetainedInstanceMetadata returns the metadata of an instance, including
+// the metadata retained after the instance was deleted.
+func (b *Broker) getRetainedInstanceMetadata(ctx context.Context, instanceID string, logger *log.Logger) (InstanceMetadata, bool, error) {
+	if b.DisableBoshConfigs {
+		return InstanceMetadata{}, false, nil
+	}
+
+	configs, err := b.boshClient.GetConfigs(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return InstanceMetadata{}, false, err
+	}
+
+	for _, c := range configs {
+		if c.Type != InstanceMetadataConfigType {
+			continue
+		}
+		var metadata InstanceMetadata
+		if err := json.Unmarshal([]byte(c.Content), &metadata); err != nil {
+			return InstanceMetadata{}, false, fmt.Errorf("error parsing instance metadata for %s: %s", instanceID, err)
+		}
+		return metadata, true, nil
+	}
+
+	return InstanceMetadata{}, false, nil
+}
+
+// instanceMetadataByDeployment returns the metadata of the instances that
+// exist by deployment name, read with a single BOSH call. Metadata that cannot
+// be parsed is logged and left out.
+func (b *Broker) instanceMetadataByDeployment(ctx context.Context, logger *log.Logger) (map[string]InstanceMetadata, error) {
+	metadataByDeployment := map[string]InstanceMetadata{}
+	if b.DisableBoshConfigs {
+		return metadataByDeployment, nil
+	}
+
+	configs, err := b.boshClient.GetConfigsOfType(ctx, InstanceMetadataConfigType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range configs {
+		var metadata InstanceMetadata
+		if err := json.Unmarshal([]byte(c.Content), &metadata); err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.WarnLevel).Printf("ignoring instance metadata of %s that cannot be parsed: %s\n", c.Name, err)
+			continue
+		}
+		if metadata.DeletedAt == nil {
+			metadataByDeployment[c.Name] = metadata
+		}
+	}
+	return metadataByDeployment, nil
+}
+
+// InstancePlanID returns the plan recorded in the metadata of an instance, or
+// an empty string when there is no metadata for it. The plan of a deleted
+// instance is returned while its metadata is retained.
+func (b *Broker) InstancePlanID(ctx context.Context, instanceID string) (string, error) {
+	metadata, _, err := b.getRetainedInstanceMetadata(ctx, instanceID, b.loggerFactory.NewWithContext(ctx))
+	return metadata.PlanID, err
+}
+
+func (b *Broker) saveInstanceMetadata(ctx context.Context, instanceID string, metadata InstanceMetadata, logger *log.Logger) error {
+	if b.DisableBoshConfigs {
+		return nil
+	}
+
+	content, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	return b.boshClient.UpdateConfig(ctx, InstanceMetadataConfigType, deploymentName(instanceID), content, logger)
+}
+
+// recordInstanceMetadata stores the metadata of a successful create or update.
+// Failures are logged rather than returned, as the deployment has already been
+// submitted by the time this is called.
+func (b *Broker) recordInstanceMetadata(ctx context.Context, instanceID string, metadata InstanceMetadata, logger *log.Logger) {
+	if err := b.saveInstanceMetadata(ctx, instanceID, metadata, logger); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error storing instance metadata for %s: %s\n", instanceID, err)
+	}
+}
+
+// recordOperation adds an operation to the stored instance metadata. Like
+// recordInstanceMetadata, it only logs failures. The recorded plan is kept
+// when planID is empty, as for deletes of instances whose plan is unknown.
+func (b *Broker) recordOperation(ctx context.Context, instanceID, planID string, record OperationRecord, logger *log.Logger) {
+	metadata, _, err := b.getInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error reading instance metadata for %s: %s\n", instanceID, err)
+		return
+	}
+	b.recordOperationIn(ctx, instanceID, metadata, planID, record, logger)
+}
+
+// recordOperationIn is recordOperation for callers that have already read the
+// metadata of the instance in the same request, so that it is not read again.
+func (b *Broker) recordOperationIn(ctx context.Context, instanceID string, metadata InstanceMetadata, planID string, record OperationRecord, logger *log.Logger) {
+	if planID != "" {
+		metadata = metadata.withPlan(planID)
+	}
+	b.recordInstanceMetadata(ctx, instanceID, metadata.withOperation(record), logger)
+}
+
+// deleteInstanceConfigs removes the configs of a deleted instance, except for
+// its metadata, which is marked as deleted and retained for auditing. The
+// metadata of instances deleted more than deletedInstanceRetention ago is
+// removed at the same time.
+func (b *Broker) deleteInstanceConfigs(ctx context.Context, instanceID string, logger *log.Logger) error {
+	metadata, found, err := b.getRetainedInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error reading instance metadata for %s, it is not retained: %s\n", instanceID, err)
+	}
+
+	if err := b.boshClient.DeleteConfigs(ctx, deploymentName(instanceID), logger); err != nil {
+		return err
+	}
+
+	if found && metadata.DeletedAt == nil {
+		deletedAt := time.Now().UTC()
+		metadata.DeletedAt = &deletedAt
+		b.recordInstanceMetadata(ctx, instanceID, metadata, logger)
+	}
+	b.removeExpiredInstanceMetadata(ctx, logger)
+	return nil
+}
+
+// removeExpiredInstanceMetadata removes the retained metadata of the instances
+// deleted more than deletedInstanceRetention ago. Failures are only logged, as
+// the metadata is removed on a later delete.
+func (b *Broker) removeExpiredInstanceMetadata(ctx context.Context, logger *log.Logger) {
+	configs, err := b.boshClient.GetConfigsOfType(ctx, InstanceMetadataConfigType, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error listing instance metadata: %s\n", err)
+		return
+	}
+
+	expiry := time.Now().Add(-deletedInstanceRetention)
+	for _, c := range configs {
+		var metadata InstanceMetadata
+		if err := json.Unmarshal([]byte(c.Content), &metadata); err != nil || metadata.DeletedAt == nil || metadata.DeletedAt.After(expiry) {
+			continue
+		}
+		if _, err := b.boshClient.DeleteConfig(ctx, InstanceMetadataConfigType, c.Name, logger); err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error removing the instance metadata of %s: %s\n", c.Name, err)
+		}
+	}
+}
+
+// recordLabeledOperation is recordOperation for the operations that
+// regenerate the manifest, and so the labels, of the instance.
+func (b *Broker) recordLabeledOperation(ctx context.Context, instanceID, planID string, labels map[string]any, record OperationRecord, logger *log.Logger) {
+	metadata, _, err := b.getInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error reading instance metadata for %s: %s\n", instanceID, err)
+		return
+	}
+	b.recordInstanceMetadata(ctx, instanceID, metadata.withPlan(planID).withLabels(labels).withOperation(record), logger)
+}
+
+func (m InstanceMetadata) withPlan(planID string) InstanceMetadata {
+	m.PlanID = planID
+	return m
+}
+
+// withOrgAndSpace records where the instance was created, from the org and
+// space GUIDs of a provision request.
+func (m InstanceMetadata) withOrgAndSpace(requestParams map[string]interface{}) InstanceMetadata {
+	m.OrgGUID, _ = requestParams["organization_guid"].(string)
+	m.SpaceGUID, _ = requestParams["space_guid"].(string)
+	return m
+}
+
+// withLabels records the labels the service adapter generated for the
+// instance. The labels recorded so far are kept when it generated none.
+func (m InstanceMetadata) withLabels(labels map[string]any) InstanceMetadata {
+	if len(labels) == 0 {
+		return m
+	}
+
+	m.Labels = map[string]string{}
+	for key, value := range labels {
+		m.Labels[key] = fmt.Sprint(value)
+	}
+	return m
+}
+
+// withOperation records an operation. The creation time of the instance is
+// recorded with its create operation, and kept when the create operation is
+// dropped from the operations.
+func (m InstanceMetadata) withOperation(record OperationRecord) InstanceMetadata {
+	operations := append(append([]OperationRecord{}, m.Operations...), record)
+	if m.CreatedAt == nil {
+		for _, operation := range operations {
+			if operation.Type == OperationTypeCreate {
+				createdAt := operation.StartedAt
+				m.CreatedAt = &createdAt
+				break
+			}
+		}
+	}
+	if len(operations) > maxRecordedOperations {
+		operations = operations[len(operations)-maxRecordedOperations:]
+	}
+	m.Operations = operations
+	return m
+}
+
+// mergeParameters overlays the parameters of an update request on the ones
+// recorded so far, mirroring how the platform treats update parameters.
+func (m InstanceMetadata) mergeParameters(requestParams map[string]interface{}) InstanceMetadata {
+	params, ok := requestParams["parameters"].(map[string]interface{})
+	if !ok || len(params) == 0 {
+		return m
+	}
+
+	merged := map[string]interface{}{}
+	for k, v := range m.Parameters {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	m.Parameters = merged
+	return m
+}
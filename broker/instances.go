@@ -7,16 +7,125 @@
 package broker
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
 
-func (b *Broker) Instances(filter map[string]string, logger *log.Logger) ([]service.Instance, error) {
-	instances, err := b.instanceLister.Instances(filter)
+// Instances lists the instances selected by the filter. The filters that are
+// not service.InstanceFilter ones are left to the instance lister. The
+// deployment filters look up the metadata and the deployments of all the
+// instances at once, rather than for every listed instance.
+func (b *Broker) Instances(ctx context.Context, filter map[string]string, logger *log.Logger) ([]service.Instance, error) {
+	instanceFilter, err := service.ParseInstanceFilter(filter)
 	if err != nil {
 		return nil, b.processError(err, logger)
 	}
 
-	return instances, nil
+	instances, err := b.instanceLister.Instances(instanceFilter.ListerFilter)
+	if err != nil {
+		return nil, b.processError(err, logger)
+	}
+
+	var deployments instanceDeployments
+	if instanceFilter.FiltersDeployments() {
+		deployments, err = b.instanceDeployments(ctx, instanceFilter, logger)
+		if err != nil {
+			return nil, b.processError(fmt.Errorf("error filtering instances: %s", err), logger)
+		}
+	}
+
+	selected := []service.Instance{}
+	now := time.Now().UTC()
+	for _, instance := range instances {
+		if !instanceFilter.Selects(instance) {
+			continue
+		}
+		if instanceFilter.FiltersDeployments() {
+			deployment, found, err := deployments.deploymentInfo(instance.GUID, instanceFilter, logger)
+			if err != nil {
+				return nil, b.processError(fmt.Errorf("error filtering instance %s: %s", instance.GUID, err), logger)
+			}
+			if !found || !instanceFilter.SelectsDeployment(deployment, now) {
+				continue
+			}
+		}
+		selected = append(selected, instance)
+	}
+
+	return selected, nil
+}
+
+// instanceDeployments holds what the deployment filters need, by deployment
+// name. Only what the filter needs is looked up: the metadata of the
+// instances for the labels and the age, the deployments for the releases and
+// the stemcells.
+type instanceDeployments struct {
+	metadata    map[string]InstanceMetadata
+	deployments map[string]boshdirector.Deployment
+}
+
+func (b *Broker) instanceDeployments(ctx context.Context, instanceFilter service.InstanceFilter, logger *log.Logger) (instanceDeployments, error) {
+	var deployments instanceDeployments
+
+	if len(instanceFilter.Labels) > 0 || instanceFilter.MinAge > 0 {
+		metadata, err := b.instanceMetadataByDeployment(ctx, logger)
+		if err != nil {
+			return instanceDeployments{}, err
+		}
+		deployments.metadata = metadata
+	}
+
+	if len(instanceFilter.Releases) > 0 || instanceFilter.StemcellVersion != "" {
+		boshDeployments, err := b.boshClient.GetDeployments(ctx, logger)
+		if err != nil {
+			return instanceDeployments{}, err
+		}
+		deployments.deployments = map[string]boshdirector.Deployment{}
+		for _, deployment := range boshDeployments {
+			deployments.deployments[deployment.Name] = deployment
+		}
+	}
+
+	return deployments, nil
+}
+
+// deploymentInfo returns what the deployment filters match an instance
+// against. The labels are the ones the service adapter returned when the
+// instance was last deployed. The age of an instance comes from the creation
+// time in its metadata, and it is an error for it to be unknown, rather than
+// the instance being silently left out.
+func (d instanceDeployments) deploymentInfo(instanceID string, instanceFilter service.InstanceFilter, logger *log.Logger) (service.DeploymentInfo, bool, error) {
+	var deployment service.DeploymentInfo
+
+	if len(instanceFilter.Releases) > 0 || instanceFilter.StemcellVersion != "" {
+		boshDeployment, found := d.deployments[deploymentName(instanceID)]
+		if !found {
+			return service.DeploymentInfo{}, false, nil
+		}
+		deployment.Releases = boshDeployment.Releases
+		deployment.StemcellVersions = boshDeployment.StemcellVersions
+	}
+
+	metadata := d.metadata[deploymentName(instanceID)]
+	if len(instanceFilter.Labels) > 0 {
+		if len(metadata.Labels) == 0 {
+			logger.Printf("no labels recorded for instance %s, they are recorded when it is next deployed\n", instanceID)
+		}
+		deployment.Labels = metadata.Labels
+	}
+
+	if instanceFilter.MinAge > 0 {
+		if metadata.CreatedAt == nil {
+			return service.DeploymentInfo{}, false, errors.New("the age of the instance is unknown, as no creation time is recorded for it")
+		}
+		deployment.CreatedAt = *metadata.CreatedAt
+	}
+
+	return deployment, true, nil
 }
@@ -7,12 +7,16 @@
 package broker_test
 
 import (
+	"context"
 	"errors"
 	"log"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
 
@@ -34,7 +38,7 @@ var _ = Describe("Instances", func() {
 				{GUID: "blue", PlanUniqueID: "colour-plan", SpaceGUID: "space_id"},
 			}, nil)
 
-			Expect(b.Instances(nil, logger)).To(ConsistOf(
+			Expect(b.Instances(context.Background(), nil, logger)).To(ConsistOf(
 				service.Instance{GUID: "red", PlanUniqueID: "colour-plan", SpaceGUID: "space_id"},
 				service.Instance{GUID: "green", PlanUniqueID: "colour-plan", SpaceGUID: "space_id"},
 				service.Instance{GUID: "blue", PlanUniqueID: "colour-plan", SpaceGUID: "space_id"},
@@ -44,7 +48,7 @@ var _ = Describe("Instances", func() {
 		It("returns an error when the list of instances cannot be retrieved", func() {
 			fakeInstanceLister.InstancesReturns(nil, errors.New("an error occurred"))
 
-			_, err := b.Instances(nil, logger)
+			_, err := b.Instances(context.Background(), nil, logger)
 			Expect(err).To(MatchError(ContainSubstring("an error occurred")))
 		})
 	})
@@ -64,7 +68,7 @@ var _ = Describe("Instances", func() {
 		It("returns a list of instance IDs", func() {
 			b = createDefaultBroker()
 
-			filteredInstances, err := b.Instances(map[string]string{"foo": "bar"}, logger)
+			filteredInstances, err := b.Instances(context.Background(), map[string]string{"foo": "bar"}, logger)
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(filteredInstances).To(ConsistOf(
@@ -81,9 +85,116 @@ var _ = Describe("Instances", func() {
 
 			It("returns an error", func() {
 				b = createDefaultBroker()
-				_, err := b.Instances(map[string]string{"foo": "bar"}, logger)
+				_, err := b.Instances(context.Background(), map[string]string{"foo": "bar"}, logger)
 				Expect(err).To(MatchError(ContainSubstring("an error occurred")))
 			})
 		})
 	})
+
+	Describe("selecting instances", func() {
+		BeforeEach(func() {
+			fakeInstanceLister.InstancesReturns([]service.Instance{
+				{GUID: "red", PlanUniqueID: "small"},
+				{GUID: "green", PlanUniqueID: "large"},
+				{GUID: "blue", PlanUniqueID: "large"},
+			}, nil)
+			b = createDefaultBroker()
+		})
+
+		It("selects the instances by plan and guid, leaving the other filters to the instance lister", func() {
+			instances, err := b.Instances(context.Background(), map[string]string{
+				"plan_ids":               "large",
+				"exclude_instance_guids": "blue",
+				"cf_org":                 "org",
+				"cf_space":               "space",
+			}, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(Equal([]service.Instance{{GUID: "green", PlanUniqueID: "large"}}))
+			Expect(fakeInstanceLister.InstancesArgsForCall(0)).To(Equal(map[string]string{"cf_org": "org", "cf_space": "space"}))
+			Expect(boshClient.GetDeploymentCallCount()).To(BeZero())
+		})
+
+		It("selects the instances by the labels recorded for them", func() {
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+				{Type: broker.InstanceMetadataConfigType, Name: "service-instance_red", Content: `{"plan_id":"small","labels":{"tier":"silver"}}`},
+				{Type: broker.InstanceMetadataConfigType, Name: "service-instance_green", Content: `{"plan_id":"large","labels":{"tier":"gold"}}`},
+				{Type: broker.InstanceMetadataConfigType, Name: "service-instance_blue", Content: `{"plan_id":"large"}`},
+			}, nil)
+
+			instances, err := b.Instances(context.Background(), map[string]string{"labels": "tier=gold"}, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(Equal([]service.Instance{{GUID: "green", PlanUniqueID: "large"}}))
+			Expect(boshClient.GetConfigsOfTypeCallCount()).To(Equal(1))
+			_, configType, _ := boshClient.GetConfigsOfTypeArgsForCall(0)
+			Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+			Expect(boshClient.GetConfigsCallCount()).To(BeZero())
+			Expect(boshClient.GetDeploymentsCallCount()).To(BeZero())
+		})
+
+		It("selects the instances by the releases and stemcell of their deployments", func() {
+			boshClient.GetDeploymentsReturns([]boshdirector.Deployment{
+				{Name: "service-instance_red", Releases: map[string]string{"redis": "1.2.3"}, StemcellVersions: []string{"1.1"}},
+				{Name: "service-instance_green", Releases: map[string]string{"redis": "1.2.3"}, StemcellVersions: []string{"1.2"}},
+			}, nil)
+
+			instances, err := b.Instances(context.Background(), map[string]string{"releases": "redis/1.2.3", "stemcell_version": "1.1"}, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(Equal([]service.Instance{{GUID: "red", PlanUniqueID: "small"}}))
+			Expect(boshClient.GetDeploymentsCallCount()).To(Equal(1))
+			Expect(boshClient.GetDeploymentCallCount()).To(BeZero())
+			Expect(boshClient.GetConfigsOfTypeCallCount()).To(BeZero())
+		})
+
+		It("selects the instances created long enough ago", func() {
+			longAgo := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+			recently := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+				{Type: broker.InstanceMetadataConfigType, Name: "service-instance_green", Content: `{"plan_id":"large","created_at":"` + longAgo + `"}`},
+				{Type: broker.InstanceMetadataConfigType, Name: "service-instance_blue", Content: `{"plan_id":"large","created_at":"` + recently + `"}`},
+			}, nil)
+
+			instances, err := b.Instances(context.Background(), map[string]string{"min_age": "720h", "plan_ids": "large"}, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(Equal([]service.Instance{{GUID: "green", PlanUniqueID: "large"}}))
+			Expect(boshClient.GetEventsCallCount()).To(BeZero())
+		})
+
+		It("fails when the age of a selected instance is unknown", func() {
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+				{Type: broker.InstanceMetadataConfigType, Name: "service-instance_green", Content: `{"plan_id":"large","created_at":"2020-01-01T00:00:00Z"}`},
+				{Type: broker.InstanceMetadataConfigType, Name: "service-instance_blue", Content: `{"plan_id":"large"}`},
+			}, nil)
+
+			_, err := b.Instances(context.Background(), map[string]string{"min_age": "720h", "plan_ids": "large"}, logger)
+
+			Expect(err).To(MatchError("error filtering instance blue: the age of the instance is unknown, as no creation time is recorded for it"))
+		})
+
+		It("returns an error when a filter is invalid", func() {
+			_, err := b.Instances(context.Background(), map[string]string{"min_age": "old"}, logger)
+
+			Expect(err).To(BeAssignableToTypeOf(service.InvalidFilterError{}))
+			Expect(fakeInstanceLister.InstancesCallCount()).To(BeZero())
+		})
+
+		It("returns an error when the deployments cannot be looked up", func() {
+			boshClient.GetDeploymentsReturns(nil, errors.New("director unreachable"))
+
+			_, err := b.Instances(context.Background(), map[string]string{"stemcell_version": "1.1"}, logger)
+
+			Expect(err).To(MatchError("error filtering instances: director unreachable"))
+		})
+
+		It("returns an error when the instance metadata cannot be looked up", func() {
+			boshClient.GetConfigsOfTypeReturns(nil, errors.New("director unreachable"))
+
+			_, err := b.Instances(context.Background(), map[string]string{"labels": "tier=gold"}, logger)
+
+			Expect(err).To(MatchError("error filtering instances: director unreachable"))
+		})
+	})
 })
@@ -0,0 +1,55 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// IteratorCheckpointConfigType is the BOSH config type under which the errands
+// that process every instance, such as upgrade-all-service-instances, keep the
+// progress of a run, so that it can be resumed from another errand VM. The
+// config is named after the errand. The broker stores the checkpoints without
+// interpreting them.
+const IteratorCheckpointConfigType = "odb-iterator-checkpoint"
+
+var errCheckpointsDisabled = errors.New("checkpoints are not available when BOSH configs are disabled")
+
+// IteratorCheckpoint returns the checkpoint stored under name, if any.
+func (b *Broker) IteratorCheckpoint(ctx context.Context, name string, logger *log.Logger) ([]byte, bool, error) {
+	if b.DisableBoshConfigs {
+		return nil, false, errCheckpointsDisabled
+	}
+
+	configs, err := b.boshClient.GetConfigs(ctx, name, logger)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, c := range configs {
+		if c.Type == IteratorCheckpointConfigType {
+			return []byte(c.Content), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (b *Broker) SaveIteratorCheckpoint(ctx context.Context, name string, checkpoint []byte, logger *log.Logger) error {
+	if b.DisableBoshConfigs {
+		return errCheckpointsDisabled
+	}
+	return b.boshClient.UpdateConfig(ctx, IteratorCheckpointConfigType, name, checkpoint, logger)
+}
+
+func (b *Broker) ClearIteratorCheckpoint(ctx context.Context, name string, logger *log.Logger) error {
+	if b.DisableBoshConfigs {
+		return errCheckpointsDisabled
+	}
+	_, err := b.boshClient.DeleteConfig(ctx, IteratorCheckpointConfigType, name, logger)
+	return err
+}
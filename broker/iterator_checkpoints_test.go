@@ -0,0 +1,71 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("Iterator checkpoints", func() {
+	JustBeforeEach(func() {
+		b = createDefaultBroker()
+	})
+
+	It("returns the checkpoint stored in a BOSH config", func() {
+		boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+			{Type: "some-other-type", Name: "upgrade-all-bosh", Content: "other"},
+			{Type: broker.IteratorCheckpointConfigType, Name: "upgrade-all-bosh", Content: `{"canaries_completed":true}`},
+		}, nil)
+
+		checkpoint, found, err := b.IteratorCheckpoint(context.Background(), "upgrade-all-bosh", loggerFactory.New())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(checkpoint).To(MatchJSON(`{"canaries_completed":true}`))
+		_, name, _ := boshClient.GetConfigsArgsForCall(0)
+		Expect(name).To(Equal("upgrade-all-bosh"))
+	})
+
+	It("reports that there is no checkpoint", func() {
+		boshClient.GetConfigsReturns(nil, nil)
+
+		_, found, err := b.IteratorCheckpoint(context.Background(), "upgrade-all-bosh", loggerFactory.New())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("stores and clears the checkpoint", func() {
+		Expect(b.SaveIteratorCheckpoint(context.Background(), "upgrade-all-bosh", []byte("{}"), loggerFactory.New())).To(Succeed())
+		_, configType, name, content, _ := boshClient.UpdateConfigArgsForCall(0)
+		Expect(configType).To(Equal(broker.IteratorCheckpointConfigType))
+		Expect(name).To(Equal("upgrade-all-bosh"))
+		Expect(content).To(Equal([]byte("{}")))
+
+		Expect(b.ClearIteratorCheckpoint(context.Background(), "upgrade-all-bosh", loggerFactory.New())).To(Succeed())
+		_, configType, name, _ = boshClient.DeleteConfigArgsForCall(0)
+		Expect(configType).To(Equal(broker.IteratorCheckpointConfigType))
+		Expect(name).To(Equal("upgrade-all-bosh"))
+	})
+
+	When("BOSH configs are disabled", func() {
+		BeforeEach(func() {
+			brokerConfig.DisableBoshConfigs = true
+		})
+
+		It("fails", func() {
+			_, _, err := b.IteratorCheckpoint(context.Background(), "upgrade-all-bosh", loggerFactory.New())
+			Expect(err).To(MatchError(ContainSubstring("checkpoints are not available")))
+			Expect(boshClient.GetConfigsCallCount()).To(BeZero())
+		})
+	})
+})
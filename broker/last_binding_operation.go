@@ -2,13 +2,37 @@ package broker
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"github.com/pborman/uuid"
+
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 )
 
 func (b *Broker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details domain.PollDetails) (domain.LastOperation, error) {
-	err := apiresponses.NewFailureResponse(errors.New("LastBindingOperation Not Implemented"), 404, "")
-	return domain.LastOperation{}, err
+	requestID := uuid.New()
+	if len(brokercontext.GetReqID(ctx)) > 0 {
+		requestID = brokercontext.GetReqID(ctx)
+	}
+
+	ctx = brokercontext.New(ctx, string(OperationTypeBind), requestID, b.offering().Name, instanceID)
+	logger := b.loggerFactory.NewWithContext(ctx)
+
+	record, found, err := b.getBinding(ctx, instanceID, bindingID, logger)
+	if err != nil {
+		return domain.LastOperation{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+	if !found {
+		return domain.LastOperation{}, b.processError(NewDisplayableError(
+			apiresponses.ErrBindingNotFound,
+			fmt.Errorf("polling binding %s for instance %s: binding not found", bindingID, instanceID),
+		), logger)
+	}
+
+	return domain.LastOperation{
+		State:       record.State,
+		Description: record.Description,
+	}, nil
 }
@@ -12,7 +12,11 @@ import (
 )
 
 var _ = Describe("LastBindingOperation", func() {
-	It("returns an appropriate error", func() {
+	BeforeEach(func() {
+		b = createDefaultBroker()
+	})
+
+	It("returns a not found error for an unknown binding", func() {
 		_, err := b.LastBindingOperation(context.Background(), "instanceID", "bID", domain.PollDetails{})
 		fresp, ok := err.(*apiresponses.FailureResponse)
 		Expect(ok).To(BeTrue(), "err wasn't a FailureResponse")
@@ -18,32 +18,39 @@ import (
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 )
 
 var descriptions = map[domain.LastOperationState]map[OperationType]string{
 	domain.InProgress: {
-		OperationTypeCreate:      "Instance provisioning in progress",
-		OperationTypeUpdate:      "Instance update in progress",
-		OperationTypeUpgrade:     "Instance upgrade in progress",
-		OperationTypeDelete:      "Instance deletion in progress",
-		OperationTypeForceDelete: "Instance forced deletion in progress",
-		OperationTypeRecreate:    "Instance recreate in progress",
+		OperationTypeCreate:        "Instance provisioning in progress",
+		OperationTypeUpdate:        "Instance update in progress",
+		OperationTypeUpgrade:       "Instance upgrade in progress",
+		OperationTypeDelete:        "Instance deletion in progress",
+		OperationTypeForceDelete:   "Instance forced deletion in progress",
+		OperationTypeRecreate:      "Instance recreate in progress",
+		OperationTypeRollback:      "Instance rollback in progress",
+		OperationTypeRotateSecrets: "Instance secret rotation in progress",
 	},
 	domain.Succeeded: {
-		OperationTypeCreate:      "Instance provisioning completed",
-		OperationTypeUpdate:      "Instance update completed",
-		OperationTypeUpgrade:     "Instance upgrade completed",
-		OperationTypeDelete:      "Instance deletion completed",
-		OperationTypeForceDelete: "Instance forced deletion completed",
-		OperationTypeRecreate:    "Instance recreate completed",
+		OperationTypeCreate:        "Instance provisioning completed",
+		OperationTypeUpdate:        "Instance update completed",
+		OperationTypeUpgrade:       "Instance upgrade completed",
+		OperationTypeDelete:        "Instance deletion completed",
+		OperationTypeForceDelete:   "Instance forced deletion completed",
+		OperationTypeRecreate:      "Instance recreate completed",
+		OperationTypeRollback:      "Instance rollback completed",
+		OperationTypeRotateSecrets: "Instance secret rotation completed",
 	},
 	domain.Failed: {
-		OperationTypeCreate:      "Instance provisioning failed",
-		OperationTypeUpdate:      "Instance update failed",
-		OperationTypeUpgrade:     "Failed for bosh task",
-		OperationTypeDelete:      "Instance deletion failed",
-		OperationTypeForceDelete: "Instance forced deletion failed",
-		OperationTypeRecreate:    "Instance recreate failed",
+		OperationTypeCreate:        "Instance provisioning failed",
+		OperationTypeUpdate:        "Instance update failed",
+		OperationTypeUpgrade:       "Failed for bosh task",
+		OperationTypeDelete:        "Instance deletion failed",
+		OperationTypeForceDelete:   "Instance forced deletion failed",
+		OperationTypeRecreate:      "Instance recreate failed",
+		OperationTypeRollback:      "Instance rollback failed",
+		OperationTypeRotateSecrets: "Instance secret rotation failed",
 	},
 }
 
@@ -54,7 +61,7 @@ func (b *Broker) LastOperation(
 ) (domain.LastOperation, error) {
 	operationDataRaw := pollDetails.OperationData
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, "", requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, "", requestID, b.offering().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
 	if operationDataRaw == "" {
@@ -74,11 +81,12 @@ func (b *Broker) LastOperation(
 	}
 
 	ctx = brokercontext.WithBoshTaskID(ctx, operationData.BoshTaskID)
+	logger = b.loggerFactory.NewWithContext(ctx)
 
-	lifeCycleRunner := NewLifeCycleRunner(b.boshClient, b.serviceOffering.Plans)
+	lifeCycleRunner := NewLifeCycleRunner(b.boshClient, b.offering().Plans)
 
 	// if the errand isn't already running, or delete deployment wasn't triggered, GetTask will start it!
-	lastBoshTask, err := lifeCycleRunner.GetTask(deploymentName(instanceID), operationData, logger)
+	lastBoshTask, err := lifeCycleRunner.GetTask(ctx, deploymentName(instanceID), operationData, logger)
 	if err != nil {
 		return domain.LastOperation{}, b.processError(
 			NewGenericError(ctx, fmt.Errorf("error retrieving tasks from bosh, for deployment '%s': %s", deploymentName(instanceID), err)),
@@ -86,17 +94,27 @@ func (b *Broker) LastOperation(
 		)
 	}
 
-	err = b.deleteConfigsAndSecretsAfterDelete(instanceID, operationData.OperationType, lastBoshTask, logger)
+	err = b.deleteConfigsAndSecretsAfterDelete(ctx, instanceID, operationData.OperationType, lastBoshTask, logger)
 	if err != nil {
 		ctx = brokercontext.WithBoshTaskID(ctx, 0)
-		return constructLastOperation(ctx, domain.Failed, lastBoshTask, operationData, b.ExposeOperationalErrors), nil
+		lastOperation := constructLastOperation(ctx, domain.Failed, lastBoshTask, operationData, b.ExposeOperationalErrors)
+		b.notifyOperationFinished(instanceID, pollDetails.PlanID, operationData, lastOperation)
+		return lastOperation, nil
 	}
 
 	ctx = brokercontext.WithBoshTaskID(ctx, lastBoshTask.ID)
+	logger = b.loggerFactory.NewWithContext(ctx)
 
 	taskState := lastOperationState(lastBoshTask, logger)
+	if taskState != domain.InProgress {
+		b.releaseQuotaReservation(ctx, instanceID, operationData, logger)
+	}
+	if taskState == domain.Succeeded {
+		b.recordSnapshotAfterDeploy(ctx, instanceID, operationData, logger)
+	}
 	lastOperation := constructLastOperation(ctx, taskState, lastBoshTask, operationData, b.ExposeOperationalErrors)
 	logLastOperation(instanceID, lastBoshTask, operationData, logger)
+	b.notifyOperationFinished(instanceID, pollDetails.PlanID, operationData, lastOperation)
 
 	b.telemetryLogger.LogInstances(b.instanceLister, "instance", string(operationData.OperationType))
 
@@ -147,6 +165,7 @@ func logLastOperation(instanceID string, boshTask boshdirector.BoshTask, operati
 }
 
 func (b *Broker) deleteConfigsAndSecretsAfterDelete(
+	ctx context.Context,
 	instanceID string,
 	operationType OperationType,
 	lastBoshTask boshdirector.BoshTask,
@@ -155,14 +174,14 @@ func (b *Broker) deleteConfigsAndSecretsAfterDelete(
 	if (operationType == OperationTypeDelete || operationType == OperationTypeForceDelete) &&
 		lastBoshTask.StateType() == boshdirector.TaskComplete {
 		if !b.DisableBoshConfigs {
-			if err := b.boshClient.DeleteConfigs(deploymentName(instanceID), logger); err != nil {
-				logger.Printf("Failed to delete configs for service instance %s: %s\n", instanceID, err.Error())
+			if err := b.deleteInstanceConfigs(ctx, instanceID, logger); err != nil {
+				loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("Failed to delete configs for service instance %s: %s\n", instanceID, err.Error())
 				return err
 			}
 		}
 
-		if err := b.secretManager.DeleteSecretsForInstance(instanceID, logger); err != nil {
-			logger.Printf("Failed to delete credhub secrets for service instance %s. Credhub error: %s\n", instanceID, err.Error())
+		if err := b.secretManager.DeleteSecretsForInstance(ctx, instanceID, logger); err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("Failed to delete credhub secrets for service instance %s. Credhub error: %s\n", instanceID, err.Error())
 			return err
 		}
 	}
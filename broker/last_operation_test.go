@@ -284,7 +284,7 @@ var _ = Describe("LastOperation", func() {
 
 				It("succeeds", func() {
 					Expect(boshClient.GetTaskCallCount()).To(Equal(1))
-					actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
+					_, actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
 					Expect(actualTaskID).To(Equal(taskID))
 					Expect(actualLastOperationError).NotTo(HaveOccurred())
 
@@ -635,7 +635,7 @@ var _ = Describe("LastOperation", func() {
 					Expect(actualLastOperation.Description).To(Equal("Instance deletion completed"))
 
 					Expect(boshClient.GetTaskCallCount()).To(Equal(1))
-					actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
+					_, actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
 					Expect(actualTaskID).To(Equal(taskID))
 
 					By("deleting configs")
@@ -702,13 +702,13 @@ var _ = Describe("LastOperation", func() {
 					Expect(actualLastOperation.Description).To(Equal("Instance deletion completed"))
 
 					Expect(boshClient.GetTaskCallCount()).To(Equal(1))
-					actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
+					_, actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
 					Expect(actualTaskID).To(Equal(taskID))
 
 					By("deleting secrets")
 					Expect(fakeSecretManager.DeleteSecretsForInstanceCallCount()).To(Equal(1), "expected to call secret manager")
 
-					actualInstanceID, _ := fakeSecretManager.DeleteSecretsForInstanceArgsForCall(0)
+					_, actualInstanceID, _ := fakeSecretManager.DeleteSecretsForInstanceArgsForCall(0)
 					Expect(instanceID).To(Equal(actualInstanceID))
 
 					By("logging the deployment, type: delete, state: done")
@@ -931,7 +931,7 @@ var _ = Describe("LastOperation", func() {
 					Expect(actualLastOperation.Description).To(Equal("Instance forced deletion completed"))
 
 					Expect(boshClient.GetTaskCallCount()).To(Equal(1))
-					actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
+					_, actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
 					Expect(actualTaskID).To(Equal(taskID))
 
 					By("deleting configs")
@@ -998,13 +998,13 @@ var _ = Describe("LastOperation", func() {
 					Expect(actualLastOperation.Description).To(Equal("Instance forced deletion completed"))
 
 					Expect(boshClient.GetTaskCallCount()).To(Equal(1))
-					actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
+					_, actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
 					Expect(actualTaskID).To(Equal(taskID))
 
 					By("deleting secrets")
 					Expect(fakeSecretManager.DeleteSecretsForInstanceCallCount()).To(Equal(1), "expected to call secret manager")
 
-					actualInstanceID, _ := fakeSecretManager.DeleteSecretsForInstanceArgsForCall(0)
+					_, actualInstanceID, _ := fakeSecretManager.DeleteSecretsForInstanceArgsForCall(0)
 					Expect(instanceID).To(Equal(actualInstanceID))
 
 					By("logging the deployment, type: delete, state: done")
@@ -1432,4 +1432,57 @@ var _ = Describe("LastOperation", func() {
 			)
 		})
 	})
+
+	Context("releasing quota reservations", func() {
+		const instanceID = "an-instance"
+
+		lastOperation := func(state string, quotaReserved bool) {
+			operationData, err := json.Marshal(broker.OperationData{
+				OperationType: broker.OperationTypeCreate,
+				BoshTaskID:    42,
+				QuotaReserved: quotaReserved,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			boshClient.GetTaskReturns(boshdirector.BoshTask{State: state, ID: 42}, nil)
+			b = createDefaultBroker()
+			_, err = b.LastOperation(context.Background(), instanceID, domain.PollDetails{OperationData: string(operationData)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("releases the reservation once the operation has succeeded", func() {
+			lastOperation(boshdirector.TaskDone, true)
+
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+			_, configType, configName, _ := boshClient.DeleteConfigArgsForCall(0)
+			Expect(configType).To(Equal(broker.QuotaReservationConfigType))
+			Expect(configName).To(Equal("service-instance_" + instanceID))
+		})
+
+		It("releases the reservation once the operation has failed", func() {
+			lastOperation(boshdirector.TaskError, true)
+
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+		})
+
+		It("keeps the reservation while the operation is in progress", func() {
+			lastOperation(boshdirector.TaskProcessing, true)
+
+			Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+		})
+
+		It("does nothing when no quota was reserved", func() {
+			lastOperation(boshdirector.TaskDone, false)
+
+			Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+		})
+
+		It("does not fail the operation when the reservation cannot be released", func() {
+			boshClient.DeleteConfigReturns(false, errors.New("bosh is down"))
+
+			lastOperation(boshdirector.TaskDone, true)
+
+			Expect(logBuffer.String()).To(ContainSubstring("error releasing quota reservation for an-instance: bosh is down"))
+		})
+	})
 })
@@ -0,0 +1,128 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+)
+
+const (
+	LifecycleResourceInstance = "instance"
+	LifecycleResourceBinding  = "binding"
+
+	LifecycleStateStarted   = "started"
+	LifecycleStateSucceeded = "succeeded"
+	LifecycleStateFailed    = "failed"
+)
+
+// LifecycleEvent is a transition of a service instance or binding: an
+// operation that started, succeeded or failed. BoshTaskID is the task that
+// started the operation, so that the events of an operation share it.
+type LifecycleEvent struct {
+	Resource    string
+	Operation   OperationType
+	State       string
+	ServiceID   string
+	PlanID      string
+	InstanceID  string
+	BindingID   string
+	RequestID   string
+	BoshTaskID  int
+	Description string
+	Time        time.Time
+}
+
+type noopEventNotifier struct{}
+
+func (noopEventNotifier) Notify(LifecycleEvent) {}
+
+// SetEventNotifier sets where the broker sends lifecycle events. No events are
+// sent by default.
+func (b *Broker) SetEventNotifier(eventNotifier EventNotifier) {
+	b.eventNotifier = eventNotifier
+}
+
+// notifyOperationStarted is called once an operation on an instance has been
+// submitted to BOSH, with the record stored for it.
+func (b *Broker) notifyOperationStarted(instanceID string, record OperationRecord) {
+	planID := record.PlanIDAfter
+	if planID == "" {
+		planID = record.PlanIDBefore
+	}
+	b.eventNotifier.Notify(LifecycleEvent{
+		Resource:   LifecycleResourceInstance,
+		Operation:  record.Type,
+		State:      LifecycleStateStarted,
+		ServiceID:  b.offering().ID,
+		PlanID:     planID,
+		InstanceID: instanceID,
+		RequestID:  record.RequestID,
+		BoshTaskID: record.BoshTaskID,
+		Time:       record.StartedAt,
+	})
+}
+
+// notifyRequestFailed is called when a request for an operation on an instance
+// fails, which is mostly before the operation is submitted to BOSH, so that the
+// operations that never start are heard of too. The event has no BOSH task.
+func (b *Broker) notifyRequestFailed(ctx context.Context, operationType OperationType, instanceID, planID string, err error) {
+	b.eventNotifier.Notify(LifecycleEvent{
+		Resource:    LifecycleResourceInstance,
+		Operation:   operationType,
+		State:       LifecycleStateFailed,
+		ServiceID:   b.offering().ID,
+		PlanID:      planID,
+		InstanceID:  instanceID,
+		RequestID:   brokercontext.GetReqID(ctx),
+		Description: err.Error(),
+		Time:        time.Now().UTC(),
+	})
+}
+
+// notifyOperationFinished is called when LastOperation finds an operation in a
+// terminal state. It is called each time the operation is polled, so the same
+// event may be sent more than once. The request that polled the operation is
+// not the one that started it, so its ID is left out.
+func (b *Broker) notifyOperationFinished(instanceID, planID string, operationData OperationData, lastOperation domain.LastOperation) {
+	state := LifecycleStateSucceeded
+	switch lastOperation.State {
+	case domain.InProgress:
+		return
+	case domain.Failed:
+		state = LifecycleStateFailed
+	}
+	b.eventNotifier.Notify(LifecycleEvent{
+		Resource:    LifecycleResourceInstance,
+		Operation:   operationData.OperationType,
+		State:       state,
+		ServiceID:   b.offering().ID,
+		PlanID:      planID,
+		InstanceID:  instanceID,
+		BoshTaskID:  operationData.BoshTaskID,
+		Description: lastOperation.Description,
+		Time:        time.Now().UTC(),
+	})
+}
+
+func (b *Broker) notifyBinding(ctx context.Context, operationType OperationType, state, instanceID, bindingID, planID string) {
+	b.eventNotifier.Notify(LifecycleEvent{
+		Resource:   LifecycleResourceBinding,
+		Operation:  operationType,
+		State:      state,
+		ServiceID:  b.offering().ID,
+		PlanID:     planID,
+		InstanceID: instanceID,
+		BindingID:  bindingID,
+		RequestID:  brokercontext.GetReqID(ctx),
+		Time:       time.Now().UTC(),
+	})
+}
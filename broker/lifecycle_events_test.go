@@ -0,0 +1,207 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+)
+
+var _ = Describe("Lifecycle events", func() {
+	const instanceID = "an-instance"
+
+	var fakeEventNotifier *fakes.FakeEventNotifier
+
+	// notifiedEvent returns the event sent, without the time it was sent at
+	notifiedEvent := func(i int) broker.LifecycleEvent {
+		event := fakeEventNotifier.NotifyArgsForCall(i)
+		Expect(event.Time).To(BeTemporally("~", time.Now(), time.Minute))
+		event.Time = time.Time{}
+		return event
+	}
+
+	BeforeEach(func() {
+		fakeEventNotifier = new(fakes.FakeEventNotifier)
+		b = createDefaultBroker()
+		b.SetEventNotifier(fakeEventNotifier)
+	})
+
+	It("notifies that an operation on an instance started", func() {
+		fakeDeployer.RecreateReturns(42, nil)
+		ctx := brokercontext.New(context.Background(), "recreate", "request-id", "a-cool-redis-service", instanceID)
+
+		_, err := b.Recreate(ctx, instanceID, domain.UpdateDetails{PlanID: existingPlanID}, loggerFactory.NewWithRequestID())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+		Expect(notifiedEvent(0)).To(Equal(broker.LifecycleEvent{
+			Resource:   broker.LifecycleResourceInstance,
+			Operation:  broker.OperationTypeRecreate,
+			State:      broker.LifecycleStateStarted,
+			ServiceID:  serviceOfferingID,
+			PlanID:     existingPlanID,
+			InstanceID: instanceID,
+			RequestID:  "request-id",
+			BoshTaskID: 42,
+		}))
+	})
+
+	Describe("requests that fail before the operation runs in BOSH", func() {
+		It("notifies that a create failed", func() {
+			_, err := b.Provision(context.Background(), instanceID, domain.ProvisionDetails{PlanID: "no-such-plan"}, true)
+
+			Expect(err).To(HaveOccurred())
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			event := notifiedEvent(0)
+			Expect(event.RequestID).NotTo(BeEmpty())
+			event.RequestID = ""
+			Expect(event).To(Equal(broker.LifecycleEvent{
+				Resource:    broker.LifecycleResourceInstance,
+				Operation:   broker.OperationTypeCreate,
+				State:       broker.LifecycleStateFailed,
+				ServiceID:   serviceOfferingID,
+				PlanID:      "no-such-plan",
+				InstanceID:  instanceID,
+				Description: err.Error(),
+			}))
+		})
+
+		It("notifies that an update failed", func() {
+			_, err := b.Update(context.Background(), instanceID, domain.UpdateDetails{PlanID: existingPlanID}, false)
+
+			Expect(err).To(HaveOccurred())
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			event := notifiedEvent(0)
+			Expect(event.Operation).To(Equal(broker.OperationTypeUpdate))
+			Expect(event.State).To(Equal(broker.LifecycleStateFailed))
+			Expect(event.Description).To(Equal(err.Error()))
+		})
+
+		It("notifies that a delete failed", func() {
+			boshClient.GetInfoReturns(boshdirector.Info{}, errors.New("director unreachable"))
+
+			_, err := b.Deprovision(context.Background(), instanceID, domain.DeprovisionDetails{PlanID: existingPlanID}, true)
+
+			Expect(err).To(HaveOccurred())
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			event := notifiedEvent(0)
+			Expect(event.Operation).To(Equal(broker.OperationTypeDelete))
+			Expect(event.State).To(Equal(broker.LifecycleStateFailed))
+			Expect(event.BoshTaskID).To(BeZero())
+		})
+
+		It("does not notify the delete of an instance that is already gone", func() {
+			boshClient.GetDeploymentReturns(nil, false, nil)
+
+			_, err := b.Deprovision(context.Background(), instanceID, domain.DeprovisionDetails{PlanID: existingPlanID}, true)
+
+			Expect(err).To(HaveOccurred())
+			Expect(fakeEventNotifier.NotifyCallCount()).To(BeZero())
+		})
+	})
+
+	Describe("LastOperation", func() {
+		lastOperation := func(state string) {
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 43, State: state}, nil)
+			_, err := b.LastOperation(context.Background(), instanceID, domain.PollDetails{
+				PlanID:        existingPlanID,
+				OperationData: `{"BoshTaskID": 42, "OperationType": "update"}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("notifies that an operation succeeded", func() {
+			lastOperation(boshdirector.TaskDone)
+
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			Expect(notifiedEvent(0)).To(Equal(broker.LifecycleEvent{
+				Resource:    broker.LifecycleResourceInstance,
+				Operation:   broker.OperationTypeUpdate,
+				State:       broker.LifecycleStateSucceeded,
+				ServiceID:   serviceOfferingID,
+				PlanID:      existingPlanID,
+				InstanceID:  instanceID,
+				BoshTaskID:  42,
+				Description: "Instance update completed",
+			}))
+		})
+
+		It("notifies that an operation failed", func() {
+			lastOperation(boshdirector.TaskError)
+
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			event := notifiedEvent(0)
+			Expect(event.State).To(Equal(broker.LifecycleStateFailed))
+			Expect(event.Description).To(HavePrefix("Instance update failed"))
+		})
+
+		It("does not notify while the operation is in progress", func() {
+			lastOperation(boshdirector.TaskProcessing)
+
+			Expect(fakeEventNotifier.NotifyCallCount()).To(BeZero())
+		})
+	})
+
+	Describe("bindings", func() {
+		BeforeEach(func() {
+			boshClient.VMsReturns(bosh.BoshVMs{"redis-server": []string{"an.ip"}}, nil)
+			boshClient.GetDeploymentReturns([]byte("name: foo"), true, nil)
+			fakeSecretManager.ResolveManifestSecretsReturns(map[string]string{}, nil)
+		})
+
+		It("notifies that a binding was created", func() {
+			ctx := brokercontext.WithReqID(context.Background(), "request-id")
+			_, err := b.Bind(ctx, instanceID, "binding-id", domain.BindDetails{PlanID: existingPlanID, ServiceID: serviceOfferingID}, false)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			Expect(notifiedEvent(0)).To(Equal(broker.LifecycleEvent{
+				Resource:   broker.LifecycleResourceBinding,
+				Operation:  broker.OperationTypeBind,
+				State:      broker.LifecycleStateSucceeded,
+				ServiceID:  serviceOfferingID,
+				PlanID:     existingPlanID,
+				InstanceID: instanceID,
+				BindingID:  "binding-id",
+				RequestID:  "request-id",
+			}))
+		})
+
+		It("notifies that a binding could not be created", func() {
+			serviceAdapter.CreateBindingReturns(sdk.Binding{}, errors.New("oops"))
+
+			_, err := b.Bind(context.Background(), instanceID, "binding-id", domain.BindDetails{PlanID: existingPlanID, ServiceID: serviceOfferingID}, false)
+
+			Expect(err).To(HaveOccurred())
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			Expect(fakeEventNotifier.NotifyArgsForCall(0).State).To(Equal(broker.LifecycleStateFailed))
+		})
+
+		It("notifies that a binding was deleted", func() {
+			_, err := b.Unbind(context.Background(), instanceID, "binding-id", domain.UnbindDetails{PlanID: existingPlanID, ServiceID: serviceOfferingID}, false)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeEventNotifier.NotifyCallCount()).To(Equal(1))
+			event := notifiedEvent(0)
+			Expect(event.Operation).To(Equal(broker.OperationTypeUnbind))
+			Expect(event.State).To(Equal(broker.LifecycleStateSucceeded))
+			Expect(event.BindingID).To(Equal("binding-id"))
+		})
+	})
+})
@@ -7,6 +7,7 @@
 package broker
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -29,17 +30,17 @@ func NewLifeCycleRunner(
 	}
 }
 
-func (l LifeCycleRunner) GetTask(deploymentName string, operationData OperationData, logger *log.Logger,
+func (l LifeCycleRunner) GetTask(ctx context.Context, deploymentName string, operationData OperationData, logger *log.Logger,
 ) (boshdirector.BoshTask, error) {
 	switch {
 	case operationData.BoshContextID == "":
-		return l.boshClient.GetTask(operationData.BoshTaskID, logger)
+		return l.boshClient.GetTask(ctx, operationData.BoshTaskID, logger)
 	case validPostDeployOpType(operationData.OperationType):
-		return l.processPostDeployment(deploymentName, operationData, logger)
+		return l.processPostDeployment(ctx, deploymentName, operationData, logger)
 	case validPreDeleteOpType(operationData.OperationType):
-		return l.processPreDelete(deploymentName, operationData, logger)
+		return l.processPreDelete(ctx, deploymentName, operationData, logger)
 	default:
-		return l.boshClient.GetTask(operationData.BoshTaskID, logger)
+		return l.boshClient.GetTask(ctx, operationData.BoshTaskID, logger)
 	}
 }
 
@@ -47,7 +48,8 @@ func validPostDeployOpType(op OperationType) bool {
 	return op == OperationTypeCreate ||
 		op == OperationTypeUpdate ||
 		op == OperationTypeRecreate ||
-		op == OperationTypeUpgrade
+		op == OperationTypeUpgrade ||
+		op == OperationTypeRotateSecrets
 }
 
 func validPreDeleteOpType(op OperationType) bool {
@@ -55,11 +57,12 @@ func validPreDeleteOpType(op OperationType) bool {
 }
 
 func (l LifeCycleRunner) processPostDeployment(
+	ctx context.Context,
 	deploymentName string,
 	operationData OperationData,
 	logger *log.Logger,
 ) (boshdirector.BoshTask, error) {
-	boshTasks, err := l.boshClient.GetNormalisedTasksByContext(deploymentName, operationData.BoshContextID, logger)
+	boshTasks, err := l.boshClient.GetNormalisedTasksByContext(ctx, deploymentName, operationData.BoshContextID, logger)
 	if err != nil {
 		return boshdirector.BoshTask{}, err
 	}
@@ -75,12 +78,12 @@ func (l LifeCycleRunner) processPostDeployment(
 	}
 
 	if isOldStylePostDeployOperationData(boshTasks, operationData) {
-		return l.runErrand(deploymentName, config.Errand{Name: operationData.PostDeployErrand.Name, Instances: operationData.PostDeployErrand.Instances}, operationData.BoshContextID, logger)
+		return l.runErrand(ctx, deploymentName, config.Errand{Name: operationData.PostDeployErrand.Name, Instances: operationData.PostDeployErrand.Instances}, operationData.BoshContextID, logger)
 	}
 
 	nextErrandIndex := len(boshTasks) - 1
 	if nextErrandIndex < len(operationData.Errands) {
-		return l.runErrand(deploymentName, operationData.Errands[nextErrandIndex], operationData.BoshContextID, logger)
+		return l.runErrand(ctx, deploymentName, operationData.Errands[nextErrandIndex], operationData.BoshContextID, logger)
 	}
 
 	if len(operationData.Errands) == 0 && operationData.PostDeployErrand.Name == "" {
@@ -90,11 +93,12 @@ func (l LifeCycleRunner) processPostDeployment(
 }
 
 func (l LifeCycleRunner) processPreDelete(
+	ctx context.Context,
 	deploymentName string,
 	operationData OperationData,
 	logger *log.Logger,
 ) (boshdirector.BoshTask, error) {
-	boshTasks, err := l.getTasks(deploymentName, operationData, logger)
+	boshTasks, err := l.getTasks(ctx, deploymentName, operationData, logger)
 	if err != nil {
 		return boshdirector.BoshTask{}, err
 	}
@@ -105,7 +109,7 @@ func (l LifeCycleRunner) processPreDelete(
 	}
 
 	if shouldRunDeprovision(boshTasks, operationData) {
-		return l.deprovisionAfterAllErrand(deploymentName, boshTasks, logger, operationData)
+		return l.deprovisionAfterAllErrand(ctx, deploymentName, boshTasks, logger, operationData)
 	}
 
 	if hasRunAllErrands(boshTasks, operationData) {
@@ -113,15 +117,16 @@ func (l LifeCycleRunner) processPreDelete(
 	}
 
 	errand := operationData.Errands[len(boshTasks)]
-	return l.runErrand(deploymentName, errand, operationData.BoshContextID, logger)
+	return l.runErrand(ctx, deploymentName, errand, operationData.BoshContextID, logger)
 }
 
 func shouldRunDeprovision(boshTasks []boshdirector.BoshTask, operationData OperationData) bool {
 	return len(boshTasks) == len(operationData.Errands) || isOldStylePreDeleteOperationData(boshTasks, operationData)
 }
 
-func (l LifeCycleRunner) deprovisionAfterAllErrand(deploymentName string, boshTasks []boshdirector.BoshTask, logger *log.Logger, operationData OperationData) (boshdirector.BoshTask, error) {
+func (l LifeCycleRunner) deprovisionAfterAllErrand(ctx context.Context, deploymentName string, boshTasks []boshdirector.BoshTask, logger *log.Logger, operationData OperationData) (boshdirector.BoshTask, error) {
 	taskID, err := l.boshClient.DeleteDeployment(
+		ctx,
 		deploymentName,
 		operationData.BoshContextID,
 		shouldForceDelete(operationData),
@@ -130,11 +135,11 @@ func (l LifeCycleRunner) deprovisionAfterAllErrand(deploymentName string, boshTa
 	if err != nil {
 		return boshdirector.BoshTask{}, err
 	}
-	return l.boshClient.GetTask(taskID, logger)
+	return l.boshClient.GetTask(ctx, taskID, logger)
 }
 
-func (l LifeCycleRunner) getTasks(deploymentName string, operationData OperationData, logger *log.Logger) ([]boshdirector.BoshTask, error) {
-	boshTasks, err := l.boshClient.GetNormalisedTasksByContext(deploymentName, operationData.BoshContextID, logger)
+func (l LifeCycleRunner) getTasks(ctx context.Context, deploymentName string, operationData OperationData, logger *log.Logger) ([]boshdirector.BoshTask, error) {
+	boshTasks, err := l.boshClient.GetNormalisedTasksByContext(ctx, deploymentName, operationData.BoshContextID, logger)
 	if err != nil {
 		return []boshdirector.BoshTask{}, err
 	}
@@ -185,13 +190,13 @@ func isOldStylePostDeployOperationData(boshTasks boshdirector.BoshTasks, operati
 	return len(boshTasks) == 1 && operationData.PostDeployErrand.Name != ""
 }
 
-func (l LifeCycleRunner) runErrand(deploymentName string, errand config.Errand, contextID string, log *log.Logger) (boshdirector.BoshTask, error) {
-	taskID, err := l.boshClient.RunErrand(deploymentName, errand.Name, errand.Instances, contextID, log, boshdirector.NewAsyncTaskReporter())
+func (l LifeCycleRunner) runErrand(ctx context.Context, deploymentName string, errand config.Errand, contextID string, log *log.Logger) (boshdirector.BoshTask, error) {
+	taskID, err := l.boshClient.RunErrand(ctx, deploymentName, errand.Name, errand.Instances, contextID, log, boshdirector.NewAsyncTaskReporter())
 	if err != nil {
 		return boshdirector.BoshTask{}, err
 	}
 
-	task, err := l.boshClient.GetTask(taskID, log)
+	task, err := l.boshClient.GetTask(ctx, taskID, log)
 	if err != nil {
 		return boshdirector.BoshTask{}, err
 	}
@@ -7,6 +7,7 @@
 package broker_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -90,7 +91,7 @@ var _ = Describe("Lifecycle runner", func() {
 			}
 			firstErrand := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "errand 1", Result: "result-1", ContextID: contextID}
 			secondErrand := boshdirector.BoshTask{ID: 2, State: boshdirector.TaskProcessing, Description: "errand 2", Result: "result-1", ContextID: contextID}
-			boshClient.GetTaskStub = func(id int, l *log.Logger) (boshdirector.BoshTask, error) {
+			boshClient.GetTaskStub = func(_ context.Context, id int, l *log.Logger) (boshdirector.BoshTask, error) {
 				switch id {
 				case secondErrand.ID:
 					return secondErrand, nil
@@ -103,7 +104,7 @@ var _ = Describe("Lifecycle runner", func() {
 
 			By("waiting the deployment to complete")
 			boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{taskProcessing}, nil)
-			task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+			task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(task).To(Equal(taskProcessing))
 
@@ -111,11 +112,11 @@ var _ = Describe("Lifecycle runner", func() {
 			boshClient.GetNormalisedTasksByContextReturnsOnCall(1, boshdirector.BoshTasks{taskComplete}, nil)
 			boshClient.RunErrandReturns(firstErrand.ID, nil)
 
-			task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+			task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(task).To(Equal(firstErrand))
 
-			depName, errandName, instances, ctxID, _, _ := boshClient.RunErrandArgsForCall(0)
+			_, depName, errandName, instances, ctxID, _, _ := boshClient.RunErrandArgsForCall(0)
 			Expect(depName).To(Equal(deploymentName))
 			Expect(errandName).To(Equal(operationData.Errands[0].Name))
 			Expect(instances).To(Equal(operationData.Errands[0].Instances))
@@ -127,11 +128,11 @@ var _ = Describe("Lifecycle runner", func() {
 			boshClient.GetNormalisedTasksByContextReturnsOnCall(2, boshdirector.BoshTasks{firstErrand, taskComplete}, nil)
 			boshClient.RunErrandReturns(secondErrand.ID, nil)
 
-			task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+			task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(task).To(Equal(secondErrand))
 
-			depName, errandName, instances, ctxID, _, _ = boshClient.RunErrandArgsForCall(1)
+			_, depName, errandName, instances, ctxID, _, _ = boshClient.RunErrandArgsForCall(1)
 			Expect(depName).To(Equal(deploymentName))
 			Expect(errandName).To(Equal(operationData.Errands[1].Name))
 			Expect(instances).To(Equal(operationData.Errands[1].Instances))
@@ -141,7 +142,7 @@ var _ = Describe("Lifecycle runner", func() {
 
 			By("returning the last task")
 			boshClient.GetNormalisedTasksByContextReturnsOnCall(3, boshdirector.BoshTasks{secondErrand, firstErrand, taskComplete}, nil)
-			task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+			task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(task).To(Equal(secondErrand))
 
@@ -151,13 +152,13 @@ var _ = Describe("Lifecycle runner", func() {
 
 		It("returns the errored task when the deployment errors", func() {
 			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{taskErrored}, nil)
-			task, _ := deployRunner.GetTask(deploymentName, operationData, logger)
+			task, _ := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 			Expect(task.State).To(Equal(boshdirector.TaskError))
 		})
 
 		It("returns an error when there are no tasks", func() {
 			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{}, nil)
-			_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+			_, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 			Expect(err).To(MatchError("no tasks found for context id: " + contextID))
 		})
 
@@ -171,7 +172,7 @@ var _ = Describe("Lifecycle runner", func() {
 				operationData.PlanID = ""
 				operationData.Errands = []config.Errand{}
 				operationData.PostDeployErrand.Name = ""
-				task, _ = deployRunner.GetTask(deploymentName, operationData, logger)
+				task, _ = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 
 				Expect(logBuffer.String()).To(ContainSubstring("can't determine lifecycle errands, neither PlanID nor PostDeployErrand.Name is present"))
 				Expect(boshClient.RunErrandCallCount()).To(Equal(0))
@@ -189,7 +190,7 @@ var _ = Describe("Lifecycle runner", func() {
 
 				It("returns the failed task when the errands fails", func() {
 					boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{taskErrored, taskComplete}, nil)
-					task, _ = deployRunner.GetTask(deploymentName, operationData, logger)
+					task, _ = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(task.State).To(Equal(boshdirector.TaskError))
 				})
 
@@ -197,13 +198,13 @@ var _ = Describe("Lifecycle runner", func() {
 					boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{taskComplete}, nil)
 					boshClient.RunErrandReturns(0, errors.New("some errand err"))
 
-					_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+					_, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(err).To(MatchError("some errand err"))
 				})
 
 				It("returns an error when bosh cannot retrieve the task", func() {
 					boshClient.GetTaskReturns(boshdirector.BoshTask{}, errors.New("some err"))
-					_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+					_, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(err).To(MatchError("some err"))
 				})
 			})
@@ -215,7 +216,7 @@ var _ = Describe("Lifecycle runner", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+				_, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).To(MatchError("some err"))
 			})
 		})
@@ -229,7 +230,7 @@ var _ = Describe("Lifecycle runner", func() {
 					Errands:       []config.Errand{{Name: "foo"}},
 				}
 				boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{taskComplete}, nil)
-				deployRunner.GetTask(deploymentName, operationData, logger)
+				deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 
 				if errandRuns {
 					Expect(boshClient.RunErrandCallCount()).To(Equal(1))
@@ -255,7 +256,7 @@ var _ = Describe("Lifecycle runner", func() {
 					Errands: []config.Errand{},
 				}
 				firstErrand := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "errand 1", Result: "result-1", ContextID: contextID}
-				boshClient.GetTaskStub = func(id int, l *log.Logger) (boshdirector.BoshTask, error) {
+				boshClient.GetTaskStub = func(_ context.Context, id int, l *log.Logger) (boshdirector.BoshTask, error) {
 					if id == firstErrand.ID {
 						return firstErrand, nil
 					}
@@ -265,7 +266,7 @@ var _ = Describe("Lifecycle runner", func() {
 
 				boshClient.RunErrandReturns(firstErrand.ID, nil)
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{taskComplete}, nil)
-				task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(task).To(Equal(firstErrand))
 
@@ -290,7 +291,7 @@ var _ = Describe("Lifecycle runner", func() {
 				})
 
 				It("returns the processing task", func() {
-					task, _ := deployRunner.GetTask(deploymentName, operationData, logger)
+					task, _ := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(task.State).To(Equal(boshdirector.TaskProcessing))
 				})
 			})
@@ -301,7 +302,7 @@ var _ = Describe("Lifecycle runner", func() {
 				})
 
 				It("returns the errored task", func() {
-					task, _ := deployRunner.GetTask(deploymentName, operationData, logger)
+					task, _ := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(task.State).To(Equal(boshdirector.TaskError))
 				})
 			})
@@ -312,7 +313,7 @@ var _ = Describe("Lifecycle runner", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+					_, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(err).To(MatchError("no tasks found for context id: " + contextID))
 				})
 			})
@@ -324,7 +325,7 @@ var _ = Describe("Lifecycle runner", func() {
 					boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{taskComplete}, nil)
 					boshClient.DeleteDeploymentReturns(taskProcessing.ID, nil)
 					boshClient.GetTaskReturns(taskProcessing, nil)
-					task, _ = deployRunner.GetTask(deploymentName, operationData, logger)
+					task, _ = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				})
 
 				It("runs bosh delete deployment ", func() {
@@ -332,12 +333,12 @@ var _ = Describe("Lifecycle runner", func() {
 				})
 
 				It("deletes the correct deployment", func() {
-					deletedDeploymentName, _, _, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+					_, deletedDeploymentName, _, _, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
 					Expect(deletedDeploymentName).To(Equal(deploymentName))
 				})
 
 				It("runs the delete deployment with the correct contextID", func() {
-					_, ctxID, _, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+					_, _, ctxID, _, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
 					Expect(ctxID).To(Equal(contextID))
 				})
 
@@ -352,7 +353,7 @@ var _ = Describe("Lifecycle runner", func() {
 					})
 
 					It("returns an error", func() {
-						_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+						_, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 						Expect(err).To(MatchError("some err"))
 					})
 				})
@@ -364,7 +365,7 @@ var _ = Describe("Lifecycle runner", func() {
 				})
 
 				It("returns the latest task", func() {
-					task, _ := deployRunner.GetTask(deploymentName, operationData, logger)
+					task, _ := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(task).To(Equal(taskProcessing))
 				})
 			})
@@ -377,7 +378,7 @@ var _ = Describe("Lifecycle runner", func() {
 				}
 				firstErrand := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "errand 1", Result: "result-1", ContextID: contextID}
 				secondErrand := boshdirector.BoshTask{ID: 2, State: boshdirector.TaskProcessing, Description: "errand 2", Result: "result-1", ContextID: contextID}
-				boshClient.GetTaskStub = func(id int, l *log.Logger) (boshdirector.BoshTask, error) {
+				boshClient.GetTaskStub = func(_ context.Context, id int, l *log.Logger) (boshdirector.BoshTask, error) {
 					switch id {
 					case secondErrand.ID:
 						return secondErrand, nil
@@ -389,14 +390,14 @@ var _ = Describe("Lifecycle runner", func() {
 				}
 
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{firstErrand}, nil)
-				task, _ := deployRunner.GetTask(deploymentName, operationData, logger)
+				task, _ := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(task).To(Equal(firstErrand))
 
 				firstErrand.State = boshdirector.TaskDone
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(1, boshdirector.BoshTasks{firstErrand}, nil)
 				boshClient.RunErrandReturns(secondErrand.ID, nil)
 
-				task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(task).To(Equal(secondErrand))
 
@@ -404,16 +405,16 @@ var _ = Describe("Lifecycle runner", func() {
 
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(2, boshdirector.BoshTasks{secondErrand, firstErrand}, nil)
 				boshClient.DeleteDeploymentReturns(taskProcessing.ID, nil)
-				task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1))
 				Expect(task).To(Equal(taskProcessing))
 
-				_, _, isForce, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+				_, _, _, isForce, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
 				Expect(isForce).To(BeFalse())
 
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(3, boshdirector.BoshTasks{taskComplete, secondErrand, firstErrand}, nil)
-				task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(task).To(Equal(taskComplete))
 			})
@@ -424,7 +425,7 @@ var _ = Describe("Lifecycle runner", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+					_, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(err).To(MatchError("some err"))
 				})
 			})
@@ -440,7 +441,7 @@ var _ = Describe("Lifecycle runner", func() {
 						Errands: []config.Errand{},
 					}
 					firstErrand := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "errand 1", Result: "result-1", ContextID: contextID}
-					boshClient.GetTaskStub = func(id int, l *log.Logger) (boshdirector.BoshTask, error) {
+					boshClient.GetTaskStub = func(_ context.Context, id int, l *log.Logger) (boshdirector.BoshTask, error) {
 						if id == taskProcessing.ID {
 							return taskProcessing, nil
 						}
@@ -449,20 +450,20 @@ var _ = Describe("Lifecycle runner", func() {
 					}
 
 					boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{firstErrand}, nil)
-					task, _ := deployRunner.GetTask(deploymentName, operationData, logger)
+					task, _ := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(task).To(Equal(firstErrand))
 
 					firstErrand.State = boshdirector.TaskDone
 
 					boshClient.GetNormalisedTasksByContextReturnsOnCall(1, boshdirector.BoshTasks{firstErrand}, nil)
 					boshClient.DeleteDeploymentReturns(taskProcessing.ID, nil)
-					task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+					task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1))
 					Expect(task).To(Equal(taskProcessing))
 
 					boshClient.GetNormalisedTasksByContextReturnsOnCall(2, boshdirector.BoshTasks{taskComplete, firstErrand}, nil)
-					task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+					task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(task).To(Equal(taskComplete))
 				})
@@ -478,7 +479,7 @@ var _ = Describe("Lifecycle runner", func() {
 				}
 				firstErrand := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskProcessing, Description: "errand 1", Result: "result-1", ContextID: contextID}
 				secondErrand := boshdirector.BoshTask{ID: 2, State: boshdirector.TaskProcessing, Description: "errand 2", Result: "result-1", ContextID: contextID}
-				boshClient.GetTaskStub = func(id int, l *log.Logger) (boshdirector.BoshTask, error) {
+				boshClient.GetTaskStub = func(_ context.Context, id int, l *log.Logger) (boshdirector.BoshTask, error) {
 					switch id {
 					case secondErrand.ID:
 						return secondErrand, nil
@@ -490,14 +491,14 @@ var _ = Describe("Lifecycle runner", func() {
 				}
 
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{firstErrand}, nil)
-				task, _ := deployRunner.GetTask(deploymentName, operationData, logger)
+				task, _ := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(task).To(Equal(firstErrand))
 
 				firstErrand.State = boshdirector.TaskDone
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(1, boshdirector.BoshTasks{firstErrand}, nil)
 				boshClient.RunErrandReturns(secondErrand.ID, nil)
 
-				task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(task).To(Equal(secondErrand))
 
@@ -505,17 +506,17 @@ var _ = Describe("Lifecycle runner", func() {
 
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(2, boshdirector.BoshTasks{secondErrand, firstErrand}, nil)
 				boshClient.DeleteDeploymentReturns(taskProcessing.ID, nil)
-				task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1))
 
-				_, _, isForce, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+				_, _, _, isForce, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
 				Expect(isForce).To(BeTrue(), "expected to pass 'force' to DeleteDeployment")
 
 				Expect(task).To(Equal(taskProcessing))
 
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(3, boshdirector.BoshTasks{taskComplete, secondErrand, firstErrand}, nil)
-				task, err = deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err = deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(task).To(Equal(taskComplete))
 			})
@@ -527,11 +528,13 @@ var _ = Describe("Lifecycle runner", func() {
 					Errands:       []config.Errand{{Name: "an-errand"}},
 				}
 				firstErrand := boshdirector.BoshTask{ID: 1, State: boshdirector.TaskError, Description: "errand 1", Result: "result-1", ContextID: contextID}
-				boshClient.GetTaskStub = func(id int, l *log.Logger) (boshdirector.BoshTask, error) { return taskProcessing, nil }
+				boshClient.GetTaskStub = func(_ context.Context, id int, l *log.Logger) (boshdirector.BoshTask, error) {
+					return taskProcessing, nil
+				}
 
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{firstErrand}, nil)
 
-				task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 
 				Expect(err).NotTo(HaveOccurred())
 				Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1), "delete deployment expected to be called once")
@@ -548,7 +551,7 @@ var _ = Describe("Lifecycle runner", func() {
 				}
 				boshClient.GetNormalisedTasksByContextReturnsOnCall(0, boshdirector.BoshTasks{taskErrored}, nil)
 
-				task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+				task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 
 				Expect(task).To(Equal(taskErrored))
 				Expect(err).NotTo(HaveOccurred())
@@ -565,7 +568,7 @@ var _ = Describe("Lifecycle runner", func() {
 			}
 			boshClient.GetTaskReturns(taskComplete, nil)
 
-			task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+			task, err := deployRunner.GetTask(context.Background(), deploymentName, operationData, logger)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(task).To(Equal(taskComplete))
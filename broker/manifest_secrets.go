@@ -7,6 +7,7 @@
 package broker
 
 import (
+	"context"
 	"log"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
@@ -14,6 +15,8 @@ import (
 
 //counterfeiter:generate -o fakes/fake_manifest_secrets_manager.go . ManifestSecretManager
 type ManifestSecretManager interface {
-	ResolveManifestSecrets(manifest []byte, deploymentVariables []boshdirector.Variable, logger *log.Logger) (map[string]string, error)
-	DeleteSecretsForInstance(instanceID string, logger *log.Logger) error
+	ResolveManifestSecrets(ctx context.Context, manifest []byte, deploymentVariables []boshdirector.Variable, logger *log.Logger) (map[string]string, error)
+	DeleteSecretsForInstance(ctx context.Context, instanceID string, logger *log.Logger) error
+	RestoreSecrets(ctx context.Context, variables []boshdirector.Variable, logger *log.Logger) error
+	RegenerateSecrets(ctx context.Context, paths []string, logger *log.Logger) error
 }
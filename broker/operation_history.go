@@ -0,0 +1,208 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+)
+
+// OperationRecord is what the broker stores about an operation when it submits
+// it to BOSH. BOSH knows which tasks ran, but not which request asked for them
+// or how the plan changed.
+type OperationRecord struct {
+	Type          OperationType `json:"type"`
+	RequestID     string        `json:"request_id,omitempty"`
+	PlanIDBefore  string        `json:"plan_id_before,omitempty"`
+	PlanIDAfter   string        `json:"plan_id_after,omitempty"`
+	BoshTaskID    int           `json:"bosh_task_id"`
+	BoshContextID string        `json:"bosh_context_id,omitempty"`
+	StartedAt     time.Time     `json:"started_at"`
+}
+
+type OperationHistoryEntry struct {
+	Type          OperationType             `json:"type"`
+	StartedAt     time.Time                 `json:"started_at"`
+	RequestID     string                    `json:"request_id,omitempty"`
+	PlanIDBefore  string                    `json:"plan_id_before,omitempty"`
+	PlanIDAfter   string                    `json:"plan_id_after,omitempty"`
+	BoshContextID string                    `json:"bosh_context_id,omitempty"`
+	BoshTaskIDs   []int                     `json:"bosh_task_ids"`
+	Errands       []string                  `json:"errands,omitempty"`
+	Outcome       domain.LastOperationState `json:"outcome"`
+}
+
+func newOperationRecord(ctx context.Context, operationType OperationType, planIDBefore, planIDAfter string, boshTaskID int, boshContextID string) OperationRecord {
+	return OperationRecord{
+		Type:          operationType,
+		RequestID:     brokercontext.GetReqID(ctx),
+		PlanIDBefore:  planIDBefore,
+		PlanIDAfter:   planIDAfter,
+		BoshTaskID:    boshTaskID,
+		BoshContextID: boshContextID,
+		StartedAt:     time.Now().UTC(),
+	}
+}
+
+// OperationHistory returns the operations run on an instance, oldest first.
+// Operations recorded by the broker are completed with their BOSH tasks; any
+// other deployment events, such as deletes, are reported with what BOSH knows
+// about them. The history of a deleted instance is kept until its metadata
+// expires. It fails with a DeploymentNotFoundError when neither the broker nor
+// BOSH know the instance.
+func (b *Broker) OperationHistory(ctx context.Context, instanceID string, logger *log.Logger) ([]OperationHistoryEntry, error) {
+	metadata, found, err := b.getRetainedInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := b.boshClient.GetEvents(ctx, deploymentName(instanceID), "", logger)
+	if err != nil {
+		return nil, fmt.Errorf("error getting events for deployment %s: %s", deploymentName(instanceID), err)
+	}
+
+	if !found && len(events) == 0 {
+		return nil, NewDeploymentNotFoundError(fmt.Errorf("instance %s not found", instanceID))
+	}
+
+	eventEntries := eventOperations(events)
+	eventOutcomes := map[int]domain.LastOperationState{}
+	for _, entry := range eventEntries {
+		eventOutcomes[entry.BoshTaskIDs[0]] = entry.Outcome
+	}
+
+	history := []OperationHistoryEntry{}
+	recordedTasks := map[int]bool{}
+	for _, record := range metadata.Operations {
+		entry := OperationHistoryEntry{
+			Type:          record.Type,
+			StartedAt:     record.StartedAt,
+			RequestID:     record.RequestID,
+			PlanIDBefore:  record.PlanIDBefore,
+			PlanIDAfter:   record.PlanIDAfter,
+			BoshContextID: record.BoshContextID,
+			BoshTaskIDs:   []int{record.BoshTaskID},
+		}
+
+		// Deployment events already tell how a single task ended, so the task
+		// is only fetched when BOSH has no event for it. Operations that ran
+		// several tasks, such as errands, are looked up by their context.
+		if outcome, ok := eventOutcomes[record.BoshTaskID]; ok && record.BoshContextID == "" {
+			entry.Outcome = outcome
+		} else {
+			tasks, err := b.operationTasks(ctx, instanceID, record, logger)
+			if err != nil {
+				return nil, err
+			}
+			entry.Outcome = tasksOutcome(tasks)
+			entry.BoshTaskIDs = []int{}
+			for _, task := range tasks {
+				entry.BoshTaskIDs = append(entry.BoshTaskIDs, task.ID)
+				if errand, ok := errandName(task); ok {
+					entry.Errands = append(entry.Errands, errand)
+				}
+				recordedTasks[task.ID] = true
+			}
+		}
+		recordedTasks[record.BoshTaskID] = true
+		history = append(history, entry)
+	}
+
+	for _, entry := range eventEntries {
+		if !recordedTasks[entry.BoshTaskIDs[0]] {
+			history = append(history, entry)
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].BoshTaskIDs[0] < history[j].BoshTaskIDs[0]
+	})
+
+	return history, nil
+}
+
+func (b *Broker) operationTasks(ctx context.Context, instanceID string, record OperationRecord, logger *log.Logger) (boshdirector.BoshTasks, error) {
+	if record.BoshContextID != "" {
+		tasks, err := b.boshClient.GetNormalisedTasksByContext(ctx, deploymentName(instanceID), record.BoshContextID, logger)
+		if err != nil {
+			return nil, fmt.Errorf("error getting tasks for context %s: %s", record.BoshContextID, err)
+		}
+		if len(tasks) > 0 {
+			sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+			return tasks, nil
+		}
+	}
+
+	task, err := b.boshClient.GetTask(ctx, record.BoshTaskID, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error getting task %d: %s", record.BoshTaskID, err)
+	}
+	return boshdirector.BoshTasks{task}, nil
+}
+
+// eventOperations turns the deployment events into one entry per task. BOSH
+// records an event when a task starts and another one, pointing at the first,
+// when it finishes.
+func eventOperations(events []boshdirector.BoshEvent) []OperationHistoryEntry {
+	var entries []OperationHistoryEntry
+	indexes := map[int]int{}
+	for _, event := range events {
+		i, found := indexes[event.TaskId]
+		if !found {
+			i = len(entries)
+			indexes[event.TaskId] = i
+			entries = append(entries, OperationHistoryEntry{
+				Type:        OperationType(event.Action),
+				StartedAt:   event.Timestamp,
+				BoshTaskIDs: []int{event.TaskId},
+				Outcome:     domain.InProgress,
+			})
+		}
+
+		entry := &entries[i]
+		if event.ParentID == "" {
+			entry.Type = OperationType(event.Action)
+			entry.StartedAt = event.Timestamp
+		} else if entry.Outcome != domain.Failed {
+			entry.Outcome = domain.Succeeded
+		}
+		if event.Error != "" {
+			entry.Outcome = domain.Failed
+		}
+	}
+	return entries
+}
+
+func tasksOutcome(tasks boshdirector.BoshTasks) domain.LastOperationState {
+	switch {
+	case len(tasks.FailedTasks()) > 0:
+		return domain.Failed
+	case tasks.AllTasksAreDone():
+		return domain.Succeeded
+	default:
+		return domain.InProgress
+	}
+}
+
+// errandName extracts the errand from the description of an errand task, such
+// as "run errand health-check from deployment service-instance_some-id".
+func errandName(task boshdirector.BoshTask) (string, bool) {
+	fields := strings.Fields(task.Description)
+	if len(fields) < 3 || fields[0] != "run" || fields[1] != "errand" {
+		return "", false
+	}
+	return fields[2], true
+}
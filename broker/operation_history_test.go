@@ -0,0 +1,172 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("OperationHistory", func() {
+	var (
+		instanceID = "some-instance-id"
+		createdAt  = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		deletedAt  = time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+			Type: broker.InstanceMetadataConfigType,
+			Name: "service-instance_some-instance-id",
+			Content: `{"plan_id":"another-plan","operations":[
+				{"type":"create","request_id":"create-request","plan_id_after":"some-plan-id","bosh_task_id":10,"bosh_context_id":"create-context","started_at":"2026-01-02T03:04:05Z"},
+				{"type":"update","request_id":"update-request","plan_id_before":"some-plan-id","plan_id_after":"another-plan","bosh_task_id":20,"started_at":"2026-01-03T03:04:05Z"}
+			]}`,
+		}}, nil)
+		boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{
+			{ID: 11, State: boshdirector.TaskDone, Description: "run errand health-check from deployment service-instance_some-instance-id"},
+			{ID: 10, State: boshdirector.TaskDone, Description: "create deployment"},
+		}, nil)
+		boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 20, State: boshdirector.TaskError}, nil)
+		boshClient.GetEventsReturns([]boshdirector.BoshEvent{
+			{TaskId: 30, Action: "delete", Timestamp: deletedAt.Add(time.Minute), ParentID: "5"},
+			{TaskId: 30, Action: "delete", Timestamp: deletedAt},
+			{TaskId: 20, Action: "update", Timestamp: createdAt, ParentID: "3", Error: "failed"},
+			{TaskId: 20, Action: "update", Timestamp: createdAt},
+			{TaskId: 10, Action: "create", Timestamp: createdAt, ParentID: "1"},
+			{TaskId: 10, Action: "create", Timestamp: createdAt},
+		}, nil)
+	})
+
+	JustBeforeEach(func() {
+		b = createDefaultBroker()
+	})
+
+	It("combines the recorded operations with the BOSH tasks and events", func() {
+		history, err := b.OperationHistory(context.Background(), instanceID, loggerFactory.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(history).To(Equal([]broker.OperationHistoryEntry{
+			{
+				Type:          broker.OperationTypeCreate,
+				StartedAt:     createdAt,
+				RequestID:     "create-request",
+				PlanIDAfter:   existingPlanID,
+				BoshContextID: "create-context",
+				BoshTaskIDs:   []int{10, 11},
+				Errands:       []string{"health-check"},
+				Outcome:       domain.Succeeded,
+			},
+			{
+				Type:         broker.OperationTypeUpdate,
+				StartedAt:    createdAt.Add(24 * time.Hour),
+				RequestID:    "update-request",
+				PlanIDBefore: existingPlanID,
+				PlanIDAfter:  secondPlanID,
+				BoshTaskIDs:  []int{20},
+				Outcome:      domain.Failed,
+			},
+			{
+				Type:        broker.OperationTypeDelete,
+				StartedAt:   deletedAt,
+				BoshTaskIDs: []int{30},
+				Outcome:     domain.Succeeded,
+			},
+		}))
+
+		_, deploymentName, contextID, _ := boshClient.GetNormalisedTasksByContextArgsForCall(0)
+		Expect(deploymentName).To(Equal("service-instance_some-instance-id"))
+		Expect(contextID).To(Equal("create-context"))
+		Expect(boshClient.GetTaskCallCount()).To(BeZero())
+		_, eventsDeployment, action, _ := boshClient.GetEventsArgsForCall(0)
+		Expect(eventsDeployment).To(Equal("service-instance_some-instance-id"))
+		Expect(action).To(BeEmpty())
+	})
+
+	It("reports the operations from the events alone when nothing was recorded", func() {
+		boshClient.GetConfigsReturns(nil, nil)
+		boshClient.GetEventsReturns([]boshdirector.BoshEvent{
+			{TaskId: 20, Action: "update", Timestamp: deletedAt},
+			{TaskId: 10, Action: "create", Timestamp: createdAt, ParentID: "1"},
+			{TaskId: 10, Action: "create", Timestamp: createdAt},
+		}, nil)
+
+		history, err := b.OperationHistory(context.Background(), instanceID, loggerFactory.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(history).To(Equal([]broker.OperationHistoryEntry{
+			{Type: broker.OperationTypeCreate, StartedAt: createdAt, BoshTaskIDs: []int{10}, Outcome: domain.Succeeded},
+			{Type: broker.OperationTypeUpdate, StartedAt: deletedAt, BoshTaskIDs: []int{20}, Outcome: domain.InProgress},
+		}))
+	})
+
+	It("fetches the task of an operation that has no event", func() {
+		boshClient.GetEventsReturns([]boshdirector.BoshEvent{
+			{TaskId: 10, Action: "create", Timestamp: createdAt, ParentID: "1"},
+			{TaskId: 10, Action: "create", Timestamp: createdAt},
+		}, nil)
+
+		history, err := b.OperationHistory(context.Background(), instanceID, loggerFactory.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(history).To(HaveLen(2))
+		Expect(history[1].BoshTaskIDs).To(Equal([]int{20}))
+		Expect(history[1].Outcome).To(Equal(domain.Failed))
+		Expect(boshClient.GetTaskCallCount()).To(Equal(1))
+		_, taskID, _ := boshClient.GetTaskArgsForCall(0)
+		Expect(taskID).To(Equal(20))
+	})
+
+	It("reports the history of a deleted instance", func() {
+		boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+			Type:    broker.InstanceMetadataConfigType,
+			Name:    "service-instance_some-instance-id",
+			Content: `{"plan_id":"some-plan-id","deleted_at":"2026-02-03T04:05:06Z","operations":[{"type":"delete","request_id":"delete-request","bosh_task_id":30,"started_at":"2026-02-03T04:05:06Z"}]}`,
+		}}, nil)
+
+		history, err := b.OperationHistory(context.Background(), instanceID, loggerFactory.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(history).To(ContainElement(broker.OperationHistoryEntry{
+			Type:        broker.OperationTypeDelete,
+			StartedAt:   deletedAt,
+			RequestID:   "delete-request",
+			BoshTaskIDs: []int{30},
+			Outcome:     domain.Succeeded,
+		}))
+	})
+
+	It("fails with a not found error when neither the broker nor BOSH know the instance", func() {
+		boshClient.GetConfigsReturns(nil, nil)
+		boshClient.GetEventsReturns(nil, nil)
+
+		_, err := b.OperationHistory(context.Background(), instanceID, loggerFactory.New())
+		Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+	})
+
+	It("fails when the events cannot be retrieved", func() {
+		boshClient.GetEventsReturns(nil, errors.New("director unreachable"))
+
+		_, err := b.OperationHistory(context.Background(), instanceID, loggerFactory.New())
+		Expect(err).To(MatchError(ContainSubstring("director unreachable")))
+	})
+
+	It("fails when the tasks of an operation cannot be retrieved", func() {
+		boshClient.GetNormalisedTasksByContextReturns(nil, errors.New("task not found"))
+
+		_, err := b.OperationHistory(context.Background(), instanceID, loggerFactory.New())
+		Expect(err).To(MatchError(ContainSubstring("task not found")))
+	})
+})
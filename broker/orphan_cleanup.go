@@ -0,0 +1,229 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// OrphanMarkerConfigType is the BOSH config type under which the broker
+// records when a deployment was first found to be orphaned. The config is
+// named after the deployment, so it is removed with the other configs of the
+// instance once the deployment is deleted.
+const OrphanMarkerConfigType = "odb-orphan-marker"
+
+// OrphanMarker records when an orphan was first detected. ServiceID is the
+// offering that marked it, as offerings may share the BOSH director.
+type OrphanMarker struct {
+	ServiceID  string    `json:"service_id"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// OrphanCleanupOptions configures CleanupOrphanDeployments. Orphans are
+// deleted once GracePeriod has passed since they were first detected. Orphans
+// in AllowList, by deployment name or instance ID, are never deleted. With
+// DryRun, the report says what would be done without changing anything.
+type OrphanCleanupOptions struct {
+	GracePeriod time.Duration
+	AllowList   []string
+	DryRun      bool
+}
+
+type OrphanCleanupAction string
+
+const (
+	OrphanAllowListed         = OrphanCleanupAction("allow-listed")
+	OrphanPlanNotFound        = OrphanCleanupAction("plan-not-found")
+	OrphanMarked              = OrphanCleanupAction("marked")
+	OrphanPending             = OrphanCleanupAction("pending")
+	OrphanOperationInProgress = OrphanCleanupAction("operation-in-progress")
+	OrphanDeletionStarted     = OrphanCleanupAction("deleting")
+)
+
+type OrphanCleanupReport struct {
+	DryRun  bool            `json:"dry_run"`
+	Orphans []OrphanCleanup `json:"orphans"`
+}
+
+// OrphanCleanup is what was done with an orphan deployment. Operation is set
+// when its deletion was started, so that it can be polled until complete.
+type OrphanCleanup struct {
+	DeploymentName string              `json:"deployment_name"`
+	Action         OrphanCleanupAction `json:"action"`
+	DetectedAt     *time.Time          `json:"detected_at,omitempty"`
+	DeleteAfter    *time.Time          `json:"delete_after,omitempty"`
+	Operation      *OperationData      `json:"operation,omitempty"`
+}
+
+// CleanupOrphanDeployments marks the orphans that are detected for the first
+// time and deletes those whose grace period has passed. Deletion goes through
+// the same pre-delete errands as deprovisioning; the configs and secrets of
+// the instance are removed when the operation is polled to completion.
+// Orphans whose recorded plan is not one of the offering are left alone, as
+// they may belong to another offering on the same BOSH director. Markers of
+// the offering for deployments that are no longer orphaned are removed.
+func (b *Broker) CleanupOrphanDeployments(ctx context.Context, orphans []string, options OrphanCleanupOptions, logger *log.Logger) (OrphanCleanupReport, error) {
+	if b.DisableBoshConfigs {
+		return OrphanCleanupReport{}, b.processError(errors.New("orphan cleanup requires BOSH configs to be enabled"), logger)
+	}
+
+	serviceOffering := b.offering()
+	markers, err := b.getOrphanMarkers(ctx, serviceOffering.ID, logger)
+	if err != nil {
+		return OrphanCleanupReport{}, b.processError(fmt.Errorf("error getting orphan markers: %s", err), logger)
+	}
+
+	report := OrphanCleanupReport{DryRun: options.DryRun, Orphans: []OrphanCleanup{}}
+	now := time.Now().UTC()
+	isOrphan := map[string]bool{}
+	for _, deployment := range orphans {
+		cleanup := OrphanCleanup{DeploymentName: deployment}
+
+		if allowListed(deployment, options.AllowList) {
+			cleanup.Action = OrphanAllowListed
+			report.Orphans = append(report.Orphans, cleanup)
+			continue
+		}
+
+		metadata, _, err := b.getInstanceMetadata(ctx, instanceID(deployment), logger)
+		if err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.WarnLevel).Printf("cannot read the instance metadata of orphan deployment %s: %s\n", deployment, err)
+		}
+		plan, found := serviceOffering.FindPlanByID(metadata.PlanID)
+		if !found {
+			logger.Printf("leaving orphan deployment %s, as plan %q is not a plan of service offering %s\n", deployment, metadata.PlanID, serviceOffering.ID)
+			cleanup.Action = OrphanPlanNotFound
+			report.Orphans = append(report.Orphans, cleanup)
+			continue
+		}
+
+		isOrphan[deployment] = true
+		marker, marked := markers[deployment]
+		if !marked {
+			marker = OrphanMarker{ServiceID: serviceOffering.ID, DetectedAt: now}
+			if !options.DryRun {
+				if err := b.saveOrphanMarker(ctx, deployment, marker, logger); err != nil {
+					return OrphanCleanupReport{}, b.processError(fmt.Errorf("error marking orphan deployment %s: %s", deployment, err), logger)
+				}
+			}
+		}
+		deleteAfter := marker.DetectedAt.Add(options.GracePeriod)
+		cleanup.DetectedAt = &marker.DetectedAt
+		cleanup.DeleteAfter = &deleteAfter
+
+		switch {
+		case !marked:
+			logger.Printf("marked orphan deployment %s for deletion after %s\n", deployment, deleteAfter.Format(time.RFC3339))
+			cleanup.Action = OrphanMarked
+		case now.Before(deleteAfter):
+			cleanup.Action = OrphanPending
+		case options.DryRun:
+			cleanup.Action = OrphanDeletionStarted
+		default:
+			operationData, err := b.deleteOrphan(ctx, instanceID(deployment), metadata, plan, logger)
+			switch err.(type) {
+			case nil:
+				cleanup.Action = OrphanDeletionStarted
+				cleanup.Operation = &operationData
+			case OperationInProgressError:
+				logger.Println(err)
+				cleanup.Action = OrphanOperationInProgress
+			default:
+				return OrphanCleanupReport{}, b.processError(err, logger)
+			}
+		}
+		report.Orphans = append(report.Orphans, cleanup)
+	}
+
+	if !options.DryRun {
+		for deployment := range markers {
+			if isOrphan[deployment] {
+				continue
+			}
+			if _, err := b.boshClient.DeleteConfig(ctx, OrphanMarkerConfigType, deployment, logger); err != nil {
+				loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error removing orphan marker of %s: %s\n", deployment, err)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (b *Broker) deleteOrphan(ctx context.Context, instanceID string, metadata InstanceMetadata, plan config.Plan, logger *log.Logger) (OperationData, error) {
+	defer b.deploymentLocks.lock(instanceID)()
+	ctx = brokercontext.WithOperation(brokercontext.WithInstanceID(ctx, instanceID), string(OperationTypeDelete))
+
+	tasks, err := b.boshClient.GetTasksInProgress(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return OperationData{}, NewGenericError(ctx, err)
+	}
+	if len(tasks) > 0 {
+		return OperationData{}, NewOperationInProgressError(fmt.Errorf("orphan deployment %s is still in progress: tasks %s", deploymentName(instanceID), tasks.ToLog()))
+	}
+
+	logger.Printf("deleting orphan deployment %s\n", deploymentName(instanceID))
+	serviceSpec, err := b.startDelete(ctx, instanceID, metadata, plan, true, OperationTypeDelete, logger)
+	if err != nil {
+		return OperationData{}, err
+	}
+
+	var operationData OperationData
+	if err := json.Unmarshal([]byte(serviceSpec.OperationData), &operationData); err != nil {
+		return OperationData{}, NewGenericError(ctx, err)
+	}
+	return operationData, nil
+}
+
+// getOrphanMarkers returns the orphan markers of the offering with serviceID by
+// deployment name.
+func (b *Broker) getOrphanMarkers(ctx context.Context, serviceID string, logger *log.Logger) (map[string]OrphanMarker, error) {
+	configs, err := b.boshClient.GetConfigsOfType(ctx, OrphanMarkerConfigType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	markers := map[string]OrphanMarker{}
+	for _, c := range configs {
+		var marker OrphanMarker
+		if err := json.Unmarshal([]byte(c.Content), &marker); err != nil {
+			logger.Printf("ignoring orphan marker of %s that cannot be parsed: %s\n", c.Name, err)
+			continue
+		}
+		if marker.ServiceID != serviceID {
+			continue
+		}
+		markers[c.Name] = marker
+	}
+	return markers, nil
+}
+
+func (b *Broker) saveOrphanMarker(ctx context.Context, deployment string, marker OrphanMarker, logger *log.Logger) error {
+	content, err := json.Marshal(marker)
+	if err != nil {
+		return err
+	}
+
+	return b.boshClient.UpdateConfig(ctx, OrphanMarkerConfigType, deployment, content, logger)
+}
+
+func allowListed(deployment string, allowList []string) bool {
+	for _, allowed := range allowList {
+		if allowed == deployment || allowed == instanceID(deployment) {
+			return true
+		}
+	}
+	return false
+}
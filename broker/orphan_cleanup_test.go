@@ -0,0 +1,278 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("Orphan Cleanup", func() {
+	const (
+		orphanInstanceID = "orphan-instance"
+		deleteTaskID     = 42
+	)
+
+	var (
+		logger     *log.Logger
+		orphan     string
+		options    broker.OrphanCleanupOptions
+		report     broker.OrphanCleanupReport
+		cleanupErr error
+	)
+
+	markedAt := func(detectedAt time.Time) {
+		content := fmt.Sprintf(`{"service_id":%q,"detected_at":%q}`, serviceOfferingID, detectedAt.Format(time.RFC3339))
+		boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{{
+			Type:    broker.OrphanMarkerConfigType,
+			Name:    orphan,
+			Content: content,
+		}}, nil)
+	}
+
+	BeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		orphan = deploymentName(orphanInstanceID)
+		options = broker.OrphanCleanupOptions{GracePeriod: time.Hour}
+		boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+			Type:    broker.InstanceMetadataConfigType,
+			Name:    orphan,
+			Content: fmt.Sprintf(`{"plan_id":%q}`, existingPlanID),
+		}}, nil)
+		boshClient.DeleteDeploymentReturns(deleteTaskID, nil)
+		b = createDefaultBroker()
+	})
+
+	JustBeforeEach(func() {
+		report, cleanupErr = b.CleanupOrphanDeployments(context.Background(), []string{orphan}, options, logger)
+	})
+
+	It("marks orphans when they are first detected", func() {
+		Expect(cleanupErr).NotTo(HaveOccurred())
+		Expect(report.Orphans).To(HaveLen(1))
+		Expect(report.Orphans[0].DeploymentName).To(Equal(orphan))
+		Expect(report.Orphans[0].Action).To(Equal(broker.OrphanMarked))
+		Expect(*report.Orphans[0].DeleteAfter).To(Equal(report.Orphans[0].DetectedAt.Add(time.Hour)))
+
+		Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+		_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(0)
+		Expect(configType).To(Equal(broker.OrphanMarkerConfigType))
+		Expect(configName).To(Equal(orphan))
+		var marker broker.OrphanMarker
+		Expect(json.Unmarshal(content, &marker)).To(Succeed())
+		Expect(marker.ServiceID).To(Equal(serviceOfferingID))
+		Expect(marker.DetectedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+		Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+	})
+
+	When("the plan recorded for the orphan is not a plan of the offering", func() {
+		BeforeEach(func() {
+			boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+				Type:    broker.InstanceMetadataConfigType,
+				Name:    orphan,
+				Content: `{"plan_id":"plan-of-another-offering"}`,
+			}}, nil)
+			markedAt(time.Now().Add(-2 * time.Hour))
+		})
+
+		It("neither marks nor deletes it", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(report.Orphans[0].Action).To(Equal(broker.OrphanPlanNotFound))
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(0))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+		})
+	})
+
+	When("there is no instance metadata for the orphan", func() {
+		BeforeEach(func() {
+			boshClient.GetConfigsReturns(nil, nil)
+			markedAt(time.Now().Add(-2 * time.Hour))
+		})
+
+		It("neither marks nor deletes it", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(report.Orphans[0].Action).To(Equal(broker.OrphanPlanNotFound))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the orphan was marked by another offering", func() {
+		BeforeEach(func() {
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{{
+				Type:    broker.OrphanMarkerConfigType,
+				Name:    deploymentName("other-offering-instance"),
+				Content: `{"service_id":"another-service-id","detected_at":"2020-01-01T00:00:00Z"}`,
+			}}, nil)
+		})
+
+		It("leaves its marker", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the orphan is within its grace period", func() {
+		BeforeEach(func() {
+			markedAt(time.Now().Add(-time.Minute))
+		})
+
+		It("leaves it for later", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(report.Orphans[0].Action).To(Equal(broker.OrphanPending))
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(0))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the grace period of the orphan has passed", func() {
+		BeforeEach(func() {
+			markedAt(time.Now().Add(-2 * time.Hour))
+		})
+
+		It("deletes the deployment and its UAA client", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(report.Orphans[0].Action).To(Equal(broker.OrphanDeletionStarted))
+			Expect(*report.Orphans[0].Operation).To(Equal(broker.OperationData{
+				BoshTaskID:    deleteTaskID,
+				OperationType: broker.OperationTypeDelete,
+			}))
+
+			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1))
+			_, deployment, _, force, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+			Expect(deployment).To(Equal(orphan))
+			Expect(force).To(BeFalse())
+
+			Expect(fakeUAAClient.DeleteClientCallCount()).To(Equal(1))
+			Expect(fakeUAAClient.DeleteClientArgsForCall(0)).To(Equal(orphanInstanceID))
+		})
+
+		When("the recorded plan has pre-delete errands", func() {
+			BeforeEach(func() {
+				boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+					Type:    broker.InstanceMetadataConfigType,
+					Name:    orphan,
+					Content: fmt.Sprintf(`{"plan_id":%q}`, preDeleteErrandPlanID),
+				}}, nil)
+				boshClient.RunErrandReturns(deleteTaskID, nil)
+			})
+
+			It("runs them before deleting the deployment", func() {
+				Expect(cleanupErr).NotTo(HaveOccurred())
+				Expect(report.Orphans[0].Operation.OperationType).To(Equal(broker.OperationTypeDelete))
+				Expect(report.Orphans[0].Operation.Errands).To(HaveLen(1))
+
+				Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+				_, deployment, errand, _, _, _, _ := boshClient.RunErrandArgsForCall(0)
+				Expect(deployment).To(Equal(orphan))
+				Expect(errand).To(Equal("cleanup-resources"))
+				Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+			})
+		})
+
+		When("an operation is in progress on the deployment", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{{State: boshdirector.TaskProcessing}}, nil)
+			})
+
+			It("reports it and deletes it on a later run", func() {
+				Expect(cleanupErr).NotTo(HaveOccurred())
+				Expect(report.Orphans[0].Action).To(Equal(broker.OrphanOperationInProgress))
+				Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the deletion fails", func() {
+			BeforeEach(func() {
+				boshClient.DeleteDeploymentReturns(0, errors.New("director unavailable"))
+			})
+
+			It("returns an error", func() {
+				Expect(cleanupErr).To(HaveOccurred())
+			})
+		})
+
+		When("it is a dry run", func() {
+			BeforeEach(func() {
+				options.DryRun = true
+			})
+
+			It("reports the deletion without deleting", func() {
+				Expect(cleanupErr).NotTo(HaveOccurred())
+				Expect(report.DryRun).To(BeTrue())
+				Expect(report.Orphans[0].Action).To(Equal(broker.OrphanDeletionStarted))
+				Expect(report.Orphans[0].Operation).To(BeNil())
+				Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	When("the orphan is allow-listed by instance ID", func() {
+		BeforeEach(func() {
+			options.AllowList = []string{orphanInstanceID}
+			markedAt(time.Now().Add(-2 * time.Hour))
+		})
+
+		It("neither marks nor deletes it", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(report.Orphans[0].Action).To(Equal(broker.OrphanAllowListed))
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(0))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+		})
+	})
+
+	When("it is a dry run", func() {
+		BeforeEach(func() {
+			options.DryRun = true
+		})
+
+		It("reports new orphans without marking them", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(report.Orphans[0].Action).To(Equal(broker.OrphanMarked))
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(0))
+		})
+	})
+
+	When("a marked deployment is no longer orphaned", func() {
+		BeforeEach(func() {
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{{
+				Type:    broker.OrphanMarkerConfigType,
+				Name:    deploymentName("adopted-instance"),
+				Content: fmt.Sprintf(`{"service_id":%q,"detected_at":"2020-01-01T00:00:00Z"}`, serviceOfferingID),
+			}}, nil)
+		})
+
+		It("removes its marker", func() {
+			Expect(cleanupErr).NotTo(HaveOccurred())
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+			_, configType, configName, _ := boshClient.DeleteConfigArgsForCall(0)
+			Expect(configType).To(Equal(broker.OrphanMarkerConfigType))
+			Expect(configName).To(Equal(deploymentName("adopted-instance")))
+		})
+	})
+
+	When("BOSH configs are disabled", func() {
+		BeforeEach(func() {
+			brokerConfig.DisableBoshConfigs = true
+			b = createDefaultBroker()
+		})
+
+		It("fails, as orphans cannot be marked", func() {
+			Expect(cleanupErr).To(MatchError("orphan cleanup requires BOSH configs to be enabled"))
+		})
+	})
+})
@@ -7,14 +7,17 @@
 package broker
 
 import (
+	"context"
 	"log"
 	"strings"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 )
 
-func (b *Broker) OrphanDeployments(logger *log.Logger) ([]string, error) {
-	rawInstances, err := b.Instances(nil, logger)
+func (b *Broker) OrphanDeployments(ctx context.Context, logger *log.Logger) ([]string, error) {
+	rawInstances, err := b.Instances(ctx, nil, logger)
 	if err != nil {
-		logger.Printf("error listing instances: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error listing instances: %s", err)
 		return nil, b.processError(err, logger)
 	}
 
@@ -23,9 +26,9 @@ func (b *Broker) OrphanDeployments(logger *log.Logger) ([]string, error) {
 		instanceIDs[instance.GUID] = true
 	}
 
-	deployments, err := b.boshClient.GetDeployments(logger)
+	deployments, err := b.boshClient.GetDeployments(ctx, logger)
 	if err != nil {
-		logger.Printf("error getting deployments: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error getting deployments: %s", err)
 		return nil, b.processError(err, logger)
 	}
 
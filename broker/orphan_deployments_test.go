@@ -7,6 +7,7 @@
 package broker_test
 
 import (
+	"context"
 	"errors"
 	"log"
 
@@ -30,7 +31,7 @@ var _ = Describe("Orphan Deployments", func() {
 	})
 
 	It("returns an empty list when there are no instances or deployments", func() {
-		orphans, orphanDeploymentsErr = b.OrphanDeployments(logger)
+		orphans, orphanDeploymentsErr = b.OrphanDeployments(context.Background(), logger)
 
 		Expect(orphanDeploymentsErr).NotTo(HaveOccurred())
 		Expect(orphans).To(BeEmpty())
@@ -40,7 +41,7 @@ var _ = Describe("Orphan Deployments", func() {
 		fakeInstanceLister.InstancesReturns([]service.Instance{{GUID: "one"}}, nil)
 		boshClient.GetDeploymentsReturns([]boshdirector.Deployment{{Name: "service-instance_one"}}, nil)
 
-		orphans, orphanDeploymentsErr = b.OrphanDeployments(logger)
+		orphans, orphanDeploymentsErr = b.OrphanDeployments(context.Background(), logger)
 
 		Expect(orphanDeploymentsErr).NotTo(HaveOccurred())
 		Expect(orphans).To(BeEmpty())
@@ -49,7 +50,7 @@ var _ = Describe("Orphan Deployments", func() {
 	It("returns a list of one orphan when there are no instances but one deployment", func() {
 		boshClient.GetDeploymentsReturns([]boshdirector.Deployment{{Name: "service-instance_one"}}, nil)
 
-		orphans, orphanDeploymentsErr = b.OrphanDeployments(logger)
+		orphans, orphanDeploymentsErr = b.OrphanDeployments(context.Background(), logger)
 
 		Expect(orphanDeploymentsErr).NotTo(HaveOccurred())
 		Expect(orphans).To(ConsistOf("service-instance_one"))
@@ -59,7 +60,7 @@ var _ = Describe("Orphan Deployments", func() {
 		deployments := []boshdirector.Deployment{{Name: "not-a-service-instance"}, {Name: "acme-deployment"}}
 		boshClient.GetDeploymentsReturns(deployments, nil)
 
-		orphans, orphanDeploymentsErr = b.OrphanDeployments(logger)
+		orphans, orphanDeploymentsErr = b.OrphanDeployments(context.Background(), logger)
 
 		Expect(orphanDeploymentsErr).NotTo(HaveOccurred())
 		Expect(orphans).To(BeEmpty())
@@ -68,7 +69,7 @@ var _ = Describe("Orphan Deployments", func() {
 	It("logs an error when getting the list of instances fails", func() {
 		fakeInstanceLister.InstancesReturns([]service.Instance{}, errors.New("error listing instances: listing error"))
 
-		orphans, orphanDeploymentsErr = b.OrphanDeployments(logger)
+		orphans, orphanDeploymentsErr = b.OrphanDeployments(context.Background(), logger)
 
 		Expect(orphanDeploymentsErr).To(HaveOccurred())
 		Expect(logBuffer.String()).To(ContainSubstring("error listing instances: listing error"))
@@ -77,7 +78,7 @@ var _ = Describe("Orphan Deployments", func() {
 	It("logs an error when getting the list of deployments fails", func() {
 		boshClient.GetDeploymentsReturns([]boshdirector.Deployment{}, errors.New("error getting deployments: get deployment error"))
 
-		orphans, orphanDeploymentsErr = b.OrphanDeployments(logger)
+		orphans, orphanDeploymentsErr = b.OrphanDeployments(context.Background(), logger)
 
 		Expect(orphanDeploymentsErr).To(HaveOccurred())
 		Expect(logBuffer.String()).To(ContainSubstring("error getting deployments: get deployment error"))
@@ -0,0 +1,113 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+const RedactedValue = "<redacted>"
+
+// ManifestDiff describes how the manifest generated for an operation differs
+// from the deployed one. Each change is identified by a path in the style of
+// BOSH ops files, such as /instance_groups/name=redis/instances.
+type ManifestDiff struct {
+	Releases       []ManifestChange `json:"releases"`
+	Stemcells      []ManifestChange `json:"stemcells"`
+	InstanceGroups []ManifestChange `json:"instance_groups"`
+	Properties     []ManifestChange `json:"properties"`
+}
+
+// ManifestChange has no Before when something is added and no After when it is
+// removed. Values of properties that look like credentials are redacted.
+type ManifestChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+func (d ManifestDiff) Empty() bool {
+	return len(d.Releases) == 0 && len(d.Stemcells) == 0 && len(d.InstanceGroups) == 0 && len(d.Properties) == 0
+}
+
+// PreviewUpgrade generates the manifest an upgrade would deploy and compares it
+// with the deployed manifest. Nothing is deployed, and no UAA client is created.
+func (b *Broker) PreviewUpgrade(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (ManifestDiff, error) {
+	logger.Printf("previewing upgrade of instance %s", instanceID)
+
+	if details.PlanID == "" {
+		return ManifestDiff{}, b.processError(errors.New("no plan ID provided in upgrade request body"), logger)
+	}
+
+	plan, found := b.offering().FindPlanByID(details.PlanID)
+	if !found {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error: finding plan ID %s", details.PlanID)
+		return ManifestDiff{}, b.processError(fmt.Errorf("plan %s not found", details.PlanID), logger)
+	}
+
+	rawCtx, err := convertToMap(details.RawContext)
+	if err != nil {
+		return ManifestDiff{}, b.processError(fmt.Errorf("invalid request context"), logger)
+	}
+
+	instanceClient, err := b.uaaClient.GetClient(instanceID)
+	if err != nil {
+		return ManifestDiff{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+
+	diff, err := b.deployer.PreviewUpgrade(ctx, deploymentName(instanceID), plan, map[string]interface{}{"context": rawCtx}, instanceClient, logger)
+	if err != nil {
+		_, err := b.handleUpdateError(ctx, err, logger)
+		return ManifestDiff{}, err
+	}
+	return diff, nil
+}
+
+// PreviewUpdate generates the manifest an update would deploy and compares it
+// with the deployed manifest. Nothing is deployed, and no UAA client is created.
+func (b *Broker) PreviewUpdate(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (ManifestDiff, error) {
+	logger.Printf("previewing update of instance %s", instanceID)
+
+	plan, err := findPlanInCatalog(b.offering(), details)
+	if err != nil {
+		return ManifestDiff{}, b.processError(err, logger)
+	}
+
+	detailsMap, err := convertDetailsToMap(domain.DetailsWithRawParameters(details))
+	if err != nil {
+		return ManifestDiff{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+
+	instanceClient, err := b.uaaClient.GetClient(instanceID)
+	if err != nil {
+		return ManifestDiff{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+
+	secretMap, err := b.getSecretMap(ctx, instanceID, logger)
+	if err != nil {
+		return ManifestDiff{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+
+	previousPlanID := details.PreviousValues.PlanID
+	if previousPlanID == "" {
+		previousPlanID = plan.ID
+	}
+
+	diff, err := b.deployer.PreviewUpdate(ctx, deploymentName(instanceID), plan.ID, detailsMap, &previousPlanID, secretMap, instanceClient, logger)
+	if err != nil {
+		_, err := b.handleUpdateError(ctx, err, logger)
+		return ManifestDiff{}, err
+	}
+	return diff, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("Preview", func() {
+	var (
+		instanceID     = "some-instance"
+		instanceClient = map[string]string{"client_id": "some-id"}
+		expectedDiff   = broker.ManifestDiff{
+			Releases: []broker.ManifestChange{{Path: "/releases/name=redis/version", Before: "1", After: "2"}},
+		}
+	)
+
+	BeforeEach(func() {
+		b = createDefaultBroker()
+		fakeUAAClient.GetClientReturns(instanceClient, nil)
+	})
+
+	Describe("PreviewUpgrade", func() {
+		BeforeEach(func() {
+			fakeDeployer.PreviewUpgradeReturns(expectedDiff, nil)
+		})
+
+		It("returns the difference the upgrade would make", func() {
+			diff, err := b.PreviewUpgrade(context.Background(), instanceID, domain.UpdateDetails{
+				PlanID:     existingPlanID,
+				RawContext: []byte(`{"space_guid":"some-space"}`),
+			}, loggerFactory.New())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal(expectedDiff))
+
+			_, actualDeploymentName, actualPlan, actualRequestParams, actualClient, _ := fakeDeployer.PreviewUpgradeArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal("service-instance_" + instanceID))
+			Expect(actualPlan).To(Equal(existingPlan))
+			Expect(actualRequestParams).To(Equal(map[string]interface{}{"context": map[string]interface{}{"space_guid": "some-space"}}))
+			Expect(actualClient).To(Equal(instanceClient))
+
+			Expect(fakeDeployer.UpgradeCallCount()).To(BeZero())
+			Expect(fakeUAAClient.CreateClientCallCount()).To(BeZero())
+		})
+
+		It("fails when the plan does not exist", func() {
+			_, err := b.PreviewUpgrade(context.Background(), instanceID, domain.UpdateDetails{PlanID: "not-a-plan"}, loggerFactory.New())
+
+			Expect(err).To(MatchError(ContainSubstring("plan not-a-plan not found")))
+			Expect(fakeDeployer.PreviewUpgradeCallCount()).To(BeZero())
+		})
+
+		It("returns the deployment not found error from the deployer", func() {
+			fakeDeployer.PreviewUpgradeReturns(broker.ManifestDiff{}, broker.NewDeploymentNotFoundError(errors.New("gone")))
+
+			_, err := b.PreviewUpgrade(context.Background(), instanceID, domain.UpdateDetails{PlanID: existingPlanID}, loggerFactory.New())
+
+			Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+		})
+	})
+
+	Describe("PreviewUpdate", func() {
+		BeforeEach(func() {
+			fakeDeployer.PreviewUpdateReturns(expectedDiff, nil)
+			fakeSecretManager.ResolveManifestSecretsReturns(map[string]string{"((foo))": "bar"}, nil)
+		})
+
+		It("returns the difference the update would make", func() {
+			diff, err := b.PreviewUpdate(context.Background(), instanceID, domain.UpdateDetails{
+				PlanID:         secondPlanID,
+				RawParameters:  []byte(`{"foo":"bar"}`),
+				PreviousValues: domain.PreviousValues{PlanID: existingPlanID},
+			}, loggerFactory.New())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal(expectedDiff))
+
+			_, actualDeploymentName, actualPlanID, actualRequestParams, actualPreviousPlanID, actualSecrets, actualClient, _ := fakeDeployer.PreviewUpdateArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal("service-instance_" + instanceID))
+			Expect(actualPlanID).To(Equal(secondPlanID))
+			Expect(actualRequestParams).To(HaveKeyWithValue("parameters", map[string]interface{}{"foo": "bar"}))
+			Expect(*actualPreviousPlanID).To(Equal(existingPlanID))
+			Expect(actualSecrets).To(Equal(map[string]string{"((foo))": "bar"}))
+			Expect(actualClient).To(Equal(instanceClient))
+
+			Expect(fakeDeployer.UpdateCallCount()).To(BeZero())
+		})
+
+		It("compares with the current plan when no previous plan is given", func() {
+			_, err := b.PreviewUpdate(context.Background(), instanceID, domain.UpdateDetails{PlanID: existingPlanID}, loggerFactory.New())
+
+			Expect(err).NotTo(HaveOccurred())
+			_, _, _, _, actualPreviousPlanID, _, _, _ := fakeDeployer.PreviewUpdateArgsForCall(0)
+			Expect(*actualPreviousPlanID).To(Equal(existingPlanID))
+		})
+
+		It("fails when the secrets cannot be resolved", func() {
+			fakeSecretManager.ResolveManifestSecretsReturns(nil, errors.New("credhub down"))
+
+			_, err := b.PreviewUpdate(context.Background(), instanceID, domain.UpdateDetails{PlanID: existingPlanID}, loggerFactory.New())
+
+			Expect(err).To(HaveOccurred())
+			Expect(fakeDeployer.PreviewUpdateCallCount()).To(BeZero())
+		})
+	})
+})
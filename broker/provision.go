@@ -37,13 +37,17 @@ func (b *Broker) Provision(
 	instanceID string,
 	details domain.ProvisionDetails,
 	asyncAllowed bool,
-) (domain.ProvisionedServiceSpec, error) {
-	b.deploymentLock.Lock()
-	defer b.deploymentLock.Unlock()
+) (_ domain.ProvisionedServiceSpec, err error) {
+	defer b.deploymentLocks.lock(instanceID)()
 
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeCreate), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeCreate), requestID, b.offering().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
+	defer func() {
+		if err != nil {
+			b.notifyRequestFailed(ctx, OperationTypeCreate, instanceID, details.PlanID, err)
+		}
+	}()
 
 	if !asyncAllowed {
 		return domain.ProvisionedServiceSpec{}, b.processError(apiresponses.ErrAsyncRequired, logger)
@@ -64,7 +68,7 @@ func (b *Broker) Provision(
 		return domain.ProvisionedServiceSpec{}, b.processError(err, logger)
 	}
 
-	_, err = b.boshClient.GetInfo(logger)
+	_, err = b.boshClient.GetInfo(ctx, logger)
 	if err != nil {
 		return domain.ProvisionedServiceSpec{}, b.processError(NewBoshRequestError("create", err), logger)
 	}
@@ -83,6 +87,9 @@ func (b *Broker) Provision(
 		instanceName,
 		logger,
 	)
+	if _, ok := err.(OperationAlreadyCompletedError); ok {
+		return domain.ProvisionedServiceSpec{AlreadyExists: true}, nil
+	}
 	if err != nil {
 		return domain.ProvisionedServiceSpec{}, b.processError(err, logger)
 	}
@@ -108,7 +115,8 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, detai
 		return OperationData{}, "", nil, err
 	}
 
-	plan, found := b.serviceOffering.FindPlanByID(planID)
+	serviceOffering := b.offering()
+	plan, found := serviceOffering.FindPlanByID(planID)
 	if !found {
 		return errs(NewDisplayableError(
 			fmt.Errorf("plan %s not found", planID),
@@ -116,7 +124,7 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, detai
 		))
 	}
 
-	_, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	_, found, err := b.boshClient.GetDeployment(ctx, deploymentName(instanceID), logger)
 	switch err := err.(type) {
 	case boshdirector.RequestError:
 		return errs(NewBoshRequestError("create", fmt.Errorf("could not get manifest: %s", err)))
@@ -125,23 +133,24 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, detai
 	}
 
 	if found {
-		return errs(NewDisplayableError(
-			apiresponses.ErrInstanceAlreadyExists,
-			fmt.Errorf("deploying instance %s", instanceID),
-		))
+		operationData, err := b.provisionRetry(ctx, instanceID, serviceOffering, plan, requestParams, logger)
+		return operationData, "", nil, err
 	}
 
-	cfPlanCounts, err := b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+	contextMap, _ := requestParams["context"].(map[string]interface{})
+	quotaReserved, err := b.checkAndReserveQuotas(ctx, instanceID, serviceOffering, plan, "", contextMap, logger)
 	if err != nil {
-		return errs(NewGenericError(ctx, err))
+		return errs(err)
 	}
 
-	quotasErrors, ok := b.checkQuotas(ctx, plan, cfPlanCounts, b.serviceOffering.ID, logger)
-	if !ok {
-		return errs(quotasErrors)
-	}
+	deployed := false
+	defer func() {
+		if !deployed {
+			b.releaseQuotaReservation(ctx, instanceID, OperationData{QuotaReserved: quotaReserved}, logger)
+		}
+	}()
 
-	if err := b.checkPlanSchemas(ctx, requestParams, plan, logger); err != nil {
+	if err := b.checkPlanSchemas(ctx, requestParams, serviceOffering, plan, logger); err != nil {
 		return errs(err)
 	}
 
@@ -163,6 +172,7 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, detai
 	}
 
 	boshTaskID, manifest, brokerLabels, err := b.deployer.Create(
+		ctx,
 		deploymentName(instanceID),
 		plan.ID,
 		requestParams,
@@ -175,17 +185,27 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, detai
 		return errs(NewBoshRequestError("create", err))
 	case DisplayableError:
 		return errs(err)
-	case serviceadapter.UnknownFailureError:
+	case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError:
 		return errs(adapterToAPIError(ctx, err))
 	case error:
 		return errs(NewGenericError(ctx, err))
 	}
 
+	deployed = true
 	ctx = brokercontext.WithBoshTaskID(ctx, boshTaskID)
 
-	abridgedPlan := plan.AdapterPlan(b.serviceOffering.GlobalProperties)
+	record := newOperationRecord(ctx, OperationTypeCreate, "", plan.ID, boshTaskID, boshContextID)
+	b.recordInstanceMetadata(ctx, instanceID, InstanceMetadata{}.
+		withPlan(plan.ID).
+		withOrgAndSpace(requestParams).
+		mergeParameters(requestParams).
+		withLabels(brokerLabels).
+		withOperation(record), logger)
+	b.notifyOperationStarted(instanceID, record)
+
+	abridgedPlan := plan.AdapterPlan(serviceOffering.GlobalProperties)
 
-	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(instanceID, abridgedPlan, manifest, logger)
+	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(ctx, instanceID, abridgedPlan, manifest, logger)
 	if err != nil {
 		logger.Printf("generating dashboard: %v\n", err)
 	}
@@ -195,6 +215,7 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, detai
 		OperationType: OperationTypeCreate,
 		BoshContextID: boshContextID,
 		Errands:       plan.PostDeployErrands(),
+		QuotaReserved: quotaReserved,
 	}
 
 	// Dashboard url optional
@@ -214,13 +235,13 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, detai
 	return operationData, dashboardUrl, brokerLabels, nil
 }
 
-func (b *Broker) checkPlanSchemas(ctx context.Context, requestParams map[string]interface{}, plan config.Plan, logger *log.Logger) error {
+func (b *Broker) checkPlanSchemas(ctx context.Context, requestParams map[string]interface{}, serviceOffering config.ServiceOffering, plan config.Plan, logger *log.Logger) error {
 	if b.EnablePlanSchemas {
 		var schemas domain.ServiceSchemas
-		schemas, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		schemas, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(serviceOffering.GlobalProperties), logger)
 		if err != nil {
 			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
-				return err
+				return adapterError(ctx, err, err)
 			}
 			logger.Println("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas")
 			return fmt.Errorf("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas")
@@ -11,7 +11,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
@@ -104,7 +106,7 @@ var _ = Describe("Provisioning", func() {
 
 		BeforeEach(func() {
 			newlyGeneratedManifest = []byte("a newly generated manifest")
-			fakeDeployer.CreateReturns(deployTaskID, newlyGeneratedManifest, nil, nil)
+			fakeDeployer.CreateReturns(deployTaskID, newlyGeneratedManifest, map[string]any{"tier": "gold", "replicas": 3}, nil)
 		})
 
 		It("returns expected operation data", func() {
@@ -126,7 +128,7 @@ var _ = Describe("Provisioning", func() {
 
 			By("invoking the deployer", func() {
 				Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
-				actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, actualClient, _ := fakeDeployer.CreateArgsForCall(0)
+				_, actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, actualClient, _ := fakeDeployer.CreateArgsForCall(0)
 				Expect(actualRequestParams).To(Equal(map[string]interface{}{
 					"plan_id":           planID,
 					"context":           arbContext,
@@ -142,12 +144,44 @@ var _ = Describe("Provisioning", func() {
 				Expect(actualClient).To(Equal(expectedClient))
 			})
 
+			By("reserving quota for the instance", func() {
+				Expect(boshClient.UpdateConfigCallCount()).To(Equal(2))
+				_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(0)
+				Expect(configType).To(Equal(broker.QuotaReservationConfigType))
+				Expect(configName).To(Equal(deploymentName(instanceID)))
+				var reservation broker.QuotaReservation
+				Expect(json.Unmarshal(content, &reservation)).To(Succeed())
+				Expect(reservation.PlanID).To(Equal("some-plan-id"))
+				Expect(reservation.CreatedAt).NotTo(BeZero())
+			})
+
+			By("recording the instance metadata", func() {
+				_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(1)
+				Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+				Expect(configName).To(Equal(deploymentName(instanceID)))
+				var metadata broker.InstanceMetadata
+				Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+				Expect(metadata.PlanID).To(Equal("some-plan-id"))
+				Expect(metadata.OrgGUID).To(Equal(organizationGUID))
+				Expect(metadata.SpaceGUID).To(Equal(spaceGUID))
+				Expect(metadata.Parameters).To(Equal(map[string]interface{}{"foo": "bar"}))
+				Expect(metadata.Labels).To(Equal(map[string]string{"tier": "gold", "replicas": "3"}))
+				Expect(metadata.Operations).To(HaveLen(1))
+				Expect(metadata.Operations[0].Type).To(Equal(broker.OperationTypeCreate))
+				Expect(metadata.Operations[0].PlanIDAfter).To(Equal("some-plan-id"))
+				Expect(metadata.Operations[0].BoshTaskID).To(Equal(deployTaskID))
+				Expect(metadata.Operations[0].RequestID).NotTo(BeEmpty())
+				Expect(metadata.CreatedAt).NotTo(BeNil())
+				Expect(*metadata.CreatedAt).To(Equal(metadata.Operations[0].StartedAt))
+			})
+
 			var operationData broker.OperationData
 			Expect(json.Unmarshal([]byte(serviceSpec.OperationData), &operationData)).To(Succeed())
 			Expect(operationData.BoshTaskID).To(Equal(deployTaskID))
 			Expect(operationData.OperationType).To(Equal(broker.OperationTypeCreate))
 			Expect(operationData.PlanID).To(BeEmpty())
 			Expect(operationData.BoshContextID).To(BeEmpty())
+			Expect(operationData.QuotaReserved).To(BeTrue())
 		})
 
 		Context("Handling dashboard url generation", func() {
@@ -162,7 +196,7 @@ var _ = Describe("Provisioning", func() {
 
 				By("invoking the adapter with the right arguments", func() {
 					Expect(serviceAdapter.GenerateDashboardUrlCallCount()).To(Equal(1))
-					instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
+					_, instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
 					Expect(instanceID).To(Equal(instanceID))
 					expectedProperties := sdk.Properties{"super": "no", "a_global_property": "global_value", "some_other_global_property": "other_global_value"}
 					Expect(plan).To(Equal(sdk.Plan{
@@ -277,7 +311,7 @@ var _ = Describe("Provisioning", func() {
 
 			By("calling the deployer with a bosh context id", func() {
 				Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
-				_, _, _, actualBoshContextID, _, _ := fakeDeployer.CreateArgsForCall(0)
+				_, _, _, _, actualBoshContextID, _, _ := fakeDeployer.CreateArgsForCall(0)
 				Expect(actualBoshContextID).NotTo(BeEmpty())
 			})
 		})
@@ -288,10 +322,10 @@ var _ = Describe("Provisioning", func() {
 
 			Expect(secondProvisionErr).NotTo(HaveOccurred())
 			Expect(fakeDeployer.CreateCallCount()).To(Equal(2))
-			_, _, _, firstBoshContextID, _, _ := fakeDeployer.CreateArgsForCall(0)
+			_, _, _, _, firstBoshContextID, _, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(firstBoshContextID).NotTo(BeNil())
 
-			_, _, _, secondBoshContextID, _, _ := fakeDeployer.CreateArgsForCall(1)
+			_, _, _, _, secondBoshContextID, _, _ := fakeDeployer.CreateArgsForCall(1)
 			Expect(secondBoshContextID).NotTo(Equal(firstBoshContextID))
 		})
 	})
@@ -358,7 +392,7 @@ var _ = Describe("Provisioning", func() {
 			serviceSpec, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
 
 			Expect(provisionErr).NotTo(HaveOccurred())
-			_, _, actualRequestParams, _, _, _ := fakeDeployer.CreateArgsForCall(0)
+			_, _, _, actualRequestParams, _, _, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(actualRequestParams["parameters"]).To(HaveLen(0))
 			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
 		})
@@ -445,6 +479,18 @@ var _ = Describe("Provisioning", func() {
 		})
 	})
 
+	Context("when the service adapter times out", func() {
+		It("tells the operator which subcommand timed out", func() {
+			fakeDeployer.CreateReturns(0, nil, nil, serviceadapter.TimeoutError{Subcommand: "generate-manifest", Timeout: 30 * time.Second})
+
+			serviceSpec, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("Timed out waiting for the service adapter, please try again later")))
+			Expect(provisionErr).NotTo(MatchError(ContainSubstring("generate-manifest")))
+			Expect(logBuffer.String()).To(ContainSubstring("service adapter timed out running generate-manifest after 30s"))
+		})
+	})
+
 	Context("when a provision of an already provisioned instance is triggered", func() {
 		It("returns an error", func() {
 			boshClient.GetDeploymentReturns([]byte(`manifest: true`), true, nil)
@@ -453,6 +499,79 @@ var _ = Describe("Provisioning", func() {
 
 			Expect(provisionErr).To(Equal(apiresponses.ErrInstanceAlreadyExists))
 		})
+
+		When("the request repeats the one that created the instance", func() {
+			var recordedParams string
+
+			BeforeEach(func() {
+				recordedParams = `{"foo":"bar"}`
+				boshClient.GetDeploymentReturns([]byte(`manifest: true`), true, nil)
+			})
+
+			JustBeforeEach(func() {
+				boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+					Type: broker.InstanceMetadataConfigType,
+					Name: "service-instance_some-instance-id",
+					Content: fmt.Sprintf(
+						`{"plan_id":%q,"organization_guid":%q,"space_guid":%q,"parameters":%s,"operations":[{"type":"create","plan_id_after":%q,"bosh_task_id":42}]}`,
+						existingPlanID, organizationGUID, spaceGUID, recordedParams, existingPlanID,
+					),
+				}}, nil)
+			})
+
+			It("returns the operation of the create while it is in progress", func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskProcessing}, nil)
+
+				serviceSpec, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+				Expect(provisionErr).NotTo(HaveOccurred())
+				Expect(serviceSpec.IsAsync).To(BeTrue())
+				Expect(serviceSpec.AlreadyExists).To(BeFalse())
+				var operationData broker.OperationData
+				Expect(json.Unmarshal([]byte(serviceSpec.OperationData), &operationData)).To(Succeed())
+				Expect(operationData).To(Equal(broker.OperationData{
+					BoshTaskID:    42,
+					OperationType: broker.OperationTypeCreate,
+					QuotaReserved: true,
+				}))
+				_, taskID, _ := boshClient.GetTaskArgsForCall(0)
+				Expect(taskID).To(Equal(42))
+				Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+			})
+
+			It("reports that the instance already exists once the create has succeeded", func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskDone}, nil)
+
+				serviceSpec, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+				Expect(provisionErr).NotTo(HaveOccurred())
+				Expect(serviceSpec.AlreadyExists).To(BeTrue())
+				Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+			})
+
+			It("returns an error when the create failed", func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskError}, nil)
+
+				serviceSpec, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+				Expect(provisionErr).To(Equal(apiresponses.ErrInstanceAlreadyExists))
+			})
+
+			When("the parameters differ from the recorded ones", func() {
+				BeforeEach(func() {
+					recordedParams = `{"foo":"baz"}`
+				})
+
+				It("returns an error", func() {
+					boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 42, State: boshdirector.TaskProcessing}, nil)
+
+					serviceSpec, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+					Expect(provisionErr).To(Equal(apiresponses.ErrInstanceAlreadyExists))
+					Expect(boshClient.GetTaskCallCount()).To(BeZero())
+				})
+			})
+		})
 	})
 
 	When("creating the uaa client fails", func() {
@@ -1035,6 +1154,232 @@ var _ = Describe("Provisioning", func() {
 		})
 	})
 
+	Describe("quota reservations", func() {
+		reservationConfig := func(instanceID, planID string, createdAt time.Time) boshdirector.BoshConfig {
+			content, err := json.Marshal(broker.QuotaReservation{PlanID: planID, CreatedAt: createdAt})
+			Expect(err).NotTo(HaveOccurred())
+			return boshdirector.BoshConfig{
+				Type:    broker.QuotaReservationConfigType,
+				Name:    deploymentName(instanceID),
+				Content: string(content),
+			}
+		}
+
+		BeforeEach(func() {
+			cfClient.CountInstancesOfServiceOfferingReturns(map[cf.ServicePlan]int{
+				cfServicePlan("1234", existingPlanID, "url", "name"): existingPlanServiceInstanceLimit - 1,
+			}, nil)
+			fakeDeployer.CreateReturns(deployTaskID, []byte("a manifest"), nil, nil)
+		})
+
+		It("counts the creates still in flight against the quotas", func() {
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+				reservationConfig("in-flight-instance", existingPlanID, time.Now()),
+			}, nil)
+
+			_, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("plan instance limit exceeded for service ID: service-id. Total instances: 3")))
+			Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+			_, configType, _ := boshClient.GetConfigsOfTypeArgsForCall(0)
+			Expect(configType).To(Equal(broker.QuotaReservationConfigType))
+		})
+
+		It("counts a create that completes while the instances are counted", func() {
+			reservations := []boshdirector.BoshConfig{reservationConfig("completing-instance", existingPlanID, time.Now())}
+			boshClient.GetConfigsOfTypeStub = func(context.Context, string, *log.Logger) ([]boshdirector.BoshConfig, error) {
+				return reservations, nil
+			}
+			cfClient.CountInstancesOfServiceOfferingStub = func(context.Context, string, *log.Logger) (map[cf.ServicePlan]int, error) {
+				reservations = nil
+				return map[cf.ServicePlan]int{
+					cfServicePlan("1234", existingPlanID, "url", "name"): existingPlanServiceInstanceLimit - 1,
+				}, nil
+			}
+
+			_, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("plan instance limit exceeded")))
+			Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+		})
+
+		It("ignores expired reservations and the reservation of the instance itself", func() {
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+				reservationConfig("stuck-instance", existingPlanID, time.Now().Add(-25*time.Hour)),
+				reservationConfig(instanceID, existingPlanID, time.Now()),
+			}, nil)
+
+			_, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
+		})
+
+		It("ignores the reservations of other service offerings", func() {
+			globalLimit := existingPlanServiceInstanceLimit
+			catalog := serviceCatalog
+			catalog.GlobalQuotas = config.Quotas{ServiceInstanceLimit: &globalLimit}
+			b = createBrokerWithServiceCatalog(catalog)
+			boshClient.GetConfigsOfTypeReturns([]boshdirector.BoshConfig{
+				reservationConfig("other-offering-instance", "plan-of-another-offering", time.Now()),
+			}, nil)
+
+			_, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
+		})
+
+		It("fails when the reservations cannot be read", func() {
+			boshClient.GetConfigsOfTypeReturns(nil, errors.New("bosh is down"))
+
+			_, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+		})
+
+		It("releases the reservation when the deployment is not submitted", func() {
+			fakeDeployer.CreateReturns(0, nil, nil, errors.New("adapter failed"))
+
+			_, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).To(HaveOccurred())
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+			_, configType, configName, _ := boshClient.DeleteConfigArgsForCall(0)
+			Expect(configType).To(Equal(broker.QuotaReservationConfigType))
+			Expect(configName).To(Equal(deploymentName(instanceID)))
+		})
+
+		It("does not reserve quota when BOSH configs are disabled", func() {
+			b.DisableBoshConfigs = true
+
+			serviceSpec, provisionErr = b.Provision(context.Background(), instanceID, provisionDetails, asyncAllowed)
+
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(boshClient.GetConfigsOfTypeCallCount()).To(BeZero())
+			Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+
+			var operationData broker.OperationData
+			Expect(json.Unmarshal([]byte(serviceSpec.OperationData), &operationData)).To(Succeed())
+			Expect(operationData.QuotaReserved).To(BeFalse())
+		})
+	})
+
+	Describe("org and space quotas", func() {
+		var (
+			catalog      config.ServiceOffering
+			provisionErr error
+		)
+
+		BeforeEach(func() {
+			arbContext = map[string]interface{}{"platform": "cloudfoundry", "organization_guid": "an-org", "space_guid": "a-space"}
+			var err error
+			jsonContext, err = json.Marshal(arbContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			one, two := 1, 2
+			existing := existingPlan
+			existing.Quotas = config.Quotas{Resources: map[string]config.ResourceQuota{"memory": {Cost: 4}}}
+			catalog = serviceCatalog
+			catalog.Plans = config.Plans{existing, secondPlan}
+			catalog.GlobalQuotas = config.Quotas{
+				Orgs: &config.ScopedQuotas{
+					Default:   config.Quotas{ServiceInstanceLimit: &two},
+					Overrides: map[string]config.Quotas{"a-big-org": {ServiceInstanceLimit: &one}},
+				},
+				Spaces: &config.ScopedQuotas{
+					Default: config.Quotas{Resources: map[string]config.ResourceQuota{"memory": {Limit: 8}}},
+				},
+			}
+
+			cfClient.CountInstancesOfServiceOfferingReturns(map[cf.ServicePlan]int{}, nil)
+			boshClient.GetDeploymentReturns(nil, false, nil)
+		})
+
+		provision := func(counts cf.OrgAndSpaceInstanceCounts) error {
+			cfClient.CountInstancesOfServiceOfferingByOrgAndSpaceReturns(counts, nil)
+			fakeDeployer = new(brokerfakes.FakeDeployer)
+			b = createBrokerWithServiceCatalog(catalog)
+
+			_, err := b.Provision(context.Background(), instanceID, domain.ProvisionDetails{
+				PlanID:     existingPlanID,
+				RawContext: jsonContext,
+				ServiceID:  serviceOfferingID,
+			}, true)
+			return err
+		}
+
+		planCounts := func(count int) map[cf.ServicePlan]int {
+			return map[cf.ServicePlan]int{cfServicePlan("1234", existingPlanID, "url", "name"): count}
+		}
+
+		It("succeeds when neither the org nor the space quota is reached", func() {
+			provisionErr = provision(cf.OrgAndSpaceInstanceCounts{
+				Orgs:   map[string]map[cf.ServicePlan]int{"an-org": planCounts(1), "another-org": planCounts(5)},
+				Spaces: map[string]map[cf.ServicePlan]int{"a-space": planCounts(1)},
+			})
+
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
+		})
+
+		It("fails when the default org instance limit is reached", func() {
+			provisionErr = provision(cf.OrgAndSpaceInstanceCounts{
+				Orgs: map[string]map[cf.ServicePlan]int{"an-org": planCounts(2)},
+			})
+
+			Expect(provisionErr).To(MatchError("org instance limit exceeded for org GUID: an-org. Total instances: 2"))
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(0))
+		})
+
+		It("fails when the space resource quota would be exceeded", func() {
+			provisionErr = provision(cf.OrgAndSpaceInstanceCounts{
+				Orgs:   map[string]map[cf.ServicePlan]int{"an-org": planCounts(1)},
+				Spaces: map[string]map[cf.ServicePlan]int{"a-space": planCounts(2)},
+			})
+
+			Expect(provisionErr).To(MatchError("space a-space quotas [memory: (limit 8, used 8, requires 4)] would be exceeded by this deployment"))
+		})
+
+		It("applies the quotas of an org that overrides the default", func() {
+			arbContext["organization_guid"] = "a-big-org"
+			var err error
+			jsonContext, err = json.Marshal(arbContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			provisionErr = provision(cf.OrgAndSpaceInstanceCounts{
+				Orgs: map[string]map[cf.ServicePlan]int{"a-big-org": planCounts(1)},
+			})
+
+			Expect(provisionErr).To(MatchError("org instance limit exceeded for org GUID: a-big-org. Total instances: 1"))
+		})
+
+		It("does not count instances by org when there are no org or space quotas", func() {
+			catalog.GlobalQuotas = config.Quotas{}
+
+			provisionErr = provision(cf.OrgAndSpaceInstanceCounts{})
+
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(cfClient.CountInstancesOfServiceOfferingByOrgAndSpaceCallCount()).To(Equal(0))
+		})
+
+		It("fails when the instances cannot be counted", func() {
+			cfClient.CountInstancesOfServiceOfferingByOrgAndSpaceReturns(cf.OrgAndSpaceInstanceCounts{}, errors.New("cf is down"))
+			fakeDeployer = new(brokerfakes.FakeDeployer)
+			b = createBrokerWithServiceCatalog(catalog)
+
+			_, provisionErr = b.Provision(context.Background(), instanceID, domain.ProvisionDetails{
+				PlanID:     existingPlanID,
+				RawContext: jsonContext,
+				ServiceID:  serviceOfferingID,
+			}, true)
+
+			Expect(provisionErr).To(MatchError(ContainSubstring("There was a problem completing your request")))
+			Expect(logBuffer.String()).To(ContainSubstring("cf is down"))
+		})
+	})
+
 	Describe("Maintenance info", func() {
 		BeforeEach(func() {
 			newlyGeneratedManifest := []byte("a newly generated manifest")
@@ -0,0 +1,184 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+)
+
+// QuotaReservationConfigType is the BOSH config type under which the broker
+// reserves quota for a create or plan change that is still in flight. The
+// instance counts of the CF API do not include such operations until they
+// complete, so reservations are added to them when checking quotas. The config
+// is named after the deployment, like the instance metadata.
+const QuotaReservationConfigType = "odb-quota-reservation"
+
+// quotaReservationExpiry bounds how long a reservation is honoured, so that
+// quota is not held forever for an operation the platform stopped polling.
+const quotaReservationExpiry = 24 * time.Hour
+
+type QuotaReservation struct {
+	PlanID         string    `json:"plan_id"`
+	PreviousPlanID string    `json:"previous_plan_id,omitempty"`
+	OrgGUID        string    `json:"organization_guid,omitempty"`
+	SpaceGUID      string    `json:"space_guid,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// checkAndReserveQuotas checks the quotas for instanceID to be deployed with
+// plan of serviceOffering, counting the operations still in flight, and reserves quota for it
+// until its operation completes. When the instance already exists on
+// previousPlanID, it is counted as moving to plan. Checks and reservations are
+// serialised, so that concurrent operations can't all see the same quota as
+// free. It returns whether quota was reserved.
+//
+// The reservations are read before the instances are counted. A reservation
+// is released once its operation is complete, so an operation completing in
+// between is counted by CF, by its reservation or by both, but never missed.
+func (b *Broker) checkAndReserveQuotas(ctx context.Context, instanceID string, serviceOffering config.ServiceOffering, plan config.Plan, previousPlanID string, contextMap map[string]interface{}, logger *log.Logger) (bool, error) {
+	reserve := quotasEnabled(serviceOffering) && !b.DisableBoshConfigs
+	var reservations map[string]QuotaReservation
+	if reserve {
+		b.quotaLock.Lock()
+		defer b.quotaLock.Unlock()
+
+		var err error
+		reservations, err = b.getQuotaReservations(ctx, serviceOffering, logger)
+		if err != nil {
+			return false, NewGenericError(ctx, err)
+		}
+		delete(reservations, instanceID)
+	}
+
+	_, span := tracing.Start(ctx, "count-instances")
+	cfPlanCounts, err := b.instanceCounter.CountInstancesOfServiceOffering(ctx, serviceOffering.ID, logger)
+	span.End(err)
+	if err != nil {
+		return false, NewGenericError(ctx, err)
+	}
+
+	scopedCounts, err := b.countInstancesByOrgAndSpace(ctx, serviceOffering, logger)
+	if err != nil {
+		return false, NewGenericError(ctx, err)
+	}
+
+	quotasErrors, ok := checkQuotas(serviceOffering, plan, cfPlanCounts, reservations)
+	if !ok {
+		return false, quotasErrors
+	}
+
+	if err := checkOrgAndSpaceQuotas(serviceOffering, plan, previousPlanID, contextMap, scopedCounts, reservations); err != nil {
+		return false, err
+	}
+
+	if !reserve {
+		return false, nil
+	}
+
+	reservation := QuotaReservation{
+		PlanID:         plan.ID,
+		PreviousPlanID: previousPlanID,
+		OrgGUID:        getOrgGUIDFromContext(contextMap),
+		SpaceGUID:      getSpaceGUIDFromContext(contextMap),
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := b.saveQuotaReservation(ctx, instanceID, reservation, logger); err != nil {
+		return false, NewGenericError(ctx, err)
+	}
+	return true, nil
+}
+
+func quotasEnabled(serviceOffering config.ServiceOffering) bool {
+	global := serviceOffering.GlobalQuotas
+	if global.ServiceInstanceLimit != nil || len(global.Resources) > 0 || global.Orgs != nil || global.Spaces != nil {
+		return true
+	}
+	for _, plan := range serviceOffering.Plans {
+		if plan.Quotas.ServiceInstanceLimit != nil || len(plan.Quotas.Resources) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getQuotaReservations returns the reservations for the plans of the service
+// offering that have not expired, by instance ID. The configs are shared by
+// all the service offerings deployed by the BOSH director.
+func (b *Broker) getQuotaReservations(ctx context.Context, serviceOffering config.ServiceOffering, logger *log.Logger) (map[string]QuotaReservation, error) {
+	configs, err := b.boshClient.GetConfigsOfType(ctx, QuotaReservationConfigType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := map[string]QuotaReservation{}
+	for _, c := range configs {
+		if !strings.HasPrefix(c.Name, InstancePrefix) {
+			continue
+		}
+		instanceID := strings.TrimPrefix(c.Name, InstancePrefix)
+
+		var reservation QuotaReservation
+		if err := json.Unmarshal([]byte(c.Content), &reservation); err != nil {
+			logger.Printf("ignoring quota reservation for %s that cannot be parsed: %s\n", instanceID, err)
+			continue
+		}
+		if time.Since(reservation.CreatedAt) > quotaReservationExpiry {
+			continue
+		}
+		if _, found := serviceOffering.FindPlanByID(reservation.PlanID); !found {
+			continue
+		}
+		reservations[instanceID] = reservation
+	}
+	return reservations, nil
+}
+
+func (b *Broker) saveQuotaReservation(ctx context.Context, instanceID string, reservation QuotaReservation, logger *log.Logger) error {
+	content, err := json.Marshal(reservation)
+	if err != nil {
+		return err
+	}
+
+	return b.boshClient.UpdateConfig(ctx, QuotaReservationConfigType, deploymentName(instanceID), content, logger)
+}
+
+// releaseQuotaReservation removes the reservation of an operation that has
+// reached a terminal state. Failures are only logged, as the reservation
+// expires in any case.
+func (b *Broker) releaseQuotaReservation(ctx context.Context, instanceID string, operationData OperationData, logger *log.Logger) {
+	if !operationData.QuotaReserved || b.DisableBoshConfigs {
+		return
+	}
+
+	if _, err := b.boshClient.DeleteConfig(ctx, QuotaReservationConfigType, deploymentName(instanceID), logger); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error releasing quota reservation for %s: %s\n", instanceID, err)
+	}
+}
+
+// withReservations adds the reservations to instance counts by plan ID. Plan
+// changes are counted as moving an instance between plans. When inScope is
+// set, only the reservations it returns true for are added.
+func withReservations(planCounts map[string]int, reservations map[string]QuotaReservation, inScope func(QuotaReservation) bool) map[string]int {
+	for _, reservation := range reservations {
+		if inScope != nil && !inScope(reservation) {
+			continue
+		}
+		planCounts[reservation.PlanID]++
+		if reservation.PreviousPlanID != "" {
+			planCounts = withoutInstance(planCounts, reservation.PreviousPlanID)
+		}
+	}
+	return planCounts
+}
@@ -5,31 +5,33 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (b *Broker) checkQuotas(ctx context.Context, plan config.Plan, cfPlanCounts map[cf.ServicePlan]int, serviceOffering string, logger *log.Logger) (error, bool) {
+func checkQuotas(serviceOffering config.ServiceOffering, plan config.Plan, cfPlanCounts map[cf.ServicePlan]int, reservations map[string]QuotaReservation) (error, bool) {
 	var quotasErrors []error
 
-	planCounts := convertCfPlanCounts(cfPlanCounts)
+	planCounts := withReservations(convertCfPlanCounts(cfPlanCounts), reservations, nil)
 
 	if instanceLimit := plan.Quotas.ServiceInstanceLimit; instanceLimit != nil {
-		if err := checkPlanServiceCount(plan, planCounts, *instanceLimit, serviceOffering); err != nil {
+		if err := checkPlanServiceCount(plan, planCounts, *instanceLimit, serviceOffering.ID); err != nil {
 			quotasErrors = append(quotasErrors, err)
 		}
 	}
 
-	if instanceLimit := b.serviceOffering.GlobalQuotas.ServiceInstanceLimit; instanceLimit != nil {
-		if err := checkGlobalServiceCount(planCounts, *instanceLimit, serviceOffering); err != nil {
+	if instanceLimit := serviceOffering.GlobalQuotas.ServiceInstanceLimit; instanceLimit != nil {
+		if err := checkGlobalServiceCount(planCounts, *instanceLimit, serviceOffering.ID); err != nil {
 			quotasErrors = append(quotasErrors, err)
 		}
 	}
 
-	if globalResourceQuota := b.serviceOffering.GlobalQuotas.Resources; globalResourceQuota != nil {
-		if err := checkGlobalResourceQuotaNotExceeded(plan, b.serviceOffering.Plans, planCounts, globalResourceQuota); err != nil {
+	if globalResourceQuota := serviceOffering.GlobalQuotas.Resources; globalResourceQuota != nil {
+		if err := checkGlobalResourceQuotaNotExceeded(plan, serviceOffering.Plans, planCounts, globalResourceQuota); err != nil {
 			quotasErrors = append(quotasErrors, err)
 		}
 	}
@@ -51,6 +53,98 @@ func (b *Broker) checkQuotas(ctx context.Context, plan config.Plan, cfPlanCounts
 	return nil, true
 }
 
+// countInstancesByOrgAndSpace counts the instances of each org and each space
+// when the service offering has org or space quotas.
+func (b *Broker) countInstancesByOrgAndSpace(ctx context.Context, serviceOffering config.ServiceOffering, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	if serviceOffering.GlobalQuotas.Orgs == nil && serviceOffering.GlobalQuotas.Spaces == nil {
+		return cf.OrgAndSpaceInstanceCounts{}, nil
+	}
+
+	_, span := tracing.Start(ctx, "count-instances-by-org-and-space")
+	counts, err := b.instanceCounter.CountInstancesOfServiceOfferingByOrgAndSpace(ctx, serviceOffering.ID, logger)
+	span.End(err)
+	return counts, err
+}
+
+// checkOrgAndSpaceQuotas checks the quotas of the org and the space in the
+// request context, for an instance of plan to be created there. When the
+// instance already exists on previousPlanID, it is counted as moving to plan.
+func checkOrgAndSpaceQuotas(serviceOffering config.ServiceOffering, plan config.Plan, previousPlanID string, contextMap map[string]interface{}, counts cf.OrgAndSpaceInstanceCounts, reservations map[string]QuotaReservation) error {
+	orgQuotas, spaceQuotas := serviceOffering.GlobalQuotas.Orgs, serviceOffering.GlobalQuotas.Spaces
+
+	var quotasErrors []error
+	if orgGUID := getOrgGUIDFromContext(contextMap); orgQuotas != nil && orgGUID != "" {
+		planCounts := withoutInstance(convertCfPlanCounts(counts.Orgs[orgGUID]), previousPlanID)
+		planCounts = withReservations(planCounts, reservations, func(r QuotaReservation) bool { return r.OrgGUID == orgGUID })
+		quotasErrors = append(quotasErrors, checkScopedQuotas("org", orgGUID, orgQuotas.For(orgGUID), plan, serviceOffering.Plans, planCounts)...)
+	}
+	if spaceGUID := getSpaceGUIDFromContext(contextMap); spaceQuotas != nil && spaceGUID != "" {
+		planCounts := withoutInstance(convertCfPlanCounts(counts.Spaces[spaceGUID]), previousPlanID)
+		planCounts = withReservations(planCounts, reservations, func(r QuotaReservation) bool { return r.SpaceGUID == spaceGUID })
+		quotasErrors = append(quotasErrors, checkScopedQuotas("space", spaceGUID, spaceQuotas.For(spaceGUID), plan, serviceOffering.Plans, planCounts)...)
+	}
+
+	if len(quotasErrors) > 0 {
+		errorStrings := []string{}
+		for _, e := range quotasErrors {
+			errorStrings = append(errorStrings, e.Error())
+		}
+		return errors.New(strings.Join(errorStrings, ", "))
+	}
+
+	return nil
+}
+
+// withoutInstance removes an instance of the plan from the counts, as when
+// the instance is changing plan.
+func withoutInstance(planCounts map[string]int, planID string) map[string]int {
+	if planCounts[planID] > 0 {
+		planCounts[planID]--
+	}
+	return planCounts
+}
+
+func checkScopedQuotas(scope, guid string, quotas config.Quotas, plan config.Plan, plans []config.Plan, planCounts map[string]int) []error {
+	var quotasErrors []error
+
+	if instanceLimit := quotas.ServiceInstanceLimit; instanceLimit != nil {
+		totalServiceInstances := 0
+		for _, count := range planCounts {
+			totalServiceInstances += count
+		}
+		if totalServiceInstances >= *instanceLimit {
+			quotasErrors = append(quotasErrors, fmt.Errorf("%s instance limit exceeded for %s GUID: %s. Total instances: %d", scope, scope, guid, totalServiceInstances))
+		}
+	}
+
+	var kinds []string
+	for kind := range quotas.Resources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var exceededQuotas []exceededQuota
+	for _, kind := range kinds {
+		var currentUsage int
+		for _, p := range plans {
+			currentUsage += p.Quotas.Resources[kind].Cost * planCounts[p.ID]
+		}
+		required := plan.Quotas.Resources[kind].Cost
+		if limit := quotas.Resources[kind].Limit; currentUsage+required > limit {
+			exceededQuotas = append(exceededQuotas, exceededQuota{kind, limit, currentUsage, required})
+		}
+	}
+	if exceededQuotas != nil {
+		errorDetails := []string{}
+		for _, q := range exceededQuotas {
+			errorDetails = append(errorDetails, fmt.Sprintf("%s: (limit %d, used %d, requires %d)", q.name, q.limit, q.usage, q.required))
+		}
+		quotasErrors = append(quotasErrors, fmt.Errorf("%s %s quotas [%s] would be exceeded by this deployment", scope, guid, strings.Join(errorDetails, ", ")))
+	}
+
+	return quotasErrors
+}
+
 func convertCfPlanCounts(cfPlanCounts map[cf.ServicePlan]int) map[string]int {
 	brokerPlanCounts := make(map[string]int)
 
@@ -9,12 +9,12 @@ import (
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"github.com/pborman/uuid"
 
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 func (b *Broker) Recreate(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (OperationData, error) {
-	b.deploymentLock.Lock()
-	defer b.deploymentLock.Unlock()
+	defer b.deploymentLocks.lock(instanceID)()
 
 	logger.Printf("recreating instance %s", instanceID)
 
@@ -24,9 +24,9 @@ func (b *Broker) Recreate(ctx context.Context, instanceID string, details domain
 		return OperationData{}, b.processError(errors.New("no plan ID provided in recreate request body"), logger)
 	}
 
-	plan, found := b.serviceOffering.FindPlanByID(details.PlanID)
+	plan, found := b.offering().FindPlanByID(details.PlanID)
 	if !found {
-		logger.Printf("error: finding plan ID %s", details.PlanID)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error: finding plan ID %s", details.PlanID)
 		return OperationData{}, b.processError(fmt.Errorf("plan %s not found", details.PlanID), logger)
 	}
 
@@ -34,12 +34,12 @@ func (b *Broker) Recreate(ctx context.Context, instanceID string, details domain
 		boshContextID = uuid.New()
 	}
 
-	taskID, err := b.deployer.Recreate(deploymentName(instanceID), details.PlanID, boshContextID, logger)
+	taskID, err := b.deployer.Recreate(ctx, deploymentName(instanceID), details.PlanID, boshContextID, logger)
 	if err != nil {
-		logger.Printf("error recreating instance %s: %s", instanceID, err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error recreating instance %s: %s", instanceID, err)
 
 		switch err := err.(type) {
-		case serviceadapter.UnknownFailureError:
+		case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError:
 			return OperationData{}, b.processError(adapterToAPIError(ctx, err), logger)
 		case TaskInProgressError:
 			return OperationData{}, b.processError(NewOperationInProgressError(err), logger)
@@ -48,6 +48,10 @@ func (b *Broker) Recreate(ctx context.Context, instanceID string, details domain
 		}
 	}
 
+	record := newOperationRecord(ctx, OperationTypeRecreate, plan.ID, plan.ID, taskID, boshContextID)
+	b.recordOperation(ctx, instanceID, plan.ID, record, logger)
+	b.notifyOperationStarted(instanceID, record)
+
 	return OperationData{
 		BoshContextID: boshContextID,
 		BoshTaskID:    taskID,
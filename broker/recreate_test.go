@@ -46,7 +46,7 @@ var _ = Describe("Recreate", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(fakeDeployer.RecreateCallCount()).To(Equal(1), "expected the deployer to be called once")
 
-		actualDeploymentName, _, actualBoshContextID, _ := fakeDeployer.RecreateArgsForCall(0)
+		_, actualDeploymentName, _, actualBoshContextID, _ := fakeDeployer.RecreateArgsForCall(0)
 
 		Expect(actualDeploymentName).To(Equal(broker.InstancePrefix + instanceID))
 		Expect(actualBoshContextID).To(BeEmpty())
@@ -66,7 +66,7 @@ var _ = Describe("Recreate", func() {
 
 		operationData, _ = b.Recreate(context.Background(), instanceID, details, logger)
 
-		_, _, contextID, _ := fakeDeployer.RecreateArgsForCall(0)
+		_, _, _, contextID, _ := fakeDeployer.RecreateArgsForCall(0)
 		Expect(contextID).NotTo(BeEmpty())
 		Expect(operationData.BoshContextID).NotTo(BeEmpty())
 		Expect(contextID).To(Equal(operationData.BoshContextID))
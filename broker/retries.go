@@ -0,0 +1,174 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// Platforms retry requests they got no answer for, and OSBAPI requires the
+// broker to answer a retry as it did the first attempt: with the operation it
+// started while that is in progress, and as done once it has succeeded. The
+// broker tells retries apart from other requests by the plan, parameters and
+// operations it recorded in the instance metadata.
+
+// provisionRetry answers a provision request for an instance that is already
+// deployed. When the request repeats the one that created the instance, it
+// returns the operation data of the create while it is in progress, or an
+// OperationAlreadyCompletedError once it has succeeded. Any other request
+// conflicts with the instance.
+func (b *Broker) provisionRetry(ctx context.Context, instanceID string, serviceOffering config.ServiceOffering, plan config.Plan, requestParams map[string]interface{}, logger *log.Logger) (OperationData, error) {
+	conflict := NewDisplayableError(
+		apiresponses.ErrInstanceAlreadyExists,
+		fmt.Errorf("deploying instance %s", instanceID),
+	)
+
+	metadata, found, err := b.getInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		return OperationData{}, NewGenericError(ctx, err)
+	}
+
+	requested := InstanceMetadata{}.withPlan(plan.ID).withOrgAndSpace(requestParams)
+	if !found ||
+		metadata.PlanID != requested.PlanID ||
+		metadata.OrgGUID != requested.OrgGUID ||
+		metadata.SpaceGUID != requested.SpaceGUID ||
+		!sameParameters(metadata.Parameters, requestParams) {
+		return OperationData{}, conflict
+	}
+
+	record, ok := metadata.lastOperation()
+	if !ok || record.Type != OperationTypeCreate {
+		return OperationData{}, conflict
+	}
+
+	outcome, err := b.operationOutcome(ctx, instanceID, record, plan.PostDeployErrands(), logger)
+	if err != nil {
+		return OperationData{}, NewGenericError(ctx, err)
+	}
+
+	switch outcome {
+	case domain.InProgress:
+		logger.Printf("provision of instance %s repeats the create in progress, returning its operation\n", instanceID)
+		return OperationData{
+			BoshTaskID:    record.BoshTaskID,
+			OperationType: OperationTypeCreate,
+			BoshContextID: record.BoshContextID,
+			Errands:       plan.PostDeployErrands(),
+			QuotaReserved: quotasEnabled(serviceOffering),
+		}, nil
+	case domain.Succeeded:
+		logger.Printf("provision of instance %s repeats the create that succeeded\n", instanceID)
+		return OperationData{}, NewOperationAlreadyCompletedError(fmt.Errorf("instance %s already exists", instanceID))
+	default:
+		return OperationData{}, conflict
+	}
+}
+
+// updateInProgress returns the operation data of the update in progress on the
+// instance when the request repeats it, that is when it is for the same plan
+// and would not change the recorded parameters.
+func (b *Broker) updateInProgress(ctx context.Context, instanceID string, serviceOffering config.ServiceOffering, plan config.Plan, metadata InstanceMetadata, detailsMap map[string]interface{}, logger *log.Logger) (OperationData, bool) {
+	record, ok := metadata.lastOperation()
+	if !ok || record.Type != OperationTypeUpdate || record.PlanIDAfter != plan.ID {
+		return OperationData{}, false
+	}
+	if !reflect.DeepEqual(metadata.mergeParameters(detailsMap).Parameters, metadata.Parameters) {
+		return OperationData{}, false
+	}
+
+	outcome, err := b.operationOutcome(ctx, instanceID, record, plan.PostDeployErrands(), logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error checking the last update of instance %s: %s\n", instanceID, err)
+		return OperationData{}, false
+	}
+	if outcome != domain.InProgress {
+		return OperationData{}, false
+	}
+
+	logger.Printf("update of instance %s repeats the update in progress, returning its operation\n", instanceID)
+	return OperationData{
+		BoshTaskID:    record.BoshTaskID,
+		OperationType: OperationTypeUpdate,
+		BoshContextID: record.BoshContextID,
+		Errands:       plan.PostDeployErrands(),
+		QuotaReserved: record.PlanIDBefore != record.PlanIDAfter && quotasEnabled(serviceOffering),
+	}, true
+}
+
+// deleteInProgress returns the operation data of the delete in progress on the
+// instance, if any, so that repeated deprovision requests are answered with it.
+func (b *Broker) deleteInProgress(ctx context.Context, instanceID string, metadata InstanceMetadata, plan config.Plan, logger *log.Logger) (OperationData, bool) {
+	record, ok := metadata.lastOperation()
+	if !ok || (record.Type != OperationTypeDelete && record.Type != OperationTypeForceDelete) {
+		return OperationData{}, false
+	}
+
+	var errands []config.Errand
+	if record.BoshContextID != "" {
+		errands = plan.PreDeleteErrands()
+	}
+
+	outcome, err := b.operationOutcome(ctx, instanceID, record, errands, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error checking the last delete of instance %s: %s\n", instanceID, err)
+		return OperationData{}, false
+	}
+	if outcome != domain.InProgress {
+		return OperationData{}, false
+	}
+
+	logger.Printf("deprovision of instance %s repeats the delete in progress, returning its operation\n", instanceID)
+	return OperationData{
+		BoshTaskID:    record.BoshTaskID,
+		OperationType: record.Type,
+		BoshContextID: record.BoshContextID,
+		Errands:       errands,
+	}, true
+}
+
+// operationOutcome is how a recorded operation went. An operation with errands
+// is in progress until the broker has run all of them, even when the tasks
+// started so far are done.
+func (b *Broker) operationOutcome(ctx context.Context, instanceID string, record OperationRecord, errands []config.Errand, logger *log.Logger) (domain.LastOperationState, error) {
+	tasks, err := b.operationTasks(ctx, instanceID, record, logger)
+	if err != nil {
+		return "", err
+	}
+
+	outcome := tasksOutcome(tasks)
+	if outcome == domain.Succeeded && record.BoshContextID != "" && len(tasks) < len(errands)+1 {
+		return domain.InProgress, nil
+	}
+	return outcome, nil
+}
+
+func (m InstanceMetadata) lastOperation() (OperationRecord, bool) {
+	if len(m.Operations) == 0 {
+		return OperationRecord{}, false
+	}
+	return m.Operations[len(m.Operations)-1], true
+}
+
+// sameParameters compares the recorded parameters of an instance with the ones
+// of a request. No parameters and empty parameters are the same.
+func sameParameters(recorded map[string]interface{}, requestParams map[string]interface{}) bool {
+	params, _ := requestParams["parameters"].(map[string]interface{})
+	if len(recorded) == 0 && len(params) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(recorded, params)
+}
@@ -0,0 +1,286 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"github.com/pborman/uuid"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// InstanceSnapshotConfigType is the BOSH config type under which the broker
+// keeps the last deployment of an instance known to have succeeded. Like the
+// instance metadata, it is named after the deployment.
+const InstanceSnapshotConfigType = "odb-instance-snapshot"
+
+// odbSecretsPathPrefix is where the broker stores the secrets the adapter asks
+// it to manage. These are the only secrets a deployment can change.
+const odbSecretsPathPrefix = "/odb/"
+
+// InstanceSnapshot is what is needed to redeploy an instance as it was. Secret
+// values are never stored in it: it records which versions of the ODB managed
+// secrets were in use, and those versions are kept by CredHub. BoshTaskID is the
+// task that deployed it.
+type InstanceSnapshot struct {
+	PlanID     string                  `json:"plan_id"`
+	Manifest   string                  `json:"manifest"`
+	Configs    map[string]string       `json:"configs,omitempty"`
+	Secrets    []boshdirector.Variable `json:"secrets,omitempty"`
+	BoshTaskID int                     `json:"bosh_task_id,omitempty"`
+}
+
+// Rollback redeploys the last deployment of the instance known to have
+// succeeded, restoring its configs and the versions of its ODB managed secrets.
+// The secrets are only restored once the deployment is known to be deployable,
+// and the versions in use are put back if the deploy cannot be started.
+func (b *Broker) Rollback(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (OperationData, error) {
+	defer b.deploymentLocks.lock(instanceID)()
+
+	logger.Printf("rolling back instance %s", instanceID)
+
+	if b.DisableBoshConfigs {
+		return OperationData{}, b.processError(NewNoRollbackSnapshotError(errors.New("rollback is not available when BOSH configs are disabled")), logger)
+	}
+
+	snapshot, found, err := b.getInstanceSnapshot(ctx, instanceID, logger)
+	if err != nil {
+		return OperationData{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+	if !found {
+		return OperationData{}, b.processError(NewNoRollbackSnapshotError(fmt.Errorf("no successful deployment recorded for instance %s", instanceID)), logger)
+	}
+
+	plan, found := b.offering().FindPlanByID(snapshot.PlanID)
+	if !found {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error: finding plan ID %s", snapshot.PlanID)
+		return OperationData{}, b.processError(fmt.Errorf("plan %s not found", snapshot.PlanID), logger)
+	}
+
+	metadata, _, err := b.getInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error reading instance metadata for %s: %s\n", instanceID, err)
+	}
+	deployedPlanID := metadata.PlanID
+	if deployedPlanID == "" {
+		deployedPlanID = snapshot.PlanID
+	}
+
+	variables, _, err := b.checkDeployable(ctx, instanceID, deployedPlanID, logger)
+	if err != nil {
+		return OperationData{}, b.rollbackError(instanceID, err, logger)
+	}
+
+	var boshContextID string
+	if plan.LifecycleErrands != nil {
+		boshContextID = uuid.New()
+	}
+
+	if err := b.secretManager.RestoreSecrets(ctx, snapshot.Secrets, logger); err != nil {
+		b.restoreSecretVersions(ctx, instanceID, odbSecrets(variables), logger)
+		return OperationData{}, b.processError(NewGenericError(ctx, fmt.Errorf("error restoring secrets: %s", err)), logger)
+	}
+
+	taskID, err := b.deployer.Rollback(ctx, deploymentName(instanceID), []byte(snapshot.Manifest), snapshot.Configs, boshContextID, logger)
+	if err != nil {
+		b.restoreSecretVersions(ctx, instanceID, odbSecrets(variables), logger)
+		return OperationData{}, b.rollbackError(instanceID, err, logger)
+	}
+
+	record := newOperationRecord(ctx, OperationTypeRollback, metadata.PlanID, plan.ID, taskID, boshContextID)
+	b.recordInstanceMetadata(ctx, instanceID, metadata.
+		withPlan(plan.ID).
+		withOperation(record), logger)
+	b.notifyOperationStarted(instanceID, record)
+
+	return OperationData{
+		BoshContextID: boshContextID,
+		BoshTaskID:    taskID,
+		OperationType: OperationTypeRollback,
+		Errands:       plan.PostDeployErrands(),
+	}, nil
+}
+
+func (b *Broker) rollbackError(instanceID string, err error, logger *log.Logger) error {
+	loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error rolling back instance %s: %s", instanceID, err)
+
+	switch err := err.(type) {
+	case TaskInProgressError:
+		return b.processError(NewOperationInProgressError(err), logger)
+	case PendingChangesNotAppliedError:
+		return b.processError(apiresponses.NewFailureResponse(
+			errors.New(PendingChangesErrorMessage),
+			http.StatusUnprocessableEntity,
+			UpdateLoggerAction,
+		), logger)
+	default:
+		return b.processError(err, logger)
+	}
+}
+
+// checkDeployable fails when the deployment of an instance cannot be deployed
+// as it is: when an operation is in progress or it has pending changes.
+// Operations that change its secrets call it first, so that the secrets are
+// not changed under a deployment that will not happen. It returns the
+// variables of the deployment, so that their versions can be put back, and the
+// secrets its manifest resolves to.
+func (b *Broker) checkDeployable(ctx context.Context, instanceID, deployedPlanID string, logger *log.Logger) ([]boshdirector.Variable, map[string]string, error) {
+	manifest, found, err := b.boshClient.GetDeployment(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, NewDeploymentNotFoundError(fmt.Errorf("bosh deployment '%s' not found", deploymentName(instanceID)))
+	}
+
+	variables, err := b.boshClient.Variables(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secretsMap, err := b.secretManager.ResolveManifestSecrets(ctx, manifest, variables, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := b.deployer.CheckDeployable(ctx, deploymentName(instanceID), deployedPlanID, secretsMap, logger); err != nil {
+		return nil, nil, err
+	}
+	return variables, secretsMap, nil
+}
+
+// restoreSecretVersions puts back the versions of the secrets that were in use
+// before an operation that changed them failed to start. Failures are only
+// logged, as the operation has already failed.
+func (b *Broker) restoreSecretVersions(ctx context.Context, instanceID string, secrets []boshdirector.Variable, logger *log.Logger) {
+	if len(secrets) == 0 {
+		return
+	}
+	if err := b.secretManager.RestoreSecrets(ctx, secrets, logger); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error restoring the secrets of instance %s: %s\n", instanceID, err)
+	}
+}
+
+func odbSecrets(variables []boshdirector.Variable) []boshdirector.Variable {
+	var secrets []boshdirector.Variable
+	for _, variable := range variables {
+		if strings.HasPrefix(variable.Path, odbSecretsPathPrefix) {
+			secrets = append(secrets, variable)
+		}
+	}
+	return secrets
+}
+
+// recordSnapshotAfterDeploy stores the deployment of an instance once an
+// operation that deploys it has succeeded. It is stored once per BOSH task, as
+// the operation may be polled again after it succeeded. Failures are only
+// logged: they must not fail the operation, which has already completed.
+func (b *Broker) recordSnapshotAfterDeploy(ctx context.Context, instanceID string, operationData OperationData, logger *log.Logger) {
+	switch operationData.OperationType {
+	case OperationTypeCreate, OperationTypeUpdate, OperationTypeUpgrade, OperationTypeRecreate, OperationTypeRollback, OperationTypeRotateSecrets:
+	default:
+		return
+	}
+
+	if b.DisableBoshConfigs {
+		return
+	}
+
+	if err := b.saveInstanceSnapshot(ctx, instanceID, operationData.PlanID, operationData.BoshTaskID, logger); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error storing the deployment of instance %s for rollback: %s\n", instanceID, err)
+	}
+}
+
+func (b *Broker) saveInstanceSnapshot(ctx context.Context, instanceID, planID string, taskID int, logger *log.Logger) error {
+	boshConfigs, err := b.boshClient.GetConfigs(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return err
+	}
+
+	previous, found, err := instanceSnapshotIn(instanceID, boshConfigs)
+	if err != nil {
+		return err
+	}
+	if found && taskID != 0 && previous.BoshTaskID == taskID {
+		return nil
+	}
+
+	manifest, found, err := b.boshClient.GetDeployment(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("bosh deployment '%s' not found", deploymentName(instanceID))
+	}
+
+	snapshot := InstanceSnapshot{PlanID: planID, Manifest: string(manifest), Configs: map[string]string{}, BoshTaskID: taskID}
+	for _, c := range boshConfigs {
+		switch c.Type {
+		case InstanceMetadataConfigType:
+			var metadata InstanceMetadata
+			if err := json.Unmarshal([]byte(c.Content), &metadata); err != nil {
+				return fmt.Errorf("error parsing instance metadata for %s: %s", instanceID, err)
+			}
+			if metadata.PlanID != "" {
+				snapshot.PlanID = metadata.PlanID
+			}
+		case InstanceSnapshotConfigType, QuotaReservationConfigType, OrphanMarkerConfigType, BindingsConfigType:
+		default:
+			snapshot.Configs[c.Type] = c.Content
+		}
+	}
+
+	if snapshot.PlanID == "" {
+		return fmt.Errorf("no plan recorded for instance %s", instanceID)
+	}
+
+	variables, err := b.boshClient.Variables(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return err
+	}
+	snapshot.Secrets = odbSecrets(variables)
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return b.boshClient.UpdateConfig(ctx, InstanceSnapshotConfigType, deploymentName(instanceID), content, logger)
+}
+
+func (b *Broker) getInstanceSnapshot(ctx context.Context, instanceID string, logger *log.Logger) (InstanceSnapshot, bool, error) {
+	configs, err := b.boshClient.GetConfigs(ctx, deploymentName(instanceID), logger)
+	if err != nil {
+		return InstanceSnapshot{}, false, err
+	}
+	return instanceSnapshotIn(instanceID, configs)
+}
+
+func instanceSnapshotIn(instanceID string, configs []boshdirector.BoshConfig) (InstanceSnapshot, bool, error) {
+	for _, c := range configs {
+		if c.Type != InstanceSnapshotConfigType {
+			continue
+		}
+		var snapshot InstanceSnapshot
+		if err := json.Unmarshal([]byte(c.Content), &snapshot); err != nil {
+			return InstanceSnapshot{}, false, fmt.Errorf("error parsing the deployment stored for rollback of %s: %s", instanceID, err)
+		}
+		return snapshot, true, nil
+	}
+
+	return InstanceSnapshot{}, false, nil
+}
@@ -0,0 +1,220 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("Rollback", func() {
+	const (
+		instanceID = "an-instance"
+		boshTaskID = 4321
+	)
+
+	var (
+		secrets        = []boshdirector.Variable{{Path: "/odb/service-id/service-instance_an-instance/admin", ID: "42"}}
+		currentSecrets = []boshdirector.Variable{{Path: "/odb/service-id/service-instance_an-instance/admin", ID: "43"}}
+	)
+
+	snapshotConfig := func(snapshot broker.InstanceSnapshot) boshdirector.BoshConfig {
+		content, err := json.Marshal(snapshot)
+		Expect(err).NotTo(HaveOccurred())
+		return boshdirector.BoshConfig{Type: broker.InstanceSnapshotConfigType, Name: "service-instance_" + instanceID, Content: string(content)}
+	}
+
+	BeforeEach(func() {
+		boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+			{Type: broker.InstanceMetadataConfigType, Content: fmt.Sprintf(`{"plan_id":%q}`, secondPlanID)},
+			snapshotConfig(broker.InstanceSnapshot{
+				PlanID:   existingPlanID,
+				Manifest: "name: known-good",
+				Configs:  map[string]string{"cloud": "known-good-cloud-config"},
+				Secrets:  secrets,
+			}),
+		}, nil)
+		boshClient.GetDeploymentReturns([]byte("name: failed"), true, nil)
+		boshClient.VariablesReturns(append([]boshdirector.Variable{{Path: "/bosh-generated/password", ID: "7"}}, currentSecrets...), nil)
+		fakeSecretManager.ResolveManifestSecretsReturns(map[string]string{"((/odb/service-id/service-instance_an-instance/admin))": "current"}, nil)
+		fakeDeployer.RollbackReturns(boshTaskID, nil)
+		b = createDefaultBroker()
+	})
+
+	It("redeploys the last known-good deployment with its secrets and configs", func() {
+		operationData, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operationData).To(Equal(broker.OperationData{
+			BoshTaskID:    boshTaskID,
+			OperationType: broker.OperationTypeRollback,
+		}))
+
+		Expect(fakeSecretManager.RestoreSecretsCallCount()).To(Equal(1))
+		_, restoredSecrets, _ := fakeSecretManager.RestoreSecretsArgsForCall(0)
+		Expect(restoredSecrets).To(Equal(secrets))
+
+		_, checkedDeployment, deployedPlanID, secretsMap, _ := fakeDeployer.CheckDeployableArgsForCall(0)
+		Expect(checkedDeployment).To(Equal("service-instance_" + instanceID))
+		Expect(deployedPlanID).To(Equal(secondPlanID))
+		Expect(secretsMap).To(Equal(map[string]string{"((/odb/service-id/service-instance_an-instance/admin))": "current"}))
+
+		_, deploymentName, manifest, configs, contextID, _ := fakeDeployer.RollbackArgsForCall(0)
+		Expect(deploymentName).To(Equal("service-instance_" + instanceID))
+		Expect(string(manifest)).To(Equal("name: known-good"))
+		Expect(configs).To(Equal(map[string]string{"cloud": "known-good-cloud-config"}))
+		Expect(contextID).To(BeEmpty())
+	})
+
+	It("records the rollback and the plan it returns to", func() {
+		_, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+		_, configType, _, content, _ := boshClient.UpdateConfigArgsForCall(0)
+		Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+		var metadata broker.InstanceMetadata
+		Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+		Expect(metadata.PlanID).To(Equal(existingPlanID))
+		Expect(metadata.Operations).To(HaveLen(1))
+		Expect(metadata.Operations[0].Type).To(Equal(broker.OperationTypeRollback))
+		Expect(metadata.Operations[0].PlanIDBefore).To(Equal(secondPlanID))
+		Expect(metadata.Operations[0].BoshTaskID).To(Equal(boshTaskID))
+	})
+
+	It("fails when no successful deployment was recorded", func() {
+		boshClient.GetConfigsReturns(nil, nil)
+
+		_, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+
+		Expect(err).To(BeAssignableToTypeOf(broker.NoRollbackSnapshotError{}))
+		Expect(fakeDeployer.RollbackCallCount()).To(BeZero())
+	})
+
+	It("fails when BOSH configs are disabled", func() {
+		brokerConfig.DisableBoshConfigs = true
+		b = createDefaultBroker()
+
+		_, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+
+		Expect(err).To(BeAssignableToTypeOf(broker.NoRollbackSnapshotError{}))
+	})
+
+	It("does not deploy when the secrets cannot be restored", func() {
+		fakeSecretManager.RestoreSecretsReturns(errors.New("credhub unavailable"))
+
+		_, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+
+		Expect(err).To(HaveOccurred())
+		Expect(fakeDeployer.RollbackCallCount()).To(BeZero())
+	})
+
+	It("reports an operation in progress without restoring the secrets", func() {
+		fakeDeployer.CheckDeployableReturns(broker.TaskInProgressError{Message: "task in progress"})
+
+		_, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+
+		Expect(err).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+		Expect(fakeSecretManager.RestoreSecretsCallCount()).To(BeZero())
+		Expect(fakeDeployer.RollbackCallCount()).To(BeZero())
+	})
+
+	It("reports pending changes without restoring the secrets", func() {
+		fakeDeployer.CheckDeployableReturns(broker.NewPendingChangesNotAppliedError(errors.New("There are pending changes")))
+
+		_, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+
+		Expect(err).To(MatchError(broker.PendingChangesErrorMessage))
+		Expect(fakeSecretManager.RestoreSecretsCallCount()).To(BeZero())
+		Expect(fakeDeployer.RollbackCallCount()).To(BeZero())
+	})
+
+	It("puts back the secret versions in use when the deploy fails", func() {
+		fakeDeployer.RollbackReturns(0, broker.TaskInProgressError{Message: "task in progress"})
+
+		_, err := b.Rollback(context.Background(), instanceID, domain.UpdateDetails{}, loggerFactory.New())
+
+		Expect(err).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+		Expect(fakeSecretManager.RestoreSecretsCallCount()).To(Equal(2))
+		_, restoredSecrets, _ := fakeSecretManager.RestoreSecretsArgsForCall(1)
+		Expect(restoredSecrets).To(Equal(currentSecrets))
+	})
+
+	Describe("recording the known-good deployment", func() {
+		lastOperation := func(state string) {
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: boshTaskID, State: state}, nil)
+			operationData, err := json.Marshal(broker.OperationData{BoshTaskID: boshTaskID, OperationType: broker.OperationTypeUpgrade})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = b.LastOperation(context.Background(), instanceID, domain.PollDetails{OperationData: string(operationData)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns([]byte("name: deployed"), true, nil)
+			boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+				{Type: broker.InstanceMetadataConfigType, Content: fmt.Sprintf(`{"plan_id":%q}`, secondPlanID)},
+				{Type: "cloud", Content: "deployed-cloud-config"},
+				snapshotConfig(broker.InstanceSnapshot{Manifest: "name: older"}),
+			}, nil)
+			boshClient.VariablesReturns(append([]boshdirector.Variable{{Path: "/bosh-generated/password", ID: "7"}}, secrets...), nil)
+		})
+
+		It("stores the deployment once an operation succeeds", func() {
+			lastOperation(boshdirector.TaskDone)
+
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+			_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(0)
+			Expect(configType).To(Equal(broker.InstanceSnapshotConfigType))
+			Expect(configName).To(Equal("service-instance_" + instanceID))
+			var snapshot broker.InstanceSnapshot
+			Expect(json.Unmarshal(content, &snapshot)).To(Succeed())
+			Expect(snapshot).To(Equal(broker.InstanceSnapshot{
+				PlanID:     secondPlanID,
+				Manifest:   "name: deployed",
+				Configs:    map[string]string{"cloud": "deployed-cloud-config"},
+				Secrets:    secrets,
+				BoshTaskID: boshTaskID,
+			}))
+		})
+
+		It("stores the deployment once per task", func() {
+			boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+				{Type: broker.InstanceMetadataConfigType, Content: fmt.Sprintf(`{"plan_id":%q}`, secondPlanID)},
+				snapshotConfig(broker.InstanceSnapshot{Manifest: "name: deployed", BoshTaskID: boshTaskID}),
+			}, nil)
+
+			lastOperation(boshdirector.TaskDone)
+
+			Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+			Expect(boshClient.GetDeploymentCallCount()).To(BeZero())
+		})
+
+		It("keeps the previous deployment when an operation fails", func() {
+			lastOperation(boshdirector.TaskError)
+
+			Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+		})
+
+		It("does not fail the operation when the deployment cannot be stored", func() {
+			boshClient.UpdateConfigReturns(errors.New("director unavailable"))
+
+			lastOperation(boshdirector.TaskDone)
+
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,170 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"github.com/pborman/uuid"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// RotateSecrets regenerates secrets of an instance and redeploys it with the
+// new values. Secrets generated by BOSH are regenerated in CredHub. ODB managed
+// secrets are left out of the secrets map passed to the service adapter, so
+// that it generates new values for them, which are stored as new versions.
+// Secret paths select the secrets to rotate, by their full path or by their
+// path relative to the deployment; all the secrets of the deployment are
+// rotated when none are given. Nothing is regenerated when the instance cannot
+// be redeployed as it is, and the versions in use before are put back when
+// the redeploy fails to start.
+func (b *Broker) RotateSecrets(ctx context.Context, instanceID string, details domain.UpdateDetails, secretPaths []string, logger *log.Logger) (OperationData, error) {
+	defer b.deploymentLocks.lock(instanceID)()
+
+	logger.Printf("rotating secrets of instance %s", instanceID)
+
+	if details.PlanID == "" {
+		return OperationData{}, b.processError(errors.New("no plan ID provided in rotate-secrets request body"), logger)
+	}
+
+	plan, found := b.offering().FindPlanByID(details.PlanID)
+	if !found {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error: finding plan ID %s", details.PlanID)
+		return OperationData{}, b.processError(fmt.Errorf("plan %s not found", details.PlanID), logger)
+	}
+
+	// secrets must not change under an operation in flight, nor for a
+	// deployment that would not happen
+	variables, secretsMap, err := b.checkDeployable(ctx, instanceID, plan.ID, logger)
+	if err != nil {
+		_, err := b.handleUpdateError(ctx, err, logger)
+		return OperationData{}, err
+	}
+
+	selected, err := selectSecrets(deploymentName(instanceID), variables, secretPaths)
+	if err != nil {
+		return OperationData{}, b.processError(err, logger)
+	}
+
+	var boshGenerated []string
+	for _, variable := range selected {
+		if strings.HasPrefix(variable.Path, odbSecretsPathPrefix) {
+			delete(secretsMap, fmt.Sprintf("((%s))", variable.Path))
+		} else {
+			boshGenerated = append(boshGenerated, variable.Path)
+		}
+	}
+
+	var boshContextID string
+	if plan.LifecycleErrands != nil {
+		boshContextID = uuid.New()
+	}
+
+	rawCtx, err := convertToMap(details.RawContext)
+	if err != nil {
+		return OperationData{}, b.processError(fmt.Errorf("invalid request context"), logger)
+	}
+
+	contextMap := map[string]interface{}{
+		"context": rawCtx,
+	}
+
+	instanceClient, err := b.GetServiceInstanceClient(instanceID, contextMap)
+	if err != nil {
+		return OperationData{}, b.processError(NewGenericError(ctx, err), logger)
+	}
+
+	if err := b.secretManager.RegenerateSecrets(ctx, boshGenerated, logger); err != nil {
+		b.restoreSecretVersions(ctx, instanceID, selected, logger)
+		return OperationData{}, b.processError(NewGenericError(ctx, fmt.Errorf("error regenerating secrets: %s", err)), logger)
+	}
+
+	taskID, _, _, err := b.deployer.RotateSecrets(
+		ctx,
+		deploymentName(instanceID),
+		plan,
+		contextMap,
+		boshContextID,
+		secretsMap,
+		instanceClient,
+		logger,
+	)
+	if err != nil {
+		b.restoreSecretVersions(ctx, instanceID, selected, logger)
+		_, err := b.handleUpdateError(ctx, err, logger)
+		return OperationData{}, err
+	}
+
+	logger.Printf("rotating %d secrets of instance %s in bosh task %d", len(selected), instanceID, taskID)
+
+	record := newOperationRecord(ctx, OperationTypeRotateSecrets, plan.ID, plan.ID, taskID, boshContextID)
+	b.recordOperation(ctx, instanceID, plan.ID, record, logger)
+	b.notifyOperationStarted(instanceID, record)
+
+	return OperationData{
+		BoshContextID: boshContextID,
+		BoshTaskID:    taskID,
+		OperationType: OperationTypeRotateSecrets,
+		Errands:       plan.PostDeployErrands(),
+	}, nil
+}
+
+// selectSecrets returns the variables of the deployment that secretPaths
+// select, or all of them when no paths are given. Every path must select a
+// variable.
+func selectSecrets(deploymentName string, variables []boshdirector.Variable, secretPaths []string) ([]boshdirector.Variable, error) {
+	if len(secretPaths) == 0 {
+		return variables, nil
+	}
+
+	matched := map[string]bool{}
+	var selected []boshdirector.Variable
+	for _, variable := range variables {
+		isSelected := false
+		for _, secretPath := range secretPaths {
+			if variable.Path == secretPath || relativeSecretPath(deploymentName, variable.Path) == secretPath {
+				matched[secretPath] = true
+				isSelected = true
+			}
+		}
+		if isSelected {
+			selected = append(selected, variable)
+		}
+	}
+
+	var unknown []string
+	for _, secretPath := range secretPaths {
+		if !matched[secretPath] {
+			unknown = append(unknown, secretPath)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, NewSecretsNotFoundError(fmt.Errorf("secrets not found in deployment %s: %s", deploymentName, strings.Join(unknown, ", ")))
+	}
+	return selected, nil
+}
+
+// relativeSecretPath is the part of a secret path after the deployment name,
+// as in "admin_password" for "/odb/offering-id/service-instance_id/admin_password".
+func relativeSecretPath(deploymentName, path string) string {
+	deploymentDir := "/" + deploymentName + "/"
+	i := strings.Index(path, deploymentDir)
+	if i < 0 {
+		return ""
+	}
+	return path[i+len(deploymentDir):]
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("RotateSecrets", func() {
+	var (
+		instanceID = "an-instance"
+		logger     *log.Logger
+		boshTaskID = 4242
+		details    domain.UpdateDetails
+	)
+
+	BeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		details = domain.UpdateDetails{PlanID: existingPlanID}
+
+		boshClient.GetDeploymentReturns([]byte("manifest: true"), true, nil)
+		boshClient.VariablesReturns([]boshdirector.Variable{
+			{Path: "/odb/service-id/service-instance_an-instance/admin_password", ID: "1"},
+			{Path: "/director/service-instance_an-instance/tls_cert", ID: "2"},
+			{Path: "/director/service-instance_an-instance/db_password", ID: "3"},
+		}, nil)
+		fakeSecretManager.ResolveManifestSecretsReturns(map[string]string{
+			"((/odb/service-id/service-instance_an-instance/admin_password))": "old-admin-password",
+			"((tls_cert))":    "old-cert",
+			"((db_password))": "old-db-password",
+		}, nil)
+		fakeDeployer.RotateSecretsReturns(boshTaskID, []byte("new-manifest"), nil, nil)
+
+		b = createDefaultBroker()
+	})
+
+	It("regenerates all the secrets and redeploys the instance", func() {
+		operationData, err := b.RotateSecrets(context.Background(), instanceID, details, nil, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeSecretManager.RegenerateSecretsCallCount()).To(Equal(1))
+		_, paths, _ := fakeSecretManager.RegenerateSecretsArgsForCall(0)
+		Expect(paths).To(Equal([]string{
+			"/director/service-instance_an-instance/tls_cert",
+			"/director/service-instance_an-instance/db_password",
+		}))
+
+		Expect(fakeDeployer.RotateSecretsCallCount()).To(Equal(1))
+		_, deployment, plan, _, _, secretsMap, _, _ := fakeDeployer.RotateSecretsArgsForCall(0)
+		Expect(deployment).To(Equal("service-instance_an-instance"))
+		Expect(plan.ID).To(Equal(existingPlanID))
+		Expect(secretsMap).To(Equal(map[string]string{
+			"((tls_cert))":    "old-cert",
+			"((db_password))": "old-db-password",
+		}))
+
+		Expect(operationData).To(Equal(broker.OperationData{
+			BoshTaskID:    boshTaskID,
+			OperationType: broker.OperationTypeRotateSecrets,
+		}))
+	})
+
+	It("records the operation", func() {
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, nil, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+		_, configType, _, content, _ := boshClient.UpdateConfigArgsForCall(0)
+		Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+		var metadata broker.InstanceMetadata
+		Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+		Expect(metadata.Operations).To(ConsistOf(SatisfyAll(
+			HaveField("Type", broker.OperationTypeRotateSecrets),
+			HaveField("BoshTaskID", boshTaskID),
+		)))
+	})
+
+	It("rotates only the selected secrets, by full or relative path", func() {
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, []string{
+			"/odb/service-id/service-instance_an-instance/admin_password",
+			"db_password",
+		}, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, paths, _ := fakeSecretManager.RegenerateSecretsArgsForCall(0)
+		Expect(paths).To(Equal([]string{"/director/service-instance_an-instance/db_password"}))
+
+		_, _, _, _, _, secretsMap, _, _ := fakeDeployer.RotateSecretsArgsForCall(0)
+		Expect(secretsMap).To(Equal(map[string]string{
+			"((tls_cert))":    "old-cert",
+			"((db_password))": "old-db-password",
+		}))
+	})
+
+	It("fails without rotating anything when a selected secret is not in the deployment", func() {
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, []string{"db_password", "not_a_secret"}, logger)
+
+		Expect(err).To(BeAssignableToTypeOf(broker.SecretsNotFoundError{}))
+		Expect(err).To(MatchError("secrets not found in deployment service-instance_an-instance: not_a_secret"))
+		Expect(fakeSecretManager.RegenerateSecretsCallCount()).To(BeZero())
+		Expect(fakeDeployer.RotateSecretsCallCount()).To(BeZero())
+	})
+
+	It("returns an OperationInProgressError when there is a task in progress on the instance", func() {
+		fakeDeployer.CheckDeployableReturns(broker.TaskInProgressError{Message: "task in progress"})
+
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, nil, logger)
+
+		Expect(err).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+		Expect(fakeSecretManager.RegenerateSecretsCallCount()).To(BeZero())
+	})
+
+	It("does not regenerate the secrets when the instance has pending changes", func() {
+		fakeDeployer.CheckDeployableReturns(broker.NewPendingChangesNotAppliedError(errors.New("There are pending changes")))
+
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, nil, logger)
+
+		Expect(err).To(MatchError(broker.PendingChangesErrorMessage))
+		_, deployment, deployedPlanID, _, _ := fakeDeployer.CheckDeployableArgsForCall(0)
+		Expect(deployment).To(Equal("service-instance_an-instance"))
+		Expect(deployedPlanID).To(Equal(existingPlanID))
+		Expect(fakeSecretManager.RegenerateSecretsCallCount()).To(BeZero())
+		Expect(fakeDeployer.RotateSecretsCallCount()).To(BeZero())
+	})
+
+	It("puts back the secret versions in use when the redeploy fails", func() {
+		fakeDeployer.RotateSecretsReturns(0, nil, nil, errors.New("adapter failed"))
+
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, []string{"tls_cert"}, logger)
+
+		Expect(err).To(HaveOccurred())
+		Expect(fakeSecretManager.RestoreSecretsCallCount()).To(Equal(1))
+		_, restoredSecrets, _ := fakeSecretManager.RestoreSecretsArgsForCall(0)
+		Expect(restoredSecrets).To(Equal([]boshdirector.Variable{
+			{Path: "/director/service-instance_an-instance/tls_cert", ID: "2"},
+		}))
+	})
+
+	It("returns a DeploymentNotFoundError when the deployment does not exist", func() {
+		boshClient.GetDeploymentReturns(nil, false, nil)
+
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, nil, logger)
+
+		Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+	})
+
+	It("does not redeploy when the secrets cannot be regenerated", func() {
+		fakeSecretManager.RegenerateSecretsReturns(errors.New("rotating secrets requires enable_secure_manifests"))
+
+		_, err := b.RotateSecrets(context.Background(), instanceID, details, nil, logger)
+
+		Expect(err).To(HaveOccurred())
+		Expect(logBuffer.String()).To(ContainSubstring("error regenerating secrets: rotating secrets requires enable_secure_manifests"))
+		Expect(fakeDeployer.RotateSecretsCallCount()).To(BeZero())
+		Expect(fakeSecretManager.RestoreSecretsCallCount()).To(Equal(1))
+	})
+
+	It("when no plan ID is provided returns an error", func() {
+		_, err := b.RotateSecrets(context.Background(), instanceID, domain.UpdateDetails{}, nil, logger)
+
+		Expect(err).To(MatchError(ContainSubstring("no plan ID provided in rotate-secrets request body")))
+	})
+})
@@ -47,3 +47,11 @@ func getSpaceGUIDFromContext(contextMap map[string]interface{}) string {
 	}
 	return spaceGUID
 }
+
+func getOrgGUIDFromContext(contextMap map[string]interface{}) string {
+	var orgGUID string
+	if rawOrgGUID, found := contextMap["organization_guid"]; found {
+		orgGUID = rawOrgGUID.(string)
+	}
+	return orgGUID
+}
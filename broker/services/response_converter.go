@@ -78,6 +78,55 @@ func (r ResponseConverter) ExtractOperationFrom(response *http.Response) (BOSHOp
 	}
 }
 
+// UpdateOperationFrom converts the response of an OSBAPI update. Unlike the
+// mgmt API, the broker reports an instance that is busy as an error.
+func (r ResponseConverter) UpdateOperationFrom(response *http.Response) (BOSHOperation, error) {
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusAccepted:
+		var updateResponse apiresponses.UpdateResponse
+		if err := json.NewDecoder(response.Body).Decode(&updateResponse); err != nil {
+			return BOSHOperation{}, fmt.Errorf("cannot parse update response: %s", err)
+		}
+		var operationData broker.OperationData
+		if err := json.Unmarshal([]byte(updateResponse.OperationData), &operationData); err != nil {
+			return BOSHOperation{}, fmt.Errorf("cannot parse operation data of update response: %s", err)
+		}
+		return BOSHOperation{Type: OperationAccepted, Data: operationData}, nil
+	case http.StatusOK:
+		return BOSHOperation{Type: OperationSkipped}, nil
+	case http.StatusNotFound:
+		return BOSHOperation{Type: InstanceNotFound}, nil
+	default:
+		var errorResponse apiresponses.ErrorResponse
+		body, _ := ioutil.ReadAll(response.Body)
+		if err := json.Unmarshal(body, &errorResponse); err != nil {
+			return BOSHOperation{}, fmt.Errorf(
+				"unexpected status code: %d. body: %s", response.StatusCode, string(body),
+			)
+		}
+
+		if errorResponse.Error == "ConcurrencyError" || errorResponse.Description == broker.OperationInProgressMessage {
+			return BOSHOperation{Type: OperationInProgress}, nil
+		}
+
+		return BOSHOperation{}, fmt.Errorf(
+			"unexpected status code: %d. description: %s", response.StatusCode, errorResponse.Description,
+		)
+	}
+}
+
+func (r ResponseConverter) CatalogFrom(response *http.Response) ([]domain.Service, error) {
+	var catalog apiresponses.CatalogResponse
+	err := decodeBodyInto(response, &catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog.Services, nil
+}
+
 func (r ResponseConverter) LastOperationFrom(response *http.Response) (domain.LastOperation, error) {
 	var lastOperation domain.LastOperation
 	err := decodeBodyInto(response, &lastOperation)
@@ -88,6 +137,47 @@ func (r ResponseConverter) LastOperationFrom(response *http.Response) (domain.La
 	return lastOperation, nil
 }
 
+// InstancePreview is the outcome of previewing an operation: either the
+// difference the operation would make or why there is nothing to compare.
+type InstancePreview struct {
+	Type BOSHOperationType
+	Diff broker.ManifestDiff
+}
+
+func (r ResponseConverter) PreviewFrom(response *http.Response) (InstancePreview, error) {
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		var diff broker.ManifestDiff
+		if err := json.NewDecoder(response.Body).Decode(&diff); err != nil {
+			return InstancePreview{}, fmt.Errorf("cannot parse preview response: %s", err)
+		}
+		return InstancePreview{Type: OperationSucceeded, Diff: diff}, nil
+	case http.StatusNotFound:
+		return InstancePreview{Type: InstanceNotFound}, nil
+	case http.StatusGone:
+		return InstancePreview{Type: OrphanDeployment}, nil
+	case http.StatusInternalServerError:
+		var errorResponse apiresponses.ErrorResponse
+		body, _ := ioutil.ReadAll(response.Body)
+		if err := json.Unmarshal(body, &errorResponse); err != nil {
+			return InstancePreview{}, fmt.Errorf(
+				"unexpected status code: %d. cannot parse preview response: '%s'", response.StatusCode, body,
+			)
+		}
+
+		return InstancePreview{}, fmt.Errorf(
+			"unexpected status code: %d. description: %s", response.StatusCode, errorResponse.Description,
+		)
+	default:
+		body, _ := ioutil.ReadAll(response.Body)
+		return InstancePreview{}, fmt.Errorf(
+			"unexpected status code: %d. body: %s", response.StatusCode, string(body),
+		)
+	}
+}
+
 func (r ResponseConverter) OrphanDeploymentsFrom(response *http.Response) ([]mgmtapi.Deployment, error) {
 	var orphans []mgmtapi.Deployment
 	err := decodeBodyInto(response, &orphans)
@@ -98,6 +188,16 @@ func (r ResponseConverter) OrphanDeploymentsFrom(response *http.Response) ([]mgm
 	return orphans, nil
 }
 
+func (r ResponseConverter) OrphanCleanupReportFrom(response *http.Response) (broker.OrphanCleanupReport, error) {
+	var report broker.OrphanCleanupReport
+	err := decodeBodyInto(response, &report)
+	if err != nil {
+		return broker.OrphanCleanupReport{}, err
+	}
+
+	return report, nil
+}
+
 func decodeBodyInto(response *http.Response, contents interface{}) error {
 	defer response.Body.Close()
 
@@ -8,6 +8,7 @@ package services_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -73,6 +74,72 @@ var _ = Describe("Response Converter", func() {
 		})
 	})
 
+	Context("update operation", func() {
+		It("returns the operation data of an accepted update", func() {
+			response := http.Response{
+				StatusCode: http.StatusAccepted,
+				Body:       asBody(`{"operation":"{\"BoshTaskID\":1,\"OperationType\":\"update\"}"}`),
+			}
+
+			result, err := converter.UpdateOperationFrom(&response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Type).To(Equal(services.OperationAccepted))
+			Expect(result.Data.BoshTaskID).To(Equal(1))
+			Expect(result.Data.OperationType).To(Equal(broker.OperationTypeUpdate))
+		})
+
+		It("returns a skipped result when the update completed synchronously", func() {
+			response := http.Response{StatusCode: http.StatusOK, Body: asBody(`{}`)}
+
+			result, err := converter.UpdateOperationFrom(&response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Type).To(Equal(services.OperationSkipped))
+		})
+
+		It("returns a not found result when the instance does not exist", func() {
+			response := http.Response{StatusCode: http.StatusNotFound, Body: asBody(`{}`)}
+
+			result, err := converter.UpdateOperationFrom(&response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Type).To(Equal(services.InstanceNotFound))
+		})
+
+		DescribeTable("returns an operation in progress result when the instance is busy",
+			func(statusCode int, body string) {
+				response := http.Response{StatusCode: statusCode, Body: asBody(body)}
+
+				result, err := converter.UpdateOperationFrom(&response)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Type).To(Equal(services.OperationInProgress))
+			},
+			Entry("concurrency error", http.StatusUnprocessableEntity, `{"error":"ConcurrencyError"}`),
+			Entry("task in progress", http.StatusInternalServerError, fmt.Sprintf(`{"description":%q}`, broker.OperationInProgressMessage)),
+		)
+
+		It("returns the error description when the update fails", func() {
+			response := http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       asBody(`{"description":"plan quota exceeded"}`),
+			}
+
+			_, err := converter.UpdateOperationFrom(&response)
+
+			Expect(err).To(MatchError("unexpected status code: 500. description: plan quota exceeded"))
+		})
+
+		It("returns the body when the error response cannot be decoded", func() {
+			response := http.Response{StatusCode: http.StatusBadGateway, Body: asBody("bad gateway")}
+
+			_, err := converter.UpdateOperationFrom(&response)
+
+			Expect(err).To(MatchError("unexpected status code: 502. body: bad gateway"))
+		})
+	})
+
 	Context("upgrade operation", func() {
 		Context("when the upgrade is accepted", func() {
 			It("returns the upgrade operation data", func() {
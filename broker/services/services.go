@@ -7,6 +7,7 @@
 package services
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -62,6 +63,70 @@ func (b *BrokerServices) ProcessInstance(instance service.Instance, operationTyp
 	return b.converter.ExtractOperationFrom(response)
 }
 
+// RotateSecrets asks the broker to rotate the secrets of an instance, only
+// those selected by secretPaths when it is not empty.
+func (b *BrokerServices) RotateSecrets(instance service.Instance, secretPaths []string) (BOSHOperation, error) {
+	body, err := json.Marshal(mgmtapi.RotateSecretsRequest{
+		UpdateDetails: domain.UpdateDetails{
+			PlanID:     instance.PlanUniqueID,
+			RawContext: json.RawMessage(fmt.Sprintf(`{"space_guid":%q}`, instance.SpaceGUID)),
+		},
+		SecretPaths: secretPaths,
+	})
+	if err != nil {
+		return BOSHOperation{}, err
+	}
+
+	response, err := b.doRequest(
+		http.MethodPatch,
+		fmt.Sprintf("/mgmt/service_instances/%s?operation_type=%s", instance.GUID, broker.OperationTypeRotateSecrets),
+		bytes.NewReader(body))
+	if err != nil {
+		return BOSHOperation{}, err
+	}
+	return b.converter.ExtractOperationFrom(response)
+}
+
+func (b *BrokerServices) PreviewInstance(instance service.Instance, operationType string) (InstancePreview, error) {
+	body := strings.NewReader(fmt.Sprintf(`{"plan_id": "%s", "context":{"space_guid":"%s"}}`, instance.PlanUniqueID, instance.SpaceGUID))
+	response, err := b.doRequest(
+		http.MethodPost,
+		fmt.Sprintf("/mgmt/service_instances/%s/preview?operation_type=%s", instance.GUID, operationType),
+		body)
+	if err != nil {
+		return InstancePreview{}, err
+	}
+	return b.converter.PreviewFrom(response)
+}
+
+// Catalog fetches the catalog of the broker.
+func (b *BrokerServices) Catalog() ([]domain.Service, error) {
+	response, err := b.doRequest(http.MethodGet, "/v2/catalog", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.converter.CatalogFrom(response)
+}
+
+// UpdatePlan asks the broker to move an instance to the plan of the update
+// details, as Cloud Foundry does when the plan of an instance is updated.
+func (b *BrokerServices) UpdatePlan(instance service.Instance, details domain.UpdateDetails) (BOSHOperation, error) {
+	body, err := json.Marshal(details)
+	if err != nil {
+		return BOSHOperation{}, err
+	}
+
+	response, err := b.doRequest(
+		http.MethodPatch,
+		fmt.Sprintf("/v2/service_instances/%s?accepts_incomplete=true", instance.GUID),
+		bytes.NewReader(body))
+	if err != nil {
+		return BOSHOperation{}, err
+	}
+	return b.converter.UpdateOperationFrom(response)
+}
+
 func (b *BrokerServices) LastOperation(instanceGUID string, operationData broker.OperationData) (domain.LastOperation, error) {
 	asJSON, err := json.Marshal(operationData)
 	if err != nil {
@@ -121,6 +186,69 @@ func (b *BrokerServices) OrphanDeployments() ([]mgmtapi.Deployment, error) {
 	return b.converter.OrphanDeploymentsFrom(response)
 }
 
+func (b *BrokerServices) CleanupOrphanDeployments(cleanupRequest mgmtapi.OrphanCleanupRequest) (broker.OrphanCleanupReport, error) {
+	body, err := json.Marshal(cleanupRequest)
+	if err != nil {
+		return broker.OrphanCleanupReport{}, err
+	}
+
+	response, err := b.doRequest(http.MethodPost, "/mgmt/orphan_deployments/cleanup", bytes.NewReader(body))
+	if err != nil {
+		return broker.OrphanCleanupReport{}, err
+	}
+
+	return b.converter.OrphanCleanupReportFrom(response)
+}
+
+// IteratorCheckpoint returns the checkpoint the broker stores under name, if
+// any.
+func (b *BrokerServices) IteratorCheckpoint(name string) ([]byte, bool, error) {
+	response, err := b.doRequest(http.MethodGet, "/mgmt/iterator_checkpoints/"+name, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		checkpoint, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, false, err
+		}
+		return checkpoint, true, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("failed to get checkpoint %s with status code: %d", name, response.StatusCode)
+	}
+}
+
+func (b *BrokerServices) SaveIteratorCheckpoint(name string, checkpoint []byte) error {
+	response, err := b.doRequest(http.MethodPut, "/mgmt/iterator_checkpoints/"+name, bytes.NewReader(checkpoint))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to save checkpoint %s with status code: %d", name, response.StatusCode)
+	}
+	return nil
+}
+
+func (b *BrokerServices) ClearIteratorCheckpoint(name string) error {
+	response, err := b.doRequest(http.MethodDelete, "/mgmt/iterator_checkpoints/"+name, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to clear checkpoint %s with status code: %d", name, response.StatusCode)
+	}
+	return nil
+}
+
 func (b *BrokerServices) doRequest(method, path string, body io.Reader) (*http.Response, error) {
 	request, err := http.NewRequest(method, b.buildURL(path), body)
 	if err != nil {
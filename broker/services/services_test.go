@@ -7,6 +7,7 @@
 package services_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -127,6 +128,158 @@ var _ = Describe("Broker Services", func() {
 		})
 	})
 
+	Describe("RotateSecrets", func() {
+		It("requests the rotation of the selected secrets", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(response(http.StatusAccepted, `{"BoshTaskID":42,"OperationType":"rotate-secrets"}`), nil)
+
+			operation, err := brokerServices.RotateSecrets(
+				service.Instance{
+					GUID:         serviceInstanceGUID,
+					PlanUniqueID: "unique_plan_id",
+					SpaceGUID:    "space-id",
+				}, []string{"admin_password"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.Type).To(Equal(services.OperationAccepted))
+			Expect(operation.Data.BoshTaskID).To(Equal(42))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodPatch))
+			Expect(request.URL.Path).To(Equal("/mgmt/service_instances/" + serviceInstanceGUID))
+			Expect(request.URL.Query()).To(Equal(url.Values{"operation_type": {"rotate-secrets"}}))
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			var rotateRequest mgmtapi.RotateSecretsRequest
+			Expect(json.Unmarshal(body, &rotateRequest)).To(Succeed())
+			Expect(rotateRequest.PlanID).To(Equal("unique_plan_id"))
+			Expect(rotateRequest.RawContext).To(MatchJSON(`{"space_guid": "space-id"}`))
+			Expect(rotateRequest.SecretPaths).To(Equal([]string{"admin_password"}))
+		})
+
+		It("returns an error when the request fails", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(nil, errors.New("connection error"))
+
+			_, err := brokerServices.RotateSecrets(service.Instance{GUID: serviceInstanceGUID}, nil)
+			Expect(err).To(MatchError(ContainSubstring("connection error")))
+		})
+	})
+
+	Describe("Catalog", func() {
+		It("returns the services of the catalog", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(response(http.StatusOK, `{"services":[{"id":"service-id","plans":[{"id":"plan-id"}]}]}`), nil)
+
+			catalog, err := brokerServices.Catalog()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(catalog).To(HaveLen(1))
+			Expect(catalog[0].ID).To(Equal("service-id"))
+			Expect(catalog[0].Plans[0].ID).To(Equal("plan-id"))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodGet))
+			Expect(request.URL.Path).To(Equal("/v2/catalog"))
+		})
+
+		It("returns an error when the request fails", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(nil, errors.New("connection error"))
+
+			_, err := brokerServices.Catalog()
+			Expect(err).To(MatchError(ContainSubstring("connection error")))
+		})
+	})
+
+	Describe("UpdatePlan", func() {
+		It("requests an update to the plan of the details", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(response(http.StatusAccepted, `{"operation":"{\"BoshTaskID\":42,\"OperationType\":\"update\"}"}`), nil)
+
+			operation, err := brokerServices.UpdatePlan(
+				service.Instance{GUID: serviceInstanceGUID, PlanUniqueID: "source-plan-id"},
+				domain.UpdateDetails{
+					ServiceID:      "service-id",
+					PlanID:         "target-plan-id",
+					PreviousValues: domain.PreviousValues{PlanID: "source-plan-id"},
+				})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.Type).To(Equal(services.OperationAccepted))
+			Expect(operation.Data.BoshTaskID).To(Equal(42))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodPatch))
+			Expect(request.URL.Path).To(Equal("/v2/service_instances/" + serviceInstanceGUID))
+			Expect(request.URL.Query()).To(Equal(url.Values{"accepts_incomplete": {"true"}}))
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			var details domain.UpdateDetails
+			Expect(json.Unmarshal(body, &details)).To(Succeed())
+			Expect(details.ServiceID).To(Equal("service-id"))
+			Expect(details.PlanID).To(Equal("target-plan-id"))
+			Expect(details.PreviousValues.PlanID).To(Equal("source-plan-id"))
+		})
+
+		It("returns an error when the request fails", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(nil, errors.New("connection error"))
+
+			_, err := brokerServices.UpdatePlan(service.Instance{GUID: serviceInstanceGUID}, domain.UpdateDetails{})
+			Expect(err).To(MatchError(ContainSubstring("connection error")))
+		})
+	})
+
+	Describe("PreviewInstance", func() {
+		BeforeEach(func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+		})
+
+		It("returns the difference the operation would make", func() {
+			client.DoReturns(response(http.StatusOK, `{"releases":[{"path":"/releases/name=redis/version","before":"1","after":"2"}]}`), nil)
+
+			preview, err := brokerServices.PreviewInstance(service.Instance{
+				GUID:         serviceInstanceGUID,
+				PlanUniqueID: "unique_plan_id",
+				SpaceGUID:    "space-id",
+			}, "upgrade")
+
+			Expect(err).NotTo(HaveOccurred())
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodPost))
+			Expect(request.URL.Path).To(Equal("/mgmt/service_instances/" + serviceInstanceGUID + "/preview"))
+			Expect(request.URL.Query()).To(Equal(url.Values{"operation_type": {"upgrade"}}))
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal(`{"plan_id": "unique_plan_id", "context":{"space_guid":"space-id"}}`))
+
+			Expect(preview).To(Equal(services.InstancePreview{
+				Type: services.OperationSucceeded,
+				Diff: broker.ManifestDiff{
+					Releases: []broker.ManifestChange{{Path: "/releases/name=redis/version", Before: "1", After: "2"}},
+				},
+			}))
+		})
+
+		It("reports orphaned deployments", func() {
+			client.DoReturns(response(http.StatusGone, ""), nil)
+
+			preview, err := brokerServices.PreviewInstance(service.Instance{GUID: serviceInstanceGUID}, "upgrade")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(preview.Type).To(Equal(services.OrphanDeployment))
+		})
+
+		It("returns the error described by the broker", func() {
+			client.DoReturns(response(http.StatusInternalServerError, `{"description":"adapter failed"}`), nil)
+
+			_, err := brokerServices.PreviewInstance(service.Instance{GUID: serviceInstanceGUID}, "upgrade")
+
+			Expect(err).To(MatchError("unexpected status code: 500. description: adapter failed"))
+		})
+	})
+
 	Describe("LastOperation", func() {
 		It("returns a last operation", func() {
 			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
@@ -252,6 +405,106 @@ var _ = Describe("Broker Services", func() {
 		})
 	})
 
+	Describe("CleanupOrphanDeployments", func() {
+		It("requests the cleanup and returns the report", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(response(http.StatusOK, `{"dry_run":false,"orphans":[{"deployment_name":"service-instance_one","action":"deleting","operation":{"BoshTaskID":42,"OperationType":"delete"}}]}`), nil)
+
+			report, err := brokerServices.CleanupOrphanDeployments(mgmtapi.OrphanCleanupRequest{
+				GracePeriodSecs: 3600,
+				AllowList:       []string{"keep-me"},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodPost))
+			Expect(request.URL.Path).To(Equal("/mgmt/orphan_deployments/cleanup"))
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{"grace_period_in_seconds":3600,"allow_list":["keep-me"],"dry_run":false}`))
+
+			Expect(report.Orphans).To(HaveLen(1))
+			Expect(report.Orphans[0].DeploymentName).To(Equal("service-instance_one"))
+			Expect(report.Orphans[0].Action).To(Equal(broker.OrphanDeletionStarted))
+			Expect(report.Orphans[0].Operation.BoshTaskID).To(Equal(42))
+		})
+
+		It("returns an error when the broker responds with an error", func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+			client.DoReturns(response(http.StatusInternalServerError, `{"description":"oops"}`), nil)
+
+			_, err := brokerServices.CleanupOrphanDeployments(mgmtapi.OrphanCleanupRequest{})
+			Expect(err).To(MatchError(ContainSubstring("HTTP response status")))
+		})
+	})
+
+	Describe("iterator checkpoints", func() {
+		BeforeEach(func() {
+			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://test.test", logger)
+		})
+
+		It("returns the stored checkpoint", func() {
+			client.DoReturns(response(http.StatusOK, `{"canaries_completed":true}`), nil)
+
+			checkpoint, found, err := brokerServices.IteratorCheckpoint("upgrade-all-bosh")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(checkpoint).To(MatchJSON(`{"canaries_completed":true}`))
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodGet))
+			Expect(request.URL.Path).To(Equal("/mgmt/iterator_checkpoints/upgrade-all-bosh"))
+		})
+
+		It("reports that there is no checkpoint", func() {
+			client.DoReturns(response(http.StatusNotFound, ""), nil)
+
+			_, found, err := brokerServices.IteratorCheckpoint("upgrade-all-bosh")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns an error when the checkpoint cannot be read", func() {
+			client.DoReturns(response(http.StatusInternalServerError, ""), nil)
+
+			_, _, err := brokerServices.IteratorCheckpoint("upgrade-all-bosh")
+
+			Expect(err).To(MatchError("failed to get checkpoint upgrade-all-bosh with status code: 500"))
+		})
+
+		It("saves a checkpoint", func() {
+			client.DoReturns(response(http.StatusNoContent, ""), nil)
+
+			Expect(brokerServices.SaveIteratorCheckpoint("upgrade-all-bosh", []byte(`{"canaries_completed":true}`))).To(Succeed())
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodPut))
+			Expect(request.URL.Path).To(Equal("/mgmt/iterator_checkpoints/upgrade-all-bosh"))
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{"canaries_completed":true}`))
+		})
+
+		It("returns an error when the checkpoint cannot be saved", func() {
+			client.DoReturns(response(http.StatusInternalServerError, ""), nil)
+
+			err := brokerServices.SaveIteratorCheckpoint("upgrade-all-bosh", []byte("{}"))
+
+			Expect(err).To(MatchError("failed to save checkpoint upgrade-all-bosh with status code: 500"))
+		})
+
+		It("clears a checkpoint", func() {
+			client.DoReturns(response(http.StatusNoContent, ""), nil)
+
+			Expect(brokerServices.ClearIteratorCheckpoint("upgrade-all-bosh")).To(Succeed())
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal(http.MethodDelete))
+			Expect(request.URL.Path).To(Equal("/mgmt/iterator_checkpoints/upgrade-all-bosh"))
+		})
+	})
+
 	Describe("FilterInstances", func() {
 		It("returns the list of instances when called", func() {
 			host := "test.test"
@@ -319,7 +572,7 @@ var _ = Describe("Broker Services", func() {
 		It("returns the list of instances when called", func() {
 			host := "test.test"
 			brokerServices = services.NewBrokerServices(client, authHeaderBuilder, "http://"+host, logger)
-			client.DoReturns(response(http.StatusOK, `[{"service_instance_id": "foo", "plan_id": "plan", "space_guid": "space_id"}, {"service_instance_id": "bar", "plan_id": "another-plan", "space_guid": "space_id"}]`), nil)
+			client.DoReturns(response(http.StatusOK, `[{"service_instance_id": "foo", "plan_id": "plan", "space_guid": "space_id", "organization_guid": "org_id"}, {"service_instance_id": "bar", "plan_id": "another-plan", "space_guid": "space_id"}]`), nil)
 
 			instances, err := brokerServices.Instances(nil)
 			Expect(err).NotTo(HaveOccurred())
@@ -337,9 +590,10 @@ var _ = Describe("Broker Services", func() {
 
 			Expect(instances).To(Equal([]service.Instance{
 				{
-					GUID:         "foo",
-					PlanUniqueID: "plan",
-					SpaceGUID:    "space_id",
+					GUID:             "foo",
+					PlanUniqueID:     "plan",
+					SpaceGUID:        "space_id",
+					OrganizationGUID: "org_id",
 				},
 				{
 					GUID:         "bar",
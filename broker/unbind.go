@@ -11,9 +11,11 @@ import (
 	"fmt"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
 	"github.com/pborman/uuid"
 
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 )
 
 func (b *Broker) Unbind(
@@ -23,8 +25,7 @@ func (b *Broker) Unbind(
 	details domain.UnbindDetails,
 	asyncAllowed bool,
 ) (domain.UnbindSpec, error) {
-	b.bindLock.Lock()
-	defer b.bindLock.Unlock()
+	defer b.bindLocks.lock(instanceID)()
 
 	emptyUnbindSpec := domain.UnbindSpec{}
 	requestID := uuid.New()
@@ -32,9 +33,22 @@ func (b *Broker) Unbind(
 		requestID = brokercontext.GetReqID(ctx)
 	}
 
-	ctx = brokercontext.New(ctx, string(OperationTypeUnbind), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeUnbind), requestID, b.offering().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
+	if b.EnableAsyncBinding {
+		record, found, err := b.getBinding(ctx, instanceID, bindingID, logger)
+		if err != nil {
+			return emptyUnbindSpec, b.processError(NewGenericError(ctx, err), logger)
+		}
+		if found && record.State == domain.InProgress {
+			return emptyUnbindSpec, b.processError(NewDisplayableError(
+				apiresponses.ErrConcurrentInstanceAccess,
+				fmt.Errorf("binding %s for instance %s is still being created", bindingID, instanceID),
+			), logger)
+		}
+	}
+
 	manifest, vms, deploymentErr := b.getDeploymentInfo(instanceID, ctx, "unbind", logger)
 	if deploymentErr != nil {
 		return emptyUnbindSpec, b.processError(deploymentErr, logger)
@@ -45,17 +59,17 @@ func (b *Broker) Unbind(
 		"service_id": details.ServiceID,
 	}
 
-	deploymentVariables, err := b.boshClient.Variables(deploymentName(instanceID), logger)
+	deploymentVariables, err := b.boshClient.Variables(ctx, deploymentName(instanceID), logger)
 	if err != nil {
-		logger.Printf("failed to retrieve deployment variables for deployment '%s': %s", deploymentName(instanceID), err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to retrieve deployment variables for deployment '%s': %s", deploymentName(instanceID), err)
 	}
 
-	secretsMap, err := b.secretManager.ResolveManifestSecrets(manifest, deploymentVariables, logger)
+	secretsMap, err := b.secretManager.ResolveManifestSecrets(ctx, manifest, deploymentVariables, logger)
 	if err != nil {
-		logger.Printf("failed to resolve manifest secrets: %s", err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to resolve manifest secrets: %s", err.Error())
 	}
 
-	plan, found := b.serviceOffering.FindPlanByID(details.PlanID)
+	plan, found := b.offering().FindPlanByID(details.PlanID)
 	if !found {
 		return emptyUnbindSpec, b.processError(NewDisplayableError(
 			fmt.Errorf("plan %q not found", details.PlanID),
@@ -63,20 +77,28 @@ func (b *Broker) Unbind(
 		), logger)
 	}
 
-	dnsAddresses, err := b.boshClient.GetDNSAddresses(deploymentName(instanceID), plan.BindingWithDNS)
+	dnsAddresses, err := b.boshClient.GetDNSAddresses(ctx, deploymentName(instanceID), plan.BindingWithDNS)
 	if err != nil {
 		return emptyUnbindSpec, b.processError(NewGenericError(ctx, fmt.Errorf("failed to get required DNS info: %s", err)), logger)
 	}
 
 	logger.Printf("service adapter will delete binding with ID %s for instance %s\n", bindingID, instanceID)
-	err = b.adapterClient.DeleteBinding(bindingID, vms, manifest, requestParams, secretsMap, dnsAddresses, logger)
+	err = b.adapterClient.DeleteBinding(ctx, bindingID, vms, manifest, requestParams, secretsMap, dnsAddresses, logger)
 	if err != nil {
 		logger.Printf("delete binding: %v\n", err)
 	}
 
 	if err := adapterToAPIError(ctx, err); err != nil {
+		b.notifyBinding(ctx, OperationTypeUnbind, LifecycleStateFailed, instanceID, bindingID, details.PlanID)
 		return emptyUnbindSpec, b.processError(err, logger)
 	}
 
+	if b.EnableAsyncBinding {
+		if err := b.deleteBinding(ctx, instanceID, bindingID, logger); err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error removing the record of binding %s for instance %s: %s\n", bindingID, instanceID, err)
+		}
+	}
+	b.notifyBinding(ctx, OperationTypeUnbind, LifecycleStateSucceeded, instanceID, bindingID, details.PlanID)
+
 	return emptyUnbindSpec, nil
 }
@@ -63,11 +63,11 @@ var _ = Describe("Unbind", func() {
 		Expect(unbindErr).NotTo(HaveOccurred())
 
 		Expect(boshClient.VMsCallCount()).To(Equal(1))
-		actualDeploymentName, _ := boshClient.VMsArgsForCall(0)
+		_, actualDeploymentName, _ := boshClient.VMsArgsForCall(0)
 		Expect(actualDeploymentName).To(Equal(deploymentName))
 
 		Expect(serviceAdapter.DeleteBindingCallCount()).To(Equal(1))
-		passedBindingID, passedVms, passedManifest, passedRequestParams, passedSecretsMap, dnsAddresses, _ := serviceAdapter.DeleteBindingArgsForCall(0)
+		_, passedBindingID, passedVms, passedManifest, passedRequestParams, passedSecretsMap, dnsAddresses, _ := serviceAdapter.DeleteBindingArgsForCall(0)
 		Expect(passedBindingID).To(Equal(bindingID))
 		Expect(passedVms).To(Equal(boshVms))
 		Expect(passedManifest).To(Equal(actualManifest))
@@ -21,6 +21,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/broker/decider"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
@@ -29,11 +30,18 @@ func (b *Broker) Update(
 	instanceID string,
 	details domain.UpdateDetails,
 	asyncAllowed bool,
-) (domain.UpdateServiceSpec, error) {
+) (_ domain.UpdateServiceSpec, err error) {
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeUpdate), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeUpdate), requestID, b.offering().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
+	operationType := OperationTypeUpdate
+	defer func() {
+		if err != nil {
+			b.notifyRequestFailed(ctx, operationType, instanceID, details.PlanID, err)
+		}
+	}()
+
 	if !asyncAllowed {
 		return domain.UpdateServiceSpec{}, b.processError(apiresponses.ErrAsyncRequired, logger)
 	}
@@ -46,6 +54,7 @@ func (b *Broker) Update(
 	}
 
 	if operation == decider.Upgrade {
+		operationType = OperationTypeUpgrade
 		return b.doUpgrade(ctx, instanceID, details, logger)
 	}
 
@@ -91,34 +100,56 @@ func (b *Broker) doUpgrade(ctx context.Context, instanceID string, details domai
 }
 
 func (b *Broker) doUpdate(ctx context.Context, instanceID string, details domain.UpdateDetails, detailsMap, contextMap map[string]interface{}, siClient map[string]string, logger *log.Logger) (domain.UpdateServiceSpec, error) {
-	b.deploymentLock.Lock()
-	defer b.deploymentLock.Unlock()
+	defer b.deploymentLocks.lock(instanceID)()
 
-	plan, err := b.findPlanInCatalog(details, logger)
+	serviceOffering := b.offering()
+	plan, err := findPlanInCatalog(serviceOffering, details)
 	if err != nil {
 		return domain.UpdateServiceSpec{}, b.processError(err, logger)
 	}
 
-	if err := b.validateQuotasForUpdate(ctx, plan, details, logger); err != nil {
-		return domain.UpdateServiceSpec{}, b.processError(err, logger)
+	metadata, _, err := b.getInstanceMetadata(ctx, instanceID, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error reading instance metadata for %s: %s\n", instanceID, err)
 	}
 
-	if err := b.validatePlanSchemas(plan, details, logger); err != nil {
+	if operationData, ok := b.updateInProgress(ctx, instanceID, serviceOffering, plan, metadata, detailsMap, logger); ok {
+		operationDataJSON, err := json.Marshal(operationData)
+		if err != nil {
+			return domain.UpdateServiceSpec{}, b.processError(NewGenericError(ctx, err), logger)
+		}
+		return domain.UpdateServiceSpec{IsAsync: true, OperationData: string(operationDataJSON)}, nil
+	}
+
+	quotaReserved, err := b.validateQuotasForUpdate(ctx, instanceID, serviceOffering, plan, details, contextMap, logger)
+	if err != nil {
 		return domain.UpdateServiceSpec{}, b.processError(err, logger)
 	}
 
+	deployed := false
+	defer func() {
+		if !deployed {
+			b.releaseQuotaReservation(ctx, instanceID, OperationData{QuotaReserved: quotaReserved}, logger)
+		}
+	}()
+
+	if err := b.validatePlanSchemas(ctx, serviceOffering, plan, details, logger); err != nil {
+		return domain.UpdateServiceSpec{}, b.processError(adapterError(ctx, err, err), logger)
+	}
+
 	var boshContextID string
 	if len(plan.PostDeployErrands()) > 0 {
 		boshContextID = uuid.New()
 	}
 
-	secretMap, err := b.getSecretMap(instanceID, logger)
+	secretMap, err := b.getSecretMap(ctx, instanceID, logger)
 	if err != nil {
 		return domain.UpdateServiceSpec{}, b.processError(NewGenericError(ctx, err), logger)
 	}
 
 	logger.Printf("updating instance %s", instanceID)
 	boshTaskID, manifest, brokerLabels, err := b.deployer.Update(
+		ctx,
 		deploymentName(instanceID),
 		details.PlanID,
 		detailsMap,
@@ -131,12 +162,21 @@ func (b *Broker) doUpdate(ctx context.Context, instanceID string, details domain
 	if err != nil {
 		return b.handleUpdateError(ctx, err, logger)
 	}
-
-	abridgedPlan := plan.AdapterPlan(b.serviceOffering.GlobalProperties)
-	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(instanceID, abridgedPlan, manifest, logger)
+	deployed = true
+
+	record := newOperationRecord(ctx, OperationTypeUpdate, details.PreviousValues.PlanID, plan.ID, boshTaskID, boshContextID)
+	b.recordInstanceMetadata(ctx, instanceID, metadata.
+		withPlan(plan.ID).
+		mergeParameters(detailsMap).
+		withLabels(brokerLabels).
+		withOperation(record), logger)
+	b.notifyOperationStarted(instanceID, record)
+
+	abridgedPlan := plan.AdapterPlan(serviceOffering.GlobalProperties)
+	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(ctx, instanceID, abridgedPlan, manifest, logger)
 	if err != nil {
 		if _, ok := err.(serviceadapter.NotImplementedError); !ok {
-			return domain.UpdateServiceSpec{}, b.processError(NewGenericError(ctx, err), logger)
+			return domain.UpdateServiceSpec{}, b.processError(adapterError(ctx, err, NewGenericError(ctx, err)), logger)
 		}
 	}
 
@@ -149,6 +189,7 @@ func (b *Broker) doUpdate(ctx context.Context, instanceID string, details domain
 		OperationType: OperationTypeUpdate,
 		BoshContextID: boshContextID,
 		Errands:       plan.PostDeployErrands(),
+		QuotaReserved: quotaReserved,
 	})
 	if err != nil {
 		return domain.UpdateServiceSpec{}, b.processError(NewGenericError(brokercontext.WithBoshTaskID(ctx, boshTaskID), err), logger)
@@ -178,7 +219,7 @@ func (b *Broker) handleUpdateError(ctx context.Context, err error, logger *log.L
 		return domain.UpdateServiceSpec{}, b.processError(NewOperationInProgressError(errors.New(OperationInProgressMessage)), logger)
 	case PlanNotFoundError, DeploymentNotFoundError, OperationAlreadyCompletedError:
 		return domain.UpdateServiceSpec{}, b.processError(err, logger)
-	case serviceadapter.UnknownFailureError:
+	case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError:
 		return domain.UpdateServiceSpec{}, b.processError(adapterToAPIError(ctx, err), logger)
 	case error:
 		return domain.UpdateServiceSpec{}, b.processError(NewGenericError(ctx, fmt.Errorf("error deploying instance: %s", err)), logger)
@@ -186,8 +227,8 @@ func (b *Broker) handleUpdateError(ctx context.Context, err error, logger *log.L
 	return domain.UpdateServiceSpec{}, nil
 }
 
-func (b *Broker) findPlanInCatalog(details domain.UpdateDetails, logger *log.Logger) (config.Plan, error) {
-	plan, found := b.serviceOffering.FindPlanByID(details.PlanID)
+func findPlanInCatalog(serviceOffering config.ServiceOffering, details domain.UpdateDetails) (config.Plan, error) {
+	plan, found := serviceOffering.FindPlanByID(details.PlanID)
 	if !found {
 		return config.Plan{}, PlanNotFoundError{PlanGUID: details.PlanID}
 	}
@@ -195,26 +236,20 @@ func (b *Broker) findPlanInCatalog(details domain.UpdateDetails, logger *log.Log
 	return plan, nil
 }
 
-func (b *Broker) validateQuotasForUpdate(ctx context.Context, plan config.Plan, details domain.UpdateDetails, logger *log.Logger) error {
-	if details.PreviousValues.PlanID != plan.ID {
-		cfPlanCounts, err := b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
-		if err != nil {
-			return NewGenericError(ctx, err)
-		}
-
-		quotasErrors, ok := b.checkQuotas(ctx, plan, cfPlanCounts, b.serviceOffering.ID, logger)
-		if !ok {
-			return quotasErrors
-		}
+// validateQuotasForUpdate checks the quotas when the plan changes, reserving
+// quota for the update. It returns whether quota was reserved.
+func (b *Broker) validateQuotasForUpdate(ctx context.Context, instanceID string, serviceOffering config.ServiceOffering, plan config.Plan, details domain.UpdateDetails, contextMap map[string]interface{}, logger *log.Logger) (bool, error) {
+	if details.PreviousValues.PlanID == plan.ID {
+		return false, nil
 	}
 
-	return nil
+	return b.checkAndReserveQuotas(ctx, instanceID, serviceOffering, plan, details.PreviousValues.PlanID, contextMap, logger)
 }
 
-func (b *Broker) validatePlanSchemas(plan config.Plan, details domain.UpdateDetails, logger *log.Logger) error {
+func (b *Broker) validatePlanSchemas(ctx context.Context, serviceOffering config.ServiceOffering, plan config.Plan, details domain.UpdateDetails, logger *log.Logger) error {
 	if b.EnablePlanSchemas {
 		var schemas domain.ServiceSchemas
-		schemas, err := b.adapterClient.GeneratePlanSchema(plan.AdapterPlan(b.serviceOffering.GlobalProperties), logger)
+		schemas, err := b.adapterClient.GeneratePlanSchema(ctx, plan.AdapterPlan(serviceOffering.GlobalProperties), logger)
 		if err != nil {
 			if _, ok := err.(serviceadapter.NotImplementedError); !ok {
 				return err
@@ -246,18 +281,18 @@ func (b *Broker) validatePlanSchemas(plan config.Plan, details domain.UpdateDeta
 	return nil
 }
 
-func (b *Broker) getSecretMap(instanceID string, logger *log.Logger) (map[string]string, error) {
-	manifest, _, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+func (b *Broker) getSecretMap(ctx context.Context, instanceID string, logger *log.Logger) (map[string]string, error) {
+	manifest, _, err := b.boshClient.GetDeployment(ctx, deploymentName(instanceID), logger)
 	if err != nil {
 		return nil, err
 	}
 
-	deploymentVariables, err := b.boshClient.Variables(deploymentName(instanceID), logger)
+	deploymentVariables, err := b.boshClient.Variables(ctx, deploymentName(instanceID), logger)
 	if err != nil {
 		return nil, err
 	}
 
-	secretMap, err := b.secretManager.ResolveManifestSecrets(manifest, deploymentVariables, logger)
+	secretMap, err := b.secretManager.ResolveManifestSecrets(ctx, manifest, deploymentVariables, logger)
 	if err != nil {
 		return nil, err
 	}
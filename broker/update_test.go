@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
@@ -19,6 +20,7 @@ import (
 	. "github.com/onsi/gomega"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/decider"
 	brokerfakes "github.com/pivotal-cf/on-demand-service-broker/broker/fakes"
@@ -123,7 +125,7 @@ var _ = Describe("Update", func() {
 
 		It("invokes the deployer with the correct arguments", func() {
 			Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-			_, planID, actualRequestParams, _, _, actualSecretsMap, _, _ := fakeDeployer.UpdateArgsForCall(0)
+			_, _, planID, actualRequestParams, _, _, actualSecretsMap, _, _ := fakeDeployer.UpdateArgsForCall(0)
 
 			Expect(actualRequestParams).To(Equal(map[string]interface{}{
 				"plan_id":    planID,
@@ -143,6 +145,109 @@ var _ = Describe("Update", func() {
 			Expect(actualSecretsMap).To(Equal(expectedSecretsMap))
 		})
 
+		Context("recording the instance metadata", func() {
+			BeforeEach(func() {
+				boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+					Type:    broker.InstanceMetadataConfigType,
+					Name:    "service-instance_some-instance-id",
+					Content: `{"plan_id":"some-plan-id","parameters":{"foo":"old","baz":"qux"}}`,
+				}}, nil)
+			})
+
+			It("stores the new plan and merges the parameters", func() {
+				Expect(updateError).NotTo(HaveOccurred())
+				Expect(boshClient.UpdateConfigCallCount()).To(Equal(2))
+				_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(1)
+				Expect(configType).To(Equal(broker.InstanceMetadataConfigType))
+				Expect(configName).To(Equal("service-instance_some-instance-id"))
+				var metadata broker.InstanceMetadata
+				Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+				Expect(metadata.PlanID).To(Equal("another-plan"))
+				Expect(metadata.Parameters).To(Equal(map[string]interface{}{"foo": "bar", "baz": "qux"}))
+			})
+
+			It("records the operation with the plan change", func() {
+				_, _, _, content, _ := boshClient.UpdateConfigArgsForCall(1)
+				var metadata broker.InstanceMetadata
+				Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+				Expect(metadata.Operations).To(ConsistOf(SatisfyAll(
+					HaveField("Type", broker.OperationTypeUpdate),
+					HaveField("PlanIDBefore", "some-plan-id"),
+					HaveField("PlanIDAfter", "another-plan"),
+					HaveField("BoshTaskID", boshTaskID),
+				)))
+			})
+
+			It("does not fail the update when the metadata cannot be stored", func() {
+				boshClient.UpdateConfigReturns(errors.New("config failed"))
+				Expect(updateError).NotTo(HaveOccurred())
+			})
+
+			Context("when the creation time of the instance is not recorded", func() {
+				BeforeEach(func() {
+					boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+						Type:    broker.InstanceMetadataConfigType,
+						Name:    "service-instance_some-instance-id",
+						Content: `{"plan_id":"some-plan-id","operations":[{"type":"create","plan_id_after":"some-plan-id","bosh_task_id":7,"started_at":"2020-01-02T03:04:05Z"}]}`,
+					}}, nil)
+				})
+
+				It("records it from the recorded create operation", func() {
+					_, _, _, content, _ := boshClient.UpdateConfigArgsForCall(1)
+					var metadata broker.InstanceMetadata
+					Expect(json.Unmarshal(content, &metadata)).To(Succeed())
+					Expect(metadata.CreatedAt).NotTo(BeNil())
+					Expect(*metadata.CreatedAt).To(Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+				})
+			})
+		})
+
+		Context("when the request repeats the update in progress", func() {
+			BeforeEach(func() {
+				boshClient.GetConfigsReturns([]boshdirector.BoshConfig{{
+					Type:    broker.InstanceMetadataConfigType,
+					Name:    "service-instance_some-instance-id",
+					Content: `{"plan_id":"another-plan","parameters":{"foo":"bar"},"operations":[{"type":"update","plan_id_before":"some-plan-id","plan_id_after":"another-plan","bosh_task_id":99}]}`,
+				}}, nil)
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 99, State: boshdirector.TaskProcessing}, nil)
+			})
+
+			It("returns the operation of the update in progress", func() {
+				Expect(updateError).NotTo(HaveOccurred())
+				Expect(updateSpec.IsAsync).To(BeTrue())
+				var operationData broker.OperationData
+				Expect(json.Unmarshal([]byte(updateSpec.OperationData), &operationData)).To(Succeed())
+				Expect(operationData).To(Equal(broker.OperationData{
+					BoshTaskID:    99,
+					OperationType: broker.OperationTypeUpdate,
+					QuotaReserved: true,
+				}))
+				Expect(fakeDeployer.UpdateCallCount()).To(BeZero())
+			})
+
+			When("the update has finished", func() {
+				BeforeEach(func() {
+					boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 99, State: boshdirector.TaskDone}, nil)
+				})
+
+				It("updates the instance", func() {
+					Expect(updateError).NotTo(HaveOccurred())
+					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
+				})
+			})
+
+			When("the parameters change", func() {
+				BeforeEach(func() {
+					arbitraryParams = map[string]interface{}{"foo": "baz"}
+				})
+
+				It("updates the instance", func() {
+					Expect(updateError).NotTo(HaveOccurred())
+					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
+				})
+			})
+		})
+
 		Context("the request is switching plan", func() {
 			Context("and the new plan's quota has not been met", func() {
 				It("does not error", func() {
@@ -151,7 +256,7 @@ var _ = Describe("Update", func() {
 
 				It("calls the deployer without a bosh context id", func() {
 					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-					_, _, _, _, actualBoshContextID, _, _, _ := fakeDeployer.UpdateArgsForCall(0)
+					_, _, _, _, _, actualBoshContextID, _, _, _ := fakeDeployer.UpdateArgsForCall(0)
 					Expect(actualBoshContextID).To(BeEmpty())
 				})
 
@@ -159,9 +264,19 @@ var _ = Describe("Update", func() {
 					Expect(updateSpec.IsAsync).To(BeTrue())
 				})
 
+				It("reserves quota for the plan change", func() {
+					_, configType, configName, content, _ := boshClient.UpdateConfigArgsForCall(0)
+					Expect(configType).To(Equal(broker.QuotaReservationConfigType))
+					Expect(configName).To(Equal(deploymentName(instanceID)))
+					var reservation broker.QuotaReservation
+					Expect(json.Unmarshal(content, &reservation)).To(Succeed())
+					Expect(reservation.PlanID).To(Equal(newPlanID))
+					Expect(reservation.PreviousPlanID).To(Equal(oldPlanID))
+				})
+
 				It("returns the bosh task ID and operation type", func() {
 					data := unmarshalOperationData(updateSpec)
-					Expect(data).To(Equal(broker.OperationData{BoshTaskID: boshTaskID, OperationType: broker.OperationTypeUpdate}))
+					Expect(data).To(Equal(broker.OperationData{BoshTaskID: boshTaskID, OperationType: broker.OperationTypeUpdate, QuotaReserved: true}))
 				})
 
 				It("logs with a request ID", func() {
@@ -181,7 +296,7 @@ var _ = Describe("Update", func() {
 
 				It("returns the bosh task ID and operation type", func() {
 					data := unmarshalOperationData(updateSpec)
-					Expect(data).To(Equal(broker.OperationData{BoshTaskID: boshTaskID, OperationType: broker.OperationTypeUpdate}))
+					Expect(data).To(Equal(broker.OperationData{BoshTaskID: boshTaskID, OperationType: broker.OperationTypeUpdate, QuotaReserved: true}))
 				})
 			})
 
@@ -207,7 +322,7 @@ var _ = Describe("Update", func() {
 
 				It("calls the deployer with a bosh context id", func() {
 					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-					_, _, _, _, actualBoshContextID, _, _, _ := fakeDeployer.UpdateArgsForCall(0)
+					_, _, _, _, _, actualBoshContextID, _, _, _ := fakeDeployer.UpdateArgsForCall(0)
 					Expect(actualBoshContextID).NotTo(BeEmpty())
 				})
 			})
@@ -321,7 +436,7 @@ var _ = Describe("Update", func() {
 
 					It("calls the deployer with a bosh context id", func() {
 						Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-						_, _, _, _, actualBoshContextID, _, _, _ := fakeDeployer.UpdateArgsForCall(0)
+						_, _, _, _, _, actualBoshContextID, _, _, _ := fakeDeployer.UpdateArgsForCall(0)
 						Expect(actualBoshContextID).NotTo(BeEmpty())
 					})
 				})
@@ -687,6 +802,78 @@ var _ = Describe("Update", func() {
 			})
 		})
 
+		When("org and space quotas are enabled", func() {
+			updateWithScopedQuotas := func(counts cf.OrgAndSpaceInstanceCounts) error {
+				newPlan := existingPlan
+				newPlan.Quotas = config.Quotas{Resources: map[string]config.ResourceQuota{"memory": {Cost: 4}}}
+				oldPlan := secondPlan
+				oldPlan.Quotas = config.Quotas{Resources: map[string]config.ResourceQuota{"memory": {Cost: 1}}}
+
+				two := 2
+				catalog := serviceCatalog
+				catalog.Plans = config.Plans{newPlan, oldPlan}
+				catalog.GlobalQuotas = config.Quotas{
+					Orgs: &config.ScopedQuotas{
+						Default: config.Quotas{ServiceInstanceLimit: &two},
+					},
+					Spaces: &config.ScopedQuotas{
+						Default: config.Quotas{Resources: map[string]config.ResourceQuota{"memory": {Limit: 8}}},
+					},
+				}
+				cfClient.CountInstancesOfServiceOfferingReturns(map[cf.ServicePlan]int{}, nil)
+				cfClient.CountInstancesOfServiceOfferingByOrgAndSpaceReturns(counts, nil)
+				fakeDeployer = new(brokerfakes.FakeDeployer)
+				b = createBrokerWithServiceCatalog(catalog)
+
+				rawContext, err := json.Marshal(map[string]interface{}{
+					"platform":          "cloudfoundry",
+					"organization_guid": "an-org",
+					"space_guid":        "a-space",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, updateErr := b.Update(context.Background(), instanceID, domain.UpdateDetails{
+					PlanID:     newPlan.ID,
+					RawContext: rawContext,
+					ServiceID:  "serviceID",
+					PreviousValues: domain.PreviousValues{
+						PlanID:    oldPlan.ID,
+						OrgID:     "an-org",
+						ServiceID: "serviceID",
+						SpaceID:   "a-space",
+					},
+				}, true)
+				return updateErr
+			}
+
+			planCounts := func(newPlanCount, oldPlanCount int) map[cf.ServicePlan]int {
+				return map[cf.ServicePlan]int{
+					cfServicePlan("guid_1234", existingPlan.ID, "url", "name"): newPlanCount,
+					cfServicePlan("guid_2345", secondPlan.ID, "url", "name"):   oldPlanCount,
+				}
+			}
+
+			It("counts the instance as moving to the new plan", func() {
+				updateErr := updateWithScopedQuotas(cf.OrgAndSpaceInstanceCounts{
+					Orgs:   map[string]map[cf.ServicePlan]int{"an-org": planCounts(1, 1)},
+					Spaces: map[string]map[cf.ServicePlan]int{"a-space": planCounts(1, 1)},
+				})
+
+				Expect(updateErr).NotTo(HaveOccurred())
+				Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("fails when the space resource quota would be exceeded", func() {
+				updateErr := updateWithScopedQuotas(cf.OrgAndSpaceInstanceCounts{
+					Orgs:   map[string]map[cf.ServicePlan]int{"an-org": planCounts(0, 1)},
+					Spaces: map[string]map[cf.ServicePlan]int{"a-space": planCounts(2, 1)},
+				})
+
+				Expect(updateErr).To(MatchError("space a-space quotas [memory: (limit 8, used 8, requires 4)] would be exceeded by this deployment"))
+				Expect(fakeDeployer.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
 		When("the plan does not exist in the catalog", func() {
 			BeforeEach(func() {
 				newPlanID = "invalid-plan-guid"
@@ -718,7 +905,7 @@ var _ = Describe("Update", func() {
 
 			It("calls the adapter", func() {
 				Expect(serviceAdapter.GenerateDashboardUrlCallCount()).To(Equal(1))
-				instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
+				_, instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
 				Expect(instanceID).To(Equal(instanceID))
 				expectedProperties := sdk.Properties{
 					"a_global_property":          "overrides_global_value",
@@ -779,7 +966,7 @@ var _ = Describe("Update", func() {
 
 			It("passes the client to the deployer", func() {
 				Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-				_, _, _, _, _, _, actualClient, _ := fakeDeployer.UpdateArgsForCall(0)
+				_, _, _, _, _, _, _, actualClient, _ := fakeDeployer.UpdateArgsForCall(0)
 				Expect(actualClient).To(Equal(existingClient))
 			})
 
@@ -15,12 +15,12 @@ import (
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"github.com/pborman/uuid"
 
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 func (b *Broker) Upgrade(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (OperationData, string, map[string]any, error) {
-	b.deploymentLock.Lock()
-	defer b.deploymentLock.Unlock()
+	defer b.deploymentLocks.lock(instanceID)()
 
 	logger.Printf("upgrading instance %s", instanceID)
 
@@ -28,9 +28,10 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, details domain.
 		return OperationData{}, "", nil, b.processError(errors.New("no plan ID provided in upgrade request body"), logger)
 	}
 
-	plan, found := b.serviceOffering.FindPlanByID(details.PlanID)
+	serviceOffering := b.offering()
+	plan, found := serviceOffering.FindPlanByID(details.PlanID)
 	if !found {
-		logger.Printf("error: finding plan ID %s", details.PlanID)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error: finding plan ID %s", details.PlanID)
 		return OperationData{}, "", nil, b.processError(fmt.Errorf("plan %s not found", details.PlanID), logger)
 	}
 
@@ -55,6 +56,7 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, details domain.
 	}
 
 	taskID, manifest, brokerLabels, err := b.deployer.Upgrade(
+		ctx,
 		deploymentName(instanceID),
 		plan,
 		contextMap,
@@ -67,12 +69,16 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, details domain.
 		return OperationData{}, "", nil, err
 	}
 
-	abridgedPlan := plan.AdapterPlan(b.serviceOffering.GlobalProperties)
+	record := newOperationRecord(ctx, OperationTypeUpgrade, plan.ID, plan.ID, taskID, boshContextID)
+	b.recordLabeledOperation(ctx, instanceID, plan.ID, brokerLabels, record, logger)
+	b.notifyOperationStarted(instanceID, record)
 
-	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(instanceID, abridgedPlan, manifest, logger)
+	abridgedPlan := plan.AdapterPlan(serviceOffering.GlobalProperties)
+
+	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(ctx, instanceID, abridgedPlan, manifest, logger)
 	if err != nil {
 		if _, ok := err.(serviceadapter.NotImplementedError); !ok {
-			return OperationData{}, "", nil, b.processError(NewGenericError(ctx, err), logger)
+			return OperationData{}, "", nil, b.processError(adapterError(ctx, err, NewGenericError(ctx, err)), logger)
 		}
 	}
 
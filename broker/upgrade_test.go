@@ -74,7 +74,7 @@ var _ = Describe("Upgrade", func() {
 		Expect(fakeDeployer.CreateCallCount()).To(Equal(0))
 		Expect(fakeDeployer.UpgradeCallCount()).To(Equal(1))
 		Expect(fakeDeployer.UpdateCallCount()).To(Equal(0))
-		actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, _, _ := fakeDeployer.UpgradeArgsForCall(0)
+		_, actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, _, _ := fakeDeployer.UpgradeArgsForCall(0)
 		Expect(actualPlan).To(Equal(existingPlan))
 		Expect(actualRequestParams).To(Equal(map[string]interface{}{
 			"context": arbContext,
@@ -115,7 +115,7 @@ var _ = Describe("Upgrade", func() {
 
 			upgradeOperationData, _, _, _ = b.Upgrade(context.Background(), instanceID, details, logger)
 
-			_, _, _, contextID, _, _ := fakeDeployer.UpgradeArgsForCall(0)
+			_, _, _, _, contextID, _, _ := fakeDeployer.UpgradeArgsForCall(0)
 			Expect(contextID).NotTo(BeEmpty())
 			Expect(upgradeOperationData.BoshContextID).NotTo(BeEmpty())
 			Expect(upgradeOperationData).To(Equal(
@@ -211,7 +211,7 @@ tags:
 			Expect(upgradeError).NotTo(HaveOccurred())
 
 			Expect(serviceAdapter.GenerateDashboardUrlCallCount()).To(Equal(1))
-			instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
+			_, instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
 			Expect(instanceID).To(Equal(instanceID))
 			expectedProperties := sdk.Properties{
 				"some_other_global_property": "other_global_value",
@@ -283,7 +283,7 @@ tags:
 			Expect(upgradeError).NotTo(HaveOccurred())
 
 			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(1))
-			_, _, _, _, actualClient, _ := fakeDeployer.UpgradeArgsForCall(0)
+			_, _, _, _, _, actualClient, _ := fakeDeployer.UpgradeArgsForCall(0)
 			Expect(actualClient).To(Equal(existingClient))
 		})
 
@@ -0,0 +1,67 @@
+package brokerinitiator
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// Reloader re-reads the broker configuration and swaps the service catalog
+// and service deployment of every offering in the offering store that the
+// running broker reads them from, all at once. Other settings, such as the
+// broker credentials or the service adapter, are only read at startup. A
+// configuration that fails to load or validate is rejected and the current one
+// stays in use.
+type Reloader struct {
+	loadConfig func() (config.Config, error)
+	offerings  *config.OfferingStore
+	logger     *log.Logger
+
+	mu   sync.Mutex
+	conf config.Config
+}
+
+func NewReloader(conf config.Config, loadConfig func() (config.Config, error), offerings *config.OfferingStore, logger *log.Logger) *Reloader {
+	return &Reloader{
+		loadConfig: loadConfig,
+		offerings:  offerings,
+		logger:     logger,
+		conf:       conf,
+	}
+}
+
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newConf, err := r.loadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %s", err)
+	}
+
+	if err := r.conf.CheckReloadable(newConf); err != nil {
+		return err
+	}
+
+	r.offerings.SetOfferings(newConf.Offerings())
+	r.conf = newConf
+
+	return nil
+}
+
+// ReloadOnSignal reloads the configuration every time a signal is received,
+// until signals is closed.
+func (r *Reloader) ReloadOnSignal(signals <-chan os.Signal) {
+	for sig := range signals {
+		r.logger.Printf("received %s, reloading config\n", sig)
+		if err := r.Reload(); err != nil {
+			loggerfactory.WithLevel(r.logger, loggerfactory.ErrorLevel).Printf("error reloading config, the current config stays in use: %s\n", err)
+			continue
+		}
+		r.logger.Println("config reloaded")
+	}
+}
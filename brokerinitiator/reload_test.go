@@ -0,0 +1,76 @@
+package brokerinitiator_test
+
+import (
+	"errors"
+	"log"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/pivotal-cf/on-demand-service-broker/brokerinitiator"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+var _ = Describe("Reloader", func() {
+	var (
+		currentConfig  config.Config
+		reloadedConfig config.Config
+		loadErr        error
+		offerings      *config.OfferingStore
+		logBuffer      *gbytes.Buffer
+		reloader       *brokerinitiator.Reloader
+	)
+
+	BeforeEach(func() {
+		currentConfig = config.Config{
+			ServiceCatalog: config.ServiceOffering{ID: "service-id", Name: "service-name", Plans: config.Plans{{ID: "a-plan-id"}}},
+		}
+		reloadedConfig = currentConfig
+		reloadedConfig.ServiceCatalog.Plans = config.Plans{{ID: "a-plan-id"}, {ID: "new-plan-id"}}
+		loadErr = nil
+
+		offerings = config.NewOfferingStore(currentConfig.Offerings())
+		logBuffer = gbytes.NewBuffer()
+		reloader = brokerinitiator.NewReloader(currentConfig, func() (config.Config, error) {
+			return reloadedConfig, loadErr
+		}, offerings, log.New(logBuffer, "", 0))
+	})
+
+	It("swaps the reloaded offerings into the store", func() {
+		Expect(reloader.Reload()).To(Succeed())
+
+		Expect(offerings.Offerings()).To(Equal(reloadedConfig.Offerings()))
+		Expect(offerings.Offering("service-id").ServiceCatalog.Plans).To(HaveLen(2))
+	})
+
+	It("keeps the current config when the new one fails to load", func() {
+		loadErr = errors.New("invalid config")
+
+		Expect(reloader.Reload()).To(MatchError("error loading config: invalid config"))
+
+		Expect(offerings.ServiceCatalogs()).To(Equal(currentConfig.ServiceCatalogs()))
+	})
+
+	It("keeps the current config when the offerings cannot be reloaded", func() {
+		reloadedConfig.ServiceCatalog.ID = "another-service-id"
+
+		Expect(reloader.Reload()).To(HaveOccurred())
+
+		Expect(offerings.ServiceCatalogs()).To(Equal(currentConfig.ServiceCatalogs()))
+	})
+
+	It("reloads on every signal and logs failures", func() {
+		signals := make(chan os.Signal, 2)
+		signals <- syscall.SIGHUP
+		close(signals)
+
+		reloader.ReloadOnSignal(signals)
+
+		Expect(offerings.Offering("service-id").ServiceCatalog.Plans).To(HaveLen(2))
+		Expect(logBuffer).To(gbytes.Say("received hangup, reloading config"))
+		Expect(logBuffer).To(gbytes.Say("config reloaded"))
+	})
+})
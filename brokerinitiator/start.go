@@ -1,9 +1,13 @@
 package brokerinitiator
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	credhub2 "code.cloudfoundry.org/credhub-cli/credhub"
 	"code.cloudfoundry.org/credhub-cli/credhub/auth"
@@ -17,7 +21,11 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/hasher"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/manifestsecrets"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 	"github.com/pivotal-cf/on-demand-service-broker/network"
+	"github.com/pivotal-cf/on-demand-service-broker/notifications"
+	"github.com/pivotal-cf/on-demand-service-broker/routingbroker"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-service-broker/startupchecker"
@@ -33,77 +41,161 @@ func Initiate(conf config.Config,
 	commandRunner serviceadapter.CommandRunner,
 	stopServer chan os.Signal,
 	loggerFactory *loggerfactory.LoggerFactory,
+	loadConfig func() (config.Config, error),
 ) {
 	logger := loggerFactory.New()
-	var err error
 	startupChecks := buildStartupChecks(conf, cfClient, logger, brokerBoshClient)
+	boshCredhubStore := buildCredhubStore(conf, logger)
+	manifestSecretManager := manifestsecrets.BuildManager(conf.Broker.EnableSecureManifests, new(manifestsecrets.CredHubPathMatcher), boshCredhubStore)
 
-	serviceAdapter := &serviceadapter.Client{
-		ExternalBinPath: conf.ServiceAdapter.Path,
-		CommandRunner:   commandRunner,
-		UsingStdin:      conf.Broker.UsingStdin,
+	client, err := uaa.New(conf.CF.UAA, conf.CF.TrustedCert, conf.CF.DisableSSLCertVerification)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error creating UAA client: #{err}")
 	}
 
-	manifestGenerator := task.NewManifestGenerator(
-		serviceAdapter,
-		conf.ServiceCatalog,
-		conf.ServiceDeployment.Stemcells,
-		conf.ServiceDeployment.Releases,
-	)
-	odbSecrets := manifestsecrets.ODBSecrets{ServiceOfferingID: conf.ServiceCatalog.ID}
-	boshCredhubStore := buildCredhubStore(conf, logger)
-
-	deploymentManager := task.NewDeployer(taskBoshClient, manifestGenerator, odbSecrets, boshCredhubStore)
-	deploymentManager.DisableBoshConfigs = conf.Broker.DisableBoshConfigs
-	deploymentManager.SkipCheckForPendingChanges = conf.Broker.SkipCheckForPendingChanges
+	registry := metrics.NewRegistry()
+	commandRunner = serviceadapter.NewInstrumentedCommandRunner(commandRunner, registry)
 
-	manifestSecretManager := manifestsecrets.BuildManager(conf.Broker.EnableSecureManifests, new(manifestsecrets.CredHubPathMatcher), boshCredhubStore)
+	var eventNotifier *notifications.WebhookNotifier
+	if len(conf.Broker.Notifications.Webhooks) > 0 {
+		eventNotifier = notifications.NewWebhookNotifier(conf.Broker.Notifications, notifications.NewHTTPClient(conf.Broker.Notifications), notifications.DefaultRetryInterval, logger)
+		// the queued events are sent within the same bound as the requests
+		// in flight when the broker stops
+		defer eventNotifier.Shutdown(time.Duration(conf.Broker.ShutdownTimeoutSecs) * time.Second)
+	}
 
-	instanceLister, err := service.BuildInstanceLister(cfClient, conf.ServiceCatalog.ID, conf.ServiceInstancesAPI, logger)
-	if err != nil {
-		logger.Fatalf("error building instance lister: %s", err)
+	var runtimeCredentialStore *credhub.Store
+	if conf.HasRuntimeCredHub() {
+		runtimeCredentialStore = buildRuntimeCredentialStore(conf, logger)
 	}
 
-	telemetryLogger := telemetry.Build(conf.Broker.EnableTelemetry, conf.ServiceCatalog, logger)
+	// every component that serves an offering reads it from the store, so that
+	// a reload replaces the configuration of all of them at once
+	offerings := config.NewOfferingStore(conf.Offerings())
 
-	var onDemandBroker apiserver.CombinedBroker
-	onDemandBroker, err = broker.New(brokerBoshClient, cfClient, conf.ServiceCatalog, conf.Broker, startupChecks, serviceAdapter, deploymentManager, manifestSecretManager, instanceLister, &hasher.MapHasher{}, loggerFactory, telemetryLogger, decider.Decider{})
+	// max_concurrent_adapter_calls bounds the adapter calls of all offerings
+	adapterLimiter := broker.NewAdapterLimiter(conf.Broker.MaxConcurrentAdapterCalls)
 
-	if err != nil {
-		logger.Fatalf("error starting broker: %s", err)
+	var routes []routingbroker.Route
+	var planFinder routingbroker.InstancePlanFinder
+	var instanceListers []service.InstanceLister
+	var telemetryLoggers []broker.TelemetryLogger
+	for i, offering := range conf.Offerings() {
+		serviceAdapter := &serviceadapter.Client{
+			ExternalBinPath: offering.ServiceAdapter.Path,
+			CommandRunner:   commandRunner,
+			UsingStdin:      conf.Broker.UsingStdin,
+			Timeouts:        offering.ServiceAdapter.Timeouts(),
+		}
+		if offering.ServiceAdapter.UsesServer() {
+			serviceAdapter.ExternalBinPath = "unix://" + offering.ServiceAdapter.Socket
+			serviceAdapter.CommandRunner = serviceadapter.NewInstrumentedCommandRunner(
+				serviceadapter.NewServerCommandRunner(offering.ServiceAdapter.Socket),
+				registry,
+			)
+		}
+
+		manifestGenerator := task.NewManifestGenerator(
+			serviceAdapter,
+			offering.ServiceCatalog,
+			offering.ServiceDeployment.Stemcells,
+			offering.ServiceDeployment.Releases,
+		)
+		manifestGenerator.SetOfferingStore(offerings)
+		odbSecrets := manifestsecrets.ODBSecrets{ServiceOfferingID: offering.ServiceCatalog.ID}
+
+		deploymentManager := task.NewDeployer(taskBoshClient, manifestGenerator, odbSecrets, boshCredhubStore)
+		deploymentManager.DisableBoshConfigs = conf.Broker.DisableBoshConfigs
+		deploymentManager.SkipCheckForPendingChanges = conf.Broker.SkipCheckForPendingChanges
+
+		instanceLister, err := service.BuildInstanceLister(cfClient, offering.ServiceCatalog.ID, conf.ServiceInstancesAPI, logger)
+		if err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error building instance lister: %s", err)
+		}
+
+		telemetryLogger := telemetry.Build(conf.Broker.EnableTelemetry, offering.ServiceCatalog, logger)
+
+		offeringStartupChecks := startupChecks
+		if i > 0 {
+			offeringStartupChecks = buildPlanConsistencyChecks(conf, offering.ServiceCatalog, cfClient, logger)
+		}
+
+		offeringBroker, err := broker.New(brokerBoshClient, cfClient, offering.ServiceCatalog, conf.Broker, offeringStartupChecks, serviceAdapter, deploymentManager, manifestSecretManager, instanceLister, &hasher.MapHasher{}, loggerFactory, telemetryLogger, decider.Decider{})
+
+		if err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error starting broker: %s", err)
+		}
+		offeringBroker.SetOfferingStore(offerings)
+		offeringBroker.SetAdapterLimiter(adapterLimiter)
+		if eventNotifier != nil {
+			offeringBroker.SetEventNotifier(eventNotifier)
+		}
+		if planFinder == nil {
+			// the instance metadata is on the BOSH director that all offerings share
+			planFinder = offeringBroker
+		}
+
+		var onDemandBroker apiserver.CombinedBroker = offeringBroker
+
+		onDemandBroker.SetUAAClient(client)
+
+		if runtimeCredentialStore != nil {
+			offeringBroker.SetBindingCredentialStore(runtimeCredentialStore)
+			onDemandBroker = credhubbroker.New(onDemandBroker, runtimeCredentialStore, offering.ServiceCatalog.Name, loggerFactory)
+		}
+
+		routes = append(routes, routingbroker.Route{ServiceOffering: offering.ServiceCatalog, Broker: onDemandBroker})
+		instanceListers = append(instanceListers, instanceLister)
+		telemetryLoggers = append(telemetryLoggers, telemetryLogger)
 	}
 
-	client, err := uaa.New(conf.CF.UAA, conf.CF.TrustedCert, conf.CF.DisableSSLCertVerification)
-	if err != nil {
-		logger.Fatalf("error creating UAA client: #{err}")
+	var onDemandBroker apiserver.CombinedBroker = routes[0].Broker
+	if len(routes) > 1 {
+		routingBroker := routingbroker.New(routes, planFinder)
+		routingBroker.SetOfferingStore(offerings)
+		routingBroker.SetAdapterLimiter(adapterLimiter)
+		onDemandBroker = routingBroker
 	}
-	onDemandBroker.SetUAAClient(client)
 
-	if conf.HasRuntimeCredHub() {
-		onDemandBroker = wrapWithCredHubBroker(conf, logger, onDemandBroker, loggerFactory)
+	var configReloader mgmtapi.ConfigReloader
+	if loadConfig != nil {
+		reloader := NewReloader(conf, loadConfig, offerings, logger)
+		configReloader = reloader
+		reloadSignals := make(chan os.Signal, 1)
+		signal.Notify(reloadSignals, syscall.SIGHUP)
+		defer func() {
+			signal.Stop(reloadSignals)
+			close(reloadSignals)
+		}()
+		go reloader.ReloadOnSignal(reloadSignals)
 	}
 
 	server := apiserver.New(
 		conf,
+		offerings,
+		configReloader,
 		onDemandBroker,
 		broker.ComponentName,
 		loggerFactory,
 		logger,
+		registry,
 	)
 
 	displayBanner(conf)
 
-	telemetryLogger.LogInstances(instanceLister, "broker", "startup")
+	for i, telemetryLogger := range telemetryLoggers {
+		telemetryLogger.LogInstances(instanceListers[i], "broker", "startup")
+	}
 
 	if err := apiserver.StartAndWait(conf, server, logger, stopServer); err != nil {
-		logger.Fatal(err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatal(err)
 	}
 }
 
-func wrapWithCredHubBroker(conf config.Config, logger *log.Logger, onDemandBroker apiserver.CombinedBroker, loggerFactory *loggerfactory.LoggerFactory) apiserver.CombinedBroker {
+func buildRuntimeCredentialStore(conf config.Config, logger *log.Logger) *credhub.Store {
 	err := network.NewHostWaiter().Wait(conf.CredHub.APIURL, 16, 10)
 	if err != nil {
-		logger.Fatalf("error connecting to runtime credhub: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error connecting to runtime credhub: %s", err)
 	}
 
 	runtimeCredentialStore, err := credhub.Build(
@@ -112,9 +204,9 @@ func wrapWithCredHubBroker(conf config.Config, logger *log.Logger, onDemandBroke
 		credhub2.Auth(auth.UaaClientCredentials(conf.CredHub.ClientID, conf.CredHub.ClientSecret)),
 	)
 	if err != nil {
-		logger.Fatalf("error creating runtime credhub client: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error creating runtime credhub client: %s", err)
 	}
-	return credhubbroker.New(onDemandBroker, runtimeCredentialStore, conf.ServiceCatalog.Name, loggerFactory)
+	return runtimeCredentialStore
 }
 
 func buildCredhubStore(conf config.Config, logger *log.Logger) *credhub.Store {
@@ -130,7 +222,7 @@ func buildCredhubStore(conf config.Config, logger *log.Logger) *credhub.Store {
 			credhub2.CaCerts(conf.BoshCredhub.RootCACert, conf.Bosh.TrustedCert),
 		)
 		if err != nil {
-			logger.Fatalf("error starting broker: %s", err)
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error starting broker: %s", err)
 		}
 	}
 	return boshCredhubStore
@@ -145,9 +237,9 @@ func buildStartupChecks(conf config.Config, cfClient broker.CloudFoundryClient,
 			startupchecker.NewCFPlanConsistencyChecker(cfClient, conf.ServiceCatalog, logger),
 		)
 	}
-	boshInfo, err := boshClient.GetInfo(logger)
+	boshInfo, err := boshClient.GetInfo(context.Background(), logger)
 	if err != nil {
-		logger.Fatalf("error starting broker: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error starting broker: %s", err)
 	}
 	startupChecks = append(startupChecks,
 		startupchecker.NewBOSHDirectorVersionChecker(
@@ -161,6 +253,13 @@ func buildStartupChecks(conf config.Config, cfClient broker.CloudFoundryClient,
 	return startupChecks
 }
 
+func buildPlanConsistencyChecks(conf config.Config, serviceOffering config.ServiceOffering, cfClient broker.CloudFoundryClient, logger *log.Logger) []broker.StartupChecker {
+	if conf.Broker.DisableCFStartupChecks {
+		return nil
+	}
+	return []broker.StartupChecker{startupchecker.NewCFPlanConsistencyChecker(cfClient, serviceOffering, logger)}
+}
+
 func displayBanner(conf config.Config) {
 	if conf.Broker.StartUpBanner {
 		fmt.Println(`
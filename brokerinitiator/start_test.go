@@ -102,6 +102,7 @@ var _ = Describe("Start", func() {
 			new(serviceAdapterFakes.FakeCommandRunner),
 			stopServer,
 			loggerFactory,
+			nil,
 		)
 
 		Eventually(logBuffer).Should(gbytes.Say(fmt.Sprintf(`"telemetry-source":"on-demand-broker","service-offering":{"name":"%s"},"event":{"item":"broker","operation":"startup"},"service-instances":{"total":2}`, brokerConfig.ServiceCatalog.Name)))
@@ -120,6 +121,7 @@ var _ = Describe("Start", func() {
 			new(serviceAdapterFakes.FakeCommandRunner),
 			stopServer,
 			loggerFactory,
+			nil,
 		)
 
 		Consistently(logBuffer).ShouldNot(gbytes.Say("telemetry-source"))
@@ -139,6 +141,7 @@ var _ = Describe("Start", func() {
 			new(serviceAdapterFakes.FakeCommandRunner),
 			stopServer,
 			loggerFactory,
+			nil,
 		)
 
 		Eventually(logBuffer).Should(gbytes.Say("Failed to query list of instances for telemetry"))
@@ -8,6 +8,7 @@ package cf
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -30,9 +31,10 @@ type Client struct {
 }
 
 type Instance struct {
-	GUID         string `json:"service_instance_id"`
-	PlanUniqueID string `json:"plan_id"`
-	SpaceGUID    string `json:"space_guid"`
+	GUID             string `json:"service_instance_id"`
+	PlanUniqueID     string `json:"plan_id"`
+	SpaceGUID        string `json:"space_guid"`
+	OrganizationGUID string `json:"organization_guid"`
 }
 
 func New(
@@ -49,7 +51,7 @@ func New(
 	return Client{httpJsonClient: httpClient, url: url, logger: logger}, nil
 }
 
-func (c Client) CountInstancesOfServiceOffering(serviceID string, logger *log.Logger) (map[ServicePlan]int, error) {
+func (c Client) CountInstancesOfServiceOffering(ctx context.Context, serviceID string, logger *log.Logger) (map[ServicePlan]int, error) {
 	plans, err := c.getPlansForServiceID(serviceID, logger)
 	if err != nil {
 		return map[ServicePlan]int{}, err
@@ -67,6 +69,44 @@ func (c Client) CountInstancesOfServiceOffering(serviceID string, logger *log.Lo
 	return output, nil
 }
 
+// OrgAndSpaceInstanceCounts are the service instances of each plan of a
+// service offering, by the org and by the space they are in.
+type OrgAndSpaceInstanceCounts struct {
+	Orgs   map[string]map[ServicePlan]int
+	Spaces map[string]map[ServicePlan]int
+}
+
+func (c Client) CountInstancesOfServiceOfferingByOrgAndSpace(ctx context.Context, serviceID string, logger *log.Logger) (OrgAndSpaceInstanceCounts, error) {
+	counts := OrgAndSpaceInstanceCounts{
+		Orgs:   map[string]map[ServicePlan]int{},
+		Spaces: map[string]map[ServicePlan]int{},
+	}
+
+	plans, err := c.getPlansForServiceID(serviceID, logger)
+	if err != nil {
+		return OrgAndSpaceInstanceCounts{}, err
+	}
+
+	err = c.eachInstance(plans, inlineSpacesQuery, logger, func(plan ServicePlan, instance ServiceInstanceResource) {
+		spaceGUID := instance.Entity.SpaceGUID
+		orgGUID := instance.Entity.Space.Entity.OrganizationGUID
+
+		if counts.Orgs[orgGUID] == nil {
+			counts.Orgs[orgGUID] = map[ServicePlan]int{}
+		}
+		counts.Orgs[orgGUID][plan]++
+		if counts.Spaces[spaceGUID] == nil {
+			counts.Spaces[spaceGUID] = map[ServicePlan]int{}
+		}
+		counts.Spaces[spaceGUID][plan]++
+	})
+	if err != nil {
+		return OrgAndSpaceInstanceCounts{}, err
+	}
+
+	return counts, nil
+}
+
 func (c Client) GetLastOperationForInstance(serviceInstanceGUID string, logger *log.Logger) (LastOperation, error) {
 	instance, err := c.GetServiceInstance(serviceInstanceGUID, logger)
 	if err != nil {
@@ -7,6 +7,7 @@
 package cf_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -674,6 +675,59 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("CountInstancesOfServiceOfferingByOrgAndSpace", func() {
+		It("fetches instance counts per plan for each org and space, with the spaces inlined", func() {
+			spaceGUID := "a157c861-92bb-4f57-9108-f791260f66ab"
+			anotherSpaceGUID := "5e1b2f7a-8d2c-4a4e-9f0b-3c6d1e2f4a5b"
+			server.VerifyAndMock(
+				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
+				mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
+				mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_with_spaces_for_plan_1_response.json")),
+				mockcfapi.ListServiceInstancesWithSpaces("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_with_spaces_for_plan_2_response.json")),
+			)
+
+			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
+			Expect(err).NotTo(HaveOccurred())
+
+			small := servicePlan(
+				"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
+				"11789210-D743-4C65-9D38-C80B29F4D9C8",
+				"/v2/service_plans/ff717e7c-afd5-4d0a-bafe-16c7eff546ec/service_instances",
+				"small",
+			)
+			big := servicePlan(
+				"2777ad05-8114-4169-8188-2ef5f39e0c6b",
+				"22789210-D743-4C65-9D38-C80B29F4D9C8",
+				"/v2/service_plans/2777ad05-8114-4169-8188-2ef5f39e0c6b/service_instances",
+				"big",
+			)
+			Expect(client.CountInstancesOfServiceOfferingByOrgAndSpace(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(cf.OrgAndSpaceInstanceCounts{
+				Orgs: map[string]map[cf.ServicePlan]int{
+					"an-org-guid":      {small: 1, big: 1},
+					"another-org-guid": {big: 1},
+				},
+				Spaces: map[string]map[cf.ServicePlan]int{
+					spaceGUID:        {small: 1, big: 1},
+					anotherSpaceGUID: {big: 1},
+				},
+			}))
+		})
+
+		It("fails when the instances cannot be listed", func() {
+			server.VerifyAndMock(
+				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
+				mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
+				mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsInternalServerErrorWith("failed"),
+			)
+
+			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.CountInstancesOfServiceOfferingByOrgAndSpace(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("CountInstancesOfServiceOffering", func() {
 		It("fetches instance counts per plan", func() {
 			server.VerifyAndMock(
@@ -686,7 +740,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
+			Expect(client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
 				servicePlan(
 					"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
 					"11789210-D743-4C65-9D38-C80B29F4D9C8",
@@ -710,7 +764,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{}))
+			Expect(client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{}))
 		})
 
 		It("fails if getting a new token fails", func() {
@@ -723,7 +777,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, err = client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
+			_, err = client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(ContainSubstring("Invalid Auth Token")))
 		})
@@ -739,7 +793,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
+			Expect(client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
 				servicePlan(
 					"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
 					"11789210-D743-4C65-9D38-C80B29F4D9C8",
@@ -761,7 +815,7 @@ var _ = Describe("Client", func() {
 				mockcfapi.ListServiceInstances("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
 			)
 
-			Expect(client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
+			Expect(client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
 				servicePlan(
 					"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
 					"11789210-D743-4C65-9D38-C80B29F4D9C8",
@@ -789,7 +843,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
+			Expect(client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
 				servicePlan(
 					"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
 					"11789210-D743-4C65-9D38-C80B29F4D9C8",
@@ -817,7 +871,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
+			Expect(client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)).To(Equal(map[cf.ServicePlan]int{
 				servicePlan(
 					"ff717e7c-afd5-4d0a-bafe-16c7eff546ec",
 					"11789210-D743-4C65-9D38-C80B29F4D9C8",
@@ -851,7 +905,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, err = client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
+			_, err = client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
 			Expect(err).To(MatchError(ContainSubstring("niet goed")))
 		})
 
@@ -863,7 +917,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, err = client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
+			_, err = client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
 			Expect(err).To(MatchError(ContainSubstring("niet goed")))
 		})
 
@@ -876,7 +930,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, err = client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
+			_, err = client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
 			Expect(err).To(MatchError(ContainSubstring("niet goed")))
 		})
 
@@ -890,7 +944,7 @@ var _ = Describe("Client", func() {
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, err = client.CountInstancesOfServiceOffering("D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
+			_, err = client.CountInstancesOfServiceOffering(context.Background(), "D94A086D-203D-4966-A6F1-60A9E2300F72", testLogger)
 			Expect(err).To(MatchError(ContainSubstring("niet goed")))
 		})
 	})
@@ -101,6 +101,17 @@ type ServiceInstanceEntity struct {
 	ServicePlanURL  string          `json:"service_plan_url"`
 	LastOperation   LastOperation   `json:"last_operation"`
 	MaintenanceInfo MaintenanceInfo `json:"maintenance_info"`
+	// Space is only set when the space is inlined in the response.
+	Space SpaceResource `json:"space"`
+}
+
+type SpaceResource struct {
+	Metadata Metadata    `json:"metadata"`
+	Entity   SpaceEntity `json:"entity"`
+}
+
+type SpaceEntity struct {
+	OrganizationGUID string `json:"organization_guid"`
 }
 
 type serviceInstancesResponse struct {
@@ -5,6 +5,8 @@ import (
 	"log"
 
 	"github.com/blang/semver/v4"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 )
 
 func (c Client) GetAPIVersion(logger *log.Logger) (string, error) {
@@ -19,18 +21,18 @@ func (c Client) GetAPIVersion(logger *log.Logger) (string, error) {
 func (c Client) CheckMinimumOSBAPIVersion(minimum string, logger *log.Logger) bool {
 	min, err := semver.ParseTolerant(minimum)
 	if err != nil {
-		logger.Printf("error parsing specified OSBAPI version '%s' to semver: %v", minimum, err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error parsing specified OSBAPI version '%s' to semver: %v", minimum, err)
 		return false
 	}
 
 	var infoResponse infoResponse
 	if err := c.get(fmt.Sprintf("%s/v2/info", c.url), &infoResponse, logger); err != nil {
-		logger.Printf("error requesting OSBAPI version: %v", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error requesting OSBAPI version: %v", err)
 	}
 
 	ver, err := semver.ParseTolerant(infoResponse.OSBAPIVersion)
 	if err != nil {
-		logger.Printf("error parsing discovered OSBAPI version '%s' to semver: %v", infoResponse.OSBAPIVersion, err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error parsing discovered OSBAPI version '%s' to semver: %v", infoResponse.OSBAPIVersion, err)
 		return false
 	}
 
@@ -63,6 +63,39 @@ func (c Client) UpgradeServiceInstance(serviceInstanceGUID string, maintenanceIn
 	return parsedResponse.Entity.LastOperation, nil
 }
 
+// UpdateServiceInstancePlan moves the service instance to the plan with the
+// GUID, passing the parameters to the broker.
+func (c Client) UpdateServiceInstancePlan(serviceInstanceGUID, planGUID string, parameters map[string]interface{}, logger *log.Logger) (LastOperation, error) {
+	path := fmt.Sprintf(`%s/v2/service_instances/%s?accepts_incomplete=true`, c.url, serviceInstanceGUID)
+
+	requestBody, err := json.Marshal(struct {
+		ServicePlanGUID string                 `json:"service_plan_guid"`
+		Parameters      map[string]interface{} `json:"parameters,omitempty"`
+	}{ServicePlanGUID: planGUID, Parameters: parameters})
+	if err != nil {
+		return LastOperation{}, errors.Wrap(err, "failed to serialize request body")
+	}
+
+	resp, err := c.put(path, string(requestBody), logger)
+	if err != nil {
+		return LastOperation{}, err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusCreated {
+		return LastOperation{},
+			fmt.Errorf("unexpected response status %d when updating the plan of service instance %q; response body %q", resp.StatusCode, serviceInstanceGUID, string(body))
+	}
+
+	var parsedResponse ServiceInstanceResource
+	if err := json.Unmarshal(body, &parsedResponse); err != nil {
+		return LastOperation{}, errors.Wrap(err, "failed to de-serialise the response body")
+	}
+
+	return parsedResponse.Entity.LastOperation, nil
+}
+
 func (c Client) DeleteServiceInstance(instanceGUID string, logger *log.Logger) error {
 	url := fmt.Sprintf(
 		"%s/v2/service_instances/%s?accepts_incomplete=true",
@@ -152,8 +185,32 @@ func (c Client) createQuery(filters GetInstancesFilter, logger *log.Logger) (str
 	return query, nil
 }
 
+// inlineSpacesQuery inlines the space of each instance, rather than fetching
+// every space, for its org
+const inlineSpacesQuery = "&inline-relations-depth=1&include-relations=space"
+
 func (c Client) getInstances(plans []ServicePlan, query string, logger *log.Logger) ([]Instance, error) {
 	instances := []Instance{}
+	err := c.eachInstance(plans, inlineSpacesQuery+query, logger, func(plan ServicePlan, instance ServiceInstanceResource) {
+		instances = append(
+			instances,
+			Instance{
+				GUID:             instance.Metadata.GUID,
+				PlanUniqueID:     plan.ServicePlanEntity.UniqueID,
+				SpaceGUID:        instance.Entity.SpaceGUID,
+				OrganizationGUID: instance.Entity.Space.Entity.OrganizationGUID,
+			},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+// eachInstance calls f with each service instance of the plans, listed with
+// the query appended to the request of each page.
+func (c Client) eachInstance(plans []ServicePlan, query string, logger *log.Logger, f func(ServicePlan, ServiceInstanceResource)) error {
 	for _, plan := range plans {
 		path := fmt.Sprintf(
 			"/v2/service_plans/%s/service_instances?results-per-page=%d%s",
@@ -169,22 +226,15 @@ func (c Client) getInstances(plans []ServicePlan, query string, logger *log.Logg
 
 			err := c.get(instancesURL, &serviceInstancesResp, logger)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			for _, instance := range serviceInstancesResp.ServiceInstances {
-				instances = append(
-					instances,
-					Instance{
-						GUID:         instance.Metadata.GUID,
-						PlanUniqueID: plan.ServicePlanEntity.UniqueID,
-						SpaceGUID:    instance.Entity.SpaceGUID,
-					},
-				)
+				f(plan, instance)
 			}
 			path = serviceInstancesResp.NextPath
 		}
 	}
-	return instances, nil
+	return nil
 }
 
 func serialiseMaintenanceInfo(maintenanceInfo MaintenanceInfo) (string, error) {
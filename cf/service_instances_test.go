@@ -112,14 +112,14 @@ var _ = Describe("ServiceInstancesClient", func() {
 	})
 
 	Describe("GetServiceInstances", func() {
-		It("returns a list of instances filtered by the service offering", func() {
+		It("returns a list of instances filtered by the service offering, with their spaces and orgs", func() {
 			offeringID := "8F3E8998-5FD0-4F32-924A-5478DC390A5F"
 
 			server.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
 				mockcfapi.ListServicePlans("34c08156-5b5d-4cc1-9af1-29cda9ec056f").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
-				mockcfapi.ListServiceInstances("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
-				mockcfapi.ListServiceInstances("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
+				mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_with_spaces_for_plan_1_response.json")),
+				mockcfapi.ListServiceInstancesWithSpaces("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_with_spaces_for_plan_2_response.json")),
 			)
 
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
@@ -128,9 +128,9 @@ var _ = Describe("ServiceInstancesClient", func() {
 			instances, err := client.GetServiceInstances(cf.GetInstancesFilter{ServiceOfferingID: offeringID}, testLogger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(instances).To(ConsistOf(
-				cf.Instance{GUID: "520f8566-b727-4c67-8be8-d9285645e936", PlanUniqueID: "11789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab"},
-				cf.Instance{GUID: "f897f40d-0b2d-474a-a5c9-98426a2cb4b8", PlanUniqueID: "22789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab"},
-				cf.Instance{GUID: "2f759033-04a4-426b-bccd-01722036c152", PlanUniqueID: "22789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab"},
+				cf.Instance{GUID: "520f8566-b727-4c67-8be8-d9285645e936", PlanUniqueID: "11789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab", OrganizationGUID: "an-org-guid"},
+				cf.Instance{GUID: "f897f40d-0b2d-474a-a5c9-98426a2cb4b8", PlanUniqueID: "22789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab", OrganizationGUID: "an-org-guid"},
+				cf.Instance{GUID: "2f759033-04a4-426b-bccd-01722036c152", PlanUniqueID: "22789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "5e1b2f7a-8d2c-4a4e-9f0b-3c6d1e2f4a5b", OrganizationGUID: "another-org-guid"},
 			))
 		})
 
@@ -142,8 +142,8 @@ var _ = Describe("ServiceInstancesClient", func() {
 					mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response_page_1.json")),
 					mockcfapi.ListServiceOfferingsForPage(2).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response_page_2.json")),
 					mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
-					mockcfapi.ListServiceInstances("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
-					mockcfapi.ListServiceInstances("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
+					mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
+					mockcfapi.ListServiceInstancesWithSpaces("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
 				)
 
 				client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
@@ -179,8 +179,8 @@ var _ = Describe("ServiceInstancesClient", func() {
 					mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
 					mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response_page_1.json")),
 					mockcfapi.ListServicePlansForPage(serviceGUID, 2).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response_page_2.json")),
-					mockcfapi.ListServiceInstances("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
-					mockcfapi.ListServiceInstances("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
+					mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
+					mockcfapi.ListServiceInstancesWithSpaces("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
 				)
 
 				client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
@@ -215,8 +215,8 @@ var _ = Describe("ServiceInstancesClient", func() {
 				server.VerifyAndMock(
 					mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
 					mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
-					mockcfapi.ListServiceInstances("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
-					mockcfapi.ListServiceInstances("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_page_1.json")),
+					mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
+					mockcfapi.ListServiceInstancesWithSpaces("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_page_1.json")),
 					mockcfapi.ListServiceInstancesForPage("2777ad05-8114-4169-8188-2ef5f39e0c6b", 2).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_page_2.json")),
 				)
 
@@ -251,8 +251,8 @@ var _ = Describe("ServiceInstancesClient", func() {
 			server.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
 				mockcfapi.ListServicePlans(serviceGUID).WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
-				mockcfapi.ListServiceInstances("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_empty_response.json")),
-				mockcfapi.ListServiceInstances("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_empty_response.json")),
+				mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_empty_response.json")),
+				mockcfapi.ListServiceInstancesWithSpaces("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_empty_response.json")),
 			)
 
 			client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
@@ -306,7 +306,7 @@ var _ = Describe("ServiceInstancesClient", func() {
 
 					mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
 					mockcfapi.ListServicePlans("34c08156-5b5d-4cc1-9af1-29cda9ec056f").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
-					mockcfapi.ListServiceInstances("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsInternalServerErrorWith("oops"),
+					mockcfapi.ListServiceInstancesWithSpaces("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsInternalServerErrorWith("oops"),
 				)
 
 				client, err := cf.New(server.URL, authHeaderBuilder, nil, true, testLogger)
@@ -503,6 +503,35 @@ var _ = Describe("ServiceInstancesClient", func() {
 		})
 	})
 
+	Describe("UpdateServiceInstancePlan", func() {
+		It("updates the plan and returns the last operation", func() {
+			cfApi.RouteToHandler(http.MethodPut, regexp.MustCompile(`/v2/service_instances/*`), ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPut, "/v2/service_instances/service-instance-guid", "accepts_incomplete=true"),
+				ghttp.VerifyBody([]byte(`{"service_plan_guid":"target-plan-guid","parameters":{"size":2}}`)),
+				ghttp.RespondWith(http.StatusAccepted, `{"entity": {"last_operation": {"type": "update", "state": "in progress"}}}`),
+			))
+
+			client, err := cf.New(cfApi.URL(), authHeaderBuilder, nil, true, testLogger)
+			Expect(err).NotTo(HaveOccurred())
+
+			lastOperation, err := client.UpdateServiceInstancePlan("service-instance-guid", "target-plan-guid", map[string]interface{}{"size": 2}, testLogger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastOperation.State).To(Equal(cf.OperationStateInProgress))
+		})
+
+		It("returns an error when CF rejects the update", func() {
+			cfApi.RouteToHandler(http.MethodPut, regexp.MustCompile(`/v2/service_instances/*`), ghttp.RespondWith(http.StatusBadGateway, `quota exceeded`))
+
+			client, err := cf.New(cfApi.URL(), authHeaderBuilder, nil, true, testLogger)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.UpdateServiceInstancePlan("service-instance-guid", "target-plan-guid", nil, testLogger)
+
+			Expect(err).To(MatchError(`unexpected response status 502 when updating the plan of service instance "service-instance-guid"; response body "quota exceeded"`))
+		})
+	})
+
 	Describe("DeleteServiceInstance", func() {
 		const serviceInstanceGUID = "596736f1-eee4-4249-a201-e21f00a55209"
 
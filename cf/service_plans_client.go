@@ -15,3 +15,16 @@ func (c Client) GetPlanByServiceInstanceGUID(serviceGUID string, logger *log.Log
 	}
 	return servicePlanResponse.ServicePlans[0], nil
 }
+
+// GetPlanByUniqueID returns the plan with the ID it has in the broker catalog.
+func (c Client) GetPlanByUniqueID(uniqueID string, logger *log.Logger) (ServicePlan, error) {
+	servicePlanResponse := ServicePlanResponse{}
+	err := c.get(fmt.Sprintf("%s%s", c.url, "/v2/service_plans?q=unique_id:"+uniqueID), &servicePlanResponse, logger)
+	if err != nil {
+		return ServicePlan{}, errors.Wrap(err, fmt.Sprintf("failed to retrieve plan %q", uniqueID))
+	}
+	if len(servicePlanResponse.ServicePlans) == 0 {
+		return ServicePlan{}, fmt.Errorf("plan %q not found", uniqueID)
+	}
+	return servicePlanResponse.ServicePlans[0], nil
+}
@@ -86,4 +86,34 @@ var _ = Describe("ServicePlansClient", func() {
 			Expect(err).To(MatchError(ContainSubstring("failed to retrieve plan for service")))
 		})
 	})
+
+	Describe("GetPlanByUniqueID", func() {
+		It("returns the plan", func() {
+			cfApi := ghttp.NewServer()
+			cfApi.RouteToHandler(http.MethodGet, regexp.MustCompile(`/v2/service_plans`), ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/v2/service_plans", "q=unique_id:target-plan-id"),
+				ghttp.RespondWith(http.StatusOK, `{ "resources":[{ "metadata": { "guid": "target-plan-guid" }, "entity": { "unique_id": "target-plan-id" }}]}`),
+			))
+
+			client, err := cf.New(cfApi.URL(), authHeaderBuilder, nil, true, testLogger)
+			Expect(err).NotTo(HaveOccurred())
+
+			plan, err := client.GetPlanByUniqueID("target-plan-id", testLogger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Metadata.GUID).To(Equal("target-plan-guid"))
+		})
+
+		It("returns an error when the plan is not registered", func() {
+			cfApi := ghttp.NewServer()
+			cfApi.RouteToHandler(http.MethodGet, regexp.MustCompile(`/v2/service_plans`), ghttp.RespondWith(http.StatusOK, `{ "resources":[]}`))
+
+			client, err := cf.New(cfApi.URL(), authHeaderBuilder, nil, true, testLogger)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetPlanByUniqueID("target-plan-id", testLogger)
+
+			Expect(err).To(MatchError(`plan "target-plan-id" not found`))
+		})
+	})
 })
@@ -54,7 +54,7 @@ func main() {
 
 	request, err := http.NewRequest("GET", brokerCatalogURL, nil)
 	if err != nil {
-		logger.Printf("error creating request: %s\n", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error creating request: %s\n", err)
 		os.Exit(1)
 	}
 	request.SetBasicAuth(*brokerUsername, *brokerPassword)
@@ -66,7 +66,7 @@ func main() {
 		for {
 			response, err := client.Do(request)
 			if err != nil {
-				logger.Printf("error performing request: %s", err)
+				loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error performing request: %s", err)
 			} else if response.StatusCode != http.StatusOK {
 				logger.Printf("expected status 200, was %d, from %s\n", response.StatusCode, brokerCatalogURL)
 			} else {
@@ -75,7 +75,7 @@ func main() {
 
 	purgerTool := purger.New(deleteTool, registrarTool, cfClient, logger)
 
-	err = purgerTool.DeleteInstancesAndDeregister(config.ServiceCatalog.ID, *brokerName)
+	err = purgerTool.DeleteOfferingsInstancesAndDeregister(config.ServiceOfferingIDs(), *brokerName)
 	if err != nil {
 		logger.Fatalf("%s", err.Error())
 	}
@@ -55,9 +55,11 @@ func main() {
 
 	deleteTool := deleter.New(cfClient, clock, config.PollingInitialOffset, config.PollingInterval, logger)
 
-	err = deleteTool.DeleteAllServiceInstances(config.ServiceCatalog.ID)
-	if err != nil {
-		logger.Fatalln(err)
+	for _, serviceOfferingID := range config.ServiceOfferingIDs() {
+		err = deleteTool.DeleteAllServiceInstances(serviceOfferingID)
+		if err != nil {
+			logger.Fatalln(err)
+		}
 	}
 
 	logger.Println("FINISHED DELETES")
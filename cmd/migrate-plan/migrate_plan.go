@@ -0,0 +1,92 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"syscall"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+func main() {
+	loggerFactory := loggerfactory.New(os.Stdout, "migrate-plan", loggerfactory.Flags)
+	logger := loggerFactory.New()
+
+	var configPath string
+	var resume bool
+	flag.StringVar(&configPath, "configPath", "", "path to migrate-plan config")
+	flag.BoolVar(&resume, "resume", false, "skip the instances migrated by a previous run that did not complete")
+	flag.Parse()
+
+	if configPath == "" {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln("-configPath must be given as argument")
+	}
+
+	var conf config.InstanceIteratorConfig
+	configContents, err := os.ReadFile(configPath)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	if err := yaml.Unmarshal(configContents, &conf); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	if conf.EnableStructuredLogging {
+		loggerFactory = loggerfactory.NewStructured(os.Stdout, "migrate-plan")
+		logger = loggerFactory.New()
+	}
+
+	configurator, err := instanceiterator.NewConfigurator(conf, logger, "migrate-plan")
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	configurator.Resume = resume
+	configurator.Pauser = instanceiterator.NewSignalPauser(syscall.SIGUSR1, syscall.SIGUSR2)
+
+	if err := configurator.SetMigratePlanTriggerer(conf.PlanMigrations, createCFClient(conf, logger), logger); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	if err := instanceiterator.New(configurator).Iterate(); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+}
+
+// createCFClient returns nil when CF is not configured, in which case the
+// plans are migrated through the broker. That is only allowed when the broker
+// lists its instances from the service instances API: otherwise CF would keep
+// the old plans of the instances.
+func createCFClient(conf config.InstanceIteratorConfig, logger *log.Logger) instanceiterator.CFClient {
+	if conf.CF == (config.CF{}) {
+		if conf.ServiceInstancesAPI.URL == "" {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(
+				"cf must be configured to migrate the plans of instances listed from CF; plans are only migrated through the broker when service_instances_api is configured",
+			)
+		}
+		return nil
+	}
+
+	cfAuthenticator, err := conf.CF.NewAuthHeaderBuilder(conf.CF.DisableSSLCertVerification)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error creating CF authorization header builder: %s", err)
+	}
+	cfClient, err := cf.New(conf.CF.URL, cfAuthenticator, []byte(conf.CF.TrustedCert), conf.CF.DisableSSLCertVerification, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error creating Cloud Foundry client: %s", err)
+	}
+	return cfClient
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/noopservicescontroller"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 func main() {
@@ -32,24 +33,47 @@ func main() {
 	logger := loggerFactory.New()
 	logger.Println("Starting broker")
 
-	config := configParser(logger)
-	boshClient := createBoshClient(logger, config)
+	configFilePath := configFilePathFlag(logger)
+	conf := configParser(configFilePath, logger)
+	if conf.Broker.EnableStructuredLogging {
+		loggerFactory = loggerfactory.NewStructured(os.Stdout, broker.ComponentName)
+		logger = loggerFactory.New()
+	}
+
+	exporter, err := tracing.NewExporter(conf.Broker.Tracing, broker.ComponentName, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error configuring tracing: %s", err)
+	}
+	tracing.SetExporter(exporter)
+
+	boshClient := createBoshClient(logger, conf)
 	commandRunner := serviceadapter.NewCommandRunner()
 	stopServer := make(chan os.Signal, 1)
-	cfClient := createCfClient(config, logger)
+	cfClient := createCfClient(conf, logger)
 
-	brokerinitiator.Initiate(config, boshClient, boshClient, cfClient, commandRunner, stopServer, loggerFactory)
+	loadConfig := func() (config.Config, error) {
+		return config.Parse(configFilePath)
+	}
+	brokerinitiator.Initiate(conf, boshClient, boshClient, cfClient, commandRunner, stopServer, loggerFactory, loadConfig)
+
+	if otlpExporter, ok := exporter.(*tracing.OTLPExporter); ok {
+		otlpExporter.Shutdown()
+	}
 }
 
-func configParser(logger *log.Logger) config.Config {
+func configFilePathFlag(logger *log.Logger) string {
 	configFilePath := flag.String("configFilePath", "", "path to config file")
 	flag.Parse()
 	if *configFilePath == "" {
-		logger.Fatal("must supply -configFilePath")
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatal("must supply -configFilePath")
 	}
-	config, err := config.Parse(*configFilePath)
+	return *configFilePath
+}
+
+func configParser(configFilePath string, logger *log.Logger) config.Config {
+	config, err := config.Parse(configFilePath)
 	if err != nil {
-		logger.Fatalf("error parsing config: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error parsing config: %s", err)
 	}
 	return config
 }
@@ -67,11 +91,11 @@ func createCfClient(conf config.Config, logger *log.Logger) broker.CloudFoundryC
 func createRealCfClient(conf config.Config, logger *log.Logger, cfClient broker.CloudFoundryClient) broker.CloudFoundryClient {
 	cfAuthenticator, err := conf.CF.NewAuthHeaderBuilder(conf.Broker.DisableSSLCertVerification)
 	if err != nil {
-		logger.Fatalf("error creating CF authorization header builder: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error creating CF authorization header builder: %s", err)
 	}
 	cfClient, err = cf.New(conf.CF.URL, cfAuthenticator, []byte(conf.CF.TrustedCert), conf.Broker.DisableSSLCertVerification, logger)
 	if err != nil {
-		logger.Fatalf("error creating Cloud Foundry client: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error creating Cloud Foundry client: %s", err)
 	}
 	return cfClient
 }
@@ -79,7 +103,7 @@ func createRealCfClient(conf config.Config, logger *log.Logger, cfClient broker.
 func createBoshClient(logger *log.Logger, conf config.Config) *boshdirector.Client {
 	certPool, err := x509.SystemCertPool()
 	if err != nil {
-		logger.Fatalf("error getting a certificate pool to append our trusted cert to: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error getting a certificate pool to append our trusted cert to: %s", err)
 	}
 	boshLogger := boshlog.NewLogger(boshlog.LevelError)
 	directorFactory := director.NewFactory(boshLogger)
@@ -95,7 +119,7 @@ func createBoshClient(logger *log.Logger, conf config.Config) *boshdirector.Clie
 		boshdirector.NewBoshHTTP,
 		logger)
 	if err != nil {
-		logger.Fatalf("error creating bosh client: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalf("error creating bosh client: %s", err)
 	}
 	return boshClient
 }
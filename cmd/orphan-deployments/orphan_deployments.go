@@ -12,16 +12,21 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"os"
+	"strings"
 	"time"
 
+	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"github.com/craigfurman/herottp"
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/pivotal-cf/on-demand-service-broker/authorizationheader"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 )
 
 const (
@@ -70,6 +75,11 @@ func main() {
 	authHeaderBuilder := authorizationheader.NewBasicAuthHeaderBuilder(brokerUsername, brokerPassword)
 	brokerServices := services.NewBrokerServices(httpClient, authHeaderBuilder, errandConfig.BrokerAPI.URL, logger)
 
+	if errandConfig.Cleanup.Enabled {
+		cleanupOrphans(brokerServices, errandConfig.Cleanup, logger)
+		return
+	}
+
 	orphans, err := brokerServices.OrphanDeployments()
 	if err != nil {
 		logger.Fatalf("error retrieving orphan deployments: %s", err)
@@ -87,3 +97,79 @@ func main() {
 		os.Exit(OrphanDeploymentsDetectedExitCode)
 	}
 }
+
+// cleanupOrphans asks the broker to clean up orphans and waits for the
+// deletions it started to complete.
+func cleanupOrphans(brokerServices *services.BrokerServices, cleanup config.OrphanCleanup, logger *log.Logger) {
+	if err := cleanup.Validate(); err != nil {
+		logger.Fatalln(err)
+	}
+
+	report, err := brokerServices.CleanupOrphanDeployments(mgmtapi.OrphanCleanupRequest{
+		GracePeriodSecs: cleanup.GracePeriodSecs,
+		AllowList:       cleanup.AllowList,
+		DryRun:          cleanup.DryRun,
+	})
+	if err != nil {
+		logger.Fatalf("error cleaning up orphan deployments: %s", err)
+	}
+
+	rawJSON, err := json.Marshal(report)
+	if err != nil {
+		logger.Fatalf("error marshalling orphan cleanup report: %s", err)
+	}
+
+	fmt.Fprintln(os.Stdout, string(rawJSON))
+
+	if cleanup.DryRun {
+		if toBeDeleted(report) > 0 {
+			logger.Println(OrphanBoshDeploymentsDetectedMessage)
+			os.Exit(OrphanDeploymentsDetectedExitCode)
+		}
+		return
+	}
+
+	var failed []string
+	for _, orphan := range report.Orphans {
+		if orphan.Operation == nil {
+			continue
+		}
+
+		instanceID := strings.TrimPrefix(orphan.DeploymentName, broker.InstancePrefix)
+		for {
+			lastOperation, err := brokerServices.LastOperation(instanceID, *orphan.Operation)
+			if err != nil {
+				logger.Fatalf("error polling deletion of orphan deployment %s: %s", orphan.DeploymentName, err)
+			}
+
+			if lastOperation.State == domain.InProgress {
+				time.Sleep(time.Duration(cleanup.PollingInterval) * time.Second)
+				continue
+			}
+
+			if lastOperation.State == domain.Succeeded {
+				logger.Printf("deleted orphan deployment %s", orphan.DeploymentName)
+			} else {
+				loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to delete orphan deployment %s: %s", orphan.DeploymentName, lastOperation.Description)
+				failed = append(failed, orphan.DeploymentName)
+			}
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		logger.Fatalf("failed to delete orphan deployments: %s", strings.Join(failed, ", "))
+	}
+}
+
+// toBeDeleted counts the orphans of the report that the cleanup deletes, now
+// or once their grace period has passed, leaving out those it skips.
+func toBeDeleted(report broker.OrphanCleanupReport) int {
+	count := 0
+	for _, orphan := range report.Orphans {
+		if orphan.Action != broker.OrphanAllowListed && orphan.Action != broker.OrphanPlanNotFound {
+			count++
+		}
+	}
+	return count
+}
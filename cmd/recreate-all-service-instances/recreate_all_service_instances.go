@@ -15,6 +15,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"syscall"
 	"time"
 
 	"github.com/craigfurman/herottp"
@@ -32,22 +33,29 @@ func main() {
 	logger := loggerFactory.New()
 
 	var configPath string
+	var resume bool
 	flag.StringVar(&configPath, "configPath", "", "path to recreate-all-service-instances config")
+	flag.BoolVar(&resume, "resume", false, "skip the instances recreated by a previous run that did not complete")
 	flag.Parse()
 
 	if configPath == "" {
-		logger.Fatalln("-configPath must be given as argument")
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln("-configPath must be given as argument")
 	}
 
 	var conf config.InstanceIteratorConfig
 	configContents, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
 	}
 
 	err = yaml.Unmarshal(configContents, &conf)
 	if err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	if conf.EnableStructuredLogging {
+		loggerFactory = loggerfactory.NewStructured(os.Stdout, "recreate-all-service-instances")
+		logger = loggerFactory.New()
 	}
 
 	err = checkBoshVersion(conf)
@@ -57,14 +65,16 @@ func main() {
 
 	configurator, err := instanceiterator.NewConfigurator(conf, logger, "recreate-all")
 	if err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
 	}
+	configurator.Resume = resume
+	configurator.Pauser = instanceiterator.NewSignalPauser(syscall.SIGUSR1, syscall.SIGUSR2)
 	configurator.SetRecreateTriggerer()
 
 	recreateTool := instanceiterator.New(configurator)
 	err = recreateTool.Iterate()
 	if err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
 	}
 }
 
@@ -0,0 +1,66 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"os"
+	"syscall"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+func main() {
+	loggerFactory := loggerfactory.New(os.Stdout, "rotate-secrets-for-all-service-instances", loggerfactory.Flags)
+	logger := loggerFactory.New()
+
+	var configPath string
+	var resume bool
+	flag.StringVar(&configPath, "configPath", "", "path to rotate-secrets-for-all-service-instances config")
+	flag.BoolVar(&resume, "resume", false, "skip the instances whose secrets were rotated by a previous run that did not complete")
+	flag.Parse()
+
+	if configPath == "" {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln("-configPath must be given as argument")
+	}
+
+	var conf config.InstanceIteratorConfig
+	configContents, err := os.ReadFile(configPath)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	err = yaml.Unmarshal(configContents, &conf)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	if conf.EnableStructuredLogging {
+		loggerFactory = loggerfactory.NewStructured(os.Stdout, "rotate-secrets-for-all-service-instances")
+		logger = loggerFactory.New()
+	}
+
+	configurator, err := instanceiterator.NewConfigurator(conf, logger, "rotate-secrets-for-all")
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+	configurator.Resume = resume
+	configurator.Pauser = instanceiterator.NewSignalPauser(syscall.SIGUSR1, syscall.SIGUSR2)
+	if err := configurator.SetRotateSecretsTriggerer(conf.SecretPaths); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	rotateTool := instanceiterator.New(configurator)
+	err = rotateTool.Iterate()
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"syscall"
 
 	"gopkg.in/yaml.v2"
 
@@ -25,26 +26,47 @@ func main() {
 	logger := loggerFactory.New()
 
 	var configPath string
+	var dryRun bool
+	var resume bool
 	flag.StringVar(&configPath, "configPath", "", "path to upgrade-all-service-instances config")
+	flag.BoolVar(&dryRun, "dry-run", false, "log the manifest changes the upgrade would make, without upgrading")
+	flag.BoolVar(&resume, "resume", false, "skip the instances upgraded by a previous run that did not complete")
 	flag.Parse()
 
 	if configPath == "" {
-		logger.Fatalln("-configPath must be given as argument")
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln("-configPath must be given as argument")
 	}
 
 	var errandConfig config.InstanceIteratorConfig
 	configContents, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
 	}
 
 	if err := yaml.Unmarshal(configContents, &errandConfig); err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	if errandConfig.EnableStructuredLogging {
+		loggerFactory = loggerfactory.NewStructured(os.Stdout, "upgrade-all-service-instances")
+		logger = loggerFactory.New()
 	}
 
 	configurator, err := instanceiterator.NewConfigurator(errandConfig, logger, "upgrade-all")
 	if err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+	}
+
+	configurator.Resume = resume
+	configurator.Pauser = instanceiterator.NewSignalPauser(syscall.SIGUSR1, syscall.SIGUSR2)
+
+	if dryRun {
+		configurator.SetUpgradePreviewTriggerer(logger)
+
+		if err := instanceiterator.New(configurator).Iterate(); err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
+		}
+		return
 	}
 
 	cfClient := createCFClient(errandConfig, logger)
@@ -53,14 +75,14 @@ func main() {
 		configurator.SetUpgradeTriggererToCF(cfClient, logger)
 
 		if err := instanceiterator.New(configurator).Iterate(); err != nil {
-			logger.Fatalln(err.Error())
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
 		}
 	}
 
 	configurator.SetUpgradeTriggererToBOSH()
 
 	if err := instanceiterator.New(configurator).Iterate(); err != nil {
-		logger.Fatalln(err.Error())
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Fatalln(err.Error())
 	}
 }
 
@@ -68,12 +90,12 @@ func createCFClient(errandConfig config.InstanceIteratorConfig, logger *log.Logg
 	if errandConfig.CF != (config.CF{}) {
 		cfAuthenticator, err := errandConfig.CF.NewAuthHeaderBuilder(errandConfig.CF.DisableSSLCertVerification)
 		if err != nil {
-			logger.Printf("Error creating CF authorization header builder: %s", err)
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("Error creating CF authorization header builder: %s", err)
 			return nil
 		}
 		cfClient, err := cf.New(errandConfig.CF.URL, cfAuthenticator, []byte(errandConfig.CF.TrustedCert), errandConfig.CF.DisableSSLCertVerification, logger)
 		if err != nil {
-			logger.Printf("Error creating Cloud Foundry client: %s", err)
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("Error creating Cloud Foundry client: %s", err)
 			return nil
 		}
 		return cfClient
@@ -0,0 +1,72 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/configvalidator"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+// validate-config checks a broker config, including calls to its service
+// adapters, and prints a JSON report to stdout. It exits non-zero when the
+// config is invalid.
+func main() {
+	// the report is printed to stdout, so that it can be parsed
+	loggerFactory := loggerfactory.New(os.Stderr, "validate-config", loggerfactory.Flags)
+	logger := loggerFactory.New()
+
+	configFilePath := flag.String("configFilePath", "", "path to the broker config file")
+	flag.Parse()
+	if *configFilePath == "" {
+		logger.Fatal("must supply -configFilePath")
+	}
+
+	configFileBytes, err := os.ReadFile(*configFilePath)
+	if err != nil {
+		logger.Fatalf("error reading config file: %s", err)
+	}
+
+	var conf config.Config
+	if err := yaml.Unmarshal(configFileBytes, &conf); err != nil {
+		logger.Fatalf("error parsing config file: %s", err)
+	}
+
+	adapterClientFor := func(adapter config.ServiceAdapter) task.ServiceAdapterClient {
+		client := &serviceadapter.Client{
+			ExternalBinPath: adapter.Path,
+			CommandRunner:   serviceadapter.NewCommandRunner(),
+			UsingStdin:      conf.Broker.UsingStdin,
+			Timeouts:        adapter.Timeouts(),
+		}
+		if adapter.UsesServer() {
+			client.ExternalBinPath = "unix://" + adapter.Socket
+			client.CommandRunner = serviceadapter.NewServerCommandRunner(adapter.Socket)
+		}
+		return client
+	}
+
+	report := configvalidator.New(adapterClientFor, logger).Validate(conf)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Fatalf("error writing report: %s", err)
+	}
+
+	if !report.Valid {
+		os.Exit(1)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/manifestsecrets"
 	manifestsecretsfakes "github.com/pivotal-cf/on-demand-service-broker/manifestsecrets/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 	odbserviceadapter "github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	serviceadapterfakes "github.com/pivotal-cf/on-demand-service-broker/serviceadapter/fakes"
@@ -81,10 +82,13 @@ func StartServer(conf config.Config, stopServerChan chan os.Signal, fakeCommandR
 
 	server := apiserver.New(
 		conf,
+		config.NewOfferingStore(conf.Offerings()),
+		nil,
 		fakeBroker,
 		"collaboration-tests",
 		loggerFactory,
 		logger,
+		metrics.NewRegistry(),
 	)
 
 	server.ErrorLog = logger
@@ -112,15 +112,15 @@ var _ = Describe("Binding", func() {
 			response, bodyContent := doBindRequest(instanceID, bindingID, bindDetails)
 
 			By("fetching the VM info for the deployment")
-			deploymentName, _ := fakeBoshClient.VMsArgsForCall(0)
+			_, deploymentName, _ := fakeBoshClient.VMsArgsForCall(0)
 			Expect(deploymentName).To(Equal(expectedDeploymentName))
 
 			By("fetching the deployment manifest")
-			deploymentName, _ = fakeBoshClient.GetDeploymentArgsForCall(0)
+			_, deploymentName, _ = fakeBoshClient.GetDeploymentArgsForCall(0)
 			Expect(deploymentName).To(Equal(expectedDeploymentName))
 
 			By("fetching the Bosh DNS config")
-			dnsDeploymentName, bindingBoshDNSConfig := fakeBoshClient.GetDNSAddressesArgsForCall(0)
+			_, dnsDeploymentName, bindingBoshDNSConfig := fakeBoshClient.GetDNSAddressesArgsForCall(0)
 			Expect(dnsDeploymentName).To(Equal(expectedDeploymentName))
 			Expect(bindingBoshDNSConfig).To(Equal(bindingWithDNSConf))
 
@@ -407,7 +407,7 @@ func doBindRequest(instanceID, bindingID string, bindDetails domain.BindDetails)
 
 func getBindInputParams() sdk.CreateBindingJSONParams {
 	Expect(fakeCommandRunner.RunWithInputParamsCallCount()).To(Equal(1))
-	input, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+	_, input, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 	Expect(varArgs).To(HaveLen(2))
 	Expect(varArgs[1]).To(Equal("create-binding"))
 	inputParams, ok := input.(sdk.InputParams)
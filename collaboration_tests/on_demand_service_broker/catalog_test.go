@@ -92,11 +92,12 @@ var _ = Describe("Catalog", func() {
 			Expect(catalog).To(Equal(map[string][]domain.Service{
 				"services": {
 					{
-						ID:            serviceID,
-						Name:          serviceName,
-						Description:   serviceDescription,
-						Bindable:      serviceBindable,
-						PlanUpdatable: servicePlanUpdatable,
+						ID:                   serviceID,
+						Name:                 serviceName,
+						Description:          serviceDescription,
+						Bindable:             serviceBindable,
+						PlanUpdatable:        servicePlanUpdatable,
+						InstancesRetrievable: true,
 						Metadata: &domain.ServiceMetadata{
 							DisplayName:         serviceMetadataDisplayName,
 							ImageUrl:            serviceMetadataImageURL,
@@ -233,11 +234,12 @@ var _ = Describe("Catalog", func() {
 			Expect(catalog).To(Equal(map[string][]domain.Service{
 				"services": {
 					{
-						ID:            serviceID,
-						Name:          serviceName,
-						Description:   serviceDescription,
-						Bindable:      serviceBindable,
-						PlanUpdatable: servicePlanUpdatable,
+						ID:                   serviceID,
+						Name:                 serviceName,
+						Description:          serviceDescription,
+						Bindable:             serviceBindable,
+						PlanUpdatable:        servicePlanUpdatable,
+						InstancesRetrievable: true,
 						Metadata: &domain.ServiceMetadata{
 							DisplayName:         serviceMetadataDisplayName,
 							ImageUrl:            serviceMetadataImageURL,
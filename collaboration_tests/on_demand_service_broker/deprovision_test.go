@@ -105,7 +105,7 @@ var _ = Describe("Deprovision", func() {
 			Eventually(loggerBuffer).Should(gbytes.Say(`removing deployment for instance`))
 
 			By("Passing force flag to DeleteDeployment")
-			_, _, force, _, _ := fakeBoshClient.DeleteDeploymentArgsForCall(0)
+			_, _, _, force, _, _ := fakeBoshClient.DeleteDeploymentArgsForCall(0)
 			Expect(force).To(Equal(expectedForceDelete))
 		})
 
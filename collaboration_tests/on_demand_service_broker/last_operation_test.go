@@ -16,6 +16,7 @@
 package on_demand_service_broker_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -644,7 +645,7 @@ var _ = Describe("Last Operation", func() {
 				By("running the second errand")
 				firstErrand.State = boshdirector.TaskDone
 				fakeBoshClient.GetNormalisedTasksByContextReturnsOnCall(1, boshdirector.BoshTasks{firstErrand}, nil)
-				fakeBoshClient.RunErrandStub = func(deploymentName, errand string, instances []string, contextID string, log *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error) {
+				fakeBoshClient.RunErrandStub = func(_ context.Context, deploymentName, errand string, instances []string, contextID string, log *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error) {
 					defer GinkgoRecover()
 					Expect(errand).To(Equal("bar"))
 					return secondErrand.ID, nil
@@ -671,7 +672,7 @@ var _ = Describe("Last Operation", func() {
 				secondErrand.State = boshdirector.TaskDone
 				fakeBoshClient.GetNormalisedTasksByContextReturnsOnCall(2, boshdirector.BoshTasks{secondErrand, firstErrand}, nil)
 				fakeBoshClient.DeleteDeploymentReturns(processingTask.ID, nil)
-				fakeBoshClient.GetTaskStub = func(taskID int, logger *log.Logger) (boshdirector.BoshTask, error) {
+				fakeBoshClient.GetTaskStub = func(_ context.Context, taskID int, logger *log.Logger) (boshdirector.BoshTask, error) {
 					defer GinkgoRecover()
 					Expect(taskID).To(Equal(processingTask.ID))
 					return processingTask, nil
@@ -801,7 +802,7 @@ var _ = Describe("Last Operation", func() {
 
 				By("deleting deployment from BOSH")
 				Expect(fakeBoshClient.DeleteDeploymentCallCount()).To(Equal(1), "expected to call DeleteDeployment in bosh")
-				_, _, force, _, _ := fakeBoshClient.DeleteDeploymentArgsForCall(0)
+				_, _, _, force, _, _ := fakeBoshClient.DeleteDeploymentArgsForCall(0)
 				Expect(force).To(BeTrue())
 
 				By("deleting the configs from BOSH")
@@ -834,7 +835,7 @@ var _ = Describe("Last Operation", func() {
 
 				By("deleting deployment from BOSH")
 				Expect(fakeBoshClient.DeleteDeploymentCallCount()).To(Equal(1), "expected to call DeleteDeployment in bosh")
-				_, _, force, _, _ := fakeBoshClient.DeleteDeploymentArgsForCall(0)
+				_, _, _, force, _, _ := fakeBoshClient.DeleteDeploymentArgsForCall(0)
 				Expect(force).To(BeTrue())
 
 				By("returning the correct HTTP status code")
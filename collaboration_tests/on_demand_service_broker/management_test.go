@@ -301,33 +301,33 @@ var _ = Describe("Management API", func() {
 
 			var brokerMetrics []mgmtapi.Metric
 			Expect(json.Unmarshal(bodyContent, &brokerMetrics)).To(Succeed())
-			Expect(brokerMetrics).To(ConsistOf(
-				mgmtapi.Metric{
+			Expect(brokerMetrics).To(ConsistOf(append([]mgmtapi.Metric{
+				{
 					Key:   "/on-demand-broker/service-name/dedicated-plan-name/total_instances",
 					Value: 1,
 					Unit:  "count",
 				},
-				mgmtapi.Metric{
+				{
 					Key:   "/on-demand-broker/service-name/dedicated-plan-name/quota_remaining",
 					Value: 0,
 					Unit:  "count",
 				},
-				mgmtapi.Metric{
+				{
 					Key:   "/on-demand-broker/service-name/high-memory-plan-name/total_instances",
 					Value: 4,
 					Unit:  "count",
 				},
-				mgmtapi.Metric{
+				{
 					Key:   "/on-demand-broker/service-name/total_instances",
 					Value: 5,
 					Unit:  "count",
 				},
-				mgmtapi.Metric{
+				{
 					Key:   "/on-demand-broker/service-name/quota_remaining",
 					Value: 7,
 					Unit:  "count",
 				},
-			))
+			}, zeroContentionMetrics...)))
 		})
 
 		Context("when no global quota is configured", func() {
@@ -341,28 +341,28 @@ var _ = Describe("Management API", func() {
 
 				var brokerMetrics []mgmtapi.Metric
 				Expect(json.Unmarshal(bodyContent, &brokerMetrics)).To(Succeed())
-				Expect(brokerMetrics).To(ConsistOf(
-					mgmtapi.Metric{
+				Expect(brokerMetrics).To(ConsistOf(append([]mgmtapi.Metric{
+					{
 						Key:   "/on-demand-broker/service-name/dedicated-plan-name/total_instances",
 						Value: 1,
 						Unit:  "count",
 					},
-					mgmtapi.Metric{
+					{
 						Key:   "/on-demand-broker/service-name/dedicated-plan-name/quota_remaining",
 						Value: 0,
 						Unit:  "count",
 					},
-					mgmtapi.Metric{
+					{
 						Key:   "/on-demand-broker/service-name/high-memory-plan-name/total_instances",
 						Value: 4,
 						Unit:  "count",
 					},
-					mgmtapi.Metric{
+					{
 						Key:   "/on-demand-broker/service-name/total_instances",
 						Value: 5,
 						Unit:  "count",
 					},
-				))
+				}, zeroContentionMetrics...)))
 			})
 		})
 
@@ -408,13 +408,13 @@ var _ = Describe("Management API", func() {
 				Expect(response.StatusCode).To(Equal(http.StatusAccepted))
 
 				By("upgrades the correct instance")
-				input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+				_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 				actualInput, ok := input.(sdk.InputParams)
 				Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 				Expect(actualOthers[1]).To(Equal("generate-manifest"))
 				Expect(actualInput.GenerateManifest.ServiceDeployment).To(ContainSubstring(`"deployment_name":"service-instance_some-instance-id"`))
 
-				_, contextID, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
+				_, _, contextID, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
 				Expect(contextID).NotTo(BeEmpty())
 
 				By("updating the bosh configs")
@@ -451,13 +451,13 @@ var _ = Describe("Management API", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusAccepted))
 
 					By("upgrades the correct instance")
-					input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+					_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 					actualInput, ok := input.(sdk.InputParams)
 					Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 					Expect(actualOthers[1]).To(Equal("generate-manifest"))
 					Expect(actualInput.GenerateManifest.ServiceDeployment).To(ContainSubstring(`"deployment_name":"service-instance_some-instance-id"`))
 
-					_, contextID, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
+					_, _, contextID, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
 					Expect(contextID).NotTo(BeEmpty())
 
 					By("returning the correct operation data")
@@ -534,7 +534,7 @@ var _ = Describe("Management API", func() {
 				Expect(response.StatusCode).To(Equal(http.StatusAccepted))
 
 				By("recreates the correct instance")
-				deploymentName, _, _, _ := fakeTaskBoshClient.RecreateArgsForCall(0)
+				_, deploymentName, _, _, _ := fakeTaskBoshClient.RecreateArgsForCall(0)
 				Expect(deploymentName).To(Equal(fmt.Sprintf("service-instance_%s", instanceID)))
 				Expect(fakeCommandRunner.RunWithInputParamsCallCount()).To(BeZero())
 
@@ -593,6 +593,17 @@ var _ = Describe("Management API", func() {
 	})
 })
 
+var zeroContentionMetrics = []mgmtapi.Metric{
+	{Key: "/on-demand-broker/instance_locks/acquisitions", Value: 0, Unit: "count"},
+	{Key: "/on-demand-broker/instance_locks/contended", Value: 0, Unit: "count"},
+	{Key: "/on-demand-broker/instance_locks/waiting", Value: 0, Unit: "count"},
+	{Key: "/on-demand-broker/instance_locks/wait_time", Value: 0, Unit: "ms"},
+	{Key: "/on-demand-broker/adapter_calls/acquisitions", Value: 0, Unit: "count"},
+	{Key: "/on-demand-broker/adapter_calls/contended", Value: 0, Unit: "count"},
+	{Key: "/on-demand-broker/adapter_calls/waiting", Value: 0, Unit: "count"},
+	{Key: "/on-demand-broker/adapter_calls/wait_time", Value: 0, Unit: "ms"},
+}
+
 func doProcessRequest(serviceInstanceID, body, operationType string) (*http.Response, []byte) {
 	return doRequestWithAuth(
 		http.MethodPatch,
@@ -157,7 +157,7 @@ password: ((odb_secret:foo))`,
 				resp, _ := doProvisionRequest(instanceID, planWithQuotaID, arbitraryParams, nil, true)
 				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
 
-				manifest, _, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
+				_, manifest, _, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
 				Expect(manifest).To(ContainSubstring("((odb_secret:foo))"))
 			})
 		})
@@ -167,7 +167,7 @@ password: ((odb_secret:foo))`,
 				resp, _ := doProvisionRequest(instanceID, planWithQuotaID, arbitraryParams, nil, true)
 				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
 
-				manifest, _, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
+				_, manifest, _, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
 				Expect(manifest).To(ContainSubstring(fmt.Sprintf("/odb/%s/service-instance_%s/foo", conf.ServiceCatalog.ID, instanceID)))
 				Expect(manifest).ToNot(ContainSubstring("((odb_secret:"))
 
@@ -213,7 +213,7 @@ password: ((odb_secret:foo))`,
 
 			By("calling the deployer with the correct parameters")
 
-			input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+			_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 			actualInput, ok := input.(sdk.InputParams)
 			Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 			Expect(actualOthers[1]).To(Equal("generate-manifest"))
@@ -237,7 +237,7 @@ password: ((odb_secret:foo))`,
 				"parameters":        arbitraryParams,
 			}))
 
-			_, boshContextID, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
+			_, _, boshContextID, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
 			Expect(boshContextID).To(BeEmpty())
 
 			By("logging the incoming request")
@@ -572,7 +572,7 @@ password: ((odb_secret:foo))`,
 				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
 
 				Expect(fakeTaskBoshClient.UpdateConfigCallCount()).To(Equal(1))
-				configType, configName, configContent, _ := fakeTaskBoshClient.UpdateConfigArgsForCall(0)
+				_, configType, configName, configContent, _ := fakeTaskBoshClient.UpdateConfigArgsForCall(0)
 				Expect(configType).To(Equal("cloud"))
 				Expect(configName).To(Equal("service-instance_some-instance-id"))
 				Expect(configContent).To(Equal([]byte("{}")))
@@ -658,7 +658,7 @@ func setupFakeGenerateManifestOutput() {
 }
 
 func getProvisionArgs() sdk.DashboardUrlJSONParams {
-	input, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(1)
+	_, input, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(1)
 	inputParams, ok := input.(sdk.InputParams)
 	Expect(ok).To(BeTrue(), "couldn't cast dashboard input to sdk.InputParams")
 	Expect(varArgs).To(HaveLen(2))
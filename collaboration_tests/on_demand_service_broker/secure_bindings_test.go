@@ -87,7 +87,7 @@ var _ = Describe("Secure Binding", func() {
 
 			By("calling bind on the adapter")
 			Expect(fakeCommandRunner.RunWithInputParamsCallCount()).To(Equal(1))
-			_, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+			_, _, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 			Expect(varArgs).To(HaveLen(2))
 			Expect(varArgs[1]).To(Equal("create-binding"))
 
@@ -135,7 +135,7 @@ var _ = Describe("Secure Binding", func() {
 
 			By("calling bind on the adapter")
 			Expect(fakeCommandRunner.RunWithInputParamsCallCount()).To(Equal(1))
-			_, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+			_, _, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 			Expect(varArgs).To(HaveLen(2))
 			Expect(varArgs[1]).To(Equal("create-binding"))
 
@@ -185,7 +185,7 @@ var _ = Describe("Secure Binding", func() {
 
 			By("calling bind on the adapter")
 			Expect(fakeCommandRunner.RunWithInputParamsCallCount()).To(Equal(1))
-			_, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+			_, _, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 			Expect(varArgs).To(HaveLen(2))
 			Expect(varArgs[1]).To(Equal("delete-binding"))
 
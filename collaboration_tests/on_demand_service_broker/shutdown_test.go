@@ -16,6 +16,7 @@
 package on_demand_service_broker_test
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -71,7 +72,7 @@ var _ = Describe("Shutdown of the broker process", func() {
 		deployStarted := make(chan bool)
 		deployFinished := make(chan bool)
 
-		fakeTaskBoshClient.DeployStub = func(manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error) {
+		fakeTaskBoshClient.DeployStub = func(_ context.Context, manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error) {
 			deployStarted <- true
 			<-deployFinished
 			return 0, nil
@@ -110,7 +111,7 @@ var _ = Describe("Shutdown of the broker process", func() {
 		deployStarted := make(chan bool)
 		deployFinished := make(chan bool)
 
-		fakeTaskBoshClient.DeployStub = func(manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error) {
+		fakeTaskBoshClient.DeployStub = func(_ context.Context, manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error) {
 			deployStarted <- true
 			<-deployFinished
 			return 0, errors.New("interrupted")
@@ -224,7 +224,7 @@ func doUnbindRequest(instanceID, bindingID, serviceID, planID string) (*http.Res
 
 func getUnbindInputParams() sdk.DeleteBindingJSONParams {
 	Expect(fakeCommandRunner.RunWithInputParamsCallCount()).To(Equal(1))
-	input, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+	_, input, varArgs := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 	Expect(varArgs).To(HaveLen(2))
 	Expect(varArgs[1]).To(Equal("delete-binding"))
 	inputParams, ok := input.(sdk.InputParams)
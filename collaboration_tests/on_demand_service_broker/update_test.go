@@ -189,6 +189,7 @@ var _ = Describe("Update a service instance", func() {
 			Expect(operationData).To(Equal(broker.OperationData{
 				OperationType: broker.OperationTypeUpdate,
 				BoshTaskID:    updateTaskID,
+				QuotaReserved: true,
 			}))
 
 			By("logging the update request")
@@ -202,7 +203,7 @@ var _ = Describe("Update a service instance", func() {
 			resp, bodyContent := doUpdateRequest(requestBody, instanceID)
 			Expect(resp.StatusCode).To(Equal(http.StatusAccepted), string(bodyContent))
 
-			_, boshContextId, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
+			_, _, boshContextId, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
 
 			By("including errands in the operation data")
 			var updateResponse apiresponses.UpdateResponse
@@ -215,6 +216,7 @@ var _ = Describe("Update a service instance", func() {
 				BoshTaskID:    updateTaskID,
 				BoshContextID: boshContextId,
 				Errands:       []brokerConfig.Errand{{Name: "health-check"}},
+				QuotaReserved: true,
 			}))
 
 			By("logging the update request")
@@ -246,6 +248,7 @@ var _ = Describe("Update a service instance", func() {
 			Expect(operationData).To(Equal(broker.OperationData{
 				OperationType: broker.OperationTypeUpdate,
 				BoshTaskID:    updateTaskID,
+				QuotaReserved: true,
 			}))
 
 			By("logging the update request")
@@ -328,7 +331,7 @@ var _ = Describe("Update a service instance", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
 
 			By("calling the adapter with the correct arguments", func() {
-				input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(1)
+				_, input, actualOthers := fakeCommandRunner.RunWithInputParamsArgsForCall(1)
 				actualInput, ok := input.(sdk.InputParams)
 				Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 				Expect(actualOthers[1]).To(Equal("generate-manifest"))
@@ -353,7 +356,7 @@ var _ = Describe("Update a service instance", func() {
 
 				Expect(reqParams).To(Equal(requestBody))
 
-				_, boshContextId, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
+				_, _, boshContextId, _, _ := fakeTaskBoshClient.DeployArgsForCall(0)
 				Expect(boshContextId).To(BeEmpty())
 			})
 
@@ -496,7 +499,7 @@ properties:
 			It("sends the adapter previous BOSH configs", func() {
 				doUpdateRequest(requestBody, instanceID)
 
-				generateManifestInput, _ := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
+				_, generateManifestInput, _ := fakeCommandRunner.RunWithInputParamsArgsForCall(0)
 				actualInput, ok := generateManifestInput.(sdk.InputParams)
 				Expect(ok).To(BeTrue(), "command runner takes a sdk.inputparams obj")
 				Expect(actualInput.GenerateManifest.PreviousConfigs).To(Equal(`{"cloud":"{cloud_properties: { foo: bar }}"}`))
@@ -508,7 +511,7 @@ properties:
 				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
 				Expect(fakeTaskBoshClient.GetConfigsCallCount()).To(Equal(1), "GetConfigs should have been called")
 				Expect(fakeTaskBoshClient.UpdateConfigCallCount()).To(Equal(1), "UpdateConfig should have been called")
-				configType, configName, configContent, _ := fakeTaskBoshClient.UpdateConfigArgsForCall(0)
+				_, configType, configName, configContent, _ := fakeTaskBoshClient.UpdateConfigArgsForCall(0)
 				Expect(configType).To(Equal("cloud"))
 				Expect(configName).To(Equal("service-instance_some-instance-id"))
 				Expect(configContent).To(Equal([]byte("{ bar: baz }")))
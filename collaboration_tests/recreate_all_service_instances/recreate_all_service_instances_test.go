@@ -161,7 +161,7 @@ var _ = Describe("Recreate all service instances", func() {
 
 				var instancesRecreated []string
 				for i := 0; i < expectedRecreateCallCount; i++ {
-					deploymentName, _, _, _ := fakeTaskBoshClient.RecreateArgsForCall(i)
+					_, deploymentName, _, _, _ := fakeTaskBoshClient.RecreateArgsForCall(i)
 					instancesRecreated = append(instancesRecreated, deploymentName)
 				}
 				Expect(instancesRecreated).To(ConsistOf("service-instance_service-1", "service-instance_service-2"))
@@ -16,7 +16,9 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 	"gopkg.in/yaml.v2"
@@ -33,27 +35,141 @@ type Config struct {
 	ServiceAdapter      ServiceAdapter      `yaml:"service_adapter"`
 	ServiceDeployment   ServiceDeployment   `yaml:"service_deployment"`
 	ServiceCatalog      ServiceOffering     `yaml:"service_catalog"`
+	ServiceOfferings    []OfferingConfig    `yaml:"service_offerings,omitempty"`
 	BoshCredhub         BoshCredhub         `yaml:"bosh_credhub"`
 }
 
+// OfferingConfig describes a service offering served by the broker in addition
+// to the one configured by service_adapter, service_deployment and
+// service_catalog.
+type OfferingConfig struct {
+	ServiceAdapter    ServiceAdapter    `yaml:"service_adapter"`
+	ServiceDeployment ServiceDeployment `yaml:"service_deployment"`
+	ServiceCatalog    ServiceOffering   `yaml:"service_catalog"`
+}
+
 type Broker struct {
-	Port                       int       `yaml:"port"`
-	Username                   string    `yaml:"username"`
-	Password                   string    `yaml:"password"`
-	DisableSSLCertVerification bool      `yaml:"disable_ssl_cert_verification"`
-	DisableBoshConfigs         bool      `yaml:"disable_bosh_configs"`
-	StartUpBanner              bool      `yaml:"startup_banner"`
-	ShutdownTimeoutSecs        int       `yaml:"shutdown_timeout_in_seconds"`
-	DisableCFStartupChecks     bool      `yaml:"disable_cf_startup_checks"`
-	ExposeOperationalErrors    bool      `yaml:"expose_operational_errors"`
-	EnablePlanSchemas          bool      `yaml:"enable_plan_schemas"`
-	UsingStdin                 bool      `yaml:"use_stdin"`
-	EnableSecureManifests      bool      `yaml:"enable_secure_manifests"`
-	EnableTelemetry            bool      `yaml:"enable_telemetry"`
-	EnableOptimisedUpgrades    bool      `yaml:"enable_optimised_upgrades"`
-	SupportBackupAgentBinding  bool      `yaml:"support_backup_agent_binding"`
-	TLS                        TLSConfig `yaml:"tls"`
-	SkipCheckForPendingChanges bool      `yaml:"skip_check_for_pending_changes"`
+	Port                       int           `yaml:"port"`
+	Username                   string        `yaml:"username"`
+	Password                   string        `yaml:"password"`
+	DisableSSLCertVerification bool          `yaml:"disable_ssl_cert_verification"`
+	DisableBoshConfigs         bool          `yaml:"disable_bosh_configs"`
+	StartUpBanner              bool          `yaml:"startup_banner"`
+	ShutdownTimeoutSecs        int           `yaml:"shutdown_timeout_in_seconds"`
+	DisableCFStartupChecks     bool          `yaml:"disable_cf_startup_checks"`
+	ExposeOperationalErrors    bool          `yaml:"expose_operational_errors"`
+	EnablePlanSchemas          bool          `yaml:"enable_plan_schemas"`
+	UsingStdin                 bool          `yaml:"use_stdin"`
+	EnableSecureManifests      bool          `yaml:"enable_secure_manifests"`
+	EnableTelemetry            bool          `yaml:"enable_telemetry"`
+	EnableOptimisedUpgrades    bool          `yaml:"enable_optimised_upgrades"`
+	SupportBackupAgentBinding  bool          `yaml:"support_backup_agent_binding"`
+	EnableAsyncBinding         bool          `yaml:"enable_async_binding"`
+	MaxConcurrentAdapterCalls  int           `yaml:"max_concurrent_adapter_calls"`
+	TLS                        TLSConfig     `yaml:"tls"`
+	SkipCheckForPendingChanges bool          `yaml:"skip_check_for_pending_changes"`
+	EnableStructuredLogging    bool          `yaml:"enable_structured_logging"`
+	Tracing                    Tracing       `yaml:"tracing"`
+	Notifications              Notifications `yaml:"notifications"`
+	// InstanceCounter is where the broker counts service instances for
+	// quotas and metrics: the CF API, or the BOSH deployments of the broker
+	// and the plans recorded for them.
+	InstanceCounter string `yaml:"instance_counter"`
+}
+
+const (
+	InstanceCounterCF   = "cf"
+	InstanceCounterBOSH = "bosh"
+)
+
+// Tracing configures where the broker exports the spans of the requests it
+// handles. Tracing is disabled when no exporter is set.
+type Tracing struct {
+	Exporter     string `yaml:"exporter"`
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	File         string `yaml:"file"`
+}
+
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+func (t Tracing) Validate() error {
+	switch t.Exporter {
+	case "", TracingExporterStdout:
+	case TracingExporterOTLP:
+		if t.OTLPEndpoint == "" {
+			return errors.New("broker.tracing.otlp_endpoint can't be empty when the exporter is otlp")
+		}
+	case TracingExporterFile:
+		if t.File == "" {
+			return errors.New("broker.tracing.file can't be empty when the exporter is file")
+		}
+	default:
+		return fmt.Errorf("broker.tracing.exporter must be one of otlp, stdout or file, got %q", t.Exporter)
+	}
+	return nil
+}
+
+// Notifications configures the webhooks that are sent the lifecycle events of
+// service instances and bindings, as CloudEvents. Events are queued and sent
+// in the background, so that a webhook that is down never holds up a request.
+type Notifications struct {
+	Webhooks []Webhook `yaml:"webhooks"`
+	// Source is the CloudEvents source of the events. It distinguishes the
+	// events of brokers that send to the same webhook.
+	Source     string `yaml:"source"`
+	QueueSize  int    `yaml:"queue_size"`
+	MaxRetries *int   `yaml:"max_retries"`
+	// TimeoutSecs bounds each request to a webhook. It defaults to 10 seconds.
+	TimeoutSecs int `yaml:"timeout_in_seconds"`
+}
+
+// Webhook is an endpoint that is sent lifecycle events. When a secret is set,
+// the body of each request is signed with it. Events limits the events sent
+// to the endpoint to those of the given types, such as instance or
+// instance.create.succeeded; all events are sent when it is empty.
+type Webhook struct {
+	URL    string   `yaml:"url"`
+	Secret string   `yaml:"secret"`
+	Events []string `yaml:"events"`
+}
+
+func (n Notifications) Validate() error {
+	if n.QueueSize < 0 {
+		return errors.New("broker.notifications.queue_size can't be negative")
+	}
+	if n.MaxRetries != nil && *n.MaxRetries < 0 {
+		return errors.New("broker.notifications.max_retries can't be negative")
+	}
+	if n.TimeoutSecs < 0 {
+		return errors.New("broker.notifications.timeout_in_seconds can't be negative")
+	}
+	for _, webhook := range n.Webhooks {
+		if err := webhook.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w Webhook) Validate() error {
+	if w.URL == "" {
+		return errors.New("broker.notifications.webhooks url can't be empty")
+	}
+	webhookURL, err := url.Parse(w.URL)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return fmt.Errorf("broker.notifications.webhooks url %q must be an http or https URL", w.URL)
+	}
+	for _, event := range w.Events {
+		resource, _, _ := strings.Cut(event, ".")
+		if resource != "instance" && resource != "binding" {
+			return fmt.Errorf("broker.notifications.webhooks event %q must start with instance or binding", event)
+		}
+	}
+	return nil
 }
 
 type BoshCredhub struct {
@@ -127,6 +243,15 @@ func (c Config) Validate() error {
 		return err
 	}
 
+	if c.Broker.EnableAsyncBinding {
+		if c.Broker.DisableBoshConfigs {
+			return errors.New("broker.enable_async_binding can't be true when disable_bosh_configs is true, as the bindings are recorded in BOSH configs")
+		}
+		if !c.HasRuntimeCredHub() {
+			return errors.New("broker.enable_async_binding requires credhub to be configured, as the credentials of the bindings are stored there")
+		}
+	}
+
 	if err := c.Bosh.Validate(); err != nil {
 		return fmt.Errorf("BOSH configuration error: %s", err)
 	}
@@ -136,15 +261,70 @@ func (c Config) Validate() error {
 		}
 	}
 
-	if err := checkIsExecutableFile(c.ServiceAdapter.Path); err != nil {
-		return fmt.Errorf("checking for executable service adapter file: %s", err)
+	serviceIDs := map[string]bool{}
+	serviceNames := map[string]bool{}
+	planIDs := map[string]bool{}
+	for _, offering := range c.Offerings() {
+		if err := offering.Validate(); err != nil {
+			return err
+		}
+
+		if len(c.ServiceOfferings) == 0 {
+			continue
+		}
+
+		if serviceIDs[offering.ServiceCatalog.ID] {
+			return fmt.Errorf("service offering ID '%s' is used by more than one service offering", offering.ServiceCatalog.ID)
+		}
+		serviceIDs[offering.ServiceCatalog.ID] = true
+
+		if serviceNames[offering.ServiceCatalog.Name] {
+			return fmt.Errorf("service name '%s' is used by more than one service offering", offering.ServiceCatalog.Name)
+		}
+		serviceNames[offering.ServiceCatalog.Name] = true
+
+		for _, plan := range offering.ServiceCatalog.Plans {
+			if planIDs[plan.ID] {
+				return fmt.Errorf("plan ID '%s' is used by more than one service offering", plan.ID)
+			}
+			planIDs[plan.ID] = true
+		}
 	}
 
-	if err := c.ServiceDeployment.Validate(); err != nil {
+	return nil
+}
+
+// Offerings returns every service offering served by the broker, starting with
+// the one configured at the top level.
+func (c Config) Offerings() []OfferingConfig {
+	offerings := []OfferingConfig{{
+		ServiceAdapter:    c.ServiceAdapter,
+		ServiceDeployment: c.ServiceDeployment,
+		ServiceCatalog:    c.ServiceCatalog,
+	}}
+	return append(offerings, c.ServiceOfferings...)
+}
+
+// ServiceCatalogs returns the catalog of every service offering served by the
+// broker, starting with the one configured at the top level.
+func (c Config) ServiceCatalogs() []ServiceOffering {
+	var catalogs []ServiceOffering
+	for _, offering := range c.Offerings() {
+		catalogs = append(catalogs, offering.ServiceCatalog)
+	}
+	return catalogs
+}
+
+func (o OfferingConfig) Validate() error {
+	if err := o.ServiceAdapter.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.ServiceDeployment.Validate(); err != nil {
 		return err
 	}
 
-	if err := c.ServiceCatalog.Validate(); err != nil {
+	if err := o.ServiceCatalog.Validate(); err != nil {
 		return err
 	}
 
@@ -156,8 +336,19 @@ func (c Config) HasRuntimeCredHub() bool {
 }
 
 func (c Config) HasBindingWithDNSConfigured() bool {
-	for _, plan := range c.ServiceCatalog.Plans {
-		if len(plan.BindingWithDNS) > 0 {
+	for _, catalog := range c.ServiceCatalogs() {
+		for _, plan := range catalog.Plans {
+			if len(plan.BindingWithDNS) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Config) HasLifecycleErrands() bool {
+	for _, catalog := range c.ServiceCatalogs() {
+		if catalog.HasLifecycleErrands() {
 			return true
 		}
 	}
@@ -179,7 +370,21 @@ func (b Broker) Validate() error {
 		return errors.New("broker.password can't be empty")
 	}
 
-	return nil
+	switch b.InstanceCounter {
+	case "", InstanceCounterCF:
+	case InstanceCounterBOSH:
+		if b.DisableBoshConfigs {
+			return errors.New("broker.instance_counter can't be bosh when disable_bosh_configs is true, as the plans of the instances are stored in BOSH configs")
+		}
+	default:
+		return fmt.Errorf("broker.instance_counter must be one of cf or bosh, got %q", b.InstanceCounter)
+	}
+
+	if err := b.Tracing.Validate(); err != nil {
+		return err
+	}
+
+	return b.Notifications.Validate()
 }
 
 type ServiceDeployment struct {
@@ -381,8 +586,81 @@ type CredHub struct {
 	InternalUAACaCert string `yaml:"internal_uaa_ca_cert"`
 }
 
+// ServiceAdapter is how the broker calls the service adapter. By default the
+// adapter at Path is executed for every call. With the server transport, calls
+// are sent to an adapter server listening on Socket instead.
 type ServiceAdapter struct {
-	Path string
+	Path         string
+	Transport    string         `yaml:"transport"`
+	Socket       string         `yaml:"socket"`
+	TimeoutsSecs map[string]int `yaml:"timeouts_in_seconds"`
+}
+
+// ServiceAdapterSubcommands are the subcommands the broker runs the service
+// adapter with.
+var ServiceAdapterSubcommands = []string{
+	"generate-manifest",
+	"create-binding",
+	"delete-binding",
+	"dashboard-url",
+	"generate-plan-schemas",
+}
+
+const (
+	ServiceAdapterTransportExec   = "exec"
+	ServiceAdapterTransportServer = "server"
+)
+
+func (s ServiceAdapter) UsesServer() bool {
+	return s.Transport == ServiceAdapterTransportServer
+}
+
+// Timeouts returns how long each subcommand may run for.
+func (s ServiceAdapter) Timeouts() map[string]time.Duration {
+	if len(s.TimeoutsSecs) == 0 {
+		return nil
+	}
+	timeouts := map[string]time.Duration{}
+	for subcommand, secs := range s.TimeoutsSecs {
+		timeouts[subcommand] = time.Duration(secs) * time.Second
+	}
+	return timeouts
+}
+
+func (s ServiceAdapter) Validate() error {
+	for subcommand, secs := range s.TimeoutsSecs {
+		if !IsServiceAdapterSubcommand(subcommand) {
+			return fmt.Errorf("service_adapter.timeouts_in_seconds: unknown subcommand %q, must be one of %s", subcommand, strings.Join(ServiceAdapterSubcommands, ", "))
+		}
+		if secs <= 0 {
+			return fmt.Errorf("service_adapter.timeouts_in_seconds: timeout for %s must be greater than 0", subcommand)
+		}
+	}
+
+	switch s.Transport {
+	case "", ServiceAdapterTransportExec:
+		if err := checkIsExecutableFile(s.Path); err != nil {
+			return fmt.Errorf("checking for executable service adapter file: %s", err)
+		}
+	case ServiceAdapterTransportServer:
+		if s.Socket == "" {
+			return errors.New("service_adapter.socket can't be empty when the transport is server")
+		}
+	default:
+		return fmt.Errorf("service_adapter.transport must be one of exec or server, got %q", s.Transport)
+	}
+	return nil
+}
+
+// IsServiceAdapterSubcommand reports whether subcommand is one of
+// ServiceAdapterSubcommands.
+func IsServiceAdapterSubcommand(subcommand string) bool {
+	for _, s := range ServiceAdapterSubcommands {
+		if s == subcommand {
+			return true
+		}
+	}
+	return false
 }
 
 func Parse(configFilePath string) (Config, error) {
@@ -443,7 +721,21 @@ func (s ServiceOffering) HasLifecycleErrands() bool {
 }
 
 func (s ServiceOffering) Validate() error {
+	if s.GlobalQuotas.Orgs != nil {
+		if err := s.GlobalQuotas.Orgs.validate("orgs"); err != nil {
+			return err
+		}
+	}
+	if s.GlobalQuotas.Spaces != nil {
+		if err := s.GlobalQuotas.Spaces.validate("spaces"); err != nil {
+			return err
+		}
+	}
+
 	for _, plan := range s.Plans {
+		if plan.Quotas.Orgs != nil || plan.Quotas.Spaces != nil {
+			return fmt.Errorf("plan %s can't have org or space quotas, they can only be set in global_quotas", plan.Name)
+		}
 		if plan.LifecycleErrands != nil {
 			for _, errand := range plan.LifecycleErrands.PostDeploy {
 				if err := s.validateLifecycleErrands(errand); err != nil {
@@ -599,11 +891,50 @@ type ResourceQuota struct {
 type Quotas struct {
 	ServiceInstanceLimit *int                     `yaml:"service_instance_limit,omitempty"`
 	Resources            map[string]ResourceQuota `yaml:"resources,omitempty"`
+	// Orgs and Spaces limit the service instances of each org and each
+	// space. They are only honoured in the global quotas.
+	Orgs   *ScopedQuotas `yaml:"orgs,omitempty"`
+	Spaces *ScopedQuotas `yaml:"spaces,omitempty"`
+}
+
+// ScopedQuotas are the quotas of each org, or each space, on its own. The
+// quotas of an org or space whose GUID is in Overrides replace Default.
+type ScopedQuotas struct {
+	Default   Quotas            `yaml:"default,omitempty"`
+	Overrides map[string]Quotas `yaml:"overrides,omitempty"`
 }
 
-type CanarySelectionParams map[string]string
+// For returns the quotas of the org or space with the GUID.
+func (s ScopedQuotas) For(guid string) Quotas {
+	if quotas, found := s.Overrides[guid]; found {
+		return quotas
+	}
+	return s.Default
+}
 
-func (filter CanarySelectionParams) String() string {
+func (s ScopedQuotas) validate(scope string) error {
+	quotas := map[string]Quotas{"default": s.Default}
+	for guid, q := range s.Overrides {
+		quotas["overrides."+guid] = q
+	}
+	for name, q := range quotas {
+		if q.Orgs != nil || q.Spaces != nil {
+			return fmt.Errorf("global_quotas.%s.%s can't have org or space quotas", scope, name)
+		}
+	}
+	return nil
+}
+
+// InstanceSelectionParams are the filters of the /mgmt/service_instances
+// endpoint. The iterator errands also accept instance_guids_file and
+// exclude_instance_guids_file, naming files with one instance GUID per line.
+type InstanceSelectionParams map[string]string
+
+// CanarySelectionParams select the canaries among the instances selected for
+// a run.
+type CanarySelectionParams = InstanceSelectionParams
+
+func (filter InstanceSelectionParams) String() string {
 	filters := []string{}
 	for k, v := range filter {
 		filters = append(filters, fmt.Sprintf("%s: %s", k, v))
@@ -612,17 +943,45 @@ func (filter CanarySelectionParams) String() string {
 }
 
 type InstanceIteratorConfig struct {
-	BrokerAPI              BrokerAPI             `yaml:"broker_api"`
-	PollingInterval        int                   `yaml:"polling_interval"`
-	AttemptInterval        int                   `yaml:"attempt_interval"`
-	AttemptLimit           int                   `yaml:"attempt_limit"`
-	RequestTimeout         int                   `yaml:"request_timeout"`
-	MaxInFlight            int                   `yaml:"max_in_flight"`
-	Canaries               int                   `yaml:"canaries"`
-	CanarySelectionParams  CanarySelectionParams `yaml:"canary_selection_params"`
-	Bosh                   Bosh                  `yaml:"bosh"`
-	CF                     CF                    `yaml:"cf"`
-	MaintenanceInfoPresent bool                  `yaml:"maintenance_info_present"`
+	BrokerAPI                 BrokerAPI               `yaml:"broker_api"`
+	PollingInterval           int                     `yaml:"polling_interval"`
+	AttemptInterval           int                     `yaml:"attempt_interval"`
+	AttemptLimit              int                     `yaml:"attempt_limit"`
+	RequestTimeout            int                     `yaml:"request_timeout"`
+	MaxInFlight               int                     `yaml:"max_in_flight"`
+	Canaries                  int                     `yaml:"canaries"`
+	CanarySelectionParams     CanarySelectionParams   `yaml:"canary_selection_params"`
+	InstanceSelectionParams   InstanceSelectionParams `yaml:"instance_selection_params"`
+	Bosh                      Bosh                    `yaml:"bosh"`
+	CF                        CF                      `yaml:"cf"`
+	MaintenanceInfoPresent    bool                    `yaml:"maintenance_info_present"`
+	MaintenanceWindows        []MaintenanceWindow     `yaml:"maintenance_windows"`
+	WaitForMaintenanceWindows bool                    `yaml:"wait_for_maintenance_windows"`
+	EnableStructuredLogging   bool                    `yaml:"enable_structured_logging"`
+	SecretPaths               []string                `yaml:"secret_paths"`
+	PlanMigrations            []PlanMigration         `yaml:"plan_migrations"`
+	ServiceInstancesAPI       ServiceInstancesAPI     `yaml:"service_instances_api"`
+}
+
+// PlanMigration moves the instances of the source plan to the target plan,
+// updating them with the given arbitrary parameters.
+type PlanMigration struct {
+	SourcePlanID string                 `yaml:"source_plan_id"`
+	TargetPlanID string                 `yaml:"target_plan_id"`
+	Parameters   map[string]interface{} `yaml:"parameters"`
+}
+
+// MaintenanceWindow restricts when the instances of a plan, an org, or both
+// may be processed. Schedule is a five field cron expression for the start of
+// the window and Duration how long it stays open, such as "4h". Schedules are
+// in UTC unless a TimeZone is given.
+type MaintenanceWindow struct {
+	Plan     string            `yaml:"plan"`
+	Org      string            `yaml:"org"`
+	Labels   map[string]string `yaml:"labels"`
+	Schedule string            `yaml:"schedule"`
+	Duration string            `yaml:"duration"`
+	TimeZone string            `yaml:"time_zone"`
 }
 
 type BrokerAPI struct {
@@ -651,7 +1010,31 @@ type BindingDNSProperties struct {
 }
 
 type OrphanDeploymentsErrandConfig struct {
-	BrokerAPI BrokerAPI `yaml:"broker_api"`
+	BrokerAPI BrokerAPI     `yaml:"broker_api"`
+	Cleanup   OrphanCleanup `yaml:"cleanup"`
+}
+
+// OrphanCleanup makes the orphan-deployments errand delete orphans rather than
+// only list them. Orphans are marked when first detected and deleted once
+// GracePeriodSecs has passed, unless AllowList names their deployment or
+// instance ID. With DryRun, the errand only reports what it would do.
+// Deletions are polled every PollingInterval seconds until they complete.
+type OrphanCleanup struct {
+	Enabled         bool     `yaml:"enabled"`
+	GracePeriodSecs int      `yaml:"grace_period_in_seconds"`
+	AllowList       []string `yaml:"allow_list"`
+	DryRun          bool     `yaml:"dry_run"`
+	PollingInterval int      `yaml:"polling_interval"`
+}
+
+func (c OrphanCleanup) Validate() error {
+	if c.GracePeriodSecs <= 0 {
+		return errors.New("cleanup.grace_period_in_seconds must be greater than zero")
+	}
+	if !c.DryRun && c.PollingInterval <= 0 {
+		return errors.New("cleanup.polling_interval must be greater than zero")
+	}
+	return nil
 }
 
 type ErrandTLSConfig struct {
@@ -667,6 +1050,21 @@ type RegisterBrokerErrandConfig struct {
 	CF                CF           `yaml:"cf"`
 	ServiceOfferingID string       `yaml:"service_offering_id"`
 	Plans             []PlanAccess `yaml:"plans"`
+	// ServiceOfferings are the offerings served by the broker in addition
+	// to the one configured at the top level.
+	ServiceOfferings []RegisterBrokerOffering `yaml:"service_offerings"`
+}
+
+type RegisterBrokerOffering struct {
+	ServiceOfferingID string       `yaml:"service_offering_id"`
+	Plans             []PlanAccess `yaml:"plans"`
+}
+
+// Offerings returns the plan access of every service offering of the broker,
+// starting with the one configured at the top level.
+func (c RegisterBrokerErrandConfig) Offerings() []RegisterBrokerOffering {
+	offerings := []RegisterBrokerOffering{{ServiceOfferingID: c.ServiceOfferingID, Plans: c.Plans}}
+	return append(offerings, c.ServiceOfferings...)
 }
 
 type CFServiceAccess string
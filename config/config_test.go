@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -66,6 +67,8 @@ var _ = Describe("Broker Config", func() {
 						UsingStdin:                 true,
 						EnableTelemetry:            true,
 						SupportBackupAgentBinding:  true,
+						EnableAsyncBinding:         true,
+						MaxConcurrentAdapterCalls:  4,
 						SkipCheckForPendingChanges: false,
 					},
 					Bosh: config.Bosh{
@@ -110,6 +113,11 @@ var _ = Describe("Broker Config", func() {
 						},
 						DisableSSLCertVerification: false,
 					},
+					CredHub: config.CredHub{
+						APIURL:       "https://credhub.example.com:8844",
+						ClientID:     "credhub-client",
+						ClientSecret: "credhub-secret",
+					},
 					ServiceAdapter: config.ServiceAdapter{
 						Path: "test_assets/executable.sh",
 					},
@@ -219,6 +227,16 @@ var _ = Describe("Broker Config", func() {
 			It("knows that TLS is not configured", func() {
 				Expect(conf.HasTLS()).To(BeFalse())
 			})
+
+			It("requires credhub for async binding", func() {
+				conf.CredHub = config.CredHub{}
+				Expect(conf.Validate()).To(MatchError("broker.enable_async_binding requires credhub to be configured, as the credentials of the bindings are stored there"))
+			})
+
+			It("requires BOSH configs for async binding", func() {
+				conf.Broker.DisableBoshConfigs = true
+				Expect(conf.Validate()).To(MatchError("broker.enable_async_binding can't be true when disable_bosh_configs is true, as the bindings are recorded in BOSH configs"))
+			})
 		})
 
 		Context("and the config has skip_check_for_pending_changes enabled", func() {
@@ -358,6 +376,53 @@ var _ = Describe("Broker Config", func() {
 			})
 		})
 
+		Context("when the config has additional service offerings", func() {
+			BeforeEach(func() {
+				configFileName = "good_config_with_multiple_offerings.yml"
+			})
+
+			It("returns every offering, starting with the top level one", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+
+				offerings := conf.Offerings()
+				Expect(offerings).To(HaveLen(2))
+				Expect(offerings[0].ServiceCatalog.ID).To(Equal("some-id"))
+				Expect(offerings[0].ServiceAdapter.Path).To(Equal("test_assets/executable.sh"))
+				Expect(offerings[1].ServiceCatalog.ID).To(Equal("another-id"))
+				Expect(offerings[1].ServiceCatalog.Plans[0].ID).To(Equal("another-plan-id"))
+				Expect(offerings[1].ServiceAdapter.Path).To(Equal("test_assets/executable.sh"))
+				Expect(offerings[1].ServiceDeployment.Stemcells).To(Equal([]serviceadapter.Stemcell{{OS: "ubuntu-xenial", Version: "456"}}))
+				Expect(offerings[1].ServiceDeployment.Releases[0].Name).To(Equal("another-release"))
+			})
+
+			It("returns the catalog of every offering", func() {
+				catalogs := conf.ServiceCatalogs()
+				Expect(catalogs).To(HaveLen(2))
+				Expect(catalogs[0].Name).To(Equal("some-marketplace-name"))
+				Expect(catalogs[1].Name).To(Equal("another-marketplace-name"))
+			})
+		})
+
+		Context("when two service offerings have the same ID", func() {
+			BeforeEach(func() {
+				configFileName = "config_with_duplicate_offering_ids.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("service offering ID 'some-id' is used by more than one service offering"))
+			})
+		})
+
+		Context("when two service offerings have a plan with the same ID", func() {
+			BeforeEach(func() {
+				configFileName = "config_with_duplicate_offering_plan_ids.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("plan ID 'some-dedicated-plan-id' is used by more than one service offering"))
+			})
+		})
+
 		Context("when the BOSH director uses UAA", func() {
 			BeforeEach(func() {
 				configFileName = "bosh_uaa_config.yml"
@@ -914,6 +979,53 @@ var _ = Describe("ServiceOffering", func() {
 			Expect(isConfigured).To(BeFalse(), "Expected to return false because the only plan has 'binding_with_dns' configured to be empty")
 		})
 	})
+
+	Context("org and space quotas", func() {
+		var (
+			offering            config.ServiceOffering
+			defaultLimit, limit int
+		)
+
+		BeforeEach(func() {
+			defaultLimit, limit = 2, 10
+			offering = config.ServiceOffering{
+				Plans: []config.Plan{{ID: "planId", Name: "planName"}},
+				GlobalQuotas: config.Quotas{
+					Orgs: &config.ScopedQuotas{
+						Default:   config.Quotas{ServiceInstanceLimit: &defaultLimit},
+						Overrides: map[string]config.Quotas{"big-org": {ServiceInstanceLimit: &limit}},
+					},
+					Spaces: &config.ScopedQuotas{
+						Default: config.Quotas{Resources: map[string]config.ResourceQuota{"memory": {Limit: 8}}},
+					},
+				},
+			}
+		})
+
+		It("returns the override of an org, or the default", func() {
+			Expect(offering.GlobalQuotas.Orgs.For("big-org").ServiceInstanceLimit).To(Equal(&limit))
+			Expect(offering.GlobalQuotas.Orgs.For("small-org").ServiceInstanceLimit).To(Equal(&defaultLimit))
+		})
+
+		It("validates", func() {
+			Expect(offering.Validate()).To(Succeed())
+		})
+
+		It("fails when a plan has org quotas", func() {
+			offering.Plans[0].Quotas.Orgs = &config.ScopedQuotas{}
+			Expect(offering.Validate()).To(MatchError("plan planName can't have org or space quotas, they can only be set in global_quotas"))
+		})
+
+		It("fails when the quotas of an org have space quotas", func() {
+			offering.GlobalQuotas.Orgs.Overrides["big-org"] = config.Quotas{Spaces: &config.ScopedQuotas{}}
+			Expect(offering.Validate()).To(MatchError("global_quotas.orgs.overrides.big-org can't have org or space quotas"))
+		})
+
+		It("fails when the default quotas of a space have org quotas", func() {
+			offering.GlobalQuotas.Spaces.Default.Orgs = &config.ScopedQuotas{}
+			Expect(offering.Validate()).To(MatchError("global_quotas.spaces.default can't have org or space quotas"))
+		})
+	})
 })
 
 var _ = Describe("CF#NewAuthHeaderBuilder", func() {
@@ -1175,6 +1287,98 @@ var _ = Describe("Config validation", func() {
 		Entry("fails when client_id is empty", clientCredsAuthBlock("", "secret"), errors.New("client_id can't be empty")),
 		Entry("fails when client_secret is empty", clientCredsAuthBlock("id", ""), errors.New("client_secret can't be empty")),
 	)
+
+	DescribeTable("Service Adapter",
+		func(serviceAdapter config.ServiceAdapter, expectedErr error) {
+			err := serviceAdapter.Validate()
+			if expectedErr != nil {
+				Expect(err).To(MatchError(expectedErr.Error()))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("fails when the adapter to execute is not executable", config.ServiceAdapter{Path: "test_assets/good_config.yml"}, errors.New("checking for executable service adapter file: 'test_assets/good_config.yml' is not executable")),
+		Entry("succeeds for an adapter server with a socket", config.ServiceAdapter{Transport: "server", Socket: "/var/vcap/sys/run/adapter.sock"}, nil),
+		Entry("fails for an adapter server without a socket", config.ServiceAdapter{Transport: "server"}, errors.New("service_adapter.socket can't be empty when the transport is server")),
+		Entry("fails for an unknown transport", config.ServiceAdapter{Transport: "grpc"}, errors.New(`service_adapter.transport must be one of exec or server, got "grpc"`)),
+		Entry("succeeds with subcommand timeouts", config.ServiceAdapter{Transport: "server", Socket: "/adapter.sock", TimeoutsSecs: map[string]int{"generate-manifest": 60, "create-binding": 10}}, nil),
+		Entry("fails for a timeout of an unknown subcommand", config.ServiceAdapter{Transport: "server", Socket: "/adapter.sock", TimeoutsSecs: map[string]int{"launch-rockets": 60}}, errors.New(`service_adapter.timeouts_in_seconds: unknown subcommand "launch-rockets", must be one of generate-manifest, create-binding, delete-binding, dashboard-url, generate-plan-schemas`)),
+		Entry("fails for a timeout that is not positive", config.ServiceAdapter{Transport: "server", Socket: "/adapter.sock", TimeoutsSecs: map[string]int{"dashboard-url": 0}}, errors.New("service_adapter.timeouts_in_seconds: timeout for dashboard-url must be greater than 0")),
+	)
+
+	It("converts the service adapter timeouts to durations", func() {
+		serviceAdapter := config.ServiceAdapter{TimeoutsSecs: map[string]int{"generate-manifest": 90}}
+		Expect(serviceAdapter.Timeouts()).To(Equal(map[string]time.Duration{"generate-manifest": 90 * time.Second}))
+	})
+
+	DescribeTable("Tracing",
+		func(tracing config.Tracing, expectedErr error) {
+			err := tracing.Validate()
+			if expectedErr != nil {
+				Expect(err).To(MatchError(expectedErr.Error()))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("succeeds when tracing is disabled", config.Tracing{}, nil),
+		Entry("succeeds for the stdout exporter", config.Tracing{Exporter: "stdout"}, nil),
+		Entry("succeeds for the otlp exporter with an endpoint", config.Tracing{Exporter: "otlp", OTLPEndpoint: "http://collector:4318"}, nil),
+		Entry("fails for the otlp exporter without an endpoint", config.Tracing{Exporter: "otlp"}, errors.New("broker.tracing.otlp_endpoint can't be empty when the exporter is otlp")),
+		Entry("fails for the file exporter without a file", config.Tracing{Exporter: "file"}, errors.New("broker.tracing.file can't be empty when the exporter is file")),
+		Entry("fails for an unknown exporter", config.Tracing{Exporter: "zipkin"}, errors.New(`broker.tracing.exporter must be one of otlp, stdout or file, got "zipkin"`)),
+	)
+
+	DescribeTable("Notifications",
+		func(notifications config.Notifications, expectedErr error) {
+			err := notifications.Validate()
+			if expectedErr != nil {
+				Expect(err).To(MatchError(expectedErr.Error()))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("succeeds when there are no webhooks", config.Notifications{}, nil),
+		Entry("succeeds for webhooks with events", config.Notifications{Webhooks: []config.Webhook{{URL: "https://billing.example.com/events", Secret: "s3cr3t", Events: []string{"instance.create", "binding"}}}}, nil),
+		Entry("fails for a negative queue size", config.Notifications{QueueSize: -1}, errors.New("broker.notifications.queue_size can't be negative")),
+		Entry("fails for a negative timeout", config.Notifications{TimeoutSecs: -1}, errors.New("broker.notifications.timeout_in_seconds can't be negative")),
+		Entry("fails for negative retries", config.Notifications{MaxRetries: func() *int { retries := -1; return &retries }()}, errors.New("broker.notifications.max_retries can't be negative")),
+		Entry("fails for a webhook without a url", config.Notifications{Webhooks: []config.Webhook{{}}}, errors.New("broker.notifications.webhooks url can't be empty")),
+		Entry("fails for a webhook url that is not http", config.Notifications{Webhooks: []config.Webhook{{URL: "ftp://example.com"}}}, errors.New(`broker.notifications.webhooks url "ftp://example.com" must be an http or https URL`)),
+		Entry("fails for an unknown event", config.Notifications{Webhooks: []config.Webhook{{URL: "https://example.com", Events: []string{"plan.create"}}}}, errors.New(`broker.notifications.webhooks event "plan.create" must start with instance or binding`)),
+	)
+
+	DescribeTable("OrphanCleanup",
+		func(cleanup config.OrphanCleanup, expectedErr error) {
+			err := cleanup.Validate()
+			if expectedErr != nil {
+				Expect(err).To(MatchError(expectedErr.Error()))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("succeeds with a grace period and polling interval", config.OrphanCleanup{GracePeriodSecs: 3600, PollingInterval: 10}, nil),
+		Entry("succeeds for a dry run without a polling interval", config.OrphanCleanup{GracePeriodSecs: 3600, DryRun: true}, nil),
+		Entry("fails without a grace period", config.OrphanCleanup{PollingInterval: 10}, errors.New("cleanup.grace_period_in_seconds must be greater than zero")),
+		Entry("fails for a negative grace period", config.OrphanCleanup{GracePeriodSecs: -1, PollingInterval: 10}, errors.New("cleanup.grace_period_in_seconds must be greater than zero")),
+		Entry("fails without a polling interval", config.OrphanCleanup{GracePeriodSecs: 3600}, errors.New("cleanup.polling_interval must be greater than zero")),
+	)
+
+	DescribeTable("Instance counter",
+		func(b config.Broker, expectedErr error) {
+			b.Port, b.Username, b.Password = 8080, "username", "password"
+			err := b.Validate()
+			if expectedErr != nil {
+				Expect(err).To(MatchError(expectedErr.Error()))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("succeeds when the counter is not set", config.Broker{}, nil),
+		Entry("succeeds for the cf counter", config.Broker{InstanceCounter: "cf"}, nil),
+		Entry("succeeds for the bosh counter", config.Broker{InstanceCounter: "bosh"}, nil),
+		Entry("fails for the bosh counter without BOSH configs", config.Broker{InstanceCounter: "bosh", DisableBoshConfigs: true}, errors.New("broker.instance_counter can't be bosh when disable_bosh_configs is true, as the plans of the instances are stored in BOSH configs")),
+		Entry("fails for an unknown counter", config.Broker{InstanceCounter: "k8s"}, errors.New(`broker.instance_counter must be one of cf or bosh, got "k8s"`)),
+	)
 })
 
 func authBlock(basic config.UserCredentials, uaa config.UAAAuthentication) config.Authentication {
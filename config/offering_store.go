@@ -0,0 +1,71 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// OfferingStore holds the configuration of the service offerings of a running
+// broker. The components that serve an offering share it, so that a reloaded
+// configuration replaces the catalog and deployment of every offering at once.
+type OfferingStore struct {
+	offerings atomic.Pointer[[]OfferingConfig]
+}
+
+func NewOfferingStore(offerings []OfferingConfig) *OfferingStore {
+	s := &OfferingStore{}
+	s.SetOfferings(offerings)
+	return s
+}
+
+func (s *OfferingStore) Offerings() []OfferingConfig {
+	return *s.offerings.Load()
+}
+
+// Offering returns the configuration of the offering with the given service
+// ID, or nil when there is none. It is shared by every reader of the same
+// configuration and must not be modified.
+func (s *OfferingStore) Offering(serviceID string) *OfferingConfig {
+	offerings := *s.offerings.Load()
+	for i := range offerings {
+		if offerings[i].ServiceCatalog.ID == serviceID {
+			return &offerings[i]
+		}
+	}
+	return nil
+}
+
+func (s *OfferingStore) ServiceCatalogs() []ServiceOffering {
+	var catalogs []ServiceOffering
+	for _, offering := range s.Offerings() {
+		catalogs = append(catalogs, offering.ServiceCatalog)
+	}
+	return catalogs
+}
+
+func (s *OfferingStore) SetOfferings(offerings []OfferingConfig) {
+	s.offerings.Store(&offerings)
+}
+
+// CheckReloadable returns an error when the service offerings of newConfig
+// differ from those of c in a way that needs a restart. Offerings can't be
+// added, removed or renamed, as the routes, secrets and metrics of the broker
+// are keyed on them.
+func (c Config) CheckReloadable(newConfig Config) error {
+	current, reloaded := c.ServiceCatalogs(), newConfig.ServiceCatalogs()
+	if len(current) != len(reloaded) {
+		return fmt.Errorf("service offerings cannot be added or removed without a restart")
+	}
+	for i := range current {
+		if current[i].ID != reloaded[i].ID || current[i].Name != reloaded[i].Name {
+			return fmt.Errorf("service offering %s cannot change its ID or name without a restart", current[i].Name)
+		}
+	}
+	return nil
+}
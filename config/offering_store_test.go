@@ -0,0 +1,63 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+var _ = Describe("OfferingStore", func() {
+	It("returns the offerings it was last given", func() {
+		store := config.NewOfferingStore([]config.OfferingConfig{{ServiceCatalog: config.ServiceOffering{ID: "redis-id"}}})
+		Expect(store.ServiceCatalogs()).To(Equal([]config.ServiceOffering{{ID: "redis-id"}}))
+
+		store.SetOfferings([]config.OfferingConfig{{ServiceCatalog: config.ServiceOffering{ID: "redis-id", Description: "reloaded"}}})
+		Expect(store.ServiceCatalogs()).To(Equal([]config.ServiceOffering{{ID: "redis-id", Description: "reloaded"}}))
+	})
+
+	It("finds an offering by its service ID", func() {
+		store := config.NewOfferingStore([]config.OfferingConfig{
+			{ServiceCatalog: config.ServiceOffering{ID: "redis-id"}},
+			{ServiceCatalog: config.ServiceOffering{ID: "rabbit-id", Name: "rabbit"}},
+		})
+
+		Expect(store.Offering("rabbit-id").ServiceCatalog.Name).To(Equal("rabbit"))
+		Expect(store.Offering("rabbit-id")).To(BeIdenticalTo(store.Offering("rabbit-id")))
+		Expect(store.Offering("kafka-id")).To(BeNil())
+	})
+})
+
+var _ = Describe("CheckReloadable", func() {
+	var current, reloaded config.Config
+
+	BeforeEach(func() {
+		current = config.Config{
+			ServiceCatalog:   config.ServiceOffering{ID: "redis-id", Name: "redis"},
+			ServiceOfferings: []config.OfferingConfig{{ServiceCatalog: config.ServiceOffering{ID: "rabbit-id", Name: "rabbit"}}},
+		}
+		reloaded = current
+		reloaded.ServiceOfferings = []config.OfferingConfig{{ServiceCatalog: config.ServiceOffering{ID: "rabbit-id", Name: "rabbit"}}}
+	})
+
+	It("accepts changes to the plans of an offering", func() {
+		reloaded.ServiceCatalog.Plans = config.Plans{{ID: "new-plan-id"}}
+		Expect(current.CheckReloadable(reloaded)).To(Succeed())
+	})
+
+	It("rejects an added offering", func() {
+		reloaded.ServiceOfferings = append(reloaded.ServiceOfferings, config.OfferingConfig{ServiceCatalog: config.ServiceOffering{ID: "kafka-id"}})
+		Expect(current.CheckReloadable(reloaded)).To(MatchError("service offerings cannot be added or removed without a restart"))
+	})
+
+	It("rejects a renamed offering", func() {
+		reloaded.ServiceOfferings[0].ServiceCatalog.Name = "rabbitmq"
+		Expect(current.CheckReloadable(reloaded)).To(MatchError("service offering rabbit cannot change its ID or name without a restart"))
+	})
+})
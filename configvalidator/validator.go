@@ -0,0 +1,225 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package configvalidator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"gopkg.in/yaml.v2"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+// SampleDeploymentName is the deployment that manifests are generated for when
+// probing the service adapter. Nothing is deployed.
+const SampleDeploymentName = broker.InstancePrefix + "validate-config"
+
+type CheckStatus string
+
+const (
+	CheckPassed  = CheckStatus("passed")
+	CheckFailed  = CheckStatus("failed")
+	CheckSkipped = CheckStatus("skipped")
+)
+
+type Check struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message,omitempty"`
+}
+
+// Report is the outcome of validating a broker config. It is valid when none
+// of its checks failed.
+type Report struct {
+	Valid     bool             `json:"valid"`
+	Checks    []Check          `json:"checks"`
+	Offerings []OfferingReport `json:"service_offerings"`
+}
+
+type OfferingReport struct {
+	ServiceOfferingID string       `json:"service_offering_id"`
+	ServiceName       string       `json:"service_name"`
+	Plans             []PlanReport `json:"plans"`
+}
+
+type PlanReport struct {
+	PlanID   string  `json:"plan_id"`
+	PlanName string  `json:"plan_name"`
+	Checks   []Check `json:"checks"`
+}
+
+// AdapterClientFactory returns the client of the service adapter of an
+// offering.
+type AdapterClientFactory func(adapter config.ServiceAdapter) task.ServiceAdapterClient
+
+// Validator validates a broker config without a BOSH director or Cloud
+// Foundry. Besides Config.Validate, it runs the plan checks the broker only
+// runs when it builds its catalog or deploys an instance, calling the service
+// adapter of each offering to generate the plan schemas and a manifest for a
+// sample deployment.
+type Validator struct {
+	adapterClientFor AdapterClientFactory
+	logger           *log.Logger
+}
+
+func New(adapterClientFor AdapterClientFactory, logger *log.Logger) *Validator {
+	return &Validator{adapterClientFor: adapterClientFor, logger: logger}
+}
+
+func (v *Validator) Validate(conf config.Config) Report {
+	report := Report{
+		Checks:    []Check{checkResult("config", conf.Validate())},
+		Offerings: []OfferingReport{},
+	}
+
+	for _, offering := range conf.Offerings() {
+		adapterClient := v.adapterClientFor(offering.ServiceAdapter)
+		manifestGenerator := task.NewManifestGenerator(
+			adapterClient,
+			offering.ServiceCatalog,
+			offering.ServiceDeployment.Stemcells,
+			offering.ServiceDeployment.Releases,
+		)
+
+		offeringReport := OfferingReport{
+			ServiceOfferingID: offering.ServiceCatalog.ID,
+			ServiceName:       offering.ServiceCatalog.Name,
+			Plans:             []PlanReport{},
+		}
+		for _, plan := range offering.ServiceCatalog.Plans {
+			v.logger.Printf("validating plan %s of service offering %s\n", plan.Name, offering.ServiceCatalog.Name)
+			offeringReport.Plans = append(offeringReport.Plans, PlanReport{
+				PlanID:   plan.ID,
+				PlanName: plan.Name,
+				Checks: []Check{
+					checkResult("maintenance_info", checkMaintenanceInfo(offering.ServiceCatalog, plan)),
+					checkResult("quotas", checkQuotas(offering.ServiceCatalog, plan)),
+					v.checkPlanSchemas(conf.Broker.EnablePlanSchemas, adapterClient, offering.ServiceCatalog, plan),
+					checkResult("generate_manifest", v.checkManifest(manifestGenerator, offering.ServiceCatalog, plan)),
+				},
+			})
+		}
+		report.Offerings = append(report.Offerings, offeringReport)
+	}
+
+	report.Valid = !report.failed()
+	return report
+}
+
+func (r Report) failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == CheckFailed {
+			return true
+		}
+	}
+	for _, offering := range r.Offerings {
+		for _, plan := range offering.Plans {
+			for _, check := range plan.Checks {
+				if check.Status == CheckFailed {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func checkResult(name string, err error) Check {
+	if err != nil {
+		return Check{Name: name, Status: CheckFailed, Message: err.Error()}
+	}
+	return Check{Name: name, Status: CheckPassed}
+}
+
+// checkMaintenanceInfo merges the maintenance_info of the plan as the catalog
+// does. Cloud Foundry requires the version to be semver.
+func checkMaintenanceInfo(serviceOffering config.ServiceOffering, plan config.Plan) error {
+	maintenanceInfo := broker.PlanMaintenanceInfo(serviceOffering, plan)
+	if maintenanceInfo == nil || maintenanceInfo.Version == "" {
+		return nil
+	}
+
+	if _, err := semver.Parse(maintenanceInfo.Version); err != nil {
+		return fmt.Errorf("maintenance_info version %q is not valid semver: %s", maintenanceInfo.Version, err)
+	}
+	return nil
+}
+
+// checkQuotas finds plan quotas that can never be reached, or that can never
+// be satisfied, because of the other quotas that apply to the plan.
+func checkQuotas(serviceOffering config.ServiceOffering, plan config.Plan) error {
+	globalQuotas := serviceOffering.GlobalQuotas
+
+	if planLimit, globalLimit := plan.Quotas.ServiceInstanceLimit, globalQuotas.ServiceInstanceLimit; planLimit != nil && globalLimit != nil && *planLimit > *globalLimit {
+		return fmt.Errorf("service_instance_limit %d exceeds the global service_instance_limit %d", *planLimit, *globalLimit)
+	}
+
+	var kinds []string
+	for kind := range plan.Quotas.Resources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		quota := plan.Quotas.Resources[kind]
+		if quota.Limit > 0 && quota.Cost > quota.Limit {
+			return fmt.Errorf("%s cost %d exceeds the plan limit %d", kind, quota.Cost, quota.Limit)
+		}
+		if globalQuota, found := globalQuotas.Resources[kind]; found && quota.Cost > globalQuota.Limit {
+			return fmt.Errorf("%s cost %d exceeds the global limit %d", kind, quota.Cost, globalQuota.Limit)
+		}
+	}
+	return nil
+}
+
+func (v *Validator) checkPlanSchemas(enabled bool, adapterClient task.ServiceAdapterClient, serviceOffering config.ServiceOffering, plan config.Plan) Check {
+	const name = "plan_schemas"
+	if !enabled {
+		return Check{Name: name, Status: CheckSkipped, Message: "enable_plan_schemas is false"}
+	}
+
+	planSchema, err := adapterClient.GeneratePlanSchema(context.Background(), plan.AdapterPlan(serviceOffering.GlobalProperties), v.logger)
+	if err != nil {
+		if _, ok := err.(serviceadapter.NotImplementedError); ok {
+			return checkResult(name, fmt.Errorf("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas"))
+		}
+		return checkResult(name, err)
+	}
+
+	return checkResult(name, broker.ValidatePlanSchemas(planSchema))
+}
+
+func (v *Validator) checkManifest(manifestGenerator task.ManifestGenerator, serviceOffering config.ServiceOffering, plan config.Plan) error {
+	generated, err := manifestGenerator.GenerateManifest(context.Background(), task.GenerateManifestProperties{
+		DeploymentName: SampleDeploymentName,
+		PlanID:         plan.ID,
+		RequestParams: map[string]interface{}{
+			"service_id": serviceOffering.ID,
+			"plan_id":    plan.ID,
+			"parameters": map[string]interface{}{},
+		},
+	}, v.logger)
+	if err != nil {
+		return err
+	}
+
+	var manifest map[string]interface{}
+	if err := yaml.Unmarshal([]byte(generated.Manifest), &manifest); err != nil {
+		return fmt.Errorf("generated manifest is not valid YAML: %s", err)
+	}
+	if len(manifest) == 0 {
+		return fmt.Errorf("generated manifest is empty")
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package configvalidator_test
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/configvalidator"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+	"github.com/pivotal-cf/on-demand-service-broker/task/fakes"
+)
+
+var _ = Describe("Validator", func() {
+	var (
+		conf          config.Config
+		adapterClient *fakes.FakeServiceAdapterClient
+		adapterPaths  []string
+		report        configvalidator.Report
+	)
+
+	planChecks := func() map[string]configvalidator.Check {
+		checks := map[string]configvalidator.Check{}
+		for _, check := range report.Offerings[0].Plans[0].Checks {
+			checks[check.Name] = check
+		}
+		return checks
+	}
+
+	BeforeEach(func() {
+		adapterPath := filepath.Join(GinkgoT().TempDir(), "service-adapter")
+		Expect(os.WriteFile(adapterPath, []byte("#!/bin/sh\n"), 0755)).To(Succeed())
+
+		instanceLimit := 5
+		conf = config.Config{
+			Broker: config.Broker{Port: 8080, Username: "username", Password: "password", DisableCFStartupChecks: true, EnablePlanSchemas: true},
+			Bosh: config.Bosh{
+				URL:            "https://bosh.example.com",
+				Authentication: config.Authentication{Basic: config.UserCredentials{Username: "admin", Password: "secret"}},
+			},
+			ServiceAdapter: config.ServiceAdapter{Path: adapterPath},
+			ServiceDeployment: config.ServiceDeployment{
+				Releases:  sdk.ServiceReleases{{Name: "redis", Version: "1.0", Jobs: []string{"redis-server"}}},
+				Stemcells: []sdk.Stemcell{{OS: "ubuntu-jammy", Version: "1.1"}},
+			},
+			ServiceCatalog: config.ServiceOffering{
+				ID:              "redis-id",
+				Name:            "redis",
+				GlobalQuotas:    config.Quotas{ServiceInstanceLimit: &instanceLimit},
+				MaintenanceInfo: &config.MaintenanceInfo{Version: "1.2.3"},
+				Plans:           config.Plans{{ID: "small-id", Name: "small"}},
+			},
+		}
+
+		schema := domain.Schema{Parameters: map[string]interface{}{
+			"$schema": "http://json-schema.org/draft-04/schema#",
+			"type":    "object",
+		}}
+		adapterClient = new(fakes.FakeServiceAdapterClient)
+		adapterClient.GeneratePlanSchemaReturns(domain.ServiceSchemas{
+			Instance: domain.ServiceInstanceSchema{Create: schema, Update: schema},
+			Binding:  domain.ServiceBindingSchema{Create: schema},
+		}, nil)
+		adapterClient.GenerateManifestReturns(sdk.MarshalledGenerateManifest{Manifest: "name: " + configvalidator.SampleDeploymentName}, nil)
+		adapterPaths = nil
+	})
+
+	JustBeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		report = configvalidator.New(func(adapter config.ServiceAdapter) task.ServiceAdapterClient {
+			adapterPaths = append(adapterPaths, adapter.Path)
+			return adapterClient
+		}, logger).Validate(conf)
+	})
+
+	It("passes a valid config", func() {
+		Expect(report.Valid).To(BeTrue(), "%+v", report)
+		Expect(report.Checks).To(ConsistOf(configvalidator.Check{Name: "config", Status: configvalidator.CheckPassed}))
+		Expect(report.Offerings).To(HaveLen(1))
+		Expect(report.Offerings[0].ServiceOfferingID).To(Equal("redis-id"))
+		Expect(report.Offerings[0].Plans[0].PlanID).To(Equal("small-id"))
+		for _, check := range planChecks() {
+			Expect(check.Status).To(Equal(configvalidator.CheckPassed), check.Name)
+		}
+		Expect(adapterPaths).To(Equal([]string{conf.ServiceAdapter.Path}))
+	})
+
+	It("generates a manifest for a sample deployment of each plan", func() {
+		Expect(adapterClient.GenerateManifestCallCount()).To(Equal(1))
+		_, serviceDeployment, _, requestParams, previousManifest, _, _, _, _, _ := adapterClient.GenerateManifestArgsForCall(0)
+		Expect(serviceDeployment.DeploymentName).To(Equal(configvalidator.SampleDeploymentName))
+		Expect(serviceDeployment.Releases).To(Equal(conf.ServiceDeployment.Releases))
+		Expect(requestParams).To(HaveKeyWithValue("plan_id", "small-id"))
+		Expect(previousManifest).To(BeEmpty())
+	})
+
+	When("Config.Validate fails", func() {
+		BeforeEach(func() {
+			conf.Broker.Port = 0
+		})
+
+		It("reports it and still checks the plans", func() {
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks).To(ConsistOf(configvalidator.Check{Name: "config", Status: configvalidator.CheckFailed, Message: "broker.port can't be empty"}))
+			Expect(report.Offerings[0].Plans).To(HaveLen(1))
+		})
+	})
+
+	When("the merged maintenance_info version is not semver", func() {
+		BeforeEach(func() {
+			conf.ServiceCatalog.Plans[0].MaintenanceInfo = &config.MaintenanceInfo{Version: "latest"}
+		})
+
+		It("fails the plan", func() {
+			Expect(report.Valid).To(BeFalse())
+			Expect(planChecks()["maintenance_info"].Status).To(Equal(configvalidator.CheckFailed))
+			Expect(planChecks()["maintenance_info"].Message).To(ContainSubstring(`"latest" is not valid semver`))
+		})
+	})
+
+	When("a plan quota exceeds the global quota", func() {
+		BeforeEach(func() {
+			planLimit := 10
+			conf.ServiceCatalog.Plans[0].Quotas.ServiceInstanceLimit = &planLimit
+		})
+
+		It("fails the plan", func() {
+			Expect(report.Valid).To(BeFalse())
+			Expect(planChecks()["quotas"]).To(Equal(configvalidator.Check{
+				Name:    "quotas",
+				Status:  configvalidator.CheckFailed,
+				Message: "service_instance_limit 10 exceeds the global service_instance_limit 5",
+			}))
+		})
+	})
+
+	When("a plan costs more of a resource than the global limit", func() {
+		BeforeEach(func() {
+			conf.ServiceCatalog.GlobalQuotas.Resources = map[string]config.ResourceQuota{"memory": {Limit: 4}}
+			conf.ServiceCatalog.Plans[0].Quotas.Resources = map[string]config.ResourceQuota{"memory": {Cost: 8}}
+		})
+
+		It("fails the plan", func() {
+			Expect(planChecks()["quotas"].Message).To(Equal("memory cost 8 exceeds the global limit 4"))
+		})
+	})
+
+	When("plan schemas are disabled", func() {
+		BeforeEach(func() {
+			conf.Broker.EnablePlanSchemas = false
+		})
+
+		It("skips generating them", func() {
+			Expect(report.Valid).To(BeTrue())
+			Expect(planChecks()["plan_schemas"].Status).To(Equal(configvalidator.CheckSkipped))
+			Expect(adapterClient.GeneratePlanSchemaCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the adapter does not implement generate-plan-schemas", func() {
+		BeforeEach(func() {
+			adapterClient.GeneratePlanSchemaReturns(domain.ServiceSchemas{}, serviceadapter.NewNotImplementedError("not implemented"))
+		})
+
+		It("fails the plan", func() {
+			Expect(report.Valid).To(BeFalse())
+			Expect(planChecks()["plan_schemas"].Message).To(Equal("enable_plan_schemas is set to true, but the service adapter does not implement generate-plan-schemas"))
+		})
+	})
+
+	When("the adapter generates an invalid plan schema", func() {
+		BeforeEach(func() {
+			adapterClient.GeneratePlanSchemaReturns(domain.ServiceSchemas{
+				Instance: domain.ServiceInstanceSchema{
+					Create: domain.Schema{Parameters: map[string]interface{}{"$schema": "http://json-schema.org/draft-04/schema#", "type": "not-a-type"}},
+				},
+			}, nil)
+		})
+
+		It("fails the plan", func() {
+			Expect(report.Valid).To(BeFalse())
+			Expect(planChecks()["plan_schemas"].Message).To(ContainSubstring("Error validating plan schemas for instance create"))
+		})
+	})
+
+	When("the adapter fails to generate a manifest", func() {
+		BeforeEach(func() {
+			adapterClient.GenerateManifestReturns(sdk.MarshalledGenerateManifest{}, errors.New("missing property"))
+		})
+
+		It("fails the plan", func() {
+			Expect(report.Valid).To(BeFalse())
+			Expect(planChecks()["generate_manifest"]).To(Equal(configvalidator.Check{
+				Name:    "generate_manifest",
+				Status:  configvalidator.CheckFailed,
+				Message: "missing property",
+			}))
+		})
+	})
+})
@@ -16,6 +16,7 @@
 package bosh_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -48,13 +49,13 @@ var _ = Describe("BOSH client", func() {
 
 	AfterEach(func() {
 		reporter := boshdirector.NewAsyncTaskReporter()
-		_, err := boshClient.DeleteDeployment(deploymentName, "", false, reporter, logger)
+		_, err := boshClient.DeleteDeployment(context.Background(), deploymentName, "", false, reporter, logger)
 		Expect(err).NotTo(HaveOccurred())
 		Eventually(reporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for deployment %s to be deleted", deploymentName))
 	})
 
 	verifyContextID := func(expectedContextID string, taskID int) {
-		task, err := boshClient.GetTask(taskID, logger)
+		task, err := boshClient.GetTask(context.Background(), taskID, logger)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(task.ContextID).To(Equal(expectedContextID))
 	}
@@ -71,14 +72,14 @@ var _ = Describe("BOSH client", func() {
 			)
 
 			By("verifying no tasks in progress", func() {
-				tasks, err := boshClient.GetTasksInProgress(deploymentName, logger)
+				tasks, err := boshClient.GetTasksInProgress(context.Background(), deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(tasks).To(BeEmpty())
 			})
 
 			By("deploying the manifest", func() {
 				var err error
-				taskID, err = boshClient.Deploy(manifest, "some-context-id", logger, reporter)
+				taskID, err = boshClient.Deploy(context.Background(), manifest, "some-context-id", logger, reporter)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(taskID).To(BeNumerically(">=", 1))
 				verifyContextID("some-context-id", taskID)
@@ -87,13 +88,13 @@ var _ = Describe("BOSH client", func() {
 			})
 
 			By("checking all the deployed vms", func() {
-				deployments, getDeploymentErr := boshClient.GetDeployments(logger)
+				deployments, getDeploymentErr := boshClient.GetDeployments(context.Background(), logger)
 				Expect(getDeploymentErr).NotTo(HaveOccurred())
 				Expect(deployments).To(ContainElement(boshdirector.Deployment{Name: deploymentName}))
 			})
 
 			By("checking that the deployment is there", func() {
-				returnedManifest, found, getDeploymentErr := boshClient.GetDeployment(deploymentName, logger)
+				returnedManifest, found, getDeploymentErr := boshClient.GetDeployment(context.Background(), deploymentName, logger)
 				Expect(found).To(BeTrue())
 				Expect(getDeploymentErr).NotTo(HaveOccurred())
 				Expect(returnedManifest).To(MatchYAML(manifest))
@@ -101,19 +102,19 @@ var _ = Describe("BOSH client", func() {
 
 			By("verifying a single task", func() {
 				var err error
-				task, err = boshClient.GetTask(taskID, logger)
+				task, err = boshClient.GetTask(context.Background(), taskID, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(task.ID).To(Equal(taskID))
 			})
 
 			By("pulling VM information", func() {
-				vms, err := boshClient.VMs(deploymentName, logger)
+				vms, err := boshClient.VMs(context.Background(), deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(vms["dummy"]).NotTo(BeEmpty())
 			})
 
 			By("pulling the bosh variables", func() {
-				variables, err := boshClient.Variables(deploymentName, logger)
+				variables, err := boshClient.Variables(context.Background(), deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 
 				odbVar1Matcher, err := regexp.Compile("a-var$")
@@ -138,14 +139,14 @@ var _ = Describe("BOSH client", func() {
 			By("running an errand", func() {
 				var err error
 				errandReporter := boshdirector.NewAsyncTaskReporter()
-				errandTaskID, err = boshClient.RunErrand(deploymentName, "dummy_errand", nil, "some-context-id", logger, errandReporter)
+				errandTaskID, err = boshClient.RunErrand(context.Background(), deploymentName, "dummy_errand", nil, "some-context-id", logger, errandReporter)
 				Expect(err).NotTo(HaveOccurred())
 				Eventually(errandReporter.Finished).Should(Receive(), "timed out waiting for errand")
 				verifyContextID("some-context-id", errandTaskID)
 			})
 
 			By("getting the task output", func() {
-				output, err := boshClient.GetTaskOutput(errandTaskID, logger)
+				output, err := boshClient.GetTaskOutput(context.Background(), errandTaskID, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(output.StdOut).To(Equal("running dummy errand\n"))
 			})
@@ -155,7 +156,7 @@ var _ = Describe("BOSH client", func() {
 
 				recreateCtx := "recreate-context-1"
 				recreateReporter := boshdirector.NewAsyncTaskReporter()
-				recreateTaskID, err = boshClient.Recreate(deploymentName, recreateCtx, logger, recreateReporter)
+				recreateTaskID, err = boshClient.Recreate(context.Background(), deploymentName, recreateCtx, logger, recreateReporter)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(recreateTaskID).To(BeNumerically(">", 0))
 				verifyContextID(recreateCtx, recreateTaskID)
@@ -165,14 +166,14 @@ var _ = Describe("BOSH client", func() {
 
 			By("deleting the deployment", func() {
 				deleteDeploymentReporter := boshdirector.NewAsyncTaskReporter()
-				deleteTaskID, err := boshClient.DeleteDeployment(deploymentName, "some-context-id", false, deleteDeploymentReporter, logger)
+				deleteTaskID, err := boshClient.DeleteDeployment(context.Background(), deploymentName, "some-context-id", false, deleteDeploymentReporter, logger)
 				Expect(deleteTaskID).To(BeNumerically(">=", 1))
 				Expect(err).NotTo(HaveOccurred())
 				verifyContextID("some-context-id", deleteTaskID)
 				Eventually(deleteDeploymentReporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for deployment %s to be deleted", deploymentName))
 
 				By("verifying the deployment was deleted")
-				deployments, getDeploymentErr := boshClient.GetDeployments(logger)
+				deployments, getDeploymentErr := boshClient.GetDeployments(context.Background(), logger)
 				Expect(getDeploymentErr).NotTo(HaveOccurred())
 				Expect(deployments).NotTo(ContainElement(boshdirector.Deployment{Name: deploymentName}))
 			})
@@ -186,7 +187,7 @@ var _ = Describe("BOSH client", func() {
 
 			By("deploying the manifest", func() {
 				var err error
-				_, err = boshClient.Deploy(manifest, "some-context-id", logger, reporter)
+				_, err = boshClient.Deploy(context.Background(), manifest, "some-context-id", logger, reporter)
 				Expect(err).NotTo(HaveOccurred())
 				Eventually(reporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for %s to deploy", deploymentName))
 				Expect(reporter.State).ToNot(Equal("error"), fmt.Sprintf("Deployment of %s failed", deploymentName))
@@ -194,14 +195,14 @@ var _ = Describe("BOSH client", func() {
 
 			By("force deleting the deployment", func() {
 				deleteDeploymentReporter := boshdirector.NewAsyncTaskReporter()
-				deleteTaskID, err := boshClient.DeleteDeployment(deploymentName, "some-context-id", true, deleteDeploymentReporter, logger)
+				deleteTaskID, err := boshClient.DeleteDeployment(context.Background(), deploymentName, "some-context-id", true, deleteDeploymentReporter, logger)
 				Expect(deleteTaskID).To(BeNumerically(">=", 1))
 				Expect(err).NotTo(HaveOccurred())
 				verifyContextID("some-context-id", deleteTaskID)
 				Eventually(deleteDeploymentReporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for deployment %s to be deleted", deploymentName))
 
 				By("verifying the deployment was deleted")
-				deployments, getDeploymentErr := boshClient.GetDeployments(logger)
+				deployments, getDeploymentErr := boshClient.GetDeployments(context.Background(), logger)
 				Expect(getDeploymentErr).NotTo(HaveOccurred())
 				Expect(deployments).NotTo(ContainElement(boshdirector.Deployment{Name: deploymentName}))
 			})
@@ -212,7 +213,7 @@ var _ = Describe("BOSH client", func() {
 		When("DeleteDeployment is called", func() {
 			It("returns 0 for task ID and no error", func() {
 				reporter := boshdirector.NewAsyncTaskReporter()
-				taskID, err := boshClient.DeleteDeployment("something-that-does-not-exist", "", false, reporter, logger)
+				taskID, err := boshClient.DeleteDeployment(context.Background(), "something-that-does-not-exist", "", false, reporter, logger)
 				Expect(taskID).To(Equal(0))
 				Expect(err).NotTo(HaveOccurred())
 
@@ -221,7 +222,7 @@ var _ = Describe("BOSH client", func() {
 		})
 		When("GetDeployment is called", func() {
 			It("returns false and no error", func() {
-				_, found, getDeploymentErr := boshClient.GetDeployment("sad-face", logger)
+				_, found, getDeploymentErr := boshClient.GetDeployment(context.Background(), "sad-face", logger)
 				Expect(found).To(BeFalse())
 				Expect(getDeploymentErr).NotTo(HaveOccurred())
 			})
@@ -230,14 +231,14 @@ var _ = Describe("BOSH client", func() {
 
 	Describe("GetInfo()", func() {
 		It("talks to the director", func() {
-			info, err := boshClient.GetInfo(logger)
+			info, err := boshClient.GetInfo(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(info.Version).NotTo(BeEmpty())
 		})
 
 		It("gets the info about director", func() {
-			info, err := boshClient.GetInfo(logger)
+			info, err := boshClient.GetInfo(context.Background(), logger)
 
 			Expect(err).NotTo(HaveOccurred())
 			uaaURL := info.UserAuthentication.Options.URL
@@ -249,7 +250,7 @@ var _ = Describe("BOSH client", func() {
 		})
 
 		It("is an authenticated director", func() {
-			err := boshClient.VerifyAuth(logger)
+			err := boshClient.VerifyAuth(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
@@ -257,12 +258,12 @@ var _ = Describe("BOSH client", func() {
 	Describe("VerifyAuth()", func() {
 		It("validates the credentials", func() {
 			By("succeeding when the creds are correct")
-			err := boshClient.VerifyAuth(logger)
+			err := boshClient.VerifyAuth(context.Background(), logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("failing when the creds are wrong")
 			wrongBoshClient := NewBOSHClientWithBadCredentials()
-			err = wrongBoshClient.VerifyAuth(logger)
+			err = wrongBoshClient.VerifyAuth(context.Background(), logger)
 			Expect(err).To(MatchError(ContainSubstring("Bad credentials")))
 		})
 	})
@@ -279,7 +280,7 @@ var _ = Describe("BOSH client", func() {
 
 		Describe("RawGet", func() {
 			It("returns valid info", func() {
-				r, err := boshHTTP.RawGet("/info")
+				r, err := boshHTTP.RawGet(context.Background(), "/info")
 				Expect(err).NotTo(HaveOccurred())
 
 				boshInfo := struct {
@@ -300,12 +301,12 @@ var _ = Describe("BOSH client", func() {
 
 		Describe("RawPost()", func() {
 			AfterEach(func() {
-				_, err := boshHTTP.RawDelete("/configs?type=cloud&name=test")
+				_, err := boshHTTP.RawDelete(context.Background(), "/configs?type=cloud&name=test")
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("can set cloud config", func() {
-				r, err := boshHTTP.RawPost("/configs", cloudConfigJSON, "application/json")
+				r, err := boshHTTP.RawPost(context.Background(), "/configs", cloudConfigJSON, "application/json")
 				Expect(err).NotTo(HaveOccurred())
 
 				postResponse := struct {
@@ -324,12 +325,12 @@ var _ = Describe("BOSH client", func() {
 
 		Describe("RawDelete()", func() {
 			BeforeEach(func() {
-				_, err := boshHTTP.RawPost("/configs", cloudConfigJSON, "application/json")
+				_, err := boshHTTP.RawPost(context.Background(), "/configs", cloudConfigJSON, "application/json")
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("can delete a cloud config", func() {
-				_, err := boshHTTP.RawDelete("/configs?type=cloud&name=test")
+				_, err := boshHTTP.RawDelete(context.Background(), "/configs?type=cloud&name=test")
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
@@ -339,7 +340,7 @@ var _ = Describe("BOSH client", func() {
 		var dnsRetriever boshdirector.DNSRetriever
 		BeforeEach(func() {
 			reporter := boshdirector.NewAsyncTaskReporter()
-			_, err := boshClient.Deploy(getManifest("deployment_with_link_provider.yml", deploymentName), "some-context-id", logger, reporter)
+			_, err := boshClient.Deploy(context.Background(), getManifest("deployment_with_link_provider.yml", deploymentName), "some-context-id", logger, reporter)
 			Expect(err).NotTo(HaveOccurred())
 
 			Eventually(reporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for %s to deploy", deploymentName))
@@ -350,7 +351,7 @@ var _ = Describe("BOSH client", func() {
 
 		AfterEach(func() {
 			reporter := boshdirector.NewAsyncTaskReporter()
-			_, err := boshClient.DeleteDeployment(deploymentName, "", false, reporter, logger)
+			_, err := boshClient.DeleteDeployment(context.Background(), deploymentName, "", false, reporter, logger)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -358,27 +359,27 @@ var _ = Describe("BOSH client", func() {
 			instanceGroupName := "dummy"
 			providerName := "link_from_dummy"
 
-			linkProviderId, err := dnsRetriever.LinkProviderID(deploymentName, instanceGroupName, providerName)
+			linkProviderId, err := dnsRetriever.LinkProviderID(context.Background(), deploymentName, instanceGroupName, providerName)
 			Expect(err).NotTo(HaveOccurred())
 
-			linkConsumerId, err := dnsRetriever.CreateLinkConsumer(linkProviderId)
+			linkConsumerId, err := dnsRetriever.CreateLinkConsumer(context.Background(), linkProviderId)
 			Expect(err).NotTo(HaveOccurred())
 
-			linkAddress, err := dnsRetriever.GetLinkAddress(linkConsumerId, nil, "")
+			linkAddress, err := dnsRetriever.GetLinkAddress(context.Background(), linkConsumerId, nil, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(linkAddress).To(MatchRegexp(`\.dummy\..*\.bosh$`))
 
-			linkAddressWithAZs, err := dnsRetriever.GetLinkAddress(linkConsumerId, []string{"z1", "z2"}, "")
+			linkAddressWithAZs, err := dnsRetriever.GetLinkAddress(context.Background(), linkConsumerId, []string{"z1", "z2"}, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(linkAddressWithAZs).To(MatchRegexp(`\.dummy\..*\.bosh$`))
 			Expect(linkAddressWithAZs).NotTo(Equal(linkAddress))
 
-			linkAddressWithStatus, err := dnsRetriever.GetLinkAddress(linkConsumerId, []string{}, "healthy")
+			linkAddressWithStatus, err := dnsRetriever.GetLinkAddress(context.Background(), linkConsumerId, []string{}, "healthy")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(linkAddressWithStatus).To(MatchRegexp(`\.dummy\..*\.bosh$`))
 			Expect(linkAddressWithStatus).NotTo(Equal(linkAddress))
 			Expect(linkAddressWithStatus).NotTo(Equal(linkAddressWithAZs))
-			err = dnsRetriever.DeleteLinkConsumer(linkConsumerId)
+			err = dnsRetriever.DeleteLinkConsumer(context.Background(), linkConsumerId)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
@@ -392,45 +393,45 @@ var _ = Describe("BOSH client", func() {
 		)
 
 		AfterEach(func() {
-			_, err := boshClient.DeleteConfig(configType, deploymentName, logger)
+			_, err := boshClient.DeleteConfig(context.Background(), configType, deploymentName, logger)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("can control the entire lifecycle of a config", func() {
 			By("updating a config", func() {
-				err := boshClient.UpdateConfig(configType, deploymentName, configContent, logger)
+				err := boshClient.UpdateConfig(context.Background(), configType, deploymentName, configContent, logger)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			By("checking all the configs", func() {
-				configs, err := boshClient.GetConfigs(deploymentName, logger)
+				configs, err := boshClient.GetConfigs(context.Background(), deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(len(configs)).To(Equal(1))
 				Expect(configs).To(ContainElement(boshdirector.BoshConfig{Type: configType, Name: deploymentName, Content: string(configContent)}))
 			})
 
 			By("adding another config with the same name but different type", func() {
-				err := boshClient.UpdateConfig(anotherConfigType, deploymentName, anotherConfigContent, logger)
+				err := boshClient.UpdateConfig(context.Background(), anotherConfigType, deploymentName, anotherConfigContent, logger)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			By("checking list configs now returns both configs", func() {
-				configs, err := boshClient.GetConfigs(deploymentName, logger)
+				configs, err := boshClient.GetConfigs(context.Background(), deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(len(configs)).To(Equal(2))
 			})
 
 			By("deleting configs", func() {
-				found, err := boshClient.DeleteConfig(configType, deploymentName, logger)
+				found, err := boshClient.DeleteConfig(context.Background(), configType, deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(found).To(BeTrue())
 
-				found, err = boshClient.DeleteConfig(anotherConfigType, deploymentName, logger)
+				found, err = boshClient.DeleteConfig(context.Background(), anotherConfigType, deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(found).To(BeTrue())
 
 				By("verifying the configs were deleted")
-				configs, err := boshClient.GetConfigs(deploymentName, logger)
+				configs, err := boshClient.GetConfigs(context.Background(), deploymentName, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(len(configs)).To(Equal(0))
 			})
@@ -446,7 +447,7 @@ var _ = Describe("BOSH client", func() {
 				manifest := getManifest("single_vm_deployment.yml", deploymentName)
 
 				var err error
-				expectedTaskID, err = boshClient.Deploy(manifest, "some-context-id", logger, reporter)
+				expectedTaskID, err = boshClient.Deploy(context.Background(), manifest, "some-context-id", logger, reporter)
 				Expect(err).NotTo(HaveOccurred())
 				Eventually(reporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for %s to deploy", deploymentName))
 				Expect(reporter.State).ToNot(Equal("error"), fmt.Sprintf("Deployment of %s failed", deploymentName))
@@ -454,14 +455,14 @@ var _ = Describe("BOSH client", func() {
 
 			AfterEach(func() {
 				reporter := boshdirector.NewAsyncTaskReporter()
-				_, err := boshClient.DeleteDeployment(deploymentName, "", false, reporter, logger)
+				_, err := boshClient.DeleteDeployment(context.Background(), deploymentName, "", false, reporter, logger)
 				Expect(err).NotTo(HaveOccurred())
 				Eventually(reporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for deployment %s to be deleted", deploymentName))
 			})
 
 			It("returns a list of events for that deployment", func() {
 				By("querying create events", func() {
-					events, err := boshClient.GetEvents(deploymentName, "create", logger)
+					events, err := boshClient.GetEvents(context.Background(), deploymentName, "create", logger)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(len(events)).To(BeNumerically(">", 0))
 					Expect(events[0].TaskId).To(Equal(expectedTaskID))
@@ -470,12 +471,12 @@ var _ = Describe("BOSH client", func() {
 				By("querying update events", func() {
 					reporter := boshdirector.NewAsyncTaskReporter()
 					manifest := getManifest("single_vm_deployment.yml", deploymentName)
-					redeployTaskID, err := boshClient.Deploy(manifest, "some-other-context-id", logger, reporter)
+					redeployTaskID, err := boshClient.Deploy(context.Background(), manifest, "some-other-context-id", logger, reporter)
 					Expect(err).NotTo(HaveOccurred())
 					Eventually(reporter.Finished).Should(Receive(), fmt.Sprintf("Timed out waiting for %s to deploy", deploymentName))
 					Expect(reporter.State).ToNot(Equal("error"), fmt.Sprintf("Deployment of %s failed", deploymentName))
 
-					events, err := boshClient.GetEvents(deploymentName, "update", logger)
+					events, err := boshClient.GetEvents(context.Background(), deploymentName, "update", logger)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(len(events)).To(BeNumerically(">", 0))
 					Expect(events[0].TaskId).To(Equal(redeployTaskID))
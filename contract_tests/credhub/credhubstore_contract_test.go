@@ -16,6 +16,7 @@
 package credhub_tests
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"strings"
@@ -98,7 +99,7 @@ var _ = Describe("Credential store", func() {
 		It("sets multiple values", func() {
 			path1 := makeKeyPath("secret-1")
 			path2 := makeKeyPath("secret-2")
-			err := subject.BulkSet([]broker.ManifestSecret{
+			err := subject.BulkSet(context.Background(), []broker.ManifestSecret{
 				{Name: "secret-1", Path: path1, Value: map[string]interface{}{"hi": "there"}},
 				{Name: "secret-2", Path: path2, Value: "value2"},
 			})
@@ -297,7 +298,7 @@ var _ = Describe("Credential store", func() {
 				secretWithSubkeyName: string(certSecret.Value.PrivateKey),
 			}
 
-			actualSecrets, err := subject.BulkGet(secretsToFetch, logger)
+			actualSecrets, err := subject.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualSecrets).To(Equal(expectedSecrets))
 		})
@@ -315,7 +316,7 @@ var _ = Describe("Credential store", func() {
 				passwordSecret.Name: string(passwordSecret.Value),
 			}
 
-			actualSecrets, err := subject.BulkGet(secretsToFetch, logger)
+			actualSecrets, err := subject.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualSecrets).To(Equal(expectedSecrets))
 
@@ -326,7 +327,7 @@ var _ = Describe("Credential store", func() {
 			expectedSecrets = map[string]string{
 				passwordSecret.Name: string(newPasswordSecret.Value),
 			}
-			actualSecrets, err = subject.BulkGet(secretsToFetch, logger)
+			actualSecrets, err = subject.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualSecrets).To(Equal(expectedSecrets))
 		})
@@ -335,7 +336,7 @@ var _ = Describe("Credential store", func() {
 			secretsToFetch := map[string]boshdirector.Variable{
 				"blah": {Path: "blah"},
 			}
-			_, err := subject.BulkGet(secretsToFetch, logger)
+			_, err := subject.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(logBuffer).To(gbytes.Say("Could not resolve blah"))
 		})
@@ -368,7 +369,7 @@ var _ = Describe("Credential store", func() {
 		})
 
 		It("can find all secrets containing a portion of a path in their path", func() {
-			actualPaths, err := subject.FindNameLike(randomGuid, nil)
+			actualPaths, err := subject.FindNameLike(context.Background(), randomGuid, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(len(actualPaths)).To(Equal(2))
 			Expect(actualPaths).To(ConsistOf(paths))
@@ -401,7 +402,7 @@ var _ = Describe("Credential store", func() {
 		})
 
 		It("can delete all secrets", func() {
-			err := subject.BulkDelete(paths, nil)
+			err := subject.BulkDelete(context.Background(), paths, nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			for p := range paths {
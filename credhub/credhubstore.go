@@ -16,19 +16,23 @@
 package credhub
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 
 	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
 	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
 	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
 	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 type Store struct {
@@ -43,6 +47,7 @@ type CredhubClient interface {
 	FindByPartialName(partialName string) (credentials.FindResults, error)
 	SetJSON(name string, value values.JSON, options ...credhub.SetOption) (credentials.JSON, error)
 	SetValue(name string, value values.Value, options ...credhub.SetOption) (credentials.Value, error)
+	Regenerate(name string, options ...credhub.RegenerateOption) (credentials.Credential, error)
 	AddPermission(credName, actor string, ops []string) (*permissions.Permission, error)
 	Delete(name string) error
 }
@@ -61,13 +66,39 @@ func New(credhubClient CredhubClient) *Store {
 	return &Store{credhubClient: credhubClient}
 }
 
+// client returns the client to make the calls for the request of ctx with,
+// which sets the traceparent header of the span in ctx on every request.
+func (c *Store) client(ctx context.Context) CredhubClient {
+	credhubClient, ok := c.credhubClient.(*credhub.CredHub)
+	if !ok {
+		return c.credhubClient
+	}
+	traced := *credhubClient
+	traced.Auth = tracingStrategy{Strategy: credhubClient.Auth, ctx: ctx}
+	return &traced
+}
+
+type tracingStrategy struct {
+	auth.Strategy
+	ctx context.Context
+}
+
+func (t tracingStrategy) Do(req *http.Request) (*http.Response, error) {
+	tracing.Inject(t.ctx, req.Header)
+	return t.Strategy.Do(req)
+}
+
 func (c *Store) Set(key string, value interface{}) error {
+	return set(c.credhubClient, key, value)
+}
+
+func set(credhubClient CredhubClient, key string, value interface{}) error {
 	var err error
 	switch credValue := value.(type) {
 	case map[string]interface{}:
-		_, err = c.credhubClient.SetJSON(key, values.JSON(credValue))
+		_, err = credhubClient.SetJSON(key, values.JSON(credValue))
 	case string:
-		_, err = c.credhubClient.SetValue(key, values.Value(credValue))
+		_, err = credhubClient.SetValue(key, values.Value(credValue))
 	default:
 		return errors.New("Unknown credential type")
 	}
@@ -78,9 +109,13 @@ func (c *Store) AddPermission(credName, actor string, ops []string) (*permission
 	return c.credhubClient.AddPermission(credName, actor, ops)
 }
 
-func (c *Store) BulkDelete(paths []string, logger *log.Logger) error {
+func (c *Store) BulkDelete(ctx context.Context, paths []string, logger *log.Logger) (err error) {
+	ctx, span := tracing.Start(ctx, "credhub.bulk-delete")
+	defer func() { span.End(err) }()
+
+	client := c.client(ctx)
 	for _, path := range paths {
-		if err := c.Delete(path); err != nil {
+		if err := client.Delete(path); err != nil {
 			logger.Printf("could not delete secret '%s': %s", path, err.Error())
 			return err
 		}
@@ -88,8 +123,11 @@ func (c *Store) BulkDelete(paths []string, logger *log.Logger) error {
 	return nil
 }
 
-func (c *Store) FindNameLike(name string, logger *log.Logger) ([]string, error) {
-	results, err := c.credhubClient.FindByPartialName(name)
+func (c *Store) FindNameLike(ctx context.Context, name string, logger *log.Logger) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "credhub.find-name-like")
+	defer func() { span.End(err) }()
+
+	results, err := c.client(ctx).FindByPartialName(name)
 	if err != nil {
 		return nil, err
 	}
@@ -106,16 +144,20 @@ func (c *Store) Delete(key string) error {
 	return c.credhubClient.Delete(key)
 }
 
-func (c *Store) BulkGet(secretsToFetch map[string]boshdirector.Variable, logger *log.Logger) (map[string]string, error) {
+func (c *Store) BulkGet(ctx context.Context, secretsToFetch map[string]boshdirector.Variable, logger *log.Logger) (_ map[string]string, err error) {
+	ctx, span := tracing.Start(ctx, "credhub.bulk-get")
+	defer func() { span.End(err) }()
+
+	client := c.client(ctx)
 	ret := map[string]string{}
 	for name, deploymentVar := range secretsToFetch {
 		var cred credentials.Credential
 		var err error
 
 		if deploymentVar.ID != "" {
-			cred, err = c.credhubClient.GetById(deploymentVar.ID)
+			cred, err = client.GetById(deploymentVar.ID)
 		} else {
-			cred, err = c.credhubClient.GetLatestVersion(deploymentVar.Path)
+			cred, err = client.GetLatestVersion(deploymentVar.Path)
 		}
 		if err != nil {
 			logger.Printf("Could not resolve %s: %s", name, err)
@@ -191,9 +233,46 @@ func getSubKey(credValue interface{}, subkey string) (string, error) {
 	return requestedValue, nil
 }
 
-func (c *Store) BulkSet(secretsToSet []broker.ManifestSecret) error {
+func (c *Store) BulkSet(ctx context.Context, secretsToSet []broker.ManifestSecret) error {
+	client := c.client(ctx)
 	for _, secret := range secretsToSet {
-		if err := c.Set(secret.Path, secret.Value); err != nil {
+		if err := set(client, secret.Path, secret.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreVersions sets the value of each variable to the value of the version
+// it refers to, making that version current again.
+func (c *Store) RestoreVersions(ctx context.Context, variables []boshdirector.Variable, logger *log.Logger) (err error) {
+	ctx, span := tracing.Start(ctx, "credhub.restore-versions")
+	defer func() { span.End(err) }()
+
+	client := c.client(ctx)
+	for _, variable := range variables {
+		cred, err := client.GetById(variable.ID)
+		if err != nil {
+			logger.Printf("could not get version %s of secret '%s': %s", variable.ID, variable.Path, err)
+			return err
+		}
+		if err := set(client, variable.Path, cred.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Regenerate generates new values for the secrets, using the parameters they
+// were generated with.
+func (c *Store) Regenerate(ctx context.Context, paths []string, logger *log.Logger) (err error) {
+	ctx, span := tracing.Start(ctx, "credhub.regenerate")
+	defer func() { span.End(err) }()
+
+	client := c.client(ctx)
+	for _, path := range paths {
+		if _, err := client.Regenerate(path); err != nil {
+			logger.Printf("could not regenerate secret '%s': %s", path, err)
 			return err
 		}
 	}
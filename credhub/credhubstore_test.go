@@ -1,6 +1,7 @@
 package credhub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -65,7 +66,7 @@ var _ = Describe("CredStore", func() {
 				Value: credhubSecretValue,
 			}, nil)
 
-			secrets, err := store.BulkGet(secretsToFetch, logger)
+			secrets, err := store.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(secrets).To(Equal(expectedSecrets))
@@ -92,7 +93,7 @@ var _ = Describe("CredStore", func() {
 				Value: credhubSecretValue,
 			}, nil)
 
-			secrets, err := store.BulkGet(secretsToFetch, logger)
+			secrets, err := store.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(secrets).To(Equal(expectedSecrets))
@@ -113,7 +114,7 @@ var _ = Describe("CredStore", func() {
 				Value: credhubSecretValue,
 			}, nil)
 
-			result, err := store.BulkGet(secretsToFetch, logger)
+			result, err := store.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			_, ok := result[ref]
@@ -144,7 +145,7 @@ var _ = Describe("CredStore", func() {
 				}
 				return credentials.Credential{}, nil
 			}
-			result, err := store.BulkGet(secretsToFetch, logger)
+			result, err := store.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(HaveLen(2))
 			Expect(result["((one))"]).To(Equal("foo-val"))
@@ -162,7 +163,7 @@ var _ = Describe("CredStore", func() {
 				Value: 5,
 			}, nil)
 
-			result, err := store.BulkGet(secretsToFetch, logger)
+			result, err := store.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 
 			_, ok := result[ref]
@@ -176,7 +177,7 @@ var _ = Describe("CredStore", func() {
 			secretsToFetch := map[string]boshdirector.Variable{
 				"((somePath))": {Path: "/path/to/somePath"},
 			}
-			secrets, err := store.BulkGet(secretsToFetch, logger)
+			secrets, err := store.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 			_, ok := secrets["((somePath))"]
 			Expect(ok).To(BeFalse(), "somePath should not be returned in secrets")
@@ -188,7 +189,7 @@ var _ = Describe("CredStore", func() {
 			secretsToFetch := map[string]boshdirector.Variable{
 				"((somePath))": {Path: "/path/to/somePath", ID: "31313"},
 			}
-			secrets, err := store.BulkGet(secretsToFetch, logger)
+			secrets, err := store.BulkGet(context.Background(), secretsToFetch, logger)
 			Expect(err).NotTo(HaveOccurred())
 			_, ok := secrets["((somePath))"]
 			Expect(ok).To(BeFalse(), "somePath should not be returned in secrets")
@@ -223,6 +224,83 @@ var _ = Describe("CredStore", func() {
 		})
 	})
 
+	Describe("RestoreVersions", func() {
+		var (
+			logBuffer *gbytes.Buffer
+			logger    *log.Logger
+		)
+
+		BeforeEach(func() {
+			logBuffer = gbytes.NewBuffer()
+			logger = log.New(io.Writer(logBuffer), "my-app", log.LstdFlags)
+		})
+
+		It("sets each secret to the value of the version it refers to", func() {
+			fakeCredhubClient.GetByIdReturnsOnCall(0, credentials.Credential{Value: "old-password"}, nil)
+			fakeCredhubClient.GetByIdReturnsOnCall(1, credentials.Credential{Value: map[string]interface{}{"certificate": "old-cert"}}, nil)
+
+			err := store.RestoreVersions(context.Background(), []boshdirector.Variable{
+				{Path: "/odb/some/password", ID: "1"},
+				{Path: "/odb/some/cert", ID: "2"},
+			}, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeCredhubClient.GetByIdArgsForCall(0)).To(Equal("1"))
+			Expect(fakeCredhubClient.GetByIdArgsForCall(1)).To(Equal("2"))
+
+			path, value, _ := fakeCredhubClient.SetValueArgsForCall(0)
+			Expect(path).To(Equal("/odb/some/password"))
+			Expect(value).To(Equal(values.Value("old-password")))
+
+			path, json, _ := fakeCredhubClient.SetJSONArgsForCall(0)
+			Expect(path).To(Equal("/odb/some/cert"))
+			Expect(json).To(Equal(values.JSON{"certificate": "old-cert"}))
+		})
+
+		It("returns an error when a version cannot be read", func() {
+			fakeCredhubClient.GetByIdReturns(credentials.Credential{}, errors.New("version gone"))
+
+			err := store.RestoreVersions(context.Background(), []boshdirector.Variable{{Path: "/odb/some/password", ID: "1"}}, logger)
+
+			Expect(err).To(MatchError("version gone"))
+			Expect(fakeCredhubClient.SetValueCallCount()).To(BeZero())
+			Expect(logBuffer).To(gbytes.Say("could not get version 1 of secret '/odb/some/password': version gone"))
+		})
+	})
+
+	Describe("Regenerate", func() {
+		var (
+			logBuffer *gbytes.Buffer
+			logger    *log.Logger
+		)
+
+		BeforeEach(func() {
+			logBuffer = gbytes.NewBuffer()
+			logger = log.New(io.Writer(logBuffer), "my-app", log.LstdFlags)
+		})
+
+		It("regenerates each secret", func() {
+			err := store.Regenerate(context.Background(), []string{"/director/some/password", "/director/some/cert"}, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeCredhubClient.RegenerateCallCount()).To(Equal(2))
+			path, _ := fakeCredhubClient.RegenerateArgsForCall(0)
+			Expect(path).To(Equal("/director/some/password"))
+			path, _ = fakeCredhubClient.RegenerateArgsForCall(1)
+			Expect(path).To(Equal("/director/some/cert"))
+		})
+
+		It("returns an error when a secret cannot be regenerated", func() {
+			fakeCredhubClient.RegenerateReturns(credentials.Credential{}, errors.New("not generated by credhub"))
+
+			err := store.Regenerate(context.Background(), []string{"/director/some/password", "/director/some/cert"}, logger)
+
+			Expect(err).To(MatchError("not generated by credhub"))
+			Expect(fakeCredhubClient.RegenerateCallCount()).To(Equal(1))
+			Expect(logBuffer).To(gbytes.Say("could not regenerate secret '/director/some/password': not generated by credhub"))
+		})
+	})
+
 	Describe("Delete", func() {
 		It("can delete a credhub secret at path p", func() {
 			p := "/some/path"
@@ -267,7 +345,7 @@ var _ = Describe("CredStore", func() {
 		It("does not set anything when called with an empty secrets map", func() {
 			secretsToSet := []broker.ManifestSecret{}
 
-			err := store.BulkSet(secretsToSet)
+			err := store.BulkSet(context.Background(), secretsToSet)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeCredhubClient.SetJSONCallCount()).To(Equal(0), "SetJSON was called")
@@ -280,7 +358,7 @@ var _ = Describe("CredStore", func() {
 				{Name: "bar", Path: "/foo/bar", Value: map[string]interface{}{"key": "value"}},
 			}
 
-			err := store.BulkSet(secretsToSet)
+			err := store.BulkSet(context.Background(), secretsToSet)
 			Expect(err).NotTo(HaveOccurred())
 
 			By("calling SetJSON for JSON values")
@@ -302,7 +380,7 @@ var _ = Describe("CredStore", func() {
 				{Name: "foo", Path: "/foo/foo", Value: make(chan bool)},
 			}
 
-			err := store.BulkSet(secretsToSet)
+			err := store.BulkSet(context.Background(), secretsToSet)
 			Expect(err).To(MatchError("Unknown credential type"))
 		})
 
@@ -312,7 +390,7 @@ var _ = Describe("CredStore", func() {
 			}
 
 			fakeCredhubClient.SetJSONReturns(credentials.JSON{}, errors.New("can't do it right now"))
-			err := store.BulkSet(secretsToSet)
+			err := store.BulkSet(context.Background(), secretsToSet)
 			Expect(err).To(MatchError("can't do it right now"))
 		})
 
@@ -322,7 +400,7 @@ var _ = Describe("CredStore", func() {
 			}
 
 			fakeCredhubClient.SetValueReturns(credentials.Value{}, errors.New("too busy, sorry"))
-			err := store.BulkSet(secretsToSet)
+			err := store.BulkSet(context.Background(), secretsToSet)
 			Expect(err).To(MatchError("too busy, sorry"))
 		})
 	})
@@ -339,7 +417,7 @@ var _ = Describe("CredStore", func() {
 				},
 			}, nil)
 
-			actualPaths, err := store.FindNameLike("tofu", nil)
+			actualPaths, err := store.FindNameLike(context.Background(), "tofu", nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualPaths).To(ConsistOf([]string{
 				"/tofu/path",
@@ -350,7 +428,7 @@ var _ = Describe("CredStore", func() {
 		It("returns an error when there is an error with the credhub client", func() {
 			fakeCredhubClient.FindByPartialNameReturns(credentials.FindResults{}, errors.New("couldn't do it"))
 
-			_, err := store.FindNameLike("tofu", nil)
+			_, err := store.FindNameLike(context.Background(), "tofu", nil)
 			Expect(err).To(MatchError("couldn't do it"))
 		})
 	})
@@ -370,7 +448,7 @@ var _ = Describe("CredStore", func() {
 			secretsToDelete := []string{"/some/path/secret", "/some/path/another_secret"}
 			fakeCredhubClient.DeleteReturns(nil)
 
-			err := store.BulkDelete(secretsToDelete, logger)
+			err := store.BulkDelete(context.Background(), secretsToDelete, logger)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeCredhubClient.DeleteCallCount()).To(Equal(2))
@@ -382,7 +460,7 @@ var _ = Describe("CredStore", func() {
 			secretsToDelete := []string{"/some/path/secret", "/some/path/another_secret"}
 			fakeCredhubClient.DeleteReturns(errors.New("too difficult to delete"))
 
-			err := store.BulkDelete(secretsToDelete, logger)
+			err := store.BulkDelete(context.Background(), secretsToDelete, logger)
 
 			Expect(err).To(MatchError("too difficult to delete"))
 			Expect(logBuffer).To(gbytes.Say("could not delete secret '/some/path/secret': too difficult to delete"))
@@ -77,6 +77,20 @@ type FakeCredhubClient struct {
 		result1 credentials.Credential
 		result2 error
 	}
+	RegenerateStub        func(string, ...credhuba.RegenerateOption) (credentials.Credential, error)
+	regenerateMutex       sync.RWMutex
+	regenerateArgsForCall []struct {
+		arg1 string
+		arg2 []credhuba.RegenerateOption
+	}
+	regenerateReturns struct {
+		result1 credentials.Credential
+		result2 error
+	}
+	regenerateReturnsOnCall map[int]struct {
+		result1 credentials.Credential
+		result2 error
+	}
 	SetJSONStub        func(string, values.JSON, ...credhuba.SetOption) (credentials.JSON, error)
 	setJSONMutex       sync.RWMutex
 	setJSONArgsForCall []struct {
@@ -435,6 +449,71 @@ func (fake *FakeCredhubClient) GetLatestVersionReturnsOnCall(i int, result1 cred
 	}{result1, result2}
 }
 
+func (fake *FakeCredhubClient) Regenerate(arg1 string, arg2 ...credhuba.RegenerateOption) (credentials.Credential, error) {
+	fake.regenerateMutex.Lock()
+	ret, specificReturn := fake.regenerateReturnsOnCall[len(fake.regenerateArgsForCall)]
+	fake.regenerateArgsForCall = append(fake.regenerateArgsForCall, struct {
+		arg1 string
+		arg2 []credhuba.RegenerateOption
+	}{arg1, arg2})
+	stub := fake.RegenerateStub
+	fakeReturns := fake.regenerateReturns
+	fake.recordInvocation("Regenerate", []interface{}{arg1, arg2})
+	fake.regenerateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCredhubClient) RegenerateCallCount() int {
+	fake.regenerateMutex.RLock()
+	defer fake.regenerateMutex.RUnlock()
+	return len(fake.regenerateArgsForCall)
+}
+
+func (fake *FakeCredhubClient) RegenerateCalls(stub func(string, ...credhuba.RegenerateOption) (credentials.Credential, error)) {
+	fake.regenerateMutex.Lock()
+	defer fake.regenerateMutex.Unlock()
+	fake.RegenerateStub = stub
+}
+
+func (fake *FakeCredhubClient) RegenerateArgsForCall(i int) (string, []credhuba.RegenerateOption) {
+	fake.regenerateMutex.RLock()
+	defer fake.regenerateMutex.RUnlock()
+	argsForCall := fake.regenerateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredhubClient) RegenerateReturns(result1 credentials.Credential, result2 error) {
+	fake.regenerateMutex.Lock()
+	defer fake.regenerateMutex.Unlock()
+	fake.RegenerateStub = nil
+	fake.regenerateReturns = struct {
+		result1 credentials.Credential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredhubClient) RegenerateReturnsOnCall(i int, result1 credentials.Credential, result2 error) {
+	fake.regenerateMutex.Lock()
+	defer fake.regenerateMutex.Unlock()
+	fake.RegenerateStub = nil
+	if fake.regenerateReturnsOnCall == nil {
+		fake.regenerateReturnsOnCall = make(map[int]struct {
+			result1 credentials.Credential
+			result2 error
+		})
+	}
+	fake.regenerateReturnsOnCall[i] = struct {
+		result1 credentials.Credential
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCredhubClient) SetJSON(arg1 string, arg2 values.JSON, arg3 ...credhuba.SetOption) (credentials.JSON, error) {
 	fake.setJSONMutex.Lock()
 	ret, specificReturn := fake.setJSONReturnsOnCall[len(fake.setJSONArgsForCall)]
@@ -580,6 +659,8 @@ func (fake *FakeCredhubClient) Invocations() map[string][][]interface{} {
 	defer fake.getByIdMutex.RUnlock()
 	fake.getLatestVersionMutex.RLock()
 	defer fake.getLatestVersionMutex.RUnlock()
+	fake.regenerateMutex.RLock()
+	defer fake.regenerateMutex.RUnlock()
 	fake.setJSONMutex.RLock()
 	defer fake.setJSONMutex.RUnlock()
 	fake.setValueMutex.RLock()
@@ -17,7 +17,6 @@ package credhubbroker
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
@@ -49,17 +48,14 @@ func New(broker apiserver.CombinedBroker,
 	}
 }
 
+// Bind stores the credentials of the binding in CredHub, and returns a
+// reference to them instead. The wrapped broker stores the credentials of
+// asynchronous bindings itself once they are created, and only ever returns
+// references to them.
 func (b *CredHubBroker) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, asyncAllowed bool) (domain.Binding, error) {
-	var actor string
-	switch {
-	case details.AppGUID != "":
-		actor = fmt.Sprintf("mtls-app:%s", details.AppGUID)
-	case details.BindResource != nil && details.BindResource.AppGuid != "":
-		actor = fmt.Sprintf("mtls-app:%s", details.BindResource.AppGuid)
-	case details.BindResource != nil && details.BindResource.CredentialClientID != "":
-		actor = fmt.Sprintf("uaa-client:%s", details.BindResource.CredentialClientID)
-	default:
-		return domain.Binding{}, errors.New("No app-guid or credential client ID were provided in the binding request, you must configure one of these")
+	actor, err := broker.BindingActor(details)
+	if err != nil {
+		return domain.Binding{}, err
 	}
 
 	requestID := uuid.New()
@@ -71,7 +67,7 @@ func (b *CredHubBroker) Bind(ctx context.Context, instanceID, bindingID string,
 		return domain.Binding{}, err
 	}
 
-	if b.credentialsEmpty(binding) {
+	if binding.IsAsync || b.credentialsEmpty(binding) {
 		return binding, nil
 	}
 
@@ -121,12 +117,12 @@ func (b *CredHubBroker) Unbind(ctx context.Context, instanceID, bindingID string
 	key := constructKey(details.ServiceID, instanceID, bindingID)
 	chErr := b.credStore.Delete(key)
 	if chErr != nil {
-		logger.Printf("WARNING: failed to remove key '%s' from credential store", key)
+		loggerfactory.WithLevel(logger, loggerfactory.WarnLevel).Printf("WARNING: failed to remove key '%s' from credential store", key)
 	}
 
 	return unbind, nil
 }
 
 func constructKey(serviceID, instanceID, bindingID string) string {
-	return fmt.Sprintf("/c/%s/%s/%s/credentials", serviceID, instanceID, bindingID)
+	return broker.BindingCredentialsKey(serviceID, instanceID, bindingID)
 }
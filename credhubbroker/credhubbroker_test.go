@@ -253,6 +253,49 @@ var _ = Describe("CredHub broker", func() {
 		})
 	})
 
+	Describe("asynchronous bindings", func() {
+		var (
+			fakeCredStore *credfakes.FakeCredentialStore
+			credhubBroker *credhubbroker.CredHubBroker
+			credhubRef    string
+		)
+
+		BeforeEach(func() {
+			fakeCredStore = new(credfakes.FakeCredentialStore)
+			credhubBroker = credhubbroker.New(fakeBroker, fakeCredStore, serviceName, loggerFactory)
+			credhubRef = constructCredhubRef(bindDetails.ServiceID, instanceID, bindingID)
+
+			fakeBroker.BindReturns(domain.Binding{IsAsync: true, OperationData: "some-operation"}, nil)
+			bindDetails.AppGUID = "an-app"
+		})
+
+		It("leaves storing the credentials to the wrapped broker", func() {
+			response, err := credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(domain.Binding{IsAsync: true, OperationData: "some-operation"}))
+			Expect(fakeCredStore.SetCallCount()).To(Equal(0))
+		})
+
+		It("returns the reference to the credentials from the wrapped broker", func() {
+			fakeBroker.GetBindingReturns(domain.GetBindingSpec{Credentials: map[string]string{"credhub-ref": credhubRef}, SyslogDrainURL: "some.thing"}, nil)
+
+			spec, err := credhubBroker.GetBinding(ctx, instanceID, bindingID, domain.FetchBindingDetails{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Credentials).To(Equal(map[string]string{"credhub-ref": credhubRef}))
+			Expect(spec.SyslogDrainURL).To(Equal("some.thing"))
+			Expect(fakeCredStore.SetCallCount()).To(Equal(0))
+		})
+
+		It("refuses to bind without an app or client to give access to", func() {
+			bindDetails.AppGUID = ""
+			bindDetails.BindResource = nil
+
+			_, err := credhubBroker.Bind(ctx, instanceID, bindingID, bindDetails, true)
+			Expect(err).To(MatchError(ContainSubstring("No app-guid or credential client ID were provided")))
+			Expect(fakeBroker.BindCallCount()).To(Equal(0))
+		})
+	})
+
 	Describe("Unbind", func() {
 		unbindDetails := domain.UnbindDetails{
 			PlanID:    "asdf",
@@ -33,17 +33,28 @@ type Sleeper interface {
 }
 
 type Config struct {
-	ServiceCatalog             ServiceCatalog `yaml:"service_catalog"`
-	DisableSSLCertVerification bool           `yaml:"disable_ssl_cert_verification"` // TODO use the CF.disable_ssl_cert_verification field
-	CF                         config.CF      `yaml:"cf"`
-	PollingInterval            int            `yaml:"polling_interval"`
-	PollingInitialOffset       int            `yaml:"polling_initial_offset"`
+	ServiceCatalog             ServiceCatalog   `yaml:"service_catalog"`
+	ServiceOfferings           []ServiceCatalog `yaml:"service_offerings"`
+	DisableSSLCertVerification bool             `yaml:"disable_ssl_cert_verification"` // TODO use the CF.disable_ssl_cert_verification field
+	CF                         config.CF        `yaml:"cf"`
+	PollingInterval            int              `yaml:"polling_interval"`
+	PollingInitialOffset       int              `yaml:"polling_initial_offset"`
 }
 
 type ServiceCatalog struct {
 	ID string `yaml:"id"`
 }
 
+// ServiceOfferingIDs returns the IDs of every service offering served by the
+// broker, starting with the one in service_catalog.
+func (c Config) ServiceOfferingIDs() []string {
+	ids := []string{c.ServiceCatalog.ID}
+	for _, offering := range c.ServiceOfferings {
+		ids = append(ids, offering.ID)
+	}
+	return ids
+}
+
 type Deleter struct {
 	logger               *log.Logger
 	pollingInitialOffset time.Duration
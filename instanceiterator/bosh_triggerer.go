@@ -52,6 +52,34 @@ func (t *BOSHTriggerer) TriggerOperation(instance service.Instance) (TriggeredOp
 	return t.translateTriggerResponse(operation), nil
 }
 
+// RotateSecretsTriggerer rotates the secrets of the instances, only those
+// selected by secretPaths when it is not empty.
+type RotateSecretsTriggerer struct {
+	*BOSHTriggerer
+	secretPaths []string
+}
+
+func NewRotateSecretsTriggerer(brokerServices BrokerServices, secretPaths []string) *RotateSecretsTriggerer {
+	return &RotateSecretsTriggerer{
+		BOSHTriggerer: &BOSHTriggerer{operationType: "rotate-secrets", brokerServices: brokerServices},
+		secretPaths:   secretPaths,
+	}
+}
+
+func (t *RotateSecretsTriggerer) TriggerOperation(instance service.Instance) (TriggeredOperation, error) {
+	operation, err := t.brokerServices.RotateSecrets(instance, t.secretPaths)
+	if err != nil {
+		return TriggeredOperation{},
+			fmt.Errorf(
+				"operation type: %s failed for service instance %s: %s",
+				t.operationType,
+				instance.GUID,
+				err,
+			)
+	}
+	return t.translateTriggerResponse(operation), nil
+}
+
 func (t *BOSHTriggerer) Check(serviceInstanceGUID string, operationData broker.OperationData) (TriggeredOperation, error) {
 	lastOperation, err := t.brokerServices.LastOperation(serviceInstanceGUID, operationData)
 	if err != nil {
@@ -105,6 +105,33 @@ var _ = Describe("BOSH Operation Triggerer", func() {
 				Expect(operationType).To(Equal("recreate"))
 			})
 		})
+
+		When("it is a Rotate Secrets Triggerer", func() {
+			BeforeEach(func() {
+				subject = instanceiterator.NewRotateSecretsTriggerer(fakeBrokerService, []string{"admin_password"})
+			})
+
+			It("rotates the selected secrets of the instance", func() {
+				fakeBrokerService.RotateSecretsReturns(services.BOSHOperation{Type: services.OperationAccepted}, nil)
+
+				operation, err := subject.TriggerOperation(instance)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(operation).To(Equal(instanceiterator.TriggeredOperation{State: instanceiterator.OperationAccepted}))
+
+				Expect(fakeBrokerService.ProcessInstanceCallCount()).To(Equal(0))
+				Expect(fakeBrokerService.RotateSecretsCallCount()).To(Equal(1))
+				instanceToProcess, secretPaths := fakeBrokerService.RotateSecretsArgsForCall(0)
+				Expect(instanceToProcess).To(Equal(instance))
+				Expect(secretPaths).To(Equal([]string{"admin_password"}))
+			})
+
+			It("returns an error if the rotate secrets request fails", func() {
+				fakeBrokerService.RotateSecretsReturns(services.BOSHOperation{}, errors.New("oops"))
+
+				_, err := subject.TriggerOperation(instance)
+				Expect(err).To(MatchError(fmt.Sprintf("operation type: rotate-secrets failed for service instance %s: oops", guid)))
+			})
+		})
 	})
 
 	Context("Check()", func() {
@@ -17,6 +17,8 @@ type CFClient interface {
 	UpgradeServiceInstance(serviceInstanceGUID string, maintenanceInfo cf.MaintenanceInfo, logger *log.Logger) (cf.LastOperation, error)
 	GetLastOperationForInstance(serviceInstanceGUID string, logger *log.Logger) (cf.LastOperation, error)
 	GetPlanByServiceInstanceGUID(planUniqueID string, logger *log.Logger) (cf.ServicePlan, error)
+	GetPlanByUniqueID(uniqueID string, logger *log.Logger) (cf.ServicePlan, error)
+	UpdateServiceInstancePlan(serviceInstanceGUID, planGUID string, parameters map[string]interface{}, logger *log.Logger) (cf.LastOperation, error)
 }
 
 type CFTriggerer struct {
@@ -0,0 +1,88 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+// Checkpoint is the progress of a run, as needed to resume it.
+type Checkpoint struct {
+	CanariesCompleted bool                          `json:"canaries_completed"`
+	Instances         map[string]InstanceCheckpoint `json:"instances"`
+}
+
+type InstanceCheckpoint struct {
+	State         OperationState       `json:"state"`
+	OperationData broker.OperationData `json:"operation_data"`
+}
+
+//counterfeiter:generate -o fakes/fake_checkpointer.go . Checkpointer
+type Checkpointer interface {
+	Load() (Checkpoint, error)
+	Save(Checkpoint) error
+	Clear() error
+}
+
+// BrokerCheckpointer keeps the checkpoint of a run in the broker, which stores
+// it on the BOSH director, so that a run can be resumed from a recreated errand
+// VM.
+type BrokerCheckpointer struct {
+	brokerServices BrokerServices
+	name           string
+}
+
+func NewBrokerCheckpointer(brokerServices BrokerServices, name string) *BrokerCheckpointer {
+	return &BrokerCheckpointer{brokerServices: brokerServices, name: name}
+}
+
+// Load returns an empty checkpoint when no run has been recorded.
+func (c *BrokerCheckpointer) Load() (Checkpoint, error) {
+	contents, found, err := c.brokerServices.IteratorCheckpoint(c.name)
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("error reading checkpoint %s: %s", c.name, err)
+	}
+	if !found {
+		return Checkpoint{}, nil
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(contents, &checkpoint); err != nil {
+		return Checkpoint{}, fmt.Errorf("error parsing checkpoint %s: %s", c.name, err)
+	}
+	return checkpoint, nil
+}
+
+func (c *BrokerCheckpointer) Save(checkpoint Checkpoint) error {
+	contents, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := c.brokerServices.SaveIteratorCheckpoint(c.name, contents); err != nil {
+		return fmt.Errorf("error writing checkpoint %s: %s", c.name, err)
+	}
+	return nil
+}
+
+func (c *BrokerCheckpointer) Clear() error {
+	if err := c.brokerServices.ClearIteratorCheckpoint(c.name); err != nil {
+		return fmt.Errorf("error removing checkpoint %s: %s", c.name, err)
+	}
+	return nil
+}
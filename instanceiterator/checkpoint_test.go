@@ -0,0 +1,94 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator/fakes"
+)
+
+var _ = Describe("Broker Checkpointer", func() {
+	var (
+		fakeBrokerServices *fakes.FakeBrokerServices
+		checkpointer       *instanceiterator.BrokerCheckpointer
+	)
+
+	BeforeEach(func() {
+		fakeBrokerServices = new(fakes.FakeBrokerServices)
+		checkpointer = instanceiterator.NewBrokerCheckpointer(fakeBrokerServices, "upgrade-all-bosh")
+	})
+
+	It("loads the checkpoint it saved", func() {
+		checkpoint := instanceiterator.Checkpoint{
+			CanariesCompleted: true,
+			Instances: map[string]instanceiterator.InstanceCheckpoint{
+				"1": {State: instanceiterator.OperationSucceeded},
+				"2": {State: instanceiterator.OperationAccepted, OperationData: broker.OperationData{BoshTaskID: 42, OperationType: broker.OperationTypeUpgrade}},
+			},
+		}
+
+		Expect(checkpointer.Save(checkpoint)).To(Succeed())
+
+		Expect(fakeBrokerServices.SaveIteratorCheckpointCallCount()).To(Equal(1))
+		name, contents := fakeBrokerServices.SaveIteratorCheckpointArgsForCall(0)
+		Expect(name).To(Equal("upgrade-all-bosh"))
+
+		fakeBrokerServices.IteratorCheckpointReturns(contents, true, nil)
+		Expect(checkpointer.Load()).To(Equal(checkpoint))
+		Expect(fakeBrokerServices.IteratorCheckpointArgsForCall(0)).To(Equal("upgrade-all-bosh"))
+	})
+
+	It("loads an empty checkpoint when none was saved", func() {
+		fakeBrokerServices.IteratorCheckpointReturns(nil, false, nil)
+
+		Expect(checkpointer.Load()).To(Equal(instanceiterator.Checkpoint{}))
+	})
+
+	It("fails to load a checkpoint that cannot be read", func() {
+		fakeBrokerServices.IteratorCheckpointReturns(nil, false, errors.New("boom"))
+
+		_, err := checkpointer.Load()
+
+		Expect(err).To(MatchError("error reading checkpoint upgrade-all-bosh: boom"))
+	})
+
+	It("fails to load a checkpoint that cannot be parsed", func() {
+		fakeBrokerServices.IteratorCheckpointReturns([]byte("not json"), true, nil)
+
+		_, err := checkpointer.Load()
+
+		Expect(err).To(MatchError(ContainSubstring("error parsing checkpoint upgrade-all-bosh")))
+	})
+
+	It("fails when the checkpoint cannot be saved", func() {
+		fakeBrokerServices.SaveIteratorCheckpointReturns(errors.New("boom"))
+
+		Expect(checkpointer.Save(instanceiterator.Checkpoint{})).To(MatchError("error writing checkpoint upgrade-all-bosh: boom"))
+	})
+
+	It("clears the checkpoint", func() {
+		Expect(checkpointer.Clear()).To(Succeed())
+
+		Expect(fakeBrokerServices.ClearIteratorCheckpointCallCount()).To(Equal(1))
+		Expect(fakeBrokerServices.ClearIteratorCheckpointArgsForCall(0)).To(Equal("upgrade-all-bosh"))
+	})
+})
@@ -19,6 +19,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/craigfurman/herottp"
@@ -27,20 +29,31 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/network"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
 	"github.com/pivotal-cf/on-demand-service-broker/tools"
 )
 
 type Configurator struct {
-	BrokerServices        BrokerServices
-	PollingInterval       time.Duration
-	AttemptInterval       time.Duration
-	AttemptLimit          int
-	MaxInFlight           int
-	Canaries              int
-	Listener              Listener
-	Sleeper               sleeper
-	Triggerer             Triggerer
-	CanarySelectionParams config.CanarySelectionParams
+	BrokerServices          BrokerServices
+	PollingInterval         time.Duration
+	AttemptInterval         time.Duration
+	AttemptLimit            int
+	MaxInFlight             int
+	Canaries                int
+	Listener                Listener
+	Sleeper                 sleeper
+	Triggerer               Triggerer
+	CanarySelectionParams   config.CanarySelectionParams
+	InstanceSelectionParams config.InstanceSelectionParams
+	Checkpointer            Checkpointer
+	Resume                  bool
+	Pauser                  Pauser
+
+	MaintenanceWindows        []MaintenanceWindow
+	WaitForMaintenanceWindows bool
+	Clock                     clock
+
+	logPrefix string
 }
 
 func NewConfigurator(conf config.InstanceIteratorConfig, logger *log.Logger, logPrefix string) (*Configurator, error) {
@@ -79,18 +92,33 @@ func NewConfigurator(conf config.InstanceIteratorConfig, logger *log.Logger, log
 		return nil, err
 	}
 
+	instanceSelectionParams, err := instanceSelectionParams(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	maintenanceWindows, err := NewMaintenanceWindows(conf.MaintenanceWindows)
+	if err != nil {
+		return nil, err
+	}
+
 	listener := NewLoggingListener(logger, logPrefix)
 
 	b := &Configurator{
-		BrokerServices:        brokerServices,
-		PollingInterval:       pollingInterval,
-		AttemptInterval:       attemptInterval,
-		AttemptLimit:          attemptLimit,
-		MaxInFlight:           maxInFlight,
-		Canaries:              canaries,
-		Listener:              listener,
-		Sleeper:               &tools.RealSleeper{},
-		CanarySelectionParams: canarySelectionParams,
+		BrokerServices:            brokerServices,
+		PollingInterval:           pollingInterval,
+		AttemptInterval:           attemptInterval,
+		AttemptLimit:              attemptLimit,
+		MaxInFlight:               maxInFlight,
+		Canaries:                  canaries,
+		Listener:                  listener,
+		Sleeper:                   &tools.RealSleeper{},
+		CanarySelectionParams:     canarySelectionParams,
+		InstanceSelectionParams:   instanceSelectionParams,
+		MaintenanceWindows:        maintenanceWindows,
+		WaitForMaintenanceWindows: conf.WaitForMaintenanceWindows,
+		Clock:                     tools.RealClock{},
+		logPrefix:                 logPrefix,
 	}
 
 	return b, nil
@@ -99,11 +127,19 @@ func NewConfigurator(conf config.InstanceIteratorConfig, logger *log.Logger, log
 func (b *Configurator) SetUpgradeTriggererToBOSH() {
 	b.Listener.UpgradeStrategy("BOSH")
 	b.Triggerer = NewBOSHUpgradeTriggerer(b.BrokerServices)
+	b.setCheckpointer("bosh")
 }
 
 func (b *Configurator) SetUpgradeTriggererToCF(cfClient CFClient, logger *log.Logger) {
 	b.Listener.UpgradeStrategy("CF")
 	b.Triggerer = NewCFTrigger(cfClient, logger)
+	b.setCheckpointer("cf")
+}
+
+func (b *Configurator) SetUpgradePreviewTriggerer(logger *log.Logger) {
+	b.Listener.UpgradeStrategy("dry run, nothing will be deployed")
+	b.Triggerer = NewUpgradePreviewTriggerer(b.BrokerServices, logger)
+	b.Checkpointer = nil
 }
 
 func (b *Configurator) SetRecreateTriggerer() error {
@@ -111,9 +147,66 @@ func (b *Configurator) SetRecreateTriggerer() error {
 		return errors.New("unable to set triggerer, brokerServices must not be nil")
 	}
 	b.Triggerer = NewRecreateTriggerer(b.BrokerServices)
+	b.setCheckpointer("bosh")
+	return nil
+}
+
+func (b *Configurator) SetRotateSecretsTriggerer(secretPaths []string) error {
+	if b.BrokerServices == nil {
+		return errors.New("unable to set triggerer, brokerServices must not be nil")
+	}
+	b.Triggerer = NewRotateSecretsTriggerer(b.BrokerServices, secretPaths)
+	b.setCheckpointer("bosh")
+	return nil
+}
+
+// SetMigratePlanTriggerer moves the instances of the source plans of the
+// migrations to their target plans. The plans are updated through CF when a
+// cfClient is given, and through the broker otherwise.
+func (b *Configurator) SetMigratePlanTriggerer(migrations []config.PlanMigration, cfClient CFClient, logger *log.Logger) error {
+	if b.BrokerServices == nil {
+		return errors.New("unable to set triggerer, brokerServices must not be nil")
+	}
+
+	catalog, err := b.BrokerServices.Catalog()
+	if err != nil {
+		return fmt.Errorf("error fetching the catalog of the broker: %s", err)
+	}
+
+	planMigrations, err := resolvePlanMigrations(catalog, migrations)
+	if err != nil {
+		return fmt.Errorf("invalid plan migrations: %s", err)
+	}
+
+	if cfClient == nil {
+		b.Triggerer = NewMigratePlanTriggerer(b.BrokerServices, planMigrations)
+		b.setCheckpointer("broker")
+		return nil
+	}
+
+	for i, migration := range planMigrations {
+		plan, err := cfClient.GetPlanByUniqueID(migration.TargetPlanID, logger)
+		if err != nil {
+			return fmt.Errorf("error finding target plan %s in CF: %s", migration.TargetPlanID, err)
+		}
+		planMigrations[i].TargetPlanGUID = plan.Metadata.GUID
+	}
+	b.Triggerer = NewCFMigratePlanTriggerer(cfClient, planMigrations, logger)
+	b.setCheckpointer("cf")
 	return nil
 }
 
+// setCheckpointer keeps the progress of each errand and upgrade strategy in its
+// own checkpoint, as a run may upgrade the instances through CF and then
+// through BOSH.
+func (b *Configurator) setCheckpointer(triggererName string) {
+	if b.BrokerServices == nil {
+		b.Checkpointer = nil
+		return
+	}
+	b.Checkpointer = NewBrokerCheckpointer(b.BrokerServices, fmt.Sprintf("%s-%s", b.logPrefix, triggererName))
+}
+
 func brokerServices(conf config.InstanceIteratorConfig, logger *log.Logger) (*services.BrokerServices, error) {
 	if conf.BrokerAPI.Authentication.Basic.Username == "" ||
 		conf.BrokerAPI.Authentication.Basic.Password == "" ||
@@ -181,5 +274,46 @@ func canaries(conf config.InstanceIteratorConfig) (int, error) {
 }
 
 func canarySelectionParams(conf config.InstanceIteratorConfig) (config.CanarySelectionParams, error) {
-	return conf.CanarySelectionParams, nil
+	return resolveGUIDFiles(conf.CanarySelectionParams)
+}
+
+func instanceSelectionParams(conf config.InstanceIteratorConfig) (config.InstanceSelectionParams, error) {
+	return resolveGUIDFiles(conf.InstanceSelectionParams)
+}
+
+// resolveGUIDFiles replaces the GUID files of the selection params with the
+// GUIDs they list, as the broker cannot read the files of the errand.
+func resolveGUIDFiles(params config.InstanceSelectionParams) (config.InstanceSelectionParams, error) {
+	guidFileKeys := map[string]string{
+		"instance_guids_file":         service.InstanceGUIDsFilterKey,
+		"exclude_instance_guids_file": service.ExcludedInstanceGUIDsFilterKey,
+	}
+
+	var resolved config.InstanceSelectionParams
+	for key, value := range params {
+		if resolved == nil {
+			resolved = config.InstanceSelectionParams{}
+		}
+		if _, isFile := guidFileKeys[key]; !isFile {
+			resolved[key] = value
+		}
+	}
+
+	for fileKey, guidsKey := range guidFileKeys {
+		path, found := params[fileKey]
+		if !found {
+			continue
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", fileKey, err)
+		}
+
+		guids := strings.Fields(string(contents))
+		if existing := resolved[guidsKey]; existing != "" {
+			guids = append([]string{existing}, guids...)
+		}
+		resolved[guidsKey] = strings.Join(guids, ",")
+	}
+	return resolved, nil
 }
@@ -16,14 +16,19 @@
 package instanceiterator_test
 
 import (
+	"errors"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
 
+	"code.cloudfoundry.org/brokerapi/v13/domain"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator/fakes"
@@ -197,6 +202,45 @@ var _ = Describe("Configurator", func() {
 		})
 	})
 
+	Describe("Instance selection", func() {
+		It("passes the instance selection params through", func() {
+			conf := newErrandConfig("user", "password", "http://example.org")
+			conf.InstanceSelectionParams = config.InstanceSelectionParams{"plan_ids": "small"}
+
+			configurator, err := instanceiterator.NewConfigurator(conf, logger, logPrefix)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configurator.InstanceSelectionParams).To(Equal(config.InstanceSelectionParams{"plan_ids": "small"}))
+		})
+
+		It("replaces the GUID files with the GUIDs they list", func() {
+			guidsFile := filepath.Join(GinkgoT().TempDir(), "failed-guids")
+			Expect(os.WriteFile(guidsFile, []byte("guid-2\nguid-3\n\n"), 0644)).To(Succeed())
+
+			conf := newErrandConfig("user", "password", "http://example.org")
+			conf.InstanceSelectionParams = config.InstanceSelectionParams{
+				"instance_guids":      "guid-1",
+				"instance_guids_file": guidsFile,
+			}
+			conf.CanarySelectionParams = config.CanarySelectionParams{"exclude_instance_guids_file": guidsFile}
+
+			configurator, err := instanceiterator.NewConfigurator(conf, logger, logPrefix)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configurator.InstanceSelectionParams).To(Equal(config.InstanceSelectionParams{"instance_guids": "guid-1,guid-2,guid-3"}))
+			Expect(configurator.CanarySelectionParams).To(Equal(config.CanarySelectionParams{"exclude_instance_guids": "guid-2,guid-3"}))
+		})
+
+		It("fails when a GUID file cannot be read", func() {
+			conf := newErrandConfig("user", "password", "http://example.org")
+			conf.InstanceSelectionParams = config.InstanceSelectionParams{"instance_guids_file": "/non-existent"}
+
+			_, err := instanceiterator.NewConfigurator(conf, logger, logPrefix)
+
+			Expect(err).To(MatchError(ContainSubstring("error reading instance_guids_file")))
+		})
+	})
+
 	Describe("SetUpgradeTriggererToBOSH", func() {
 		It("sets the triggerer to a BOSH triggerer", func() {
 			conf := newErrandConfig("user", "password", "http://example.org")
@@ -209,6 +253,66 @@ var _ = Describe("Configurator", func() {
 		})
 	})
 
+	Describe("Maintenance Windows", func() {
+		It("when configured returns the windows", func() {
+			conf := newErrandConfig("user", "password", "http://example.org")
+			conf.MaintenanceWindows = []config.MaintenanceWindow{{Plan: "some-plan", Schedule: "0 2 * * 6", Duration: "4h"}}
+			conf.WaitForMaintenanceWindows = true
+			configurator, err := instanceiterator.NewConfigurator(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(configurator.MaintenanceWindows).To(HaveLen(1))
+			Expect(configurator.MaintenanceWindows[0].Plan).To(Equal("some-plan"))
+			Expect(configurator.WaitForMaintenanceWindows).To(BeTrue())
+		})
+
+		It("fails when a window is invalid", func() {
+			conf := newErrandConfig("user", "password", "http://example.org")
+			conf.MaintenanceWindows = []config.MaintenanceWindow{{Schedule: "every saturday", Duration: "4h"}}
+			_, err := instanceiterator.NewConfigurator(conf, logger, logPrefix)
+
+			Expect(err).To(MatchError(ContainSubstring(`invalid maintenance window schedule "every saturday"`)))
+		})
+	})
+
+	Describe("checkpoints", func() {
+		var (
+			configurator       *instanceiterator.Configurator
+			fakeBrokerServices *fakes.FakeBrokerServices
+		)
+
+		BeforeEach(func() {
+			conf := newErrandConfig("user", "password", "http://example.org")
+
+			var err error
+			configurator, err = instanceiterator.NewConfigurator(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeBrokerServices = new(fakes.FakeBrokerServices)
+			configurator.BrokerServices = fakeBrokerServices
+		})
+
+		It("keeps a checkpoint per upgrade strategy in the broker", func() {
+			configurator.SetUpgradeTriggererToBOSH()
+			Expect(configurator.Checkpointer).To(Equal(instanceiterator.NewBrokerCheckpointer(fakeBrokerServices, "clean-all-bosh")))
+
+			configurator.SetUpgradeTriggererToCF(new(fakes.FakeCFClient), logger)
+			Expect(configurator.Checkpointer).To(Equal(instanceiterator.NewBrokerCheckpointer(fakeBrokerServices, "clean-all-cf")))
+
+			configurator.SetUpgradePreviewTriggerer(logger)
+			Expect(configurator.Checkpointer).To(BeNil())
+		})
+
+		It("keeps a checkpoint when recreating or rotating secrets", func() {
+			Expect(configurator.SetRecreateTriggerer()).To(Succeed())
+			Expect(configurator.Checkpointer).To(Equal(instanceiterator.NewBrokerCheckpointer(fakeBrokerServices, "clean-all-bosh")))
+
+			configurator.Checkpointer = nil
+			Expect(configurator.SetRotateSecretsTriggerer([]string{"/secret"})).To(Succeed())
+			Expect(configurator.Checkpointer).To(Equal(instanceiterator.NewBrokerCheckpointer(fakeBrokerServices, "clean-all-bosh")))
+		})
+	})
+
 	Describe("SetUpgradeTriggererToCF", func() {
 		var (
 			configurator *instanceiterator.Configurator
@@ -252,6 +356,82 @@ var _ = Describe("Configurator", func() {
 		})
 	})
 
+	Describe("SetMigratePlanTriggerer", func() {
+		var (
+			configurator       *instanceiterator.Configurator
+			fakeBrokerServices *fakes.FakeBrokerServices
+			migrations         []config.PlanMigration
+		)
+
+		BeforeEach(func() {
+			conf := newErrandConfig("user", "password", "http://example.org")
+
+			var err error
+			configurator, err = instanceiterator.NewConfigurator(conf, logger, logPrefix)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeBrokerServices = new(fakes.FakeBrokerServices)
+			fakeBrokerServices.CatalogReturns([]domain.Service{
+				{ID: "service-id", Plans: []domain.ServicePlan{{ID: "small"}, {ID: "large"}}},
+				{ID: "other-service-id", Plans: []domain.ServicePlan{{ID: "other"}}},
+			}, nil)
+			configurator.BrokerServices = fakeBrokerServices
+
+			migrations = []config.PlanMigration{{SourcePlanID: "small", TargetPlanID: "large"}}
+		})
+
+		It("migrates the plans through the broker when there is no CF client", func() {
+			Expect(configurator.SetMigratePlanTriggerer(migrations, nil, logger)).To(Succeed())
+
+			Expect(configurator.Triggerer).To(BeAssignableToTypeOf(new(instanceiterator.MigratePlanTriggerer)))
+			Expect(configurator.Checkpointer).To(Equal(instanceiterator.NewBrokerCheckpointer(fakeBrokerServices, "clean-all-broker")))
+		})
+
+		It("migrates the plans through CF to the target plans registered in CF", func() {
+			fakeCFClient := new(fakes.FakeCFClient)
+			fakeCFClient.GetPlanByUniqueIDReturns(cf.ServicePlan{Metadata: cf.Metadata{GUID: "large-guid"}}, nil)
+
+			Expect(configurator.SetMigratePlanTriggerer(migrations, fakeCFClient, logger)).To(Succeed())
+
+			Expect(configurator.Triggerer).To(BeAssignableToTypeOf(new(instanceiterator.CFMigratePlanTriggerer)))
+			Expect(configurator.Checkpointer).To(Equal(instanceiterator.NewBrokerCheckpointer(fakeBrokerServices, "clean-all-cf")))
+			planID, _ := fakeCFClient.GetPlanByUniqueIDArgsForCall(0)
+			Expect(planID).To(Equal("large"))
+		})
+
+		It("fails when the target plan is not registered in CF", func() {
+			fakeCFClient := new(fakes.FakeCFClient)
+			fakeCFClient.GetPlanByUniqueIDReturns(cf.ServicePlan{}, errors.New(`plan "large" not found`))
+
+			err := configurator.SetMigratePlanTriggerer(migrations, fakeCFClient, logger)
+
+			Expect(err).To(MatchError(`error finding target plan large in CF: plan "large" not found`))
+		})
+
+		It("fails when the catalog cannot be fetched", func() {
+			fakeBrokerServices.CatalogReturns(nil, errors.New("connection refused"))
+
+			err := configurator.SetMigratePlanTriggerer(migrations, nil, logger)
+
+			Expect(err).To(MatchError("error fetching the catalog of the broker: connection refused"))
+		})
+
+		DescribeTable("rejects invalid migrations",
+			func(migrations []config.PlanMigration, expectedError string) {
+				err := configurator.SetMigratePlanTriggerer(migrations, nil, logger)
+
+				Expect(err).To(MatchError("invalid plan migrations: " + expectedError))
+			},
+			Entry("no migrations", nil, "no plan migrations configured"),
+			Entry("missing target", []config.PlanMigration{{SourcePlanID: "small"}}, "plan migrations require a source_plan_id and a target_plan_id"),
+			Entry("same plan", []config.PlanMigration{{SourcePlanID: "small", TargetPlanID: "small"}}, "cannot migrate plan small to itself"),
+			Entry("source migrated twice", []config.PlanMigration{{SourcePlanID: "small", TargetPlanID: "large"}, {SourcePlanID: "small", TargetPlanID: "other"}}, "plan small is migrated more than once"),
+			Entry("unknown source", []config.PlanMigration{{SourcePlanID: "tiny", TargetPlanID: "large"}}, "source plan tiny not found in the catalog"),
+			Entry("unknown target", []config.PlanMigration{{SourcePlanID: "small", TargetPlanID: "huge"}}, "target plan huge not found in the catalog"),
+			Entry("other service offering", []config.PlanMigration{{SourcePlanID: "small", TargetPlanID: "other"}}, "cannot migrate plan small to plan other of another service offering"),
+		)
+	})
+
 	Describe("passing logging prefix into configurator", func() {
 		It("sets an appropriately configured logger on the configurator", func() {
 			conf := newErrandConfig("user", "password", "http://example.org")
@@ -12,6 +12,29 @@ import (
 )
 
 type FakeBrokerServices struct {
+	CatalogStub        func() ([]domain.Service, error)
+	catalogMutex       sync.RWMutex
+	catalogArgsForCall []struct {
+	}
+	catalogReturns struct {
+		result1 []domain.Service
+		result2 error
+	}
+	catalogReturnsOnCall map[int]struct {
+		result1 []domain.Service
+		result2 error
+	}
+	ClearIteratorCheckpointStub        func(string) error
+	clearIteratorCheckpointMutex       sync.RWMutex
+	clearIteratorCheckpointArgsForCall []struct {
+		arg1 string
+	}
+	clearIteratorCheckpointReturns struct {
+		result1 error
+	}
+	clearIteratorCheckpointReturnsOnCall map[int]struct {
+		result1 error
+	}
 	InstancesStub        func(map[string]string) ([]service.Instance, error)
 	instancesMutex       sync.RWMutex
 	instancesArgsForCall []struct {
@@ -25,6 +48,21 @@ type FakeBrokerServices struct {
 		result1 []service.Instance
 		result2 error
 	}
+	IteratorCheckpointStub        func(string) ([]byte, bool, error)
+	iteratorCheckpointMutex       sync.RWMutex
+	iteratorCheckpointArgsForCall []struct {
+		arg1 string
+	}
+	iteratorCheckpointReturns struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	iteratorCheckpointReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
 	LastOperationStub        func(string, broker.OperationData) (domain.LastOperation, error)
 	lastOperationMutex       sync.RWMutex
 	lastOperationArgsForCall []struct {
@@ -52,6 +90,20 @@ type FakeBrokerServices struct {
 		result1 service.Instance
 		result2 error
 	}
+	PreviewInstanceStub        func(service.Instance, string) (services.InstancePreview, error)
+	previewInstanceMutex       sync.RWMutex
+	previewInstanceArgsForCall []struct {
+		arg1 service.Instance
+		arg2 string
+	}
+	previewInstanceReturns struct {
+		result1 services.InstancePreview
+		result2 error
+	}
+	previewInstanceReturnsOnCall map[int]struct {
+		result1 services.InstancePreview
+		result2 error
+	}
 	ProcessInstanceStub        func(service.Instance, string) (services.BOSHOperation, error)
 	processInstanceMutex       sync.RWMutex
 	processInstanceArgsForCall []struct {
@@ -66,10 +118,167 @@ type FakeBrokerServices struct {
 		result1 services.BOSHOperation
 		result2 error
 	}
+	RotateSecretsStub        func(service.Instance, []string) (services.BOSHOperation, error)
+	rotateSecretsMutex       sync.RWMutex
+	rotateSecretsArgsForCall []struct {
+		arg1 service.Instance
+		arg2 []string
+	}
+	rotateSecretsReturns struct {
+		result1 services.BOSHOperation
+		result2 error
+	}
+	rotateSecretsReturnsOnCall map[int]struct {
+		result1 services.BOSHOperation
+		result2 error
+	}
+	SaveIteratorCheckpointStub        func(string, []byte) error
+	saveIteratorCheckpointMutex       sync.RWMutex
+	saveIteratorCheckpointArgsForCall []struct {
+		arg1 string
+		arg2 []byte
+	}
+	saveIteratorCheckpointReturns struct {
+		result1 error
+	}
+	saveIteratorCheckpointReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdatePlanStub        func(service.Instance, domain.UpdateDetails) (services.BOSHOperation, error)
+	updatePlanMutex       sync.RWMutex
+	updatePlanArgsForCall []struct {
+		arg1 service.Instance
+		arg2 domain.UpdateDetails
+	}
+	updatePlanReturns struct {
+		result1 services.BOSHOperation
+		result2 error
+	}
+	updatePlanReturnsOnCall map[int]struct {
+		result1 services.BOSHOperation
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeBrokerServices) Catalog() ([]domain.Service, error) {
+	fake.catalogMutex.Lock()
+	ret, specificReturn := fake.catalogReturnsOnCall[len(fake.catalogArgsForCall)]
+	fake.catalogArgsForCall = append(fake.catalogArgsForCall, struct {
+	}{})
+	stub := fake.CatalogStub
+	fakeReturns := fake.catalogReturns
+	fake.recordInvocation("Catalog", []interface{}{})
+	fake.catalogMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBrokerServices) CatalogCallCount() int {
+	fake.catalogMutex.RLock()
+	defer fake.catalogMutex.RUnlock()
+	return len(fake.catalogArgsForCall)
+}
+
+func (fake *FakeBrokerServices) CatalogCalls(stub func() ([]domain.Service, error)) {
+	fake.catalogMutex.Lock()
+	defer fake.catalogMutex.Unlock()
+	fake.CatalogStub = stub
+}
+
+func (fake *FakeBrokerServices) CatalogReturns(result1 []domain.Service, result2 error) {
+	fake.catalogMutex.Lock()
+	defer fake.catalogMutex.Unlock()
+	fake.CatalogStub = nil
+	fake.catalogReturns = struct {
+		result1 []domain.Service
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) CatalogReturnsOnCall(i int, result1 []domain.Service, result2 error) {
+	fake.catalogMutex.Lock()
+	defer fake.catalogMutex.Unlock()
+	fake.CatalogStub = nil
+	if fake.catalogReturnsOnCall == nil {
+		fake.catalogReturnsOnCall = make(map[int]struct {
+			result1 []domain.Service
+			result2 error
+		})
+	}
+	fake.catalogReturnsOnCall[i] = struct {
+		result1 []domain.Service
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) ClearIteratorCheckpoint(arg1 string) error {
+	fake.clearIteratorCheckpointMutex.Lock()
+	ret, specificReturn := fake.clearIteratorCheckpointReturnsOnCall[len(fake.clearIteratorCheckpointArgsForCall)]
+	fake.clearIteratorCheckpointArgsForCall = append(fake.clearIteratorCheckpointArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ClearIteratorCheckpointStub
+	fakeReturns := fake.clearIteratorCheckpointReturns
+	fake.recordInvocation("ClearIteratorCheckpoint", []interface{}{arg1})
+	fake.clearIteratorCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBrokerServices) ClearIteratorCheckpointCallCount() int {
+	fake.clearIteratorCheckpointMutex.RLock()
+	defer fake.clearIteratorCheckpointMutex.RUnlock()
+	return len(fake.clearIteratorCheckpointArgsForCall)
+}
+
+func (fake *FakeBrokerServices) ClearIteratorCheckpointCalls(stub func(string) error) {
+	fake.clearIteratorCheckpointMutex.Lock()
+	defer fake.clearIteratorCheckpointMutex.Unlock()
+	fake.ClearIteratorCheckpointStub = stub
+}
+
+func (fake *FakeBrokerServices) ClearIteratorCheckpointArgsForCall(i int) string {
+	fake.clearIteratorCheckpointMutex.RLock()
+	defer fake.clearIteratorCheckpointMutex.RUnlock()
+	argsForCall := fake.clearIteratorCheckpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBrokerServices) ClearIteratorCheckpointReturns(result1 error) {
+	fake.clearIteratorCheckpointMutex.Lock()
+	defer fake.clearIteratorCheckpointMutex.Unlock()
+	fake.ClearIteratorCheckpointStub = nil
+	fake.clearIteratorCheckpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBrokerServices) ClearIteratorCheckpointReturnsOnCall(i int, result1 error) {
+	fake.clearIteratorCheckpointMutex.Lock()
+	defer fake.clearIteratorCheckpointMutex.Unlock()
+	fake.ClearIteratorCheckpointStub = nil
+	if fake.clearIteratorCheckpointReturnsOnCall == nil {
+		fake.clearIteratorCheckpointReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.clearIteratorCheckpointReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBrokerServices) Instances(arg1 map[string]string) ([]service.Instance, error) {
 	fake.instancesMutex.Lock()
 	ret, specificReturn := fake.instancesReturnsOnCall[len(fake.instancesArgsForCall)]
@@ -134,6 +343,73 @@ func (fake *FakeBrokerServices) InstancesReturnsOnCall(i int, result1 []service.
 	}{result1, result2}
 }
 
+func (fake *FakeBrokerServices) IteratorCheckpoint(arg1 string) ([]byte, bool, error) {
+	fake.iteratorCheckpointMutex.Lock()
+	ret, specificReturn := fake.iteratorCheckpointReturnsOnCall[len(fake.iteratorCheckpointArgsForCall)]
+	fake.iteratorCheckpointArgsForCall = append(fake.iteratorCheckpointArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.IteratorCheckpointStub
+	fakeReturns := fake.iteratorCheckpointReturns
+	fake.recordInvocation("IteratorCheckpoint", []interface{}{arg1})
+	fake.iteratorCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeBrokerServices) IteratorCheckpointCallCount() int {
+	fake.iteratorCheckpointMutex.RLock()
+	defer fake.iteratorCheckpointMutex.RUnlock()
+	return len(fake.iteratorCheckpointArgsForCall)
+}
+
+func (fake *FakeBrokerServices) IteratorCheckpointCalls(stub func(string) ([]byte, bool, error)) {
+	fake.iteratorCheckpointMutex.Lock()
+	defer fake.iteratorCheckpointMutex.Unlock()
+	fake.IteratorCheckpointStub = stub
+}
+
+func (fake *FakeBrokerServices) IteratorCheckpointArgsForCall(i int) string {
+	fake.iteratorCheckpointMutex.RLock()
+	defer fake.iteratorCheckpointMutex.RUnlock()
+	argsForCall := fake.iteratorCheckpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeBrokerServices) IteratorCheckpointReturns(result1 []byte, result2 bool, result3 error) {
+	fake.iteratorCheckpointMutex.Lock()
+	defer fake.iteratorCheckpointMutex.Unlock()
+	fake.IteratorCheckpointStub = nil
+	fake.iteratorCheckpointReturns = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBrokerServices) IteratorCheckpointReturnsOnCall(i int, result1 []byte, result2 bool, result3 error) {
+	fake.iteratorCheckpointMutex.Lock()
+	defer fake.iteratorCheckpointMutex.Unlock()
+	fake.IteratorCheckpointStub = nil
+	if fake.iteratorCheckpointReturnsOnCall == nil {
+		fake.iteratorCheckpointReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 bool
+			result3 error
+		})
+	}
+	fake.iteratorCheckpointReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeBrokerServices) LastOperation(arg1 string, arg2 broker.OperationData) (domain.LastOperation, error) {
 	fake.lastOperationMutex.Lock()
 	ret, specificReturn := fake.lastOperationReturnsOnCall[len(fake.lastOperationArgsForCall)]
@@ -263,6 +539,71 @@ func (fake *FakeBrokerServices) LatestInstanceInfoReturnsOnCall(i int, result1 s
 	}{result1, result2}
 }
 
+func (fake *FakeBrokerServices) PreviewInstance(arg1 service.Instance, arg2 string) (services.InstancePreview, error) {
+	fake.previewInstanceMutex.Lock()
+	ret, specificReturn := fake.previewInstanceReturnsOnCall[len(fake.previewInstanceArgsForCall)]
+	fake.previewInstanceArgsForCall = append(fake.previewInstanceArgsForCall, struct {
+		arg1 service.Instance
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.PreviewInstanceStub
+	fakeReturns := fake.previewInstanceReturns
+	fake.recordInvocation("PreviewInstance", []interface{}{arg1, arg2})
+	fake.previewInstanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBrokerServices) PreviewInstanceCallCount() int {
+	fake.previewInstanceMutex.RLock()
+	defer fake.previewInstanceMutex.RUnlock()
+	return len(fake.previewInstanceArgsForCall)
+}
+
+func (fake *FakeBrokerServices) PreviewInstanceCalls(stub func(service.Instance, string) (services.InstancePreview, error)) {
+	fake.previewInstanceMutex.Lock()
+	defer fake.previewInstanceMutex.Unlock()
+	fake.PreviewInstanceStub = stub
+}
+
+func (fake *FakeBrokerServices) PreviewInstanceArgsForCall(i int) (service.Instance, string) {
+	fake.previewInstanceMutex.RLock()
+	defer fake.previewInstanceMutex.RUnlock()
+	argsForCall := fake.previewInstanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBrokerServices) PreviewInstanceReturns(result1 services.InstancePreview, result2 error) {
+	fake.previewInstanceMutex.Lock()
+	defer fake.previewInstanceMutex.Unlock()
+	fake.PreviewInstanceStub = nil
+	fake.previewInstanceReturns = struct {
+		result1 services.InstancePreview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) PreviewInstanceReturnsOnCall(i int, result1 services.InstancePreview, result2 error) {
+	fake.previewInstanceMutex.Lock()
+	defer fake.previewInstanceMutex.Unlock()
+	fake.PreviewInstanceStub = nil
+	if fake.previewInstanceReturnsOnCall == nil {
+		fake.previewInstanceReturnsOnCall = make(map[int]struct {
+			result1 services.InstancePreview
+			result2 error
+		})
+	}
+	fake.previewInstanceReturnsOnCall[i] = struct {
+		result1 services.InstancePreview
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBrokerServices) ProcessInstance(arg1 service.Instance, arg2 string) (services.BOSHOperation, error) {
 	fake.processInstanceMutex.Lock()
 	ret, specificReturn := fake.processInstanceReturnsOnCall[len(fake.processInstanceArgsForCall)]
@@ -328,17 +669,233 @@ func (fake *FakeBrokerServices) ProcessInstanceReturnsOnCall(i int, result1 serv
 	}{result1, result2}
 }
 
+func (fake *FakeBrokerServices) RotateSecrets(arg1 service.Instance, arg2 []string) (services.BOSHOperation, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.rotateSecretsMutex.Lock()
+	ret, specificReturn := fake.rotateSecretsReturnsOnCall[len(fake.rotateSecretsArgsForCall)]
+	fake.rotateSecretsArgsForCall = append(fake.rotateSecretsArgsForCall, struct {
+		arg1 service.Instance
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.RotateSecretsStub
+	fakeReturns := fake.rotateSecretsReturns
+	fake.recordInvocation("RotateSecrets", []interface{}{arg1, arg2Copy})
+	fake.rotateSecretsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBrokerServices) RotateSecretsCallCount() int {
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	return len(fake.rotateSecretsArgsForCall)
+}
+
+func (fake *FakeBrokerServices) RotateSecretsCalls(stub func(service.Instance, []string) (services.BOSHOperation, error)) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = stub
+}
+
+func (fake *FakeBrokerServices) RotateSecretsArgsForCall(i int) (service.Instance, []string) {
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	argsForCall := fake.rotateSecretsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBrokerServices) RotateSecretsReturns(result1 services.BOSHOperation, result2 error) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = nil
+	fake.rotateSecretsReturns = struct {
+		result1 services.BOSHOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) RotateSecretsReturnsOnCall(i int, result1 services.BOSHOperation, result2 error) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = nil
+	if fake.rotateSecretsReturnsOnCall == nil {
+		fake.rotateSecretsReturnsOnCall = make(map[int]struct {
+			result1 services.BOSHOperation
+			result2 error
+		})
+	}
+	fake.rotateSecretsReturnsOnCall[i] = struct {
+		result1 services.BOSHOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) SaveIteratorCheckpoint(arg1 string, arg2 []byte) error {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.saveIteratorCheckpointMutex.Lock()
+	ret, specificReturn := fake.saveIteratorCheckpointReturnsOnCall[len(fake.saveIteratorCheckpointArgsForCall)]
+	fake.saveIteratorCheckpointArgsForCall = append(fake.saveIteratorCheckpointArgsForCall, struct {
+		arg1 string
+		arg2 []byte
+	}{arg1, arg2Copy})
+	stub := fake.SaveIteratorCheckpointStub
+	fakeReturns := fake.saveIteratorCheckpointReturns
+	fake.recordInvocation("SaveIteratorCheckpoint", []interface{}{arg1, arg2Copy})
+	fake.saveIteratorCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeBrokerServices) SaveIteratorCheckpointCallCount() int {
+	fake.saveIteratorCheckpointMutex.RLock()
+	defer fake.saveIteratorCheckpointMutex.RUnlock()
+	return len(fake.saveIteratorCheckpointArgsForCall)
+}
+
+func (fake *FakeBrokerServices) SaveIteratorCheckpointCalls(stub func(string, []byte) error) {
+	fake.saveIteratorCheckpointMutex.Lock()
+	defer fake.saveIteratorCheckpointMutex.Unlock()
+	fake.SaveIteratorCheckpointStub = stub
+}
+
+func (fake *FakeBrokerServices) SaveIteratorCheckpointArgsForCall(i int) (string, []byte) {
+	fake.saveIteratorCheckpointMutex.RLock()
+	defer fake.saveIteratorCheckpointMutex.RUnlock()
+	argsForCall := fake.saveIteratorCheckpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBrokerServices) SaveIteratorCheckpointReturns(result1 error) {
+	fake.saveIteratorCheckpointMutex.Lock()
+	defer fake.saveIteratorCheckpointMutex.Unlock()
+	fake.SaveIteratorCheckpointStub = nil
+	fake.saveIteratorCheckpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBrokerServices) SaveIteratorCheckpointReturnsOnCall(i int, result1 error) {
+	fake.saveIteratorCheckpointMutex.Lock()
+	defer fake.saveIteratorCheckpointMutex.Unlock()
+	fake.SaveIteratorCheckpointStub = nil
+	if fake.saveIteratorCheckpointReturnsOnCall == nil {
+		fake.saveIteratorCheckpointReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveIteratorCheckpointReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBrokerServices) UpdatePlan(arg1 service.Instance, arg2 domain.UpdateDetails) (services.BOSHOperation, error) {
+	fake.updatePlanMutex.Lock()
+	ret, specificReturn := fake.updatePlanReturnsOnCall[len(fake.updatePlanArgsForCall)]
+	fake.updatePlanArgsForCall = append(fake.updatePlanArgsForCall, struct {
+		arg1 service.Instance
+		arg2 domain.UpdateDetails
+	}{arg1, arg2})
+	stub := fake.UpdatePlanStub
+	fakeReturns := fake.updatePlanReturns
+	fake.recordInvocation("UpdatePlan", []interface{}{arg1, arg2})
+	fake.updatePlanMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBrokerServices) UpdatePlanCallCount() int {
+	fake.updatePlanMutex.RLock()
+	defer fake.updatePlanMutex.RUnlock()
+	return len(fake.updatePlanArgsForCall)
+}
+
+func (fake *FakeBrokerServices) UpdatePlanCalls(stub func(service.Instance, domain.UpdateDetails) (services.BOSHOperation, error)) {
+	fake.updatePlanMutex.Lock()
+	defer fake.updatePlanMutex.Unlock()
+	fake.UpdatePlanStub = stub
+}
+
+func (fake *FakeBrokerServices) UpdatePlanArgsForCall(i int) (service.Instance, domain.UpdateDetails) {
+	fake.updatePlanMutex.RLock()
+	defer fake.updatePlanMutex.RUnlock()
+	argsForCall := fake.updatePlanArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeBrokerServices) UpdatePlanReturns(result1 services.BOSHOperation, result2 error) {
+	fake.updatePlanMutex.Lock()
+	defer fake.updatePlanMutex.Unlock()
+	fake.UpdatePlanStub = nil
+	fake.updatePlanReturns = struct {
+		result1 services.BOSHOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) UpdatePlanReturnsOnCall(i int, result1 services.BOSHOperation, result2 error) {
+	fake.updatePlanMutex.Lock()
+	defer fake.updatePlanMutex.Unlock()
+	fake.UpdatePlanStub = nil
+	if fake.updatePlanReturnsOnCall == nil {
+		fake.updatePlanReturnsOnCall = make(map[int]struct {
+			result1 services.BOSHOperation
+			result2 error
+		})
+	}
+	fake.updatePlanReturnsOnCall[i] = struct {
+		result1 services.BOSHOperation
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBrokerServices) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.catalogMutex.RLock()
+	defer fake.catalogMutex.RUnlock()
+	fake.clearIteratorCheckpointMutex.RLock()
+	defer fake.clearIteratorCheckpointMutex.RUnlock()
 	fake.instancesMutex.RLock()
 	defer fake.instancesMutex.RUnlock()
+	fake.iteratorCheckpointMutex.RLock()
+	defer fake.iteratorCheckpointMutex.RUnlock()
 	fake.lastOperationMutex.RLock()
 	defer fake.lastOperationMutex.RUnlock()
 	fake.latestInstanceInfoMutex.RLock()
 	defer fake.latestInstanceInfoMutex.RUnlock()
+	fake.previewInstanceMutex.RLock()
+	defer fake.previewInstanceMutex.RUnlock()
 	fake.processInstanceMutex.RLock()
 	defer fake.processInstanceMutex.RUnlock()
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	fake.saveIteratorCheckpointMutex.RLock()
+	defer fake.saveIteratorCheckpointMutex.RUnlock()
+	fake.updatePlanMutex.RLock()
+	defer fake.updatePlanMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -50,6 +50,20 @@ type FakeCFClient struct {
 		result1 cf.ServicePlan
 		result2 error
 	}
+	GetPlanByUniqueIDStub        func(string, *log.Logger) (cf.ServicePlan, error)
+	getPlanByUniqueIDMutex       sync.RWMutex
+	getPlanByUniqueIDArgsForCall []struct {
+		arg1 string
+		arg2 *log.Logger
+	}
+	getPlanByUniqueIDReturns struct {
+		result1 cf.ServicePlan
+		result2 error
+	}
+	getPlanByUniqueIDReturnsOnCall map[int]struct {
+		result1 cf.ServicePlan
+		result2 error
+	}
 	GetServiceInstanceStub        func(string, *log.Logger) (cf.ServiceInstanceResource, error)
 	getServiceInstanceMutex       sync.RWMutex
 	getServiceInstanceArgsForCall []struct {
@@ -64,6 +78,22 @@ type FakeCFClient struct {
 		result1 cf.ServiceInstanceResource
 		result2 error
 	}
+	UpdateServiceInstancePlanStub        func(string, string, map[string]interface{}, *log.Logger) (cf.LastOperation, error)
+	updateServiceInstancePlanMutex       sync.RWMutex
+	updateServiceInstancePlanArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 map[string]interface{}
+		arg4 *log.Logger
+	}
+	updateServiceInstancePlanReturns struct {
+		result1 cf.LastOperation
+		result2 error
+	}
+	updateServiceInstancePlanReturnsOnCall map[int]struct {
+		result1 cf.LastOperation
+		result2 error
+	}
 	UpgradeServiceInstanceStub        func(string, cf.MaintenanceInfo, *log.Logger) (cf.LastOperation, error)
 	upgradeServiceInstanceMutex       sync.RWMutex
 	upgradeServiceInstanceArgsForCall []struct {
@@ -275,6 +305,71 @@ func (fake *FakeCFClient) GetPlanByServiceInstanceGUIDReturnsOnCall(i int, resul
 	}{result1, result2}
 }
 
+func (fake *FakeCFClient) GetPlanByUniqueID(arg1 string, arg2 *log.Logger) (cf.ServicePlan, error) {
+	fake.getPlanByUniqueIDMutex.Lock()
+	ret, specificReturn := fake.getPlanByUniqueIDReturnsOnCall[len(fake.getPlanByUniqueIDArgsForCall)]
+	fake.getPlanByUniqueIDArgsForCall = append(fake.getPlanByUniqueIDArgsForCall, struct {
+		arg1 string
+		arg2 *log.Logger
+	}{arg1, arg2})
+	stub := fake.GetPlanByUniqueIDStub
+	fakeReturns := fake.getPlanByUniqueIDReturns
+	fake.recordInvocation("GetPlanByUniqueID", []interface{}{arg1, arg2})
+	fake.getPlanByUniqueIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCFClient) GetPlanByUniqueIDCallCount() int {
+	fake.getPlanByUniqueIDMutex.RLock()
+	defer fake.getPlanByUniqueIDMutex.RUnlock()
+	return len(fake.getPlanByUniqueIDArgsForCall)
+}
+
+func (fake *FakeCFClient) GetPlanByUniqueIDCalls(stub func(string, *log.Logger) (cf.ServicePlan, error)) {
+	fake.getPlanByUniqueIDMutex.Lock()
+	defer fake.getPlanByUniqueIDMutex.Unlock()
+	fake.GetPlanByUniqueIDStub = stub
+}
+
+func (fake *FakeCFClient) GetPlanByUniqueIDArgsForCall(i int) (string, *log.Logger) {
+	fake.getPlanByUniqueIDMutex.RLock()
+	defer fake.getPlanByUniqueIDMutex.RUnlock()
+	argsForCall := fake.getPlanByUniqueIDArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCFClient) GetPlanByUniqueIDReturns(result1 cf.ServicePlan, result2 error) {
+	fake.getPlanByUniqueIDMutex.Lock()
+	defer fake.getPlanByUniqueIDMutex.Unlock()
+	fake.GetPlanByUniqueIDStub = nil
+	fake.getPlanByUniqueIDReturns = struct {
+		result1 cf.ServicePlan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) GetPlanByUniqueIDReturnsOnCall(i int, result1 cf.ServicePlan, result2 error) {
+	fake.getPlanByUniqueIDMutex.Lock()
+	defer fake.getPlanByUniqueIDMutex.Unlock()
+	fake.GetPlanByUniqueIDStub = nil
+	if fake.getPlanByUniqueIDReturnsOnCall == nil {
+		fake.getPlanByUniqueIDReturnsOnCall = make(map[int]struct {
+			result1 cf.ServicePlan
+			result2 error
+		})
+	}
+	fake.getPlanByUniqueIDReturnsOnCall[i] = struct {
+		result1 cf.ServicePlan
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCFClient) GetServiceInstance(arg1 string, arg2 *log.Logger) (cf.ServiceInstanceResource, error) {
 	fake.getServiceInstanceMutex.Lock()
 	ret, specificReturn := fake.getServiceInstanceReturnsOnCall[len(fake.getServiceInstanceArgsForCall)]
@@ -340,6 +435,73 @@ func (fake *FakeCFClient) GetServiceInstanceReturnsOnCall(i int, result1 cf.Serv
 	}{result1, result2}
 }
 
+func (fake *FakeCFClient) UpdateServiceInstancePlan(arg1 string, arg2 string, arg3 map[string]interface{}, arg4 *log.Logger) (cf.LastOperation, error) {
+	fake.updateServiceInstancePlanMutex.Lock()
+	ret, specificReturn := fake.updateServiceInstancePlanReturnsOnCall[len(fake.updateServiceInstancePlanArgsForCall)]
+	fake.updateServiceInstancePlanArgsForCall = append(fake.updateServiceInstancePlanArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 map[string]interface{}
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UpdateServiceInstancePlanStub
+	fakeReturns := fake.updateServiceInstancePlanReturns
+	fake.recordInvocation("UpdateServiceInstancePlan", []interface{}{arg1, arg2, arg3, arg4})
+	fake.updateServiceInstancePlanMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCFClient) UpdateServiceInstancePlanCallCount() int {
+	fake.updateServiceInstancePlanMutex.RLock()
+	defer fake.updateServiceInstancePlanMutex.RUnlock()
+	return len(fake.updateServiceInstancePlanArgsForCall)
+}
+
+func (fake *FakeCFClient) UpdateServiceInstancePlanCalls(stub func(string, string, map[string]interface{}, *log.Logger) (cf.LastOperation, error)) {
+	fake.updateServiceInstancePlanMutex.Lock()
+	defer fake.updateServiceInstancePlanMutex.Unlock()
+	fake.UpdateServiceInstancePlanStub = stub
+}
+
+func (fake *FakeCFClient) UpdateServiceInstancePlanArgsForCall(i int) (string, string, map[string]interface{}, *log.Logger) {
+	fake.updateServiceInstancePlanMutex.RLock()
+	defer fake.updateServiceInstancePlanMutex.RUnlock()
+	argsForCall := fake.updateServiceInstancePlanArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeCFClient) UpdateServiceInstancePlanReturns(result1 cf.LastOperation, result2 error) {
+	fake.updateServiceInstancePlanMutex.Lock()
+	defer fake.updateServiceInstancePlanMutex.Unlock()
+	fake.UpdateServiceInstancePlanStub = nil
+	fake.updateServiceInstancePlanReturns = struct {
+		result1 cf.LastOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCFClient) UpdateServiceInstancePlanReturnsOnCall(i int, result1 cf.LastOperation, result2 error) {
+	fake.updateServiceInstancePlanMutex.Lock()
+	defer fake.updateServiceInstancePlanMutex.Unlock()
+	fake.UpdateServiceInstancePlanStub = nil
+	if fake.updateServiceInstancePlanReturnsOnCall == nil {
+		fake.updateServiceInstancePlanReturnsOnCall = make(map[int]struct {
+			result1 cf.LastOperation
+			result2 error
+		})
+	}
+	fake.updateServiceInstancePlanReturnsOnCall[i] = struct {
+		result1 cf.LastOperation
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCFClient) UpgradeServiceInstance(arg1 string, arg2 cf.MaintenanceInfo, arg3 *log.Logger) (cf.LastOperation, error) {
 	fake.upgradeServiceInstanceMutex.Lock()
 	ret, specificReturn := fake.upgradeServiceInstanceReturnsOnCall[len(fake.upgradeServiceInstanceArgsForCall)]
@@ -415,8 +577,12 @@ func (fake *FakeCFClient) Invocations() map[string][][]interface{} {
 	defer fake.getLastOperationForInstanceMutex.RUnlock()
 	fake.getPlanByServiceInstanceGUIDMutex.RLock()
 	defer fake.getPlanByServiceInstanceGUIDMutex.RUnlock()
+	fake.getPlanByUniqueIDMutex.RLock()
+	defer fake.getPlanByUniqueIDMutex.RUnlock()
 	fake.getServiceInstanceMutex.RLock()
 	defer fake.getServiceInstanceMutex.RUnlock()
+	fake.updateServiceInstancePlanMutex.RLock()
+	defer fake.updateServiceInstancePlanMutex.RUnlock()
 	fake.upgradeServiceInstanceMutex.RLock()
 	defer fake.upgradeServiceInstanceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
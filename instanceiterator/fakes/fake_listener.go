@@ -26,7 +26,12 @@ type FakeListener struct {
 	failedToRefreshInstanceInfoArgsForCall []struct {
 		arg1 string
 	}
-	FinishedStub        func(int, int, int, int, []string, []string)
+	FailedToSaveCheckpointStub        func(error)
+	failedToSaveCheckpointMutex       sync.RWMutex
+	failedToSaveCheckpointArgsForCall []struct {
+		arg1 error
+	}
+	FinishedStub        func(int, int, int, int, []string, []string, []string)
 	finishedMutex       sync.RWMutex
 	finishedArgsForCall []struct {
 		arg1 int
@@ -35,6 +40,7 @@ type FakeListener struct {
 		arg4 int
 		arg5 []string
 		arg6 []string
+		arg7 []string
 	}
 	InstanceOperationFinishedStub        func(string, string)
 	instanceOperationFinishedMutex       sync.RWMutex
@@ -56,11 +62,26 @@ type FakeListener struct {
 		arg3 int
 		arg4 bool
 	}
+	InstancesPreviewedStub        func([]string)
+	instancesPreviewedMutex       sync.RWMutex
+	instancesPreviewedArgsForCall []struct {
+		arg1 []string
+	}
 	InstancesToProcessStub        func([]service.Instance)
 	instancesToProcessMutex       sync.RWMutex
 	instancesToProcessArgsForCall []struct {
 		arg1 []service.Instance
 	}
+	OutsideMaintenanceWindowStub        func(string)
+	outsideMaintenanceWindowMutex       sync.RWMutex
+	outsideMaintenanceWindowArgsForCall []struct {
+		arg1 string
+	}
+	PausedStub        func(int)
+	pausedMutex       sync.RWMutex
+	pausedArgsForCall []struct {
+		arg1 int
+	}
 	ProgressStub        func(time.Duration, int, int, int, int, int)
 	progressMutex       sync.RWMutex
 	progressArgsForCall []struct {
@@ -71,6 +92,11 @@ type FakeListener struct {
 		arg5 int
 		arg6 int
 	}
+	ResumingFromCheckpointStub        func(int)
+	resumingFromCheckpointMutex       sync.RWMutex
+	resumingFromCheckpointArgsForCall []struct {
+		arg1 int
+	}
 	RetryAttemptStub        func(int, int)
 	retryAttemptMutex       sync.RWMutex
 	retryAttemptArgsForCall []struct {
@@ -89,6 +115,10 @@ type FakeListener struct {
 	startingArgsForCall []struct {
 		arg1 int
 	}
+	UnpausedStub        func()
+	unpausedMutex       sync.RWMutex
+	unpausedArgsForCall []struct {
+	}
 	UpgradeStrategyStub        func(string)
 	upgradeStrategyMutex       sync.RWMutex
 	upgradeStrategyArgsForCall []struct {
@@ -100,6 +130,11 @@ type FakeListener struct {
 		arg1 string
 		arg2 int
 	}
+	WaitingForMaintenanceWindowsStub        func(int)
+	waitingForMaintenanceWindowsMutex       sync.RWMutex
+	waitingForMaintenanceWindowsArgsForCall []struct {
+		arg1 int
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -193,7 +228,39 @@ func (fake *FakeListener) FailedToRefreshInstanceInfoArgsForCall(i int) string {
 	return argsForCall.arg1
 }
 
-func (fake *FakeListener) Finished(arg1 int, arg2 int, arg3 int, arg4 int, arg5 []string, arg6 []string) {
+func (fake *FakeListener) FailedToSaveCheckpoint(arg1 error) {
+	fake.failedToSaveCheckpointMutex.Lock()
+	fake.failedToSaveCheckpointArgsForCall = append(fake.failedToSaveCheckpointArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.FailedToSaveCheckpointStub
+	fake.recordInvocation("FailedToSaveCheckpoint", []interface{}{arg1})
+	fake.failedToSaveCheckpointMutex.Unlock()
+	if stub != nil {
+		fake.FailedToSaveCheckpointStub(arg1)
+	}
+}
+
+func (fake *FakeListener) FailedToSaveCheckpointCallCount() int {
+	fake.failedToSaveCheckpointMutex.RLock()
+	defer fake.failedToSaveCheckpointMutex.RUnlock()
+	return len(fake.failedToSaveCheckpointArgsForCall)
+}
+
+func (fake *FakeListener) FailedToSaveCheckpointCalls(stub func(error)) {
+	fake.failedToSaveCheckpointMutex.Lock()
+	defer fake.failedToSaveCheckpointMutex.Unlock()
+	fake.FailedToSaveCheckpointStub = stub
+}
+
+func (fake *FakeListener) FailedToSaveCheckpointArgsForCall(i int) error {
+	fake.failedToSaveCheckpointMutex.RLock()
+	defer fake.failedToSaveCheckpointMutex.RUnlock()
+	argsForCall := fake.failedToSaveCheckpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeListener) Finished(arg1 int, arg2 int, arg3 int, arg4 int, arg5 []string, arg6 []string, arg7 []string) {
 	var arg5Copy []string
 	if arg5 != nil {
 		arg5Copy = make([]string, len(arg5))
@@ -204,6 +271,11 @@ func (fake *FakeListener) Finished(arg1 int, arg2 int, arg3 int, arg4 int, arg5
 		arg6Copy = make([]string, len(arg6))
 		copy(arg6Copy, arg6)
 	}
+	var arg7Copy []string
+	if arg7 != nil {
+		arg7Copy = make([]string, len(arg7))
+		copy(arg7Copy, arg7)
+	}
 	fake.finishedMutex.Lock()
 	fake.finishedArgsForCall = append(fake.finishedArgsForCall, struct {
 		arg1 int
@@ -212,12 +284,13 @@ func (fake *FakeListener) Finished(arg1 int, arg2 int, arg3 int, arg4 int, arg5
 		arg4 int
 		arg5 []string
 		arg6 []string
-	}{arg1, arg2, arg3, arg4, arg5Copy, arg6Copy})
+		arg7 []string
+	}{arg1, arg2, arg3, arg4, arg5Copy, arg6Copy, arg7Copy})
 	stub := fake.FinishedStub
-	fake.recordInvocation("Finished", []interface{}{arg1, arg2, arg3, arg4, arg5Copy, arg6Copy})
+	fake.recordInvocation("Finished", []interface{}{arg1, arg2, arg3, arg4, arg5Copy, arg6Copy, arg7Copy})
 	fake.finishedMutex.Unlock()
 	if stub != nil {
-		fake.FinishedStub(arg1, arg2, arg3, arg4, arg5, arg6)
+		fake.FinishedStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	}
 }
 
@@ -227,17 +300,17 @@ func (fake *FakeListener) FinishedCallCount() int {
 	return len(fake.finishedArgsForCall)
 }
 
-func (fake *FakeListener) FinishedCalls(stub func(int, int, int, int, []string, []string)) {
+func (fake *FakeListener) FinishedCalls(stub func(int, int, int, int, []string, []string, []string)) {
 	fake.finishedMutex.Lock()
 	defer fake.finishedMutex.Unlock()
 	fake.FinishedStub = stub
 }
 
-func (fake *FakeListener) FinishedArgsForCall(i int) (int, int, int, int, []string, []string) {
+func (fake *FakeListener) FinishedArgsForCall(i int) (int, int, int, int, []string, []string, []string) {
 	fake.finishedMutex.RLock()
 	defer fake.finishedMutex.RUnlock()
 	argsForCall := fake.finishedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
 func (fake *FakeListener) InstanceOperationFinished(arg1 string, arg2 string) {
@@ -341,6 +414,43 @@ func (fake *FakeListener) InstanceOperationStartingArgsForCall(i int) (string, i
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
+func (fake *FakeListener) InstancesPreviewed(arg1 []string) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.instancesPreviewedMutex.Lock()
+	fake.instancesPreviewedArgsForCall = append(fake.instancesPreviewedArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	stub := fake.InstancesPreviewedStub
+	fake.recordInvocation("InstancesPreviewed", []interface{}{arg1Copy})
+	fake.instancesPreviewedMutex.Unlock()
+	if stub != nil {
+		fake.InstancesPreviewedStub(arg1)
+	}
+}
+
+func (fake *FakeListener) InstancesPreviewedCallCount() int {
+	fake.instancesPreviewedMutex.RLock()
+	defer fake.instancesPreviewedMutex.RUnlock()
+	return len(fake.instancesPreviewedArgsForCall)
+}
+
+func (fake *FakeListener) InstancesPreviewedCalls(stub func([]string)) {
+	fake.instancesPreviewedMutex.Lock()
+	defer fake.instancesPreviewedMutex.Unlock()
+	fake.InstancesPreviewedStub = stub
+}
+
+func (fake *FakeListener) InstancesPreviewedArgsForCall(i int) []string {
+	fake.instancesPreviewedMutex.RLock()
+	defer fake.instancesPreviewedMutex.RUnlock()
+	argsForCall := fake.instancesPreviewedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeListener) InstancesToProcess(arg1 []service.Instance) {
 	var arg1Copy []service.Instance
 	if arg1 != nil {
@@ -378,6 +488,70 @@ func (fake *FakeListener) InstancesToProcessArgsForCall(i int) []service.Instanc
 	return argsForCall.arg1
 }
 
+func (fake *FakeListener) OutsideMaintenanceWindow(arg1 string) {
+	fake.outsideMaintenanceWindowMutex.Lock()
+	fake.outsideMaintenanceWindowArgsForCall = append(fake.outsideMaintenanceWindowArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.OutsideMaintenanceWindowStub
+	fake.recordInvocation("OutsideMaintenanceWindow", []interface{}{arg1})
+	fake.outsideMaintenanceWindowMutex.Unlock()
+	if stub != nil {
+		fake.OutsideMaintenanceWindowStub(arg1)
+	}
+}
+
+func (fake *FakeListener) OutsideMaintenanceWindowCallCount() int {
+	fake.outsideMaintenanceWindowMutex.RLock()
+	defer fake.outsideMaintenanceWindowMutex.RUnlock()
+	return len(fake.outsideMaintenanceWindowArgsForCall)
+}
+
+func (fake *FakeListener) OutsideMaintenanceWindowCalls(stub func(string)) {
+	fake.outsideMaintenanceWindowMutex.Lock()
+	defer fake.outsideMaintenanceWindowMutex.Unlock()
+	fake.OutsideMaintenanceWindowStub = stub
+}
+
+func (fake *FakeListener) OutsideMaintenanceWindowArgsForCall(i int) string {
+	fake.outsideMaintenanceWindowMutex.RLock()
+	defer fake.outsideMaintenanceWindowMutex.RUnlock()
+	argsForCall := fake.outsideMaintenanceWindowArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeListener) Paused(arg1 int) {
+	fake.pausedMutex.Lock()
+	fake.pausedArgsForCall = append(fake.pausedArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.PausedStub
+	fake.recordInvocation("Paused", []interface{}{arg1})
+	fake.pausedMutex.Unlock()
+	if stub != nil {
+		fake.PausedStub(arg1)
+	}
+}
+
+func (fake *FakeListener) PausedCallCount() int {
+	fake.pausedMutex.RLock()
+	defer fake.pausedMutex.RUnlock()
+	return len(fake.pausedArgsForCall)
+}
+
+func (fake *FakeListener) PausedCalls(stub func(int)) {
+	fake.pausedMutex.Lock()
+	defer fake.pausedMutex.Unlock()
+	fake.PausedStub = stub
+}
+
+func (fake *FakeListener) PausedArgsForCall(i int) int {
+	fake.pausedMutex.RLock()
+	defer fake.pausedMutex.RUnlock()
+	argsForCall := fake.pausedArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeListener) Progress(arg1 time.Duration, arg2 int, arg3 int, arg4 int, arg5 int, arg6 int) {
 	fake.progressMutex.Lock()
 	fake.progressArgsForCall = append(fake.progressArgsForCall, struct {
@@ -415,6 +589,38 @@ func (fake *FakeListener) ProgressArgsForCall(i int) (time.Duration, int, int, i
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
+func (fake *FakeListener) ResumingFromCheckpoint(arg1 int) {
+	fake.resumingFromCheckpointMutex.Lock()
+	fake.resumingFromCheckpointArgsForCall = append(fake.resumingFromCheckpointArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.ResumingFromCheckpointStub
+	fake.recordInvocation("ResumingFromCheckpoint", []interface{}{arg1})
+	fake.resumingFromCheckpointMutex.Unlock()
+	if stub != nil {
+		fake.ResumingFromCheckpointStub(arg1)
+	}
+}
+
+func (fake *FakeListener) ResumingFromCheckpointCallCount() int {
+	fake.resumingFromCheckpointMutex.RLock()
+	defer fake.resumingFromCheckpointMutex.RUnlock()
+	return len(fake.resumingFromCheckpointArgsForCall)
+}
+
+func (fake *FakeListener) ResumingFromCheckpointCalls(stub func(int)) {
+	fake.resumingFromCheckpointMutex.Lock()
+	defer fake.resumingFromCheckpointMutex.Unlock()
+	fake.ResumingFromCheckpointStub = stub
+}
+
+func (fake *FakeListener) ResumingFromCheckpointArgsForCall(i int) int {
+	fake.resumingFromCheckpointMutex.RLock()
+	defer fake.resumingFromCheckpointMutex.RUnlock()
+	argsForCall := fake.resumingFromCheckpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeListener) RetryAttempt(arg1 int, arg2 int) {
 	fake.retryAttemptMutex.Lock()
 	fake.retryAttemptArgsForCall = append(fake.retryAttemptArgsForCall, struct {
@@ -514,6 +720,30 @@ func (fake *FakeListener) StartingArgsForCall(i int) int {
 	return argsForCall.arg1
 }
 
+func (fake *FakeListener) Unpaused() {
+	fake.unpausedMutex.Lock()
+	fake.unpausedArgsForCall = append(fake.unpausedArgsForCall, struct {
+	}{})
+	stub := fake.UnpausedStub
+	fake.recordInvocation("Unpaused", []interface{}{})
+	fake.unpausedMutex.Unlock()
+	if stub != nil {
+		fake.UnpausedStub()
+	}
+}
+
+func (fake *FakeListener) UnpausedCallCount() int {
+	fake.unpausedMutex.RLock()
+	defer fake.unpausedMutex.RUnlock()
+	return len(fake.unpausedArgsForCall)
+}
+
+func (fake *FakeListener) UnpausedCalls(stub func()) {
+	fake.unpausedMutex.Lock()
+	defer fake.unpausedMutex.Unlock()
+	fake.UnpausedStub = stub
+}
+
 func (fake *FakeListener) UpgradeStrategy(arg1 string) {
 	fake.upgradeStrategyMutex.Lock()
 	fake.upgradeStrategyArgsForCall = append(fake.upgradeStrategyArgsForCall, struct {
@@ -579,6 +809,38 @@ func (fake *FakeListener) WaitingForArgsForCall(i int) (string, int) {
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeListener) WaitingForMaintenanceWindows(arg1 int) {
+	fake.waitingForMaintenanceWindowsMutex.Lock()
+	fake.waitingForMaintenanceWindowsArgsForCall = append(fake.waitingForMaintenanceWindowsArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.WaitingForMaintenanceWindowsStub
+	fake.recordInvocation("WaitingForMaintenanceWindows", []interface{}{arg1})
+	fake.waitingForMaintenanceWindowsMutex.Unlock()
+	if stub != nil {
+		fake.WaitingForMaintenanceWindowsStub(arg1)
+	}
+}
+
+func (fake *FakeListener) WaitingForMaintenanceWindowsCallCount() int {
+	fake.waitingForMaintenanceWindowsMutex.RLock()
+	defer fake.waitingForMaintenanceWindowsMutex.RUnlock()
+	return len(fake.waitingForMaintenanceWindowsArgsForCall)
+}
+
+func (fake *FakeListener) WaitingForMaintenanceWindowsCalls(stub func(int)) {
+	fake.waitingForMaintenanceWindowsMutex.Lock()
+	defer fake.waitingForMaintenanceWindowsMutex.Unlock()
+	fake.WaitingForMaintenanceWindowsStub = stub
+}
+
+func (fake *FakeListener) WaitingForMaintenanceWindowsArgsForCall(i int) int {
+	fake.waitingForMaintenanceWindowsMutex.RLock()
+	defer fake.waitingForMaintenanceWindowsMutex.RUnlock()
+	argsForCall := fake.waitingForMaintenanceWindowsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeListener) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -588,6 +850,8 @@ func (fake *FakeListener) Invocations() map[string][][]interface{} {
 	defer fake.canariesStartingMutex.RUnlock()
 	fake.failedToRefreshInstanceInfoMutex.RLock()
 	defer fake.failedToRefreshInstanceInfoMutex.RUnlock()
+	fake.failedToSaveCheckpointMutex.RLock()
+	defer fake.failedToSaveCheckpointMutex.RUnlock()
 	fake.finishedMutex.RLock()
 	defer fake.finishedMutex.RUnlock()
 	fake.instanceOperationFinishedMutex.RLock()
@@ -596,20 +860,32 @@ func (fake *FakeListener) Invocations() map[string][][]interface{} {
 	defer fake.instanceOperationStartResultMutex.RUnlock()
 	fake.instanceOperationStartingMutex.RLock()
 	defer fake.instanceOperationStartingMutex.RUnlock()
+	fake.instancesPreviewedMutex.RLock()
+	defer fake.instancesPreviewedMutex.RUnlock()
 	fake.instancesToProcessMutex.RLock()
 	defer fake.instancesToProcessMutex.RUnlock()
+	fake.outsideMaintenanceWindowMutex.RLock()
+	defer fake.outsideMaintenanceWindowMutex.RUnlock()
+	fake.pausedMutex.RLock()
+	defer fake.pausedMutex.RUnlock()
 	fake.progressMutex.RLock()
 	defer fake.progressMutex.RUnlock()
+	fake.resumingFromCheckpointMutex.RLock()
+	defer fake.resumingFromCheckpointMutex.RUnlock()
 	fake.retryAttemptMutex.RLock()
 	defer fake.retryAttemptMutex.RUnlock()
 	fake.retryCanariesAttemptMutex.RLock()
 	defer fake.retryCanariesAttemptMutex.RUnlock()
 	fake.startingMutex.RLock()
 	defer fake.startingMutex.RUnlock()
+	fake.unpausedMutex.RLock()
+	defer fake.unpausedMutex.RUnlock()
 	fake.upgradeStrategyMutex.RLock()
 	defer fake.upgradeStrategyMutex.RUnlock()
 	fake.waitingForMutex.RLock()
 	defer fake.waitingForMutex.RUnlock()
+	fake.waitingForMaintenanceWindowsMutex.RLock()
+	defer fake.waitingForMaintenanceWindowsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -8,6 +8,7 @@ package instanceiterator
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -33,18 +34,32 @@ type Listener interface {
 	InstanceOperationFinished(instance, result string)
 	WaitingFor(instance string, boshTaskId int)
 	Progress(pollingInterval time.Duration, orphanCount, processedCount, skippedCount, toRetryCount, deletedCount int)
-	Finished(orphanCount, finishedCount, skippedCount, deletedCount int, busyInstances, failedInstances []string)
+	Finished(orphanCount, finishedCount, skippedCount, deletedCount int, busyInstances, failedInstances, pendingInstances []string)
 	CanariesStarting(canaries int, filter config.CanarySelectionParams)
 	CanariesFinished()
 	UpgradeStrategy(strategy string)
+	ResumingFromCheckpoint(succeededCount int)
+	FailedToSaveCheckpoint(err error)
+	Paused(inFlightCount int)
+	Unpaused()
+	OutsideMaintenanceWindow(instance string)
+	WaitingForMaintenanceWindows(pendingCount int)
+	InstancesPreviewed(instances []string)
 }
 
 //counterfeiter:generate -o fakes/fake_broker_services.go . BrokerServices
 type BrokerServices interface {
 	ProcessInstance(instance service.Instance, operationType string) (services.BOSHOperation, error)
+	RotateSecrets(instance service.Instance, secretPaths []string) (services.BOSHOperation, error)
+	PreviewInstance(instance service.Instance, operationType string) (services.InstancePreview, error)
 	LastOperation(instance string, operationData broker.OperationData) (domain.LastOperation, error)
 	Instances(filter map[string]string) ([]service.Instance, error)
 	LatestInstanceInfo(inst service.Instance) (service.Instance, error)
+	Catalog() ([]domain.Service, error)
+	UpdatePlan(instance service.Instance, details domain.UpdateDetails) (services.BOSHOperation, error)
+	IteratorCheckpoint(name string) ([]byte, bool, error)
+	SaveIteratorCheckpoint(name string, checkpoint []byte) error
+	ClearIteratorCheckpoint(name string) error
 }
 
 //counterfeiter:generate -o fakes/fake_sleeper.go . sleeper
@@ -52,6 +67,11 @@ type sleeper interface {
 	Sleep(d time.Duration)
 }
 
+//counterfeiter:generate -o fakes/fake_clock.go . clock
+type clock interface {
+	Now() time.Time
+}
+
 type instanceFailure struct {
 	guid string
 	err  error
@@ -74,6 +94,7 @@ const (
 	InstanceNotFound    OperationState = "instance-not-found"
 	OperationPending    OperationState = "not-started"
 	OrphanDeployment    OperationState = "orphan-deployment"
+	OperationPreviewed  OperationState = "previewed"
 )
 
 //counterfeiter:generate -o fakes/fake_triggerer.go . Triggerer
@@ -82,6 +103,12 @@ type Triggerer interface {
 	Check(string, broker.OperationData) (TriggeredOperation, error)
 }
 
+// instanceSelector is implemented by triggerers that only operate on some of
+// the instances. The other instances are left out of the run.
+type instanceSelector interface {
+	Selects(instance service.Instance) bool
+}
+
 type Iterator struct {
 	brokerServices  BrokerServices
 	pollingInterval time.Duration
@@ -91,25 +118,49 @@ type Iterator struct {
 	listener        Listener
 	sleeper         sleeper
 
-	failures              []instanceFailure
-	canaries              int
-	canarySelectionParams config.CanarySelectionParams
-	iteratorState         *iteratorState
-	triggerer             Triggerer
+	failures                []instanceFailure
+	canaries                int
+	canarySelectionParams   config.CanarySelectionParams
+	instanceSelectionParams config.InstanceSelectionParams
+	iteratorState           *iteratorState
+	triggerer               Triggerer
+	checkpointer            Checkpointer
+	lastCheckpoint          *Checkpoint
+	resume                  bool
+	pauser                  Pauser
+	paused                  bool
+
+	maintenanceWindows        []MaintenanceWindow
+	waitForMaintenanceWindows bool
+	instanceWindows           map[string][]int
+	deferredBeforeWait        int
+	clock                     clock
 }
 
+// maintenanceWindowHorizon is how far ahead the iterator looks for a window to
+// open before giving up on the instances it deferred.
+const maintenanceWindowHorizon = 366 * 24 * time.Hour
+
 func New(builder *Configurator) *Iterator {
 	return &Iterator{
-		brokerServices:        builder.BrokerServices,
-		pollingInterval:       builder.PollingInterval,
-		attemptInterval:       builder.AttemptInterval,
-		attemptLimit:          builder.AttemptLimit,
-		maxInFlight:           builder.MaxInFlight,
-		listener:              builder.Listener,
-		sleeper:               builder.Sleeper,
-		canaries:              builder.Canaries,
-		canarySelectionParams: builder.CanarySelectionParams,
-		triggerer:             builder.Triggerer,
+		brokerServices:          builder.BrokerServices,
+		pollingInterval:         builder.PollingInterval,
+		attemptInterval:         builder.AttemptInterval,
+		attemptLimit:            builder.AttemptLimit,
+		maxInFlight:             builder.MaxInFlight,
+		listener:                builder.Listener,
+		sleeper:                 builder.Sleeper,
+		canaries:                builder.Canaries,
+		canarySelectionParams:   builder.CanarySelectionParams,
+		instanceSelectionParams: builder.InstanceSelectionParams,
+		triggerer:               builder.Triggerer,
+		checkpointer:            builder.Checkpointer,
+		resume:                  builder.Resume,
+		pauser:                  builder.Pauser,
+
+		maintenanceWindows:        builder.MaintenanceWindows,
+		waitForMaintenanceWindows: builder.WaitForMaintenanceWindows,
+		clock:                     builder.Clock,
 	}
 }
 
@@ -120,6 +171,10 @@ func (it *Iterator) Iterate() error {
 		return err
 	}
 
+	if err := it.restoreCheckpoint(); err != nil {
+		return err
+	}
+
 	it.listener.InstancesToProcess(it.iteratorState.AllInstances())
 
 	if it.iteratorState.IsProcessingCanaries() {
@@ -129,6 +184,7 @@ func (it *Iterator) Iterate() error {
 			return err
 		}
 		it.iteratorState.MarkCanariesCompleted()
+		it.saveCheckpoint()
 		it.listener.CanariesFinished()
 	}
 
@@ -137,6 +193,7 @@ func (it *Iterator) Iterate() error {
 		return err
 	}
 	it.printSummary()
+	it.clearCheckpoint()
 	return nil
 }
 
@@ -146,10 +203,13 @@ func (it *Iterator) IterateInstancesWithAttempts() error {
 		it.logRetryAttempt(attempt)
 
 		for it.iteratorState.HasInstancesToProcess() {
-			it.triggerOperation()
+			if !it.isPaused() {
+				it.triggerOperation()
+			}
 			it.pollRunningTasks()
+			it.saveCheckpoint()
 
-			if it.iteratorState.HasInstancesProcessing() {
+			if it.iteratorState.HasInstancesProcessing() || it.paused {
 				it.sleeper.Sleep(it.pollingInterval)
 				continue
 			}
@@ -169,6 +229,18 @@ func (it *Iterator) IterateInstancesWithAttempts() error {
 			break
 		}
 
+		if wait, ok := it.maintenanceWindowWait(); ok {
+			// waiting for a window to open does not use up an attempt, unless the
+			// previous wait did not let any deferred instance be processed
+			deferred := len(it.iteratorState.DeferredGUIDs())
+			if it.deferredBeforeWait == 0 || deferred < it.deferredBeforeWait {
+				attempt--
+			}
+			it.deferredBeforeWait = deferred
+			it.sleeper.Sleep(wait)
+			continue
+		}
+
 		it.sleeper.Sleep(it.attemptInterval)
 	}
 	return it.checkStillBusyInstances()
@@ -177,16 +249,18 @@ func (it *Iterator) IterateInstancesWithAttempts() error {
 func (it *Iterator) registerInstancesAndCanaries() error {
 	var canaryInstances []service.Instance
 
-	allInstances, err := it.brokerServices.Instances(nil)
+	allInstances, err := it.brokerServices.Instances(it.instanceSelectionParams)
 	if err != nil {
 		return fmt.Errorf("error listing service instances: %s", err)
 	}
+	allInstances = it.selectInstances(allInstances)
 
 	if len(it.canarySelectionParams) > 0 {
 		canaryInstances, err = it.brokerServices.Instances(it.canarySelectionParams)
 		if err != nil {
 			return fmt.Errorf("error listing service instances: %s", err)
 		}
+		canaryInstances = onlyInstancesOf(canaryInstances, allInstances)
 		if len(canaryInstances) == 0 && len(allInstances) > 0 {
 			return fmt.Errorf("Failed to find a match to the canary selection criteria: %s. "+
 				"Please ensure these selection criteria will match one or more service instances, "+
@@ -206,9 +280,99 @@ func (it *Iterator) registerInstancesAndCanaries() error {
 	if err != nil {
 		return fmt.Errorf("error with canary instance listing: %s", err)
 	}
+
+	if len(it.maintenanceWindows) > 0 {
+		it.instanceWindows, err = maintenanceWindowsByInstance(it.maintenanceWindows, allInstances, it.brokerServices)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onlyInstancesOf keeps the canaries that are selected for the run.
+func onlyInstancesOf(canaryInstances, allInstances []service.Instance) []service.Instance {
+	selected := map[string]bool{}
+	for _, instance := range allInstances {
+		selected[instance.GUID] = true
+	}
+
+	instances := []service.Instance{}
+	for _, instance := range canaryInstances {
+		if selected[instance.GUID] {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+func (it *Iterator) selectInstances(instances []service.Instance) []service.Instance {
+	selector, ok := it.triggerer.(instanceSelector)
+	if !ok {
+		return instances
+	}
+
+	selected := []service.Instance{}
+	for _, instance := range instances {
+		if selector.Selects(instance) {
+			selected = append(selected, instance)
+		}
+	}
+	return selected
+}
+
+func (it *Iterator) restoreCheckpoint() error {
+	if it.checkpointer == nil || !it.resume {
+		return nil
+	}
+
+	checkpoint, err := it.checkpointer.Load()
+	if err != nil {
+		return fmt.Errorf("error resuming from checkpoint: %s", err)
+	}
+	it.listener.ResumingFromCheckpoint(it.iteratorState.Restore(checkpoint))
 	return nil
 }
 
+func (it *Iterator) saveCheckpoint() {
+	if it.checkpointer == nil {
+		return
+	}
+	checkpoint := it.iteratorState.Checkpoint()
+	if it.lastCheckpoint != nil && reflect.DeepEqual(*it.lastCheckpoint, checkpoint) {
+		return
+	}
+	if err := it.checkpointer.Save(checkpoint); err != nil {
+		it.listener.FailedToSaveCheckpoint(err)
+		return
+	}
+	it.lastCheckpoint = &checkpoint
+}
+
+// clearCheckpoint forgets a run that completed, so that resuming does not skip
+// the instances of the next run.
+func (it *Iterator) clearCheckpoint() {
+	if it.checkpointer == nil {
+		return
+	}
+	if err := it.checkpointer.Clear(); err != nil {
+		it.listener.FailedToSaveCheckpoint(err)
+	}
+}
+
+func (it *Iterator) isPaused() bool {
+	paused := it.pauser != nil && it.pauser.Paused()
+	if paused != it.paused {
+		if paused {
+			it.listener.Paused(it.iteratorState.CountInProgressInstances())
+		} else {
+			it.listener.Unpaused()
+		}
+		it.paused = paused
+	}
+	return paused
+}
+
 func (it *Iterator) logRetryAttempt(attempt int) {
 	if it.iteratorState.IsProcessingCanaries() {
 		it.listener.RetryCanariesAttempt(attempt, it.attemptLimit, it.iteratorState.OutstandingCanaryCount())
@@ -236,6 +400,7 @@ func (it *Iterator) triggerOperation() {
 	}
 
 	totalInstances := it.iteratorState.CountInstancesInCurrentPhase()
+	openWindows := it.openMaintenanceWindows()
 
 	acceptedCount := 0
 	for acceptedCount < needed {
@@ -243,6 +408,13 @@ func (it *Iterator) triggerOperation() {
 		if err != nil {
 			break
 		}
+
+		if !it.inMaintenanceWindow(instance.GUID, openWindows) {
+			it.iteratorState.Defer(instance.GUID)
+			it.listener.OutsideMaintenanceWindow(instance.GUID)
+			continue
+		}
+
 		it.listener.InstanceOperationStarting(instance.GUID, it.iteratorState.GetIteratorIndex(), totalInstances, it.iteratorState.IsProcessingCanaries())
 
 		var operation TriggeredOperation
@@ -274,6 +446,68 @@ func (it *Iterator) triggerOperation() {
 	}
 }
 
+func (it *Iterator) openMaintenanceWindows() []bool {
+	if len(it.maintenanceWindows) == 0 {
+		return nil
+	}
+
+	now := it.clock.Now()
+	open := make([]bool, len(it.maintenanceWindows))
+	for i, w := range it.maintenanceWindows {
+		open[i] = w.OpenAt(now)
+	}
+	return open
+}
+
+// inMaintenanceWindow reports whether any of the windows of an instance is
+// open. Instances without windows may be processed at any time.
+func (it *Iterator) inMaintenanceWindow(guid string, openWindows []bool) bool {
+	windows := it.instanceWindows[guid]
+	if len(windows) == 0 {
+		return true
+	}
+	for _, i := range windows {
+		if openWindows[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceWindowWait returns how long until a window of a deferred instance
+// opens. Deferred canaries are always waited for, as the run cannot carry on
+// without them; other instances only when configured to.
+func (it *Iterator) maintenanceWindowWait() (time.Duration, bool) {
+	if !it.waitForMaintenanceWindows && !it.iteratorState.IsProcessingCanaries() {
+		return 0, false
+	}
+	if it.iteratorState.HasBusyInstances() {
+		return 0, false
+	}
+
+	deferred := it.iteratorState.DeferredGUIDs()
+	if len(deferred) == 0 {
+		return 0, false
+	}
+
+	now := it.clock.Now()
+	limit := now.Add(maintenanceWindowHorizon)
+	var next time.Time
+	for _, guid := range deferred {
+		for _, i := range it.instanceWindows[guid] {
+			if opening, found := it.maintenanceWindows[i].NextOpening(now, limit); found && (next.IsZero() || opening.Before(next)) {
+				next = opening
+			}
+		}
+	}
+	if next.IsZero() {
+		return 0, false
+	}
+
+	it.listener.WaitingForMaintenanceWindows(len(deferred))
+	return next.Sub(now), true
+}
+
 func (it *Iterator) pollRunningTasks() {
 	for _, inst := range it.iteratorState.InProgressInstances() {
 		guid := inst.GUID
@@ -311,13 +545,22 @@ func (it *Iterator) printSummary() {
 		failedInstances = append(failedInstances, failure.guid)
 	}
 
-	it.listener.Finished(summary.orphaned, summary.succeeded, summary.skipped, summary.deleted, busyInstances, failedInstances)
+	if previewed := it.iteratorState.GetGUIDsInStates(OperationPreviewed); len(previewed) > 0 {
+		it.listener.InstancesPreviewed(previewed)
+	}
+	it.listener.Finished(summary.orphaned, summary.succeeded, summary.skipped, summary.deleted, busyInstances, failedInstances, it.iteratorState.DeferredGUIDs())
 }
 
 func (it *Iterator) checkStillBusyInstances() error {
 	busyInstances := it.iteratorState.GetGUIDsInStates(OperationInProgress)
 	busyInstancesCount := len(busyInstances)
 
+	if it.iteratorState.IsProcessingCanaries() && !it.iteratorState.canariesCompleted() {
+		if deferred := it.iteratorState.DeferredGUIDs(); len(deferred) > 0 {
+			return fmt.Errorf("canaries didn't process successfully: %d canaries are outside their maintenance window", len(deferred))
+		}
+	}
+
 	if busyInstancesCount == 0 {
 		return nil
 	}
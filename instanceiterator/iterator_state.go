@@ -27,6 +27,8 @@ type instanceInfo struct {
 	initialPlan   string
 	operation     TriggeredOperation
 	couldBeCanary bool
+	// deferred instances are pending, but outside their maintenance window
+	deferred bool
 }
 
 type iteratorState struct {
@@ -82,13 +84,39 @@ func (is *iteratorState) RewindAndResetBusyInstances() {
 	for k, v := range is.states {
 		if v.status == OperationInProgress {
 			v.status = OperationPending
-			is.states[k] = v
 		}
+		v.deferred = false
+		is.states[k] = v
 	}
 }
 
 func (is *iteratorState) HasInstancesToProcess() bool {
-	return len(is.GetInstancesInStates(OperationPending, OperationAccepted)) > 0
+	return len(is.GetInstancesInStates(OperationAccepted)) > 0 || len(is.pendingGUIDs(false)) > 0
+}
+
+func (is *iteratorState) HasBusyInstances() bool {
+	return len(is.GetInstancesInStates(OperationInProgress)) > 0
+}
+
+// Defer leaves an instance pending until the next attempt.
+func (is *iteratorState) Defer(guid string) {
+	info := is.states[guid]
+	info.deferred = true
+	is.states[guid] = info
+}
+
+func (is *iteratorState) DeferredGUIDs() []string {
+	return is.pendingGUIDs(true)
+}
+
+func (is *iteratorState) pendingGUIDs(deferred bool) []string {
+	guids := []string{}
+	for _, guid := range is.GetGUIDsInStates(OperationPending) {
+		if is.states[guid].deferred == deferred {
+			guids = append(guids, guid)
+		}
+	}
+	return guids
 }
 
 func (is *iteratorState) HasInstancesProcessing() bool {
@@ -123,7 +151,7 @@ func (is *iteratorState) NextPending() (service.Instance, error) {
 }
 
 func (is *iteratorState) GetIteratorIndex() int {
-	return len(is.GetInstancesInStates(OperationSucceeded, OperationAccepted, InstanceNotFound, OrphanDeployment)) + 1
+	return len(is.GetInstancesInStates(OperationSucceeded, OperationAccepted, InstanceNotFound, OrphanDeployment, OperationPreviewed)) + 1
 }
 
 func (is *iteratorState) GetGUIDsInStates(states ...OperationState) (guids []string) {
@@ -257,14 +285,56 @@ func (is *iteratorState) processable(guid string) bool {
 func (is *iteratorState) doingCanariesAndPendingCanary(guid string) bool {
 	return is.processCanaries &&
 		is.states[guid].couldBeCanary &&
-		is.states[guid].status == OperationPending
+		is.states[guid].status == OperationPending &&
+		!is.states[guid].deferred
 }
 
 func (is *iteratorState) notDoingCanariesAndPendingInstance(guid string) bool {
 	return !is.processCanaries &&
-		is.states[guid].status == OperationPending
+		is.states[guid].status == OperationPending &&
+		!is.states[guid].deferred
 }
 
 func isFinalState(status OperationState) bool {
 	return status != OperationInProgress && status != OperationPending && status != OperationAccepted
 }
+
+func (is *iteratorState) Checkpoint() Checkpoint {
+	checkpoint := Checkpoint{
+		CanariesCompleted: !is.processCanaries,
+		Instances:         map[string]InstanceCheckpoint{},
+	}
+	for guid, info := range is.states {
+		checkpoint.Instances[guid] = InstanceCheckpoint{State: info.status, OperationData: info.operation.Data}
+	}
+	return checkpoint
+}
+
+// Restore applies the progress of a previous run. Instances that succeeded are
+// not processed again and operations that were in flight are waited for; any
+// other instance is processed as if the previous run had not happened. It
+// returns the number of instances that succeeded.
+func (is *iteratorState) Restore(checkpoint Checkpoint) int {
+	succeeded := 0
+	for guid, instance := range checkpoint.Instances {
+		info, found := is.states[guid]
+		if !found {
+			continue
+		}
+		switch instance.State {
+		case OperationSucceeded:
+			succeeded++
+		case OperationAccepted:
+		default:
+			continue
+		}
+		info.status = instance.State
+		info.operation = TriggeredOperation{State: instance.State, Data: instance.OperationData}
+		is.states[guid] = info
+	}
+
+	if checkpoint.CanariesCompleted && is.processCanaries {
+		is.MarkCanariesCompleted()
+	}
+	return succeeded
+}
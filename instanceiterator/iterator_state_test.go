@@ -21,6 +21,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
@@ -184,6 +185,56 @@ var _ = Describe("Iterate State", func() {
 	})
 })
 
+var _ = Describe("Iterator State checkpoints", func() {
+	It("restores the instances that succeeded or were in flight", func() {
+		_, all := instances(func(int) bool { return false }, 4)
+		us, err := instanceiterator.NewIteratorState(nil, all, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		succeeded := us.Restore(instanceiterator.Checkpoint{
+			CanariesCompleted: true,
+			Instances: map[string]instanceiterator.InstanceCheckpoint{
+				"guid_0":      {State: instanceiterator.OperationSucceeded},
+				"guid_1":      {State: instanceiterator.OperationAccepted, OperationData: broker.OperationData{BoshTaskID: 7}},
+				"guid_2":      {State: instanceiterator.OperationFailed},
+				"not-an-guid": {State: instanceiterator.OperationSucceeded},
+			},
+		})
+
+		Expect(succeeded).To(Equal(1))
+		Expect(us.GetGUIDsInStates(instanceiterator.OperationSucceeded)).To(ConsistOf("guid_0"))
+		Expect(us.GetGUIDsInStates(instanceiterator.OperationAccepted)).To(ConsistOf("guid_1"))
+		Expect(us.GetOperation("guid_1").Data.BoshTaskID).To(Equal(7))
+		Expect(us.GetGUIDsInStates(instanceiterator.OperationPending)).To(ConsistOf("guid_2", "guid_3"))
+	})
+
+	It("skips the canaries when they had completed", func() {
+		canaries, all := instances(func(i int) bool { return i == 0 }, 2)
+		us, err := instanceiterator.NewIteratorState(canaries, all, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		us.Restore(instanceiterator.Checkpoint{CanariesCompleted: true})
+
+		Expect(us.IsProcessingCanaries()).To(BeFalse())
+	})
+
+	It("records the state of every instance", func() {
+		canaries, all := instances(func(i int) bool { return i == 0 }, 2)
+		us, err := instanceiterator.NewIteratorState(canaries, all, 1)
+		Expect(err).NotTo(HaveOccurred())
+		us.SetOperation("guid_0", instanceiterator.TriggeredOperation{Data: broker.OperationData{BoshTaskID: 7}})
+		us.SetState("guid_0", instanceiterator.OperationAccepted)
+
+		Expect(us.Checkpoint()).To(Equal(instanceiterator.Checkpoint{
+			CanariesCompleted: false,
+			Instances: map[string]instanceiterator.InstanceCheckpoint{
+				"guid_0": {State: instanceiterator.OperationAccepted, OperationData: broker.OperationData{BoshTaskID: 7}},
+				"guid_1": {State: instanceiterator.OperationPending},
+			},
+		}))
+	})
+})
+
 func instances(isCanary func(int) bool, total int) (canaries, all []service.Instance) {
 	for i := 0; i < total; i++ {
 		inst := service.Instance{GUID: fmt.Sprintf("guid_%d", i), PlanUniqueID: "plan"}
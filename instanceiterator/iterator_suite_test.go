@@ -80,7 +80,7 @@ func setupTest(states []*testState, brokerServices *fakes.FakeBrokerServices, fa
 func hasReportedFinished(fakeListener *fakes.FakeListener, expectedOrphans, expectedProcessed, expectedDeleted int, expectedBusyInstances, expectedFailedInstances []string) {
 	GinkgoHelper()
 	Expect(fakeListener.FinishedCallCount()).To(Equal(1), "Finished call count")
-	orphanCount, processedCount, _, deletedCount, busyInstances, failedInstances := fakeListener.FinishedArgsForCall(0)
+	orphanCount, processedCount, _, deletedCount, busyInstances, failedInstances, _ := fakeListener.FinishedArgsForCall(0)
 	Expect(orphanCount).To(Equal(expectedOrphans), "orphans")
 	Expect(processedCount).To(Equal(expectedProcessed), "processed")
 	Expect(deletedCount).To(Equal(expectedDeleted), "deleted")
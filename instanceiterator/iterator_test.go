@@ -12,9 +12,11 @@ import (
 	"sync"
 	"time"
 
+	"code.cloudfoundry.org/brokerapi/v13/domain"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
@@ -135,6 +137,371 @@ var _ = Describe("Iterator", func() {
 		})
 	})
 
+	Context("instance selection", func() {
+		It("processes the selected instances and picks the canaries among them", func() {
+			fakeBrokerServicesClient.InstancesStub = func(filter map[string]string) ([]service.Instance, error) {
+				if filter["plan_ids"] == "small" {
+					return []service.Instance{{GUID: "1"}, {GUID: "2"}}, nil
+				}
+				return []service.Instance{{GUID: "2"}, {GUID: "3"}}, nil
+			}
+			fakeBrokerServicesClient.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			fakeTriggerer.TriggerOperationReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationAccepted}, nil)
+			fakeTriggerer.CheckReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationSucceeded}, nil)
+
+			builder.Canaries = 1
+			builder.InstanceSelectionParams = config.InstanceSelectionParams{"plan_ids": "small"}
+			builder.CanarySelectionParams = config.CanarySelectionParams{"cf_org": "org", "cf_space": "space"}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.InstancesToProcessArgsForCall(0)).To(Equal([]service.Instance{{GUID: "1"}, {GUID: "2"}}))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(2))
+			Expect(fakeTriggerer.TriggerOperationArgsForCall(0).GUID).To(Equal("2"), "the canary should be the selected instance of the org and space")
+			Expect(fakeTriggerer.TriggerOperationArgsForCall(1).GUID).To(Equal("1"))
+		})
+	})
+
+	Context("previews", func() {
+		It("reports the instances whose manifest would change", func() {
+			fakeBrokerServicesClient.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}, {GUID: "3"}}, nil)
+			fakeBrokerServicesClient.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			fakeTriggerer.TriggerOperationStub = func(inst service.Instance) (instanceiterator.TriggeredOperation, error) {
+				if inst.GUID == "2" {
+					return instanceiterator.TriggeredOperation{State: instanceiterator.OperationSkipped}, nil
+				}
+				return instanceiterator.TriggeredOperation{State: instanceiterator.OperationPreviewed}, nil
+			}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.CheckCallCount()).To(BeZero())
+			Expect(fakeListener.InstancesPreviewedCallCount()).To(Equal(1))
+			Expect(fakeListener.InstancesPreviewedArgsForCall(0)).To(Equal([]string{"1", "3"}))
+			_, processedCount, skippedCount, _, _, _, _ := fakeListener.FinishedArgsForCall(0)
+			Expect(processedCount).To(BeZero())
+			Expect(skippedCount).To(Equal(1))
+		})
+	})
+
+	Context("triggerers that select instances", func() {
+		It("only processes the selected instances, and picks canaries among them", func() {
+			fakeBrokerServicesClient.InstancesReturns([]service.Instance{
+				{GUID: "1", PlanUniqueID: "other-plan-id"},
+				{GUID: "2", PlanUniqueID: "source-plan-id"},
+			}, nil)
+			fakeBrokerServicesClient.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			fakeBrokerServicesClient.UpdatePlanReturns(services.BOSHOperation{Type: services.OperationAccepted}, nil)
+			fakeBrokerServicesClient.LastOperationReturns(domain.LastOperation{State: domain.Succeeded}, nil)
+
+			builder.Canaries = 1
+			builder.Triggerer = instanceiterator.NewMigratePlanTriggerer(fakeBrokerServicesClient, []instanceiterator.PlanMigration{
+				{SourcePlanID: "source-plan-id", TargetPlanID: "target-plan-id"},
+			})
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.InstancesToProcessArgsForCall(0)).To(Equal([]service.Instance{{GUID: "2", PlanUniqueID: "source-plan-id"}}))
+			Expect(fakeBrokerServicesClient.UpdatePlanCallCount()).To(Equal(1))
+			instance, _ := fakeBrokerServicesClient.UpdatePlanArgsForCall(0)
+			Expect(instance.GUID).To(Equal("2"))
+		})
+	})
+
+	Context("checkpoints", func() {
+		var fakeCheckpointer *fakes.FakeCheckpointer
+
+		BeforeEach(func() {
+			fakeCheckpointer = new(fakes.FakeCheckpointer)
+			builder.Checkpointer = fakeCheckpointer
+
+			fakeBrokerServicesClient.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}, {GUID: "3"}}, nil)
+			fakeBrokerServicesClient.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			fakeTriggerer.TriggerOperationReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationAccepted}, nil)
+			fakeTriggerer.CheckReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationSucceeded}, nil)
+		})
+
+		It("saves the progress and clears it once the run completes", func() {
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCheckpointer.LoadCallCount()).To(BeZero())
+			Expect(fakeCheckpointer.SaveCallCount()).To(BeNumerically(">=", 3))
+			lastCheckpoint := fakeCheckpointer.SaveArgsForCall(fakeCheckpointer.SaveCallCount() - 1)
+			Expect(lastCheckpoint.Instances).To(HaveLen(3))
+			for _, instance := range lastCheckpoint.Instances {
+				Expect(instance.State).To(Equal(instanceiterator.OperationSucceeded))
+			}
+			Expect(fakeCheckpointer.ClearCallCount()).To(Equal(1))
+		})
+
+		It("only saves the progress when it changes", func() {
+			checks := 0
+			fakeTriggerer.CheckStub = func(string, broker.OperationData) (instanceiterator.TriggeredOperation, error) {
+				checks++
+				if checks%3 != 0 {
+					return instanceiterator.TriggeredOperation{State: instanceiterator.OperationAccepted}, nil
+				}
+				return instanceiterator.TriggeredOperation{State: instanceiterator.OperationSucceeded}, nil
+			}
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCheckpointer.SaveCallCount()).To(BeNumerically(">=", 3))
+			for i := 1; i < fakeCheckpointer.SaveCallCount(); i++ {
+				Expect(fakeCheckpointer.SaveArgsForCall(i)).NotTo(Equal(fakeCheckpointer.SaveArgsForCall(i - 1)))
+			}
+		})
+
+		It("keeps the progress when the run fails", func() {
+			fakeTriggerer.CheckReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationFailed}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(fakeCheckpointer.SaveCallCount()).To(BeNumerically(">", 0))
+			Expect(fakeCheckpointer.ClearCallCount()).To(BeZero())
+		})
+
+		It("carries on when the progress cannot be saved", func() {
+			fakeCheckpointer.SaveReturns(errors.New("disk full"))
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.FailedToSaveCheckpointCallCount()).To(BeNumerically(">", 0))
+			Expect(fakeListener.FailedToSaveCheckpointArgsForCall(0)).To(MatchError("disk full"))
+			hasReportedFinished(fakeListener, 0, 3, 0, emptyBusyList, emptyFailedList)
+		})
+
+		When("resuming", func() {
+			BeforeEach(func() {
+				builder.Resume = true
+				fakeCheckpointer.LoadReturns(instanceiterator.Checkpoint{
+					CanariesCompleted: true,
+					Instances: map[string]instanceiterator.InstanceCheckpoint{
+						"1": {State: instanceiterator.OperationSucceeded},
+						"2": {State: instanceiterator.OperationAccepted, OperationData: broker.OperationData{BoshTaskID: 5}},
+					},
+				}, nil)
+			})
+
+			It("skips the instances that succeeded and waits for the operations that were in flight", func() {
+				err := instanceiterator.New(&builder).Iterate()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeListener.ResumingFromCheckpointArgsForCall(0)).To(Equal(1))
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(1))
+				Expect(fakeTriggerer.TriggerOperationArgsForCall(0).GUID).To(Equal("3"))
+
+				guid, operationData := fakeTriggerer.CheckArgsForCall(0)
+				Expect(guid).To(Equal("2"))
+				Expect(operationData.BoshTaskID).To(Equal(5))
+
+				hasReportedFinished(fakeListener, 0, 3, 0, emptyBusyList, emptyFailedList)
+			})
+
+			It("fails when the progress cannot be loaded", func() {
+				fakeCheckpointer.LoadReturns(instanceiterator.Checkpoint{}, errors.New("unreadable"))
+
+				err := instanceiterator.New(&builder).Iterate()
+
+				Expect(err).To(MatchError("error resuming from checkpoint: unreadable"))
+				Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Context("pausing", func() {
+		It("stops triggering operations while paused", func() {
+			fakePauser := new(fakes.FakePauser)
+			fakePauser.PausedReturnsOnCall(1, true)
+			fakePauser.PausedReturnsOnCall(2, true)
+			builder.Pauser = fakePauser
+
+			fakeBrokerServicesClient.InstancesReturns([]service.Instance{{GUID: "1"}, {GUID: "2"}}, nil)
+			fakeBrokerServicesClient.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			fakeTriggerer.TriggerOperationReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationAccepted}, nil)
+			fakeTriggerer.CheckReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationSucceeded}, nil)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.PausedCallCount()).To(Equal(1))
+			Expect(fakeListener.UnpausedCallCount()).To(Equal(1))
+			Expect(fakeSleeper.SleepCallCount()).To(Equal(2))
+			hasSlept(fakeSleeper, 0, builder.PollingInterval)
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(2))
+			hasReportedFinished(fakeListener, 0, 2, 0, emptyBusyList, emptyFailedList)
+		})
+	})
+
+	Context("maintenance windows", func() {
+		var (
+			fakeClock *fakes.FakeClock
+			now       time.Time
+		)
+
+		BeforeEach(func() {
+			windows, err := instanceiterator.NewMaintenanceWindows([]config.MaintenanceWindow{
+				{Plan: "plan-with-window", Schedule: "0 2 * * *", Duration: "2h"},
+				{Org: "org-with-window", Schedule: "0 12 * * *", Duration: "1h"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			builder.MaintenanceWindows = windows
+
+			now = time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+			fakeClock = new(fakes.FakeClock)
+			fakeClock.NowStub = func() time.Time { return now }
+			builder.Clock = fakeClock
+			fakeSleeper.SleepStub = func(d time.Duration) { now = now.Add(d) }
+
+			fakeBrokerServicesClient.InstancesStub = func(filter map[string]string) ([]service.Instance, error) {
+				if filter["org"] == "org-with-window" {
+					return []service.Instance{{GUID: "in-org", PlanUniqueID: "plan-with-window"}}, nil
+				}
+				if filter["labels"] == "tier=gold" {
+					return []service.Instance{{GUID: "no-window", PlanUniqueID: "another-plan"}}, nil
+				}
+				return []service.Instance{
+					{GUID: "on-plan", PlanUniqueID: "plan-with-window"},
+					{GUID: "in-org", PlanUniqueID: "plan-with-window"},
+					{GUID: "no-window", PlanUniqueID: "another-plan"},
+				}, nil
+			}
+			fakeBrokerServicesClient.LatestInstanceInfoStub = func(inst service.Instance) (service.Instance, error) {
+				return inst, nil
+			}
+			fakeTriggerer.TriggerOperationReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationAccepted}, nil)
+			fakeTriggerer.CheckReturns(instanceiterator.TriggeredOperation{State: instanceiterator.OperationSucceeded}, nil)
+		})
+
+		triggeredInstances := func() []string {
+			var triggered []string
+			for i := 0; i < fakeTriggerer.TriggerOperationCallCount(); i++ {
+				triggered = append(triggered, fakeTriggerer.TriggerOperationArgsForCall(i).GUID)
+			}
+			return triggered
+		}
+
+		It("defers the instances whose windows are closed and reports them as pending", func() {
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(triggeredInstances()).To(ConsistOf("in-org", "no-window"))
+
+			Expect(fakeListener.OutsideMaintenanceWindowArgsForCall(0)).To(Equal("on-plan"))
+			Expect(fakeListener.RetryAttemptCallCount()).To(Equal(builder.AttemptLimit))
+			Expect(fakeListener.WaitingForMaintenanceWindowsCallCount()).To(BeZero())
+			_, processedCount, _, _, _, _, pendingInstances := fakeListener.FinishedArgsForCall(0)
+			Expect(processedCount).To(Equal(2))
+			Expect(pendingInstances).To(ConsistOf("on-plan"))
+		})
+
+		It("selects the instances of a window by their labels", func() {
+			windows, err := instanceiterator.NewMaintenanceWindows([]config.MaintenanceWindow{
+				{Labels: map[string]string{"tier": "gold"}, Schedule: "0 2 * * *", Duration: "2h"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			builder.MaintenanceWindows = windows
+
+			err = instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(triggeredInstances()).To(ConsistOf("on-plan", "in-org"))
+			_, _, _, _, _, _, pendingInstances := fakeListener.FinishedArgsForCall(0)
+			Expect(pendingInstances).To(ConsistOf("no-window"))
+		})
+
+		It("sleeps until the windows open when configured to", func() {
+			builder.WaitForMaintenanceWindows = true
+			builder.AttemptLimit = 1
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(Equal(3))
+			Expect(fakeListener.WaitingForMaintenanceWindowsCallCount()).To(Equal(1))
+			Expect(fakeListener.WaitingForMaintenanceWindowsArgsForCall(0)).To(Equal(1))
+			Expect(fakeSleeper.SleepCallCount()).To(Equal(1))
+			Expect(fakeSleeper.SleepArgsForCall(0)).To(Equal(13*time.Hour+30*time.Minute), "should sleep until the window opens at 2am")
+			hasReportedFinished(fakeListener, 0, 3, 0, emptyBusyList, emptyFailedList)
+		})
+
+		It("stops waiting when a window opens without letting any deferred instance through", func() {
+			builder.WaitForMaintenanceWindows = true
+			builder.AttemptLimit = 1
+			fakeSleeper.SleepStub = nil // the clock stands still, so the window never opens
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.WaitingForMaintenanceWindowsCallCount()).To(Equal(2))
+			_, _, _, _, _, _, pendingInstances := fakeListener.FinishedArgsForCall(0)
+			Expect(pendingInstances).To(ConsistOf("on-plan"))
+		})
+
+		It("does not wait for windows that do not open within a year", func() {
+			windows, err := instanceiterator.NewMaintenanceWindows([]config.MaintenanceWindow{
+				{Plan: "plan-with-window", Schedule: "0 2 30 2 *", Duration: "2h"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			builder.MaintenanceWindows = windows
+			builder.WaitForMaintenanceWindows = true
+
+			err = instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.WaitingForMaintenanceWindowsCallCount()).To(BeZero())
+			_, _, _, _, _, _, pendingInstances := fakeListener.FinishedArgsForCall(0)
+			Expect(pendingInstances).To(ConsistOf("on-plan", "in-org"))
+		})
+
+		It("waits for the windows of the canaries to open", func() {
+			builder.Canaries = 1
+			builder.AttemptLimit = 1
+			builder.CanarySelectionParams = config.CanarySelectionParams{"org": "org-with-window"}
+			now = time.Date(2024, 6, 1, 5, 0, 0, 0, time.UTC)
+
+			err := instanceiterator.New(&builder).Iterate()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeListener.WaitingForMaintenanceWindowsCallCount()).To(Equal(1))
+			Expect(fakeTriggerer.TriggerOperationArgsForCall(0).GUID).To(Equal("in-org"))
+			Expect(fakeListener.CanariesFinishedCallCount()).To(Equal(1))
+		})
+
+		It("fails when the windows of the canaries do not open within a year", func() {
+			windows, err := instanceiterator.NewMaintenanceWindows([]config.MaintenanceWindow{
+				{Org: "org-with-window", Schedule: "0 12 30 2 *", Duration: "1h"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			builder.MaintenanceWindows = windows
+			builder.Canaries = 1
+			builder.AttemptLimit = 1
+			builder.CanarySelectionParams = config.CanarySelectionParams{"org": "org-with-window"}
+
+			err = instanceiterator.New(&builder).Iterate()
+
+			Expect(err).To(MatchError("canaries didn't process successfully: 1 canaries are outside their maintenance window"))
+			Expect(fakeTriggerer.TriggerOperationCallCount()).To(BeZero())
+		})
+	})
+
 	Context("processes instances without canaries", func() {
 		AfterEach(func() {
 			hasReportedStarting(fakeListener, builder.MaxInFlight)
@@ -83,6 +83,8 @@ func (ll LoggingListener) InstanceOperationStartResult(instance string, status O
 		message = "operation in progress"
 	case OperationSkipped, OperationSucceeded:
 		message = "instance already up to date - operation skipped"
+	case OperationPreviewed:
+		message = "manifest changes previewed - operation not run"
 	default:
 		message = "unexpected result"
 	}
@@ -119,7 +121,7 @@ func (ll LoggingListener) Progress(pollingInterval time.Duration, orphanCount, p
 	)
 }
 
-func (ll LoggingListener) Finished(orphanCount, finishedCount, skippedCount, deletedCount int, busyInstances, failedInstances []string) {
+func (ll LoggingListener) Finished(orphanCount, finishedCount, skippedCount, deletedCount int, busyInstances, failedInstances, pendingInstances []string) {
 	var failedList string
 	var busyList string
 	if len(failedInstances) > 0 {
@@ -134,13 +136,18 @@ func (ll LoggingListener) Finished(orphanCount, finishedCount, skippedCount, del
 		status = "FAILED"
 	}
 
+	var pendingSummary string
+	if len(pendingInstances) > 0 {
+		pendingSummary = fmt.Sprintf("; Number of service instances outside their maintenance window, still pending: %d [%s]", len(pendingInstances), strings.Join(pendingInstances, ", "))
+	}
+
 	ll.printf("FINISHED PROCESSING Status: %s; Summary: "+
 		"Number of successful operations: %d; "+
 		"Number of skipped operations: %d; "+
 		"Number of service instance orphans detected: %d; "+
 		"Number of deleted instances before operation could happen: %d; "+
 		"Number of busy instances which could not be processed: %d%s; "+
-		"Number of service instances that failed to process: %d%s",
+		"Number of service instances that failed to process: %d%s%s",
 		status,
 		finishedCount,
 		skippedCount,
@@ -150,6 +157,7 @@ func (ll LoggingListener) Finished(orphanCount, finishedCount, skippedCount, del
 		busyList,
 		len(failedInstances),
 		failedList,
+		pendingSummary,
 	)
 }
 
@@ -165,6 +173,34 @@ func (ll LoggingListener) CanariesFinished() {
 	ll.printf("FINISHED CANARIES")
 }
 
+func (ll LoggingListener) ResumingFromCheckpoint(succeededCount int) {
+	ll.printf("Resuming from checkpoint: %d instances already processed successfully will be skipped\n", succeededCount)
+}
+
+func (ll LoggingListener) FailedToSaveCheckpoint(err error) {
+	ll.printf("Failed to save checkpoint, the run cannot be resumed from this point: %s\n", err)
+}
+
+func (ll LoggingListener) Paused(inFlightCount int) {
+	ll.printf("PAUSED: no new operations will be started; waiting for %d operations in progress\n", inFlightCount)
+}
+
+func (ll LoggingListener) Unpaused() {
+	ll.printf("UNPAUSED: starting new operations again\n")
+}
+
+func (ll LoggingListener) OutsideMaintenanceWindow(instance string) {
+	ll.printf("[%s] Result: outside its maintenance window - operation deferred", instance)
+}
+
+func (ll LoggingListener) WaitingForMaintenanceWindows(pendingCount int) {
+	ll.printf("Waiting for the maintenance windows of %d service instances to open\n", pendingCount)
+}
+
+func (ll LoggingListener) InstancesPreviewed(instances []string) {
+	ll.printf("PREVIEW: Number of service instances whose manifest would change: %d [%s]\n", len(instances), strings.Join(instances, ", "))
+}
+
 func (ll LoggingListener) FailedToRefreshInstanceInfo(instance string) {
 	ll.logger.Printf("[%s] Failed to get refreshed list of instances. Continuing with previously fetched info.\n", instance)
 }
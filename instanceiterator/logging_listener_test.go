@@ -7,6 +7,7 @@
 package instanceiterator_test
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -38,6 +39,38 @@ var _ = Describe("Logging Listener", func() {
 			To(ContainSubstring("[%s] Upgrading all instances via foo", logPrefix))
 	})
 
+	It("Logs resuming from a checkpoint", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.ResumingFromCheckpoint(3) })).
+			To(ContainSubstring("[%s] Resuming from checkpoint: 3 instances already processed successfully will be skipped", logPrefix))
+	})
+
+	It("Logs a failure to save the checkpoint", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.FailedToSaveCheckpoint(errors.New("disk full")) })).
+			To(ContainSubstring("[%s] Failed to save checkpoint, the run cannot be resumed from this point: disk full", logPrefix))
+	})
+
+	It("Logs pausing and unpausing", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.Paused(2) })).
+			To(ContainSubstring("[%s] PAUSED: no new operations will be started; waiting for 2 operations in progress", logPrefix))
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.Unpaused() })).
+			To(ContainSubstring("[%s] UNPAUSED: starting new operations again", logPrefix))
+	})
+
+	It("Logs an instance outside its maintenance window", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.OutsideMaintenanceWindow("GUID") })).
+			To(ContainSubstring("[%s] [GUID] Result: outside its maintenance window - operation deferred", logPrefix))
+	})
+
+	It("Logs waiting for maintenance windows", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.WaitingForMaintenanceWindows(4) })).
+			To(ContainSubstring("[%s] Waiting for the maintenance windows of 4 service instances to open", logPrefix))
+	})
+
+	It("Logs the previewed instances", func() {
+		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.InstancesPreviewed([]string{"a", "b"}) })).
+			To(ContainSubstring("[%s] PREVIEW: Number of service instances whose manifest would change: 2 [a, b]", logPrefix))
+	})
+
 	It("Shows starting message", func() {
 		Expect(logResultsFromAsString(processType, func(listener instanceiterator.Listener) { listener.Starting(2) })).
 			To(ContainSubstring("[%s] STARTING OPERATION with 2 concurrent workers", logPrefix))
@@ -187,6 +220,16 @@ var _ = Describe("Logging Listener", func() {
 			})
 		})
 
+		Context("when previewed", func() {
+			BeforeEach(func() {
+				result = instanceiterator.OperationPreviewed
+			})
+
+			It("shows that the operation was not run", func() {
+				Expect(loggedString).To(ContainSubstring("[%s] [service-instance] Result: manifest changes previewed - operation not run", logPrefix))
+			})
+		})
+
 		Context("when error", func() {
 			BeforeEach(func() {
 				result = instanceiterator.OperationState(fmt.Sprint(-1))
@@ -236,7 +279,7 @@ var _ = Describe("Logging Listener", func() {
 
 	It("Shows a final summary where we completed successfully", func() {
 		result := logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
-			listener.Finished(23, 34, 15, 45, nil, nil)
+			listener.Finished(23, 34, 15, 45, nil, nil, nil)
 		})
 
 		Expect(result).To(SatisfyAll(
@@ -251,10 +294,21 @@ var _ = Describe("Logging Listener", func() {
 		))
 	})
 
+	It("Shows a final summary with instances outside their maintenance window", func() {
+		result := logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
+			listener.Finished(0, 3, 0, 0, nil, nil, []string{"guid-1", "guid-2"})
+		})
+
+		Expect(result).To(SatisfyAll(
+			ContainSubstring("[%s] FINISHED PROCESSING Status: SUCCESS; Summary", logPrefix),
+			ContainSubstring("Number of service instances that failed to process: 0; Number of service instances outside their maintenance window, still pending: 2 [guid-1, guid-2]"),
+		))
+	})
+
 	It("Shows a final summary where instances could not start", func() {
 		result := logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
 			busyList := make([]string, 56)
-			listener.Finished(23, 34, 0, 45, busyList, nil)
+			listener.Finished(23, 34, 0, 45, busyList, nil, nil)
 		})
 
 		Expect(result).To(SatisfyAll(
@@ -270,7 +324,7 @@ var _ = Describe("Logging Listener", func() {
 
 	It("Shows a final summary where a single service instance failed to process", func() {
 		result := logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
-			listener.Finished(23, 34, 0, 45, []string{"foo"}, []string{"2f9752c3-887b-4ccb-8693-7c15811ffbdd"})
+			listener.Finished(23, 34, 0, 45, []string{"foo"}, []string{"2f9752c3-887b-4ccb-8693-7c15811ffbdd"}, nil)
 		})
 
 		Expect(result).To(SatisfyAll(
@@ -285,7 +339,7 @@ var _ = Describe("Logging Listener", func() {
 
 	It("Shows a final summary where multiple services instances failed the operation", func() {
 		result := logResultsFromAsString(processType, func(listener instanceiterator.Listener) {
-			listener.Finished(23, 34, 12, 45, make([]string, 56), []string{"2f9752c3-887b-4ccb-8693-7c15811ffbdd", "7a2c7adb-1d47-4355-af39-41c5a2892b92"})
+			listener.Finished(23, 34, 12, 45, make([]string, 56), []string{"2f9752c3-887b-4ccb-8693-7c15811ffbdd", "7a2c7adb-1d47-4355-af39-41c5a2892b92"}, nil)
 		})
 
 		Expect(result).To(SatisfyAll(
@@ -0,0 +1,337 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+// MaintenanceWindow is a recurring period during which the instances it
+// applies to may be processed. A window with no plan, org or labels applies to
+// every instance.
+type MaintenanceWindow struct {
+	Plan     string
+	Org      string
+	Labels   map[string]string
+	schedule cronSchedule
+	duration time.Duration
+	location *time.Location
+}
+
+func NewMaintenanceWindows(conf []config.MaintenanceWindow) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, c := range conf {
+		schedule, err := parseCronSchedule(c.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window schedule %q: %s", c.Schedule, err)
+		}
+
+		duration, err := time.ParseDuration(c.Duration)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid maintenance window duration %q: must be a positive duration such as 4h", c.Duration)
+		}
+
+		location := time.UTC
+		if c.TimeZone != "" {
+			location, err = time.LoadLocation(c.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid maintenance window time zone %q: %s", c.TimeZone, err)
+			}
+		}
+
+		windows = append(windows, MaintenanceWindow{
+			Plan:     c.Plan,
+			Org:      c.Org,
+			Labels:   c.Labels,
+			schedule: schedule,
+			duration: duration,
+			location: location,
+		})
+	}
+	return windows, nil
+}
+
+// OpenAt reports whether a window started less than its duration before t.
+func (w MaintenanceWindow) OpenAt(t time.Time) bool {
+	t = t.In(w.location)
+	start, found := w.schedule.latestStart(t, t.Add(-w.duration))
+	return found && t.Sub(start) < w.duration
+}
+
+// NextOpening returns when the window next opens after t, if it does so by
+// limit.
+func (w MaintenanceWindow) NextOpening(t, limit time.Time) (time.Time, bool) {
+	return w.schedule.nextStart(t.In(w.location), limit)
+}
+
+// instanceFilter selects the instances in the org and with the labels of the
+// window, the same way canaries are selected.
+func (w MaintenanceWindow) instanceFilter() map[string]string {
+	filter := map[string]string{}
+	if w.Org != "" {
+		filter["org"] = w.Org
+	}
+	if len(w.Labels) > 0 {
+		var labels []string
+		for key, value := range w.Labels {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+		filter[service.LabelsFilterKey] = strings.Join(labels, ",")
+	}
+	return filter
+}
+
+// maintenanceWindowsByInstance finds the windows that apply to each instance.
+// The broker lists the instances of each org and set of labels once.
+func maintenanceWindowsByInstance(windows []MaintenanceWindow, instances []service.Instance, brokerServices BrokerServices) (map[string][]int, error) {
+	listed := map[string]map[string]bool{}
+	windowInstances := make([]map[string]bool, len(windows))
+	for i, w := range windows {
+		filter := w.instanceFilter()
+		if len(filter) == 0 {
+			continue
+		}
+
+		key := fmt.Sprint(filter)
+		if listed[key] == nil {
+			selected, err := brokerServices.Instances(filter)
+			if err != nil {
+				return nil, fmt.Errorf("error listing service instances with %s: %s", key, err)
+			}
+			listed[key] = map[string]bool{}
+			for _, instance := range selected {
+				listed[key][instance.GUID] = true
+			}
+		}
+		windowInstances[i] = listed[key]
+	}
+
+	byInstance := map[string][]int{}
+	for _, instance := range instances {
+		for i, w := range windows {
+			if w.Plan != "" && w.Plan != instance.PlanUniqueID {
+				continue
+			}
+			if windowInstances[i] != nil && !windowInstances[i][instance.GUID] {
+				continue
+			}
+			byInstance[instance.GUID] = append(byInstance[instance.GUID], i)
+		}
+	}
+	return byInstance, nil
+}
+
+// cronSchedule matches the minutes described by a cron expression of the
+// form "minute hour day-of-month month day-of-week".
+type cronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	anyDayOfMonth, anyDayOfWeek                bool
+}
+
+func parseCronSchedule(expression string) (cronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var (
+		s   cronSchedule
+		err error
+	)
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.dayOfMonth, err = parseCronField(fields[2], 1, 31); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.dayOfWeek, err = parseCronField(fields[4], 0, 7); err != nil {
+		return cronSchedule{}, err
+	}
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1 // both 0 and 7 are Sunday
+	}
+	s.anyDayOfMonth = fields[2] == "*"
+	s.anyDayOfWeek = fields[4] == "*"
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		first, last := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if first, err = parseCronValue(bounds[0], min, max); err != nil {
+				return 0, err
+			}
+			if last, err = parseCronValue(bounds[1], min, max); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			var err error
+			if first, err = parseCronValue(rangePart, min, max); err != nil {
+				return 0, err
+			}
+			if !strings.Contains(part, "/") {
+				last = first
+			}
+		}
+
+		for v := first; v <= last; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, min, max int) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("%q is not between %d and %d", value, min, max)
+	}
+	return v, nil
+}
+
+// latestStart returns the last minute of the schedule at or before t, if it is
+// after earliest. Only the days in between are visited; the hour and minute of
+// a day are read off the bits of the schedule.
+func (s cronSchedule) latestStart(t, earliest time.Time) (time.Time, bool) {
+	hour, minute := t.Hour(), t.Minute()
+	for day := midnight(t); day.AddDate(0, 0, 1).After(earliest); day = day.AddDate(0, 0, -1) {
+		if s.matchesDay(day) {
+			if h, m, found := s.latestTimeOfDay(hour, minute); found {
+				start := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, day.Location())
+				return start, start.After(earliest)
+			}
+		}
+		hour, minute = 23, 59
+	}
+	return time.Time{}, false
+}
+
+// nextStart returns the first minute of the schedule after t, if it is not
+// after limit.
+func (s cronSchedule) nextStart(t, limit time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	hour, minute := t.Hour(), t.Minute()
+	for day := midnight(t); !day.After(limit); day = day.AddDate(0, 0, 1) {
+		if s.matchesDay(day) {
+			if h, m, found := s.earliestTimeOfDay(hour, minute); found {
+				start := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, day.Location())
+				return start, !start.After(limit)
+			}
+		}
+		hour, minute = 0, 0
+	}
+	return time.Time{}, false
+}
+
+func (s cronSchedule) latestTimeOfDay(hour, minute int) (int, int, bool) {
+	for h := highestBit(s.hour, hour); h >= 0; h = highestBit(s.hour, h-1) {
+		maxMinute := 59
+		if h == hour {
+			maxMinute = minute
+		}
+		if m := highestBit(s.minute, maxMinute); m >= 0 {
+			return h, m, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (s cronSchedule) earliestTimeOfDay(hour, minute int) (int, int, bool) {
+	for h := lowestBit(s.hour, hour); h >= 0; h = lowestBit(s.hour, h+1) {
+		minMinute := 0
+		if h == hour {
+			minMinute = minute
+		}
+		if m := lowestBit(s.minute, minMinute); m >= 0 {
+			return h, m, true
+		}
+	}
+	return 0, 0, false
+}
+
+// highestBit returns the highest bit set in set that is at most max, or -1.
+func highestBit(set uint64, max int) int {
+	if max < 0 {
+		return -1
+	}
+	return bits.Len64(set&(1<<uint(max+1)-1)) - 1
+}
+
+// lowestBit returns the lowest bit set in set that is at least min, or -1.
+func lowestBit(set uint64, min int) int {
+	if min > 63 || set>>uint(min) == 0 {
+		return -1
+	}
+	return min + bits.TrailingZeros64(set>>uint(min))
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (s cronSchedule) matchesDay(t time.Time) bool {
+	if s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDayOfMonth && s.anyDayOfWeek:
+		return true
+	case s.anyDayOfMonth:
+		return dayOfWeek
+	case s.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		// as in cron, a day matches if either of the day fields does
+		return dayOfMonth || dayOfWeek
+	}
+}
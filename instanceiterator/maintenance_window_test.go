@@ -0,0 +1,101 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+)
+
+var _ = Describe("Maintenance Window", func() {
+	window := func(schedule, duration, timeZone string) instanceiterator.MaintenanceWindow {
+		GinkgoHelper()
+		windows, err := instanceiterator.NewMaintenanceWindows([]config.MaintenanceWindow{{Schedule: schedule, Duration: duration, TimeZone: timeZone}})
+		Expect(err).NotTo(HaveOccurred())
+		return windows[0]
+	}
+
+	at := func(value string) time.Time {
+		GinkgoHelper()
+		t, err := time.Parse(time.RFC3339, value)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+
+	DescribeTable("is open",
+		func(schedule, duration, timeZone, now string, open bool) {
+			Expect(window(schedule, duration, timeZone).OpenAt(at(now))).To(Equal(open))
+		},
+		// 2024-06-01 is a Saturday
+		Entry("at the start of the window", "0 2 * * 6", "4h", "", "2024-06-01T02:00:00Z", true),
+		Entry("until the window closes", "0 2 * * 6", "4h", "", "2024-06-01T05:59:59Z", true),
+		Entry("not once the window has closed", "0 2 * * 6", "4h", "", "2024-06-01T06:00:00Z", false),
+		Entry("not before the window opens", "0 2 * * 6", "4h", "", "2024-06-01T01:59:00Z", false),
+		Entry("not on another day of the week", "0 2 * * 6", "4h", "", "2024-06-02T03:00:00Z", false),
+		Entry("past midnight", "30 22 * * 5", "3h", "", "2024-06-01T01:00:00Z", true),
+		Entry("with Sunday as 7", "0 0 * * 7", "1h", "", "2024-06-02T00:30:00Z", true),
+		Entry("on a range of days", "0 1 * * 1-5", "1h", "", "2024-06-03T01:15:00Z", true),
+		Entry("on a list of hours", "0 1,13 * * *", "30m", "", "2024-06-03T13:15:00Z", true),
+		Entry("on a step of hours", "0 */6 * * *", "1h", "", "2024-06-03T18:59:00Z", true),
+		Entry("not between steps", "0 */6 * * *", "1h", "", "2024-06-03T19:00:00Z", false),
+		Entry("on a day of the month", "0 3 15 * *", "2h", "", "2024-06-15T04:00:00Z", true),
+		Entry("on either the day of the month or of the week", "0 3 15 * 1", "2h", "", "2024-06-03T04:00:00Z", true),
+		Entry("in a month", "0 0 1 1 *", "24h", "", "2024-06-01T12:00:00Z", false),
+		Entry("in a time zone", "0 2 * * *", "1h", "Europe/London", "2024-06-01T01:30:00Z", true),
+		Entry("not in UTC when in a time zone", "0 2 * * *", "1h", "Europe/London", "2024-06-01T02:30:00Z", false),
+		Entry("days after a window that lasts several days opened", "0 22 * * 5", "72h", "", "2024-06-03T21:59:00Z", true),
+		Entry("not once a window that lasts several days has closed", "0 22 * * 5", "72h", "", "2024-06-03T22:00:00Z", false),
+		Entry("from the latest start of the window", "0,30 2 * * *", "15m", "", "2024-06-01T02:40:00Z", true),
+	)
+
+	DescribeTable("opens next",
+		func(schedule, timeZone, now, limit, next string) {
+			opening, found := window(schedule, "1h", timeZone).NextOpening(at(now), at(limit))
+			if next == "" {
+				Expect(found).To(BeFalse())
+				return
+			}
+			Expect(found).To(BeTrue())
+			Expect(opening).To(BeTemporally("==", at(next)))
+		},
+		Entry("later the same day", "0 2,14 * * *", "", "2024-06-01T03:00:00Z", "2024-07-01T00:00:00Z", "2024-06-01T14:00:00Z"),
+		Entry("not at the current minute", "0 2 * * *", "", "2024-06-01T02:00:00Z", "2024-07-01T00:00:00Z", "2024-06-02T02:00:00Z"),
+		Entry("on a later day of the week", "30 1 * * 3", "", "2024-06-01T03:00:00Z", "2024-07-01T00:00:00Z", "2024-06-05T01:30:00Z"),
+		Entry("in a later month", "0 0 1 9 *", "", "2024-06-01T03:00:00Z", "2025-01-01T00:00:00Z", "2024-09-01T00:00:00Z"),
+		Entry("in a time zone", "0 2 * * *", "Europe/London", "2024-06-01T03:00:00Z", "2024-07-01T00:00:00Z", "2024-06-02T01:00:00Z"),
+		Entry("not after the limit", "0 0 1 9 *", "", "2024-06-01T03:00:00Z", "2024-08-31T23:59:00Z", ""),
+		Entry("not when the schedule never matches", "0 0 30 2 *", "", "2024-06-01T03:00:00Z", "2034-06-01T00:00:00Z", ""),
+	)
+
+	DescribeTable("fails to parse",
+		func(window config.MaintenanceWindow, message string) {
+			_, err := instanceiterator.NewMaintenanceWindows([]config.MaintenanceWindow{window})
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("too few fields", config.MaintenanceWindow{Schedule: "0 2 * *", Duration: "1h"}, `invalid maintenance window schedule "0 2 * *": expected 5 fields, got 4`),
+		Entry("a value out of range", config.MaintenanceWindow{Schedule: "0 24 * * *", Duration: "1h"}, `"24" is not between 0 and 23`),
+		Entry("a reversed range", config.MaintenanceWindow{Schedule: "0 5-2 * * *", Duration: "1h"}, `invalid range "5-2"`),
+		Entry("a zero step", config.MaintenanceWindow{Schedule: "*/0 * * * *", Duration: "1h"}, `invalid step in "*/0"`),
+		Entry("a missing duration", config.MaintenanceWindow{Schedule: "0 2 * * *"}, `invalid maintenance window duration ""`),
+		Entry("a negative duration", config.MaintenanceWindow{Schedule: "0 2 * * *", Duration: "-1h"}, `invalid maintenance window duration "-1h"`),
+		Entry("an unknown time zone", config.MaintenanceWindow{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Nowhere/Special"}, `invalid maintenance window time zone "Nowhere/Special"`),
+	)
+})
@@ -0,0 +1,182 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package instanceiterator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"github.com/pkg/errors"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+// PlanMigration is a config.PlanMigration resolved against the catalog of the
+// broker. TargetPlanGUID is only needed to migrate through CF.
+type PlanMigration struct {
+	ServiceID       string
+	SourcePlanID    string
+	TargetPlanID    string
+	TargetPlanGUID  string
+	MaintenanceInfo *domain.MaintenanceInfo
+	Parameters      map[string]interface{}
+}
+
+// planMigrations holds the migrations by source plan. Instances of other plans
+// are not selected, so they are neither processed nor picked as canaries.
+type planMigrations map[string]PlanMigration
+
+func newPlanMigrations(migrations []PlanMigration) planMigrations {
+	bySourcePlan := planMigrations{}
+	for _, migration := range migrations {
+		bySourcePlan[migration.SourcePlanID] = migration
+	}
+	return bySourcePlan
+}
+
+func (m planMigrations) Selects(instance service.Instance) bool {
+	_, found := m[instance.PlanUniqueID]
+	return found
+}
+
+// MigratePlanTriggerer moves instances to the target plan of their migration
+// by updating them through the broker, as CF would.
+type MigratePlanTriggerer struct {
+	*BOSHTriggerer
+	planMigrations
+}
+
+func NewMigratePlanTriggerer(brokerServices BrokerServices, migrations []PlanMigration) *MigratePlanTriggerer {
+	return &MigratePlanTriggerer{
+		BOSHTriggerer:  &BOSHTriggerer{operationType: "migrate-plan", brokerServices: brokerServices},
+		planMigrations: newPlanMigrations(migrations),
+	}
+}
+
+func (t *MigratePlanTriggerer) TriggerOperation(instance service.Instance) (TriggeredOperation, error) {
+	migration, found := t.planMigrations[instance.PlanUniqueID]
+	if !found {
+		return TriggeredOperation{State: OperationSkipped}, nil
+	}
+
+	details := domain.UpdateDetails{
+		ServiceID:       migration.ServiceID,
+		PlanID:          migration.TargetPlanID,
+		RawContext:      json.RawMessage(fmt.Sprintf(`{"space_guid":%q,"organization_guid":%q}`, instance.SpaceGUID, instance.OrganizationGUID)),
+		MaintenanceInfo: migration.MaintenanceInfo,
+		PreviousValues: domain.PreviousValues{
+			PlanID:    instance.PlanUniqueID,
+			ServiceID: migration.ServiceID,
+		},
+	}
+	if len(migration.Parameters) > 0 {
+		rawParameters, err := json.Marshal(migration.Parameters)
+		if err != nil {
+			return TriggeredOperation{}, fmt.Errorf("invalid parameters for plan %s: %s", migration.TargetPlanID, err)
+		}
+		details.RawParameters = rawParameters
+	}
+
+	operation, err := t.brokerServices.UpdatePlan(instance, details)
+	if err != nil {
+		return TriggeredOperation{},
+			fmt.Errorf(
+				"operation type: %s failed for service instance %s: %s",
+				t.operationType,
+				instance.GUID,
+				err,
+			)
+	}
+	return t.translateTriggerResponse(operation), nil
+}
+
+// CFMigratePlanTriggerer moves instances to the target plan of their
+// migration through CF, so that CF records the new plan of the instances.
+type CFMigratePlanTriggerer struct {
+	*CFTriggerer
+	planMigrations
+}
+
+func NewCFMigratePlanTriggerer(client CFClient, migrations []PlanMigration, logger *log.Logger) *CFMigratePlanTriggerer {
+	return &CFMigratePlanTriggerer{
+		CFTriggerer:    NewCFTrigger(client, logger),
+		planMigrations: newPlanMigrations(migrations),
+	}
+}
+
+func (t *CFMigratePlanTriggerer) TriggerOperation(instance service.Instance) (TriggeredOperation, error) {
+	migration, found := t.planMigrations[instance.PlanUniqueID]
+	if !found {
+		return TriggeredOperation{State: OperationSkipped}, nil
+	}
+
+	lastOperation, err := t.cfClient.UpdateServiceInstancePlan(instance.GUID, migration.TargetPlanGUID, migration.Parameters, t.logger)
+	if err != nil {
+		return TriggeredOperation{}, errors.Wrapf(err, "failed to trigger operation for instance %q", instance.GUID)
+	}
+
+	return t.translateTriggerResponse(lastOperation), nil
+}
+
+// resolvePlanMigrations checks the migrations against the catalog of the
+// broker: each source plan is migrated once, to another plan of the same
+// service offering.
+func resolvePlanMigrations(catalog []domain.Service, migrations []config.PlanMigration) ([]PlanMigration, error) {
+	if len(migrations) == 0 {
+		return nil, errors.New("no plan migrations configured")
+	}
+
+	var resolved []PlanMigration
+	sourcePlans := map[string]bool{}
+	for _, migration := range migrations {
+		if migration.SourcePlanID == "" || migration.TargetPlanID == "" {
+			return nil, errors.New("plan migrations require a source_plan_id and a target_plan_id")
+		}
+		if migration.SourcePlanID == migration.TargetPlanID {
+			return nil, fmt.Errorf("cannot migrate plan %s to itself", migration.SourcePlanID)
+		}
+		if sourcePlans[migration.SourcePlanID] {
+			return nil, fmt.Errorf("plan %s is migrated more than once", migration.SourcePlanID)
+		}
+		sourcePlans[migration.SourcePlanID] = true
+
+		sourceService, _, found := findCatalogPlan(catalog, migration.SourcePlanID)
+		if !found {
+			return nil, fmt.Errorf("source plan %s not found in the catalog", migration.SourcePlanID)
+		}
+		targetService, targetPlan, found := findCatalogPlan(catalog, migration.TargetPlanID)
+		if !found {
+			return nil, fmt.Errorf("target plan %s not found in the catalog", migration.TargetPlanID)
+		}
+		if sourceService.ID != targetService.ID {
+			return nil, fmt.Errorf("cannot migrate plan %s to plan %s of another service offering", migration.SourcePlanID, migration.TargetPlanID)
+		}
+
+		resolved = append(resolved, PlanMigration{
+			ServiceID:       targetService.ID,
+			SourcePlanID:    migration.SourcePlanID,
+			TargetPlanID:    migration.TargetPlanID,
+			MaintenanceInfo: targetPlan.MaintenanceInfo,
+			Parameters:      migration.Parameters,
+		})
+	}
+	return resolved, nil
+}
+
+func findCatalogPlan(catalog []domain.Service, planID string) (domain.Service, domain.ServicePlan, bool) {
+	for _, service := range catalog {
+		for _, plan := range service.Plans {
+			if plan.ID == planID {
+				return service, plan, true
+			}
+		}
+	}
+	return domain.Service{}, domain.ServicePlan{}, false
+}
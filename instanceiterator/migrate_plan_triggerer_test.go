@@ -0,0 +1,157 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"errors"
+	"io"
+	"log"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+var _ = Describe("Migrate plan triggerers", func() {
+	var (
+		migrations []instanceiterator.PlanMigration
+		instance   service.Instance
+	)
+
+	BeforeEach(func() {
+		migrations = []instanceiterator.PlanMigration{{
+			ServiceID:       "service-id",
+			SourcePlanID:    "source-plan-id",
+			TargetPlanID:    "target-plan-id",
+			TargetPlanGUID:  "target-plan-guid",
+			MaintenanceInfo: &domain.MaintenanceInfo{Version: "1.2.3"},
+			Parameters:      map[string]interface{}{"size": "large"},
+		}}
+		instance = service.Instance{GUID: "some-guid", PlanUniqueID: "source-plan-id", SpaceGUID: "space-guid", OrganizationGUID: "org-guid"}
+	})
+
+	Describe("MigratePlanTriggerer", func() {
+		var (
+			fakeBrokerServices *fakes.FakeBrokerServices
+			subject            *instanceiterator.MigratePlanTriggerer
+		)
+
+		BeforeEach(func() {
+			fakeBrokerServices = new(fakes.FakeBrokerServices)
+			subject = instanceiterator.NewMigratePlanTriggerer(fakeBrokerServices, migrations)
+		})
+
+		It("updates the instance to the target plan through the broker", func() {
+			fakeBrokerServices.UpdatePlanReturns(services.BOSHOperation{
+				Type: services.OperationAccepted,
+				Data: broker.OperationData{BoshTaskID: 42},
+			}, nil)
+
+			operation, err := subject.TriggerOperation(instance)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation).To(Equal(instanceiterator.TriggeredOperation{
+				State: instanceiterator.OperationAccepted,
+				Data:  broker.OperationData{BoshTaskID: 42},
+			}))
+
+			Expect(fakeBrokerServices.UpdatePlanCallCount()).To(Equal(1))
+			updatedInstance, details := fakeBrokerServices.UpdatePlanArgsForCall(0)
+			Expect(updatedInstance).To(Equal(instance))
+			Expect(details.ServiceID).To(Equal("service-id"))
+			Expect(details.PlanID).To(Equal("target-plan-id"))
+			Expect(details.MaintenanceInfo).To(Equal(&domain.MaintenanceInfo{Version: "1.2.3"}))
+			Expect(details.PreviousValues).To(Equal(domain.PreviousValues{PlanID: "source-plan-id", ServiceID: "service-id"}))
+			Expect(details.RawParameters).To(MatchJSON(`{"size": "large"}`))
+			Expect(details.RawContext).To(MatchJSON(`{"space_guid": "space-guid", "organization_guid": "org-guid"}`))
+		})
+
+		It("skips instances of plans that are not migrated", func() {
+			instance.PlanUniqueID = "target-plan-id"
+
+			operation, err := subject.TriggerOperation(instance)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.State).To(Equal(instanceiterator.OperationSkipped))
+			Expect(fakeBrokerServices.UpdatePlanCallCount()).To(BeZero())
+		})
+
+		It("selects only the instances of the source plans", func() {
+			Expect(subject.Selects(instance)).To(BeTrue())
+			Expect(subject.Selects(service.Instance{PlanUniqueID: "other-plan-id"})).To(BeFalse())
+		})
+
+		It("returns an error when the broker rejects the update", func() {
+			fakeBrokerServices.UpdatePlanReturns(services.BOSHOperation{}, errors.New("quota exceeded"))
+
+			_, err := subject.TriggerOperation(instance)
+
+			Expect(err).To(MatchError("operation type: migrate-plan failed for service instance some-guid: quota exceeded"))
+		})
+
+		It("checks the update through the last operation of the broker", func() {
+			fakeBrokerServices.LastOperationReturns(domain.LastOperation{State: domain.Succeeded}, nil)
+
+			operation, err := subject.Check("some-guid", broker.OperationData{BoshTaskID: 42})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.State).To(Equal(instanceiterator.OperationSucceeded))
+		})
+	})
+
+	Describe("CFMigratePlanTriggerer", func() {
+		var (
+			fakeCFClient *fakes.FakeCFClient
+			subject      *instanceiterator.CFMigratePlanTriggerer
+		)
+
+		BeforeEach(func() {
+			fakeCFClient = new(fakes.FakeCFClient)
+			subject = instanceiterator.NewCFMigratePlanTriggerer(fakeCFClient, migrations, log.New(io.Discard, "", 0))
+		})
+
+		It("updates the plan of the instance through CF", func() {
+			fakeCFClient.UpdateServiceInstancePlanReturns(cf.LastOperation{State: cf.OperationStateInProgress}, nil)
+
+			operation, err := subject.TriggerOperation(instance)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.State).To(Equal(instanceiterator.OperationAccepted))
+
+			Expect(fakeCFClient.UpdateServiceInstancePlanCallCount()).To(Equal(1))
+			instanceGUID, planGUID, parameters, _ := fakeCFClient.UpdateServiceInstancePlanArgsForCall(0)
+			Expect(instanceGUID).To(Equal("some-guid"))
+			Expect(planGUID).To(Equal("target-plan-guid"))
+			Expect(parameters).To(Equal(map[string]interface{}{"size": "large"}))
+		})
+
+		It("skips instances of plans that are not migrated", func() {
+			instance.PlanUniqueID = "other-plan-id"
+
+			operation, err := subject.TriggerOperation(instance)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operation.State).To(Equal(instanceiterator.OperationSkipped))
+			Expect(fakeCFClient.UpdateServiceInstancePlanCallCount()).To(BeZero())
+		})
+
+		It("returns an error when CF rejects the update", func() {
+			fakeCFClient.UpdateServiceInstancePlanReturns(cf.LastOperation{}, errors.New("quota exceeded"))
+
+			_, err := subject.TriggerOperation(instance)
+
+			Expect(err).To(MatchError(`failed to trigger operation for instance "some-guid": quota exceeded`))
+		})
+	})
+})
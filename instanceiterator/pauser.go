@@ -0,0 +1,53 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+)
+
+// Pauser tells the iterator to stop triggering new operations. Operations
+// already triggered are still waited for.
+//
+//counterfeiter:generate -o fakes/fake_pauser.go . Pauser
+type Pauser interface {
+	Paused() bool
+}
+
+// SignalPauser is paused by one signal and unpaused by another.
+type SignalPauser struct {
+	paused atomic.Bool
+}
+
+func NewSignalPauser(pause, unpause os.Signal) *SignalPauser {
+	p := &SignalPauser{}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, pause, unpause)
+	go func() {
+		for s := range signals {
+			p.paused.Store(s == pause)
+		}
+	}()
+
+	return p
+}
+
+func (p *SignalPauser) Paused() bool {
+	return p.paused.Load()
+}
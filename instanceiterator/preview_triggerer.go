@@ -0,0 +1,75 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+// PreviewTriggerer logs what an operation would change on each instance
+// instead of running it. Instances whose manifest would change are reported as
+// previewed, and the others as skipped.
+type PreviewTriggerer struct {
+	operationType  string
+	brokerServices BrokerServices
+	logger         *log.Logger
+}
+
+func NewUpgradePreviewTriggerer(brokerServices BrokerServices, logger *log.Logger) *PreviewTriggerer {
+	return &PreviewTriggerer{operationType: "upgrade", brokerServices: brokerServices, logger: logger}
+}
+
+func (t *PreviewTriggerer) TriggerOperation(instance service.Instance) (TriggeredOperation, error) {
+	preview, err := t.brokerServices.PreviewInstance(instance, t.operationType)
+	if err != nil {
+		return TriggeredOperation{},
+			fmt.Errorf(
+				"preview of operation type: %s failed for service instance %s: %s",
+				t.operationType,
+				instance.GUID,
+				err,
+			)
+	}
+
+	switch preview.Type {
+	case services.InstanceNotFound:
+		return TriggeredOperation{State: InstanceNotFound}, nil
+	case services.OrphanDeployment:
+		return TriggeredOperation{State: OrphanDeployment}, nil
+	}
+
+	if preview.Diff.Empty() {
+		t.logger.Printf("[%s] %s would not change the manifest\n", instance.GUID, t.operationType)
+		return TriggeredOperation{State: OperationSkipped, Description: "no changes"}, nil
+	}
+
+	diff, err := json.MarshalIndent(preview.Diff, "", "  ")
+	if err != nil {
+		return TriggeredOperation{}, err
+	}
+	t.logger.Printf("[%s] %s would change the manifest:\n%s\n", instance.GUID, t.operationType, diff)
+	return TriggeredOperation{State: OperationPreviewed, Description: string(diff)}, nil
+}
+
+func (t *PreviewTriggerer) Check(serviceInstanceGUID string, operationData broker.OperationData) (TriggeredOperation, error) {
+	return TriggeredOperation{}, fmt.Errorf("no operation was triggered for service instance %s in preview mode", serviceInstanceGUID)
+}
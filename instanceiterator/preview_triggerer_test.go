@@ -0,0 +1,96 @@
+// Copyright (C) 2015-Present Pivotal Software, Inc. All rights reserved.
+
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instanceiterator_test
+
+import (
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator"
+	"github.com/pivotal-cf/on-demand-service-broker/instanceiterator/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+var _ = Describe("Preview Triggerer", func() {
+	var (
+		instance          service.Instance
+		fakeBrokerService *fakes.FakeBrokerServices
+		logBuffer         *gbytes.Buffer
+		subject           instanceiterator.Triggerer
+	)
+
+	BeforeEach(func() {
+		instance = service.Instance{GUID: "some-guid"}
+		fakeBrokerService = new(fakes.FakeBrokerServices)
+		logBuffer = gbytes.NewBuffer()
+
+		subject = instanceiterator.NewUpgradePreviewTriggerer(fakeBrokerService, log.New(logBuffer, "", 0))
+	})
+
+	It("logs the changes and reports the instance as previewed", func() {
+		fakeBrokerService.PreviewInstanceReturns(services.InstancePreview{
+			Type: services.OperationSucceeded,
+			Diff: broker.ManifestDiff{
+				Releases: []broker.ManifestChange{{Path: "/releases/name=redis/version", Before: "1", After: "2"}},
+			},
+		}, nil)
+
+		operation, err := subject.TriggerOperation(instance)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operation.State).To(Equal(instanceiterator.OperationPreviewed))
+		Expect(operation.Description).To(ContainSubstring(`"path": "/releases/name=redis/version"`))
+		previewedInstance, operationType := fakeBrokerService.PreviewInstanceArgsForCall(0)
+		Expect(previewedInstance).To(Equal(instance))
+		Expect(operationType).To(Equal("upgrade"))
+		Expect(fakeBrokerService.ProcessInstanceCallCount()).To(BeZero())
+		Expect(logBuffer).To(gbytes.Say(`\[some-guid\] upgrade would change the manifest:`))
+		Expect(logBuffer).To(gbytes.Say(`"path": "/releases/name=redis/version"`))
+	})
+
+	It("logs when there are no changes", func() {
+		fakeBrokerService.PreviewInstanceReturns(services.InstancePreview{Type: services.OperationSucceeded}, nil)
+
+		operation, err := subject.TriggerOperation(instance)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operation.State).To(Equal(instanceiterator.OperationSkipped))
+		Expect(logBuffer).To(gbytes.Say(`\[some-guid\] upgrade would not change the manifest`))
+	})
+
+	It("reports orphaned deployments", func() {
+		fakeBrokerService.PreviewInstanceReturns(services.InstancePreview{Type: services.OrphanDeployment}, nil)
+
+		operation, err := subject.TriggerOperation(instance)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operation.State).To(Equal(instanceiterator.OrphanDeployment))
+	})
+
+	It("returns an error when the preview fails", func() {
+		fakeBrokerService.PreviewInstanceReturns(services.InstancePreview{}, errors.New("adapter failed"))
+
+		_, err := subject.TriggerOperation(instance)
+
+		Expect(err).To(MatchError("preview of operation type: upgrade failed for service instance some-guid: adapter failed"))
+	})
+})
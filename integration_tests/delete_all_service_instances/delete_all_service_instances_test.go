@@ -111,7 +111,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithNoServiceInstances(),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithNoServiceInstances(),
 			)
 
 			configuration.PollingInitialOffset = 1
@@ -142,7 +142,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
 				mockcfapi.ListServiceBindings(instanceGUID).RespondsWithServiceBinding(serviceBindingGUID, instanceGUID, boundAppGUID),
 				mockcfapi.DeleteServiceBinding(boundAppGUID, serviceBindingGUID).RespondsNoContent(),
 				mockcfapi.ListServiceKeys(instanceGUID).RespondsWithServiceKey(serviceKeyGUID, instanceGUID),
@@ -153,7 +153,7 @@ var _ = Describe("delete all service instances tool", func() {
 				mockcfapi.GetServiceInstance(instanceGUID).RespondsNotFoundWith(""),
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithNoServiceInstances(),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithNoServiceInstances(),
 			)
 
 			params := []string{"-configFilePath", configFilePath}
@@ -236,7 +236,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
 				mockcfapi.ListServiceBindings(instanceGUID).RespondsWithServiceBinding(serviceBindingGUID, instanceGUID, boundAppGUID),
 				mockcfapi.DeleteServiceBinding(boundAppGUID, serviceBindingGUID).RespondsNotFoundWith(`{
 							"code": 111111,
@@ -255,7 +255,7 @@ var _ = Describe("delete all service instances tool", func() {
 				mockcfapi.GetServiceInstance(instanceGUID).RespondsNotFoundWith(""),
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithNoServiceInstances(),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithNoServiceInstances(),
 			)
 
 			params := []string{"-configFilePath", configFilePath}
@@ -273,7 +273,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
 				mockcfapi.ListServiceBindings(instanceGUID).RespondsNotFoundWith(`{
 							"code": 111111,
 							"description": "The app could not be found: some-bound-app-guid",
@@ -291,7 +291,7 @@ var _ = Describe("delete all service instances tool", func() {
 				mockcfapi.GetServiceInstance(instanceGUID).RespondsNotFoundWith(""),
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithNoServiceInstances(),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithNoServiceInstances(),
 			)
 
 			params := []string{"-configFilePath", configFilePath}
@@ -309,7 +309,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
 				mockcfapi.ListServiceBindings(instanceGUID).RespondsWithServiceBinding(serviceBindingGUID, instanceGUID, boundAppGUID),
 				mockcfapi.DeleteServiceBinding(boundAppGUID, serviceBindingGUID).RespondsNoContent(),
 				mockcfapi.ListServiceKeys(instanceGUID).RespondsNotFoundWith(`{
@@ -322,7 +322,7 @@ var _ = Describe("delete all service instances tool", func() {
 				mockcfapi.GetServiceInstance(instanceGUID).RespondsNotFoundWith(""),
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithNoServiceInstances(),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithNoServiceInstances(),
 			)
 
 			params := []string{"-configFilePath", configFilePath}
@@ -340,7 +340,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
 				mockcfapi.ListServiceBindings(instanceGUID).RespondsWithServiceBinding(serviceBindingGUID, instanceGUID, boundAppGUID),
 				mockcfapi.DeleteServiceBinding(boundAppGUID, serviceBindingGUID).RespondsForbiddenWith(`{
 						"code": 10003,
@@ -367,7 +367,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
 				mockcfapi.ListServiceBindings(instanceGUID).RespondsWithServiceBinding(serviceBindingGUID, instanceGUID, boundAppGUID),
 				mockcfapi.DeleteServiceBinding(boundAppGUID, serviceBindingGUID).RespondsNoContent(),
 				mockcfapi.ListServiceKeys(instanceGUID).RespondsWithServiceKey(serviceKeyGUID, instanceGUID),
@@ -393,7 +393,7 @@ var _ = Describe("delete all service instances tool", func() {
 			cfAPI.VerifyAndMock(
 				mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceID, "some-cc-service-offering-guid"),
 				mockcfapi.ListServicePlans("some-cc-service-offering-guid").RespondsWithServicePlan(planID, "some-cc-plan-guid"),
-				mockcfapi.ListServiceInstances("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
+				mockcfapi.ListServiceInstancesWithSpaces("some-cc-plan-guid").RespondsWithServiceInstances(instanceGUID),
 				mockcfapi.ListServiceBindings(instanceGUID).RespondsWithServiceBinding(serviceBindingGUID, instanceGUID, boundAppGUID),
 				mockcfapi.DeleteServiceBinding(boundAppGUID, serviceBindingGUID).RespondsNoContent(),
 				mockcfapi.ListServiceKeys(instanceGUID).RespondsWithServiceKey(serviceKeyGUID, instanceGUID),
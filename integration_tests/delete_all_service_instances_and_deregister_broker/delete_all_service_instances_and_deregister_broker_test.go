@@ -108,7 +108,7 @@ var _ = Describe("purge instances and deregister tool", func() {
 			// Step 2 of the purger, deleting all service instances
 			mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceOfferingName, serviceOfferingGUID),
 			mockcfapi.ListServicePlans(serviceOfferingGUID).RespondsWithServicePlan(planID, planGUID),
-			mockcfapi.ListServiceInstances(planGUID).RespondsWithServiceInstances(instanceGUID),
+			mockcfapi.ListServiceInstancesWithSpaces(planGUID).RespondsWithServiceInstances(instanceGUID),
 			mockcfapi.ListServiceBindings(instanceGUID).RespondsWithServiceBinding(serviceBindingGUID, instanceGUID, boundAppGUID),
 			mockcfapi.DeleteServiceBinding(boundAppGUID, serviceBindingGUID).RespondsNoContent(),
 			mockcfapi.ListServiceKeys(instanceGUID).RespondsWithServiceKey(serviceKeyGUID, instanceGUID),
@@ -119,7 +119,7 @@ var _ = Describe("purge instances and deregister tool", func() {
 			mockcfapi.GetServiceInstance(instanceGUID).RespondsNotFoundWith(""),
 			mockcfapi.ListServiceOfferings().RespondsWithServiceOffering(serviceOfferingName, serviceOfferingGUID),
 			mockcfapi.ListServicePlans(serviceOfferingGUID).RespondsWithServicePlan(planID, planGUID),
-			mockcfapi.ListServiceInstances(planGUID).RespondsWithNoServiceInstances(),
+			mockcfapi.ListServiceInstancesWithSpaces(planGUID).RespondsWithNoServiceInstances(),
 			// Step 3 of the purger, deregistering the broker
 			mockcfapi.ListServiceBrokers().RespondsWithBrokers(serviceBrokerName, serviceBrokerGUID),
 			mockcfapi.DeregisterBroker(serviceBrokerGUID).RespondsNoContent(),
@@ -15,6 +15,7 @@ import (
 	"github.com/onsi/gomega/gexec"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
@@ -74,8 +75,14 @@ var _ = Describe("Basic authentication for BOSH", func() {
 				boshDirector.VerifyAndMock(
 					mockbosh.Info().RespondsOKWith(`{}`),
 					mockbosh.Deployments().RespondsOKWith(fmt.Sprintf(`[{"Name": "not-the-one"}]`)),
+					mockbosh.ListConfigsOfType(broker.QuotaReservationConfigType).RespondsWithNoConfigs(),
+					mockbosh.UpdateConfig().RespondsOKForConfig(broker.QuotaReservationConfigType, deploymentName("some-instance-id")),
 					mockbosh.TasksInProgress(deploymentName("some-instance-id")).RespondsWithNoTasks(),
 					mockbosh.Deploy().RedirectsToTask(101),
+					mockbosh.UpdateConfig().RespondsOKForConfig(broker.InstanceMetadataConfigType, deploymentName("some-instance-id")),
+				)
+				// the BOSH CLI polls the deploy task while the broker records the instance metadata
+				boshDirector.MockInAnyOrder(
 					mockbosh.Task(101).RespondsWithTaskContainingState("in progress"),
 					mockbosh.Task(101).RespondsWithTaskContainingState("done"),
 					mockbosh.TaskOutputEvent(101).RespondsWithTaskOutput([]boshdirector.BoshTaskOutput{}),
@@ -143,6 +143,128 @@ var _ = Describe("Orphan Deployments", func() {
 		})
 	})
 
+	When("cleanup is enabled", func() {
+		var c config.OrphanDeploymentsErrandConfig
+
+		BeforeEach(func() {
+			broker = ghttp.NewServer()
+
+			c = config.OrphanDeploymentsErrandConfig{
+				BrokerAPI: config.BrokerAPI{
+					URL: broker.URL(),
+					Authentication: config.Authentication{
+						Basic: config.UserCredentials{
+							Username: brokerUsername,
+							Password: brokerPassword,
+						},
+					},
+				},
+				Cleanup: config.OrphanCleanup{
+					Enabled:         true,
+					GracePeriodSecs: 3600,
+					AllowList:       []string{"keep-me"},
+					PollingInterval: 1,
+				},
+			}
+		})
+
+		AfterEach(func() {
+			broker.Close()
+		})
+
+		It("requests the cleanup and waits for the deletions to complete", func() {
+			report := `{"dry_run":false,"orphans":[` +
+				`{"deployment_name":"service-instance_one","action":"deleting","operation":{"BoshTaskID":42,"OperationType":"delete"}},` +
+				`{"deployment_name":"service-instance_two","action":"marked"}]}`
+			broker.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/mgmt/orphan_deployments/cleanup"),
+					ghttp.VerifyBasicAuth(brokerUsername, brokerPassword),
+					ghttp.VerifyBody([]byte(`{"grace_period_in_seconds":3600,"allow_list":["keep-me"],"dry_run":false}`)),
+					ghttp.RespondWith(http.StatusOK, report),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/service_instances/one/last_operation"),
+					ghttp.RespondWith(http.StatusOK, `{"state":"in progress"}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v2/service_instances/one/last_operation"),
+					ghttp.RespondWith(http.StatusOK, `{"state":"succeeded"}`),
+				),
+			)
+
+			session := helpers.StartBinaryWithParams(binaryPath, []string{"-configPath", write(c)})
+
+			Eventually(session, 5).Should(gexec.Exit(0))
+			Expect(session.Out).To(gbytes.Say(`"deployment_name":"service-instance_two","action":"marked"`))
+			Expect(session.Err).To(gbytes.Say("deleted orphan deployment service-instance_one"))
+			Expect(broker.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("fails when a deletion fails", func() {
+			report := `{"dry_run":false,"orphans":[{"deployment_name":"service-instance_one","action":"deleting","operation":{"BoshTaskID":42,"OperationType":"delete"}}]}`
+			broker.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, report),
+				ghttp.RespondWith(http.StatusOK, `{"state":"failed","description":"Instance deletion failed"}`),
+			)
+
+			session := helpers.StartBinaryWithParams(binaryPath, []string{"-configPath", write(c)})
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(SatisfyAll(
+				gbytes.Say("failed to delete orphan deployment service-instance_one: Instance deletion failed"),
+				gbytes.Say("failed to delete orphan deployments: service-instance_one"),
+			))
+		})
+
+		It("exits with code 10 on a dry run that finds orphans", func() {
+			c.Cleanup.DryRun = true
+			broker.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyBody([]byte(`{"grace_period_in_seconds":3600,"allow_list":["keep-me"],"dry_run":true}`)),
+					ghttp.RespondWith(http.StatusOK, `{"dry_run":true,"orphans":[{"deployment_name":"service-instance_one","action":"deleting"}]}`),
+				),
+			)
+
+			session := helpers.StartBinaryWithParams(binaryPath, []string{"-configPath", write(c)})
+
+			Eventually(session).Should(gexec.Exit(10))
+			Expect(broker.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("exits with 0 on a dry run that skips every orphan", func() {
+			c.Cleanup.DryRun = true
+			broker.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"dry_run":true,"orphans":[`+
+					`{"deployment_name":"keep-me","action":"allow-listed"},`+
+					`{"deployment_name":"service-instance_other","action":"plan-not-found"}]}`),
+			)
+
+			session := helpers.StartBinaryWithParams(binaryPath, []string{"-configPath", write(c)})
+
+			Eventually(session).Should(gexec.Exit(0))
+		})
+
+		It("fails when the polling interval is not set", func() {
+			c.Cleanup.PollingInterval = 0
+
+			session := helpers.StartBinaryWithParams(binaryPath, []string{"-configPath", write(c)})
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("cleanup.polling_interval must be greater than zero"))
+		})
+
+		It("fails when the grace period is not set", func() {
+			c.Cleanup.GracePeriodSecs = 0
+
+			session := helpers.StartBinaryWithParams(binaryPath, []string{"-configPath", write(c)})
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(session.Err).To(gbytes.Say("cleanup.grace_period_in_seconds must be greater than zero"))
+			Expect(broker.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
 	When("the broker is running HTTPS", func() {
 		var c config.OrphanDeploymentsErrandConfig
 
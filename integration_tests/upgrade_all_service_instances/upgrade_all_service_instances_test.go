@@ -60,6 +60,7 @@ var _ = Describe("running the tool to upgrade all service instances", func() {
 				serviceInstancesHandler, instanceID, serviceInstances = handleServiceInstanceList(broker)
 				upgradeHandler = handleBOSHServiceInstanceUpgrade(broker)
 				lastOperationHandler = handleBOSHLastOperation(broker)
+				handleIteratorCheckpoints(broker)
 			})
 
 			AfterEach(func() {
@@ -297,6 +298,7 @@ var _ = Describe("running the tool to upgrade all service instances", func() {
 				handleServiceInstanceList(broker)
 				handleBOSHServiceInstanceUpgrade(broker)
 				handleBOSHLastOperation(broker)
+				handleIteratorCheckpoints(broker)
 			})
 
 			AfterEach(func() {
@@ -343,6 +345,7 @@ var _ = Describe("running the tool to upgrade all service instances", func() {
 
 			handleBOSHServiceInstanceUpgrade(broker)
 			handleBOSHLastOperation(broker)
+			handleIteratorCheckpoints(broker)
 
 			handleCFInfo(cfApi)
 			handleCFServicePlans(cfApi)
@@ -492,6 +495,15 @@ func handleBOSHLastOperation(broker *ghttp.Server) *FakeHandler {
 	return lastOperationHandler
 }
 
+// handleIteratorCheckpoints stores no checkpoints, so that every run starts
+// afresh.
+func handleIteratorCheckpoints(broker *ghttp.Server) {
+	checkpointPath := regexp.MustCompile(`/mgmt/iterator_checkpoints/.*`)
+	broker.RouteToHandler(http.MethodGet, checkpointPath, ghttp.RespondWith(http.StatusNotFound, nil))
+	broker.RouteToHandler(http.MethodPut, checkpointPath, ghttp.RespondWith(http.StatusNoContent, nil))
+	broker.RouteToHandler(http.MethodDelete, checkpointPath, ghttp.RespondWith(http.StatusNoContent, nil))
+}
+
 func handleUAA(uaaAPI *ghttp.Server) {
 	uaaAuthenticationHandler := new(FakeHandler)
 	uaaAPI.RouteToHandler(http.MethodPost, regexp.MustCompile(`/oauth/token`), ghttp.CombineHandlers(
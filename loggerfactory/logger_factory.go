@@ -20,30 +20,71 @@ import (
 const Flags = log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC
 
 type LoggerFactory struct {
-	out  io.Writer
-	name string
-	flag int
+	out        io.Writer
+	name       string
+	flag       int
+	structured bool
 }
 
 func New(out io.Writer, name string, flag int) *LoggerFactory {
 	return &LoggerFactory{out: out, name: name, flag: flag}
 }
 
+// NewStructured returns a factory whose loggers write each message as a line
+// of JSON, with the values of the broker context as fields.
+func NewStructured(out io.Writer, name string) *LoggerFactory {
+	return &LoggerFactory{out: out, name: name, structured: true}
+}
+
 func (l *LoggerFactory) NewWithContext(ctx context.Context) *log.Logger {
-	if brokercontext.GetReqID(ctx) == "" {
-		return l.New()
+	if l.structured {
+		return l.structuredLogger(contextFields(ctx))
 	}
 
-	prefix := fmt.Sprintf("[%s] [%s] ", l.name, brokercontext.GetReqID(ctx))
+	prefix := fmt.Sprintf("[%s] ", l.name)
+	if brokercontext.GetReqID(ctx) != "" {
+		prefix = fmt.Sprintf("[%s] [%s] ", l.name, brokercontext.GetReqID(ctx))
+	}
 	return log.New(l.out, prefix, l.flag)
 }
 
 func (l *LoggerFactory) NewWithRequestID() *log.Logger {
-	prefix := fmt.Sprintf("[%s] [%s] ", l.name, uuid.New())
+	requestID := uuid.New()
+	if l.structured {
+		return l.structuredLogger(Fields{"request_id": requestID})
+	}
+
+	prefix := fmt.Sprintf("[%s] [%s] ", l.name, requestID)
 	return log.New(l.out, prefix, l.flag)
 }
 
 func (l *LoggerFactory) New() *log.Logger {
+	if l.structured {
+		return l.structuredLogger(Fields{})
+	}
+
 	prefix := fmt.Sprintf("[%s] ", l.name)
 	return log.New(l.out, prefix, l.flag)
 }
+
+func (l *LoggerFactory) structuredLogger(fields Fields) *log.Logger {
+	return log.New(&structuredWriter{out: l.out, source: l.name, fields: fields}, "", 0)
+}
+
+func contextFields(ctx context.Context) Fields {
+	fields := Fields{}
+	for key, value := range map[string]string{
+		"request_id":   brokercontext.GetReqID(ctx),
+		"operation":    brokercontext.GetOperation(ctx),
+		"service_name": brokercontext.GetServiceName(ctx),
+		"instance_id":  brokercontext.GetInstanceID(ctx),
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	if boshTaskID := brokercontext.GetBoshTaskID(ctx); boshTaskID != 0 {
+		fields["bosh_task_id"] = boshTaskID
+	}
+	return fields
+}
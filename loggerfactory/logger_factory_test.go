@@ -9,7 +9,9 @@ package loggerfactory_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"log"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -88,4 +90,111 @@ var _ = Describe("Logger Factory", func() {
 			})
 		})
 	})
+
+	Context("structured logging", func() {
+		var logs *bytes.Buffer
+
+		entry := func() map[string]interface{} {
+			var e map[string]interface{}
+			Expect(json.Unmarshal(logs.Bytes(), &e)).To(Succeed())
+			return e
+		}
+
+		BeforeEach(func() {
+			logs = &bytes.Buffer{}
+		})
+
+		It("logs each message as a line of JSON", func() {
+			logger := loggerfactory.NewStructured(logs, "some-name").New()
+			logger.Println("some log message")
+
+			Expect(logs.String()).To(HaveSuffix("}\n"))
+			e := entry()
+			Expect(e).To(HaveKeyWithValue("source", "some-name"))
+			Expect(e).To(HaveKeyWithValue("level", "info"))
+			Expect(e).To(HaveKeyWithValue("message", "some log message"))
+			Expect(e).To(HaveKey("timestamp"))
+		})
+
+		It("adds the values of the broker context", func() {
+			ctx := brokercontext.New(context.Background(), "update", "some-request-id", "some-service", "some-instance")
+			ctx = brokercontext.WithBoshTaskID(ctx, 42)
+
+			logger := loggerfactory.NewStructured(logs, "some-name").NewWithContext(ctx)
+			logger.Println("some log message")
+
+			e := entry()
+			Expect(e).To(HaveKeyWithValue("request_id", "some-request-id"))
+			Expect(e).To(HaveKeyWithValue("operation", "update"))
+			Expect(e).To(HaveKeyWithValue("service_name", "some-service"))
+			Expect(e).To(HaveKeyWithValue("instance_id", "some-instance"))
+			Expect(e).To(HaveKeyWithValue("bosh_task_id", BeNumerically("==", 42)))
+		})
+
+		It("leaves out context values that are not set", func() {
+			logger := loggerfactory.NewStructured(logs, "some-name").NewWithContext(context.Background())
+			logger.Println("some log message")
+
+			Expect(entry()).NotTo(HaveKey("request_id"))
+			Expect(entry()).NotTo(HaveKey("bosh_task_id"))
+		})
+
+		It("generates a request ID", func() {
+			logger := loggerfactory.NewStructured(logs, "some-name").NewWithRequestID()
+			logger.Println("some log message")
+
+			Expect(entry()).To(HaveKeyWithValue("request_id", MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)))
+		})
+
+		It("logs at info level whatever the message says", func() {
+			logger := loggerfactory.NewStructured(logs, "some-name").New()
+			logger.Println("error: nothing went wrong")
+
+			Expect(entry()).To(HaveKeyWithValue("level", "info"))
+		})
+
+		It("logs at the level chosen by the caller", func() {
+			logger := loggerfactory.NewStructured(logs, "some-name").New()
+			logger = loggerfactory.WithFields(logger, loggerfactory.Fields{"method": "GET"})
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Println("deploying instance")
+
+			e := entry()
+			Expect(e).To(HaveKeyWithValue("level", "error"))
+			Expect(e).To(HaveKeyWithValue("method", "GET"))
+		})
+
+		It("keeps the level when adding fields", func() {
+			logger := loggerfactory.WithLevel(loggerfactory.NewStructured(logs, "some-name").New(), loggerfactory.WarnLevel)
+			loggerfactory.WithFields(logger, loggerfactory.Fields{"method": "GET"}).Println("plan not found")
+
+			Expect(entry()).To(HaveKeyWithValue("level", "warn"))
+		})
+
+		It("adds fields and durations", func() {
+			logger := loggerfactory.NewStructured(logs, "some-name").New()
+			logger = loggerfactory.WithFields(logger, loggerfactory.Fields{"method": "GET"})
+			loggerfactory.WithDuration(logger, 1500*time.Millisecond).Println("request completed")
+
+			e := entry()
+			Expect(e).To(HaveKeyWithValue("method", "GET"))
+			Expect(e).To(HaveKeyWithValue("duration_ms", BeNumerically("==", 1500)))
+		})
+
+		It("does not add fields to loggers that are not structured", func() {
+			logger := loggerfactory.New(logs, "some-name", 0).New()
+
+			Expect(loggerfactory.IsStructured(logger)).To(BeFalse())
+			Expect(loggerfactory.WithDuration(logger, time.Second)).To(BeIdenticalTo(logger))
+			Expect(loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel)).To(BeIdenticalTo(logger))
+			Expect(loggerfactory.Writer(logger)).To(BeIdenticalTo(logs))
+		})
+
+		It("exposes where a structured logger writes", func() {
+			logger := loggerfactory.NewStructured(logs, "some-name").New()
+
+			Expect(loggerfactory.IsStructured(logger)).To(BeTrue())
+			Expect(loggerfactory.Writer(logger)).To(BeIdenticalTo(logs))
+		})
+	})
+
 })
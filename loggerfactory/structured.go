@@ -0,0 +1,110 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package loggerfactory
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// Fields are added to every line written by a structured logger.
+type Fields map[string]interface{}
+
+// Levels of the lines written by a structured logger. Lines are info unless
+// the logger was given another level with WithLevel.
+const (
+	InfoLevel  = "info"
+	WarnLevel  = "warn"
+	ErrorLevel = "error"
+)
+
+// WithFields returns a logger that adds fields to the lines it writes. Loggers
+// that are not structured are returned as they are, so callers need not know
+// how the logs are formatted.
+func WithFields(logger *log.Logger, fields Fields) *log.Logger {
+	w, ok := logger.Writer().(*structuredWriter)
+	if !ok {
+		return logger
+	}
+
+	merged := Fields{}
+	for key, value := range w.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return log.New(&structuredWriter{out: w.out, source: w.source, fields: merged, level: w.level}, "", 0)
+}
+
+// WithLevel returns a logger that writes its lines at level. Loggers that are
+// not structured are returned as they are.
+func WithLevel(logger *log.Logger, level string) *log.Logger {
+	w, ok := logger.Writer().(*structuredWriter)
+	if !ok {
+		return logger
+	}
+	return log.New(&structuredWriter{out: w.out, source: w.source, fields: w.fields, level: level}, "", 0)
+}
+
+// WithDuration adds how long something took, in milliseconds.
+func WithDuration(logger *log.Logger, duration time.Duration) *log.Logger {
+	return WithFields(logger, Fields{"duration_ms": duration.Milliseconds()})
+}
+
+// Writer returns where a logger writes its lines. Unlike logger.Writer, it
+// sees through structured loggers, for components that format lines of their
+// own.
+func Writer(logger *log.Logger) io.Writer {
+	switch w := logger.Writer().(type) {
+	case *structuredWriter:
+		return w.out
+	}
+	return logger.Writer()
+}
+
+// IsStructured reports whether a logger writes JSON lines.
+func IsStructured(logger *log.Logger) bool {
+	_, ok := logger.Writer().(*structuredWriter)
+	return ok
+}
+
+type structuredWriter struct {
+	out    io.Writer
+	source string
+	fields Fields
+	level  string
+}
+
+// Write is called by log.Logger once per message.
+func (w *structuredWriter) Write(p []byte) (int, error) {
+	message := strings.TrimSuffix(string(p), "\n")
+
+	entry := Fields{}
+	for key, value := range w.fields {
+		entry[key] = value
+	}
+	entry["timestamp"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["source"] = w.source
+	entry["level"] = InfoLevel
+	if w.level != "" {
+		entry["level"] = w.level
+	}
+	entry["message"] = message
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.out.Write(append(line, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
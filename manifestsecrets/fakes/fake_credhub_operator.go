@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -10,11 +11,12 @@ import (
 )
 
 type FakeCredhubOperator struct {
-	BulkDeleteStub        func([]string, *log.Logger) error
+	BulkDeleteStub        func(context.Context, []string, *log.Logger) error
 	bulkDeleteMutex       sync.RWMutex
 	bulkDeleteArgsForCall []struct {
-		arg1 []string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 []string
+		arg3 *log.Logger
 	}
 	bulkDeleteReturns struct {
 		result1 error
@@ -22,11 +24,12 @@ type FakeCredhubOperator struct {
 	bulkDeleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	BulkGetStub        func(map[string]boshdirector.Variable, *log.Logger) (map[string]string, error)
+	BulkGetStub        func(context.Context, map[string]boshdirector.Variable, *log.Logger) (map[string]string, error)
 	bulkGetMutex       sync.RWMutex
 	bulkGetArgsForCall []struct {
-		arg1 map[string]boshdirector.Variable
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 map[string]boshdirector.Variable
+		arg3 *log.Logger
 	}
 	bulkGetReturns struct {
 		result1 map[string]string
@@ -36,11 +39,12 @@ type FakeCredhubOperator struct {
 		result1 map[string]string
 		result2 error
 	}
-	FindNameLikeStub        func(string, *log.Logger) ([]string, error)
+	FindNameLikeStub        func(context.Context, string, *log.Logger) ([]string, error)
 	findNameLikeMutex       sync.RWMutex
 	findNameLikeArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	findNameLikeReturns struct {
 		result1 []string
@@ -50,28 +54,55 @@ type FakeCredhubOperator struct {
 		result1 []string
 		result2 error
 	}
+	RegenerateStub        func(context.Context, []string, *log.Logger) error
+	regenerateMutex       sync.RWMutex
+	regenerateArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 *log.Logger
+	}
+	regenerateReturns struct {
+		result1 error
+	}
+	regenerateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RestoreVersionsStub        func(context.Context, []boshdirector.Variable, *log.Logger) error
+	restoreVersionsMutex       sync.RWMutex
+	restoreVersionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 []boshdirector.Variable
+		arg3 *log.Logger
+	}
+	restoreVersionsReturns struct {
+		result1 error
+	}
+	restoreVersionsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeCredhubOperator) BulkDelete(arg1 []string, arg2 *log.Logger) error {
-	var arg1Copy []string
-	if arg1 != nil {
-		arg1Copy = make([]string, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeCredhubOperator) BulkDelete(arg1 context.Context, arg2 []string, arg3 *log.Logger) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
 	}
 	fake.bulkDeleteMutex.Lock()
 	ret, specificReturn := fake.bulkDeleteReturnsOnCall[len(fake.bulkDeleteArgsForCall)]
 	fake.bulkDeleteArgsForCall = append(fake.bulkDeleteArgsForCall, struct {
-		arg1 []string
-		arg2 *log.Logger
-	}{arg1Copy, arg2})
+		arg1 context.Context
+		arg2 []string
+		arg3 *log.Logger
+	}{arg1, arg2Copy, arg3})
 	stub := fake.BulkDeleteStub
 	fakeReturns := fake.bulkDeleteReturns
-	fake.recordInvocation("BulkDelete", []interface{}{arg1Copy, arg2})
+	fake.recordInvocation("BulkDelete", []interface{}{arg1, arg2Copy, arg3})
 	fake.bulkDeleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -85,17 +116,17 @@ func (fake *FakeCredhubOperator) BulkDeleteCallCount() int {
 	return len(fake.bulkDeleteArgsForCall)
 }
 
-func (fake *FakeCredhubOperator) BulkDeleteCalls(stub func([]string, *log.Logger) error) {
+func (fake *FakeCredhubOperator) BulkDeleteCalls(stub func(context.Context, []string, *log.Logger) error) {
 	fake.bulkDeleteMutex.Lock()
 	defer fake.bulkDeleteMutex.Unlock()
 	fake.BulkDeleteStub = stub
 }
 
-func (fake *FakeCredhubOperator) BulkDeleteArgsForCall(i int) ([]string, *log.Logger) {
+func (fake *FakeCredhubOperator) BulkDeleteArgsForCall(i int) (context.Context, []string, *log.Logger) {
 	fake.bulkDeleteMutex.RLock()
 	defer fake.bulkDeleteMutex.RUnlock()
 	argsForCall := fake.bulkDeleteArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeCredhubOperator) BulkDeleteReturns(result1 error) {
@@ -121,19 +152,20 @@ func (fake *FakeCredhubOperator) BulkDeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeCredhubOperator) BulkGet(arg1 map[string]boshdirector.Variable, arg2 *log.Logger) (map[string]string, error) {
+func (fake *FakeCredhubOperator) BulkGet(arg1 context.Context, arg2 map[string]boshdirector.Variable, arg3 *log.Logger) (map[string]string, error) {
 	fake.bulkGetMutex.Lock()
 	ret, specificReturn := fake.bulkGetReturnsOnCall[len(fake.bulkGetArgsForCall)]
 	fake.bulkGetArgsForCall = append(fake.bulkGetArgsForCall, struct {
-		arg1 map[string]boshdirector.Variable
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 map[string]boshdirector.Variable
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.BulkGetStub
 	fakeReturns := fake.bulkGetReturns
-	fake.recordInvocation("BulkGet", []interface{}{arg1, arg2})
+	fake.recordInvocation("BulkGet", []interface{}{arg1, arg2, arg3})
 	fake.bulkGetMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -147,17 +179,17 @@ func (fake *FakeCredhubOperator) BulkGetCallCount() int {
 	return len(fake.bulkGetArgsForCall)
 }
 
-func (fake *FakeCredhubOperator) BulkGetCalls(stub func(map[string]boshdirector.Variable, *log.Logger) (map[string]string, error)) {
+func (fake *FakeCredhubOperator) BulkGetCalls(stub func(context.Context, map[string]boshdirector.Variable, *log.Logger) (map[string]string, error)) {
 	fake.bulkGetMutex.Lock()
 	defer fake.bulkGetMutex.Unlock()
 	fake.BulkGetStub = stub
 }
 
-func (fake *FakeCredhubOperator) BulkGetArgsForCall(i int) (map[string]boshdirector.Variable, *log.Logger) {
+func (fake *FakeCredhubOperator) BulkGetArgsForCall(i int) (context.Context, map[string]boshdirector.Variable, *log.Logger) {
 	fake.bulkGetMutex.RLock()
 	defer fake.bulkGetMutex.RUnlock()
 	argsForCall := fake.bulkGetArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeCredhubOperator) BulkGetReturns(result1 map[string]string, result2 error) {
@@ -186,19 +218,20 @@ func (fake *FakeCredhubOperator) BulkGetReturnsOnCall(i int, result1 map[string]
 	}{result1, result2}
 }
 
-func (fake *FakeCredhubOperator) FindNameLike(arg1 string, arg2 *log.Logger) ([]string, error) {
+func (fake *FakeCredhubOperator) FindNameLike(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]string, error) {
 	fake.findNameLikeMutex.Lock()
 	ret, specificReturn := fake.findNameLikeReturnsOnCall[len(fake.findNameLikeArgsForCall)]
 	fake.findNameLikeArgsForCall = append(fake.findNameLikeArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.FindNameLikeStub
 	fakeReturns := fake.findNameLikeReturns
-	fake.recordInvocation("FindNameLike", []interface{}{arg1, arg2})
+	fake.recordInvocation("FindNameLike", []interface{}{arg1, arg2, arg3})
 	fake.findNameLikeMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -212,17 +245,17 @@ func (fake *FakeCredhubOperator) FindNameLikeCallCount() int {
 	return len(fake.findNameLikeArgsForCall)
 }
 
-func (fake *FakeCredhubOperator) FindNameLikeCalls(stub func(string, *log.Logger) ([]string, error)) {
+func (fake *FakeCredhubOperator) FindNameLikeCalls(stub func(context.Context, string, *log.Logger) ([]string, error)) {
 	fake.findNameLikeMutex.Lock()
 	defer fake.findNameLikeMutex.Unlock()
 	fake.FindNameLikeStub = stub
 }
 
-func (fake *FakeCredhubOperator) FindNameLikeArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeCredhubOperator) FindNameLikeArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.findNameLikeMutex.RLock()
 	defer fake.findNameLikeMutex.RUnlock()
 	argsForCall := fake.findNameLikeArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeCredhubOperator) FindNameLikeReturns(result1 []string, result2 error) {
@@ -251,6 +284,142 @@ func (fake *FakeCredhubOperator) FindNameLikeReturnsOnCall(i int, result1 []stri
 	}{result1, result2}
 }
 
+func (fake *FakeCredhubOperator) Regenerate(arg1 context.Context, arg2 []string, arg3 *log.Logger) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.regenerateMutex.Lock()
+	ret, specificReturn := fake.regenerateReturnsOnCall[len(fake.regenerateArgsForCall)]
+	fake.regenerateArgsForCall = append(fake.regenerateArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 *log.Logger
+	}{arg1, arg2Copy, arg3})
+	stub := fake.RegenerateStub
+	fakeReturns := fake.regenerateReturns
+	fake.recordInvocation("Regenerate", []interface{}{arg1, arg2Copy, arg3})
+	fake.regenerateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredhubOperator) RegenerateCallCount() int {
+	fake.regenerateMutex.RLock()
+	defer fake.regenerateMutex.RUnlock()
+	return len(fake.regenerateArgsForCall)
+}
+
+func (fake *FakeCredhubOperator) RegenerateCalls(stub func(context.Context, []string, *log.Logger) error) {
+	fake.regenerateMutex.Lock()
+	defer fake.regenerateMutex.Unlock()
+	fake.RegenerateStub = stub
+}
+
+func (fake *FakeCredhubOperator) RegenerateArgsForCall(i int) (context.Context, []string, *log.Logger) {
+	fake.regenerateMutex.RLock()
+	defer fake.regenerateMutex.RUnlock()
+	argsForCall := fake.regenerateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCredhubOperator) RegenerateReturns(result1 error) {
+	fake.regenerateMutex.Lock()
+	defer fake.regenerateMutex.Unlock()
+	fake.RegenerateStub = nil
+	fake.regenerateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubOperator) RegenerateReturnsOnCall(i int, result1 error) {
+	fake.regenerateMutex.Lock()
+	defer fake.regenerateMutex.Unlock()
+	fake.RegenerateStub = nil
+	if fake.regenerateReturnsOnCall == nil {
+		fake.regenerateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.regenerateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubOperator) RestoreVersions(arg1 context.Context, arg2 []boshdirector.Variable, arg3 *log.Logger) error {
+	var arg2Copy []boshdirector.Variable
+	if arg2 != nil {
+		arg2Copy = make([]boshdirector.Variable, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.restoreVersionsMutex.Lock()
+	ret, specificReturn := fake.restoreVersionsReturnsOnCall[len(fake.restoreVersionsArgsForCall)]
+	fake.restoreVersionsArgsForCall = append(fake.restoreVersionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 []boshdirector.Variable
+		arg3 *log.Logger
+	}{arg1, arg2Copy, arg3})
+	stub := fake.RestoreVersionsStub
+	fakeReturns := fake.restoreVersionsReturns
+	fake.recordInvocation("RestoreVersions", []interface{}{arg1, arg2Copy, arg3})
+	fake.restoreVersionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredhubOperator) RestoreVersionsCallCount() int {
+	fake.restoreVersionsMutex.RLock()
+	defer fake.restoreVersionsMutex.RUnlock()
+	return len(fake.restoreVersionsArgsForCall)
+}
+
+func (fake *FakeCredhubOperator) RestoreVersionsCalls(stub func(context.Context, []boshdirector.Variable, *log.Logger) error) {
+	fake.restoreVersionsMutex.Lock()
+	defer fake.restoreVersionsMutex.Unlock()
+	fake.RestoreVersionsStub = stub
+}
+
+func (fake *FakeCredhubOperator) RestoreVersionsArgsForCall(i int) (context.Context, []boshdirector.Variable, *log.Logger) {
+	fake.restoreVersionsMutex.RLock()
+	defer fake.restoreVersionsMutex.RUnlock()
+	argsForCall := fake.restoreVersionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCredhubOperator) RestoreVersionsReturns(result1 error) {
+	fake.restoreVersionsMutex.Lock()
+	defer fake.restoreVersionsMutex.Unlock()
+	fake.RestoreVersionsStub = nil
+	fake.restoreVersionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCredhubOperator) RestoreVersionsReturnsOnCall(i int, result1 error) {
+	fake.restoreVersionsMutex.Lock()
+	defer fake.restoreVersionsMutex.Unlock()
+	fake.RestoreVersionsStub = nil
+	if fake.restoreVersionsReturnsOnCall == nil {
+		fake.restoreVersionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restoreVersionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeCredhubOperator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -260,6 +429,10 @@ func (fake *FakeCredhubOperator) Invocations() map[string][][]interface{} {
 	defer fake.bulkGetMutex.RUnlock()
 	fake.findNameLikeMutex.RLock()
 	defer fake.findNameLikeMutex.RUnlock()
+	fake.regenerateMutex.RLock()
+	defer fake.regenerateMutex.RUnlock()
+	fake.restoreVersionsMutex.RLock()
+	defer fake.restoreVersionsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -1,6 +1,7 @@
 package manifestsecrets
 
 import (
+	"context"
 	"log"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
@@ -10,9 +11,11 @@ import (
 //counterfeiter:generate -o fakes/fake_credhub_operator.go . CredhubOperator
 
 type CredhubOperator interface {
-	BulkGet(map[string]boshdirector.Variable, *log.Logger) (map[string]string, error)
-	FindNameLike(name string, logger *log.Logger) ([]string, error)
-	BulkDelete(paths []string, logger *log.Logger) error
+	BulkGet(context.Context, map[string]boshdirector.Variable, *log.Logger) (map[string]string, error)
+	FindNameLike(ctx context.Context, name string, logger *log.Logger) ([]string, error)
+	BulkDelete(ctx context.Context, paths []string, logger *log.Logger) error
+	RestoreVersions(ctx context.Context, variables []boshdirector.Variable, logger *log.Logger) error
+	Regenerate(ctx context.Context, paths []string, logger *log.Logger) error
 }
 
 //counterfeiter:generate -o fakes/fake_matcher.go . Matcher
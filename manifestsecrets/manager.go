@@ -1,6 +1,8 @@
 package manifestsecrets
 
 import (
+	"context"
+	"errors"
 	"log"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
@@ -9,14 +11,24 @@ import (
 
 type NoopSecretManager struct{}
 
-func (r *NoopSecretManager) ResolveManifestSecrets(manifest []byte, deploymentVariables []boshdirector.Variable, logger *log.Logger) (map[string]string, error) {
+func (r *NoopSecretManager) ResolveManifestSecrets(ctx context.Context, manifest []byte, deploymentVariables []boshdirector.Variable, logger *log.Logger) (map[string]string, error) {
 	return nil, nil
 }
 
-func (r *NoopSecretManager) DeleteSecretsForInstance(instanceID string, logger *log.Logger) error {
+func (r *NoopSecretManager) DeleteSecretsForInstance(ctx context.Context, instanceID string, logger *log.Logger) error {
 	return nil
 }
 
+func (r *NoopSecretManager) RestoreSecrets(ctx context.Context, variables []boshdirector.Variable, logger *log.Logger) error {
+	return nil
+}
+
+// RegenerateSecrets fails, as without secure manifests the broker has no
+// access to the secrets of the deployments.
+func (r *NoopSecretManager) RegenerateSecrets(ctx context.Context, paths []string, logger *log.Logger) error {
+	return errors.New("rotating secrets requires enable_secure_manifests")
+}
+
 type BoshCredHubSecretManager struct {
 	matcher  Matcher
 	operator CredhubOperator
@@ -33,13 +45,13 @@ func BuildManager(enableSecureManifests bool, matcher Matcher, secretsFetcher Cr
 	}
 }
 
-func (r *BoshCredHubSecretManager) ResolveManifestSecrets(manifest []byte, deploymentVariables []boshdirector.Variable, logger *log.Logger) (map[string]string, error) {
+func (r *BoshCredHubSecretManager) ResolveManifestSecrets(ctx context.Context, manifest []byte, deploymentVariables []boshdirector.Variable, logger *log.Logger) (map[string]string, error) {
 	matches, err := r.matcher.Match(manifest, deploymentVariables)
 	if err != nil {
 		return nil, err
 	}
 
-	secrets, err := r.operator.BulkGet(matches, logger)
+	secrets, err := r.operator.BulkGet(ctx, matches, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -47,11 +59,23 @@ func (r *BoshCredHubSecretManager) ResolveManifestSecrets(manifest []byte, deplo
 	return secrets, nil
 }
 
-func (r *BoshCredHubSecretManager) DeleteSecretsForInstance(instanceID string, logger *log.Logger) error {
-	paths, err := r.operator.FindNameLike(instanceID, logger)
+func (r *BoshCredHubSecretManager) DeleteSecretsForInstance(ctx context.Context, instanceID string, logger *log.Logger) error {
+	paths, err := r.operator.FindNameLike(ctx, instanceID, logger)
 	if err != nil {
 		return err
 	}
 
-	return r.operator.BulkDelete(paths, logger)
+	return r.operator.BulkDelete(ctx, paths, logger)
+}
+
+// RestoreSecrets makes the given versions of the secrets their current values,
+// so that a manifest deployed with them resolves them as it did before.
+func (r *BoshCredHubSecretManager) RestoreSecrets(ctx context.Context, variables []boshdirector.Variable, logger *log.Logger) error {
+	return r.operator.RestoreVersions(ctx, variables, logger)
+}
+
+// RegenerateSecrets has CredHub generate new values for the given secrets,
+// which must have been generated by it, as the secrets BOSH generates are.
+func (r *BoshCredHubSecretManager) RegenerateSecrets(ctx context.Context, paths []string, logger *log.Logger) error {
+	return r.operator.Regenerate(ctx, paths, logger)
 }
@@ -1,6 +1,7 @@
 package manifestsecrets_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -49,13 +50,13 @@ var _ = Describe("ManifestSecrets", func() {
 				fakeMatcher.MatchReturns(secrets, nil)
 				fakeCredhubOperator.BulkGetReturns(secretsValues, nil)
 
-				secretsMap, err := manager.ResolveManifestSecrets(manifestWithSecrets, []boshdirector.Variable{{Path: "/some/var", ID: "1234"}}, nil)
+				secretsMap, err := manager.ResolveManifestSecrets(context.Background(), manifestWithSecrets, []boshdirector.Variable{{Path: "/some/var", ID: "1234"}}, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(fakeMatcher.MatchCallCount()).To(Equal(1))
 
 				Expect(fakeCredhubOperator.BulkGetCallCount()).To(Equal(1))
-				secretsToFetch, _ := fakeCredhubOperator.BulkGetArgsForCall(0)
+				_, secretsToFetch, _ := fakeCredhubOperator.BulkGetArgsForCall(0)
 				Expect(secretsToFetch).To(Equal(secrets))
 
 				Expect(secretsMap).To(Equal(secretsValues))
@@ -63,13 +64,13 @@ var _ = Describe("ManifestSecrets", func() {
 
 			It("returns an error when the matcher errors", func() {
 				fakeMatcher.MatchReturns(nil, errors.New("matcher error"))
-				_, err := manager.ResolveManifestSecrets([]byte("name: foo"), nil, nil)
+				_, err := manager.ResolveManifestSecrets(context.Background(), []byte("name: foo"), nil, nil)
 				Expect(err).To(MatchError(ContainSubstring("matcher error")))
 			})
 
 			It("returns an error when secrets fetcher errors", func() {
 				fakeCredhubOperator.BulkGetReturns(map[string]string{}, errors.New("something failed"))
-				_, err := manager.ResolveManifestSecrets([]byte("name: foo"), nil, nil)
+				_, err := manager.ResolveManifestSecrets(context.Background(), []byte("name: foo"), nil, nil)
 				Expect(err).To(MatchError(ContainSubstring("something failed")))
 			})
 		})
@@ -85,29 +86,66 @@ var _ = Describe("ManifestSecrets", func() {
 				fakeCredhubOperator.FindNameLikeReturns(paths, nil)
 				fakeCredhubOperator.BulkDeleteReturns(nil)
 
-				err := manager.DeleteSecretsForInstance(expectedInstanceID, nil)
+				err := manager.DeleteSecretsForInstance(context.Background(), expectedInstanceID, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(fakeCredhubOperator.FindNameLikeCallCount()).To(Equal(1), "expected to call FindNameLike once")
-				actualInstanceID, _ := fakeCredhubOperator.FindNameLikeArgsForCall(0)
+				_, actualInstanceID, _ := fakeCredhubOperator.FindNameLikeArgsForCall(0)
 				Expect(actualInstanceID).To(Equal(expectedInstanceID))
 
 				Expect(fakeCredhubOperator.BulkDeleteCallCount()).To(Equal(1), "expected to call BulkDelete once")
-				actualPaths, _ := fakeCredhubOperator.BulkDeleteArgsForCall(0)
+				_, actualPaths, _ := fakeCredhubOperator.BulkDeleteArgsForCall(0)
 				Expect(actualPaths).To(Equal(paths))
 			})
 
 			It("returns an error when finding credentials fails", func() {
 				fakeCredhubOperator.FindNameLikeReturns(nil, errors.New("FindNameLike failed miserably"))
-				err := manager.DeleteSecretsForInstance("foo", nil)
+				err := manager.DeleteSecretsForInstance(context.Background(), "foo", nil)
 				Expect(err).To(MatchError("FindNameLike failed miserably"))
 			})
 
 			It("returns an error when deleting credentials fails", func() {
 				fakeCredhubOperator.BulkDeleteReturns(errors.New("BulkDelete failed miserably this time"))
-				err := manager.DeleteSecretsForInstance("foo", nil)
+				err := manager.DeleteSecretsForInstance(context.Background(), "foo", nil)
 				Expect(err).To(MatchError("BulkDelete failed miserably this time"))
 			})
 		})
+
+		Describe("RestoreSecrets", func() {
+			It("restores the versions through credhub", func() {
+				variables := []boshdirector.Variable{{Path: "/odb/some/var", ID: "1234"}}
+
+				err := manager.RestoreSecrets(context.Background(), variables, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeCredhubOperator.RestoreVersionsCallCount()).To(Equal(1))
+				_, actualVariables, _ := fakeCredhubOperator.RestoreVersionsArgsForCall(0)
+				Expect(actualVariables).To(Equal(variables))
+			})
+
+			It("returns an error when restoring fails", func() {
+				fakeCredhubOperator.RestoreVersionsReturns(errors.New("RestoreVersions failed"))
+				err := manager.RestoreSecrets(context.Background(), nil, nil)
+				Expect(err).To(MatchError("RestoreVersions failed"))
+			})
+		})
+
+		Describe("RegenerateSecrets", func() {
+			It("regenerates the secrets through credhub", func() {
+				err := manager.RegenerateSecrets(context.Background(), []string{"/director/some/var"}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeCredhubOperator.RegenerateCallCount()).To(Equal(1))
+				_, paths, _ := fakeCredhubOperator.RegenerateArgsForCall(0)
+				Expect(paths).To(Equal([]string{"/director/some/var"}))
+			})
+		})
+	})
+
+	Context("noop manager", func() {
+		It("cannot regenerate secrets", func() {
+			err := manifestsecrets.BuildManager(false, nil, nil).RegenerateSecrets(context.Background(), nil, nil)
+			Expect(err).To(MatchError("rotating secrets requires enable_secure_manifests"))
+		})
 	})
 })
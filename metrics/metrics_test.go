@@ -0,0 +1,77 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package metrics_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	var (
+		registry *metrics.Registry
+		output   *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		registry = metrics.NewRegistry()
+		output = new(bytes.Buffer)
+	})
+
+	It("renders counters with sorted and escaped labels", func() {
+		registry.ObserveBoshTask("redis", "small \"plan\"", "create", "succeeded")
+		registry.ObserveBoshTask("redis", "small \"plan\"", "create", "succeeded")
+
+		Expect(metrics.WriteText(output, registry.Families())).To(Succeed())
+		Expect(output.String()).To(Equal(`# HELP on_demand_broker_bosh_tasks_total BOSH tasks that reached a final state, as reported by last operation requests.
+# TYPE on_demand_broker_bosh_tasks_total counter
+on_demand_broker_bosh_tasks_total{offering="redis",operation="create",plan="small \"plan\"",state="succeeded"} 2
+`))
+	})
+
+	It("renders duration histograms with cumulative buckets", func() {
+		registry.ObserveRequest("provision", "success", 200*time.Millisecond)
+		registry.ObserveRequest("provision", "success", 3*time.Second)
+
+		Expect(metrics.WriteText(output, registry.Families())).To(Succeed())
+		Expect(output.String()).To(SatisfyAll(
+			ContainSubstring("# TYPE on_demand_broker_request_duration_seconds histogram\n"),
+			ContainSubstring(`on_demand_broker_request_duration_seconds_bucket{le="0.1",operation="provision",outcome="success"} 0`+"\n"),
+			ContainSubstring(`on_demand_broker_request_duration_seconds_bucket{le="0.25",operation="provision",outcome="success"} 1`+"\n"),
+			ContainSubstring(`on_demand_broker_request_duration_seconds_bucket{le="5",operation="provision",outcome="success"} 2`+"\n"),
+			ContainSubstring(`on_demand_broker_request_duration_seconds_bucket{le="+Inf",operation="provision",outcome="success"} 2`+"\n"),
+			ContainSubstring(`on_demand_broker_request_duration_seconds_sum{operation="provision",outcome="success"} 3.2`+"\n"),
+			ContainSubstring(`on_demand_broker_request_duration_seconds_count{operation="provision",outcome="success"} 2`+"\n"),
+		))
+	})
+
+	It("leaves out the families that are not valid, and reports each of them", func() {
+		err := metrics.WriteText(output, []metrics.Family{
+			{Name: "bad name", Type: metrics.Gauge, Samples: []metrics.Sample{{Name: "bad name", Value: 1}}},
+			{Name: "good", Help: "Fine.", Type: metrics.Gauge, Samples: []metrics.Sample{{Name: "good", Value: 1}}},
+			{Name: "bad_label", Type: metrics.Gauge, Samples: []metrics.Sample{{Name: "bad_label", Labels: metrics.Labels{"a-b": "c"}, Value: 1}}},
+			{Name: "duplicate", Type: metrics.Counter, Samples: []metrics.Sample{{Name: "duplicate", Value: 1}, {Name: "duplicate", Value: 2}}},
+		})
+
+		Expect(err).To(MatchError(SatisfyAll(
+			ContainSubstring("metric family bad name: invalid metric name"),
+			ContainSubstring(`metric family bad_label: invalid label name "a-b"`),
+			ContainSubstring("metric family duplicate: duplicate sample duplicate"),
+		)))
+		Expect(output.String()).To(Equal("# HELP good Fine.\n# TYPE good gauge\ngood 1\n"))
+	})
+
+	It("does not render families without samples", func() {
+		Expect(metrics.WriteText(output, []metrics.Family{{Name: "empty", Type: metrics.Gauge}})).To(Succeed())
+		Expect(output.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,177 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	RequestDurationName = "on_demand_broker_request_duration_seconds"
+	AdapterDurationName = "on_demand_broker_adapter_call_duration_seconds"
+	AdapterCallsName    = "on_demand_broker_adapter_calls_total"
+	BoshTasksName       = "on_demand_broker_bosh_tasks_total"
+
+	// NoExitCode is the exit code label of adapter invocations that could not
+	// be run.
+	NoExitCode = "none"
+)
+
+const (
+	requestDurationHelp = "Duration of Open Service Broker API requests."
+	adapterDurationHelp = "Duration of service adapter invocations."
+	adapterCallsHelp    = "Service adapter invocations by exit code."
+	boshTasksHelp       = "BOSH tasks that reached a final state, as reported by last operation requests."
+)
+
+// DurationBuckets are the upper bounds, in seconds, of the duration histograms.
+// Adapter invocations and BOSH backed requests can take minutes, so the
+// buckets reach further than the usual HTTP defaults.
+var DurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Registry accumulates the broker's runtime metrics in memory. It is safe for
+// concurrent use.
+type Registry struct {
+	lock     sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	help   string
+	kind   string
+	series map[string]*series
+}
+
+type series struct {
+	labels Labels
+	value  float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]*family{}}
+}
+
+// ObserveRequest records the duration of an OSBAPI operation. The outcome is
+// "success" or "error".
+func (r *Registry) ObserveRequest(operation, outcome string, duration time.Duration) {
+	r.observe(RequestDurationName, requestDurationHelp, Labels{"operation": operation, "outcome": outcome}, duration)
+}
+
+// ObserveAdapterCall records the duration and exit code of a service adapter
+// invocation. A nil exit code means the adapter could not be run.
+func (r *Registry) ObserveAdapterCall(command string, exitCode *int, duration time.Duration) {
+	code := NoExitCode
+	if exitCode != nil {
+		code = strconv.Itoa(*exitCode)
+	}
+	r.observe(AdapterDurationName, adapterDurationHelp, Labels{"command": command}, duration)
+	r.inc(AdapterCallsName, adapterCallsHelp, Labels{"command": command, "exit_code": code})
+}
+
+// ObserveBoshTask records the final state of the BOSH task of an operation.
+func (r *Registry) ObserveBoshTask(offering, plan, operation, state string) {
+	r.inc(BoshTasksName, boshTasksHelp, Labels{"offering": offering, "plan": plan, "operation": operation, "state": state})
+}
+
+// Families returns a snapshot of the recorded metrics, sorted by name.
+func (r *Registry) Families() []Family {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var names []string
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var families []Family
+	for _, name := range names {
+		f := r.families[name]
+		families = append(families, Family{Name: name, Help: f.help, Type: f.kind, Samples: f.samples(name)})
+	}
+	return families
+}
+
+func (r *Registry) inc(name, help string, labels Labels) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.seriesFor(name, help, Counter, labels).value++
+}
+
+func (r *Registry) observe(name, help string, labels Labels, duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := r.seriesFor(name, help, Histogram, labels)
+	seconds := duration.Seconds()
+	for i, bound := range DurationBuckets {
+		if seconds <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += seconds
+	s.count++
+}
+
+func (r *Registry) seriesFor(name, help, kind string, labels Labels) *series {
+	f, found := r.families[name]
+	if !found {
+		f = &family{help: help, kind: kind, series: map[string]*series{}}
+		r.families[name] = f
+	}
+
+	key := formatLabels(labels)
+	s, found := f.series[key]
+	if !found {
+		s = &series{labels: labels, counts: make([]uint64, len(DurationBuckets))}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) samples(name string) []Sample {
+	var keys []string
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var samples []Sample
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != Histogram {
+			samples = append(samples, Sample{Name: name, Labels: s.labels, Value: s.value})
+			continue
+		}
+
+		for i, bound := range DurationBuckets {
+			samples = append(samples, Sample{Name: name + "_bucket", Labels: withLabel(s.labels, "le", formatValue(bound)), Value: float64(s.counts[i])})
+		}
+		samples = append(samples,
+			Sample{Name: name + "_bucket", Labels: withLabel(s.labels, "le", formatValue(math.Inf(1))), Value: float64(s.count)},
+			Sample{Name: name + "_sum", Labels: s.labels, Value: s.sum},
+			Sample{Name: name + "_count", Labels: s.labels, Value: float64(s.count)},
+		)
+	}
+	return samples
+}
+
+func withLabel(labels Labels, name, value string) Labels {
+	result := Labels{name: value}
+	for k, v := range labels {
+		result[k] = v
+	}
+	return result
+}
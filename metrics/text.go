@@ -0,0 +1,150 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TextContentType is the content type of the Prometheus text exposition format.
+const TextContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	Gauge     = "gauge"
+	Counter   = "counter"
+	Histogram = "histogram"
+)
+
+type Labels map[string]string
+
+type Sample struct {
+	Name   string
+	Labels Labels
+	Value  float64
+}
+
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// WriteText writes the families in the Prometheus text exposition format. A
+// family that is not valid is left out, so that it cannot make the whole
+// exposition unparseable, and reported in the error returned, which names each
+// family left out.
+func WriteText(w io.Writer, families []Family) error {
+	var errs []error
+	buf := bufio.NewWriter(w)
+	for _, family := range families {
+		if len(family.Samples) == 0 {
+			continue
+		}
+		if err := validate(family); err != nil {
+			errs = append(errs, fmt.Errorf("metric family %s: %w", family.Name, err))
+			continue
+		}
+		fmt.Fprintf(buf, "# HELP %s %s\n", family.Name, helpEscaper.Replace(family.Help))
+		fmt.Fprintf(buf, "# TYPE %s %s\n", family.Name, family.Type)
+		for _, sample := range family.Samples {
+			fmt.Fprintf(buf, "%s%s %s\n", sample.Name, formatLabels(sample.Labels), formatValue(sample.Value))
+		}
+	}
+	return errors.Join(append(errs, buf.Flush())...)
+}
+
+var (
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+func validate(family Family) error {
+	if !metricNamePattern.MatchString(family.Name) {
+		return errors.New("invalid metric name")
+	}
+
+	suffixes := []string{""}
+	switch family.Type {
+	case Gauge, Counter:
+	case Histogram:
+		suffixes = []string{"_bucket", "_sum", "_count"}
+	default:
+		return fmt.Errorf("unknown metric type %q", family.Type)
+	}
+
+	seen := map[string]bool{}
+	for _, sample := range family.Samples {
+		if !belongsTo(sample.Name, family.Name, suffixes) {
+			return fmt.Errorf("sample %s does not belong to the family", sample.Name)
+		}
+		for name := range sample.Labels {
+			if !labelNamePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+				return fmt.Errorf("invalid label name %q", name)
+			}
+		}
+		series := sample.Name + formatLabels(sample.Labels)
+		if seen[series] {
+			return fmt.Errorf("duplicate sample %s", series)
+		}
+		seen[series] = true
+	}
+	return nil
+}
+
+func belongsTo(sampleName, familyName string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if sampleName == familyName+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var names []string
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, labelValueEscaper.Replace(labels[name])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
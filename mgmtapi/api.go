@@ -9,10 +9,15 @@ package mgmtapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
@@ -24,31 +29,55 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
 
 type api struct {
 	manageableBroker ManageableBroker
-	serviceOffering  config.ServiceOffering
+	offerings        *config.OfferingStore
+	reloader         ConfigReloader
+	registry         *metrics.Registry
 	loggerFactory    *loggerfactory.LoggerFactory
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o fake_manageable_broker/fake_manageable_broker.go . ManageableBroker
 type ManageableBroker interface {
-	Instances(filter map[string]string, logger *log.Logger) ([]service.Instance, error)
-	OrphanDeployments(logger *log.Logger) ([]string, error)
+	Instances(ctx context.Context, filter map[string]string, logger *log.Logger) ([]service.Instance, error)
+	OrphanDeployments(ctx context.Context, logger *log.Logger) ([]string, error)
+	CleanupOrphanDeployments(ctx context.Context, orphans []string, options broker.OrphanCleanupOptions, logger *log.Logger) (broker.OrphanCleanupReport, error)
 	Upgrade(ctx context.Context, instanceID string, updateDetails domain.UpdateDetails, logger *log.Logger) (broker.OperationData, string, map[string]any, error)
 	Recreate(ctx context.Context, instanceID string, updateDetails domain.UpdateDetails, logger *log.Logger) (broker.OperationData, error)
-	CountInstancesOfPlans(logger *log.Logger) (map[cf.ServicePlan]int, error)
+	Rollback(ctx context.Context, instanceID string, updateDetails domain.UpdateDetails, logger *log.Logger) (broker.OperationData, error)
+	RotateSecrets(ctx context.Context, instanceID string, updateDetails domain.UpdateDetails, secretPaths []string, logger *log.Logger) (broker.OperationData, error)
+	CountInstancesOfPlans(ctx context.Context, logger *log.Logger) (map[cf.ServicePlan]int, error)
+	CountInstancesOfPlansByOrgAndSpace(ctx context.Context, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)
+	ContentionStats() broker.ContentionStats
+	OperationHistory(ctx context.Context, instanceID string, logger *log.Logger) ([]broker.OperationHistoryEntry, error)
+	IteratorCheckpoint(ctx context.Context, name string, logger *log.Logger) ([]byte, bool, error)
+	SaveIteratorCheckpoint(ctx context.Context, name string, checkpoint []byte, logger *log.Logger) error
+	ClearIteratorCheckpoint(ctx context.Context, name string, logger *log.Logger) error
+	PreviewUpgrade(ctx context.Context, instanceID string, updateDetails domain.UpdateDetails, logger *log.Logger) (broker.ManifestDiff, error)
+	PreviewUpdate(ctx context.Context, instanceID string, updateDetails domain.UpdateDetails, logger *log.Logger) (broker.ManifestDiff, error)
+}
+
+// ConfigReloader reloads the configuration of the running broker. It fails
+// when the new configuration is invalid, and the current one stays in use.
+//
+//counterfeiter:generate -o fake_config_reloader/fake_config_reloader.go . ConfigReloader
+type ConfigReloader interface {
+	Reload() error
 }
 
 type Deployment struct {
 	Name string `json:"deployment_name"`
 }
 
-func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, serviceOffering config.ServiceOffering, loggerFactory *loggerfactory.LoggerFactory) {
-	a := &api{manageableBroker: manageableBroker, serviceOffering: serviceOffering, loggerFactory: loggerFactory}
+// AttachRoutes adds the management API to r. The reload endpoint is only added
+// when reloader is not nil.
+func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, offerings *config.OfferingStore, reloader ConfigReloader, registry *metrics.Registry, loggerFactory *loggerfactory.LoggerFactory) {
+	a := &api{manageableBroker: manageableBroker, offerings: offerings, reloader: reloader, registry: registry, loggerFactory: loggerFactory}
 	r.HandleFunc("/mgmt/service_instances", a.listAllInstances).Methods("GET")
 
 	r.HandleFunc("/mgmt/service_instances/{instance_id}", a.recreateInstance).
@@ -59,11 +88,55 @@ func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, serviceOffer
 		Methods("PATCH").
 		Queries("operation_type", "upgrade")
 
+	r.HandleFunc("/mgmt/service_instances/{instance_id}", a.rollbackInstance).
+		Methods("PATCH").
+		Queries("operation_type", "rollback")
+
+	r.HandleFunc("/mgmt/service_instances/{instance_id}", a.rotateSecrets).
+		Methods("PATCH").
+		Queries("operation_type", "rotate-secrets")
+
 	r.HandleFunc("/mgmt/service_instances/{instance_id}", badRequestHandler()).
 		Methods("PATCH")
 
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/operations", a.listOperations).Methods("GET")
+
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/preview", a.previewInstance(broker.OperationTypeUpgrade)).
+		Methods("POST").
+		Queries("operation_type", "upgrade")
+
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/preview", a.previewInstance(broker.OperationTypeUpdate)).
+		Methods("POST").
+		Queries("operation_type", "update")
+
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/preview", badRequestHandler()).
+		Methods("POST")
+
+	r.HandleFunc("/mgmt/iterator_checkpoints/{name}", a.getIteratorCheckpoint).Methods("GET")
+	r.HandleFunc("/mgmt/iterator_checkpoints/{name}", a.saveIteratorCheckpoint).Methods("PUT")
+	r.HandleFunc("/mgmt/iterator_checkpoints/{name}", a.clearIteratorCheckpoint).Methods("DELETE")
+
 	r.HandleFunc("/mgmt/metrics", a.metrics).Methods("GET")
 	r.HandleFunc("/mgmt/orphan_deployments", a.listOrphanDeployments).Methods("GET")
+	r.HandleFunc("/mgmt/orphan_deployments/cleanup", a.cleanupOrphanDeployments).Methods("POST")
+
+	if reloader != nil {
+		r.HandleFunc("/mgmt/reload", a.reloadConfig).Methods("POST")
+	}
+}
+
+func (a *api) reloadConfig(w http.ResponseWriter, r *http.Request) {
+	logger := a.loggerFactory.NewWithRequestID()
+
+	logger.Println("reloading config")
+	if err := a.reloader.Reload(); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error reloading config, the current config stays in use: %s", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+		return
+	}
+	logger.Println("config reloaded")
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func badRequestHandler() func(w http.ResponseWriter, r *http.Request) {
@@ -75,9 +148,9 @@ func badRequestHandler() func(w http.ResponseWriter, r *http.Request) {
 func (a *api) listOrphanDeployments(w http.ResponseWriter, r *http.Request) {
 	logger := a.loggerFactory.NewWithRequestID()
 
-	orphanNames, err := a.manageableBroker.OrphanDeployments(logger)
+	orphanNames, err := a.manageableBroker.OrphanDeployments(r.Context(), logger)
 	if err != nil {
-		logger.Printf("error occurred querying orphan deployments: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred querying orphan deployments: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -90,15 +163,71 @@ func (a *api) listOrphanDeployments(w http.ResponseWriter, r *http.Request) {
 	a.writeJson(w, orphanDeployments, logger)
 }
 
+// OrphanCleanupRequest is the body of an orphan cleanup request. Orphans are
+// deleted once GracePeriodSecs has passed since they were first detected,
+// unless they are in AllowList.
+type OrphanCleanupRequest struct {
+	GracePeriodSecs int      `json:"grace_period_in_seconds"`
+	AllowList       []string `json:"allow_list,omitempty"`
+	DryRun          bool     `json:"dry_run"`
+}
+
+func (a *api) cleanupOrphanDeployments(w http.ResponseWriter, r *http.Request) {
+	requestID := uuid.New()
+	ctx := brokercontext.New(r.Context(), "", requestID, a.serviceOfferingNames(), "")
+	logger := a.loggerFactory.NewWithContext(ctx)
+
+	var cleanupRequest OrphanCleanupRequest
+	if err := json.NewDecoder(r.Body).Decode(&cleanupRequest); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred parsing requests body: %s", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
+		return
+	}
+	if cleanupRequest.GracePeriodSecs <= 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: "grace_period_in_seconds must be greater than zero"}, logger)
+		return
+	}
+
+	orphans, err := a.manageableBroker.OrphanDeployments(r.Context(), logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred querying orphan deployments: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	options := broker.OrphanCleanupOptions{
+		GracePeriod: time.Duration(cleanupRequest.GracePeriodSecs) * time.Second,
+		AllowList:   cleanupRequest.AllowList,
+		DryRun:      cleanupRequest.DryRun,
+	}
+	report, err := a.manageableBroker.CleanupOrphanDeployments(ctx, orphans, options, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred cleaning up orphan deployments: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+		return
+	}
+
+	a.writeJson(w, report, logger)
+}
+
 func (a *api) listAllInstances(w http.ResponseWriter, r *http.Request) {
 	logger := a.loggerFactory.NewWithRequestID()
 	var instances []service.Instance
 	var err error
 
 	filter := getFilterValues(r)
-	instances, err = a.manageableBroker.Instances(filter, logger)
-	if err != nil {
-		logger.Printf("error occurred querying instances: %s", err)
+	instances, err = a.manageableBroker.Instances(r.Context(), filter, logger)
+	switch err.(type) {
+	case nil:
+	case service.InvalidFilterError:
+		w.WriteHeader(http.StatusBadRequest)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+		return
+	case error:
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred querying instances: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -106,6 +235,87 @@ func (a *api) listAllInstances(w http.ResponseWriter, r *http.Request) {
 	a.writeJson(w, instances, logger)
 }
 
+func (a *api) listOperations(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	operations, err := a.manageableBroker.OperationHistory(r.Context(), instanceID, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred querying operations of instance %s: %s", instanceID, err)
+		if _, ok := err.(broker.DeploymentNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.writeJson(w, operations, logger)
+}
+
+var checkpointNamePattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+func (a *api) getIteratorCheckpoint(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	if !checkpointNamePattern.MatchString(name) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	checkpoint, found, err := a.manageableBroker.IteratorCheckpoint(r.Context(), name, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred reading checkpoint %s: %s", name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(checkpoint); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred writing checkpoint %s: %s", name, err)
+	}
+}
+
+func (a *api) saveIteratorCheckpoint(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	checkpoint, err := io.ReadAll(r.Body)
+	if err != nil || !checkpointNamePattern.MatchString(name) || !json.Valid(checkpoint) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := a.manageableBroker.SaveIteratorCheckpoint(r.Context(), name, checkpoint, logger); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred saving checkpoint %s: %s", name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) clearIteratorCheckpoint(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	if !checkpointNamePattern.MatchString(name) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := a.manageableBroker.ClearIteratorCheckpoint(r.Context(), name, logger); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred clearing checkpoint %s: %s", name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func getFilterValues(r *http.Request) map[string]string {
 	values := r.URL.Query()
 	filter := map[string]string{}
@@ -120,18 +330,21 @@ func (a *api) recreateInstance(w http.ResponseWriter, r *http.Request) {
 	instanceID := vars["instance_id"]
 
 	requestID := uuid.New()
-	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeRecreate), requestID, a.serviceOffering.Name, instanceID)
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeRecreate), requestID, "", instanceID)
 
 	logger := a.loggerFactory.NewWithContext(ctx)
 
 	var details domain.UpdateDetails
 	if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
-		logger.Printf("error occurred parsing requests body: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred parsing requests body: %s", err)
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		a.writeJson(w, apiresponses.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
 		return
 	}
 
+	ctx = brokercontext.WithServiceName(ctx, a.serviceOfferingName(details.PlanID))
+	logger = a.loggerFactory.NewWithContext(ctx)
+
 	operationData, err := a.manageableBroker.Recreate(ctx, instanceID, details, logger)
 
 	switch err.(type) {
@@ -144,8 +357,106 @@ func (a *api) recreateInstance(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusGone)
 	case broker.OperationInProgressError:
 		w.WriteHeader(http.StatusConflict)
+	case *apiresponses.FailureResponse:
+		a.writeFailureResponse(w, err.(*apiresponses.FailureResponse), logger)
 	case error:
-		logger.Printf("error occurred recreating instance %s: %s", instanceID, err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred recreating instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+	}
+}
+
+func (a *api) rollbackInstance(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["instance_id"]
+
+	requestID := uuid.New()
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeRollback), requestID, "", instanceID)
+
+	logger := a.loggerFactory.NewWithContext(ctx)
+
+	var details domain.UpdateDetails
+	if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred parsing requests body: %s", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
+		return
+	}
+
+	ctx = brokercontext.WithServiceName(ctx, a.serviceOfferingName(details.PlanID))
+	logger = a.loggerFactory.NewWithContext(ctx)
+
+	operationData, err := a.manageableBroker.Rollback(ctx, instanceID, details, logger)
+
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusAccepted)
+		a.writeJson(w, operationData, logger)
+	case cf.ResourceNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+	case broker.DeploymentNotFoundError:
+		w.WriteHeader(http.StatusGone)
+	case broker.OperationInProgressError:
+		w.WriteHeader(http.StatusConflict)
+	case broker.NoRollbackSnapshotError:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+	case *apiresponses.FailureResponse:
+		a.writeFailureResponse(w, err.(*apiresponses.FailureResponse), logger)
+	case error:
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred rolling back instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+	}
+}
+
+// RotateSecretsRequest is the body of a rotate-secrets request. SecretPaths
+// selects the secrets to rotate; all the secrets of the instance are rotated
+// when it is empty.
+type RotateSecretsRequest struct {
+	domain.UpdateDetails
+	SecretPaths []string `json:"secret_paths,omitempty"`
+}
+
+func (a *api) rotateSecrets(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["instance_id"]
+
+	requestID := uuid.New()
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeRotateSecrets), requestID, "", instanceID)
+
+	logger := a.loggerFactory.NewWithContext(ctx)
+
+	var details RotateSecretsRequest
+	if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred parsing requests body: %s", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
+		return
+	}
+
+	ctx = brokercontext.WithServiceName(ctx, a.serviceOfferingName(details.PlanID))
+	logger = a.loggerFactory.NewWithContext(ctx)
+
+	operationData, err := a.manageableBroker.RotateSecrets(ctx, instanceID, details.UpdateDetails, details.SecretPaths, logger)
+
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusAccepted)
+		a.writeJson(w, operationData, logger)
+	case cf.ResourceNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+	case broker.DeploymentNotFoundError:
+		w.WriteHeader(http.StatusGone)
+	case broker.OperationInProgressError:
+		w.WriteHeader(http.StatusConflict)
+	case broker.SecretsNotFoundError:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+	case *apiresponses.FailureResponse:
+		a.writeFailureResponse(w, err.(*apiresponses.FailureResponse), logger)
+	case error:
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred rotating secrets of instance %s: %s", instanceID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
 	}
@@ -156,18 +467,21 @@ func (a *api) upgradeInstance(w http.ResponseWriter, r *http.Request) {
 	instanceID := vars["instance_id"]
 
 	requestID := uuid.New()
-	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeUpgrade), requestID, a.serviceOffering.Name, instanceID)
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeUpgrade), requestID, "", instanceID)
 
 	logger := a.loggerFactory.NewWithContext(ctx)
 
 	var details domain.UpdateDetails
 	if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
-		logger.Printf("error occurred parsing requests body: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred parsing requests body: %s", err)
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		a.writeJson(w, apiresponses.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
 		return
 	}
 
+	ctx = brokercontext.WithServiceName(ctx, a.serviceOfferingName(details.PlanID))
+	logger = a.loggerFactory.NewWithContext(ctx)
+
 	operationData, _, _, err := a.manageableBroker.Upgrade(ctx, instanceID, details, logger)
 
 	switch err.(type) {
@@ -182,40 +496,230 @@ func (a *api) upgradeInstance(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusConflict)
 	case broker.OperationAlreadyCompletedError:
 		w.WriteHeader(http.StatusNoContent)
+	case *apiresponses.FailureResponse:
+		a.writeFailureResponse(w, err.(*apiresponses.FailureResponse), logger)
 	case error:
-		logger.Printf("error occurred upgrading instance %s: %s", instanceID, err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred upgrading instance %s: %s", instanceID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
 	}
 }
 
+func (a *api) previewInstance(operationType broker.OperationType) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		instanceID := vars["instance_id"]
+
+		requestID := uuid.New()
+		ctx := brokercontext.New(r.Context(), string(operationType), requestID, "", instanceID)
+
+		logger := a.loggerFactory.NewWithContext(ctx)
+
+		var details domain.UpdateDetails
+		if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred parsing requests body: %s", err)
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			a.writeJson(w, apiresponses.ErrorResponse{Description: "Error in request body. Invalid JSON"}, logger)
+			return
+		}
+
+		ctx = brokercontext.WithServiceName(ctx, a.serviceOfferingName(details.PlanID))
+		logger = a.loggerFactory.NewWithContext(ctx)
+
+		preview := a.manageableBroker.PreviewUpgrade
+		if operationType == broker.OperationTypeUpdate {
+			preview = a.manageableBroker.PreviewUpdate
+		}
+		diff, err := preview(ctx, instanceID, details, logger)
+
+		switch err.(type) {
+		case nil:
+			w.WriteHeader(http.StatusOK)
+			a.writeJson(w, diff, logger)
+		case cf.ResourceNotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case broker.DeploymentNotFoundError:
+			w.WriteHeader(http.StatusGone)
+		case *apiresponses.FailureResponse:
+			a.writeFailureResponse(w, err.(*apiresponses.FailureResponse), logger)
+		case error:
+			loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred previewing %s of instance %s: %s", operationType, instanceID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			a.writeJson(w, apiresponses.ErrorResponse{Description: err.Error()}, logger)
+		}
+	}
+}
+
 func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 	logger := a.loggerFactory.NewWithRequestID()
-	instanceCountsByPlan, err := a.manageableBroker.CountInstancesOfPlans(logger)
-	if err != nil {
-		logger.Printf("error getting instance count for service offering %s: %s", a.serviceOffering.Name, err)
-		w.WriteHeader(http.StatusInternalServerError)
+	if wantsPrometheus(r) {
+		a.prometheusMetrics(r.Context(), w, logger)
 		return
 	}
 
-	if len(instanceCountsByPlan) == 0 {
-		logger.Printf("The %s service broker must be registered with Cloud Foundry before metrics can be collected", a.serviceOffering.Name)
+	instanceCountsByPlanID, unknownPlanIDs, err := a.instanceCountsByPlanID(r.Context(), logger)
+	switch {
+	case err == errBrokerNotRegistered:
 		w.WriteHeader(http.StatusServiceUnavailable)
 		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	case len(unknownPlanIDs) > 0:
+		a.writeJson(w, []interface{}{}, logger)
+		return
 	}
 
+	var scopedCounts *scopedInstanceCounts
+	if a.hasScopedQuotas() {
+		scopedCounts, err = a.scopedInstanceCounts(r.Context(), logger)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	jsonMetrics := []Metric{}
+	for _, brokerMetrics := range a.offeringMetrics(instanceCountsByPlanID, scopedCounts) {
+		jsonMetrics = append(jsonMetrics, brokerMetrics.metrics...)
+	}
+	jsonMetrics = append(jsonMetrics, contentionMetrics(a.manageableBroker.ContentionStats())...)
+
+	a.writeJson(w, jsonMetrics, logger)
+}
+
+// prometheusMetrics renders the metrics in the Prometheus text format. Unlike
+// the JSON metrics, a scrape does not fail as a whole when some of the metrics
+// cannot be collected: the metrics that depend on the instance counts are left
+// out, and on_demand_broker_metrics_collection_failed reports which collection
+// failed, while the lock contention and runtime metrics are still exposed.
+func (a *api) prometheusMetrics(ctx context.Context, w http.ResponseWriter, logger *log.Logger) {
+	var samples []metrics.Sample
+	failed := map[string]bool{}
+
+	instanceCountsByPlanID, _, err := a.instanceCountsByPlanID(ctx, logger)
+	failed[instanceCountsCollection] = err != nil
+	if err == nil {
+		var scopedCounts *scopedInstanceCounts
+		if a.hasScopedQuotas() {
+			scopedCounts, err = a.scopedInstanceCounts(ctx, logger)
+			failed[scopedInstanceCountsCollection] = err != nil
+		}
+		for _, brokerMetrics := range a.offeringMetrics(instanceCountsByPlanID, scopedCounts) {
+			samples = append(samples, brokerMetrics.samples...)
+		}
+	}
+	samples = append(samples, collectionSamples(failed)...)
+	samples = append(samples, contentionSamples(a.manageableBroker.ContentionStats())...)
+
+	w.Header().Set("Content-Type", metrics.TextContentType)
+	if err := metrics.WriteText(w, append(groupSamples(samples), a.registry.Families()...)); err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred writing metrics: %s", err)
+	}
+}
+
+var errBrokerNotRegistered = errors.New("the broker is not registered")
+
+// instanceCountsByPlanID returns the instance counts of the plans of the
+// service offerings, and the IDs of the plans CF counts instances of that are
+// not in any of them.
+func (a *api) instanceCountsByPlanID(ctx context.Context, logger *log.Logger) (map[string]int, []string, error) {
+	instanceCountsByPlan, err := a.manageableBroker.CountInstancesOfPlans(ctx, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error getting instance count for service offering %s: %s", a.serviceOfferingNames(), err)
+		return nil, nil, err
+	}
+
+	if len(instanceCountsByPlan) == 0 {
+		logger.Printf("The %s service broker must be registered with Cloud Foundry before metrics can be collected", a.serviceOfferingNames())
+		return nil, nil, errBrokerNotRegistered
+	}
+
+	instanceCountsByPlanID := map[string]int{}
+	var unknownPlanIDs []string
+	for plan, instanceCount := range instanceCountsByPlan {
+		planID := plan.ServicePlanEntity.UniqueID
+		if _, _, err := a.getPlan(planID); err != nil {
+			logger.Println(err)
+			unknownPlanIDs = append(unknownPlanIDs, planID)
+			continue
+		}
+		instanceCountsByPlanID[planID] += instanceCount
+	}
+	return instanceCountsByPlanID, unknownPlanIDs, nil
+}
+
+// scopedInstanceCounts are the instance counts of each plan ID, by the org and
+// by the space the instances are in.
+type scopedInstanceCounts struct {
+	orgs   map[string]map[string]int
+	spaces map[string]map[string]int
+}
+
+func (a *api) scopedInstanceCounts(ctx context.Context, logger *log.Logger) (*scopedInstanceCounts, error) {
+	counts, err := a.manageableBroker.CountInstancesOfPlansByOrgAndSpace(ctx, logger)
+	if err != nil {
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error getting instance count by org and space for service offering %s: %s", a.serviceOfferingNames(), err)
+		return nil, err
+	}
+	return &scopedInstanceCounts{
+		orgs:   countsByPlanID(counts.Orgs),
+		spaces: countsByPlanID(counts.Spaces),
+	}, nil
+}
+
+func countsByPlanID(countsByScope map[string]map[cf.ServicePlan]int) map[string]map[string]int {
+	instanceCounts := map[string]map[string]int{}
+	for scope, planCounts := range countsByScope {
+		instanceCounts[scope] = map[string]int{}
+		for plan, instanceCount := range planCounts {
+			instanceCounts[scope][plan.ServicePlanEntity.UniqueID] += instanceCount
+		}
+	}
+	return instanceCounts
+}
+
+// offeringMetrics returns the metrics of each service offering. The org and
+// space quota metrics are left out when scopedCounts is nil.
+func (a *api) offeringMetrics(instanceCountsByPlanID map[string]int, scopedCounts *scopedInstanceCounts) []BrokerMetrics {
+	var offeringMetrics []BrokerMetrics
+	for _, serviceOffering := range a.offerings.ServiceCatalogs() {
+		brokerMetrics := serviceOfferingMetrics(serviceOffering, instanceCountsByPlanID)
+		if scopedCounts != nil {
+			if serviceOffering.GlobalQuotas.Orgs != nil {
+				brokerMetrics = scopedQuotaMetrics(brokerMetrics, serviceOffering, serviceOffering.GlobalQuotas.Orgs, scopedCounts.orgs, BrokerMetrics.AddOrgMetric)
+			}
+			if serviceOffering.GlobalQuotas.Spaces != nil {
+				brokerMetrics = scopedQuotaMetrics(brokerMetrics, serviceOffering, serviceOffering.GlobalQuotas.Spaces, scopedCounts.spaces, BrokerMetrics.AddSpaceMetric)
+			}
+		}
+		offeringMetrics = append(offeringMetrics, brokerMetrics)
+	}
+	return offeringMetrics
+}
+
+// wantsPrometheus reports whether the metrics should be rendered in the
+// Prometheus text format rather than as JSON. The format query parameter takes
+// precedence over the Accept header sent by Prometheus scrapers.
+func wantsPrometheus(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "prometheus"
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text")
+}
+
+func serviceOfferingMetrics(serviceOffering config.ServiceOffering, instanceCountsByPlanID map[string]int) BrokerMetrics {
 	brokerMetrics := BrokerMetrics{
-		serviceOfferingName: a.serviceOffering.Name,
+		serviceOfferingName: serviceOffering.Name,
 	}
 
 	totalInstances := 0
 	globalCostsPerResource := map[string]int{}
-	for plan, instanceCount := range instanceCountsByPlan {
-		serviceOfferingPlan, err := a.getPlan(plan.ServicePlanEntity.UniqueID)
-		if err != nil {
-			logger.Println(err)
-			a.writeJson(w, []interface{}{}, logger)
-			return
+	for _, serviceOfferingPlan := range serviceOffering.Plans {
+		instanceCount, found := instanceCountsByPlanID[serviceOfferingPlan.ID]
+		if !found {
+			continue
 		}
 
 		brokerMetrics = brokerMetrics.AddPlanMetric(serviceOfferingPlan.Name, "total_instances", instanceCount)
@@ -253,33 +757,117 @@ func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 
 	brokerMetrics = brokerMetrics.AddGlobalMetric("total_instances", totalInstances)
 
-	if a.serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
-		limit := *a.serviceOffering.GlobalQuotas.ServiceInstanceLimit
+	if serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
+		limit := *serviceOffering.GlobalQuotas.ServiceInstanceLimit
 
 		brokerMetrics = brokerMetrics.AddGlobalMetric("quota_remaining", limit-totalInstances)
 	}
 
-	for resourceType, quota := range a.serviceOffering.GlobalQuotas.Resources {
+	for resourceType, quota := range serviceOffering.GlobalQuotas.Resources {
 		usedResource := globalCostsPerResource[resourceType]
 
 		brokerMetrics = brokerMetrics.AddGlobalMetric(fmt.Sprintf("%s/used", resourceType), usedResource)
 		brokerMetrics = brokerMetrics.AddGlobalMetric(fmt.Sprintf("%s/remaining", resourceType), quota.Limit-usedResource)
 	}
 
-	a.writeJson(w, brokerMetrics.metrics, logger)
+	return brokerMetrics
+}
+
+func (a *api) hasScopedQuotas() bool {
+	for _, serviceOffering := range a.offerings.ServiceCatalogs() {
+		if serviceOffering.GlobalQuotas.Orgs != nil || serviceOffering.GlobalQuotas.Spaces != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// scopedQuotaMetrics adds the quota remaining to each org, or each space, that
+// has instances of the service offering or quotas of its own.
+func scopedQuotaMetrics(
+	brokerMetrics BrokerMetrics,
+	serviceOffering config.ServiceOffering,
+	scopedQuotas *config.ScopedQuotas,
+	instanceCounts map[string]map[string]int,
+	addMetric func(m BrokerMetrics, guid, metricName string, value int) BrokerMetrics,
+) BrokerMetrics {
+	scopes := map[string]bool{}
+	for scope := range scopedQuotas.Overrides {
+		scopes[scope] = true
+	}
+	for scope, planCounts := range instanceCounts {
+		for _, plan := range serviceOffering.Plans {
+			if planCounts[plan.ID] > 0 {
+				scopes[scope] = true
+			}
+		}
+	}
+	var guids []string
+	for scope := range scopes {
+		guids = append(guids, scope)
+	}
+	sort.Strings(guids)
+
+	for _, guid := range guids {
+		quotas := scopedQuotas.For(guid)
+		planCounts := instanceCounts[guid]
+
+		totalInstances := 0
+		usedResources := map[string]int{}
+		for _, plan := range serviceOffering.Plans {
+			totalInstances += planCounts[plan.ID]
+			for resourceType := range quotas.Resources {
+				usedResources[resourceType] += plan.Quotas.Resources[resourceType].Cost * planCounts[plan.ID]
+			}
+		}
+
+		if quotas.ServiceInstanceLimit != nil {
+			brokerMetrics = addMetric(brokerMetrics, guid, "quota_remaining", *quotas.ServiceInstanceLimit-totalInstances)
+		}
+		for resourceType, quota := range quotas.Resources {
+			brokerMetrics = addMetric(brokerMetrics, guid, fmt.Sprintf("%s/remaining", resourceType), quota.Limit-usedResources[resourceType])
+		}
+	}
+
+	return brokerMetrics
+}
+
+// writeFailureResponse writes an error the broker returned for the platform,
+// such as when a request matches none of the service offerings.
+func (a *api) writeFailureResponse(w http.ResponseWriter, failure *apiresponses.FailureResponse, logger *log.Logger) {
+	w.WriteHeader(failure.ValidatedStatusCode(nil))
+	a.writeJson(w, failure.ErrorResponse(), logger)
 }
 
 func (a *api) writeJson(w io.Writer, obj interface{}, logger *log.Logger) {
 	if err := json.NewEncoder(w).Encode(obj); err != nil {
-		logger.Printf("error occurred encoding json: %s", err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("error occurred encoding json: %s", err)
 	}
 }
 
-func (a *api) getPlan(planID string) (config.Plan, error) {
-	for _, plan := range a.serviceOffering.Plans {
-		if plan.ID == planID {
-			return plan, nil
+func (a *api) getPlan(planID string) (config.ServiceOffering, config.Plan, error) {
+	for _, serviceOffering := range a.offerings.ServiceCatalogs() {
+		if plan, found := serviceOffering.FindPlanByID(planID); found {
+			return serviceOffering, plan, nil
 		}
 	}
-	return config.Plan{}, fmt.Errorf("no plan found with marketplace ID %s", planID)
+	return config.ServiceOffering{}, config.Plan{}, fmt.Errorf("no plan found with marketplace ID %s", planID)
+}
+
+// serviceOfferingName returns the name of the service offering that contains
+// the given plan, or an empty name if the plan is not known, in which case the
+// broker rejects the request.
+func (a *api) serviceOfferingName(planID string) string {
+	if serviceOffering, _, err := a.getPlan(planID); err == nil {
+		return serviceOffering.Name
+	}
+	return ""
+}
+
+func (a *api) serviceOfferingNames() string {
+	var names []string
+	for _, serviceOffering := range a.offerings.ServiceCatalogs() {
+		names = append(names, serviceOffering.Name)
+	}
+	return strings.Join(names, ", ")
 }
@@ -16,8 +16,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
 	"github.com/gorilla/mux"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,7 +29,9 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
+	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi/fake_config_reloader"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi/fake_manageable_broker"
 	"github.com/pivotal-cf/on-demand-service-broker/service"
 )
@@ -36,9 +40,13 @@ var _ = Describe("Management API", func() {
 	var (
 		server           *httptest.Server
 		manageableBroker *fake_manageable_broker.FakeManageableBroker
+		configReloader   *fake_config_reloader.FakeConfigReloader
+		reloader         mgmtapi.ConfigReloader
 		logs             *gbytes.Buffer
 		loggerFactory    *loggerfactory.LoggerFactory
 		serviceOffering  config.ServiceOffering
+		otherOfferings   []config.ServiceOffering
+		registry         *metrics.Registry
 	)
 
 	BeforeEach(func() {
@@ -56,14 +64,22 @@ var _ = Describe("Management API", func() {
 				},
 			},
 		}
+		otherOfferings = nil
+		registry = metrics.NewRegistry()
 		logs = gbytes.NewBuffer()
 		loggerFactory = loggerfactory.New(io.MultiWriter(GinkgoWriter, logs), "mgmtapi-unit-tests", log.LstdFlags)
 		manageableBroker = new(fake_manageable_broker.FakeManageableBroker)
+		configReloader = new(fake_config_reloader.FakeConfigReloader)
+		reloader = configReloader
 	})
 
 	JustBeforeEach(func() {
+		offerings := []config.OfferingConfig{{ServiceCatalog: serviceOffering}}
+		for _, other := range otherOfferings {
+			offerings = append(offerings, config.OfferingConfig{ServiceCatalog: other})
+		}
 		router := mux.NewRouter()
-		mgmtapi.AttachRoutes(router, manageableBroker, serviceOffering, loggerFactory)
+		mgmtapi.AttachRoutes(router, manageableBroker, config.NewOfferingStore(offerings), reloader, registry, loggerFactory)
 		server = httptest.NewServer(router)
 	})
 
@@ -104,7 +120,7 @@ var _ = Describe("Management API", func() {
 				Expect(json.NewDecoder(listResp.Body).Decode(&instancesResp)).To(Succeed())
 				Expect(instancesResp).To(ConsistOf(instance1, instance2, instance3))
 
-				filters, _ := manageableBroker.
+				_, filters, _ := manageableBroker.
 					InstancesArgsForCall(0)
 				Expect(filters).To(BeEmpty())
 			})
@@ -129,10 +145,24 @@ var _ = Describe("Management API", func() {
 				_, err := http.Get(fmt.Sprintf("%s/mgmt/service_instances?foo=bar", server.URL))
 				Expect(err).NotTo(HaveOccurred())
 
-				filters, _ := manageableBroker.
+				_, filters, _ := manageableBroker.
 					InstancesArgsForCall(0)
 				Expect(filters).To(Equal(map[string]string{"foo": "bar"}))
 			})
+
+			It("returns HTTP 400 when a filter is invalid", func() {
+				_, filterErr := service.ParseInstanceFilter(map[string]string{"min_age": "old"})
+				manageableBroker.
+					InstancesReturns(nil, filterErr)
+
+				listResp, err := http.Get(fmt.Sprintf("%s/mgmt/service_instances?min_age=old", server.URL))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(listResp.StatusCode).To(Equal(http.StatusBadRequest))
+				var errorResponse apiresponses.ErrorResponse
+				Expect(json.NewDecoder(listResp.Body).Decode(&errorResponse)).To(Succeed())
+				Expect(errorResponse.Description).To(ContainSubstring("invalid filter min_age"))
+			})
 		})
 	})
 
@@ -240,6 +270,17 @@ var _ = Describe("Management API", func() {
 				})
 			})
 
+			Context("when the request matches none of the service offerings", func() {
+				BeforeEach(func() {
+					manageableBroker.RecreateReturns(broker.OperationData{}, apiresponses.NewFailureResponse(errors.New("no service offering matches"), http.StatusBadRequest, "unknown"))
+				})
+
+				It("responds with the status of the failure", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"description": "no service offering matches"}`))
+				})
+			})
+
 			Context("when it fails", func() {
 				BeforeEach(func() {
 					manageableBroker.RecreateReturns(broker.OperationData{}, errors.New("recreate error"))
@@ -271,6 +312,151 @@ var _ = Describe("Management API", func() {
 			})
 		})
 
+		Context("when the process is a rollback", func() {
+			JustBeforeEach(func() {
+				var err error
+				response, err = Patch(fmt.Sprintf("%s/mgmt/service_instances/%s?operation_type=%s", server.URL, instanceID, "rollback"), requestBody)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			BeforeEach(func() {
+				manageableBroker.RollbackReturns(broker.OperationData{
+					BoshTaskID:    taskID,
+					OperationType: broker.OperationTypeRollback,
+				}, nil)
+			})
+
+			It("rolls back the instance using the broker", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusAccepted))
+				Expect(manageableBroker.RollbackCallCount()).To(Equal(1))
+				_, actualInstanceID, actualUpdateDetails, _ := manageableBroker.RollbackArgsForCall(0)
+				Expect(actualInstanceID).To(Equal(instanceID))
+				Expect(actualUpdateDetails).To(Equal(domain.UpdateDetails{PlanID: planID}))
+
+				var rollbackRespBody broker.OperationData
+				Expect(json.NewDecoder(response.Body).Decode(&rollbackRespBody)).To(Succeed())
+				Expect(rollbackRespBody.BoshTaskID).To(Equal(taskID))
+				Expect(rollbackRespBody.OperationType).To(Equal(broker.OperationTypeRollback))
+			})
+
+			Context("when there is no deployment to roll back to", func() {
+				BeforeEach(func() {
+					manageableBroker.RollbackReturns(broker.OperationData{}, broker.NewNoRollbackSnapshotError(errors.New("no successful deployment recorded")))
+				})
+
+				It("responds with HTTP 422 and the reason", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"description": "no successful deployment recorded"}`))
+				})
+			})
+
+			Context("when there is an operation in progress", func() {
+				BeforeEach(func() {
+					manageableBroker.RollbackReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("operation in progress error")))
+				})
+
+				It("responds with HTTP 409 Conflict", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when it fails", func() {
+				BeforeEach(func() {
+					manageableBroker.RollbackReturns(broker.OperationData{}, errors.New("rollback error"))
+				})
+
+				It("responds with HTTP 500 and logs the error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"description": "rollback error"}`))
+					Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred rolling back instance %s: rollback error", instanceID)))
+				})
+			})
+		})
+
+		Context("when the process is a secret rotation", func() {
+			JustBeforeEach(func() {
+				var err error
+				response, err = Patch(fmt.Sprintf("%s/mgmt/service_instances/%s?operation_type=%s", server.URL, instanceID, "rotate-secrets"), requestBody)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			BeforeEach(func() {
+				manageableBroker.RotateSecretsReturns(broker.OperationData{
+					BoshTaskID:    taskID,
+					OperationType: broker.OperationTypeRotateSecrets,
+				}, nil)
+			})
+
+			It("rotates the secrets of the instance using the broker", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusAccepted))
+				Expect(manageableBroker.RotateSecretsCallCount()).To(Equal(1))
+				_, actualInstanceID, actualUpdateDetails, actualSecretPaths, _ := manageableBroker.RotateSecretsArgsForCall(0)
+				Expect(actualInstanceID).To(Equal(instanceID))
+				Expect(actualUpdateDetails).To(Equal(domain.UpdateDetails{PlanID: planID}))
+				Expect(actualSecretPaths).To(BeEmpty())
+
+				var rotateRespBody broker.OperationData
+				Expect(json.NewDecoder(response.Body).Decode(&rotateRespBody)).To(Succeed())
+				Expect(rotateRespBody.BoshTaskID).To(Equal(taskID))
+				Expect(rotateRespBody.OperationType).To(Equal(broker.OperationTypeRotateSecrets))
+			})
+
+			Context("when secret paths are given", func() {
+				BeforeEach(func() {
+					requestBody = fmt.Sprintf(`{"plan_id":"%s","secret_paths":["admin_password","tls_cert"]}`, planID)
+				})
+
+				It("rotates only those secrets", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusAccepted))
+					_, _, _, actualSecretPaths, _ := manageableBroker.RotateSecretsArgsForCall(0)
+					Expect(actualSecretPaths).To(Equal([]string{"admin_password", "tls_cert"}))
+				})
+			})
+
+			Context("when a secret is not found", func() {
+				BeforeEach(func() {
+					manageableBroker.RotateSecretsReturns(broker.OperationData{}, broker.NewSecretsNotFoundError(errors.New("secrets not found in deployment: foo")))
+				})
+
+				It("responds with HTTP 422 and the reason", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"description": "secrets not found in deployment: foo"}`))
+				})
+			})
+
+			Context("when the bosh deployment is not found", func() {
+				BeforeEach(func() {
+					manageableBroker.RotateSecretsReturns(broker.OperationData{}, broker.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+				})
+
+				It("responds with HTTP 410 Gone", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusGone))
+				})
+			})
+
+			Context("when there is an operation in progress", func() {
+				BeforeEach(func() {
+					manageableBroker.RotateSecretsReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("operation in progress error")))
+				})
+
+				It("responds with HTTP 409 Conflict", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when it fails", func() {
+				BeforeEach(func() {
+					manageableBroker.RotateSecretsReturns(broker.OperationData{}, errors.New("rotate error"))
+				})
+
+				It("responds with HTTP 500 and logs the error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"description": "rotate error"}`))
+					Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred rotating secrets of instance %s: rotate error", instanceID)))
+				})
+			})
+		})
+
 		Context("when the process is an upgrade", func() {
 			It("succeeds when instance is upgraded using the broker", func() {
 				contextID := "some-context-id"
@@ -397,6 +583,41 @@ var _ = Describe("Management API", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		Context("contention", func() {
+			BeforeEach(func() {
+				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+					cfServicePlan("1234", "foo_id", "url", "foo_plan"): 2,
+				}, nil)
+				manageableBroker.ContentionStatsReturns(broker.ContentionStats{
+					InstanceLocks: broker.Contention{Acquisitions: 10, Contended: 3, Waiting: 1, WaitTime: 1500 * time.Millisecond},
+					AdapterCalls:  broker.Contention{Acquisitions: 7, Contended: 2},
+				})
+			})
+
+			It("exposes the lock contention metrics, in a stable order", func() {
+				defer instancesForPlanResponse.Body.Close()
+				var brokerMetrics []mgmtapi.Metric
+
+				Expect(json.NewDecoder(instancesForPlanResponse.Body).Decode(&brokerMetrics)).To(Succeed())
+				var contentionMetrics []mgmtapi.Metric
+				for _, metric := range brokerMetrics {
+					if strings.Contains(metric.Key, "instance_locks") || strings.Contains(metric.Key, "adapter_calls") {
+						contentionMetrics = append(contentionMetrics, metric)
+					}
+				}
+				Expect(contentionMetrics).To(Equal([]mgmtapi.Metric{
+					{Key: "/on-demand-broker/instance_locks/acquisitions", Value: 10, Unit: "count"},
+					{Key: "/on-demand-broker/instance_locks/contended", Value: 3, Unit: "count"},
+					{Key: "/on-demand-broker/instance_locks/waiting", Value: 1, Unit: "count"},
+					{Key: "/on-demand-broker/instance_locks/wait_time", Value: 1500, Unit: "ms"},
+					{Key: "/on-demand-broker/adapter_calls/acquisitions", Value: 7, Unit: "count"},
+					{Key: "/on-demand-broker/adapter_calls/contended", Value: 2, Unit: "count"},
+					{Key: "/on-demand-broker/adapter_calls/waiting", Value: 0, Unit: "count"},
+					{Key: "/on-demand-broker/adapter_calls/wait_time", Value: 0, Unit: "ms"},
+				}))
+			})
+		})
+
 		Context("instance quotas", func() {
 			Context("when no quota is set", func() {
 				Context("when there is one plan with instance count", func() {
@@ -474,6 +695,49 @@ var _ = Describe("Management API", func() {
 				})
 			})
 
+			Context("when the broker serves several service offerings", func() {
+				BeforeEach(func() {
+					otherOfferings = []config.ServiceOffering{{
+						ID:    "another_service_offering-id",
+						Name:  "another_service_offering",
+						Plans: []config.Plan{{ID: "baz_id", Name: "baz_plan"}},
+					}}
+					manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+						cfServicePlan("1234", "foo_id", "url", "name"): 2,
+						cfServicePlan("5678", "baz_id", "url", "name"): 4,
+					}, nil)
+				})
+
+				It("returns the metrics of each offering", func() {
+					defer instancesForPlanResponse.Body.Close()
+					var brokerMetrics []mgmtapi.Metric
+
+					Expect(json.NewDecoder(instancesForPlanResponse.Body).Decode(&brokerMetrics)).To(Succeed())
+					Expect(offeringMetrics(brokerMetrics)).To(ConsistOf(
+						mgmtapi.Metric{
+							Key:   "/on-demand-broker/some_service_offering/foo_plan/total_instances",
+							Value: 2,
+							Unit:  "count",
+						},
+						mgmtapi.Metric{
+							Key:   "/on-demand-broker/some_service_offering/total_instances",
+							Value: 2,
+							Unit:  "count",
+						},
+						mgmtapi.Metric{
+							Key:   "/on-demand-broker/another_service_offering/baz_plan/total_instances",
+							Value: 4,
+							Unit:  "count",
+						},
+						mgmtapi.Metric{
+							Key:   "/on-demand-broker/another_service_offering/total_instances",
+							Value: 4,
+							Unit:  "count",
+						},
+					))
+				})
+			})
+
 			Context("when a plan quota is set", func() {
 				BeforeEach(func() {
 					limit := 7
@@ -496,7 +760,7 @@ var _ = Describe("Management API", func() {
 						var brokerMetrics []mgmtapi.Metric
 
 						Expect(json.NewDecoder(instancesForPlanResponse.Body).Decode(&brokerMetrics)).To(Succeed())
-						Expect(brokerMetrics).To(ConsistOf(
+						Expect(offeringMetrics(brokerMetrics)).To(ConsistOf(
 							mgmtapi.Metric{
 								Key:   "/on-demand-broker/some_service_offering/foo_plan/total_instances",
 								Value: 2,
@@ -571,6 +835,96 @@ var _ = Describe("Management API", func() {
 					It("counts instances for the plan", func() {
 						Expect(manageableBroker.CountInstancesOfPlansCallCount()).To(Equal(1))
 					})
+
+					It("does not count instances by org and space", func() {
+						Expect(manageableBroker.CountInstancesOfPlansByOrgAndSpaceCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when org and space quotas are set", func() {
+				BeforeEach(func() {
+					defaultLimit, overrideLimit, spaceLimit := 3, 10, 2
+					serviceOffering.GlobalQuotas = config.Quotas{
+						Orgs: &config.ScopedQuotas{
+							Default: config.Quotas{ServiceInstanceLimit: &defaultLimit},
+							Overrides: map[string]config.Quotas{
+								"big-org": {ServiceInstanceLimit: &overrideLimit},
+							},
+						},
+						Spaces: &config.ScopedQuotas{
+							Default: config.Quotas{ServiceInstanceLimit: &spaceLimit},
+						},
+					}
+					manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+						cfServicePlan("1234", "foo_id", "url", "name"): 2,
+						cfServicePlan("1234", "bar_id", "url", "name"): 1,
+					}, nil)
+				})
+
+				Context("when the instance count by org and space can be retrieved", func() {
+					BeforeEach(func() {
+						manageableBroker.CountInstancesOfPlansByOrgAndSpaceReturns(cf.OrgAndSpaceInstanceCounts{
+							Orgs: map[string]map[cf.ServicePlan]int{
+								"an-org": {
+									cfServicePlan("1234", "foo_id", "url", "name"): 2,
+									cfServicePlan("1234", "bar_id", "url", "name"): 1,
+								},
+							},
+							Spaces: map[string]map[cf.ServicePlan]int{
+								"a-space": {
+									cfServicePlan("1234", "foo_id", "url", "name"): 2,
+								},
+								"another-space": {
+									cfServicePlan("1234", "bar_id", "url", "name"): 1,
+								},
+							},
+						}, nil)
+					})
+
+					It("returns the quota remaining of each org and each space", func() {
+						defer instancesForPlanResponse.Body.Close()
+						Expect(instancesForPlanResponse.StatusCode).To(Equal(http.StatusOK))
+
+						var brokerMetrics []mgmtapi.Metric
+						Expect(json.NewDecoder(instancesForPlanResponse.Body).Decode(&brokerMetrics)).To(Succeed())
+						Expect(brokerMetrics).To(SatisfyAll(
+							ContainElement(mgmtapi.Metric{
+								Key:   "/on-demand-broker/some_service_offering/orgs/an-org/quota_remaining",
+								Value: 0,
+								Unit:  "count",
+							}),
+							ContainElement(mgmtapi.Metric{
+								Key:   "/on-demand-broker/some_service_offering/orgs/big-org/quota_remaining",
+								Value: 10,
+								Unit:  "count",
+							}),
+							ContainElement(mgmtapi.Metric{
+								Key:   "/on-demand-broker/some_service_offering/spaces/a-space/quota_remaining",
+								Value: 0,
+								Unit:  "count",
+							}),
+							ContainElement(mgmtapi.Metric{
+								Key:   "/on-demand-broker/some_service_offering/spaces/another-space/quota_remaining",
+								Value: 1,
+								Unit:  "count",
+							}),
+						))
+					})
+				})
+
+				Context("when the instance count by org and space cannot be retrieved", func() {
+					BeforeEach(func() {
+						manageableBroker.CountInstancesOfPlansByOrgAndSpaceReturns(cf.OrgAndSpaceInstanceCounts{}, errors.New("CF is down"))
+					})
+
+					It("returns HTTP 500", func() {
+						Expect(instancesForPlanResponse.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+
+					It("logs the error", func() {
+						Expect(logs).To(gbytes.Say("error getting instance count by org and space for service offering some_service_offering: CF is down"))
+					})
 				})
 			})
 
@@ -901,6 +1255,418 @@ var _ = Describe("Management API", func() {
 		})
 	})
 
+	Describe("producing service metrics in the Prometheus format", func() {
+		var (
+			query    string
+			accept   string
+			response *http.Response
+			body     string
+		)
+
+		BeforeEach(func() {
+			planLimit := 7
+			globalLimit := 20
+			serviceOffering.Plans[0].Quotas = config.Quotas{
+				ServiceInstanceLimit: &planLimit,
+				Resources:            map[string]config.ResourceQuota{"memory": {Limit: 60, Cost: 10}},
+			}
+			serviceOffering.GlobalQuotas = config.Quotas{ServiceInstanceLimit: &globalLimit}
+			manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+				cfServicePlan("1234", "foo_id", "url", "foo_plan"): 2,
+			}, nil)
+			manageableBroker.ContentionStatsReturns(broker.ContentionStats{
+				InstanceLocks: broker.Contention{Acquisitions: 10, WaitTime: 1500 * time.Millisecond},
+			})
+			registry.ObserveBoshTask("some_service_offering", "foo_plan", "create", "failed")
+			query = ""
+			accept = ""
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/mgmt/metrics%s", server.URL, query), nil)
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Set("Accept", accept)
+			response, err = http.DefaultClient.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+			defer response.Body.Close()
+			bodyBytes, err := ioutil.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+			body = string(bodyBytes)
+		})
+
+		When("the scraper accepts the text format", func() {
+			BeforeEach(func() {
+				accept = "application/openmetrics-text;version=1.0.0;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1"
+			})
+
+			It("labels the instance and quota metrics with the offering, plan and resource", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal(metrics.TextContentType))
+				Expect(body).To(ContainSubstring(`# HELP on_demand_broker_plan_total_instances Service instances of the plan.
+# TYPE on_demand_broker_plan_total_instances gauge
+on_demand_broker_plan_total_instances{offering="some_service_offering",plan="foo_plan"} 2
+`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_plan_quota_remaining{offering="some_service_offering",plan="foo_plan"} 5
+`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_plan_resource_used{offering="some_service_offering",plan="foo_plan",resource="memory"} 20
+`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_plan_resource_remaining{offering="some_service_offering",plan="foo_plan",resource="memory"} 40
+`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_total_instances{offering="some_service_offering"} 2
+`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_quota_remaining{offering="some_service_offering"} 18
+`))
+			})
+
+			It("includes the lock contention and runtime metrics", func() {
+				Expect(body).To(ContainSubstring(`# TYPE on_demand_broker_lock_acquisitions_total counter
+on_demand_broker_lock_acquisitions_total{lock="instance_locks"} 10
+on_demand_broker_lock_acquisitions_total{lock="adapter_calls"} 0
+`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_lock_wait_seconds_total{lock="instance_locks"} 1.5
+`))
+				Expect(body).To(ContainSubstring(`# TYPE on_demand_broker_bosh_tasks_total counter
+on_demand_broker_bosh_tasks_total{offering="some_service_offering",operation="create",plan="foo_plan",state="failed"} 1
+`))
+			})
+		})
+
+		When("CF counts instances of a plan that is not in the catalog", func() {
+			BeforeEach(func() {
+				query = "?format=prometheus"
+				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{
+					cfServicePlan("1234", "foo_id", "url", "foo_plan"):         2,
+					cfServicePlan("5678", "unknown_id", "url", "unknown_plan"): 3,
+				}, nil)
+			})
+
+			It("leaves out the unknown plan and exposes the others", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(body).To(ContainSubstring(`on_demand_broker_plan_total_instances{offering="some_service_offering",plan="foo_plan"} 2`))
+				Expect(body).NotTo(ContainSubstring("unknown_plan"))
+				Expect(body).To(ContainSubstring(`on_demand_broker_metrics_collection_failed{collection="instance_counts"} 0`))
+				Eventually(logs).Should(gbytes.Say("no plan found with marketplace ID unknown_id"))
+			})
+		})
+
+		When("the broker is not registered with CF", func() {
+			BeforeEach(func() {
+				query = "?format=prometheus"
+				manageableBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{}, nil)
+			})
+
+			It("reports the failed collection and still exposes the other metrics", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(body).To(ContainSubstring(`on_demand_broker_metrics_collection_failed{collection="instance_counts"} 1`))
+				Expect(body).NotTo(ContainSubstring("on_demand_broker_total_instances"))
+				Expect(body).To(ContainSubstring(`on_demand_broker_lock_acquisitions_total{lock="instance_locks"} 10`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_bosh_tasks_total{offering="some_service_offering",operation="create",plan="foo_plan",state="failed"} 1`))
+			})
+		})
+
+		When("the instances cannot be counted", func() {
+			BeforeEach(func() {
+				query = "?format=prometheus"
+				manageableBroker.CountInstancesOfPlansReturns(nil, errors.New("CF is down"))
+			})
+
+			It("reports the failed collection and still exposes the other metrics", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(body).To(ContainSubstring(`on_demand_broker_metrics_collection_failed{collection="instance_counts"} 1`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_lock_acquisitions_total{lock="instance_locks"} 10`))
+			})
+		})
+
+		When("the instances of the orgs and spaces cannot be counted", func() {
+			BeforeEach(func() {
+				query = "?format=prometheus"
+				orgLimit := 3
+				serviceOffering.GlobalQuotas.Orgs = &config.ScopedQuotas{Default: config.Quotas{ServiceInstanceLimit: &orgLimit}}
+				manageableBroker.CountInstancesOfPlansByOrgAndSpaceReturns(cf.OrgAndSpaceInstanceCounts{}, errors.New("CF is down"))
+			})
+
+			It("leaves out the org and space metrics only", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(body).To(ContainSubstring(`on_demand_broker_metrics_collection_failed{collection="instance_counts"} 0`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_metrics_collection_failed{collection="org_and_space_instance_counts"} 1`))
+				Expect(body).To(ContainSubstring(`on_demand_broker_total_instances{offering="some_service_offering"} 2`))
+				Expect(body).NotTo(ContainSubstring("on_demand_broker_org_quota_remaining"))
+			})
+		})
+
+		When("the format is requested with the query parameter", func() {
+			BeforeEach(func() {
+				query = "?format=prometheus"
+			})
+
+			It("renders the text format", func() {
+				Expect(response.Header.Get("Content-Type")).To(Equal(metrics.TextContentType))
+				Expect(body).To(ContainSubstring(`on_demand_broker_total_instances{offering="some_service_offering"} 2`))
+			})
+		})
+
+		When("no format is requested", func() {
+			It("renders JSON", func() {
+				var brokerMetrics []mgmtapi.Metric
+				Expect(json.Unmarshal([]byte(body), &brokerMetrics)).To(Succeed())
+				Expect(brokerMetrics).To(ContainElement(mgmtapi.Metric{
+					Key:   "/on-demand-broker/some_service_offering/total_instances",
+					Value: 2,
+					Unit:  "count",
+				}))
+			})
+		})
+	})
+
+	Describe("previewing an operation on an instance", func() {
+		var (
+			response      *http.Response
+			operationType string
+			diff          = broker.ManifestDiff{
+				InstanceGroups: []broker.ManifestChange{{Path: "/instance_groups/name=redis/instances", Before: 1, After: 3}},
+			}
+		)
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = http.Post(
+				fmt.Sprintf("%s/mgmt/service_instances/some-instance-id/preview?operation_type=%s", server.URL, operationType),
+				"application/json",
+				strings.NewReader(`{"plan_id": "some-plan-id", "previous_values": {"plan_id": "another-plan-id"}}`),
+			)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("previewing an upgrade", func() {
+			BeforeEach(func() {
+				operationType = "upgrade"
+				manageableBroker.PreviewUpgradeReturns(diff, nil)
+			})
+
+			It("responds with the difference the upgrade would make", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				defer response.Body.Close()
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(body).To(MatchJSON(`{
+					"releases": null,
+					"stemcells": null,
+					"instance_groups": [{"path": "/instance_groups/name=redis/instances", "before": 1, "after": 3}],
+					"properties": null
+				}`))
+
+				Expect(manageableBroker.PreviewUpgradeCallCount()).To(Equal(1))
+				_, actualInstanceID, actualDetails, _ := manageableBroker.PreviewUpgradeArgsForCall(0)
+				Expect(actualInstanceID).To(Equal("some-instance-id"))
+				Expect(actualDetails.PlanID).To(Equal("some-plan-id"))
+				Expect(manageableBroker.UpgradeCallCount()).To(BeZero())
+			})
+
+			Context("when the bosh deployment is not found", func() {
+				BeforeEach(func() {
+					manageableBroker.PreviewUpgradeReturns(broker.ManifestDiff{}, broker.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+				})
+
+				It("responds with HTTP 410 Gone", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusGone))
+				})
+			})
+
+			Context("when the preview fails", func() {
+				BeforeEach(func() {
+					manageableBroker.PreviewUpgradeReturns(broker.ManifestDiff{}, errors.New("adapter failed"))
+				})
+
+				It("responds with HTTP 500 and the error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					var errorResponse apiresponses.ErrorResponse
+					Expect(json.NewDecoder(response.Body).Decode(&errorResponse)).To(Succeed())
+					Expect(errorResponse.Description).To(Equal("adapter failed"))
+				})
+			})
+		})
+
+		Context("previewing an update", func() {
+			BeforeEach(func() {
+				operationType = "update"
+				manageableBroker.PreviewUpdateReturns(diff, nil)
+			})
+
+			It("responds with the difference the update would make", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				Expect(manageableBroker.PreviewUpdateCallCount()).To(Equal(1))
+				_, actualInstanceID, actualDetails, _ := manageableBroker.PreviewUpdateArgsForCall(0)
+				Expect(actualInstanceID).To(Equal("some-instance-id"))
+				Expect(actualDetails.PreviousValues.PlanID).To(Equal("another-plan-id"))
+				Expect(manageableBroker.PreviewUpgradeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the operation type is not supported", func() {
+			BeforeEach(func() {
+				operationType = "recreate"
+			})
+
+			It("responds with HTTP 400 Bad Request", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("listing the operations of an instance", func() {
+		var listResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			listResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_instances/some-instance-id/operations", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the history can be retrieved", func() {
+			startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			BeforeEach(func() {
+				manageableBroker.OperationHistoryReturns([]broker.OperationHistoryEntry{{
+					Type:         broker.OperationTypeUpdate,
+					StartedAt:    startedAt,
+					RequestID:    "some-request-id",
+					PlanIDBefore: "plan-a",
+					PlanIDAfter:  "plan-b",
+					BoshTaskIDs:  []int{42, 43},
+					Errands:      []string{"health-check"},
+					Outcome:      domain.Succeeded,
+				}}, nil)
+			})
+
+			It("returns HTTP 200", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("returns the operations of the instance", func() {
+				defer listResp.Body.Close()
+				body, err := ioutil.ReadAll(listResp.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(body).To(MatchJSON(`[{
+					"type": "update",
+					"started_at": "2026-01-02T03:04:05Z",
+					"request_id": "some-request-id",
+					"plan_id_before": "plan-a",
+					"plan_id_after": "plan-b",
+					"bosh_task_ids": [42, 43],
+					"errands": ["health-check"],
+					"outcome": "succeeded"
+				}]`))
+
+				Expect(manageableBroker.OperationHistoryCallCount()).To(Equal(1))
+				_, instanceID, _ := manageableBroker.OperationHistoryArgsForCall(0)
+				Expect(instanceID).To(Equal("some-instance-id"))
+			})
+		})
+
+		Context("when the broker returns an error", func() {
+			BeforeEach(func() {
+				manageableBroker.OperationHistoryReturns(nil, errors.New("director unreachable"))
+			})
+
+			It("returns HTTP 500", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+
+			It("logs an error", func() {
+				Eventually(logs).Should(gbytes.Say("error occurred querying operations of instance some-instance-id: director unreachable"))
+			})
+		})
+
+		Context("when the instance is unknown", func() {
+			BeforeEach(func() {
+				manageableBroker.OperationHistoryReturns(nil, broker.NewDeploymentNotFoundError(errors.New("instance some-instance-id not found")))
+			})
+
+			It("returns HTTP 404", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("iterator checkpoints", func() {
+		doRequest := func(method, name, body string) *http.Response {
+			req, err := http.NewRequest(method, fmt.Sprintf("%s/mgmt/iterator_checkpoints/%s", server.URL, name), strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			return resp
+		}
+
+		It("returns the stored checkpoint", func() {
+			manageableBroker.IteratorCheckpointReturns([]byte(`{"canaries_completed":true}`), true, nil)
+
+			resp := doRequest(http.MethodGet, "upgrade-all-bosh", "")
+
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(ioutil.ReadAll(resp.Body)).To(MatchJSON(`{"canaries_completed":true}`))
+			_, name, _ := manageableBroker.IteratorCheckpointArgsForCall(0)
+			Expect(name).To(Equal("upgrade-all-bosh"))
+		})
+
+		It("returns HTTP 404 when there is no checkpoint", func() {
+			manageableBroker.IteratorCheckpointReturns(nil, false, nil)
+
+			resp := doRequest(http.MethodGet, "upgrade-all-bosh", "")
+
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("returns HTTP 500 when the checkpoint cannot be read", func() {
+			manageableBroker.IteratorCheckpointReturns(nil, false, errors.New("director unreachable"))
+
+			resp := doRequest(http.MethodGet, "upgrade-all-bosh", "")
+
+			Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+			Eventually(logs).Should(gbytes.Say("error occurred reading checkpoint upgrade-all-bosh: director unreachable"))
+		})
+
+		It("stores a checkpoint", func() {
+			resp := doRequest(http.MethodPut, "upgrade-all-bosh", `{"canaries_completed":true}`)
+
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(manageableBroker.SaveIteratorCheckpointCallCount()).To(Equal(1))
+			_, name, checkpoint, _ := manageableBroker.SaveIteratorCheckpointArgsForCall(0)
+			Expect(name).To(Equal("upgrade-all-bosh"))
+			Expect(checkpoint).To(MatchJSON(`{"canaries_completed":true}`))
+		})
+
+		It("rejects a checkpoint that is not JSON", func() {
+			resp := doRequest(http.MethodPut, "upgrade-all-bosh", "not json")
+
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(manageableBroker.SaveIteratorCheckpointCallCount()).To(BeZero())
+		})
+
+		It("rejects an invalid checkpoint name", func() {
+			resp := doRequest(http.MethodPut, "Upgrade_All", "{}")
+
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(manageableBroker.SaveIteratorCheckpointCallCount()).To(BeZero())
+		})
+
+		It("clears a checkpoint", func() {
+			resp := doRequest(http.MethodDelete, "upgrade-all-bosh", "")
+
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+			_, name, _ := manageableBroker.ClearIteratorCheckpointArgsForCall(0)
+			Expect(name).To(Equal("upgrade-all-bosh"))
+		})
+
+		It("returns HTTP 500 when the checkpoint cannot be cleared", func() {
+			manageableBroker.ClearIteratorCheckpointReturns(errors.New("director unreachable"))
+
+			resp := doRequest(http.MethodDelete, "upgrade-all-bosh", "")
+
+			Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
 	Describe("listing orphan service deployments", func() {
 		var listResp *http.Response
 
@@ -969,6 +1735,136 @@ var _ = Describe("Management API", func() {
 			})
 		})
 	})
+
+	Describe("cleaning up orphan service deployments", func() {
+		var (
+			cleanupResp *http.Response
+			requestBody string
+		)
+
+		BeforeEach(func() {
+			requestBody = `{"grace_period_in_seconds":3600,"allow_list":["keep-me"],"dry_run":true}`
+			manageableBroker.OrphanDeploymentsReturns([]string{"service-instance_orphan"}, nil)
+			manageableBroker.CleanupOrphanDeploymentsReturns(broker.OrphanCleanupReport{
+				DryRun: true,
+				Orphans: []broker.OrphanCleanup{{
+					DeploymentName: "service-instance_orphan",
+					Action:         broker.OrphanMarked,
+				}},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			cleanupResp, err = http.Post(fmt.Sprintf("%s/mgmt/orphan_deployments/cleanup", server.URL), "application/json", strings.NewReader(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("cleans up the orphans using the broker", func() {
+			Expect(cleanupResp.StatusCode).To(Equal(http.StatusOK))
+
+			Expect(manageableBroker.CleanupOrphanDeploymentsCallCount()).To(Equal(1))
+			_, orphans, options, _ := manageableBroker.CleanupOrphanDeploymentsArgsForCall(0)
+			Expect(orphans).To(Equal([]string{"service-instance_orphan"}))
+			Expect(options).To(Equal(broker.OrphanCleanupOptions{
+				GracePeriod: time.Hour,
+				AllowList:   []string{"keep-me"},
+				DryRun:      true,
+			}))
+
+			var report broker.OrphanCleanupReport
+			Expect(json.NewDecoder(cleanupResp.Body).Decode(&report)).To(Succeed())
+			Expect(report.DryRun).To(BeTrue())
+			Expect(report.Orphans).To(ConsistOf(broker.OrphanCleanup{
+				DeploymentName: "service-instance_orphan",
+				Action:         broker.OrphanMarked,
+			}))
+		})
+
+		Context("when the request body is invalid", func() {
+			BeforeEach(func() {
+				requestBody = "not json"
+			})
+
+			It("responds with HTTP 422", func() {
+				Expect(cleanupResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(manageableBroker.CleanupOrphanDeploymentsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the grace period is not set", func() {
+			BeforeEach(func() {
+				requestBody = `{"allow_list":["keep-me"]}`
+			})
+
+			It("responds with HTTP 422", func() {
+				Expect(cleanupResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(cleanupResp.Body)).To(MatchJSON(`{"description": "grace_period_in_seconds must be greater than zero"}`))
+				Expect(manageableBroker.CleanupOrphanDeploymentsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the orphans cannot be listed", func() {
+			BeforeEach(func() {
+				manageableBroker.OrphanDeploymentsReturns(nil, errors.New("Broker errored."))
+			})
+
+			It("responds with HTTP 500", func() {
+				Expect(cleanupResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(manageableBroker.CleanupOrphanDeploymentsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the cleanup fails", func() {
+			BeforeEach(func() {
+				manageableBroker.CleanupOrphanDeploymentsReturns(broker.OrphanCleanupReport{}, errors.New("director unavailable"))
+			})
+
+			It("responds with HTTP 500 and logs the error", func() {
+				Expect(cleanupResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(ioutil.ReadAll(cleanupResp.Body)).To(MatchJSON(`{"description": "director unavailable"}`))
+				Eventually(logs).Should(gbytes.Say("error occurred cleaning up orphan deployments: director unavailable"))
+			})
+		})
+	})
+
+	Describe("reloading the config", func() {
+		var reloadResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			reloadResp, err = http.Post(fmt.Sprintf("%s/mgmt/reload", server.URL), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reloads the config", func() {
+			Expect(reloadResp.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(configReloader.ReloadCallCount()).To(Equal(1))
+			Eventually(logs).Should(gbytes.Say("config reloaded"))
+		})
+
+		Context("when the new config is rejected", func() {
+			BeforeEach(func() {
+				configReloader.ReloadReturns(errors.New("service offerings cannot be added or removed without a restart"))
+			})
+
+			It("responds with HTTP 422 and the reason", func() {
+				Expect(reloadResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(reloadResp.Body)).To(MatchJSON(`{"description": "service offerings cannot be added or removed without a restart"}`))
+				Eventually(logs).Should(gbytes.Say("error reloading config, the current config stays in use"))
+			})
+		})
+
+		Context("when reloading is not enabled", func() {
+			BeforeEach(func() {
+				reloader = nil
+			})
+
+			It("responds with HTTP 404", func() {
+				Expect(reloadResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
 })
 
 func Patch(url, body string) (resp *http.Response, err error) {
@@ -980,6 +1876,19 @@ func Patch(url, body string) (resp *http.Response, err error) {
 	return http.DefaultClient.Do(req)
 }
 
+// offeringMetrics drops the broker-wide contention metrics, leaving only the
+// ones of the service offerings.
+func offeringMetrics(metrics []mgmtapi.Metric) []mgmtapi.Metric {
+	var filtered []mgmtapi.Metric
+	for _, metric := range metrics {
+		if strings.HasPrefix(metric.Key, "/on-demand-broker/instance_locks/") || strings.HasPrefix(metric.Key, "/on-demand-broker/adapter_calls/") {
+			continue
+		}
+		filtered = append(filtered, metric)
+	}
+	return filtered
+}
+
 func cfServicePlan(guid, uniqueID, servicePlanUrl, name string) cf.ServicePlan {
 	return cf.ServicePlan{
 		Metadata: cf.Metadata{
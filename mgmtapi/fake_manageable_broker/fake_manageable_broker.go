@@ -14,10 +14,50 @@ import (
 )
 
 type FakeManageableBroker struct {
-	CountInstancesOfPlansStub        func(*log.Logger) (map[cf.ServicePlan]int, error)
+	CleanupOrphanDeploymentsStub        func(context.Context, []string, broker.OrphanCleanupOptions, *log.Logger) (broker.OrphanCleanupReport, error)
+	cleanupOrphanDeploymentsMutex       sync.RWMutex
+	cleanupOrphanDeploymentsArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 broker.OrphanCleanupOptions
+		arg4 *log.Logger
+	}
+	cleanupOrphanDeploymentsReturns struct {
+		result1 broker.OrphanCleanupReport
+		result2 error
+	}
+	cleanupOrphanDeploymentsReturnsOnCall map[int]struct {
+		result1 broker.OrphanCleanupReport
+		result2 error
+	}
+	ClearIteratorCheckpointStub        func(context.Context, string, *log.Logger) error
+	clearIteratorCheckpointMutex       sync.RWMutex
+	clearIteratorCheckpointArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}
+	clearIteratorCheckpointReturns struct {
+		result1 error
+	}
+	clearIteratorCheckpointReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ContentionStatsStub        func() broker.ContentionStats
+	contentionStatsMutex       sync.RWMutex
+	contentionStatsArgsForCall []struct {
+	}
+	contentionStatsReturns struct {
+		result1 broker.ContentionStats
+	}
+	contentionStatsReturnsOnCall map[int]struct {
+		result1 broker.ContentionStats
+	}
+	CountInstancesOfPlansStub        func(context.Context, *log.Logger) (map[cf.ServicePlan]int, error)
 	countInstancesOfPlansMutex       sync.RWMutex
 	countInstancesOfPlansArgsForCall []struct {
-		arg1 *log.Logger
+		arg1 context.Context
+		arg2 *log.Logger
 	}
 	countInstancesOfPlansReturns struct {
 		result1 map[cf.ServicePlan]int
@@ -27,11 +67,26 @@ type FakeManageableBroker struct {
 		result1 map[cf.ServicePlan]int
 		result2 error
 	}
-	InstancesStub        func(map[string]string, *log.Logger) ([]service.Instance, error)
+	CountInstancesOfPlansByOrgAndSpaceStub        func(context.Context, *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)
+	countInstancesOfPlansByOrgAndSpaceMutex       sync.RWMutex
+	countInstancesOfPlansByOrgAndSpaceArgsForCall []struct {
+		arg1 context.Context
+		arg2 *log.Logger
+	}
+	countInstancesOfPlansByOrgAndSpaceReturns struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}
+	countInstancesOfPlansByOrgAndSpaceReturnsOnCall map[int]struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}
+	InstancesStub        func(context.Context, map[string]string, *log.Logger) ([]service.Instance, error)
 	instancesMutex       sync.RWMutex
 	instancesArgsForCall []struct {
-		arg1 map[string]string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 map[string]string
+		arg3 *log.Logger
 	}
 	instancesReturns struct {
 		result1 []service.Instance
@@ -41,10 +96,43 @@ type FakeManageableBroker struct {
 		result1 []service.Instance
 		result2 error
 	}
-	OrphanDeploymentsStub        func(*log.Logger) ([]string, error)
+	IteratorCheckpointStub        func(context.Context, string, *log.Logger) ([]byte, bool, error)
+	iteratorCheckpointMutex       sync.RWMutex
+	iteratorCheckpointArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}
+	iteratorCheckpointReturns struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	iteratorCheckpointReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	OperationHistoryStub        func(context.Context, string, *log.Logger) ([]broker.OperationHistoryEntry, error)
+	operationHistoryMutex       sync.RWMutex
+	operationHistoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}
+	operationHistoryReturns struct {
+		result1 []broker.OperationHistoryEntry
+		result2 error
+	}
+	operationHistoryReturnsOnCall map[int]struct {
+		result1 []broker.OperationHistoryEntry
+		result2 error
+	}
+	OrphanDeploymentsStub        func(context.Context, *log.Logger) ([]string, error)
 	orphanDeploymentsMutex       sync.RWMutex
 	orphanDeploymentsArgsForCall []struct {
-		arg1 *log.Logger
+		arg1 context.Context
+		arg2 *log.Logger
 	}
 	orphanDeploymentsReturns struct {
 		result1 []string
@@ -54,6 +142,38 @@ type FakeManageableBroker struct {
 		result1 []string
 		result2 error
 	}
+	PreviewUpdateStub        func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.ManifestDiff, error)
+	previewUpdateMutex       sync.RWMutex
+	previewUpdateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 *log.Logger
+	}
+	previewUpdateReturns struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
+	previewUpdateReturnsOnCall map[int]struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
+	PreviewUpgradeStub        func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.ManifestDiff, error)
+	previewUpgradeMutex       sync.RWMutex
+	previewUpgradeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 *log.Logger
+	}
+	previewUpgradeReturns struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
+	previewUpgradeReturnsOnCall map[int]struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}
 	RecreateStub        func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.OperationData, error)
 	recreateMutex       sync.RWMutex
 	recreateArgsForCall []struct {
@@ -70,6 +190,53 @@ type FakeManageableBroker struct {
 		result1 broker.OperationData
 		result2 error
 	}
+	RollbackStub        func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.OperationData, error)
+	rollbackMutex       sync.RWMutex
+	rollbackArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 *log.Logger
+	}
+	rollbackReturns struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	rollbackReturnsOnCall map[int]struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	RotateSecretsStub        func(context.Context, string, domain.UpdateDetails, []string, *log.Logger) (broker.OperationData, error)
+	rotateSecretsMutex       sync.RWMutex
+	rotateSecretsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 []string
+		arg5 *log.Logger
+	}
+	rotateSecretsReturns struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	rotateSecretsReturnsOnCall map[int]struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	SaveIteratorCheckpointStub        func(context.Context, string, []byte, *log.Logger) error
+	saveIteratorCheckpointMutex       sync.RWMutex
+	saveIteratorCheckpointArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []byte
+		arg4 *log.Logger
+	}
+	saveIteratorCheckpointReturns struct {
+		result1 error
+	}
+	saveIteratorCheckpointReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpgradeStub        func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.OperationData, string, map[string]any, error)
 	upgradeMutex       sync.RWMutex
 	upgradeArgsForCall []struct {
@@ -94,18 +261,207 @@ type FakeManageableBroker struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlans(arg1 *log.Logger) (map[cf.ServicePlan]int, error) {
+func (fake *FakeManageableBroker) CleanupOrphanDeployments(arg1 context.Context, arg2 []string, arg3 broker.OrphanCleanupOptions, arg4 *log.Logger) (broker.OrphanCleanupReport, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.cleanupOrphanDeploymentsMutex.Lock()
+	ret, specificReturn := fake.cleanupOrphanDeploymentsReturnsOnCall[len(fake.cleanupOrphanDeploymentsArgsForCall)]
+	fake.cleanupOrphanDeploymentsArgsForCall = append(fake.cleanupOrphanDeploymentsArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 broker.OrphanCleanupOptions
+		arg4 *log.Logger
+	}{arg1, arg2Copy, arg3, arg4})
+	stub := fake.CleanupOrphanDeploymentsStub
+	fakeReturns := fake.cleanupOrphanDeploymentsReturns
+	fake.recordInvocation("CleanupOrphanDeployments", []interface{}{arg1, arg2Copy, arg3, arg4})
+	fake.cleanupOrphanDeploymentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) CleanupOrphanDeploymentsCallCount() int {
+	fake.cleanupOrphanDeploymentsMutex.RLock()
+	defer fake.cleanupOrphanDeploymentsMutex.RUnlock()
+	return len(fake.cleanupOrphanDeploymentsArgsForCall)
+}
+
+func (fake *FakeManageableBroker) CleanupOrphanDeploymentsCalls(stub func(context.Context, []string, broker.OrphanCleanupOptions, *log.Logger) (broker.OrphanCleanupReport, error)) {
+	fake.cleanupOrphanDeploymentsMutex.Lock()
+	defer fake.cleanupOrphanDeploymentsMutex.Unlock()
+	fake.CleanupOrphanDeploymentsStub = stub
+}
+
+func (fake *FakeManageableBroker) CleanupOrphanDeploymentsArgsForCall(i int) (context.Context, []string, broker.OrphanCleanupOptions, *log.Logger) {
+	fake.cleanupOrphanDeploymentsMutex.RLock()
+	defer fake.cleanupOrphanDeploymentsMutex.RUnlock()
+	argsForCall := fake.cleanupOrphanDeploymentsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeManageableBroker) CleanupOrphanDeploymentsReturns(result1 broker.OrphanCleanupReport, result2 error) {
+	fake.cleanupOrphanDeploymentsMutex.Lock()
+	defer fake.cleanupOrphanDeploymentsMutex.Unlock()
+	fake.CleanupOrphanDeploymentsStub = nil
+	fake.cleanupOrphanDeploymentsReturns = struct {
+		result1 broker.OrphanCleanupReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) CleanupOrphanDeploymentsReturnsOnCall(i int, result1 broker.OrphanCleanupReport, result2 error) {
+	fake.cleanupOrphanDeploymentsMutex.Lock()
+	defer fake.cleanupOrphanDeploymentsMutex.Unlock()
+	fake.CleanupOrphanDeploymentsStub = nil
+	if fake.cleanupOrphanDeploymentsReturnsOnCall == nil {
+		fake.cleanupOrphanDeploymentsReturnsOnCall = make(map[int]struct {
+			result1 broker.OrphanCleanupReport
+			result2 error
+		})
+	}
+	fake.cleanupOrphanDeploymentsReturnsOnCall[i] = struct {
+		result1 broker.OrphanCleanupReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) ClearIteratorCheckpoint(arg1 context.Context, arg2 string, arg3 *log.Logger) error {
+	fake.clearIteratorCheckpointMutex.Lock()
+	ret, specificReturn := fake.clearIteratorCheckpointReturnsOnCall[len(fake.clearIteratorCheckpointArgsForCall)]
+	fake.clearIteratorCheckpointArgsForCall = append(fake.clearIteratorCheckpointArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.ClearIteratorCheckpointStub
+	fakeReturns := fake.clearIteratorCheckpointReturns
+	fake.recordInvocation("ClearIteratorCheckpoint", []interface{}{arg1, arg2, arg3})
+	fake.clearIteratorCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeManageableBroker) ClearIteratorCheckpointCallCount() int {
+	fake.clearIteratorCheckpointMutex.RLock()
+	defer fake.clearIteratorCheckpointMutex.RUnlock()
+	return len(fake.clearIteratorCheckpointArgsForCall)
+}
+
+func (fake *FakeManageableBroker) ClearIteratorCheckpointCalls(stub func(context.Context, string, *log.Logger) error) {
+	fake.clearIteratorCheckpointMutex.Lock()
+	defer fake.clearIteratorCheckpointMutex.Unlock()
+	fake.ClearIteratorCheckpointStub = stub
+}
+
+func (fake *FakeManageableBroker) ClearIteratorCheckpointArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.clearIteratorCheckpointMutex.RLock()
+	defer fake.clearIteratorCheckpointMutex.RUnlock()
+	argsForCall := fake.clearIteratorCheckpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeManageableBroker) ClearIteratorCheckpointReturns(result1 error) {
+	fake.clearIteratorCheckpointMutex.Lock()
+	defer fake.clearIteratorCheckpointMutex.Unlock()
+	fake.ClearIteratorCheckpointStub = nil
+	fake.clearIteratorCheckpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) ClearIteratorCheckpointReturnsOnCall(i int, result1 error) {
+	fake.clearIteratorCheckpointMutex.Lock()
+	defer fake.clearIteratorCheckpointMutex.Unlock()
+	fake.ClearIteratorCheckpointStub = nil
+	if fake.clearIteratorCheckpointReturnsOnCall == nil {
+		fake.clearIteratorCheckpointReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.clearIteratorCheckpointReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) ContentionStats() broker.ContentionStats {
+	fake.contentionStatsMutex.Lock()
+	ret, specificReturn := fake.contentionStatsReturnsOnCall[len(fake.contentionStatsArgsForCall)]
+	fake.contentionStatsArgsForCall = append(fake.contentionStatsArgsForCall, struct {
+	}{})
+	stub := fake.ContentionStatsStub
+	fakeReturns := fake.contentionStatsReturns
+	fake.recordInvocation("ContentionStats", []interface{}{})
+	fake.contentionStatsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeManageableBroker) ContentionStatsCallCount() int {
+	fake.contentionStatsMutex.RLock()
+	defer fake.contentionStatsMutex.RUnlock()
+	return len(fake.contentionStatsArgsForCall)
+}
+
+func (fake *FakeManageableBroker) ContentionStatsCalls(stub func() broker.ContentionStats) {
+	fake.contentionStatsMutex.Lock()
+	defer fake.contentionStatsMutex.Unlock()
+	fake.ContentionStatsStub = stub
+}
+
+func (fake *FakeManageableBroker) ContentionStatsReturns(result1 broker.ContentionStats) {
+	fake.contentionStatsMutex.Lock()
+	defer fake.contentionStatsMutex.Unlock()
+	fake.ContentionStatsStub = nil
+	fake.contentionStatsReturns = struct {
+		result1 broker.ContentionStats
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) ContentionStatsReturnsOnCall(i int, result1 broker.ContentionStats) {
+	fake.contentionStatsMutex.Lock()
+	defer fake.contentionStatsMutex.Unlock()
+	fake.ContentionStatsStub = nil
+	if fake.contentionStatsReturnsOnCall == nil {
+		fake.contentionStatsReturnsOnCall = make(map[int]struct {
+			result1 broker.ContentionStats
+		})
+	}
+	fake.contentionStatsReturnsOnCall[i] = struct {
+		result1 broker.ContentionStats
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) CountInstancesOfPlans(arg1 context.Context, arg2 *log.Logger) (map[cf.ServicePlan]int, error) {
 	fake.countInstancesOfPlansMutex.Lock()
 	ret, specificReturn := fake.countInstancesOfPlansReturnsOnCall[len(fake.countInstancesOfPlansArgsForCall)]
 	fake.countInstancesOfPlansArgsForCall = append(fake.countInstancesOfPlansArgsForCall, struct {
-		arg1 *log.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 *log.Logger
+	}{arg1, arg2})
 	stub := fake.CountInstancesOfPlansStub
 	fakeReturns := fake.countInstancesOfPlansReturns
-	fake.recordInvocation("CountInstancesOfPlans", []interface{}{arg1})
+	fake.recordInvocation("CountInstancesOfPlans", []interface{}{arg1, arg2})
 	fake.countInstancesOfPlansMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -119,17 +475,17 @@ func (fake *FakeManageableBroker) CountInstancesOfPlansCallCount() int {
 	return len(fake.countInstancesOfPlansArgsForCall)
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlansCalls(stub func(*log.Logger) (map[cf.ServicePlan]int, error)) {
+func (fake *FakeManageableBroker) CountInstancesOfPlansCalls(stub func(context.Context, *log.Logger) (map[cf.ServicePlan]int, error)) {
 	fake.countInstancesOfPlansMutex.Lock()
 	defer fake.countInstancesOfPlansMutex.Unlock()
 	fake.CountInstancesOfPlansStub = stub
 }
 
-func (fake *FakeManageableBroker) CountInstancesOfPlansArgsForCall(i int) *log.Logger {
+func (fake *FakeManageableBroker) CountInstancesOfPlansArgsForCall(i int) (context.Context, *log.Logger) {
 	fake.countInstancesOfPlansMutex.RLock()
 	defer fake.countInstancesOfPlansMutex.RUnlock()
 	argsForCall := fake.countInstancesOfPlansArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeManageableBroker) CountInstancesOfPlansReturns(result1 map[cf.ServicePlan]int, result2 error) {
@@ -158,19 +514,85 @@ func (fake *FakeManageableBroker) CountInstancesOfPlansReturnsOnCall(i int, resu
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) Instances(arg1 map[string]string, arg2 *log.Logger) ([]service.Instance, error) {
+func (fake *FakeManageableBroker) CountInstancesOfPlansByOrgAndSpace(arg1 context.Context, arg2 *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.Lock()
+	ret, specificReturn := fake.countInstancesOfPlansByOrgAndSpaceReturnsOnCall[len(fake.countInstancesOfPlansByOrgAndSpaceArgsForCall)]
+	fake.countInstancesOfPlansByOrgAndSpaceArgsForCall = append(fake.countInstancesOfPlansByOrgAndSpaceArgsForCall, struct {
+		arg1 context.Context
+		arg2 *log.Logger
+	}{arg1, arg2})
+	stub := fake.CountInstancesOfPlansByOrgAndSpaceStub
+	fakeReturns := fake.countInstancesOfPlansByOrgAndSpaceReturns
+	fake.recordInvocation("CountInstancesOfPlansByOrgAndSpace", []interface{}{arg1, arg2})
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) CountInstancesOfPlansByOrgAndSpaceCallCount() int {
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfPlansByOrgAndSpaceMutex.RUnlock()
+	return len(fake.countInstancesOfPlansByOrgAndSpaceArgsForCall)
+}
+
+func (fake *FakeManageableBroker) CountInstancesOfPlansByOrgAndSpaceCalls(stub func(context.Context, *log.Logger) (cf.OrgAndSpaceInstanceCounts, error)) {
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfPlansByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfPlansByOrgAndSpaceStub = stub
+}
+
+func (fake *FakeManageableBroker) CountInstancesOfPlansByOrgAndSpaceArgsForCall(i int) (context.Context, *log.Logger) {
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfPlansByOrgAndSpaceMutex.RUnlock()
+	argsForCall := fake.countInstancesOfPlansByOrgAndSpaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeManageableBroker) CountInstancesOfPlansByOrgAndSpaceReturns(result1 cf.OrgAndSpaceInstanceCounts, result2 error) {
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfPlansByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfPlansByOrgAndSpaceStub = nil
+	fake.countInstancesOfPlansByOrgAndSpaceReturns = struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) CountInstancesOfPlansByOrgAndSpaceReturnsOnCall(i int, result1 cf.OrgAndSpaceInstanceCounts, result2 error) {
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.Lock()
+	defer fake.countInstancesOfPlansByOrgAndSpaceMutex.Unlock()
+	fake.CountInstancesOfPlansByOrgAndSpaceStub = nil
+	if fake.countInstancesOfPlansByOrgAndSpaceReturnsOnCall == nil {
+		fake.countInstancesOfPlansByOrgAndSpaceReturnsOnCall = make(map[int]struct {
+			result1 cf.OrgAndSpaceInstanceCounts
+			result2 error
+		})
+	}
+	fake.countInstancesOfPlansByOrgAndSpaceReturnsOnCall[i] = struct {
+		result1 cf.OrgAndSpaceInstanceCounts
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) Instances(arg1 context.Context, arg2 map[string]string, arg3 *log.Logger) ([]service.Instance, error) {
 	fake.instancesMutex.Lock()
 	ret, specificReturn := fake.instancesReturnsOnCall[len(fake.instancesArgsForCall)]
 	fake.instancesArgsForCall = append(fake.instancesArgsForCall, struct {
-		arg1 map[string]string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 map[string]string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.InstancesStub
 	fakeReturns := fake.instancesReturns
-	fake.recordInvocation("Instances", []interface{}{arg1, arg2})
+	fake.recordInvocation("Instances", []interface{}{arg1, arg2, arg3})
 	fake.instancesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -184,17 +606,17 @@ func (fake *FakeManageableBroker) InstancesCallCount() int {
 	return len(fake.instancesArgsForCall)
 }
 
-func (fake *FakeManageableBroker) InstancesCalls(stub func(map[string]string, *log.Logger) ([]service.Instance, error)) {
+func (fake *FakeManageableBroker) InstancesCalls(stub func(context.Context, map[string]string, *log.Logger) ([]service.Instance, error)) {
 	fake.instancesMutex.Lock()
 	defer fake.instancesMutex.Unlock()
 	fake.InstancesStub = stub
 }
 
-func (fake *FakeManageableBroker) InstancesArgsForCall(i int) (map[string]string, *log.Logger) {
+func (fake *FakeManageableBroker) InstancesArgsForCall(i int) (context.Context, map[string]string, *log.Logger) {
 	fake.instancesMutex.RLock()
 	defer fake.instancesMutex.RUnlock()
 	argsForCall := fake.instancesArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeManageableBroker) InstancesReturns(result1 []service.Instance, result2 error) {
@@ -223,18 +645,154 @@ func (fake *FakeManageableBroker) InstancesReturnsOnCall(i int, result1 []servic
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) OrphanDeployments(arg1 *log.Logger) ([]string, error) {
+func (fake *FakeManageableBroker) IteratorCheckpoint(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]byte, bool, error) {
+	fake.iteratorCheckpointMutex.Lock()
+	ret, specificReturn := fake.iteratorCheckpointReturnsOnCall[len(fake.iteratorCheckpointArgsForCall)]
+	fake.iteratorCheckpointArgsForCall = append(fake.iteratorCheckpointArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.IteratorCheckpointStub
+	fakeReturns := fake.iteratorCheckpointReturns
+	fake.recordInvocation("IteratorCheckpoint", []interface{}{arg1, arg2, arg3})
+	fake.iteratorCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeManageableBroker) IteratorCheckpointCallCount() int {
+	fake.iteratorCheckpointMutex.RLock()
+	defer fake.iteratorCheckpointMutex.RUnlock()
+	return len(fake.iteratorCheckpointArgsForCall)
+}
+
+func (fake *FakeManageableBroker) IteratorCheckpointCalls(stub func(context.Context, string, *log.Logger) ([]byte, bool, error)) {
+	fake.iteratorCheckpointMutex.Lock()
+	defer fake.iteratorCheckpointMutex.Unlock()
+	fake.IteratorCheckpointStub = stub
+}
+
+func (fake *FakeManageableBroker) IteratorCheckpointArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.iteratorCheckpointMutex.RLock()
+	defer fake.iteratorCheckpointMutex.RUnlock()
+	argsForCall := fake.iteratorCheckpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeManageableBroker) IteratorCheckpointReturns(result1 []byte, result2 bool, result3 error) {
+	fake.iteratorCheckpointMutex.Lock()
+	defer fake.iteratorCheckpointMutex.Unlock()
+	fake.IteratorCheckpointStub = nil
+	fake.iteratorCheckpointReturns = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeManageableBroker) IteratorCheckpointReturnsOnCall(i int, result1 []byte, result2 bool, result3 error) {
+	fake.iteratorCheckpointMutex.Lock()
+	defer fake.iteratorCheckpointMutex.Unlock()
+	fake.IteratorCheckpointStub = nil
+	if fake.iteratorCheckpointReturnsOnCall == nil {
+		fake.iteratorCheckpointReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 bool
+			result3 error
+		})
+	}
+	fake.iteratorCheckpointReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeManageableBroker) OperationHistory(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]broker.OperationHistoryEntry, error) {
+	fake.operationHistoryMutex.Lock()
+	ret, specificReturn := fake.operationHistoryReturnsOnCall[len(fake.operationHistoryArgsForCall)]
+	fake.operationHistoryArgsForCall = append(fake.operationHistoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
+	stub := fake.OperationHistoryStub
+	fakeReturns := fake.operationHistoryReturns
+	fake.recordInvocation("OperationHistory", []interface{}{arg1, arg2, arg3})
+	fake.operationHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) OperationHistoryCallCount() int {
+	fake.operationHistoryMutex.RLock()
+	defer fake.operationHistoryMutex.RUnlock()
+	return len(fake.operationHistoryArgsForCall)
+}
+
+func (fake *FakeManageableBroker) OperationHistoryCalls(stub func(context.Context, string, *log.Logger) ([]broker.OperationHistoryEntry, error)) {
+	fake.operationHistoryMutex.Lock()
+	defer fake.operationHistoryMutex.Unlock()
+	fake.OperationHistoryStub = stub
+}
+
+func (fake *FakeManageableBroker) OperationHistoryArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.operationHistoryMutex.RLock()
+	defer fake.operationHistoryMutex.RUnlock()
+	argsForCall := fake.operationHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeManageableBroker) OperationHistoryReturns(result1 []broker.OperationHistoryEntry, result2 error) {
+	fake.operationHistoryMutex.Lock()
+	defer fake.operationHistoryMutex.Unlock()
+	fake.OperationHistoryStub = nil
+	fake.operationHistoryReturns = struct {
+		result1 []broker.OperationHistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) OperationHistoryReturnsOnCall(i int, result1 []broker.OperationHistoryEntry, result2 error) {
+	fake.operationHistoryMutex.Lock()
+	defer fake.operationHistoryMutex.Unlock()
+	fake.OperationHistoryStub = nil
+	if fake.operationHistoryReturnsOnCall == nil {
+		fake.operationHistoryReturnsOnCall = make(map[int]struct {
+			result1 []broker.OperationHistoryEntry
+			result2 error
+		})
+	}
+	fake.operationHistoryReturnsOnCall[i] = struct {
+		result1 []broker.OperationHistoryEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) OrphanDeployments(arg1 context.Context, arg2 *log.Logger) ([]string, error) {
 	fake.orphanDeploymentsMutex.Lock()
 	ret, specificReturn := fake.orphanDeploymentsReturnsOnCall[len(fake.orphanDeploymentsArgsForCall)]
 	fake.orphanDeploymentsArgsForCall = append(fake.orphanDeploymentsArgsForCall, struct {
-		arg1 *log.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 *log.Logger
+	}{arg1, arg2})
 	stub := fake.OrphanDeploymentsStub
 	fakeReturns := fake.orphanDeploymentsReturns
-	fake.recordInvocation("OrphanDeployments", []interface{}{arg1})
+	fake.recordInvocation("OrphanDeployments", []interface{}{arg1, arg2})
 	fake.orphanDeploymentsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -248,17 +806,17 @@ func (fake *FakeManageableBroker) OrphanDeploymentsCallCount() int {
 	return len(fake.orphanDeploymentsArgsForCall)
 }
 
-func (fake *FakeManageableBroker) OrphanDeploymentsCalls(stub func(*log.Logger) ([]string, error)) {
+func (fake *FakeManageableBroker) OrphanDeploymentsCalls(stub func(context.Context, *log.Logger) ([]string, error)) {
 	fake.orphanDeploymentsMutex.Lock()
 	defer fake.orphanDeploymentsMutex.Unlock()
 	fake.OrphanDeploymentsStub = stub
 }
 
-func (fake *FakeManageableBroker) OrphanDeploymentsArgsForCall(i int) *log.Logger {
+func (fake *FakeManageableBroker) OrphanDeploymentsArgsForCall(i int) (context.Context, *log.Logger) {
 	fake.orphanDeploymentsMutex.RLock()
 	defer fake.orphanDeploymentsMutex.RUnlock()
 	argsForCall := fake.orphanDeploymentsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeManageableBroker) OrphanDeploymentsReturns(result1 []string, result2 error) {
@@ -287,6 +845,140 @@ func (fake *FakeManageableBroker) OrphanDeploymentsReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeManageableBroker) PreviewUpdate(arg1 context.Context, arg2 string, arg3 domain.UpdateDetails, arg4 *log.Logger) (broker.ManifestDiff, error) {
+	fake.previewUpdateMutex.Lock()
+	ret, specificReturn := fake.previewUpdateReturnsOnCall[len(fake.previewUpdateArgsForCall)]
+	fake.previewUpdateArgsForCall = append(fake.previewUpdateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PreviewUpdateStub
+	fakeReturns := fake.previewUpdateReturns
+	fake.recordInvocation("PreviewUpdate", []interface{}{arg1, arg2, arg3, arg4})
+	fake.previewUpdateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) PreviewUpdateCallCount() int {
+	fake.previewUpdateMutex.RLock()
+	defer fake.previewUpdateMutex.RUnlock()
+	return len(fake.previewUpdateArgsForCall)
+}
+
+func (fake *FakeManageableBroker) PreviewUpdateCalls(stub func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.ManifestDiff, error)) {
+	fake.previewUpdateMutex.Lock()
+	defer fake.previewUpdateMutex.Unlock()
+	fake.PreviewUpdateStub = stub
+}
+
+func (fake *FakeManageableBroker) PreviewUpdateArgsForCall(i int) (context.Context, string, domain.UpdateDetails, *log.Logger) {
+	fake.previewUpdateMutex.RLock()
+	defer fake.previewUpdateMutex.RUnlock()
+	argsForCall := fake.previewUpdateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeManageableBroker) PreviewUpdateReturns(result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpdateMutex.Lock()
+	defer fake.previewUpdateMutex.Unlock()
+	fake.PreviewUpdateStub = nil
+	fake.previewUpdateReturns = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) PreviewUpdateReturnsOnCall(i int, result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpdateMutex.Lock()
+	defer fake.previewUpdateMutex.Unlock()
+	fake.PreviewUpdateStub = nil
+	if fake.previewUpdateReturnsOnCall == nil {
+		fake.previewUpdateReturnsOnCall = make(map[int]struct {
+			result1 broker.ManifestDiff
+			result2 error
+		})
+	}
+	fake.previewUpdateReturnsOnCall[i] = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) PreviewUpgrade(arg1 context.Context, arg2 string, arg3 domain.UpdateDetails, arg4 *log.Logger) (broker.ManifestDiff, error) {
+	fake.previewUpgradeMutex.Lock()
+	ret, specificReturn := fake.previewUpgradeReturnsOnCall[len(fake.previewUpgradeArgsForCall)]
+	fake.previewUpgradeArgsForCall = append(fake.previewUpgradeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PreviewUpgradeStub
+	fakeReturns := fake.previewUpgradeReturns
+	fake.recordInvocation("PreviewUpgrade", []interface{}{arg1, arg2, arg3, arg4})
+	fake.previewUpgradeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) PreviewUpgradeCallCount() int {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	return len(fake.previewUpgradeArgsForCall)
+}
+
+func (fake *FakeManageableBroker) PreviewUpgradeCalls(stub func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.ManifestDiff, error)) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = stub
+}
+
+func (fake *FakeManageableBroker) PreviewUpgradeArgsForCall(i int) (context.Context, string, domain.UpdateDetails, *log.Logger) {
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
+	argsForCall := fake.previewUpgradeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeManageableBroker) PreviewUpgradeReturns(result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	fake.previewUpgradeReturns = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) PreviewUpgradeReturnsOnCall(i int, result1 broker.ManifestDiff, result2 error) {
+	fake.previewUpgradeMutex.Lock()
+	defer fake.previewUpgradeMutex.Unlock()
+	fake.PreviewUpgradeStub = nil
+	if fake.previewUpgradeReturnsOnCall == nil {
+		fake.previewUpgradeReturnsOnCall = make(map[int]struct {
+			result1 broker.ManifestDiff
+			result2 error
+		})
+	}
+	fake.previewUpgradeReturnsOnCall[i] = struct {
+		result1 broker.ManifestDiff
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeManageableBroker) Recreate(arg1 context.Context, arg2 string, arg3 domain.UpdateDetails, arg4 *log.Logger) (broker.OperationData, error) {
 	fake.recreateMutex.Lock()
 	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
@@ -354,6 +1046,215 @@ func (fake *FakeManageableBroker) RecreateReturnsOnCall(i int, result1 broker.Op
 	}{result1, result2}
 }
 
+func (fake *FakeManageableBroker) Rollback(arg1 context.Context, arg2 string, arg3 domain.UpdateDetails, arg4 *log.Logger) (broker.OperationData, error) {
+	fake.rollbackMutex.Lock()
+	ret, specificReturn := fake.rollbackReturnsOnCall[len(fake.rollbackArgsForCall)]
+	fake.rollbackArgsForCall = append(fake.rollbackArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RollbackStub
+	fakeReturns := fake.rollbackReturns
+	fake.recordInvocation("Rollback", []interface{}{arg1, arg2, arg3, arg4})
+	fake.rollbackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) RollbackCallCount() int {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return len(fake.rollbackArgsForCall)
+}
+
+func (fake *FakeManageableBroker) RollbackCalls(stub func(context.Context, string, domain.UpdateDetails, *log.Logger) (broker.OperationData, error)) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = stub
+}
+
+func (fake *FakeManageableBroker) RollbackArgsForCall(i int) (context.Context, string, domain.UpdateDetails, *log.Logger) {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	argsForCall := fake.rollbackArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeManageableBroker) RollbackReturns(result1 broker.OperationData, result2 error) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = nil
+	fake.rollbackReturns = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) RollbackReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.rollbackMutex.Lock()
+	defer fake.rollbackMutex.Unlock()
+	fake.RollbackStub = nil
+	if fake.rollbackReturnsOnCall == nil {
+		fake.rollbackReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
+			result2 error
+		})
+	}
+	fake.rollbackReturnsOnCall[i] = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) RotateSecrets(arg1 context.Context, arg2 string, arg3 domain.UpdateDetails, arg4 []string, arg5 *log.Logger) (broker.OperationData, error) {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.rotateSecretsMutex.Lock()
+	ret, specificReturn := fake.rotateSecretsReturnsOnCall[len(fake.rotateSecretsArgsForCall)]
+	fake.rotateSecretsArgsForCall = append(fake.rotateSecretsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 domain.UpdateDetails
+		arg4 []string
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5})
+	stub := fake.RotateSecretsStub
+	fakeReturns := fake.rotateSecretsReturns
+	fake.recordInvocation("RotateSecrets", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
+	fake.rotateSecretsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeManageableBroker) RotateSecretsCallCount() int {
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	return len(fake.rotateSecretsArgsForCall)
+}
+
+func (fake *FakeManageableBroker) RotateSecretsCalls(stub func(context.Context, string, domain.UpdateDetails, []string, *log.Logger) (broker.OperationData, error)) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = stub
+}
+
+func (fake *FakeManageableBroker) RotateSecretsArgsForCall(i int) (context.Context, string, domain.UpdateDetails, []string, *log.Logger) {
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	argsForCall := fake.rotateSecretsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeManageableBroker) RotateSecretsReturns(result1 broker.OperationData, result2 error) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = nil
+	fake.rotateSecretsReturns = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) RotateSecretsReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.rotateSecretsMutex.Lock()
+	defer fake.rotateSecretsMutex.Unlock()
+	fake.RotateSecretsStub = nil
+	if fake.rotateSecretsReturnsOnCall == nil {
+		fake.rotateSecretsReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
+			result2 error
+		})
+	}
+	fake.rotateSecretsReturnsOnCall[i] = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) SaveIteratorCheckpoint(arg1 context.Context, arg2 string, arg3 []byte, arg4 *log.Logger) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.saveIteratorCheckpointMutex.Lock()
+	ret, specificReturn := fake.saveIteratorCheckpointReturnsOnCall[len(fake.saveIteratorCheckpointArgsForCall)]
+	fake.saveIteratorCheckpointArgsForCall = append(fake.saveIteratorCheckpointArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []byte
+		arg4 *log.Logger
+	}{arg1, arg2, arg3Copy, arg4})
+	stub := fake.SaveIteratorCheckpointStub
+	fakeReturns := fake.saveIteratorCheckpointReturns
+	fake.recordInvocation("SaveIteratorCheckpoint", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.saveIteratorCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeManageableBroker) SaveIteratorCheckpointCallCount() int {
+	fake.saveIteratorCheckpointMutex.RLock()
+	defer fake.saveIteratorCheckpointMutex.RUnlock()
+	return len(fake.saveIteratorCheckpointArgsForCall)
+}
+
+func (fake *FakeManageableBroker) SaveIteratorCheckpointCalls(stub func(context.Context, string, []byte, *log.Logger) error) {
+	fake.saveIteratorCheckpointMutex.Lock()
+	defer fake.saveIteratorCheckpointMutex.Unlock()
+	fake.SaveIteratorCheckpointStub = stub
+}
+
+func (fake *FakeManageableBroker) SaveIteratorCheckpointArgsForCall(i int) (context.Context, string, []byte, *log.Logger) {
+	fake.saveIteratorCheckpointMutex.RLock()
+	defer fake.saveIteratorCheckpointMutex.RUnlock()
+	argsForCall := fake.saveIteratorCheckpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeManageableBroker) SaveIteratorCheckpointReturns(result1 error) {
+	fake.saveIteratorCheckpointMutex.Lock()
+	defer fake.saveIteratorCheckpointMutex.Unlock()
+	fake.SaveIteratorCheckpointStub = nil
+	fake.saveIteratorCheckpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) SaveIteratorCheckpointReturnsOnCall(i int, result1 error) {
+	fake.saveIteratorCheckpointMutex.Lock()
+	defer fake.saveIteratorCheckpointMutex.Unlock()
+	fake.SaveIteratorCheckpointStub = nil
+	if fake.saveIteratorCheckpointReturnsOnCall == nil {
+		fake.saveIteratorCheckpointReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveIteratorCheckpointReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeManageableBroker) Upgrade(arg1 context.Context, arg2 string, arg3 domain.UpdateDetails, arg4 *log.Logger) (broker.OperationData, string, map[string]any, error) {
 	fake.upgradeMutex.Lock()
 	ret, specificReturn := fake.upgradeReturnsOnCall[len(fake.upgradeArgsForCall)]
@@ -430,14 +1331,36 @@ func (fake *FakeManageableBroker) UpgradeReturnsOnCall(i int, result1 broker.Ope
 func (fake *FakeManageableBroker) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cleanupOrphanDeploymentsMutex.RLock()
+	defer fake.cleanupOrphanDeploymentsMutex.RUnlock()
+	fake.clearIteratorCheckpointMutex.RLock()
+	defer fake.clearIteratorCheckpointMutex.RUnlock()
+	fake.contentionStatsMutex.RLock()
+	defer fake.contentionStatsMutex.RUnlock()
 	fake.countInstancesOfPlansMutex.RLock()
 	defer fake.countInstancesOfPlansMutex.RUnlock()
+	fake.countInstancesOfPlansByOrgAndSpaceMutex.RLock()
+	defer fake.countInstancesOfPlansByOrgAndSpaceMutex.RUnlock()
 	fake.instancesMutex.RLock()
 	defer fake.instancesMutex.RUnlock()
+	fake.iteratorCheckpointMutex.RLock()
+	defer fake.iteratorCheckpointMutex.RUnlock()
+	fake.operationHistoryMutex.RLock()
+	defer fake.operationHistoryMutex.RUnlock()
 	fake.orphanDeploymentsMutex.RLock()
 	defer fake.orphanDeploymentsMutex.RUnlock()
+	fake.previewUpdateMutex.RLock()
+	defer fake.previewUpdateMutex.RUnlock()
+	fake.previewUpgradeMutex.RLock()
+	defer fake.previewUpgradeMutex.RUnlock()
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	fake.rotateSecretsMutex.RLock()
+	defer fake.rotateSecretsMutex.RUnlock()
+	fake.saveIteratorCheckpointMutex.RLock()
+	defer fake.saveIteratorCheckpointMutex.RUnlock()
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -1,6 +1,12 @@
 package mgmtapi
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+)
 
 type Metric struct {
 	Key   string  `json:"key"`
@@ -11,6 +17,7 @@ type Metric struct {
 type BrokerMetrics struct {
 	serviceOfferingName string
 	metrics             []Metric
+	samples             []metrics.Sample
 }
 
 func (m BrokerMetrics) AddPlanMetric(serviceOfferingPlanName, metricName string, value int) BrokerMetrics {
@@ -18,7 +25,7 @@ func (m BrokerMetrics) AddPlanMetric(serviceOfferingPlanName, metricName string,
 		Key:   fmt.Sprintf("/on-demand-broker/%s/%s/%s", m.serviceOfferingName, serviceOfferingPlanName, metricName),
 		Unit:  "count",
 		Value: float64(value),
-	})
+	}, prometheusSample("plan_", metricName, metrics.Labels{"offering": m.serviceOfferingName, "plan": serviceOfferingPlanName}, value))
 }
 
 func (m BrokerMetrics) AddGlobalMetric(metricName string, value int) BrokerMetrics {
@@ -26,12 +33,152 @@ func (m BrokerMetrics) AddGlobalMetric(metricName string, value int) BrokerMetri
 		Key:   fmt.Sprintf("/on-demand-broker/%s/%s", m.serviceOfferingName, metricName),
 		Unit:  "count",
 		Value: float64(value),
-	})
+	}, prometheusSample("", metricName, metrics.Labels{"offering": m.serviceOfferingName}, value))
+}
+
+func (m BrokerMetrics) AddOrgMetric(orgGUID, metricName string, value int) BrokerMetrics {
+	return m.addMetric(Metric{
+		Key:   fmt.Sprintf("/on-demand-broker/%s/orgs/%s/%s", m.serviceOfferingName, orgGUID, metricName),
+		Unit:  "count",
+		Value: float64(value),
+	}, prometheusSample("org_", metricName, metrics.Labels{"offering": m.serviceOfferingName, "org": orgGUID}, value))
+}
+
+func (m BrokerMetrics) AddSpaceMetric(spaceGUID, metricName string, value int) BrokerMetrics {
+	return m.addMetric(Metric{
+		Key:   fmt.Sprintf("/on-demand-broker/%s/spaces/%s/%s", m.serviceOfferingName, spaceGUID, metricName),
+		Unit:  "count",
+		Value: float64(value),
+	}, prometheusSample("space_", metricName, metrics.Labels{"offering": m.serviceOfferingName, "space": spaceGUID}, value))
 }
 
-func (m BrokerMetrics) addMetric(metric Metric) BrokerMetrics {
+func (m BrokerMetrics) addMetric(metric Metric, sample metrics.Sample) BrokerMetrics {
 	return BrokerMetrics{
 		serviceOfferingName: m.serviceOfferingName,
 		metrics:             append(m.metrics, metric),
+		samples:             append(m.samples, sample),
+	}
+}
+
+// prometheusSample maps a metric name such as total_instances or memory/used
+// to a Prometheus sample. Resource quota metrics carry the resource as a label
+// rather than in the name.
+func prometheusSample(scope, metricName string, labels metrics.Labels, value int) metrics.Sample {
+	name := metricName
+	if resource, stat, found := strings.Cut(metricName, "/"); found {
+		name = "resource_" + stat
+		labels["resource"] = resource
+	}
+	return metrics.Sample{
+		Name:   "on_demand_broker_" + scope + name,
+		Labels: labels,
+		Value:  float64(value),
+	}
+}
+
+const (
+	instanceCountsCollection       = "instance_counts"
+	scopedInstanceCountsCollection = "org_and_space_instance_counts"
+)
+
+// collectionSamples reports, for each collection attempted, whether it failed.
+func collectionSamples(failed map[string]bool) []metrics.Sample {
+	var samples []metrics.Sample
+	for _, collection := range []string{instanceCountsCollection, scopedInstanceCountsCollection} {
+		collectionFailed, attempted := failed[collection]
+		if !attempted {
+			continue
+		}
+		value := 0.0
+		if collectionFailed {
+			value = 1
+		}
+		samples = append(samples, metrics.Sample{
+			Name:   "on_demand_broker_metrics_collection_failed",
+			Labels: metrics.Labels{"collection": collection},
+			Value:  value,
+		})
+	}
+	return samples
+}
+
+type familyDescription struct {
+	help string
+	kind string
+}
+
+var familyDescriptions = map[string]familyDescription{
+	"on_demand_broker_plan_total_instances":      {"Service instances of the plan.", metrics.Gauge},
+	"on_demand_broker_plan_quota_remaining":      {"Service instances that can be created before the plan quota is reached.", metrics.Gauge},
+	"on_demand_broker_plan_resource_used":        {"Resources used by the service instances of the plan.", metrics.Gauge},
+	"on_demand_broker_plan_resource_remaining":   {"Resources left before the plan resource quota is reached.", metrics.Gauge},
+	"on_demand_broker_total_instances":           {"Service instances of the service offering.", metrics.Gauge},
+	"on_demand_broker_quota_remaining":           {"Service instances that can be created before the global quota is reached.", metrics.Gauge},
+	"on_demand_broker_resource_used":             {"Resources used by the service instances of the service offering.", metrics.Gauge},
+	"on_demand_broker_resource_remaining":        {"Resources left before the global resource quota is reached.", metrics.Gauge},
+	"on_demand_broker_org_quota_remaining":       {"Service instances that can be created in the org before its quota is reached.", metrics.Gauge},
+	"on_demand_broker_org_resource_remaining":    {"Resources left in the org before its resource quota is reached.", metrics.Gauge},
+	"on_demand_broker_space_quota_remaining":     {"Service instances that can be created in the space before its quota is reached.", metrics.Gauge},
+	"on_demand_broker_space_resource_remaining":  {"Resources left in the space before its resource quota is reached.", metrics.Gauge},
+	"on_demand_broker_metrics_collection_failed": {"Whether the metrics that depend on the collection were left out, as it failed.", metrics.Gauge},
+	"on_demand_broker_lock_acquisitions_total":   {"Acquisitions of the broker locks.", metrics.Counter},
+	"on_demand_broker_lock_contended_total":      {"Acquisitions of the broker locks that had to wait.", metrics.Counter},
+	"on_demand_broker_lock_waiting":              {"Operations currently waiting for a broker lock.", metrics.Gauge},
+	"on_demand_broker_lock_wait_seconds_total":   {"Time spent waiting for the broker locks.", metrics.Counter},
+}
+
+// groupSamples groups the samples into families, in the order in which each
+// family is first seen.
+func groupSamples(samples []metrics.Sample) []metrics.Family {
+	var families []metrics.Family
+	indexes := map[string]int{}
+	for _, sample := range samples {
+		i, found := indexes[sample.Name]
+		if !found {
+			description := familyDescriptions[sample.Name]
+			i = len(families)
+			indexes[sample.Name] = i
+			families = append(families, metrics.Family{Name: sample.Name, Help: description.help, Type: description.kind})
+		}
+		families[i].Samples = append(families[i].Samples, sample)
+	}
+	return families
+}
+
+func contentionMetrics(stats broker.ContentionStats) []Metric {
+	var metrics []Metric
+	for _, lock := range []struct {
+		name       string
+		contention broker.Contention
+	}{
+		{"instance_locks", stats.InstanceLocks},
+		{"adapter_calls", stats.AdapterCalls},
+	} {
+		metrics = append(metrics,
+			Metric{Key: fmt.Sprintf("/on-demand-broker/%s/acquisitions", lock.name), Unit: "count", Value: float64(lock.contention.Acquisitions)},
+			Metric{Key: fmt.Sprintf("/on-demand-broker/%s/contended", lock.name), Unit: "count", Value: float64(lock.contention.Contended)},
+			Metric{Key: fmt.Sprintf("/on-demand-broker/%s/waiting", lock.name), Unit: "count", Value: float64(lock.contention.Waiting)},
+			Metric{Key: fmt.Sprintf("/on-demand-broker/%s/wait_time", lock.name), Unit: "ms", Value: float64(lock.contention.WaitTime.Milliseconds())},
+		)
+	}
+	return metrics
+}
+
+func contentionSamples(stats broker.ContentionStats) []metrics.Sample {
+	var samples []metrics.Sample
+	for _, lock := range []struct {
+		name       string
+		contention broker.Contention
+	}{
+		{"instance_locks", stats.InstanceLocks},
+		{"adapter_calls", stats.AdapterCalls},
+	} {
+		samples = append(samples,
+			metrics.Sample{Name: "on_demand_broker_lock_acquisitions_total", Labels: metrics.Labels{"lock": lock.name}, Value: float64(lock.contention.Acquisitions)},
+			metrics.Sample{Name: "on_demand_broker_lock_contended_total", Labels: metrics.Labels{"lock": lock.name}, Value: float64(lock.contention.Contended)},
+			metrics.Sample{Name: "on_demand_broker_lock_waiting", Labels: metrics.Labels{"lock": lock.name}, Value: float64(lock.contention.Waiting)},
+			metrics.Sample{Name: "on_demand_broker_lock_wait_seconds_total", Labels: metrics.Labels{"lock": lock.name}, Value: lock.contention.WaitTime.Seconds()},
+		)
 	}
+	return samples
 }
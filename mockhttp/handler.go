@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
@@ -164,6 +165,15 @@ func NewMockedHttpRequest(method, url string) *Handler {
 	}
 }
 
+// matches is true when the request has the method and URL of the mock.
+func (i *Handler) matches(req *http.Request) bool {
+	request := req.Method + " " + req.URL.String()
+	if i.matchURLWithRegex {
+		return regexp.MustCompile(i.expectedMethod + " " + i.expectedUrl).MatchString(request)
+	}
+	return request == i.expectedMethod+" "+i.expectedUrl
+}
+
 func (i *Handler) Verify(req *http.Request, s *Server) {
 	if i.matchURLWithRegex == true {
 		Expect(req.Method+" "+req.URL.String()).To(MatchRegexp(i.expectedMethod+" "+i.expectedUrl), unexpectedRequestDescription(req, s))
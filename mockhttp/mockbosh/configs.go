@@ -0,0 +1,41 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package mockbosh
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+)
+
+type updateConfigMock struct {
+	*mockhttp.Handler
+}
+
+func UpdateConfig() *updateConfigMock {
+	return &updateConfigMock{
+		Handler: mockhttp.NewMockedHttpRequest("POST", "/configs"),
+	}
+}
+
+func (m *updateConfigMock) RespondsOKForConfig(configType, configName string) *mockhttp.Handler {
+	return m.RespondsOKWith(fmt.Sprintf(`{"id":"1","type":"%s","name":"%s","content":""}`, configType, configName))
+}
+
+type listConfigsMock struct {
+	*mockhttp.Handler
+}
+
+func ListConfigsOfType(configType string) *listConfigsMock {
+	return &listConfigsMock{
+		Handler: mockhttp.NewMockedHttpRequest("GET", fmt.Sprintf("/configs?latest=true&limit=1&type=%s", configType)),
+	}
+}
+
+func (m *listConfigsMock) RespondsWithNoConfigs() *mockhttp.Handler {
+	return m.RespondsOKWith(`[]`)
+}
@@ -80,11 +80,20 @@ func ListServiceInstances(servicePlanGUID string) *listServiceInstancesMock {
 	}
 }
 
+func ListServiceInstancesWithSpaces(servicePlanGUID string) *listServiceInstancesMock {
+	return &listServiceInstancesMock{
+		mockhttp.NewMockedHttpRequest(
+			"GET",
+			"/v2/service_plans/"+servicePlanGUID+"/service_instances?results-per-page=100&inline-relations-depth=1&include-relations=space",
+		),
+	}
+}
+
 func ListServiceInstancesBySpace(servicePlanGUID, spaceGUID string) *listServiceInstancesMock {
 	return &listServiceInstancesMock{
 		mockhttp.NewMockedHttpRequest(
 			"GET",
-			"/v2/service_plans/"+servicePlanGUID+"/service_instances?results-per-page=100&q=space_guid:"+spaceGUID,
+			"/v2/service_plans/"+servicePlanGUID+"/service_instances?results-per-page=100&inline-relations-depth=1&include-relations=space&q=space_guid:"+spaceGUID,
 		),
 	}
 }
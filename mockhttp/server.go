@@ -32,6 +32,11 @@ type Server struct {
 	mockHandlers   []MockedResponseBuilder
 	currentHandler int
 
+	// unorderedHandlers are matched before the ordered mocks, for requests
+	// sent concurrently with them, such as the polling of a BOSH task
+	unorderedHandlers []*Handler
+	unorderedUsed     []bool
+
 	logger *log.Logger
 }
 
@@ -93,6 +98,16 @@ func (s *Server) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
 	defer s.Unlock()
 	defer GinkgoRecover()
 
+	for i, handler := range s.unorderedHandlers {
+		if !s.unorderedUsed[i] && handler.matches(req) {
+			s.logger.Printf("%s %s\n", req.Method, req.URL.String())
+			s.unorderedUsed[i] = true
+			handler.Verify(req, s)
+			handler.Respond(writer, s.logger)
+			return
+		}
+	}
+
 	if s.currentHandler >= len(s.mockHandlers) {
 		received := req.Method + " " + req.URL.String()
 		completedMocks := strings.Join(s.completedMocks(), "\n")
@@ -112,6 +127,11 @@ func (s *Server) completedMocks() []string {
 	for i := 0; i < s.currentHandler; i++ {
 		completedMocks = append(completedMocks, "\t"+s.mockHandlers[i].Url())
 	}
+	for i, handler := range s.unorderedHandlers {
+		if s.unorderedUsed[i] {
+			completedMocks = append(completedMocks, "\t"+handler.Url()+" (in any order)")
+		}
+	}
 	return completedMocks
 }
 
@@ -120,6 +140,11 @@ func (s *Server) pendingMocks() []string {
 	for i := s.currentHandler; i < len(s.mockHandlers); i++ {
 		pendingMocks = append(pendingMocks, "\t"+s.mockHandlers[i].Url())
 	}
+	for i, handler := range s.unorderedHandlers {
+		if !s.unorderedUsed[i] {
+			pendingMocks = append(pendingMocks, "\t"+handler.Url()+" (in any order)")
+		}
+	}
 	return pendingMocks
 }
 
@@ -130,6 +155,19 @@ func (s *Server) VerifyAndMock(mockedResponses ...MockedResponseBuilder) {
 
 	s.currentHandler = 0
 	s.mockHandlers = mockedResponses
+	s.unorderedHandlers = nil
+	s.unorderedUsed = nil
+}
+
+// MockInAnyOrder adds mocks for requests that may arrive at any point among
+// the ordered mocks. Mocks for the same request are used in the given order.
+// They must be added after VerifyAndMock.
+func (s *Server) MockInAnyOrder(mockedResponses ...*Handler) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.unorderedHandlers = append(s.unorderedHandlers, mockedResponses...)
+	s.unorderedUsed = append(s.unorderedUsed, make([]bool, len(mockedResponses))...)
 }
 
 func (s *Server) AppendMocks(mockedResponses ...MockedResponseBuilder) {
@@ -140,13 +178,22 @@ func (s *Server) AppendMocks(mockedResponses ...MockedResponseBuilder) {
 }
 
 func (s *Server) VerifyMocks() {
-	if len(s.mockHandlers) != s.currentHandler {
+	if len(s.mockHandlers) != s.currentHandler || s.hasUnusedUnorderedMocks() {
 		completedMocks := strings.Join(s.completedMocks(), "\n")
 		pendingMocks := strings.Join(s.pendingMocks(), "\n")
 		Fail(fmt.Sprintf("Uninvoked mocks for:\n\t%s\nCompleted:\n%s\nPending:\n%s\n", s.name, completedMocks, pendingMocks))
 	}
 }
 
+func (s *Server) hasUnusedUnorderedMocks() bool {
+	for _, used := range s.unorderedUsed {
+		if !used {
+			return true
+		}
+	}
+	return false
+}
+
 type MockedResponseBuilder interface {
 	Verify(req *http.Request, d *Server)
 	Respond(writer http.ResponseWriter, logger *log.Logger)
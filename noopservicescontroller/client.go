@@ -7,6 +7,7 @@
 package noopservicescontroller
 
 import (
+	"context"
 	"log"
 
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
@@ -23,10 +24,14 @@ func (Client) CountInstancesOfPlan(serviceOfferingID, planID string, logger *log
 	return 1, nil
 }
 
-func (Client) CountInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error) {
+func (Client) CountInstancesOfServiceOffering(ctx context.Context, serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error) {
 	return make(map[cf.ServicePlan]int), nil
 }
 
+func (Client) CountInstancesOfServiceOfferingByOrgAndSpace(ctx context.Context, serviceOfferingID string, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	return cf.OrgAndSpaceInstanceCounts{}, nil
+}
+
 func (Client) GetInstanceState(serviceInstanceGUID string, logger *log.Logger) (cf.InstanceState, error) {
 	return cf.InstanceState{}, nil
 }
@@ -16,6 +16,7 @@
 package noopservicescontroller_test
 
 import (
+	"context"
 	"io"
 	"log"
 
@@ -70,12 +71,21 @@ var _ = Describe("Client", func() {
 	Describe("CountInstancesOfServiceOffering", func() {
 		It("returns empty map", func() {
 			client := noopservicescontroller.New()
-			instanceCountByPlanID, err := client.CountInstancesOfServiceOffering("offeringId", testLogger)
+			instanceCountByPlanID, err := client.CountInstancesOfServiceOffering(context.Background(), "offeringId", testLogger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(instanceCountByPlanID).ToNot(BeNil())
 		})
 	})
 
+	Describe("CountInstancesOfServiceOfferingByOrgAndSpace", func() {
+		It("returns no counts", func() {
+			client := noopservicescontroller.New()
+			counts, err := client.CountInstancesOfServiceOfferingByOrgAndSpace(context.Background(), "offeringId", testLogger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(cf.OrgAndSpaceInstanceCounts{}))
+		})
+	})
+
 	Describe("GetInstanceState", func() {
 		It("return default state", func() {
 			client := noopservicescontroller.New()
@@ -0,0 +1,319 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+const (
+	EventTypePrefix = "io.pivotal.odb."
+	DefaultSource   = "/" + broker.ComponentName
+
+	// SignatureHeader holds the HMAC-SHA256 of the value of TimestampHeader,
+	// a dot and the request body, keyed with the secret of the webhook, as
+	// sha256=<hex digest>. TimestampHeader is when the request was sent, in
+	// seconds since the epoch, so that webhooks can reject replayed requests.
+	SignatureHeader = "X-ODB-Signature"
+	TimestampHeader = "X-ODB-Timestamp"
+
+	DefaultQueueSize     = 100
+	DefaultMaxRetries    = 3
+	DefaultRetryInterval = time.Second
+	DefaultTimeout       = 10 * time.Second
+)
+
+// CloudEvent is a lifecycle event in the structured mode of the CloudEvents
+// 1.0 JSON format.
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            EventData `json:"data"`
+}
+
+type EventData struct {
+	ServiceID   string `json:"service_id"`
+	PlanID      string `json:"plan_id,omitempty"`
+	InstanceID  string `json:"instance_id"`
+	BindingID   string `json:"binding_id,omitempty"`
+	Operation   string `json:"operation"`
+	State       string `json:"state"`
+	RequestID   string `json:"request_id,omitempty"`
+	BoshTaskID  int    `json:"bosh_task_id,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// EventType is the type of the event without EventTypePrefix, such as
+// instance.create.succeeded. Webhooks select the events they are sent by it.
+func EventType(event broker.LifecycleEvent) string {
+	return fmt.Sprintf("%s.%s.%s", event.Resource, event.Operation, event.State)
+}
+
+// NewCloudEvent converts a lifecycle event. The events of instance operations
+// are identified by their BOSH task, so that an event sent again, such as when
+// an operation is polled more than once, has the same ID.
+func NewCloudEvent(source string, event broker.LifecycleEvent) CloudEvent {
+	id := uuid.New()
+	if event.Resource == broker.LifecycleResourceInstance && event.BoshTaskID != 0 {
+		id = fmt.Sprintf("%s-%d-%s", event.InstanceID, event.BoshTaskID, event.State)
+	}
+	subject := event.InstanceID
+	if event.Resource == broker.LifecycleResourceBinding {
+		subject = event.BindingID
+	}
+
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              id,
+		Source:          source,
+		Type:            EventTypePrefix + EventType(event),
+		Subject:         subject,
+		Time:            event.Time,
+		DataContentType: "application/json",
+		Data: EventData{
+			ServiceID:   event.ServiceID,
+			PlanID:      event.PlanID,
+			InstanceID:  event.InstanceID,
+			BindingID:   event.BindingID,
+			Operation:   string(event.Operation),
+			State:       event.State,
+			RequestID:   event.RequestID,
+			BoshTaskID:  event.BoshTaskID,
+			Description: event.Description,
+		},
+	}
+}
+
+// Sign returns the value of SignatureHeader for a request body sent at the
+// timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewHTTPClient returns a client whose requests time out after the configured
+// timeout, or DefaultTimeout.
+func NewHTTPClient(conf config.Notifications) *http.Client {
+	timeout := DefaultTimeout
+	if conf.TimeoutSecs > 0 {
+		timeout = time.Duration(conf.TimeoutSecs) * time.Second
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// WebhookNotifier sends lifecycle events to the configured webhooks. Events
+// are queued, and sent in order by a single routine; when the queue is full,
+// new events are dropped rather than holding up the broker. Requests that fail
+// with a network error or a 429 or 5xx response are retried, with a backoff
+// that doubles each time.
+type WebhookNotifier struct {
+	webhooks      []config.Webhook
+	source        string
+	maxRetries    int
+	retryInterval time.Duration
+	client        *http.Client
+	logger        *log.Logger
+
+	mu      sync.Mutex
+	closed  bool
+	queue   chan broker.LifecycleEvent
+	done    chan struct{}
+	stopped chan struct{}
+
+	// ctx is cancelled when Shutdown times out, to abort the request in
+	// flight and drop the events still queued
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewWebhookNotifier(conf config.Notifications, client *http.Client, retryInterval time.Duration, logger *log.Logger) *WebhookNotifier {
+	source := conf.Source
+	if source == "" {
+		source = DefaultSource
+	}
+	queueSize := conf.QueueSize
+	if queueSize == 0 {
+		queueSize = DefaultQueueSize
+	}
+	maxRetries := DefaultMaxRetries
+	if conf.MaxRetries != nil {
+		maxRetries = *conf.MaxRetries
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &WebhookNotifier{
+		webhooks:      conf.Webhooks,
+		source:        source,
+		maxRetries:    maxRetries,
+		retryInterval: retryInterval,
+		client:        client,
+		logger:        logger,
+		queue:         make(chan broker.LifecycleEvent, queueSize),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	go n.run()
+	return n
+}
+
+func (n *WebhookNotifier) Notify(event broker.LifecycleEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return
+	}
+
+	select {
+	case n.queue <- event:
+	default:
+		n.logger.Printf("notification queue is full, dropping event %s for instance %s\n", EventType(event), event.InstanceID)
+	}
+}
+
+// Shutdown sends the events still queued, without retrying them, and stops
+// the notifier. The events that are not sent within the timeout are dropped.
+func (n *WebhookNotifier) Shutdown(timeout time.Duration) {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.done)
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-n.stopped:
+	case <-timer.C:
+		n.cancel()
+		<-n.stopped
+	}
+}
+
+func (n *WebhookNotifier) run() {
+	defer close(n.stopped)
+	defer n.cancel()
+
+	dropped := 0
+	for event := range n.queue {
+		if n.ctx.Err() != nil {
+			dropped++
+			continue
+		}
+		n.deliver(event)
+	}
+	if dropped > 0 {
+		loggerfactory.WithLevel(n.logger, loggerfactory.ErrorLevel).Printf("notifications shut down before %d queued events were sent, dropping them\n", dropped)
+	}
+}
+
+func (n *WebhookNotifier) deliver(event broker.LifecycleEvent) {
+	eventType := EventType(event)
+	body, err := json.Marshal(NewCloudEvent(n.source, event))
+	if err != nil {
+		loggerfactory.WithLevel(n.logger, loggerfactory.ErrorLevel).Printf("error encoding event %s for instance %s: %s\n", eventType, event.InstanceID, err)
+		return
+	}
+
+	for _, webhook := range n.webhooks {
+		if !subscribes(webhook, eventType) {
+			continue
+		}
+		if err := n.sendWithRetries(webhook, body); err != nil {
+			loggerfactory.WithLevel(n.logger, loggerfactory.ErrorLevel).Printf("error sending event %s for instance %s to %s: %s\n", eventType, event.InstanceID, webhook.URL, err)
+		}
+	}
+}
+
+func (n *WebhookNotifier) sendWithRetries(webhook config.Webhook, body []byte) error {
+	wait := n.retryInterval
+	for attempt := 0; ; attempt++ {
+		retryable, err := n.send(webhook, body)
+		if err == nil || !retryable || attempt >= n.maxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-n.done:
+			return err
+		}
+	}
+}
+
+func (n *WebhookNotifier) send(webhook config.Webhook, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json; charset=utf-8")
+	if webhook.Secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, body))
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		retryable := res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+		return retryable, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return false, nil
+}
+
+// subscribes is true when the webhook is sent events of the type: when it
+// lists no events, or lists the type or one of its prefixes, such as instance
+// or instance.create.
+func subscribes(webhook config.Webhook, eventType string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, event := range webhook.Events {
+		if eventType == event || strings.HasPrefix(eventType, event+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,345 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package notifications_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/notifications"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type webhookServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	requests  []receivedRequest
+	responses []int
+}
+
+func newWebhookServer(responses ...int) *webhookServer {
+	s := &webhookServer{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, receivedRequest{header: r.Header, body: body})
+		status := http.StatusOK
+		if len(s.responses) > 0 {
+			status, s.responses = s.responses[0], s.responses[1:]
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	return s
+}
+
+func (s *webhookServer) Requests() []receivedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedRequest{}, s.requests...)
+}
+
+var _ = Describe("Webhooks", func() {
+	var event broker.LifecycleEvent
+
+	BeforeEach(func() {
+		event = broker.LifecycleEvent{
+			Resource:   broker.LifecycleResourceInstance,
+			Operation:  broker.OperationTypeCreate,
+			State:      broker.LifecycleStateSucceeded,
+			ServiceID:  "service-id",
+			PlanID:     "plan-id",
+			InstanceID: "instance-id",
+			RequestID:  "request-id",
+			BoshTaskID: 42,
+			Time:       time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		}
+	})
+
+	Describe("NewCloudEvent", func() {
+		It("converts an instance event", func() {
+			Expect(notifications.NewCloudEvent("/my-broker", event)).To(Equal(notifications.CloudEvent{
+				SpecVersion:     "1.0",
+				ID:              "instance-id-42-succeeded",
+				Source:          "/my-broker",
+				Type:            "io.pivotal.odb.instance.create.succeeded",
+				Subject:         "instance-id",
+				Time:            event.Time,
+				DataContentType: "application/json",
+				Data: notifications.EventData{
+					ServiceID:  "service-id",
+					PlanID:     "plan-id",
+					InstanceID: "instance-id",
+					Operation:  "create",
+					State:      "succeeded",
+					RequestID:  "request-id",
+					BoshTaskID: 42,
+				},
+			}))
+		})
+
+		It("converts a binding event, with a new ID each time", func() {
+			event.Resource = broker.LifecycleResourceBinding
+			event.Operation = broker.OperationTypeBind
+			event.BindingID = "binding-id"
+			event.BoshTaskID = 0
+
+			cloudEvent := notifications.NewCloudEvent("/my-broker", event)
+
+			Expect(cloudEvent.Type).To(Equal("io.pivotal.odb.binding.bind.succeeded"))
+			Expect(cloudEvent.Subject).To(Equal("binding-id"))
+			Expect(cloudEvent.Data.BindingID).To(Equal("binding-id"))
+			Expect(cloudEvent.ID).NotTo(BeEmpty())
+			Expect(cloudEvent.ID).NotTo(Equal(notifications.NewCloudEvent("/my-broker", event).ID))
+		})
+	})
+
+	Describe("WebhookNotifier", func() {
+		var (
+			server   *webhookServer
+			notifier *notifications.WebhookNotifier
+			logs     *gbytes.Buffer
+			conf     config.Notifications
+		)
+
+		BeforeEach(func() {
+			logs = gbytes.NewBuffer()
+			conf = config.Notifications{}
+		})
+
+		JustBeforeEach(func() {
+			conf.Webhooks = append(conf.Webhooks, config.Webhook{URL: server.URL, Secret: "s3cr3t"})
+			notifier = notifications.NewWebhookNotifier(conf, server.Client(), time.Millisecond, log.New(logs, "", 0))
+		})
+
+		AfterEach(func() {
+			notifier.Shutdown(time.Second)
+			server.Close()
+		})
+
+		Context("when the webhook accepts the events", func() {
+			BeforeEach(func() {
+				server = newWebhookServer()
+			})
+
+			It("posts each event as a signed CloudEvent", func() {
+				notifier.Notify(event)
+
+				Eventually(server.Requests).Should(HaveLen(1))
+				request := server.Requests()[0]
+				Expect(request.header.Get("Content-Type")).To(Equal("application/cloudevents+json; charset=utf-8"))
+				timestamp, err := strconv.ParseInt(request.header.Get(notifications.TimestampHeader), 10, 64)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(time.Unix(timestamp, 0)).To(BeTemporally("~", time.Now(), time.Minute))
+				Expect(request.header.Get(notifications.SignatureHeader)).To(Equal(notifications.Sign("s3cr3t", timestamp, request.body)))
+				Expect(request.body).To(MatchJSON(`{
+					"specversion": "1.0",
+					"id": "instance-id-42-succeeded",
+					"source": "/on-demand-service-broker",
+					"type": "io.pivotal.odb.instance.create.succeeded",
+					"subject": "instance-id",
+					"time": "2026-03-01T12:00:00Z",
+					"datacontenttype": "application/json",
+					"data": {
+						"service_id": "service-id",
+						"plan_id": "plan-id",
+						"instance_id": "instance-id",
+						"operation": "create",
+						"state": "succeeded",
+						"request_id": "request-id",
+						"bosh_task_id": 42
+					}
+				}`))
+			})
+
+			It("signs the timestamp and the body with HMAC-SHA256", func() {
+				mac := hmac.New(sha256.New, []byte("key"))
+				mac.Write([]byte("1700000000.The quick brown fox jumps over the lazy dog"))
+
+				Expect(notifications.Sign("key", 1700000000, []byte("The quick brown fox jumps over the lazy dog"))).To(
+					Equal("sha256=" + hex.EncodeToString(mac.Sum(nil))),
+				)
+				Expect(notifications.Sign("key", 1700000001, []byte("The quick brown fox jumps over the lazy dog"))).NotTo(
+					Equal(notifications.Sign("key", 1700000000, []byte("The quick brown fox jumps over the lazy dog"))),
+				)
+			})
+
+			It("sends the events still queued on shutdown", func() {
+				notifier.Notify(event)
+				notifier.Notify(event)
+				notifier.Shutdown(time.Second)
+
+				Expect(server.Requests()).To(HaveLen(2))
+			})
+
+			It("does not send events after shutdown", func() {
+				notifier.Shutdown(time.Second)
+				notifier.Notify(event)
+
+				Consistently(server.Requests, 50*time.Millisecond).Should(BeEmpty())
+			})
+		})
+
+		Context("when webhooks only subscribe to some events", func() {
+			var bindingServer *webhookServer
+
+			BeforeEach(func() {
+				server = newWebhookServer()
+				bindingServer = newWebhookServer()
+				conf.Webhooks = []config.Webhook{{URL: bindingServer.URL, Events: []string{"binding"}}}
+			})
+
+			AfterEach(func() {
+				bindingServer.Close()
+			})
+
+			It("sends each webhook the events it subscribes to", func() {
+				notifier.Notify(event)
+				event.Resource = broker.LifecycleResourceBinding
+				notifier.Notify(event)
+				notifier.Shutdown(time.Second)
+
+				Expect(server.Requests()).To(HaveLen(2))
+				Expect(bindingServer.Requests()).To(HaveLen(1))
+				Expect(bindingServer.Requests()[0].header.Get(notifications.SignatureHeader)).To(BeEmpty())
+				Expect(bindingServer.Requests()[0].header.Get(notifications.TimestampHeader)).To(BeEmpty())
+			})
+		})
+
+		Context("when the webhook fails", func() {
+			BeforeEach(func() {
+				server = newWebhookServer(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+			})
+
+			It("retries the event", func() {
+				notifier.Notify(event)
+
+				Eventually(server.Requests).Should(HaveLen(3))
+				Consistently(server.Requests, 50*time.Millisecond).Should(HaveLen(3))
+				Expect(logs).NotTo(gbytes.Say("error sending event"))
+			})
+
+			Context("and the retries run out", func() {
+				BeforeEach(func() {
+					retries := 1
+					conf.MaxRetries = &retries
+				})
+
+				It("logs the error", func() {
+					notifier.Notify(event)
+
+					Eventually(logs).Should(gbytes.Say("error sending event instance.create.succeeded for instance instance-id to .*: webhook responded with status 429"))
+					Expect(server.Requests()).To(HaveLen(2))
+				})
+			})
+		})
+
+		Context("when the webhook rejects the event", func() {
+			BeforeEach(func() {
+				server = newWebhookServer(http.StatusBadRequest)
+			})
+
+			It("does not retry", func() {
+				notifier.Notify(event)
+
+				Eventually(logs).Should(gbytes.Say("webhook responded with status 400"))
+				Expect(server.Requests()).To(HaveLen(1))
+			})
+		})
+
+		Context("when the webhook does not respond before shutdown times out", func() {
+			var release chan struct{}
+
+			BeforeEach(func() {
+				release = make(chan struct{})
+				server = newWebhookServer()
+				blocked := server.Config.Handler
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					select {
+					case <-release:
+					case <-r.Context().Done():
+					}
+					blocked.ServeHTTP(w, r)
+				})
+			})
+
+			AfterEach(func() {
+				close(release)
+			})
+
+			It("aborts the request in flight and drops the queued events", func() {
+				notifier.Notify(event)
+				notifier.Notify(event)
+				notifier.Notify(event)
+
+				shutdownStarted := time.Now()
+				notifier.Shutdown(50 * time.Millisecond)
+
+				Expect(time.Since(shutdownStarted)).To(BeNumerically("<", time.Second))
+				Expect(logs).To(gbytes.Say("error sending event instance.create.succeeded for instance instance-id"))
+				Expect(logs).To(gbytes.Say("notifications shut down before 2 queued events were sent, dropping them"))
+			})
+		})
+
+		Context("when the queue is full", func() {
+			var release chan struct{}
+
+			BeforeEach(func() {
+				release = make(chan struct{})
+				server = newWebhookServer()
+				blocked := server.Config.Handler
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					<-release
+					blocked.ServeHTTP(w, r)
+				})
+				conf.QueueSize = 1
+			})
+
+			It("drops new events", func() {
+				for i := 0; i < 4; i++ {
+					notifier.Notify(event)
+				}
+				Eventually(logs).Should(gbytes.Say("notification queue is full, dropping event instance.create.succeeded for instance instance-id"))
+
+				close(release)
+				notifier.Shutdown(time.Second)
+				Expect(len(server.Requests())).To(BeNumerically("<=", 2))
+			})
+		})
+	})
+})
+
+var _ = Describe("NewHTTPClient", func() {
+	It("times out requests after the configured timeout", func() {
+		Expect(notifications.NewHTTPClient(config.Notifications{TimeoutSecs: 3}).Timeout).To(Equal(3 * time.Second))
+	})
+
+	It("defaults the timeout", func() {
+		Expect(notifications.NewHTTPClient(config.Notifications{}).Timeout).To(Equal(notifications.DefaultTimeout))
+	})
+})
@@ -55,20 +55,28 @@ func New(d Deleter, r Deregistrar, cfClient CloudFoundryClient, logger *log.Logg
 }
 
 func (p Purger) DeleteInstancesAndDeregister(serviceCatalogID, brokerName string) error {
-	p.logger.Println("Disabling service access for all plans")
-	err := p.cfClient.DisableServiceAccessForAllPlans(serviceCatalogID, p.logger)
-	if err != nil {
-		return fmt.Errorf(errorMessageTemplate, err.Error())
-	}
+	return p.DeleteOfferingsInstancesAndDeregister([]string{serviceCatalogID}, brokerName)
+}
 
-	p.logger.Println("Deleting all service instances")
-	err = p.deleter.DeleteAllServiceInstances(serviceCatalogID)
-	if err != nil {
-		return fmt.Errorf(errorMessageTemplate, err.Error())
+// DeleteOfferingsInstancesAndDeregister purges the instances of every service
+// offering served by the broker before deregistering it.
+func (p Purger) DeleteOfferingsInstancesAndDeregister(serviceCatalogIDs []string, brokerName string) error {
+	for _, serviceCatalogID := range serviceCatalogIDs {
+		p.logger.Println("Disabling service access for all plans")
+		err := p.cfClient.DisableServiceAccessForAllPlans(serviceCatalogID, p.logger)
+		if err != nil {
+			return fmt.Errorf(errorMessageTemplate, err.Error())
+		}
+
+		p.logger.Println("Deleting all service instances")
+		err = p.deleter.DeleteAllServiceInstances(serviceCatalogID)
+		if err != nil {
+			return fmt.Errorf(errorMessageTemplate, err.Error())
+		}
 	}
 
 	p.logger.Println("Deregistering service brokers")
-	err = p.deregistrar.Deregister(brokerName)
+	err := p.deregistrar.Deregister(brokerName)
 	if err != nil {
 		return fmt.Errorf(errorMessageTemplate, err.Error())
 	}
@@ -72,6 +72,20 @@ var _ = Describe("purger", func() {
 		Expect(fakeRegistrar.DeregisterArgsForCall(0)).To(Equal(brokerName))
 	})
 
+	It("deletes the instances of every service offering before deregistering the broker", func() {
+		Expect(purgeTool.DeleteOfferingsInstancesAndDeregister([]string{serviceOfferingGUID, "another-service-offering-guid"}, brokerName)).NotTo(HaveOccurred())
+
+		Expect(fakeCFClient.DisableServiceAccessForAllPlansCallCount()).To(Equal(2))
+		secondServiceOfferingGUID, _ := fakeCFClient.DisableServiceAccessForAllPlansArgsForCall(1)
+		Expect(secondServiceOfferingGUID).To(Equal("another-service-offering-guid"))
+
+		Expect(fakeDeleter.DeleteAllServiceInstancesCallCount()).To(Equal(2))
+		Expect(fakeDeleter.DeleteAllServiceInstancesArgsForCall(0)).To(Equal(serviceOfferingGUID))
+		Expect(fakeDeleter.DeleteAllServiceInstancesArgsForCall(1)).To(Equal("another-service-offering-guid"))
+
+		Expect(fakeRegistrar.DeregisterCallCount()).To(Equal(1))
+	})
+
 	It("returns an error when disabling the service access fails", func() {
 		fakeCFClient.DisableServiceAccessForAllPlansReturns(errors.New("failed to disable service access"))
 		Expect(purgeTool.DeleteInstancesAndDeregister(serviceOfferingGUID, brokerName)).To(MatchError("Purger Failed: failed to disable service access"))
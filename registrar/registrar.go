@@ -38,24 +38,34 @@ func (r *RegisterBrokerRunner) Run() error {
 		return errors.Wrap(err, executionError)
 	}
 
-	for _, plan := range r.Config.Plans {
+	for _, offering := range r.Config.Offerings() {
+		if err := r.setServiceAccess(offering); err != nil {
+			return errors.Wrap(err, executionError)
+		}
+	}
+
+	return nil
+}
+
+func (r *RegisterBrokerRunner) setServiceAccess(offering config.RegisterBrokerOffering) error {
+	for _, plan := range offering.Plans {
+		var err error
 		if plan.CFServiceAccess == config.PlanEnabled {
-			err = r.CFClient.EnableServiceAccess(r.Config.ServiceOfferingID, plan.Name, r.Logger)
+			err = r.CFClient.EnableServiceAccess(offering.ServiceOfferingID, plan.Name, r.Logger)
 		} else {
-			err = r.CFClient.DisableServiceAccess(r.Config.ServiceOfferingID, plan.Name, r.Logger)
+			err = r.CFClient.DisableServiceAccess(offering.ServiceOfferingID, plan.Name, r.Logger)
 		}
 		if err != nil {
-			return errors.Wrap(err, executionError)
+			return err
 		}
 
 		if plan.CFServiceAccess == config.PlanOrgRestricted {
-			err = r.CFClient.CreateServicePlanVisibility(plan.ServiceAccessOrg, r.Config.ServiceOfferingID, plan.Name, r.Logger)
+			err = r.CFClient.CreateServicePlanVisibility(plan.ServiceAccessOrg, offering.ServiceOfferingID, plan.Name, r.Logger)
 			if err != nil {
-				return errors.Wrap(err, executionError)
+				return err
 			}
 		}
 	}
-
 	return nil
 }
 
@@ -207,6 +207,48 @@ var _ = Describe("RegisterBrokerRunner", func() {
 			Expect(serviceName).To(Equal(expectedServiceOffering))
 			Expect(planName).To(Equal(orgRestrictedPlanName))
 		})
+
+		It("sets the service access of the plans of every service offering", func() {
+			runner = registrar.RegisterBrokerRunner{
+				Config: config.RegisterBrokerErrandConfig{
+					ServiceOfferingID: expectedServiceOffering,
+					Plans:             []config.PlanAccess{{Name: "plan-1", CFServiceAccess: config.PlanEnabled}},
+					ServiceOfferings: []config.RegisterBrokerOffering{
+						{
+							ServiceOfferingID: "other-service",
+							Plans: []config.PlanAccess{
+								{Name: "plan-2", CFServiceAccess: config.PlanEnabled},
+								{Name: "plan-3", CFServiceAccess: config.PlanOrgRestricted, ServiceAccessOrg: "some-org"},
+							},
+						},
+					},
+				},
+				CFClient: fakeCFClient,
+			}
+			fakeCFClient.ServiceBrokersReturns([]cf.ServiceBroker{}, nil)
+
+			err := runner.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCFClient.EnableServiceAccessCallCount()).To(Equal(2))
+			serviceName, planName, _ := fakeCFClient.EnableServiceAccessArgsForCall(0)
+			Expect(serviceName).To(Equal(expectedServiceOffering))
+			Expect(planName).To(Equal("plan-1"))
+			serviceName, planName, _ = fakeCFClient.EnableServiceAccessArgsForCall(1)
+			Expect(serviceName).To(Equal("other-service"))
+			Expect(planName).To(Equal("plan-2"))
+
+			Expect(fakeCFClient.DisableServiceAccessCallCount()).To(Equal(1))
+			serviceName, planName, _ = fakeCFClient.DisableServiceAccessArgsForCall(0)
+			Expect(serviceName).To(Equal("other-service"))
+			Expect(planName).To(Equal("plan-3"))
+
+			Expect(fakeCFClient.CreateServicePlanVisibilityCallCount()).To(Equal(1))
+			orgName, serviceName, planName, _ := fakeCFClient.CreateServicePlanVisibilityArgsForCall(0)
+			Expect(orgName).To(Equal("some-org"))
+			Expect(serviceName).To(Equal("other-service"))
+			Expect(planName).To(Equal("plan-3"))
+		})
 	})
 
 	Describe("error handling", func() {
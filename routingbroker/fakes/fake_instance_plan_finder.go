@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/routingbroker"
+)
+
+type FakeInstancePlanFinder struct {
+	InstancePlanIDStub        func(context.Context, string) (string, error)
+	instancePlanIDMutex       sync.RWMutex
+	instancePlanIDArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	instancePlanIDReturns struct {
+		result1 string
+		result2 error
+	}
+	instancePlanIDReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeInstancePlanFinder) InstancePlanID(arg1 context.Context, arg2 string) (string, error) {
+	fake.instancePlanIDMutex.Lock()
+	ret, specificReturn := fake.instancePlanIDReturnsOnCall[len(fake.instancePlanIDArgsForCall)]
+	fake.instancePlanIDArgsForCall = append(fake.instancePlanIDArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.InstancePlanIDStub
+	fakeReturns := fake.instancePlanIDReturns
+	fake.recordInvocation("InstancePlanID", []interface{}{arg1, arg2})
+	fake.instancePlanIDMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInstancePlanFinder) InstancePlanIDCallCount() int {
+	fake.instancePlanIDMutex.RLock()
+	defer fake.instancePlanIDMutex.RUnlock()
+	return len(fake.instancePlanIDArgsForCall)
+}
+
+func (fake *FakeInstancePlanFinder) InstancePlanIDCalls(stub func(context.Context, string) (string, error)) {
+	fake.instancePlanIDMutex.Lock()
+	defer fake.instancePlanIDMutex.Unlock()
+	fake.InstancePlanIDStub = stub
+}
+
+func (fake *FakeInstancePlanFinder) InstancePlanIDArgsForCall(i int) (context.Context, string) {
+	fake.instancePlanIDMutex.RLock()
+	defer fake.instancePlanIDMutex.RUnlock()
+	argsForCall := fake.instancePlanIDArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInstancePlanFinder) InstancePlanIDReturns(result1 string, result2 error) {
+	fake.instancePlanIDMutex.Lock()
+	defer fake.instancePlanIDMutex.Unlock()
+	fake.InstancePlanIDStub = nil
+	fake.instancePlanIDReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstancePlanFinder) InstancePlanIDReturnsOnCall(i int, result1 string, result2 error) {
+	fake.instancePlanIDMutex.Lock()
+	defer fake.instancePlanIDMutex.Unlock()
+	fake.InstancePlanIDStub = nil
+	if fake.instancePlanIDReturnsOnCall == nil {
+		fake.instancePlanIDReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.instancePlanIDReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInstancePlanFinder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.instancePlanIDMutex.RLock()
+	defer fake.instancePlanIDMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeInstancePlanFinder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routingbroker.InstancePlanFinder = new(FakeInstancePlanFinder)
@@ -0,0 +1,430 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package routingbroker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+
+	"github.com/pivotal-cf/on-demand-service-broker/apiserver"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+// Route pairs a service offering with the broker that serves it. Requests are
+// matched against the plans the offering has in the offering store of the
+// RoutingBroker, which are those of ServiceOffering unless it is shared.
+type Route struct {
+	ServiceOffering config.ServiceOffering
+	Broker          apiserver.CombinedBroker
+}
+
+// InstancePlanFinder finds the plan recorded for an instance, so that requests
+// that do not say which offering they are for can be routed.
+//
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate -o fakes/fake_instance_plan_finder.go . InstancePlanFinder
+type InstancePlanFinder interface {
+	InstancePlanID(ctx context.Context, instanceID string) (string, error)
+}
+
+// RoutingBroker serves several service offerings from a single broker by
+// dispatching each request to the broker of the offering it refers to.
+// Requests are matched by service ID, then by plan ID. Requests about an
+// instance that do not say which offering they are for are matched by the plan
+// recorded for the instance. Requests that cannot be matched are rejected.
+type RoutingBroker struct {
+	routes         []Route
+	planFinder     InstancePlanFinder
+	adapterLimiter *broker.AdapterLimiter
+	offerings      *config.OfferingStore
+}
+
+func New(routes []Route, planFinder InstancePlanFinder) *RoutingBroker {
+	var offerings []config.OfferingConfig
+	for _, route := range routes {
+		offerings = append(offerings, config.OfferingConfig{ServiceCatalog: route.ServiceOffering})
+	}
+	return &RoutingBroker{routes: routes, planFinder: planFinder, offerings: config.NewOfferingStore(offerings)}
+}
+
+// SetOfferingStore makes requests be matched against the plans of the
+// offerings in offerings, so that they are reloaded with the brokers of the
+// routes. It must be called before the broker serves requests.
+func (b *RoutingBroker) SetOfferingStore(offerings *config.OfferingStore) {
+	b.offerings = offerings
+}
+
+// route returns the broker of the offering a request about an instance is for.
+// It fails with a 400 when the request refers to an offering or plan that no
+// route serves, and with a 404 when it refers to none and no route serves the
+// plan recorded for the instance.
+func (b *RoutingBroker) route(ctx context.Context, instanceID, serviceID, planID string) (apiserver.CombinedBroker, error) {
+	if serviceID == "" && planID == "" {
+		recordedPlanID, err := b.planFinder.InstancePlanID(ctx, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		route, found := b.offeringOfPlan(recordedPlanID)
+		if !found {
+			return nil, apiresponses.ErrInstanceNotFound
+		}
+		return route.Broker, nil
+	}
+
+	if route, found := b.routeOf(serviceID); found {
+		return route.Broker, nil
+	}
+
+	if route, found := b.offeringOfPlan(planID); found {
+		return route.Broker, nil
+	}
+
+	return nil, apiresponses.NewFailureResponse(
+		fmt.Errorf("no service offering matches service ID %q and plan ID %q", serviceID, planID),
+		http.StatusBadRequest,
+		"unknown-service-offering",
+	)
+}
+
+func (b *RoutingBroker) Services(ctx context.Context) ([]domain.Service, error) {
+	var services []domain.Service
+	for _, route := range b.routes {
+		routeServices, err := route.Broker.Services(ctx)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, routeServices...)
+	}
+	return services, nil
+}
+
+func (b *RoutingBroker) Provision(ctx context.Context, instanceID string, details domain.ProvisionDetails, asyncAllowed bool) (domain.ProvisionedServiceSpec, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.ProvisionedServiceSpec{}, err
+	}
+	return routed.Provision(ctx, instanceID, details, asyncAllowed)
+}
+
+func (b *RoutingBroker) Deprovision(ctx context.Context, instanceID string, details domain.DeprovisionDetails, asyncAllowed bool) (domain.DeprovisionServiceSpec, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.DeprovisionServiceSpec{}, err
+	}
+	return routed.Deprovision(ctx, instanceID, details, asyncAllowed)
+}
+
+func (b *RoutingBroker) GetInstance(ctx context.Context, instanceID string, details domain.FetchInstanceDetails) (domain.GetInstanceDetailsSpec, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.GetInstanceDetailsSpec{}, err
+	}
+	return routed.GetInstance(ctx, instanceID, details)
+}
+
+// offeringOfPlan returns the route of the offering with the plan.
+func (b *RoutingBroker) offeringOfPlan(planID string) (Route, bool) {
+	if planID == "" {
+		return Route{}, false
+	}
+	for _, offering := range b.offerings.Offerings() {
+		if _, found := offering.ServiceCatalog.FindPlanByID(planID); found {
+			return b.routeOf(offering.ServiceCatalog.ID)
+		}
+	}
+	return Route{}, false
+}
+
+func (b *RoutingBroker) routeOf(serviceID string) (Route, bool) {
+	if serviceID == "" {
+		return Route{}, false
+	}
+	for _, route := range b.routes {
+		if route.ServiceOffering.ID == serviceID {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+func (b *RoutingBroker) Update(ctx context.Context, instanceID string, details domain.UpdateDetails, asyncAllowed bool) (domain.UpdateServiceSpec, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.UpdateServiceSpec{}, err
+	}
+	return routed.Update(ctx, instanceID, details, asyncAllowed)
+}
+
+func (b *RoutingBroker) LastOperation(ctx context.Context, instanceID string, details domain.PollDetails) (domain.LastOperation, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.LastOperation{}, err
+	}
+	return routed.LastOperation(ctx, instanceID, details)
+}
+
+func (b *RoutingBroker) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, asyncAllowed bool) (domain.Binding, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.Binding{}, err
+	}
+	return routed.Bind(ctx, instanceID, bindingID, details, asyncAllowed)
+}
+
+func (b *RoutingBroker) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, asyncAllowed bool) (domain.UnbindSpec, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.UnbindSpec{}, err
+	}
+	return routed.Unbind(ctx, instanceID, bindingID, details, asyncAllowed)
+}
+
+func (b *RoutingBroker) GetBinding(ctx context.Context, instanceID, bindingID string, details domain.FetchBindingDetails) (domain.GetBindingSpec, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.GetBindingSpec{}, err
+	}
+	return routed.GetBinding(ctx, instanceID, bindingID, details)
+}
+
+func (b *RoutingBroker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details domain.PollDetails) (domain.LastOperation, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return domain.LastOperation{}, err
+	}
+	return routed.LastBindingOperation(ctx, instanceID, bindingID, details)
+}
+
+func (b *RoutingBroker) Instances(ctx context.Context, filter map[string]string, logger *log.Logger) ([]service.Instance, error) {
+	instances := []service.Instance{}
+	for _, route := range b.routes {
+		routeInstances, err := route.Broker.Instances(ctx, filter, logger)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, routeInstances...)
+	}
+	return instances, nil
+}
+
+// OrphanDeployments returns the deployments that are orphaned for every
+// offering. All offerings share the BOSH director, so each broker reports the
+// instances of the other offerings as orphans.
+func (b *RoutingBroker) OrphanDeployments(ctx context.Context, logger *log.Logger) ([]string, error) {
+	orphanCounts := map[string]int{}
+	var orphans []string
+	for _, route := range b.routes {
+		routeOrphans, err := route.Broker.OrphanDeployments(ctx, logger)
+		if err != nil {
+			return nil, err
+		}
+		for _, orphan := range routeOrphans {
+			if orphanCounts[orphan] == 0 {
+				orphans = append(orphans, orphan)
+			}
+			orphanCounts[orphan]++
+		}
+	}
+
+	var orphanDeployments []string
+	for _, orphan := range orphans {
+		if orphanCounts[orphan] == len(b.routes) {
+			orphanDeployments = append(orphanDeployments, orphan)
+		}
+	}
+	return orphanDeployments, nil
+}
+
+// CleanupOrphanDeployments cleans up each orphan through the broker of the
+// offering with the plan recorded for it, so that the pre-delete errands of its
+// plan run. Every broker is called, even without orphans, so that each removes
+// the markers of its deployments that are no longer orphaned. Orphans without
+// a plan of any offering are reported but left alone.
+func (b *RoutingBroker) CleanupOrphanDeployments(ctx context.Context, orphans []string, options broker.OrphanCleanupOptions, logger *log.Logger) (broker.OrphanCleanupReport, error) {
+	orphansByServiceID := map[string][]string{}
+	cleanups := map[string]broker.OrphanCleanup{}
+	for _, orphan := range orphans {
+		planID, err := b.planFinder.InstancePlanID(ctx, strings.TrimPrefix(orphan, broker.InstancePrefix))
+		if err != nil {
+			return broker.OrphanCleanupReport{}, err
+		}
+		route, found := b.offeringOfPlan(planID)
+		if !found {
+			logger.Printf("leaving orphan deployment %s, as no service offering has plan %q\n", orphan, planID)
+			cleanups[orphan] = broker.OrphanCleanup{DeploymentName: orphan, Action: broker.OrphanPlanNotFound}
+			continue
+		}
+		orphansByServiceID[route.ServiceOffering.ID] = append(orphansByServiceID[route.ServiceOffering.ID], orphan)
+	}
+
+	for _, route := range b.routes {
+		report, err := route.Broker.CleanupOrphanDeployments(ctx, orphansByServiceID[route.ServiceOffering.ID], options, logger)
+		if err != nil {
+			return broker.OrphanCleanupReport{}, err
+		}
+		for _, cleanup := range report.Orphans {
+			cleanups[cleanup.DeploymentName] = cleanup
+		}
+	}
+
+	report := broker.OrphanCleanupReport{DryRun: options.DryRun, Orphans: []broker.OrphanCleanup{}}
+	for _, orphan := range orphans {
+		if cleanup, found := cleanups[orphan]; found {
+			report.Orphans = append(report.Orphans, cleanup)
+		}
+	}
+	return report, nil
+}
+
+func (b *RoutingBroker) Upgrade(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (broker.OperationData, string, map[string]any, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return broker.OperationData{}, "", nil, err
+	}
+	return routed.Upgrade(ctx, instanceID, details, logger)
+}
+
+func (b *RoutingBroker) Rollback(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return broker.OperationData{}, err
+	}
+	return routed.Rollback(ctx, instanceID, details, logger)
+}
+
+func (b *RoutingBroker) RotateSecrets(ctx context.Context, instanceID string, details domain.UpdateDetails, secretPaths []string, logger *log.Logger) (broker.OperationData, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return broker.OperationData{}, err
+	}
+	return routed.RotateSecrets(ctx, instanceID, details, secretPaths, logger)
+}
+
+func (b *RoutingBroker) PreviewUpgrade(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (broker.ManifestDiff, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return broker.ManifestDiff{}, err
+	}
+	return routed.PreviewUpgrade(ctx, instanceID, details, logger)
+}
+
+func (b *RoutingBroker) PreviewUpdate(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (broker.ManifestDiff, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return broker.ManifestDiff{}, err
+	}
+	return routed.PreviewUpdate(ctx, instanceID, details, logger)
+}
+
+func (b *RoutingBroker) Recreate(ctx context.Context, instanceID string, details domain.UpdateDetails, logger *log.Logger) (broker.OperationData, error) {
+	routed, err := b.route(ctx, instanceID, details.ServiceID, details.PlanID)
+	if err != nil {
+		return broker.OperationData{}, err
+	}
+	return routed.Recreate(ctx, instanceID, details, logger)
+}
+
+func (b *RoutingBroker) CountInstancesOfPlans(ctx context.Context, logger *log.Logger) (map[cf.ServicePlan]int, error) {
+	counts := map[cf.ServicePlan]int{}
+	for _, route := range b.routes {
+		routeCounts, err := route.Broker.CountInstancesOfPlans(ctx, logger)
+		if err != nil {
+			return nil, err
+		}
+		for plan, count := range routeCounts {
+			counts[plan] += count
+		}
+	}
+	return counts, nil
+}
+
+func (b *RoutingBroker) CountInstancesOfPlansByOrgAndSpace(ctx context.Context, logger *log.Logger) (cf.OrgAndSpaceInstanceCounts, error) {
+	counts := cf.OrgAndSpaceInstanceCounts{
+		Orgs:   map[string]map[cf.ServicePlan]int{},
+		Spaces: map[string]map[cf.ServicePlan]int{},
+	}
+	for _, route := range b.routes {
+		routeCounts, err := route.Broker.CountInstancesOfPlansByOrgAndSpace(ctx, logger)
+		if err != nil {
+			return cf.OrgAndSpaceInstanceCounts{}, err
+		}
+		addScopedCounts(counts.Orgs, routeCounts.Orgs)
+		addScopedCounts(counts.Spaces, routeCounts.Spaces)
+	}
+	return counts, nil
+}
+
+func addScopedCounts(counts, routeCounts map[string]map[cf.ServicePlan]int) {
+	for scope, planCounts := range routeCounts {
+		if counts[scope] == nil {
+			counts[scope] = map[cf.ServicePlan]int{}
+		}
+		for plan, count := range planCounts {
+			counts[scope][plan] += count
+		}
+	}
+}
+
+// SetAdapterLimiter records the limiter the brokers of the offerings share, so
+// that its contention is reported once rather than once per offering.
+func (b *RoutingBroker) SetAdapterLimiter(limiter *broker.AdapterLimiter) {
+	b.adapterLimiter = limiter
+}
+
+func (b *RoutingBroker) ContentionStats() broker.ContentionStats {
+	var stats broker.ContentionStats
+	for _, route := range b.routes {
+		stats = stats.Add(route.Broker.ContentionStats())
+	}
+	if b.adapterLimiter != nil {
+		stats.AdapterCalls = b.adapterLimiter.Contention()
+	}
+	return stats
+}
+
+// OperationHistory is served by the offering with the plan recorded for the
+// instance, which is kept after the instance is deleted.
+func (b *RoutingBroker) OperationHistory(ctx context.Context, instanceID string, logger *log.Logger) ([]broker.OperationHistoryEntry, error) {
+	planID, err := b.planFinder.InstancePlanID(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	route, found := b.offeringOfPlan(planID)
+	if !found {
+		return nil, broker.NewDeploymentNotFoundError(fmt.Errorf("no service offering has plan %q of instance %s", planID, instanceID))
+	}
+	return route.Broker.OperationHistory(ctx, instanceID, logger)
+}
+
+// Iterator checkpoints are served by the first route. They are kept on the BOSH
+// director, which all offerings share, and are not about any one offering.
+func (b *RoutingBroker) IteratorCheckpoint(ctx context.Context, name string, logger *log.Logger) ([]byte, bool, error) {
+	return b.routes[0].Broker.IteratorCheckpoint(ctx, name, logger)
+}
+
+func (b *RoutingBroker) SaveIteratorCheckpoint(ctx context.Context, name string, checkpoint []byte, logger *log.Logger) error {
+	return b.routes[0].Broker.SaveIteratorCheckpoint(ctx, name, checkpoint, logger)
+}
+
+func (b *RoutingBroker) ClearIteratorCheckpoint(ctx context.Context, name string, logger *log.Logger) error {
+	return b.routes[0].Broker.ClearIteratorCheckpoint(ctx, name, logger)
+}
+
+func (b *RoutingBroker) SetUAAClient(uaaClient broker.UAAClient) {
+	for _, route := range b.routes {
+		route.Broker.SetUAAClient(uaaClient)
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package routingbroker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apifakes "github.com/pivotal-cf/on-demand-service-broker/apiserver/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/routingbroker"
+	"github.com/pivotal-cf/on-demand-service-broker/routingbroker/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+var _ = Describe("RoutingBroker", func() {
+	var (
+		redisBroker     *apifakes.FakeCombinedBroker
+		rabbitBroker    *apifakes.FakeCombinedBroker
+		planFinder      *fakes.FakeInstancePlanFinder
+		routingBroker   *routingbroker.RoutingBroker
+		logger          *log.Logger
+		ctx             context.Context
+		redisPlanID     = "redis-plan-id"
+		rabbitPlanID    = "rabbit-plan-id"
+		redisServiceID  = "redis-service-id"
+		rabbitServiceID = "rabbit-service-id"
+	)
+
+	BeforeEach(func() {
+		redisBroker = new(apifakes.FakeCombinedBroker)
+		rabbitBroker = new(apifakes.FakeCombinedBroker)
+		planFinder = new(fakes.FakeInstancePlanFinder)
+		logger = log.New(GinkgoWriter, "", log.LstdFlags)
+		ctx = context.Background()
+
+		routingBroker = routingbroker.New([]routingbroker.Route{
+			{
+				ServiceOffering: config.ServiceOffering{ID: redisServiceID, Plans: config.Plans{{ID: redisPlanID}}},
+				Broker:          redisBroker,
+			},
+			{
+				ServiceOffering: config.ServiceOffering{ID: rabbitServiceID, Plans: config.Plans{{ID: rabbitPlanID}}},
+				Broker:          rabbitBroker,
+			},
+		}, planFinder)
+	})
+
+	Describe("routing requests", func() {
+		It("routes by service ID", func() {
+			_, err := routingBroker.Provision(ctx, "some-instance", domain.ProvisionDetails{ServiceID: rabbitServiceID, PlanID: rabbitPlanID}, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rabbitBroker.ProvisionCallCount()).To(Equal(1))
+			Expect(redisBroker.ProvisionCallCount()).To(Equal(0))
+		})
+
+		It("routes by plan ID when the service ID is not provided", func() {
+			_, _, _, err := routingBroker.Upgrade(ctx, "some-instance", domain.UpdateDetails{PlanID: rabbitPlanID}, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rabbitBroker.UpgradeCallCount()).To(Equal(1))
+			Expect(redisBroker.UpgradeCallCount()).To(Equal(0))
+		})
+
+		Describe("GetInstance", func() {
+			It("routes by the plan recorded for the instance when the request has no IDs", func() {
+				planFinder.InstancePlanIDReturns(rabbitPlanID, nil)
+
+				_, err := routingBroker.GetInstance(ctx, "some-instance", domain.FetchInstanceDetails{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(rabbitBroker.GetInstanceCallCount()).To(Equal(1))
+				_, instanceID, details := rabbitBroker.GetInstanceArgsForCall(0)
+				Expect(instanceID).To(Equal("some-instance"))
+				Expect(details).To(Equal(domain.FetchInstanceDetails{}))
+				_, finderInstanceID := planFinder.InstancePlanIDArgsForCall(0)
+				Expect(finderInstanceID).To(Equal("some-instance"))
+			})
+
+			It("does not look up the plan when the request has IDs", func() {
+				_, err := routingBroker.GetInstance(ctx, "some-instance", domain.FetchInstanceDetails{ServiceID: rabbitServiceID})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(rabbitBroker.GetInstanceCallCount()).To(Equal(1))
+				Expect(planFinder.InstancePlanIDCallCount()).To(BeZero())
+			})
+
+			It("returns not found when no offering has the recorded plan", func() {
+				planFinder.InstancePlanIDReturns("", nil)
+
+				_, err := routingBroker.GetInstance(ctx, "some-instance", domain.FetchInstanceDetails{})
+				Expect(err).To(MatchError(apiresponses.ErrInstanceNotFound))
+
+				Expect(redisBroker.GetInstanceCallCount()).To(BeZero())
+				Expect(rabbitBroker.GetInstanceCallCount()).To(BeZero())
+			})
+
+			It("returns the error when the plan cannot be looked up", func() {
+				planFinder.InstancePlanIDReturns("", errors.New("no director"))
+
+				_, err := routingBroker.GetInstance(ctx, "some-instance", domain.FetchInstanceDetails{})
+				Expect(err).To(MatchError("no director"))
+			})
+		})
+
+		It("routes by the plan recorded for the instance when the request has no IDs", func() {
+			planFinder.InstancePlanIDReturns(rabbitPlanID, nil)
+
+			_, err := routingBroker.LastOperation(ctx, "some-instance", domain.PollDetails{OperationData: "{}"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rabbitBroker.LastOperationCallCount()).To(Equal(1))
+			Expect(redisBroker.LastOperationCallCount()).To(Equal(0))
+			_, instanceID := planFinder.InstancePlanIDArgsForCall(0)
+			Expect(instanceID).To(Equal("some-instance"))
+		})
+
+		It("returns not found when the request has no IDs and no offering has the recorded plan", func() {
+			planFinder.InstancePlanIDReturns("", nil)
+
+			_, err := routingBroker.LastOperation(ctx, "some-instance", domain.PollDetails{OperationData: "{}"})
+			Expect(err).To(MatchError(apiresponses.ErrInstanceNotFound))
+
+			Expect(redisBroker.LastOperationCallCount()).To(BeZero())
+			Expect(rabbitBroker.LastOperationCallCount()).To(BeZero())
+		})
+
+		It("rejects requests for an offering or plan that no route serves", func() {
+			_, err := routingBroker.Provision(ctx, "some-instance", domain.ProvisionDetails{ServiceID: "unknown-service", PlanID: "unknown-plan"}, true)
+
+			Expect(err).To(MatchError(`no service offering matches service ID "unknown-service" and plan ID "unknown-plan"`))
+			failure, ok := err.(*apiresponses.FailureResponse)
+			Expect(ok).To(BeTrue())
+			Expect(failure.ValidatedStatusCode(nil)).To(Equal(http.StatusBadRequest))
+			Expect(redisBroker.ProvisionCallCount()).To(BeZero())
+			Expect(rabbitBroker.ProvisionCallCount()).To(BeZero())
+			Expect(planFinder.InstancePlanIDCallCount()).To(BeZero())
+		})
+
+		It("routes by the plans of a reloaded offering", func() {
+			offerings := config.NewOfferingStore([]config.OfferingConfig{
+				{ServiceCatalog: config.ServiceOffering{ID: redisServiceID, Plans: config.Plans{{ID: redisPlanID}}}},
+				{ServiceCatalog: config.ServiceOffering{ID: rabbitServiceID, Plans: config.Plans{{ID: rabbitPlanID}}}},
+			})
+			routingBroker.SetOfferingStore(offerings)
+			offerings.SetOfferings([]config.OfferingConfig{
+				{ServiceCatalog: config.ServiceOffering{ID: redisServiceID, Plans: config.Plans{{ID: redisPlanID}}}},
+				{ServiceCatalog: config.ServiceOffering{ID: rabbitServiceID, Plans: config.Plans{{ID: rabbitPlanID}, {ID: "new-rabbit-plan-id"}}}},
+			})
+
+			_, _, _, err := routingBroker.Upgrade(ctx, "some-instance", domain.UpdateDetails{PlanID: "new-rabbit-plan-id"}, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rabbitBroker.UpgradeCallCount()).To(Equal(1))
+			Expect(redisBroker.UpgradeCallCount()).To(Equal(0))
+		})
+	})
+
+	It("returns the services of every offering", func() {
+		redisBroker.ServicesReturns([]domain.Service{{ID: redisServiceID}}, nil)
+		rabbitBroker.ServicesReturns([]domain.Service{{ID: rabbitServiceID}}, nil)
+
+		services, err := routingBroker.Services(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(services).To(Equal([]domain.Service{{ID: redisServiceID}, {ID: rabbitServiceID}}))
+	})
+
+	It("returns the instances of every offering", func() {
+		redisBroker.InstancesReturns([]service.Instance{{GUID: "redis-instance"}}, nil)
+		rabbitBroker.InstancesReturns([]service.Instance{{GUID: "rabbit-instance"}}, nil)
+
+		instances, err := routingBroker.Instances(context.Background(), map[string]string{"foo": "bar"}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances).To(Equal([]service.Instance{{GUID: "redis-instance"}, {GUID: "rabbit-instance"}}))
+
+		_, filter, _ := rabbitBroker.InstancesArgsForCall(0)
+		Expect(filter).To(Equal(map[string]string{"foo": "bar"}))
+	})
+
+	It("fails to list the instances when one of the offerings fails", func() {
+		rabbitBroker.InstancesReturns(nil, errors.New("oops"))
+
+		_, err := routingBroker.Instances(context.Background(), nil, logger)
+		Expect(err).To(MatchError("oops"))
+	})
+
+	It("returns only the deployments that are orphaned for every offering", func() {
+		redisBroker.OrphanDeploymentsReturns([]string{"service-instance_rabbit", "service-instance_orphan"}, nil)
+		rabbitBroker.OrphanDeploymentsReturns([]string{"service-instance_redis", "service-instance_orphan"}, nil)
+
+		orphans, err := routingBroker.OrphanDeployments(context.Background(), logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(Equal([]string{"service-instance_orphan"}))
+	})
+
+	It("cleans up each orphan through the offering with the plan recorded for it", func() {
+		planFinder.InstancePlanIDStub = func(_ context.Context, instanceID string) (string, error) {
+			switch instanceID {
+			case "rabbit-orphan":
+				return rabbitPlanID, nil
+			case "redis-orphan":
+				return redisPlanID, nil
+			}
+			return "", nil
+		}
+		rabbitBroker.CleanupOrphanDeploymentsReturns(broker.OrphanCleanupReport{Orphans: []broker.OrphanCleanup{
+			{DeploymentName: "service-instance_rabbit-orphan", Action: broker.OrphanMarked},
+		}}, nil)
+		redisBroker.CleanupOrphanDeploymentsReturns(broker.OrphanCleanupReport{Orphans: []broker.OrphanCleanup{
+			{DeploymentName: "service-instance_redis-orphan", Action: broker.OrphanPending},
+		}}, nil)
+
+		report, err := routingBroker.CleanupOrphanDeployments(
+			context.Background(),
+			[]string{"service-instance_rabbit-orphan", "service-instance_unknown-orphan", "service-instance_redis-orphan"},
+			broker.OrphanCleanupOptions{DryRun: true},
+			logger,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(Equal(broker.OrphanCleanupReport{DryRun: true, Orphans: []broker.OrphanCleanup{
+			{DeploymentName: "service-instance_rabbit-orphan", Action: broker.OrphanMarked},
+			{DeploymentName: "service-instance_unknown-orphan", Action: broker.OrphanPlanNotFound},
+			{DeploymentName: "service-instance_redis-orphan", Action: broker.OrphanPending},
+		}}))
+
+		_, redisOrphans, _, _ := redisBroker.CleanupOrphanDeploymentsArgsForCall(0)
+		Expect(redisOrphans).To(Equal([]string{"service-instance_redis-orphan"}))
+		_, rabbitOrphans, _, _ := rabbitBroker.CleanupOrphanDeploymentsArgsForCall(0)
+		Expect(rabbitOrphans).To(Equal([]string{"service-instance_rabbit-orphan"}))
+	})
+
+	It("calls every offering so that each removes its stale markers", func() {
+		planFinder.InstancePlanIDReturns(rabbitPlanID, nil)
+
+		_, err := routingBroker.CleanupOrphanDeployments(context.Background(), []string{"service-instance_orphan"}, broker.OrphanCleanupOptions{}, logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redisBroker.CleanupOrphanDeploymentsCallCount()).To(Equal(1))
+		_, redisOrphans, _, _ := redisBroker.CleanupOrphanDeploymentsArgsForCall(0)
+		Expect(redisOrphans).To(BeEmpty())
+	})
+
+	It("fails when the plan of an orphan cannot be read", func() {
+		planFinder.InstancePlanIDReturns("", errors.New("director unavailable"))
+
+		_, err := routingBroker.CleanupOrphanDeployments(context.Background(), []string{"service-instance_orphan"}, broker.OrphanCleanupOptions{}, logger)
+		Expect(err).To(MatchError("director unavailable"))
+		Expect(redisBroker.CleanupOrphanDeploymentsCallCount()).To(BeZero())
+		Expect(rabbitBroker.CleanupOrphanDeploymentsCallCount()).To(BeZero())
+	})
+
+	It("merges the instance counts of every offering", func() {
+		redisPlan := cf.ServicePlan{ServicePlanEntity: cf.ServicePlanEntity{UniqueID: redisPlanID}}
+		rabbitPlan := cf.ServicePlan{ServicePlanEntity: cf.ServicePlanEntity{UniqueID: rabbitPlanID}}
+		redisBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{redisPlan: 2}, nil)
+		rabbitBroker.CountInstancesOfPlansReturns(map[cf.ServicePlan]int{rabbitPlan: 3}, nil)
+
+		counts, err := routingBroker.CountInstancesOfPlans(context.Background(), logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(Equal(map[cf.ServicePlan]int{redisPlan: 2, rabbitPlan: 3}))
+	})
+
+	It("sums the lock contention of every offering", func() {
+		redisBroker.ContentionStatsReturns(broker.ContentionStats{InstanceLocks: broker.Contention{Acquisitions: 2, Contended: 1}})
+		rabbitBroker.ContentionStatsReturns(broker.ContentionStats{InstanceLocks: broker.Contention{Acquisitions: 3}, AdapterCalls: broker.Contention{Waiting: 1}})
+
+		Expect(routingBroker.ContentionStats()).To(Equal(broker.ContentionStats{
+			InstanceLocks: broker.Contention{Acquisitions: 5, Contended: 1},
+			AdapterCalls:  broker.Contention{Waiting: 1},
+		}))
+	})
+
+	It("reports the contention of a shared adapter limiter once", func() {
+		limiter := broker.NewAdapterLimiter(1)
+		routingBroker.SetAdapterLimiter(limiter)
+		redisBroker.ContentionStatsReturns(broker.ContentionStats{AdapterCalls: broker.Contention{Acquisitions: 4}})
+		rabbitBroker.ContentionStatsReturns(broker.ContentionStats{AdapterCalls: broker.Contention{Acquisitions: 4}})
+
+		Expect(routingBroker.ContentionStats().AdapterCalls).To(Equal(limiter.Contention()))
+	})
+
+	It("reads the operation history through the offering with the recorded plan", func() {
+		planFinder.InstancePlanIDReturns(rabbitPlanID, nil)
+		history := []broker.OperationHistoryEntry{{Type: broker.OperationTypeCreate, BoshTaskIDs: []int{1}}}
+		rabbitBroker.OperationHistoryReturns(history, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		Expect(routingBroker.OperationHistory(ctx, "some-instance", logger)).To(Equal(history))
+		actualCtx, instanceID := planFinder.InstancePlanIDArgsForCall(0)
+		Expect(actualCtx).To(BeIdenticalTo(ctx))
+		Expect(instanceID).To(Equal("some-instance"))
+		_, instanceID, _ = rabbitBroker.OperationHistoryArgsForCall(0)
+		Expect(instanceID).To(Equal("some-instance"))
+		Expect(redisBroker.OperationHistoryCallCount()).To(BeZero())
+	})
+
+	It("fails with a not found error when no offering has the recorded plan", func() {
+		planFinder.InstancePlanIDReturns("", nil)
+
+		_, err := routingBroker.OperationHistory(context.Background(), "some-instance", logger)
+		Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+		Expect(redisBroker.OperationHistoryCallCount()).To(BeZero())
+		Expect(rabbitBroker.OperationHistoryCallCount()).To(BeZero())
+	})
+
+	It("sets the UAA client on every offering", func() {
+		routingBroker.SetUAAClient(nil)
+
+		Expect(redisBroker.SetUAAClientCallCount()).To(Equal(1))
+		Expect(rabbitBroker.SetUAAClientCallCount()).To(Equal(1))
+	})
+})
@@ -78,7 +78,7 @@ func (l *CFServiceInstanceLister) filtersFromMap(filter map[string]string) (orgN
 				unknownFilters = append(unknownFilters, key)
 			}
 		}
-		return "", "", fmt.Errorf("unsupported filters: %s; supported filters are: cf_org, cf_space", strings.Join(unknownFilters, ", "))
+		return "", "", fmt.Errorf("unsupported filters: %s; supported filters are: cf_org, cf_space, %s", strings.Join(unknownFilters, ", "), strings.Join(brokerFilterKeys, ", "))
 	}
 	return orgName, spaceName, nil
 }
@@ -28,9 +28,9 @@ var _ = Describe("CFServiceInstanceLister", func() {
 	Describe("Instances", func() {
 		It("queries CF for a list of service instances", func() {
 			fakeCfClient.GetServiceInstancesReturns([]cf.Instance{
-				{GUID: "some-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id"},
-				{GUID: "some-other-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id"},
-				{GUID: "yet-another-guid", PlanUniqueID: "some-other-plan", SpaceGUID: "space_id"},
+				{GUID: "some-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id", OrganizationGUID: "org_id"},
+				{GUID: "some-other-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id", OrganizationGUID: "org_id"},
+				{GUID: "yet-another-guid", PlanUniqueID: "some-other-plan", SpaceGUID: "space_id", OrganizationGUID: "org_id"},
 			}, nil)
 
 			l, err := service.BuildInstanceLister(fakeCfClient, "some-offering-id", config.ServiceInstancesAPI{}, fakeLogger)
@@ -40,9 +40,9 @@ var _ = Describe("CFServiceInstanceLister", func() {
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(instances).To(ConsistOf(
-				service.Instance{GUID: "some-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id"},
-				service.Instance{GUID: "some-other-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id"},
-				service.Instance{GUID: "yet-another-guid", PlanUniqueID: "some-other-plan", SpaceGUID: "space_id"},
+				service.Instance{GUID: "some-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id", OrganizationGUID: "org_id"},
+				service.Instance{GUID: "some-other-guid", PlanUniqueID: "some-plan", SpaceGUID: "space_id", OrganizationGUID: "org_id"},
+				service.Instance{GUID: "yet-another-guid", PlanUniqueID: "some-other-plan", SpaceGUID: "space_id", OrganizationGUID: "org_id"},
 			))
 
 			Expect(fakeCfClient.GetServiceInstancesCallCount()).To(Equal(1), "cf client wasn't called")
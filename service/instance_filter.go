@@ -0,0 +1,181 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The filters that the broker applies to the instances listed by the
+// instance lister. List values are comma separated; labels are key=value
+// pairs and releases name/version pairs. Other filters, such as cf_org and
+// cf_space, are passed on to the instance lister. Labels are recorded when an
+// instance is deployed, so instances not deployed since the broker started
+// recording them are not selected by labels until they are next deployed.
+const (
+	PlanIDsFilterKey               = "plan_ids"
+	ExcludedPlanIDsFilterKey       = "exclude_plan_ids"
+	InstanceGUIDsFilterKey         = "instance_guids"
+	ExcludedInstanceGUIDsFilterKey = "exclude_instance_guids"
+	LabelsFilterKey                = "labels"
+	MinAgeFilterKey                = "min_age"
+	ReleasesFilterKey              = "releases"
+	StemcellVersionFilterKey       = "stemcell_version"
+)
+
+var brokerFilterKeys = []string{
+	PlanIDsFilterKey,
+	ExcludedPlanIDsFilterKey,
+	InstanceGUIDsFilterKey,
+	ExcludedInstanceGUIDsFilterKey,
+	LabelsFilterKey,
+	MinAgeFilterKey,
+	ReleasesFilterKey,
+	StemcellVersionFilterKey,
+}
+
+// InvalidFilterError is returned for filters that cannot be parsed.
+type InvalidFilterError struct {
+	error
+}
+
+// InstanceFilter selects instances by what is known about them from the
+// instance lister and, for the deployment filters, from their deployments.
+// An instance is selected when it matches all the filters that are set.
+type InstanceFilter struct {
+	PlanIDs         []string
+	ExcludedPlanIDs []string
+	GUIDs           []string
+	ExcludedGUIDs   []string
+	Labels          map[string]string
+	MinAge          time.Duration
+	Releases        map[string]string
+	StemcellVersion string
+	ListerFilter    map[string]string
+}
+
+// DeploymentInfo is what the deployment filters are matched against.
+// CreatedAt is when the instance was created.
+type DeploymentInfo struct {
+	Labels           map[string]string
+	CreatedAt        time.Time
+	Releases         map[string]string
+	StemcellVersions []string
+}
+
+func ParseInstanceFilter(filter map[string]string) (InstanceFilter, error) {
+	var instanceFilter InstanceFilter
+	for key, value := range filter {
+		switch key {
+		case PlanIDsFilterKey:
+			instanceFilter.PlanIDs = splitList(value)
+		case ExcludedPlanIDsFilterKey:
+			instanceFilter.ExcludedPlanIDs = splitList(value)
+		case InstanceGUIDsFilterKey:
+			instanceFilter.GUIDs = splitList(value)
+		case ExcludedInstanceGUIDsFilterKey:
+			instanceFilter.ExcludedGUIDs = splitList(value)
+		case LabelsFilterKey:
+			labels, err := splitPairs(key, value, "=")
+			if err != nil {
+				return InstanceFilter{}, err
+			}
+			instanceFilter.Labels = labels
+		case MinAgeFilterKey:
+			minAge, err := time.ParseDuration(value)
+			if err != nil {
+				return InstanceFilter{}, InvalidFilterError{fmt.Errorf("invalid filter %s: %s", key, err)}
+			}
+			instanceFilter.MinAge = minAge
+		case ReleasesFilterKey:
+			releases, err := splitPairs(key, value, "/")
+			if err != nil {
+				return InstanceFilter{}, err
+			}
+			instanceFilter.Releases = releases
+		case StemcellVersionFilterKey:
+			instanceFilter.StemcellVersion = value
+		default:
+			if instanceFilter.ListerFilter == nil {
+				instanceFilter.ListerFilter = map[string]string{}
+			}
+			instanceFilter.ListerFilter[key] = value
+		}
+	}
+	return instanceFilter, nil
+}
+
+// Selects matches an instance against the filters that do not need its
+// deployment.
+func (f InstanceFilter) Selects(instance Instance) bool {
+	if len(f.PlanIDs) > 0 && !contains(f.PlanIDs, instance.PlanUniqueID) {
+		return false
+	}
+	if len(f.GUIDs) > 0 && !contains(f.GUIDs, instance.GUID) {
+		return false
+	}
+	return !contains(f.ExcludedPlanIDs, instance.PlanUniqueID) && !contains(f.ExcludedGUIDs, instance.GUID)
+}
+
+// FiltersDeployments is true when the instances can only be selected once
+// their deployments have been looked up.
+func (f InstanceFilter) FiltersDeployments() bool {
+	return len(f.Labels) > 0 || f.MinAge > 0 || len(f.Releases) > 0 || f.StemcellVersion != ""
+}
+
+func (f InstanceFilter) SelectsDeployment(deployment DeploymentInfo, now time.Time) bool {
+	for key, value := range f.Labels {
+		if label, found := deployment.Labels[key]; !found || label != value {
+			return false
+		}
+	}
+	for name, version := range f.Releases {
+		if deployment.Releases[name] != version {
+			return false
+		}
+	}
+	if f.StemcellVersion != "" && !contains(deployment.StemcellVersions, f.StemcellVersion) {
+		return false
+	}
+	if f.MinAge > 0 && (deployment.CreatedAt.IsZero() || now.Sub(deployment.CreatedAt) < f.MinAge) {
+		return false
+	}
+	return true
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func splitPairs(key, value, separator string) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, item := range splitList(value) {
+		name, pairValue, found := strings.Cut(item, separator)
+		if !found || name == "" {
+			return nil, InvalidFilterError{fmt.Errorf("invalid filter %s: %q is not of the form name%svalue", key, item, separator)}
+		}
+		pairs[name] = pairValue
+	}
+	return pairs, nil
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package service_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/service"
+)
+
+var _ = Describe("InstanceFilter", func() {
+	Describe("ParseInstanceFilter", func() {
+		It("parses the broker filters and leaves the others to the instance lister", func() {
+			filter, err := service.ParseInstanceFilter(map[string]string{
+				"plan_ids":               "small, large",
+				"exclude_plan_ids":       "legacy",
+				"instance_guids":         "guid-1,guid-2",
+				"exclude_instance_guids": "guid-3",
+				"labels":                 "tier=gold,region=eu",
+				"min_age":                "720h",
+				"releases":               "redis/1.2.3",
+				"stemcell_version":       "621.5",
+				"cf_org":                 "org",
+				"cf_space":               "space",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filter).To(Equal(service.InstanceFilter{
+				PlanIDs:         []string{"small", "large"},
+				ExcludedPlanIDs: []string{"legacy"},
+				GUIDs:           []string{"guid-1", "guid-2"},
+				ExcludedGUIDs:   []string{"guid-3"},
+				Labels:          map[string]string{"tier": "gold", "region": "eu"},
+				MinAge:          720 * time.Hour,
+				Releases:        map[string]string{"redis": "1.2.3"},
+				StemcellVersion: "621.5",
+				ListerFilter:    map[string]string{"cf_org": "org", "cf_space": "space"},
+			}))
+			Expect(filter.FiltersDeployments()).To(BeTrue())
+		})
+
+		DescribeTable("rejects invalid filters",
+			func(key, value, expectedError string) {
+				_, err := service.ParseInstanceFilter(map[string]string{key: value})
+
+				Expect(err).To(BeAssignableToTypeOf(service.InvalidFilterError{}))
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			},
+			Entry("min_age", "min_age", "a month", "invalid filter min_age"),
+			Entry("labels", "labels", "tier", `invalid filter labels: "tier" is not of the form name=value`),
+			Entry("releases", "releases", "redis", `invalid filter releases: "redis" is not of the form name/value`),
+		)
+	})
+
+	Describe("Selects", func() {
+		instance := service.Instance{GUID: "guid-1", PlanUniqueID: "small"}
+
+		DescribeTable("matches the instance filters",
+			func(filter service.InstanceFilter, selected bool) {
+				Expect(filter.Selects(instance)).To(Equal(selected))
+			},
+			Entry("no filters", service.InstanceFilter{}, true),
+			Entry("plan", service.InstanceFilter{PlanIDs: []string{"small"}}, true),
+			Entry("other plan", service.InstanceFilter{PlanIDs: []string{"large"}}, false),
+			Entry("excluded plan", service.InstanceFilter{ExcludedPlanIDs: []string{"small"}}, false),
+			Entry("guid", service.InstanceFilter{GUIDs: []string{"guid-1"}}, true),
+			Entry("other guid", service.InstanceFilter{GUIDs: []string{"guid-2"}}, false),
+			Entry("excluded guid", service.InstanceFilter{GUIDs: []string{"guid-1"}, ExcludedGUIDs: []string{"guid-1"}}, false),
+		)
+	})
+
+	Describe("SelectsDeployment", func() {
+		now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+		deployment := service.DeploymentInfo{
+			Labels:           map[string]string{"tier": "gold"},
+			CreatedAt:        now.Add(-48 * time.Hour),
+			Releases:         map[string]string{"redis": "1.2.3"},
+			StemcellVersions: []string{"621.5"},
+		}
+
+		DescribeTable("matches the deployment filters",
+			func(filter service.InstanceFilter, deployment service.DeploymentInfo, selected bool) {
+				Expect(filter.SelectsDeployment(deployment, now)).To(Equal(selected))
+			},
+			Entry("labels", service.InstanceFilter{Labels: map[string]string{"tier": "gold"}}, deployment, true),
+			Entry("other labels", service.InstanceFilter{Labels: map[string]string{"tier": "silver"}}, deployment, false),
+			Entry("release", service.InstanceFilter{Releases: map[string]string{"redis": "1.2.3"}}, deployment, true),
+			Entry("other release version", service.InstanceFilter{Releases: map[string]string{"redis": "1.2.4"}}, deployment, false),
+			Entry("stemcell", service.InstanceFilter{StemcellVersion: "621.5"}, deployment, true),
+			Entry("other stemcell", service.InstanceFilter{StemcellVersion: "621.6"}, deployment, false),
+			Entry("old enough", service.InstanceFilter{MinAge: 24 * time.Hour}, deployment, true),
+			Entry("too young", service.InstanceFilter{MinAge: 72 * time.Hour}, deployment, false),
+			Entry("unknown age", service.InstanceFilter{MinAge: time.Hour}, service.DeploymentInfo{}, false),
+		)
+	})
+})
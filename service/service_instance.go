@@ -29,9 +29,10 @@ import (
 )
 
 type Instance struct {
-	GUID         string `json:"service_instance_id"`
-	PlanUniqueID string `json:"plan_id"`
-	SpaceGUID    string `json:"space_guid,omitempty"`
+	GUID             string `json:"service_instance_id"`
+	PlanUniqueID     string `json:"plan_id"`
+	SpaceGUID        string `json:"space_guid,omitempty"`
+	OrganizationGUID string `json:"organization_guid,omitempty"`
 }
 
 // InstanceLister provides a interface to query service instances present in the platform
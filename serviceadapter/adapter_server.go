@@ -0,0 +1,112 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+// Handler handles the adapter calls received by an adapter server. ctx is
+// cancelled when the broker gives up on a call, as when the adapter timed out
+// or the request to the broker was cancelled.
+type Handler interface {
+	Handle(ctx context.Context, args []string, stdout, stderr io.Writer, stdin io.Reader) error
+}
+
+type HandlerFunc func(ctx context.Context, args []string, stdout, stderr io.Writer, stdin io.Reader) error
+
+func (f HandlerFunc) Handle(ctx context.Context, args []string, stdout, stderr io.Writer, stdin io.Reader) error {
+	return f(ctx, args, stdout, stderr, stdin)
+}
+
+// SDKHandler handles calls with an adapter written with the SDK, as the SDK
+// handles command line invocations, exit codes included. The SDK cannot
+// interrupt an adapter, so once ctx is cancelled the call is left to finish on
+// its own and what it writes is discarded.
+func SDKHandler(handler sdk.CommandLineHandler) Handler {
+	return HandlerFunc(func(ctx context.Context, args []string, stdout, stderr io.Writer, stdin io.Reader) error {
+		var out, errOut bytes.Buffer
+		done := make(chan error, 1)
+		go func() {
+			done <- handler.Handle(args, &out, &errOut, stdin)
+		}()
+
+		select {
+		case err := <-done:
+			stdout.Write(out.Bytes())
+			stderr.Write(errOut.Bytes())
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// NewAdapterServer serves the calls of a server command runner with handler.
+// The context of each call is cancelled when the server command runner
+// abandons it.
+func NewAdapterServer(handler Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(AdapterServerRunPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var runRequest RunRequest
+		if err := json.NewDecoder(r.Body).Decode(&runRequest); err != nil {
+			http.Error(w, fmt.Sprintf("invalid run request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		runResponse := handle(r.Context(), handler, runRequest)
+		if r.Context().Err() != nil {
+			// the server command runner is no longer waiting for a response
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(runResponse)
+	})
+	return mux
+}
+
+func handle(ctx context.Context, handler Handler, runRequest RunRequest) RunResponse {
+	var stdout, stderr bytes.Buffer
+
+	// the SDK exits when the command is unknown
+	if len(runRequest.Args) == 0 || !config.IsServiceAdapterSubcommand(runRequest.Args[0]) {
+		fmt.Fprintf(&stderr, "[odb-sdk] unknown subcommand: %v\n", runRequest.Args)
+		return RunResponse{Stderr: stderr.String(), ExitCode: sdk.ErrorExitCode}
+	}
+
+	args := append([]string{"adapter-server"}, runRequest.Args...)
+	err := handler.Handle(ctx, args, &stdout, &stderr, bytes.NewReader(runRequest.InputParams))
+
+	exitCode := SuccessExitCode
+	switch e := err.(type) {
+	case nil:
+	case sdk.CLIHandlerError:
+		exitCode = e.ExitCode
+	default:
+		exitCode = sdk.ErrorExitCode
+	}
+	if err != nil {
+		fmt.Fprintf(&stderr, "[odb-sdk] %s\n", err)
+	}
+
+	return RunResponse{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: exitCode}
+}
@@ -7,8 +7,10 @@
 package serviceadapter
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
@@ -18,14 +20,36 @@ const SuccessExitCode = 0
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o fakes/fake_command_runner.go . CommandRunner
 type CommandRunner interface {
-	Run(arg ...string) ([]byte, []byte, *int, error)
-	RunWithInputParams(inputParams interface{}, arg ...string) ([]byte, []byte, *int, error)
+	Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error)
+	RunWithInputParams(ctx context.Context, inputParams interface{}, arg ...string) ([]byte, []byte, *int, error)
 }
 
 type Client struct {
 	ExternalBinPath string
 	CommandRunner   CommandRunner
 	UsingStdin      bool
+	// Timeouts limits how long each subcommand, such as generate-manifest,
+	// may run for. Subcommands without a timeout run until they exit or the
+	// request is cancelled.
+	Timeouts map[string]time.Duration
+}
+
+// runContext returns the context to run subcommand in: the context of the
+// request, limited by the subcommand's timeout.
+func (c *Client) runContext(ctx context.Context, subcommand string) (context.Context, context.CancelFunc) {
+	if timeout, ok := c.Timeouts[subcommand]; ok && timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// runFailure returns the error for a subcommand that could not be run to
+// completion, telling timeouts apart from other failures.
+func (c *Client) runFailure(ctx context.Context, subcommand string, stdout, stderr []byte, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return TimeoutError{Subcommand: subcommand, Timeout: c.Timeouts[subcommand]}
+	}
+	return adapterError(c.ExternalBinPath, stdout, stderr, err)
 }
 
 func SanitiseForJSON(properties sdk.Properties) sdk.Properties {
@@ -98,6 +122,20 @@ type BindingNotFoundError struct {
 	error
 }
 
+// TimeoutError is returned when the adapter was killed for running a
+// subcommand for longer than its timeout.
+type TimeoutError struct {
+	Subcommand string
+	Timeout    time.Duration
+}
+
+func (e TimeoutError) Error() string {
+	if e.Timeout == 0 {
+		return fmt.Sprintf("service adapter timed out running %s", e.Subcommand)
+	}
+	return fmt.Sprintf("service adapter timed out running %s after %s", e.Subcommand, e.Timeout)
+}
+
 func NewNotImplementedError(msg string) NotImplementedError {
 	return NotImplementedError{errors.New(msg)}
 }
@@ -126,6 +164,17 @@ func invalidVersionError(adapterPath string, stderr []byte, version string) erro
 	return fmt.Errorf("external service adapter generated manifest with an incorrect version at %s. expected exact version but returned version: '%s', stderr: '%s'", adapterPath, version, stderr)
 }
 
+// runError is the outcome of running the adapter, as recorded on its span.
+func runError(err error, exitCode *int) error {
+	if err != nil {
+		return err
+	}
+	if exitCode != nil && *exitCode != 0 {
+		return fmt.Errorf("external service adapter exited with %d", *exitCode)
+	}
+	return nil
+}
+
 func adapterFailedMessage(exitCode int, adapterPath string, stdout, stderr []byte) string {
 	return fmt.Sprintf("external service adapter exited with %d at %s: stdout: '%s', stderr: '%s'", exitCode, adapterPath, stdout, stderr)
 }
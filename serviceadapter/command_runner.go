@@ -8,11 +8,16 @@ package serviceadapter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"os"
 	"os/exec"
 	"syscall"
+	"time"
 
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 func NewCommandRunner() CommandRunner {
@@ -23,13 +28,17 @@ type commandRunner struct {
 	inputParams sdk.InputParams
 }
 
-func (c commandRunner) Run(arg ...string) ([]byte, []byte, *int, error) {
-	cmd := exec.Command(arg[0], arg[1:]...)
-	return c.run(cmd)
+// killWaitDelay is how long to wait for the output of a killed adapter to be
+// closed, in case it was passed on to processes outside its process group.
+const killWaitDelay = 5 * time.Second
+
+func (c commandRunner) Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
+	cmd := command(ctx, arg...)
+	return c.run(ctx, cmd)
 }
 
-func (c commandRunner) RunWithInputParams(inputParams interface{}, arg ...string) ([]byte, []byte, *int, error) {
-	cmd := exec.Command(arg[0], arg[1:]...)
+func (c commandRunner) RunWithInputParams(ctx context.Context, inputParams interface{}, arg ...string) ([]byte, []byte, *int, error) {
+	cmd := command(ctx, arg...)
 
 	b := bytes.NewBuffer([]byte{})
 	err := json.NewEncoder(b).Encode(inputParams)
@@ -38,18 +47,35 @@ func (c commandRunner) RunWithInputParams(inputParams interface{}, arg ...string
 	}
 	cmd.Stdin = b
 
-	return c.run(cmd)
+	return c.run(ctx, cmd)
+}
+
+// command runs the adapter in a process group of its own, so that when ctx is
+// done the adapter is killed along with any processes it started. The adapter
+// is passed the traceparent of the span in ctx in its environment.
+func command(ctx context.Context, arg ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, arg[0], arg[1:]...)
+	cmd.Env = tracing.InjectEnv(ctx, os.Environ())
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = killWaitDelay
+	return cmd
 }
 
 func intPtr(val int) *int {
 	return &val
 }
 
-func (c commandRunner) run(cmd *exec.Cmd) ([]byte, []byte, *int, error) {
+func (c commandRunner) run(ctx context.Context, cmd *exec.Cmd) ([]byte, []byte, *int, error) {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
 	stdout, err := cmd.Output()
+	if ctx.Err() != nil && err != nil {
+		return stdout, stderr.Bytes(), nil, ctx.Err()
+	}
 
 	var exitCode *int
 
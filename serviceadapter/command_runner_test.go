@@ -7,15 +7,22 @@
 package serviceadapter_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 var _ = Describe("CommandRunner", func() {
@@ -50,7 +57,7 @@ var _ = Describe("CommandRunner", func() {
 		JustBeforeEach(func() {
 			runner := serviceadapter.NewCommandRunner()
 			var stdoutBytes, stderrBytes []byte
-			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.Run(scriptPath)
+			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.Run(context.Background(), scriptPath)
 			stdout = string(stdoutBytes)
 			stderr = string(stderrBytes)
 		})
@@ -133,6 +140,54 @@ var _ = Describe("CommandRunner", func() {
 		})
 	})
 
+	Describe("cancellation", func() {
+		var pidFile string
+
+		BeforeEach(func() {
+			pidFile = filepath.Join(os.TempDir(), fmt.Sprintf("cmd-child-%d.pid", rand.Int()))
+			scriptPath = createScript(fmt.Sprintf("sleep 60 & echo $! > %s; wait", pidFile))
+		})
+
+		AfterEach(func() {
+			os.Remove(scriptPath)
+			os.Remove(pidFile)
+		})
+
+		It("kills the command and the processes it started when the context is done", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			_, _, exitCode, err := serviceadapter.NewCommandRunner().Run(ctx, scriptPath)
+			Expect(err).To(Equal(context.DeadlineExceeded))
+			Expect(exitCode).To(BeNil())
+
+			pid, err := ioutil.ReadFile(pidFile)
+			Expect(err).NotTo(HaveOccurred())
+			childPid, err := strconv.Atoi(strings.TrimSpace(string(pid)))
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() bool { return isRunning(childPid) }).Should(BeFalse())
+		})
+	})
+
+	Describe("trace propagation", func() {
+		BeforeEach(func() {
+			scriptPath = createScript(`echo "$TRACEPARENT"`)
+		})
+
+		AfterEach(func() {
+			os.Remove(scriptPath)
+		})
+
+		It("passes the traceparent of the span in the context to the command", func() {
+			ctx, span := tracing.Start(context.Background(), "some-span")
+
+			stdout, _, _, err := serviceadapter.NewCommandRunner().Run(ctx, scriptPath)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(stdout)).To(Equal(tracing.Traceparent(span.SpanContext()) + "\n"))
+		})
+	})
+
 	Describe("RunWithInputParams", func() {
 		var inputParams interface{}
 
@@ -143,7 +198,7 @@ var _ = Describe("CommandRunner", func() {
 		JustBeforeEach(func() {
 			runner := serviceadapter.NewCommandRunner()
 			var stdoutBytes, stderrBytes []byte
-			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.RunWithInputParams(inputParams, scriptPath)
+			stdoutBytes, stderrBytes, actualExitCode, runErr = runner.RunWithInputParams(context.Background(), inputParams, scriptPath)
 			stdout = string(stdoutBytes)
 			stderr = string(stderrBytes)
 		})
@@ -214,3 +269,13 @@ func randSeq(n int) string {
 	}
 	return string(b)
 }
+
+// isRunning is false once a process has exited, even if it is yet to be reaped.
+func isRunning(pid int) bool {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
@@ -7,14 +7,20 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 func (c *Client) CreateBinding(
+	ctx context.Context,
 	bindingID string,
 	deploymentTopology bosh.BoshVMs,
 	manifest []byte,
@@ -48,6 +54,10 @@ func (c *Client) CreateBinding(
 	var stdout, stderr []byte
 	var exitCode *int
 
+	start := time.Now()
+	ctx, cancel := c.runContext(ctx, "create-binding")
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "serviceadapter.create-binding")
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			CreateBinding: sdk.CreateBindingJSONParams{
@@ -60,13 +70,14 @@ func (c *Client) CreateBinding(
 			},
 		}
 
-		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(inputParams, c.ExternalBinPath, "create-binding")
+		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(ctx, inputParams, c.ExternalBinPath, "create-binding")
 	} else {
-		stdout, stderr, exitCode, err = c.CommandRunner.Run(c.ExternalBinPath, "create-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
+		stdout, stderr, exitCode, err = c.CommandRunner.Run(ctx, c.ExternalBinPath, "create-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
 	}
+	span.End(runError(err, exitCode))
 
 	if err != nil {
-		return binding, adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return binding, c.runFailure(ctx, "create-binding", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -74,7 +85,7 @@ func (c *Client) CreateBinding(
 		return binding, err
 	}
 
-	logger.Printf("service adapter ran create-binding successfully, stderr logs: %s", string(stderr))
+	loggerfactory.WithDuration(logger, time.Since(start)).Printf("service adapter ran create-binding successfully, stderr logs: %s", string(stderr))
 
 	if err := json.Unmarshal(stdout, &binding); err != nil {
 		return binding, invalidJSONError(c.ExternalBinPath, stdout, stderr, err)
@@ -7,13 +7,18 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) GenerateDashboardUrl(instanceID string, plan sdk.Plan, manifest []byte, logger *log.Logger) (string, error) {
+func (c *Client) GenerateDashboardUrl(ctx context.Context, instanceID string, plan sdk.Plan, manifest []byte, logger *log.Logger) (string, error) {
 	plan.Properties = SanitiseForJSON(plan.Properties)
 	planJSON, err := json.Marshal(plan)
 	if err != nil {
@@ -23,6 +28,10 @@ func (c *Client) GenerateDashboardUrl(instanceID string, plan sdk.Plan, manifest
 	var stdout, stderr []byte
 	var exitCode *int
 
+	start := time.Now()
+	ctx, cancel := c.runContext(ctx, "dashboard-url")
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "serviceadapter.dashboard-url")
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			DashboardUrl: sdk.DashboardUrlJSONParams{
@@ -33,12 +42,14 @@ func (c *Client) GenerateDashboardUrl(instanceID string, plan sdk.Plan, manifest
 		}
 
 		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(
+			ctx,
 			inputParams,
 			c.ExternalBinPath,
 			"dashboard-url",
 		)
 	} else {
 		stdout, stderr, exitCode, err = c.CommandRunner.Run(
+			ctx,
 			c.ExternalBinPath,
 			"dashboard-url",
 			instanceID,
@@ -46,9 +57,10 @@ func (c *Client) GenerateDashboardUrl(instanceID string, plan sdk.Plan, manifest
 			string(manifest),
 		)
 	}
+	span.End(runError(err, exitCode))
 
 	if err != nil {
-		return "", adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return "", c.runFailure(ctx, "dashboard-url", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -56,7 +68,7 @@ func (c *Client) GenerateDashboardUrl(instanceID string, plan sdk.Plan, manifest
 		return "", err
 	}
 
-	logger.Printf("service adapter ran dashboard-url successfully, stderr logs: %s", string(stderr))
+	loggerfactory.WithDuration(logger, time.Since(start)).Printf("service adapter ran dashboard-url successfully, stderr logs: %s", string(stderr))
 
 	dashboardURL := sdk.DashboardUrl{}
 
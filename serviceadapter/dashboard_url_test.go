@@ -7,10 +7,12 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"log"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -68,14 +70,14 @@ var _ = Describe("dashboard url", func() {
 	})
 
 	JustBeforeEach(func() {
-		actualDashboardUrl, actualError = a.GenerateDashboardUrl(instanceID, plan, manifest, logger)
+		actualDashboardUrl, actualError = a.GenerateDashboardUrl(context.Background(), instanceID, plan, manifest, logger)
 	})
 	Context("when stdin is not set", func() {
 		It("invokes external dashboard url generator with serialised params", func() {
 			Expect(cmdRunner.RunCallCount()).To(Equal(1))
 			planJson, err := json.Marshal(plan)
 			Expect(err).NotTo(HaveOccurred())
-			argsPassed := cmdRunner.RunArgsForCall(0)
+			_, argsPassed := cmdRunner.RunArgsForCall(0)
 			Expect(argsPassed).To(ConsistOf(externalBinPath, "dashboard-url", instanceID, string(planJson), string(manifest)))
 		})
 
@@ -106,7 +108,7 @@ var _ = Describe("dashboard url", func() {
 			It("converts plan properties to be json serializable", func() {
 				Expect(actualError).NotTo(HaveOccurred())
 				Expect(cmdRunner.RunCallCount()).To(Equal(1))
-				argsPassed := cmdRunner.RunArgsForCall(0)
+				_, argsPassed := cmdRunner.RunArgsForCall(0)
 
 				convertedPlan := sdk.Plan{
 					Properties: sdk.Properties{
@@ -151,6 +153,36 @@ var _ = Describe("dashboard url", func() {
 			})
 		})
 
+		Context("when the subcommand has a timeout", func() {
+			BeforeEach(func() {
+				a.Timeouts = map[string]time.Duration{"dashboard-url": 10 * time.Millisecond}
+				cmdRunner.RunStub = func(ctx context.Context, _ ...string) ([]byte, []byte, *int, error) {
+					<-ctx.Done()
+					return nil, nil, nil, ctx.Err()
+				}
+			})
+
+			It("returns a timeout error naming the subcommand", func() {
+				Expect(actualError).To(Equal(serviceadapter.TimeoutError{Subcommand: "dashboard-url", Timeout: 10 * time.Millisecond}))
+				Expect(actualError).To(MatchError("service adapter timed out running dashboard-url after 10ms"))
+			})
+
+			When("the adapter finishes in time", func() {
+				BeforeEach(func() {
+					a.Timeouts = map[string]time.Duration{"dashboard-url": time.Minute}
+					cmdRunner.RunStub = nil
+				})
+
+				It("runs the adapter with the timeout", func() {
+					ctx, _ := cmdRunner.RunArgsForCall(0)
+					deadline, ok := ctx.Deadline()
+					Expect(ok).To(BeTrue())
+					Expect(deadline).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+					Expect(actualError).NotTo(HaveOccurred())
+				})
+			})
+		})
+
 		Context("when the external service adapter fails", func() {
 			Context("when there is a operator error message and a user error message", func() {
 				BeforeEach(func() {
@@ -191,7 +223,7 @@ var _ = Describe("dashboard url", func() {
 			By("invoking the handler")
 			Expect(cmdRunner.RunCallCount()).To(Equal(0))
 			Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
-			inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+			_, inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
 			Expect(inputParams.(sdk.InputParams)).To(Equal(sdk.InputParams{
 				DashboardUrl: sdk.DashboardUrlJSONParams{
 					InstanceId: instanceID, Plan: string(planJson), Manifest: string(manifest),
@@ -224,7 +256,7 @@ var _ = Describe("dashboard url", func() {
 			It("converts plan properties to be json serializable", func() {
 				Expect(actualError).NotTo(HaveOccurred())
 				Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
-				inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+				_, inputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
 
 				convertedPlan := sdk.Plan{
 					Properties: sdk.Properties{
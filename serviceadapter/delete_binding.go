@@ -7,14 +7,20 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 func (c *Client) DeleteBinding(
+	ctx context.Context,
 	bindingID string,
 	deploymentTopology bosh.BoshVMs,
 	manifest []byte,
@@ -46,6 +52,10 @@ func (c *Client) DeleteBinding(
 	var stdout, stderr []byte
 	var exitCode *int
 
+	start := time.Now()
+	ctx, cancel := c.runContext(ctx, "delete-binding")
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "serviceadapter.delete-binding")
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			DeleteBinding: sdk.DeleteBindingJSONParams{
@@ -57,13 +67,14 @@ func (c *Client) DeleteBinding(
 				DNSAddresses:      string(serialisedDNSAddresses),
 			},
 		}
-		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(inputParams, c.ExternalBinPath, "delete-binding")
+		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(ctx, inputParams, c.ExternalBinPath, "delete-binding")
 	} else {
-		stdout, stderr, exitCode, err = c.CommandRunner.Run(c.ExternalBinPath, "delete-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
+		stdout, stderr, exitCode, err = c.CommandRunner.Run(ctx, c.ExternalBinPath, "delete-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
 	}
+	span.End(runError(err, exitCode))
 
 	if err != nil {
-		return adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return c.runFailure(ctx, "delete-binding", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -71,6 +82,6 @@ func (c *Client) DeleteBinding(
 		return err
 	}
 
-	logger.Printf("service adapter ran delete-binding successfully, stderr logs: %s", string(stderr))
+	loggerfactory.WithDuration(logger, time.Since(start)).Printf("service adapter ran delete-binding successfully, stderr logs: %s", string(stderr))
 	return nil
 }
@@ -2,16 +2,18 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 type FakeCommandRunner struct {
-	RunStub        func(...string) ([]byte, []byte, *int, error)
+	RunStub        func(context.Context, ...string) ([]byte, []byte, *int, error)
 	runMutex       sync.RWMutex
 	runArgsForCall []struct {
-		arg1 []string
+		arg1 context.Context
+		arg2 []string
 	}
 	runReturns struct {
 		result1 []byte
@@ -25,11 +27,12 @@ type FakeCommandRunner struct {
 		result3 *int
 		result4 error
 	}
-	RunWithInputParamsStub        func(interface{}, ...string) ([]byte, []byte, *int, error)
+	RunWithInputParamsStub        func(context.Context, interface{}, ...string) ([]byte, []byte, *int, error)
 	runWithInputParamsMutex       sync.RWMutex
 	runWithInputParamsArgsForCall []struct {
-		arg1 interface{}
-		arg2 []string
+		arg1 context.Context
+		arg2 interface{}
+		arg3 []string
 	}
 	runWithInputParamsReturns struct {
 		result1 []byte
@@ -47,18 +50,19 @@ type FakeCommandRunner struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeCommandRunner) Run(arg1 ...string) ([]byte, []byte, *int, error) {
+func (fake *FakeCommandRunner) Run(arg1 context.Context, arg2 ...string) ([]byte, []byte, *int, error) {
 	fake.runMutex.Lock()
 	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
 	fake.runArgsForCall = append(fake.runArgsForCall, struct {
-		arg1 []string
-	}{arg1})
+		arg1 context.Context
+		arg2 []string
+	}{arg1, arg2})
 	stub := fake.RunStub
 	fakeReturns := fake.runReturns
-	fake.recordInvocation("Run", []interface{}{arg1})
+	fake.recordInvocation("Run", []interface{}{arg1, arg2})
 	fake.runMutex.Unlock()
 	if stub != nil {
-		return stub(arg1...)
+		return stub(arg1, arg2...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -72,17 +76,17 @@ func (fake *FakeCommandRunner) RunCallCount() int {
 	return len(fake.runArgsForCall)
 }
 
-func (fake *FakeCommandRunner) RunCalls(stub func(...string) ([]byte, []byte, *int, error)) {
+func (fake *FakeCommandRunner) RunCalls(stub func(context.Context, ...string) ([]byte, []byte, *int, error)) {
 	fake.runMutex.Lock()
 	defer fake.runMutex.Unlock()
 	fake.RunStub = stub
 }
 
-func (fake *FakeCommandRunner) RunArgsForCall(i int) []string {
+func (fake *FakeCommandRunner) RunArgsForCall(i int) (context.Context, []string) {
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
 	argsForCall := fake.runArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeCommandRunner) RunReturns(result1 []byte, result2 []byte, result3 *int, result4 error) {
@@ -117,19 +121,20 @@ func (fake *FakeCommandRunner) RunReturnsOnCall(i int, result1 []byte, result2 [
 	}{result1, result2, result3, result4}
 }
 
-func (fake *FakeCommandRunner) RunWithInputParams(arg1 interface{}, arg2 ...string) ([]byte, []byte, *int, error) {
+func (fake *FakeCommandRunner) RunWithInputParams(arg1 context.Context, arg2 interface{}, arg3 ...string) ([]byte, []byte, *int, error) {
 	fake.runWithInputParamsMutex.Lock()
 	ret, specificReturn := fake.runWithInputParamsReturnsOnCall[len(fake.runWithInputParamsArgsForCall)]
 	fake.runWithInputParamsArgsForCall = append(fake.runWithInputParamsArgsForCall, struct {
-		arg1 interface{}
-		arg2 []string
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 interface{}
+		arg3 []string
+	}{arg1, arg2, arg3})
 	stub := fake.RunWithInputParamsStub
 	fakeReturns := fake.runWithInputParamsReturns
-	fake.recordInvocation("RunWithInputParams", []interface{}{arg1, arg2})
+	fake.recordInvocation("RunWithInputParams", []interface{}{arg1, arg2, arg3})
 	fake.runWithInputParamsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2...)
+		return stub(arg1, arg2, arg3...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -143,17 +148,17 @@ func (fake *FakeCommandRunner) RunWithInputParamsCallCount() int {
 	return len(fake.runWithInputParamsArgsForCall)
 }
 
-func (fake *FakeCommandRunner) RunWithInputParamsCalls(stub func(interface{}, ...string) ([]byte, []byte, *int, error)) {
+func (fake *FakeCommandRunner) RunWithInputParamsCalls(stub func(context.Context, interface{}, ...string) ([]byte, []byte, *int, error)) {
 	fake.runWithInputParamsMutex.Lock()
 	defer fake.runWithInputParamsMutex.Unlock()
 	fake.RunWithInputParamsStub = stub
 }
 
-func (fake *FakeCommandRunner) RunWithInputParamsArgsForCall(i int) (interface{}, []string) {
+func (fake *FakeCommandRunner) RunWithInputParamsArgsForCall(i int) (context.Context, interface{}, []string) {
 	fake.runWithInputParamsMutex.RLock()
 	defer fake.runWithInputParamsMutex.RUnlock()
 	argsForCall := fake.runWithInputParamsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeCommandRunner) RunWithInputParamsReturns(result1 []byte, result2 []byte, result3 *int, result4 error) {
@@ -7,12 +7,17 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"strings"
+	"time"
 
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 	"gopkg.in/yaml.v2"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 type manifest struct {
@@ -30,6 +35,7 @@ type manifestValidator struct {
 }
 
 func (c *Client) GenerateManifest(
+	ctx context.Context,
 	serviceDeployment sdk.ServiceDeployment,
 	plan sdk.Plan,
 	requestParams map[string]interface{},
@@ -83,6 +89,10 @@ func (c *Client) GenerateManifest(
 	var exitCode *int
 	var jsonErr error
 
+	start := time.Now()
+	ctx, cancel := c.runContext(ctx, "generate-manifest")
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "serviceadapter.generate-manifest")
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			GenerateManifest: sdk.GenerateManifestJSONParams{
@@ -97,19 +107,22 @@ func (c *Client) GenerateManifest(
 			},
 		}
 		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(
+			ctx,
 			inputParams,
 			c.ExternalBinPath, "generate-manifest",
 		)
 	} else {
 		stdout, stderr, exitCode, err = c.CommandRunner.Run(
+			ctx,
 			c.ExternalBinPath, "generate-manifest",
 			string(serialisedServiceDeployment),
 			string(serialisedPlan), string(serialisedRequestParams),
 			string(previousManifest), string(serialisedPreviousPlan),
 		)
 	}
+	span.End(runError(err, exitCode))
 	if err != nil {
-		return sdk.MarshalledGenerateManifest{}, adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return sdk.MarshalledGenerateManifest{}, c.runFailure(ctx, "generate-manifest", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -125,7 +138,7 @@ func (c *Client) GenerateManifest(
 		output = sdk.MarshalledGenerateManifest{Manifest: string(stdout)}
 	}
 
-	logger.Printf("service adapter ran generate-manifest successfully, stderr logs: %s", string(stderr))
+	loggerfactory.WithDuration(logger, time.Since(start)).Printf("service adapter ran generate-manifest successfully, stderr logs: %s", string(stderr))
 
 	validator := manifestValidator{deploymentName: serviceDeployment.DeploymentName}
 	if err := validator.validateManifest(c.ExternalBinPath, output.Manifest, stderr); err != nil {
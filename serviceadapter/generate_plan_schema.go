@@ -7,14 +7,19 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
-func (c *Client) GeneratePlanSchema(plan sdk.Plan, logger *log.Logger) (domain.ServiceSchemas, error) {
+func (c *Client) GeneratePlanSchema(ctx context.Context, plan sdk.Plan, logger *log.Logger) (domain.ServiceSchemas, error) {
 	var stdout, stderr []byte
 	var exitCode *int
 	var err error
@@ -25,21 +30,27 @@ func (c *Client) GeneratePlanSchema(plan sdk.Plan, logger *log.Logger) (domain.S
 		return domain.ServiceSchemas{}, err
 	}
 
+	start := time.Now()
+	ctx, cancel := c.runContext(ctx, "generate-plan-schemas")
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "serviceadapter.generate-plan-schema")
 	if c.UsingStdin {
 		inputParams := sdk.InputParams{
 			GeneratePlanSchemas: sdk.GeneratePlanSchemasJSONParams{
 				Plan: string(serialisedPlan),
 			},
 		}
-		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(inputParams, c.ExternalBinPath, "generate-plan-schemas")
+		stdout, stderr, exitCode, err = c.CommandRunner.RunWithInputParams(ctx, inputParams, c.ExternalBinPath, "generate-plan-schemas")
 	} else {
 		stdout, stderr, exitCode, err = c.CommandRunner.Run(
+			ctx,
 			c.ExternalBinPath, "generate-plan-schemas", "--plan-json", string(serialisedPlan),
 		)
 	}
+	span.End(runError(err, exitCode))
 
 	if err != nil {
-		return domain.ServiceSchemas{}, adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return domain.ServiceSchemas{}, c.runFailure(ctx, "generate-plan-schemas", stdout, stderr, err)
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -47,7 +58,7 @@ func (c *Client) GeneratePlanSchema(plan sdk.Plan, logger *log.Logger) (domain.S
 		return domain.ServiceSchemas{}, err
 	}
 
-	logger.Printf("service adapter ran generate-plan-schema successfully, stderr logs: %s", string(stderr))
+	loggerfactory.WithDuration(logger, time.Since(start)).Printf("service adapter ran generate-plan-schema successfully, stderr logs: %s", string(stderr))
 
 	var schemas domain.ServiceSchemas
 	err = json.Unmarshal(stdout, &schemas)
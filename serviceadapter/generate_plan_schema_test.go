@@ -16,6 +16,7 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -73,7 +74,7 @@ var _ = Describe("GeneratePlanSchema", func() {
 	})
 
 	JustBeforeEach(func() {
-		actualPlanSchemas, actualError = a.GeneratePlanSchema(plan, logger)
+		actualPlanSchemas, actualError = a.GeneratePlanSchema(context.Background(), plan, logger)
 	})
 
 	When("UsingStdin is set to false", func() {
@@ -83,7 +84,7 @@ var _ = Describe("GeneratePlanSchema", func() {
 			Expect(cmdRunner.RunCallCount()).To(Equal(1))
 			planJson, err := json.Marshal(plan)
 			Expect(err).NotTo(HaveOccurred())
-			argsPassed := cmdRunner.RunArgsForCall(0)
+			_, argsPassed := cmdRunner.RunArgsForCall(0)
 			Expect(argsPassed).To(ConsistOf(externalBinPath, "generate-plan-schemas", "--plan-json", string(planJson)))
 		})
 
@@ -112,7 +113,7 @@ var _ = Describe("GeneratePlanSchema", func() {
 			It("converts plan properties to be json serializable", func() {
 				Expect(actualError).NotTo(HaveOccurred())
 				Expect(cmdRunner.RunCallCount()).To(Equal(1))
-				argsPassed := cmdRunner.RunArgsForCall(0)
+				_, argsPassed := cmdRunner.RunArgsForCall(0)
 
 				convertedPlan := sdk.Plan{
 					Properties: sdk.Properties{
@@ -201,7 +202,7 @@ var _ = Describe("GeneratePlanSchema", func() {
 					Plan: toJson(plan),
 				},
 			}
-			actualInputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
+			_, actualInputParams, argsPassed := cmdRunner.RunWithInputParamsArgsForCall(0)
 			Expect(argsPassed).To(ConsistOf(
 				externalBinPath,
 				"generate-plan-schemas",
@@ -234,7 +235,7 @@ var _ = Describe("GeneratePlanSchema", func() {
 			It("converts plan properties to be json serializable", func() {
 				Expect(actualError).NotTo(HaveOccurred())
 				Expect(cmdRunner.RunWithInputParamsCallCount()).To(Equal(1))
-				actualInputParams, _ := cmdRunner.RunWithInputParamsArgsForCall(0)
+				_, actualInputParams, _ := cmdRunner.RunWithInputParamsArgsForCall(0)
 				castInputParams, ok := actualInputParams.(sdk.InputParams)
 				Expect(ok).To(BeTrue(), "Couldn't cast interface{} back to InputParams")
 
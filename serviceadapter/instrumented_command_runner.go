@@ -0,0 +1,48 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+)
+
+// NewInstrumentedCommandRunner records the duration and exit code of every
+// adapter invocation made through runner.
+func NewInstrumentedCommandRunner(runner CommandRunner, registry *metrics.Registry) CommandRunner {
+	return instrumentedCommandRunner{runner: runner, registry: registry}
+}
+
+type instrumentedCommandRunner struct {
+	runner   CommandRunner
+	registry *metrics.Registry
+}
+
+func (c instrumentedCommandRunner) Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
+	start := time.Now()
+	stdout, stderr, exitCode, err := c.runner.Run(ctx, arg...)
+	c.registry.ObserveAdapterCall(adapterCommand(arg), exitCode, time.Since(start))
+	return stdout, stderr, exitCode, err
+}
+
+func (c instrumentedCommandRunner) RunWithInputParams(ctx context.Context, inputParams interface{}, arg ...string) ([]byte, []byte, *int, error) {
+	start := time.Now()
+	stdout, stderr, exitCode, err := c.runner.RunWithInputParams(ctx, inputParams, arg...)
+	c.registry.ObserveAdapterCall(adapterCommand(arg), exitCode, time.Since(start))
+	return stdout, stderr, exitCode, err
+}
+
+// adapterCommand returns the adapter subcommand, such as generate-manifest.
+// The first argument is the path of the adapter executable.
+func adapterCommand(arg []string) string {
+	if len(arg) < 2 {
+		return "unknown"
+	}
+	return arg[1]
+}
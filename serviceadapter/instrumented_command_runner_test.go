@@ -0,0 +1,73 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/metrics"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter/fakes"
+)
+
+var _ = Describe("InstrumentedCommandRunner", func() {
+	var (
+		fakeRunner *fakes.FakeCommandRunner
+		registry   *metrics.Registry
+		runner     serviceadapter.CommandRunner
+	)
+
+	adapterCalls := func() []metrics.Sample {
+		for _, family := range registry.Families() {
+			if family.Name == metrics.AdapterCallsName {
+				return family.Samples
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		fakeRunner = new(fakes.FakeCommandRunner)
+		registry = metrics.NewRegistry()
+		runner = serviceadapter.NewInstrumentedCommandRunner(fakeRunner, registry)
+	})
+
+	It("delegates to the wrapped runner and records the exit code", func() {
+		fakeRunner.RunReturns([]byte("stdout"), []byte("stderr"), intPtr(10), nil)
+
+		stdout, stderr, exitCode, err := runner.Run(context.Background(), "/path/to/adapter", "generate-manifest", "arg")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(stdout)).To(Equal("stdout"))
+		Expect(string(stderr)).To(Equal("stderr"))
+		Expect(*exitCode).To(Equal(10))
+		_, args := fakeRunner.RunArgsForCall(0)
+		Expect(args).To(Equal([]string{"/path/to/adapter", "generate-manifest", "arg"}))
+
+		Expect(adapterCalls()).To(ConsistOf(metrics.Sample{
+			Name:   metrics.AdapterCallsName,
+			Labels: metrics.Labels{"command": "generate-manifest", "exit_code": "10"},
+			Value:  1,
+		}))
+	})
+
+	It("records invocations that could not be run", func() {
+		fakeRunner.RunWithInputParamsReturns(nil, nil, nil, errors.New("no such file"))
+
+		_, _, _, err := runner.RunWithInputParams(context.Background(), "params", "/path/to/adapter", "create-binding")
+		Expect(err).To(MatchError("no such file"))
+
+		Expect(adapterCalls()).To(ConsistOf(metrics.Sample{
+			Name:   metrics.AdapterCallsName,
+			Labels: metrics.Labels{"command": "create-binding", "exit_code": metrics.NoExitCode},
+			Value:  1,
+		}))
+	})
+})
@@ -0,0 +1,112 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+)
+
+// AdapterServerRunPath is where an adapter server accepts adapter calls.
+//
+// Each call is a POST of a RunRequest, holding the arguments the adapter
+// would be executed with, without the path of the executable, and the input
+// params it would read from stdin. The server responds with 200 and a
+// RunResponse holding what the adapter would have written to stdout and stderr
+// and the code it would have exited with. Any other status means the call
+// could not be made, as when the adapter cannot be executed.
+const AdapterServerRunPath = "/v1/run"
+
+type RunRequest struct {
+	Args        []string        `json:"args"`
+	InputParams json.RawMessage `json:"input_params,omitempty"`
+}
+
+type RunResponse struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// NewServerCommandRunner returns a CommandRunner that sends adapter calls to
+// an adapter server listening on a unix socket, rather than executing the
+// adapter for every call.
+func NewServerCommandRunner(socketPath string) CommandRunner {
+	return serverCommandRunner{
+		socketPath: socketPath,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+type serverCommandRunner struct {
+	socketPath string
+	client     *http.Client
+}
+
+func (s serverCommandRunner) Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
+	return s.run(ctx, RunRequest{Args: arg[1:]})
+}
+
+func (s serverCommandRunner) RunWithInputParams(ctx context.Context, inputParams interface{}, arg ...string) ([]byte, []byte, *int, error) {
+	params, err := json.Marshal(inputParams)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s.run(ctx, RunRequest{Args: arg[1:], InputParams: params})
+}
+
+func (s serverCommandRunner) run(ctx context.Context, runRequest RunRequest) ([]byte, []byte, *int, error) {
+	body, err := json.Marshal(runRequest)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// the host is ignored, as connections are made to the socket
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://adapter"+AdapterServerRunPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, req.Header)
+
+	res, err := s.client.Do(req)
+	if ctx.Err() != nil {
+		if err == nil {
+			res.Body.Close()
+		}
+		return nil, nil, nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error calling adapter server at %s: %s", s.socketPath, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(res.Body)
+		return nil, nil, nil, fmt.Errorf("adapter server at %s responded with status %d: %s", s.socketPath, res.StatusCode, message)
+	}
+
+	var runResponse RunResponse
+	if err := json.NewDecoder(res.Body).Decode(&runResponse); err != nil {
+		return nil, nil, nil, fmt.Errorf("error parsing response of adapter server at %s: %s", s.socketPath, err)
+	}
+
+	return []byte(runResponse.Stdout), []byte(runResponse.Stderr), intPtr(runResponse.ExitCode), nil
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+)
+
+type stubAdapter struct {
+	dashboardURL string
+	err          error
+	block        chan struct{}
+}
+
+func (s stubAdapter) DashboardUrl(params sdk.DashboardUrlParams) (sdk.DashboardUrl, error) {
+	if s.block != nil {
+		<-s.block
+	}
+	return sdk.DashboardUrl{DashboardUrl: s.dashboardURL + "/" + params.InstanceID}, s.err
+}
+
+func (s stubAdapter) CreateBinding(params sdk.CreateBindingParams) (sdk.Binding, error) {
+	return sdk.Binding{}, s.err
+}
+
+func (s stubAdapter) DeleteBinding(params sdk.DeleteBindingParams) error {
+	return s.err
+}
+
+var _ = Describe("adapter server transport", func() {
+	var (
+		socketPath string
+		server     *http.Server
+		adapter    stubAdapter
+		client     *serviceadapter.Client
+		logger     *log.Logger
+		plan       sdk.Plan
+	)
+
+	serve := func(handler http.Handler) {
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).NotTo(HaveOccurred())
+		server = &http.Server{Handler: handler}
+		go server.Serve(listener)
+	}
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "adapter")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, dir)
+		socketPath = filepath.Join(dir, "adapter.sock")
+
+		adapter = stubAdapter{dashboardURL: "https://dashboard.example.com"}
+		logger = log.New(GinkgoWriter, "[unit-tests] ", log.LstdFlags)
+		plan = sdk.Plan{InstanceGroups: []sdk.InstanceGroup{{
+			Name: "redis", VMType: "small", Instances: 1, Networks: []string{"default"}, AZs: []string{"z1"},
+		}}}
+		server = nil
+	})
+
+	AfterEach(func() {
+		if server != nil {
+			server.Close()
+		}
+	})
+
+	newClient := func(usingStdin bool) *serviceadapter.Client {
+		return &serviceadapter.Client{
+			ExternalBinPath: "unix://" + socketPath,
+			CommandRunner:   serviceadapter.NewServerCommandRunner(socketPath),
+			UsingStdin:      usingStdin,
+		}
+	}
+
+	JustBeforeEach(func() {
+		serve(serviceadapter.NewAdapterServer(serviceadapter.SDKHandler(sdk.CommandLineHandler{
+			DashboardURLGenerator: adapter,
+			Binder:                adapter,
+		})))
+	})
+
+	It("calls the adapter with input params", func() {
+		client = newClient(true)
+
+		url, err := client.GenerateDashboardUrl(context.Background(), "some-instance", plan, []byte("name: some-deployment"), logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("https://dashboard.example.com/some-instance"))
+	})
+
+	It("calls the adapter with arguments", func() {
+		client = newClient(false)
+
+		url, err := client.GenerateDashboardUrl(context.Background(), "some-instance", plan, []byte("name: some-deployment"), logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("https://dashboard.example.com/some-instance"))
+	})
+
+	Context("when the adapter fails", func() {
+		BeforeEach(func() {
+			adapter.err = sdk.NewBindingNotFoundError(errors.New("no such binding"))
+		})
+
+		It("reports the exit code as an executed adapter would", func() {
+			client = newClient(true)
+
+			err := client.DeleteBinding(context.Background(), "some-binding", bosh.BoshVMs{}, []byte("name: some-deployment"), nil, nil, nil, logger)
+
+			Expect(err).To(BeAssignableToTypeOf(serviceadapter.BindingNotFoundError{}))
+		})
+	})
+
+	Context("when the call is abandoned", func() {
+		BeforeEach(func() {
+			block := make(chan struct{})
+			DeferCleanup(func() { close(block) })
+			adapter.block = block
+		})
+
+		It("returns without waiting for the adapter", func() {
+			client = newClient(true)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			_, err := client.GenerateDashboardUrl(ctx, "some-instance", plan, []byte("name: some-deployment"), logger)
+
+			Expect(err).To(BeAssignableToTypeOf(serviceadapter.TimeoutError{}))
+		})
+	})
+
+	It("reports a subcommand the adapter does not implement", func() {
+		client = newClient(true)
+
+		_, err := client.GeneratePlanSchema(context.Background(), plan, logger)
+
+		Expect(err).To(BeAssignableToTypeOf(serviceadapter.NotImplementedError{}))
+	})
+
+	It("reports an unknown subcommand as a failure", func() {
+		runner := serviceadapter.NewServerCommandRunner(socketPath)
+
+		_, stderr, exitCode, err := runner.Run(context.Background(), "unix://"+socketPath, "launch-rockets")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*exitCode).To(Equal(sdk.ErrorExitCode))
+		Expect(string(stderr)).To(ContainSubstring("unknown subcommand"))
+	})
+})
+
+var _ = Describe("server command runner", func() {
+	var socketPath string
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "adapter")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, dir)
+		socketPath = filepath.Join(dir, "adapter.sock")
+	})
+
+	It("fails when the adapter server is not running", func() {
+		_, _, exitCode, err := serviceadapter.NewServerCommandRunner(socketPath).Run(context.Background(), "adapter", "dashboard-url")
+
+		Expect(err).To(MatchError(ContainSubstring("error calling adapter server at " + socketPath)))
+		Expect(exitCode).To(BeNil())
+	})
+
+	It("sends the traceparent of the span in the context", func() {
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).NotTo(HaveOccurred())
+		traceparents := make(chan string, 1)
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparents <- r.Header.Get("traceparent")
+			w.Write([]byte(`{"stdout":"","stderr":"","exit_code":0}`))
+		})}
+		go server.Serve(listener)
+		defer server.Close()
+		ctx, span := tracing.Start(context.Background(), "some-span")
+
+		_, _, _, err = serviceadapter.NewServerCommandRunner(socketPath).Run(ctx, "adapter", "dashboard-url")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(traceparents).To(Receive(Equal(tracing.Traceparent(span.SpanContext()))))
+	})
+
+	It("cancels the call on the adapter server when its context is cancelled", func() {
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).NotTo(HaveOccurred())
+		cancelled := make(chan error, 1)
+		server := &http.Server{Handler: serviceadapter.NewAdapterServer(serviceadapter.HandlerFunc(
+			func(ctx context.Context, _ []string, _, _ io.Writer, _ io.Reader) error {
+				<-ctx.Done()
+				cancelled <- ctx.Err()
+				return ctx.Err()
+			},
+		))}
+		go server.Serve(listener)
+		defer server.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		_, _, exitCode, err := serviceadapter.NewServerCommandRunner(socketPath).Run(ctx, "adapter", "dashboard-url")
+
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(exitCode).To(BeNil())
+		Eventually(cancelled).Should(Receive(MatchError(context.Canceled)))
+	})
+
+	It("fails when the adapter server cannot make the call", func() {
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).NotTo(HaveOccurred())
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "adapter crashed", http.StatusInternalServerError)
+		})}
+		go server.Serve(listener)
+		defer server.Close()
+
+		_, _, exitCode, err := serviceadapter.NewServerCommandRunner(socketPath).Run(context.Background(), "adapter", "dashboard-url")
+
+		Expect(err).To(MatchError(ContainSubstring("responded with status 500: adapter crashed")))
+		Expect(exitCode).To(BeNil())
+	})
+})
@@ -16,6 +16,7 @@
 package startupchecker
 
 import (
+	"context"
 	"fmt"
 	"log"
 )
@@ -33,7 +34,7 @@ func NewBOSHAuthChecker(authVerifier AuthVerifier, logger *log.Logger) *BOSHAuth
 }
 
 func (c *BOSHAuthChecker) Check() error {
-	err := c.authVerifier.VerifyAuth(c.logger)
+	err := c.authVerifier.VerifyAuth(context.Background(), c.logger)
 	if err != nil {
 		return fmt.Errorf("BOSH Director error: %s", err.Error())
 	}
@@ -42,5 +43,5 @@ func (c *BOSHAuthChecker) Check() error {
 
 //counterfeiter:generate -o fakes/fake_auth_verifier.go . AuthVerifier
 type AuthVerifier interface {
-	VerifyAuth(*log.Logger) error
+	VerifyAuth(context.Context, *log.Logger) error
 }
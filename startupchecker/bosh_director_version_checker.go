@@ -55,7 +55,7 @@ func (c *BOSHDirectorVersionChecker) Check() error {
 	if c.brokerConfig.HasBindingWithDNSConfigured() && !c.directorVersionSufficientForBindingWithDNS(directorVersion) {
 		return fmt.Errorf("%sAPI version for 'binding_with_dns' feature is insufficient. This feature requires BOSH v266.12+ / v267.6+ (got v%s)", errPrefix, directorVersion.Version)
 	}
-	if c.brokerConfig.ServiceCatalog.HasLifecycleErrands() && !c.directorVersionSufficientForLifecycleErrands(directorVersion) {
+	if c.brokerConfig.HasLifecycleErrands() && !c.directorVersionSufficientForLifecycleErrands(directorVersion) {
 		return fmt.Errorf(
 			"%sAPI version is insufficient, one or more plans are configured with lifecycle_errands which require BOSH v%d+.",
 			errPrefix,
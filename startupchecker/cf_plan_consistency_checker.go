@@ -16,6 +16,7 @@
 package startupchecker
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -38,7 +39,7 @@ func NewCFPlanConsistencyChecker(cfClient ServiceInstanceCounter, serviceOfferin
 }
 
 func (c *CFPlanConsistencyChecker) Check() error {
-	instanceCountByPlanID, err := c.cfClient.CountInstancesOfServiceOffering(c.serviceOffering.ID, c.logger)
+	instanceCountByPlanID, err := c.cfClient.CountInstancesOfServiceOffering(context.Background(), c.serviceOffering.ID, c.logger)
 	if err != nil {
 		return err
 	}
@@ -60,5 +61,5 @@ func (c *CFPlanConsistencyChecker) Check() error {
 
 //counterfeiter:generate -o fakes/fake_service_instance_counter.go . ServiceInstanceCounter
 type ServiceInstanceCounter interface {
-	CountInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
+	CountInstancesOfServiceOffering(ctx context.Context, serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[cf.ServicePlan]int, err error)
 }
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -9,10 +10,11 @@ import (
 )
 
 type FakeAuthVerifier struct {
-	VerifyAuthStub        func(*log.Logger) error
+	VerifyAuthStub        func(context.Context, *log.Logger) error
 	verifyAuthMutex       sync.RWMutex
 	verifyAuthArgsForCall []struct {
-		arg1 *log.Logger
+		arg1 context.Context
+		arg2 *log.Logger
 	}
 	verifyAuthReturns struct {
 		result1 error
@@ -24,18 +26,19 @@ type FakeAuthVerifier struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeAuthVerifier) VerifyAuth(arg1 *log.Logger) error {
+func (fake *FakeAuthVerifier) VerifyAuth(arg1 context.Context, arg2 *log.Logger) error {
 	fake.verifyAuthMutex.Lock()
 	ret, specificReturn := fake.verifyAuthReturnsOnCall[len(fake.verifyAuthArgsForCall)]
 	fake.verifyAuthArgsForCall = append(fake.verifyAuthArgsForCall, struct {
-		arg1 *log.Logger
-	}{arg1})
+		arg1 context.Context
+		arg2 *log.Logger
+	}{arg1, arg2})
 	stub := fake.VerifyAuthStub
 	fakeReturns := fake.verifyAuthReturns
-	fake.recordInvocation("VerifyAuth", []interface{}{arg1})
+	fake.recordInvocation("VerifyAuth", []interface{}{arg1, arg2})
 	fake.verifyAuthMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -49,17 +52,17 @@ func (fake *FakeAuthVerifier) VerifyAuthCallCount() int {
 	return len(fake.verifyAuthArgsForCall)
 }
 
-func (fake *FakeAuthVerifier) VerifyAuthCalls(stub func(*log.Logger) error) {
+func (fake *FakeAuthVerifier) VerifyAuthCalls(stub func(context.Context, *log.Logger) error) {
 	fake.verifyAuthMutex.Lock()
 	defer fake.verifyAuthMutex.Unlock()
 	fake.VerifyAuthStub = stub
 }
 
-func (fake *FakeAuthVerifier) VerifyAuthArgsForCall(i int) *log.Logger {
+func (fake *FakeAuthVerifier) VerifyAuthArgsForCall(i int) (context.Context, *log.Logger) {
 	fake.verifyAuthMutex.RLock()
 	defer fake.verifyAuthMutex.RUnlock()
 	argsForCall := fake.verifyAuthArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeAuthVerifier) VerifyAuthReturns(result1 error) {
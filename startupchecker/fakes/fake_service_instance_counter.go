@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -10,11 +11,12 @@ import (
 )
 
 type FakeServiceInstanceCounter struct {
-	CountInstancesOfServiceOfferingStub        func(string, *log.Logger) (map[cf.ServicePlan]int, error)
+	CountInstancesOfServiceOfferingStub        func(context.Context, string, *log.Logger) (map[cf.ServicePlan]int, error)
 	countInstancesOfServiceOfferingMutex       sync.RWMutex
 	countInstancesOfServiceOfferingArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	countInstancesOfServiceOfferingReturns struct {
 		result1 map[cf.ServicePlan]int
@@ -28,19 +30,20 @@ type FakeServiceInstanceCounter struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOffering(arg1 string, arg2 *log.Logger) (map[cf.ServicePlan]int, error) {
+func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOffering(arg1 context.Context, arg2 string, arg3 *log.Logger) (map[cf.ServicePlan]int, error) {
 	fake.countInstancesOfServiceOfferingMutex.Lock()
 	ret, specificReturn := fake.countInstancesOfServiceOfferingReturnsOnCall[len(fake.countInstancesOfServiceOfferingArgsForCall)]
 	fake.countInstancesOfServiceOfferingArgsForCall = append(fake.countInstancesOfServiceOfferingArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.CountInstancesOfServiceOfferingStub
 	fakeReturns := fake.countInstancesOfServiceOfferingReturns
-	fake.recordInvocation("CountInstancesOfServiceOffering", []interface{}{arg1, arg2})
+	fake.recordInvocation("CountInstancesOfServiceOffering", []interface{}{arg1, arg2, arg3})
 	fake.countInstancesOfServiceOfferingMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -54,17 +57,17 @@ func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOfferingCallCount
 	return len(fake.countInstancesOfServiceOfferingArgsForCall)
 }
 
-func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOfferingCalls(stub func(string, *log.Logger) (map[cf.ServicePlan]int, error)) {
+func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOfferingCalls(stub func(context.Context, string, *log.Logger) (map[cf.ServicePlan]int, error)) {
 	fake.countInstancesOfServiceOfferingMutex.Lock()
 	defer fake.countInstancesOfServiceOfferingMutex.Unlock()
 	fake.CountInstancesOfServiceOfferingStub = stub
 }
 
-func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOfferingArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOfferingArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.countInstancesOfServiceOfferingMutex.RLock()
 	defer fake.countInstancesOfServiceOfferingMutex.RUnlock()
 	argsForCall := fake.countInstancesOfServiceOfferingArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeServiceInstanceCounter) CountInstancesOfServiceOfferingReturns(result1 map[cf.ServicePlan]int, result2 error) {
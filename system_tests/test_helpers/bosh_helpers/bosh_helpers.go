@@ -7,6 +7,7 @@
 package bosh_helpers
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
@@ -120,14 +121,14 @@ func (b *BoshHelperClient) RunErrandWithoutCheckingSuccess(deploymentName, erran
 
 func (b *BoshHelperClient) runErrandAndWait(deploymentName, errandName string, errandInstances []string, contextID string, logger *log.Logger) int {
 	taskReporter := boshdirector.NewAsyncTaskReporter()
-	taskID, err := b.Client.RunErrand(deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
+	taskID, err := b.Client.RunErrand(context.Background(), deploymentName, errandName, errandInstances, contextID, logger, taskReporter)
 	Expect(err).NotTo(HaveOccurred())
 	<-taskReporter.Finished
 	return taskID
 }
 
 func (b *BoshHelperClient) getTaskOutput(taskID int, logger *log.Logger) boshdirector.BoshTaskOutput {
-	output, err := b.Client.GetTaskOutput(taskID, logger)
+	output, err := b.Client.GetTaskOutput(context.Background(), taskID, logger)
 	Expect(err).NotTo(HaveOccurred())
 	return output
 }
@@ -135,7 +136,7 @@ func (b *BoshHelperClient) getTaskOutput(taskID int, logger *log.Logger) boshdir
 func (b *BoshHelperClient) GetManifest(deploymentName string) *bosh.BoshManifest {
 	logger := systemTestLogger()
 
-	data, found, err := b.Client.GetDeployment(deploymentName, logger)
+	data, found, err := b.Client.GetDeployment(context.Background(), deploymentName, logger)
 	Expect(err).NotTo(HaveOccurred())
 
 	if !found {
@@ -156,12 +157,12 @@ func (b *BoshHelperClient) DeployODB(manifest bosh.BoshManifest) {
 	Expect(err).NotTo(HaveOccurred())
 
 	asyncReporter := boshdirector.NewAsyncTaskReporter()
-	taskID, err := b.Client.Deploy(manifestBytes, "", logger, asyncReporter)
+	taskID, err := b.Client.Deploy(context.Background(), manifestBytes, "", logger, asyncReporter)
 	Expect(err).NotTo(HaveOccurred())
 
 	<-asyncReporter.Finished
 
-	task, err := b.Client.GetTask(taskID, logger)
+	task, err := b.Client.GetTask(context.Background(), taskID, logger)
 	Expect(err).NotTo(HaveOccurred())
 	Expect(task.StateType()).To(Equal(boshdirector.TaskComplete))
 
@@ -171,7 +172,7 @@ func (b *BoshHelperClient) DeployODB(manifest bosh.BoshManifest) {
 
 func (b *BoshHelperClient) DeploymentExists(deploymentName string) bool {
 	logger := systemTestLogger()
-	_, found, err := b.Client.GetDeployment(deploymentName, logger)
+	_, found, err := b.Client.GetDeployment(context.Background(), deploymentName, logger)
 	Expect(err).NotTo(HaveOccurred())
 	return found
 }
@@ -179,7 +180,7 @@ func (b *BoshHelperClient) DeploymentExists(deploymentName string) bool {
 func (b *BoshHelperClient) DeleteDeployment(deploymentName string) {
 	logger := systemTestLogger()
 	taskReporter := boshdirector.NewAsyncTaskReporter()
-	_, err := b.Client.DeleteDeployment(deploymentName, "", false, taskReporter, logger)
+	_, err := b.Client.DeleteDeployment(context.Background(), deploymentName, "", false, taskReporter, logger)
 	Expect(err).NotTo(HaveOccurred())
 	<-taskReporter.Finished
 }
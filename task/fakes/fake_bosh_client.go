@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -10,13 +11,30 @@ import (
 )
 
 type FakeBoshClient struct {
-	DeployStub        func([]byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
+	DeleteConfigStub        func(context.Context, string, string, *log.Logger) (bool, error)
+	deleteConfigMutex       sync.RWMutex
+	deleteConfigArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
+	}
+	deleteConfigReturns struct {
+		result1 bool
+		result2 error
+	}
+	deleteConfigReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	DeployStub        func(context.Context, []byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
 	deployMutex       sync.RWMutex
 	deployArgsForCall []struct {
-		arg1 []byte
-		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
+		arg1 context.Context
+		arg2 []byte
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
 	}
 	deployReturns struct {
 		result1 int
@@ -26,11 +44,12 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
-	GetConfigsStub        func(string, *log.Logger) ([]boshdirector.BoshConfig, error)
+	GetConfigsStub        func(context.Context, string, *log.Logger) ([]boshdirector.BoshConfig, error)
 	getConfigsMutex       sync.RWMutex
 	getConfigsArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	getConfigsReturns struct {
 		result1 []boshdirector.BoshConfig
@@ -40,11 +59,12 @@ type FakeBoshClient struct {
 		result1 []boshdirector.BoshConfig
 		result2 error
 	}
-	GetDeploymentStub        func(string, *log.Logger) ([]byte, bool, error)
+	GetDeploymentStub        func(context.Context, string, *log.Logger) ([]byte, bool, error)
 	getDeploymentMutex       sync.RWMutex
 	getDeploymentArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	getDeploymentReturns struct {
 		result1 []byte
@@ -56,12 +76,13 @@ type FakeBoshClient struct {
 		result2 bool
 		result3 error
 	}
-	GetEventsStub        func(string, string, *log.Logger) ([]boshdirector.BoshEvent, error)
+	GetEventsStub        func(context.Context, string, string, *log.Logger) ([]boshdirector.BoshEvent, error)
 	getEventsMutex       sync.RWMutex
 	getEventsArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
+		arg3 string
+		arg4 *log.Logger
 	}
 	getEventsReturns struct {
 		result1 []boshdirector.BoshEvent
@@ -71,12 +92,13 @@ type FakeBoshClient struct {
 		result1 []boshdirector.BoshEvent
 		result2 error
 	}
-	GetNormalisedTasksByContextStub        func(string, string, *log.Logger) (boshdirector.BoshTasks, error)
+	GetNormalisedTasksByContextStub        func(context.Context, string, string, *log.Logger) (boshdirector.BoshTasks, error)
 	getNormalisedTasksByContextMutex       sync.RWMutex
 	getNormalisedTasksByContextArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
+		arg3 string
+		arg4 *log.Logger
 	}
 	getNormalisedTasksByContextReturns struct {
 		result1 boshdirector.BoshTasks
@@ -86,11 +108,12 @@ type FakeBoshClient struct {
 		result1 boshdirector.BoshTasks
 		result2 error
 	}
-	GetTaskStub        func(int, *log.Logger) (boshdirector.BoshTask, error)
+	GetTaskStub        func(context.Context, int, *log.Logger) (boshdirector.BoshTask, error)
 	getTaskMutex       sync.RWMutex
 	getTaskArgsForCall []struct {
-		arg1 int
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 int
+		arg3 *log.Logger
 	}
 	getTaskReturns struct {
 		result1 boshdirector.BoshTask
@@ -100,11 +123,12 @@ type FakeBoshClient struct {
 		result1 boshdirector.BoshTask
 		result2 error
 	}
-	GetTasksInProgressStub        func(string, *log.Logger) (boshdirector.BoshTasks, error)
+	GetTasksInProgressStub        func(context.Context, string, *log.Logger) (boshdirector.BoshTasks, error)
 	getTasksInProgressMutex       sync.RWMutex
 	getTasksInProgressArgsForCall []struct {
-		arg1 string
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
 	}
 	getTasksInProgressReturns struct {
 		result1 boshdirector.BoshTasks
@@ -114,13 +138,14 @@ type FakeBoshClient struct {
 		result1 boshdirector.BoshTasks
 		result2 error
 	}
-	RecreateStub        func(string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
+	RecreateStub        func(context.Context, string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)
 	recreateMutex       sync.RWMutex
 	recreateArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
 	}
 	recreateReturns struct {
 		result1 int
@@ -130,13 +155,14 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
-	UpdateConfigStub        func(string, string, []byte, *log.Logger) error
+	UpdateConfigStub        func(context.Context, string, string, []byte, *log.Logger) error
 	updateConfigMutex       sync.RWMutex
 	updateConfigArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 []byte
-		arg4 *log.Logger
+		arg3 string
+		arg4 []byte
+		arg5 *log.Logger
 	}
 	updateConfigReturns struct {
 		result1 error
@@ -148,26 +174,94 @@ type FakeBoshClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBoshClient) Deploy(arg1 []byte, arg2 string, arg3 *log.Logger, arg4 *boshdirector.AsyncTaskReporter) (int, error) {
-	var arg1Copy []byte
-	if arg1 != nil {
-		arg1Copy = make([]byte, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeBoshClient) DeleteConfig(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger) (bool, error) {
+	fake.deleteConfigMutex.Lock()
+	ret, specificReturn := fake.deleteConfigReturnsOnCall[len(fake.deleteConfigArgsForCall)]
+	fake.deleteConfigArgsForCall = append(fake.deleteConfigArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.DeleteConfigStub
+	fakeReturns := fake.deleteConfigReturns
+	fake.recordInvocation("DeleteConfig", []interface{}{arg1, arg2, arg3, arg4})
+	fake.deleteConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeBoshClient) DeleteConfigCallCount() int {
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
+	return len(fake.deleteConfigArgsForCall)
+}
+
+func (fake *FakeBoshClient) DeleteConfigCalls(stub func(context.Context, string, string, *log.Logger) (bool, error)) {
+	fake.deleteConfigMutex.Lock()
+	defer fake.deleteConfigMutex.Unlock()
+	fake.DeleteConfigStub = stub
+}
+
+func (fake *FakeBoshClient) DeleteConfigArgsForCall(i int) (context.Context, string, string, *log.Logger) {
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
+	argsForCall := fake.deleteConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeBoshClient) DeleteConfigReturns(result1 bool, result2 error) {
+	fake.deleteConfigMutex.Lock()
+	defer fake.deleteConfigMutex.Unlock()
+	fake.DeleteConfigStub = nil
+	fake.deleteConfigReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) DeleteConfigReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.deleteConfigMutex.Lock()
+	defer fake.deleteConfigMutex.Unlock()
+	fake.DeleteConfigStub = nil
+	if fake.deleteConfigReturnsOnCall == nil {
+		fake.deleteConfigReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.deleteConfigReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) Deploy(arg1 context.Context, arg2 []byte, arg3 string, arg4 *log.Logger, arg5 *boshdirector.AsyncTaskReporter) (int, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
 	}
 	fake.deployMutex.Lock()
 	ret, specificReturn := fake.deployReturnsOnCall[len(fake.deployArgsForCall)]
 	fake.deployArgsForCall = append(fake.deployArgsForCall, struct {
-		arg1 []byte
-		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
-	}{arg1Copy, arg2, arg3, arg4})
+		arg1 context.Context
+		arg2 []byte
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
+	}{arg1, arg2Copy, arg3, arg4, arg5})
 	stub := fake.DeployStub
 	fakeReturns := fake.deployReturns
-	fake.recordInvocation("Deploy", []interface{}{arg1Copy, arg2, arg3, arg4})
+	fake.recordInvocation("Deploy", []interface{}{arg1, arg2Copy, arg3, arg4, arg5})
 	fake.deployMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -181,17 +275,17 @@ func (fake *FakeBoshClient) DeployCallCount() int {
 	return len(fake.deployArgsForCall)
 }
 
-func (fake *FakeBoshClient) DeployCalls(stub func([]byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
+func (fake *FakeBoshClient) DeployCalls(stub func(context.Context, []byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
 	fake.deployMutex.Lock()
 	defer fake.deployMutex.Unlock()
 	fake.DeployStub = stub
 }
 
-func (fake *FakeBoshClient) DeployArgsForCall(i int) ([]byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
+func (fake *FakeBoshClient) DeployArgsForCall(i int) (context.Context, []byte, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
 	fake.deployMutex.RLock()
 	defer fake.deployMutex.RUnlock()
 	argsForCall := fake.deployArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeBoshClient) DeployReturns(result1 int, result2 error) {
@@ -220,19 +314,20 @@ func (fake *FakeBoshClient) DeployReturnsOnCall(i int, result1 int, result2 erro
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetConfigs(arg1 string, arg2 *log.Logger) ([]boshdirector.BoshConfig, error) {
+func (fake *FakeBoshClient) GetConfigs(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]boshdirector.BoshConfig, error) {
 	fake.getConfigsMutex.Lock()
 	ret, specificReturn := fake.getConfigsReturnsOnCall[len(fake.getConfigsArgsForCall)]
 	fake.getConfigsArgsForCall = append(fake.getConfigsArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetConfigsStub
 	fakeReturns := fake.getConfigsReturns
-	fake.recordInvocation("GetConfigs", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetConfigs", []interface{}{arg1, arg2, arg3})
 	fake.getConfigsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -246,17 +341,17 @@ func (fake *FakeBoshClient) GetConfigsCallCount() int {
 	return len(fake.getConfigsArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetConfigsCalls(stub func(string, *log.Logger) ([]boshdirector.BoshConfig, error)) {
+func (fake *FakeBoshClient) GetConfigsCalls(stub func(context.Context, string, *log.Logger) ([]boshdirector.BoshConfig, error)) {
 	fake.getConfigsMutex.Lock()
 	defer fake.getConfigsMutex.Unlock()
 	fake.GetConfigsStub = stub
 }
 
-func (fake *FakeBoshClient) GetConfigsArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) GetConfigsArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.getConfigsMutex.RLock()
 	defer fake.getConfigsMutex.RUnlock()
 	argsForCall := fake.getConfigsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetConfigsReturns(result1 []boshdirector.BoshConfig, result2 error) {
@@ -285,19 +380,20 @@ func (fake *FakeBoshClient) GetConfigsReturnsOnCall(i int, result1 []boshdirecto
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetDeployment(arg1 string, arg2 *log.Logger) ([]byte, bool, error) {
+func (fake *FakeBoshClient) GetDeployment(arg1 context.Context, arg2 string, arg3 *log.Logger) ([]byte, bool, error) {
 	fake.getDeploymentMutex.Lock()
 	ret, specificReturn := fake.getDeploymentReturnsOnCall[len(fake.getDeploymentArgsForCall)]
 	fake.getDeploymentArgsForCall = append(fake.getDeploymentArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetDeploymentStub
 	fakeReturns := fake.getDeploymentReturns
-	fake.recordInvocation("GetDeployment", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetDeployment", []interface{}{arg1, arg2, arg3})
 	fake.getDeploymentMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -311,17 +407,17 @@ func (fake *FakeBoshClient) GetDeploymentCallCount() int {
 	return len(fake.getDeploymentArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetDeploymentCalls(stub func(string, *log.Logger) ([]byte, bool, error)) {
+func (fake *FakeBoshClient) GetDeploymentCalls(stub func(context.Context, string, *log.Logger) ([]byte, bool, error)) {
 	fake.getDeploymentMutex.Lock()
 	defer fake.getDeploymentMutex.Unlock()
 	fake.GetDeploymentStub = stub
 }
 
-func (fake *FakeBoshClient) GetDeploymentArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) GetDeploymentArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.getDeploymentMutex.RLock()
 	defer fake.getDeploymentMutex.RUnlock()
 	argsForCall := fake.getDeploymentArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetDeploymentReturns(result1 []byte, result2 bool, result3 error) {
@@ -353,20 +449,21 @@ func (fake *FakeBoshClient) GetDeploymentReturnsOnCall(i int, result1 []byte, re
 	}{result1, result2, result3}
 }
 
-func (fake *FakeBoshClient) GetEvents(arg1 string, arg2 string, arg3 *log.Logger) ([]boshdirector.BoshEvent, error) {
+func (fake *FakeBoshClient) GetEvents(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger) ([]boshdirector.BoshEvent, error) {
 	fake.getEventsMutex.Lock()
 	ret, specificReturn := fake.getEventsReturnsOnCall[len(fake.getEventsArgsForCall)]
 	fake.getEventsArgsForCall = append(fake.getEventsArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.GetEventsStub
 	fakeReturns := fake.getEventsReturns
-	fake.recordInvocation("GetEvents", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("GetEvents", []interface{}{arg1, arg2, arg3, arg4})
 	fake.getEventsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -380,17 +477,17 @@ func (fake *FakeBoshClient) GetEventsCallCount() int {
 	return len(fake.getEventsArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetEventsCalls(stub func(string, string, *log.Logger) ([]boshdirector.BoshEvent, error)) {
+func (fake *FakeBoshClient) GetEventsCalls(stub func(context.Context, string, string, *log.Logger) ([]boshdirector.BoshEvent, error)) {
 	fake.getEventsMutex.Lock()
 	defer fake.getEventsMutex.Unlock()
 	fake.GetEventsStub = stub
 }
 
-func (fake *FakeBoshClient) GetEventsArgsForCall(i int) (string, string, *log.Logger) {
+func (fake *FakeBoshClient) GetEventsArgsForCall(i int) (context.Context, string, string, *log.Logger) {
 	fake.getEventsMutex.RLock()
 	defer fake.getEventsMutex.RUnlock()
 	argsForCall := fake.getEventsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeBoshClient) GetEventsReturns(result1 []boshdirector.BoshEvent, result2 error) {
@@ -419,20 +516,21 @@ func (fake *FakeBoshClient) GetEventsReturnsOnCall(i int, result1 []boshdirector
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetNormalisedTasksByContext(arg1 string, arg2 string, arg3 *log.Logger) (boshdirector.BoshTasks, error) {
+func (fake *FakeBoshClient) GetNormalisedTasksByContext(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger) (boshdirector.BoshTasks, error) {
 	fake.getNormalisedTasksByContextMutex.Lock()
 	ret, specificReturn := fake.getNormalisedTasksByContextReturnsOnCall[len(fake.getNormalisedTasksByContextArgsForCall)]
 	fake.getNormalisedTasksByContextArgsForCall = append(fake.getNormalisedTasksByContextArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 *log.Logger
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.GetNormalisedTasksByContextStub
 	fakeReturns := fake.getNormalisedTasksByContextReturns
-	fake.recordInvocation("GetNormalisedTasksByContext", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("GetNormalisedTasksByContext", []interface{}{arg1, arg2, arg3, arg4})
 	fake.getNormalisedTasksByContextMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -446,17 +544,17 @@ func (fake *FakeBoshClient) GetNormalisedTasksByContextCallCount() int {
 	return len(fake.getNormalisedTasksByContextArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetNormalisedTasksByContextCalls(stub func(string, string, *log.Logger) (boshdirector.BoshTasks, error)) {
+func (fake *FakeBoshClient) GetNormalisedTasksByContextCalls(stub func(context.Context, string, string, *log.Logger) (boshdirector.BoshTasks, error)) {
 	fake.getNormalisedTasksByContextMutex.Lock()
 	defer fake.getNormalisedTasksByContextMutex.Unlock()
 	fake.GetNormalisedTasksByContextStub = stub
 }
 
-func (fake *FakeBoshClient) GetNormalisedTasksByContextArgsForCall(i int) (string, string, *log.Logger) {
+func (fake *FakeBoshClient) GetNormalisedTasksByContextArgsForCall(i int) (context.Context, string, string, *log.Logger) {
 	fake.getNormalisedTasksByContextMutex.RLock()
 	defer fake.getNormalisedTasksByContextMutex.RUnlock()
 	argsForCall := fake.getNormalisedTasksByContextArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeBoshClient) GetNormalisedTasksByContextReturns(result1 boshdirector.BoshTasks, result2 error) {
@@ -485,19 +583,20 @@ func (fake *FakeBoshClient) GetNormalisedTasksByContextReturnsOnCall(i int, resu
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetTask(arg1 int, arg2 *log.Logger) (boshdirector.BoshTask, error) {
+func (fake *FakeBoshClient) GetTask(arg1 context.Context, arg2 int, arg3 *log.Logger) (boshdirector.BoshTask, error) {
 	fake.getTaskMutex.Lock()
 	ret, specificReturn := fake.getTaskReturnsOnCall[len(fake.getTaskArgsForCall)]
 	fake.getTaskArgsForCall = append(fake.getTaskArgsForCall, struct {
-		arg1 int
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 int
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetTaskStub
 	fakeReturns := fake.getTaskReturns
-	fake.recordInvocation("GetTask", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetTask", []interface{}{arg1, arg2, arg3})
 	fake.getTaskMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -511,17 +610,17 @@ func (fake *FakeBoshClient) GetTaskCallCount() int {
 	return len(fake.getTaskArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetTaskCalls(stub func(int, *log.Logger) (boshdirector.BoshTask, error)) {
+func (fake *FakeBoshClient) GetTaskCalls(stub func(context.Context, int, *log.Logger) (boshdirector.BoshTask, error)) {
 	fake.getTaskMutex.Lock()
 	defer fake.getTaskMutex.Unlock()
 	fake.GetTaskStub = stub
 }
 
-func (fake *FakeBoshClient) GetTaskArgsForCall(i int) (int, *log.Logger) {
+func (fake *FakeBoshClient) GetTaskArgsForCall(i int) (context.Context, int, *log.Logger) {
 	fake.getTaskMutex.RLock()
 	defer fake.getTaskMutex.RUnlock()
 	argsForCall := fake.getTaskArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetTaskReturns(result1 boshdirector.BoshTask, result2 error) {
@@ -550,19 +649,20 @@ func (fake *FakeBoshClient) GetTaskReturnsOnCall(i int, result1 boshdirector.Bos
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) GetTasksInProgress(arg1 string, arg2 *log.Logger) (boshdirector.BoshTasks, error) {
+func (fake *FakeBoshClient) GetTasksInProgress(arg1 context.Context, arg2 string, arg3 *log.Logger) (boshdirector.BoshTasks, error) {
 	fake.getTasksInProgressMutex.Lock()
 	ret, specificReturn := fake.getTasksInProgressReturnsOnCall[len(fake.getTasksInProgressArgsForCall)]
 	fake.getTasksInProgressArgsForCall = append(fake.getTasksInProgressArgsForCall, struct {
-		arg1 string
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GetTasksInProgressStub
 	fakeReturns := fake.getTasksInProgressReturns
-	fake.recordInvocation("GetTasksInProgress", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetTasksInProgress", []interface{}{arg1, arg2, arg3})
 	fake.getTasksInProgressMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -576,17 +676,17 @@ func (fake *FakeBoshClient) GetTasksInProgressCallCount() int {
 	return len(fake.getTasksInProgressArgsForCall)
 }
 
-func (fake *FakeBoshClient) GetTasksInProgressCalls(stub func(string, *log.Logger) (boshdirector.BoshTasks, error)) {
+func (fake *FakeBoshClient) GetTasksInProgressCalls(stub func(context.Context, string, *log.Logger) (boshdirector.BoshTasks, error)) {
 	fake.getTasksInProgressMutex.Lock()
 	defer fake.getTasksInProgressMutex.Unlock()
 	fake.GetTasksInProgressStub = stub
 }
 
-func (fake *FakeBoshClient) GetTasksInProgressArgsForCall(i int) (string, *log.Logger) {
+func (fake *FakeBoshClient) GetTasksInProgressArgsForCall(i int) (context.Context, string, *log.Logger) {
 	fake.getTasksInProgressMutex.RLock()
 	defer fake.getTasksInProgressMutex.RUnlock()
 	argsForCall := fake.getTasksInProgressArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeBoshClient) GetTasksInProgressReturns(result1 boshdirector.BoshTasks, result2 error) {
@@ -615,21 +715,22 @@ func (fake *FakeBoshClient) GetTasksInProgressReturnsOnCall(i int, result1 boshd
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) Recreate(arg1 string, arg2 string, arg3 *log.Logger, arg4 *boshdirector.AsyncTaskReporter) (int, error) {
+func (fake *FakeBoshClient) Recreate(arg1 context.Context, arg2 string, arg3 string, arg4 *log.Logger, arg5 *boshdirector.AsyncTaskReporter) (int, error) {
 	fake.recreateMutex.Lock()
 	ret, specificReturn := fake.recreateReturnsOnCall[len(fake.recreateArgsForCall)]
 	fake.recreateArgsForCall = append(fake.recreateArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *log.Logger
-		arg4 *boshdirector.AsyncTaskReporter
-	}{arg1, arg2, arg3, arg4})
+		arg3 string
+		arg4 *log.Logger
+		arg5 *boshdirector.AsyncTaskReporter
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.RecreateStub
 	fakeReturns := fake.recreateReturns
-	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Recreate", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.recreateMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -643,17 +744,17 @@ func (fake *FakeBoshClient) RecreateCallCount() int {
 	return len(fake.recreateArgsForCall)
 }
 
-func (fake *FakeBoshClient) RecreateCalls(stub func(string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
+func (fake *FakeBoshClient) RecreateCalls(stub func(context.Context, string, string, *log.Logger, *boshdirector.AsyncTaskReporter) (int, error)) {
 	fake.recreateMutex.Lock()
 	defer fake.recreateMutex.Unlock()
 	fake.RecreateStub = stub
 }
 
-func (fake *FakeBoshClient) RecreateArgsForCall(i int) (string, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
+func (fake *FakeBoshClient) RecreateArgsForCall(i int) (context.Context, string, string, *log.Logger, *boshdirector.AsyncTaskReporter) {
 	fake.recreateMutex.RLock()
 	defer fake.recreateMutex.RUnlock()
 	argsForCall := fake.recreateArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeBoshClient) RecreateReturns(result1 int, result2 error) {
@@ -682,26 +783,27 @@ func (fake *FakeBoshClient) RecreateReturnsOnCall(i int, result1 int, result2 er
 	}{result1, result2}
 }
 
-func (fake *FakeBoshClient) UpdateConfig(arg1 string, arg2 string, arg3 []byte, arg4 *log.Logger) error {
-	var arg3Copy []byte
-	if arg3 != nil {
-		arg3Copy = make([]byte, len(arg3))
-		copy(arg3Copy, arg3)
+func (fake *FakeBoshClient) UpdateConfig(arg1 context.Context, arg2 string, arg3 string, arg4 []byte, arg5 *log.Logger) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.updateConfigMutex.Lock()
 	ret, specificReturn := fake.updateConfigReturnsOnCall[len(fake.updateConfigArgsForCall)]
 	fake.updateConfigArgsForCall = append(fake.updateConfigArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 []byte
-		arg4 *log.Logger
-	}{arg1, arg2, arg3Copy, arg4})
+		arg3 string
+		arg4 []byte
+		arg5 *log.Logger
+	}{arg1, arg2, arg3, arg4Copy, arg5})
 	stub := fake.UpdateConfigStub
 	fakeReturns := fake.updateConfigReturns
-	fake.recordInvocation("UpdateConfig", []interface{}{arg1, arg2, arg3Copy, arg4})
+	fake.recordInvocation("UpdateConfig", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
 	fake.updateConfigMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -715,17 +817,17 @@ func (fake *FakeBoshClient) UpdateConfigCallCount() int {
 	return len(fake.updateConfigArgsForCall)
 }
 
-func (fake *FakeBoshClient) UpdateConfigCalls(stub func(string, string, []byte, *log.Logger) error) {
+func (fake *FakeBoshClient) UpdateConfigCalls(stub func(context.Context, string, string, []byte, *log.Logger) error) {
 	fake.updateConfigMutex.Lock()
 	defer fake.updateConfigMutex.Unlock()
 	fake.UpdateConfigStub = stub
 }
 
-func (fake *FakeBoshClient) UpdateConfigArgsForCall(i int) (string, string, []byte, *log.Logger) {
+func (fake *FakeBoshClient) UpdateConfigArgsForCall(i int) (context.Context, string, string, []byte, *log.Logger) {
 	fake.updateConfigMutex.RLock()
 	defer fake.updateConfigMutex.RUnlock()
 	argsForCall := fake.updateConfigArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeBoshClient) UpdateConfigReturns(result1 error) {
@@ -754,6 +856,8 @@ func (fake *FakeBoshClient) UpdateConfigReturnsOnCall(i int, result1 error) {
 func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
 	fake.deployMutex.RLock()
 	defer fake.deployMutex.RUnlock()
 	fake.getConfigsMutex.RLock()
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
@@ -9,10 +10,11 @@ import (
 )
 
 type FakeBulkSetter struct {
-	BulkSetStub        func([]broker.ManifestSecret) error
+	BulkSetStub        func(context.Context, []broker.ManifestSecret) error
 	bulkSetMutex       sync.RWMutex
 	bulkSetArgsForCall []struct {
-		arg1 []broker.ManifestSecret
+		arg1 context.Context
+		arg2 []broker.ManifestSecret
 	}
 	bulkSetReturns struct {
 		result1 error
@@ -24,23 +26,24 @@ type FakeBulkSetter struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBulkSetter) BulkSet(arg1 []broker.ManifestSecret) error {
-	var arg1Copy []broker.ManifestSecret
-	if arg1 != nil {
-		arg1Copy = make([]broker.ManifestSecret, len(arg1))
-		copy(arg1Copy, arg1)
+func (fake *FakeBulkSetter) BulkSet(arg1 context.Context, arg2 []broker.ManifestSecret) error {
+	var arg2Copy []broker.ManifestSecret
+	if arg2 != nil {
+		arg2Copy = make([]broker.ManifestSecret, len(arg2))
+		copy(arg2Copy, arg2)
 	}
 	fake.bulkSetMutex.Lock()
 	ret, specificReturn := fake.bulkSetReturnsOnCall[len(fake.bulkSetArgsForCall)]
 	fake.bulkSetArgsForCall = append(fake.bulkSetArgsForCall, struct {
-		arg1 []broker.ManifestSecret
-	}{arg1Copy})
+		arg1 context.Context
+		arg2 []broker.ManifestSecret
+	}{arg1, arg2Copy})
 	stub := fake.BulkSetStub
 	fakeReturns := fake.bulkSetReturns
-	fake.recordInvocation("BulkSet", []interface{}{arg1Copy})
+	fake.recordInvocation("BulkSet", []interface{}{arg1, arg2Copy})
 	fake.bulkSetMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -54,17 +57,17 @@ func (fake *FakeBulkSetter) BulkSetCallCount() int {
 	return len(fake.bulkSetArgsForCall)
 }
 
-func (fake *FakeBulkSetter) BulkSetCalls(stub func([]broker.ManifestSecret) error) {
+func (fake *FakeBulkSetter) BulkSetCalls(stub func(context.Context, []broker.ManifestSecret) error) {
 	fake.bulkSetMutex.Lock()
 	defer fake.bulkSetMutex.Unlock()
 	fake.BulkSetStub = stub
 }
 
-func (fake *FakeBulkSetter) BulkSetArgsForCall(i int) []broker.ManifestSecret {
+func (fake *FakeBulkSetter) BulkSetArgsForCall(i int) (context.Context, []broker.ManifestSecret) {
 	fake.bulkSetMutex.RLock()
 	defer fake.bulkSetMutex.RUnlock()
 	argsForCall := fake.bulkSetArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeBulkSetter) BulkSetReturns(result1 error) {
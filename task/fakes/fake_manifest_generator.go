@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
@@ -10,11 +11,12 @@ import (
 )
 
 type FakeManifestGenerator struct {
-	GenerateManifestStub        func(task.GenerateManifestProperties, *log.Logger) (serviceadapter.MarshalledGenerateManifest, error)
+	GenerateManifestStub        func(context.Context, task.GenerateManifestProperties, *log.Logger) (serviceadapter.MarshalledGenerateManifest, error)
 	generateManifestMutex       sync.RWMutex
 	generateManifestArgsForCall []struct {
-		arg1 task.GenerateManifestProperties
-		arg2 *log.Logger
+		arg1 context.Context
+		arg2 task.GenerateManifestProperties
+		arg3 *log.Logger
 	}
 	generateManifestReturns struct {
 		result1 serviceadapter.MarshalledGenerateManifest
@@ -28,19 +30,20 @@ type FakeManifestGenerator struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeManifestGenerator) GenerateManifest(arg1 task.GenerateManifestProperties, arg2 *log.Logger) (serviceadapter.MarshalledGenerateManifest, error) {
+func (fake *FakeManifestGenerator) GenerateManifest(arg1 context.Context, arg2 task.GenerateManifestProperties, arg3 *log.Logger) (serviceadapter.MarshalledGenerateManifest, error) {
 	fake.generateManifestMutex.Lock()
 	ret, specificReturn := fake.generateManifestReturnsOnCall[len(fake.generateManifestArgsForCall)]
 	fake.generateManifestArgsForCall = append(fake.generateManifestArgsForCall, struct {
-		arg1 task.GenerateManifestProperties
-		arg2 *log.Logger
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 task.GenerateManifestProperties
+		arg3 *log.Logger
+	}{arg1, arg2, arg3})
 	stub := fake.GenerateManifestStub
 	fakeReturns := fake.generateManifestReturns
-	fake.recordInvocation("GenerateManifest", []interface{}{arg1, arg2})
+	fake.recordInvocation("GenerateManifest", []interface{}{arg1, arg2, arg3})
 	fake.generateManifestMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -54,17 +57,17 @@ func (fake *FakeManifestGenerator) GenerateManifestCallCount() int {
 	return len(fake.generateManifestArgsForCall)
 }
 
-func (fake *FakeManifestGenerator) GenerateManifestCalls(stub func(task.GenerateManifestProperties, *log.Logger) (serviceadapter.MarshalledGenerateManifest, error)) {
+func (fake *FakeManifestGenerator) GenerateManifestCalls(stub func(context.Context, task.GenerateManifestProperties, *log.Logger) (serviceadapter.MarshalledGenerateManifest, error)) {
 	fake.generateManifestMutex.Lock()
 	defer fake.generateManifestMutex.Unlock()
 	fake.GenerateManifestStub = stub
 }
 
-func (fake *FakeManifestGenerator) GenerateManifestArgsForCall(i int) (task.GenerateManifestProperties, *log.Logger) {
+func (fake *FakeManifestGenerator) GenerateManifestArgsForCall(i int) (context.Context, task.GenerateManifestProperties, *log.Logger) {
 	fake.generateManifestMutex.RLock()
 	defer fake.generateManifestMutex.RUnlock()
 	argsForCall := fake.generateManifestArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeManifestGenerator) GenerateManifestReturns(result1 serviceadapter.MarshalledGenerateManifest, result2 error) {
@@ -0,0 +1,305 @@
+package task
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var secretPropertyMarkers = []string{"password", "secret", "token", "credential", "private"}
+
+var interpolationPattern = regexp.MustCompile(`\(\([^()]+\)\)`)
+
+// DiffManifests compares the releases, stemcells, instance groups and
+// properties of two manifests. Update blocks are ignored, as they are when
+// checking for pending changes. Property values that are one of the given
+// secret values, such as those resolved from CredHub, or contain one are
+// redacted.
+func DiffManifests(oldManifest, newManifest []byte, secretValues []string) (broker.ManifestDiff, error) {
+	before, err := marshalBoshManifest(oldManifest)
+	if err != nil {
+		return broker.ManifestDiff{}, err
+	}
+
+	after, err := marshalBoshManifest(newManifest)
+	if err != nil {
+		return broker.ManifestDiff{}, err
+	}
+
+	d := differ{secretValues: map[string]bool{}}
+	for _, value := range secretValues {
+		if value != "" {
+			d.secretValues[value] = true
+		}
+	}
+	d.diffReleases(before.Releases, after.Releases)
+	d.diffStemcells(before.Stemcells, after.Stemcells)
+	d.diffInstanceGroups(before.InstanceGroups, after.InstanceGroups)
+	d.diffProperties("/properties", before.Properties, after.Properties)
+
+	return d.diff, nil
+}
+
+type differ struct {
+	diff         broker.ManifestDiff
+	secretValues map[string]bool
+}
+
+func (d *differ) diffReleases(before, after []bosh.Release) {
+	versions := func(releases []bosh.Release) map[string]interface{} {
+		byName := map[string]interface{}{}
+		for _, release := range releases {
+			byName[release.Name] = release.Version
+		}
+		return byName
+	}
+	d.diff.Releases = changesByName("/releases/name=%s/version", versions(before), versions(after))
+}
+
+func (d *differ) diffStemcells(before, after []bosh.Stemcell) {
+	stemcells := func(stemcells []bosh.Stemcell) map[string]interface{} {
+		byAlias := map[string]interface{}{}
+		for _, stemcell := range stemcells {
+			byAlias[stemcell.Alias] = fmt.Sprintf("%s/%s", stemcell.OS, stemcell.Version)
+		}
+		return byAlias
+	}
+	d.diff.Stemcells = changesByName("/stemcells/alias=%s", stemcells(before), stemcells(after))
+}
+
+func (d *differ) diffInstanceGroups(before, after []bosh.InstanceGroup) {
+	afterByName := map[string]bosh.InstanceGroup{}
+	for _, instanceGroup := range after {
+		afterByName[instanceGroup.Name] = instanceGroup
+	}
+
+	beforeByName := map[string]bosh.InstanceGroup{}
+	for _, oldGroup := range before {
+		beforeByName[oldGroup.Name] = oldGroup
+		path := fmt.Sprintf("/instance_groups/name=%s", oldGroup.Name)
+
+		newGroup, found := afterByName[oldGroup.Name]
+		if !found {
+			d.diff.InstanceGroups = append(d.diff.InstanceGroups, broker.ManifestChange{Path: path, Before: oldGroup.Name})
+			continue
+		}
+
+		d.addInstanceGroupChange(path+"/instances", oldGroup.Instances, newGroup.Instances)
+		d.addInstanceGroupChange(path+"/vm_type", oldGroup.VMType, newGroup.VMType)
+		d.addInstanceGroupChange(path+"/vm_extensions", oldGroup.VMExtensions, newGroup.VMExtensions)
+		d.addInstanceGroupChange(path+"/stemcell", oldGroup.Stemcell, newGroup.Stemcell)
+		d.addInstanceGroupChange(path+"/persistent_disk_type", oldGroup.PersistentDiskType, newGroup.PersistentDiskType)
+		d.addInstanceGroupChange(path+"/azs", oldGroup.AZs, newGroup.AZs)
+		d.addInstanceGroupChange(path+"/networks", networkNames(oldGroup.Networks), networkNames(newGroup.Networks))
+		d.addInstanceGroupChange(path+"/jobs", jobNames(oldGroup.Jobs), jobNames(newGroup.Jobs))
+
+		d.diffProperties(path+"/properties", oldGroup.Properties, newGroup.Properties)
+		newJobs := map[string]bosh.Job{}
+		for _, job := range newGroup.Jobs {
+			newJobs[job.Name] = job
+		}
+		for _, oldJob := range oldGroup.Jobs {
+			if newJob, found := newJobs[oldJob.Name]; found {
+				d.diffProperties(fmt.Sprintf("%s/jobs/name=%s/properties", path, oldJob.Name), oldJob.Properties, newJob.Properties)
+			}
+		}
+	}
+
+	for _, newGroup := range after {
+		if _, found := beforeByName[newGroup.Name]; !found {
+			path := fmt.Sprintf("/instance_groups/name=%s", newGroup.Name)
+			d.diff.InstanceGroups = append(d.diff.InstanceGroups, broker.ManifestChange{Path: path, After: newGroup.Name})
+		}
+	}
+}
+
+func (d *differ) addInstanceGroupChange(path string, before, after interface{}) {
+	if !reflect.DeepEqual(before, after) {
+		d.diff.InstanceGroups = append(d.diff.InstanceGroups, broker.ManifestChange{Path: path, Before: before, After: after})
+	}
+}
+
+// diffProperties walks both property trees and reports each leaf that changed.
+// Lists of the same length are compared element by element, at paths ending in
+// the element index. Properties whose names look like credentials are compared
+// as a whole and reported without their values, and so are variables to be
+// interpolated and secret values.
+func (d *differ) diffProperties(path string, before, after interface{}) {
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+	if beforeIsList && afterIsList && len(beforeList) == len(afterList) {
+		for i := range beforeList {
+			d.diffProperties(fmt.Sprintf("%s/%d", path, i), beforeList[i], afterList[i])
+		}
+		return
+	}
+
+	beforeMap, beforeIsMap := asMap(before)
+	afterMap, afterIsMap := asMap(after)
+
+	if !beforeIsMap || !afterIsMap {
+		if !reflect.DeepEqual(normalise(before), normalise(after)) {
+			d.diff.Properties = append(d.diff.Properties, broker.ManifestChange{Path: path, Before: d.redactSecrets(before), After: d.redactSecrets(after)})
+		}
+		return
+	}
+
+	for _, key := range unionOfKeys(beforeMap, afterMap) {
+		keyPath := path + "/" + key
+		if isSecretProperty(key) {
+			if !reflect.DeepEqual(normalise(beforeMap[key]), normalise(afterMap[key])) {
+				d.diff.Properties = append(d.diff.Properties, broker.ManifestChange{
+					Path:   keyPath,
+					Before: redact(beforeMap[key]),
+					After:  redact(afterMap[key]),
+				})
+			}
+			continue
+		}
+		d.diffProperties(keyPath, beforeMap[key], afterMap[key])
+	}
+}
+
+func changesByName(pathFormat string, before, after map[string]interface{}) []broker.ManifestChange {
+	var changes []broker.ManifestChange
+	for _, name := range unionOfKeys(before, after) {
+		if !reflect.DeepEqual(before[name], after[name]) {
+			changes = append(changes, broker.ManifestChange{Path: fmt.Sprintf(pathFormat, name), Before: before[name], After: after[name]})
+		}
+	}
+	return changes
+}
+
+func unionOfKeys(maps ...map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func asMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return map[string]interface{}{}, true
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[fmt.Sprint(key)] = value
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+// normalise converts the maps produced by the YAML decoder so that values can
+// be compared and encoded as JSON.
+func normalise(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}, map[string]interface{}:
+		m, _ := asMap(v)
+		normalised := map[string]interface{}{}
+		for key, value := range m {
+			normalised[key] = normalise(value)
+		}
+		return normalised
+	case []interface{}:
+		normalised := make([]interface{}, len(v))
+		for i, value := range v {
+			normalised[i] = normalise(value)
+		}
+		return normalised
+	}
+	return value
+}
+
+func isSecretProperty(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range secretPropertyMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return strings.HasSuffix(name, "key")
+}
+
+func redact(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return broker.RedactedValue
+}
+
+// redactSecrets normalises a property value and redacts the credentials it
+// contains: properties whose names look like credentials, variables to be
+// interpolated and secret values.
+func (d *differ) redactSecrets(value interface{}) interface{} {
+	switch v := normalise(value).(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecretProperty(key) {
+				v[key] = redact(value)
+			} else {
+				v[key] = d.redactSecrets(value)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = d.redactSecrets(value)
+		}
+		return v
+	case string:
+		if d.containsSecret(v) {
+			return broker.RedactedValue
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// containsSecret reports whether a string contains a variable to be
+// interpolated or one of the secret values.
+func (d *differ) containsSecret(s string) bool {
+	if interpolationPattern.MatchString(s) {
+		return true
+	}
+	for secret := range d.secretValues {
+		if strings.Contains(s, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func networkNames(networks []bosh.Network) []string {
+	var names []string
+	for _, network := range networks {
+		names = append(names, network.Name)
+	}
+	return names
+}
+
+func jobNames(jobs []bosh.Job) []string {
+	var names []string
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+	return names
+}
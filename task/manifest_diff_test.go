@@ -0,0 +1,192 @@
+package task_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var _ = Describe("DiffManifests", func() {
+	const deployedManifest = `
+name: service-instance_some-id
+releases:
+- name: redis
+  version: 1.0.0
+- name: syslog
+  version: 3
+stemcells:
+- alias: default
+  os: ubuntu-jammy
+  version: "1.10"
+instance_groups:
+- name: redis
+  instances: 1
+  vm_type: small
+  stemcell: default
+  azs: [z1]
+  networks:
+  - name: services
+  jobs:
+  - name: redis-server
+    release: redis
+    properties:
+      maxmemory: 512
+      admin_password: old-secret
+      tls:
+        enabled: false
+- name: errand
+  instances: 1
+  vm_type: small
+  stemcell: default
+  networks:
+  - name: services
+update:
+  canaries: 1
+  max_in_flight: 1
+`
+
+	It("reports no changes when only the update block differs", func() {
+		diff, err := task.DiffManifests([]byte(deployedManifest), []byte(deployedManifest+"  serial: true\n"), nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Empty()).To(BeTrue())
+	})
+
+	It("reports the changes to releases, stemcells, instance groups and properties", func() {
+		generatedManifest := `
+name: service-instance_some-id
+releases:
+- name: redis
+  version: 2.0.0
+- name: bpm
+  version: 1.2
+stemcells:
+- alias: default
+  os: ubuntu-jammy
+  version: "1.20"
+instance_groups:
+- name: redis
+  instances: 3
+  vm_type: small
+  stemcell: default
+  azs: [z1]
+  networks:
+  - name: services
+  jobs:
+  - name: redis-server
+    release: redis
+    properties:
+      maxmemory: 1024
+      admin_password: new-secret
+      tls:
+        enabled: true
+        ca: some-ca
+- name: proxy
+  instances: 1
+  vm_type: small
+  stemcell: default
+  networks:
+  - name: services
+`
+
+		diff, err := task.DiffManifests([]byte(deployedManifest), []byte(generatedManifest), nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Releases).To(Equal([]broker.ManifestChange{
+			{Path: "/releases/name=bpm/version", After: "1.2"},
+			{Path: "/releases/name=redis/version", Before: "1.0.0", After: "2.0.0"},
+			{Path: "/releases/name=syslog/version", Before: "3"},
+		}))
+		Expect(diff.Stemcells).To(Equal([]broker.ManifestChange{
+			{Path: "/stemcells/alias=default", Before: "ubuntu-jammy/1.10", After: "ubuntu-jammy/1.20"},
+		}))
+		Expect(diff.InstanceGroups).To(Equal([]broker.ManifestChange{
+			{Path: "/instance_groups/name=redis/instances", Before: 1, After: 3},
+			{Path: "/instance_groups/name=errand", Before: "errand"},
+			{Path: "/instance_groups/name=proxy", After: "proxy"},
+		}))
+		Expect(diff.Properties).To(Equal([]broker.ManifestChange{
+			{Path: "/instance_groups/name=redis/jobs/name=redis-server/properties/admin_password", Before: broker.RedactedValue, After: broker.RedactedValue},
+			{Path: "/instance_groups/name=redis/jobs/name=redis-server/properties/maxmemory", Before: 512, After: 1024},
+			{Path: "/instance_groups/name=redis/jobs/name=redis-server/properties/tls/ca", After: "some-ca"},
+			{Path: "/instance_groups/name=redis/jobs/name=redis-server/properties/tls/enabled", Before: false, After: true},
+		}))
+	})
+
+	It("compares lists element by element and redacts the credentials they contain", func() {
+		listManifest := func(users string) []byte {
+			return []byte(`
+name: service-instance_some-id
+properties:
+  users:
+` + users)
+		}
+		before := listManifest(`  - name: admin
+    password: old-password
+    roles: [read]
+  - name: monitor
+    api_key: old-key
+`)
+		after := listManifest(`  - name: admin
+    password: new-password
+    roles: [read, write]
+  - name: monitor
+    api_key: old-key
+`)
+
+		diff, err := task.DiffManifests(before, after, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Properties).To(Equal([]broker.ManifestChange{
+			{Path: "/properties/users/0/password", Before: broker.RedactedValue, After: broker.RedactedValue},
+			{Path: "/properties/users/0/roles", Before: []interface{}{"read"}, After: []interface{}{"read", "write"}},
+		}))
+
+		diff, err = task.DiffManifests(before, listManifest(`  - name: admin
+    password: old-password
+`), nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Properties).To(Equal([]broker.ManifestChange{{
+			Path: "/properties/users",
+			Before: []interface{}{
+				map[string]interface{}{"name": "admin", "password": broker.RedactedValue, "roles": []interface{}{"read"}},
+				map[string]interface{}{"name": "monitor", "api_key": broker.RedactedValue},
+			},
+			After: []interface{}{
+				map[string]interface{}{"name": "admin", "password": broker.RedactedValue},
+			},
+		}}))
+	})
+
+	It("redacts variables to be interpolated and secret values", func() {
+		before := []byte(`
+name: service-instance_some-id
+properties:
+  url: https://user:((db_password))@db
+  connection: some-connection-string
+`)
+		after := []byte(`
+name: service-instance_some-id
+properties:
+  url: https://user:resolved-password@db
+  connection: another-connection-string
+`)
+
+		diff, err := task.DiffManifests(before, after, []string{"another-connection-string", "resolved-password"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Properties).To(Equal([]broker.ManifestChange{
+			{Path: "/properties/connection", Before: "some-connection-string", After: broker.RedactedValue},
+			{Path: "/properties/url", Before: broker.RedactedValue, After: broker.RedactedValue},
+		}))
+	})
+
+	It("fails when a manifest cannot be parsed", func() {
+		_, err := task.DiffManifests([]byte(deployedManifest), []byte("not: [valid"), nil)
+
+		Expect(err).To(MatchError(ContainSubstring("unable to unmarshal manifest")))
+	})
+})
@@ -7,6 +7,7 @@
 package task
 
 import (
+	"context"
 	"log"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
@@ -20,6 +21,7 @@ import (
 //counterfeiter:generate -o fakes/fake_service_adapter_client.go . ServiceAdapterClient
 type ServiceAdapterClient interface {
 	GenerateManifest(
+		ctx context.Context,
 		serviceReleases serviceadapter.ServiceDeployment,
 		plan serviceadapter.Plan,
 		requestParams map[string]interface{},
@@ -30,14 +32,13 @@ type ServiceAdapterClient interface {
 		uaaClient map[string]string,
 		logger *log.Logger,
 	) (serviceadapter.MarshalledGenerateManifest, error)
-	GeneratePlanSchema(plan serviceadapter.Plan, logger *log.Logger) (domain.ServiceSchemas, error)
+	GeneratePlanSchema(ctx context.Context, plan serviceadapter.Plan, logger *log.Logger) (domain.ServiceSchemas, error)
 }
 
 type manifestGenerator struct {
-	adapterClient    ServiceAdapterClient
-	serviceOffering  config.ServiceOffering
-	serviceStemcells []serviceadapter.Stemcell
-	serviceReleases  serviceadapter.ServiceReleases
+	adapterClient     ServiceAdapterClient
+	offerings         *config.OfferingStore
+	serviceOfferingID string
 }
 
 func NewManifestGenerator(
@@ -45,28 +46,38 @@ func NewManifestGenerator(
 	serviceOffering config.ServiceOffering,
 	serviceStemcells []serviceadapter.Stemcell,
 	serviceReleases serviceadapter.ServiceReleases,
-) manifestGenerator {
-	return manifestGenerator{
-		adapterClient:    serviceAdapter,
-		serviceOffering:  serviceOffering,
-		serviceStemcells: serviceStemcells,
-		serviceReleases:  serviceReleases,
+) *manifestGenerator {
+	return &manifestGenerator{
+		adapterClient: serviceAdapter,
+		offerings: config.NewOfferingStore([]config.OfferingConfig{{
+			ServiceCatalog:    serviceOffering,
+			ServiceDeployment: config.ServiceDeployment{Releases: serviceReleases, Stemcells: serviceStemcells},
+		}}),
+		serviceOfferingID: serviceOffering.ID,
 	}
 }
 
+// SetOfferingStore makes manifests be generated from the plans, stemcells and
+// releases of the offering in offerings, so that they are reloaded with it.
+func (m *manifestGenerator) SetOfferingStore(offerings *config.OfferingStore) {
+	m.offerings = offerings
+}
+
 type RawBoshManifest []byte
 
-func (m manifestGenerator) GenerateManifest(
+func (m *manifestGenerator) GenerateManifest(
+	ctx context.Context,
 	generateManifestProps GenerateManifestProperties,
 	logger *log.Logger,
 ) (serviceadapter.MarshalledGenerateManifest, error) {
+	offering := m.offerings.Offering(m.serviceOfferingID)
 	serviceDeployment := serviceadapter.ServiceDeployment{
 		DeploymentName: generateManifestProps.DeploymentName,
-		Releases:       m.serviceReleases,
-		Stemcells:      m.serviceStemcells,
+		Releases:       offering.ServiceDeployment.Releases,
+		Stemcells:      offering.ServiceDeployment.Stemcells,
 	}
 
-	plan, previousPlan, err := m.findPlans(generateManifestProps.PlanID, generateManifestProps.PreviousPlanID)
+	plan, previousPlan, err := findPlans(offering.ServiceCatalog, generateManifestProps.PlanID, generateManifestProps.PreviousPlanID)
 	if err != nil {
 		logger.Println(err)
 		return serviceadapter.MarshalledGenerateManifest{}, err
@@ -75,6 +86,7 @@ func (m manifestGenerator) GenerateManifest(
 
 	// TODO: That's a lot of arguments...
 	manifest, err := m.adapterClient.GenerateManifest(
+		ctx,
 		serviceDeployment,
 		plan,
 		generateManifestProps.RequestParams,
@@ -90,8 +102,8 @@ func (m manifestGenerator) GenerateManifest(
 	return manifest, err
 }
 
-func (m manifestGenerator) findPlans(planID string, previousPlanID *string) (serviceadapter.Plan, *serviceadapter.Plan, error) {
-	plan, err := m.findPlan(planID)
+func findPlans(serviceOffering config.ServiceOffering, planID string, previousPlanID *string) (serviceadapter.Plan, *serviceadapter.Plan, error) {
+	plan, err := findPlan(serviceOffering, planID)
 	if err != nil {
 		return serviceadapter.Plan{}, nil, err
 	}
@@ -100,7 +112,7 @@ func (m manifestGenerator) findPlans(planID string, previousPlanID *string) (ser
 		return plan, nil, nil
 	}
 
-	previousPlan, err := m.findPreviousPlan(*previousPlanID)
+	previousPlan, err := findPreviousPlan(serviceOffering, *previousPlanID)
 	if err != nil {
 		return serviceadapter.Plan{}, nil, err
 	}
@@ -108,21 +120,21 @@ func (m manifestGenerator) findPlans(planID string, previousPlanID *string) (ser
 	return plan, previousPlan, nil
 }
 
-func (m manifestGenerator) findPlan(planID string) (serviceadapter.Plan, error) {
-	plan, found := m.serviceOffering.FindPlanByID(planID)
+func findPlan(serviceOffering config.ServiceOffering, planID string) (serviceadapter.Plan, error) {
+	plan, found := serviceOffering.FindPlanByID(planID)
 	if !found {
 		return serviceadapter.Plan{}, broker.PlanNotFoundError{PlanGUID: planID}
 	}
 
-	return plan.AdapterPlan(m.serviceOffering.GlobalProperties), nil
+	return plan.AdapterPlan(serviceOffering.GlobalProperties), nil
 }
 
-func (m manifestGenerator) findPreviousPlan(previousPlanID string) (*serviceadapter.Plan, error) {
-	previousPlan, found := m.serviceOffering.FindPlanByID(previousPlanID)
+func findPreviousPlan(serviceOffering config.ServiceOffering, previousPlanID string) (*serviceadapter.Plan, error) {
+	previousPlan, found := serviceOffering.FindPlanByID(previousPlanID)
 	if !found {
 		return new(serviceadapter.Plan), broker.PlanNotFoundError{PlanGUID: previousPlanID}
 	}
 
-	abridgedPlan := previousPlan.AdapterPlan(m.serviceOffering.GlobalProperties)
+	abridgedPlan := previousPlan.AdapterPlan(serviceOffering.GlobalProperties)
 	return &abridgedPlan, nil
 }
@@ -7,6 +7,7 @@
 package task_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -175,7 +176,7 @@ var _ = Describe("Manifest Generator", func() {
 		})
 
 		JustBeforeEach(func() {
-			generateManifestOutput, err = mg.GenerateManifest(GenerateManifestProperties{
+			generateManifestOutput, err = mg.GenerateManifest(context.Background(), GenerateManifestProperties{
 				DeploymentName:  deploymentName,
 				PlanID:          planGUID,
 				RequestParams:   requestParams,
@@ -214,7 +215,7 @@ var _ = Describe("Manifest Generator", func() {
 			})
 
 			It("calls the service adapter with the service deployment", func() {
-				passedServiceDeployment, _, _, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, passedServiceDeployment, _, _, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				expectedServiceDeployment := serviceadapter.ServiceDeployment{
 					DeploymentName: deploymentName,
 					Releases:       serviceReleases,
@@ -224,37 +225,37 @@ var _ = Describe("Manifest Generator", func() {
 			})
 
 			It("calls the service adapter with the plan", func() {
-				_, passedPlan, _, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, passedPlan, _, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedPlan.InstanceGroups).To(Equal(existingPlan.InstanceGroups))
 			})
 
 			It("calls the service adapter with the request params", func() {
-				_, _, passedRequestParams, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, _, passedRequestParams, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedRequestParams).To(Equal(requestParams))
 			})
 
 			It("calls the service adapter with the old manifest", func() {
-				_, _, _, passedOldManifest, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, _, _, passedOldManifest, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedOldManifest).To(Equal(oldManifest))
 			})
 
 			It("calls the service adapter with the secrets map", func() {
-				_, _, _, _, _, passedSecretsMap, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, _, _, _, _, passedSecretsMap, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedSecretsMap).To(Equal(oldSecretsMap))
 			})
 
 			It("calls the service adapter with the configs map", func() {
-				_, _, _, _, _, _, passedConfigsMap, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, _, _, _, _, _, passedConfigsMap, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedConfigsMap).To(Equal(oldConfigsMap))
 			})
 
 			It("calls the service adapter with the provided uaa client", func() {
-				_, _, _, _, _, _, _, passedUAAClient, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, _, _, _, _, _, _, passedUAAClient, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedUAAClient).To(Equal(expectedUAAClient))
 			})
 
 			It("merges global and plan properties", func() {
-				_, actualPlan, _, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, actualPlan, _, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				expectedProperties := serviceadapter.Properties{
 					"a_global_property":          "global_value",
 					"some_other_global_property": "other_global_value",
@@ -270,12 +271,12 @@ var _ = Describe("Manifest Generator", func() {
 				})
 
 				It("calls the service adapter with the previous plan", func() {
-					_, _, _, _, passedPreviousPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+					_, _, _, _, _, passedPreviousPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 					Expect(passedPreviousPlan.InstanceGroups).To(Equal(secondPlan.InstanceGroups))
 				})
 
 				It("merges global and previous plan properties, overriding global with plan props", func() {
-					_, _, _, _, previousPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+					_, _, _, _, _, previousPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 					expectedProperties := serviceadapter.Properties{
 						"a_global_property":          "overrides_global_value",
 						"some_other_global_property": "other_global_value",
@@ -292,7 +293,7 @@ var _ = Describe("Manifest Generator", func() {
 				})
 
 				It("calls the service adapter with the nil previous plan", func() {
-					_, _, _, _, passedPreviousPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+					_, _, _, _, _, passedPreviousPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 					Expect(passedPreviousPlan).To(BeNil())
 				})
 			})
@@ -334,4 +335,35 @@ var _ = Describe("Manifest Generator", func() {
 			})
 		})
 	})
+
+	Describe("SetOfferingStore", func() {
+		It("generates manifests from the offering and deployment in the store", func() {
+			reloadedPlan := config.Plan{
+				ID:         "reloaded-plan-id",
+				Properties: serviceadapter.Properties{"reloaded": true},
+			}
+			reloadedReleases := serviceadapter.ServiceReleases{{Name: "name", Version: "new-vers", Jobs: []string{"a"}}}
+			reloadedStemcells := []serviceadapter.Stemcell{{OS: "ubuntu-jammy", Version: "1.1"}}
+
+			offerings := config.NewOfferingStore([]config.OfferingConfig{{ServiceCatalog: serviceCatalog}})
+			reloadable := NewManifestGenerator(serviceAdapter, serviceCatalog, serviceStemcells, serviceReleases)
+			reloadable.SetOfferingStore(offerings)
+			offerings.SetOfferings([]config.OfferingConfig{{
+				ServiceCatalog:    config.ServiceOffering{ID: serviceOfferingID, Plans: config.Plans{reloadedPlan}},
+				ServiceDeployment: config.ServiceDeployment{Releases: reloadedReleases, Stemcells: reloadedStemcells},
+			}})
+
+			_, err := reloadable.GenerateManifest(context.Background(), GenerateManifestProperties{DeploymentName: deploymentName, PlanID: reloadedPlan.ID}, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(serviceAdapter.GenerateManifestCallCount()).To(Equal(1))
+			_, serviceDeployment, plan, _, _, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+			Expect(serviceDeployment.Releases).To(Equal(reloadedReleases))
+			Expect(serviceDeployment.Stemcells).To(Equal(reloadedStemcells))
+			Expect(plan.Properties).To(HaveKeyWithValue("reloaded", true))
+
+			_, err = reloadable.GenerateManifest(context.Background(), GenerateManifestProperties{DeploymentName: deploymentName, PlanID: existingPlanID}, logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -7,6 +7,7 @@
 package task
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -17,25 +18,29 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o fakes/fake_bosh_client.go . BoshClient
 type BoshClient interface {
-	Deploy(manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error)
-	Recreate(deploymentName, contextID string, logger *log.Logger, taskReporter *boshdirector.AsyncTaskReporter) (int, error)
-	GetTasksInProgress(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
-	GetTask(taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
-	GetNormalisedTasksByContext(deploymentName, contextID string, logger *log.Logger) (boshdirector.BoshTasks, error)
-	GetDeployment(name string, logger *log.Logger) ([]byte, bool, error)
-	GetConfigs(configName string, logger *log.Logger) ([]boshdirector.BoshConfig, error)
-	UpdateConfig(configType, configName string, configContent []byte, logger *log.Logger) error
-	GetEvents(deploymentName, eventType string, logger *log.Logger) ([]boshdirector.BoshEvent, error)
+	Deploy(ctx context.Context, manifest []byte, contextID string, logger *log.Logger, reporter *boshdirector.AsyncTaskReporter) (int, error)
+	Recreate(ctx context.Context, deploymentName, contextID string, logger *log.Logger, taskReporter *boshdirector.AsyncTaskReporter) (int, error)
+	GetTasksInProgress(ctx context.Context, deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
+	GetTask(ctx context.Context, taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
+	GetNormalisedTasksByContext(ctx context.Context, deploymentName, contextID string, logger *log.Logger) (boshdirector.BoshTasks, error)
+	GetDeployment(ctx context.Context, name string, logger *log.Logger) ([]byte, bool, error)
+	GetConfigs(ctx context.Context, configName string, logger *log.Logger) ([]boshdirector.BoshConfig, error)
+	UpdateConfig(ctx context.Context, configType, configName string, configContent []byte, logger *log.Logger) error
+	DeleteConfig(ctx context.Context, configType, configName string, logger *log.Logger) (bool, error)
+	GetEvents(ctx context.Context, deploymentName, eventType string, logger *log.Logger) ([]boshdirector.BoshEvent, error)
 }
 
 //counterfeiter:generate -o fakes/fake_manifest_generator.go . ManifestGenerator
 type ManifestGenerator interface {
 	GenerateManifest(
+		ctx context.Context,
 		generateManifestProperties GenerateManifestProperties,
 		logger *log.Logger,
 	) (serviceadapter.MarshalledGenerateManifest, error)
@@ -60,7 +65,7 @@ type ODBSecrets interface {
 
 //counterfeiter:generate -o fakes/fake_bulk_setter.go . BulkSetter
 type BulkSetter interface {
-	BulkSet([]broker.ManifestSecret) error
+	BulkSet(context.Context, []broker.ManifestSecret) error
 }
 
 type Deployer struct {
@@ -82,6 +87,7 @@ func NewDeployer(boshClient BoshClient, manifestGenerator ManifestGenerator, odb
 }
 
 func (d Deployer) Create(
+	ctx context.Context,
 	deploymentName string,
 	planID string,
 	requestParams map[string]interface{},
@@ -89,7 +95,7 @@ func (d Deployer) Create(
 	uaaClientObject map[string]string,
 	logger *log.Logger,
 ) (int, []byte, map[string]any, error) {
-	err := d.assertNoOperationsInProgress(deploymentName, logger)
+	err := d.assertNoOperationsInProgress(ctx, deploymentName, logger)
 	if err != nil {
 		return 0, nil, nil, err
 	}
@@ -101,10 +107,11 @@ func (d Deployer) Create(
 		UAAClient:      uaaClientObject,
 	}
 
-	return d.doDeploy(generateManifestProperties, "create", boshContextID, logger)
+	return d.doDeploy(ctx, generateManifestProperties, "create", boshContextID, logger)
 }
 
 func (d Deployer) Upgrade(
+	ctx context.Context,
 	deploymentName string,
 	plan config.Plan,
 	requestParams map[string]interface{},
@@ -112,25 +119,79 @@ func (d Deployer) Upgrade(
 	uaaClientObject map[string]string,
 	logger *log.Logger,
 ) (int, []byte, map[string]any, error) {
-	err := d.assertNoOperationsInProgress(deploymentName, logger)
+	err := d.assertNoOperationsInProgress(ctx, deploymentName, logger)
 	if err != nil {
 		return 0, nil, nil, err
 	}
 
-	oldManifest, err := d.getDeploymentManifest(deploymentName, logger)
+	generateManifestProperties, err := d.upgradeProperties(ctx, deploymentName, plan, requestParams, uaaClientObject, logger)
 	if err != nil {
 		return 0, nil, nil, err
 	}
 
-	var oldConfigs map[string]string
-	if !d.DisableBoshConfigs {
-		oldConfigs, err = d.getConfigMap(deploymentName, logger)
-		if err != nil {
-			return 0, nil, nil, err
-		}
+	return d.doDeploy(ctx, generateManifestProperties, "upgrade", boshContextID, logger)
+}
+
+// RotateSecrets redeploys an instance as Upgrade does, passing the service
+// adapter the values of the secrets of the deployed manifest that are not being
+// rotated. The adapter generates new values for the ODB managed secrets missing
+// from the map, which are stored as new versions.
+func (d Deployer) RotateSecrets(
+	ctx context.Context,
+	deploymentName string,
+	plan config.Plan,
+	requestParams map[string]interface{},
+	boshContextID string,
+	secretsMap map[string]string,
+	uaaClientObject map[string]string,
+	logger *log.Logger,
+) (int, []byte, map[string]any, error) {
+	err := d.assertNoOperationsInProgress(ctx, deploymentName, logger)
+	if err != nil {
+		return 0, nil, nil, err
 	}
 
-	generateManifestProperties := GenerateManifestProperties{
+	generateManifestProperties, err := d.upgradeProperties(ctx, deploymentName, plan, requestParams, uaaClientObject, logger)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	generateManifestProperties.SecretsMap = secretsMap
+
+	return d.doDeploy(ctx, generateManifestProperties, "rotate-secrets", boshContextID, logger)
+}
+
+// PreviewUpgrade generates the manifest Upgrade would deploy and returns how it
+// differs from the deployed manifest, without deploying anything.
+func (d Deployer) PreviewUpgrade(
+	ctx context.Context,
+	deploymentName string,
+	plan config.Plan,
+	requestParams map[string]interface{},
+	uaaClientObject map[string]string,
+	logger *log.Logger,
+) (broker.ManifestDiff, error) {
+	generateManifestProperties, err := d.upgradeProperties(ctx, deploymentName, plan, requestParams, uaaClientObject, logger)
+	if err != nil {
+		return broker.ManifestDiff{}, err
+	}
+
+	return d.preview(ctx, generateManifestProperties, logger)
+}
+
+func (d Deployer) upgradeProperties(
+	ctx context.Context,
+	deploymentName string,
+	plan config.Plan,
+	requestParams map[string]interface{},
+	uaaClientObject map[string]string,
+	logger *log.Logger,
+) (GenerateManifestProperties, error) {
+	oldManifest, oldConfigs, err := d.getDeployedState(ctx, deploymentName, logger)
+	if err != nil {
+		return GenerateManifestProperties{}, err
+	}
+
+	return GenerateManifestProperties{
 		DeploymentName:  deploymentName,
 		PlanID:          plan.ID,
 		OldManifest:     oldManifest,
@@ -138,31 +199,92 @@ func (d Deployer) Upgrade(
 		PreviousConfigs: oldConfigs,
 		UAAClient:       uaaClientObject,
 		RequestParams:   requestParams,
-	}
-
-	return d.doDeploy(generateManifestProperties, "upgrade", boshContextID, logger)
+	}, nil
 }
 
 func (d Deployer) Recreate(
+	ctx context.Context,
 	deploymentName,
 	planID,
 	boshContextID string,
 	logger *log.Logger,
 ) (int, error) {
-	if err := d.assertNoOperationsInProgress(deploymentName, logger); err != nil {
+	if err := d.assertNoOperationsInProgress(ctx, deploymentName, logger); err != nil {
 		return 0, err
 	}
 
-	taskID, err := d.boshClient.Recreate(deploymentName, boshContextID, logger, boshdirector.NewAsyncTaskReporter())
+	taskID, err := d.boshClient.Recreate(ctx, deploymentName, boshContextID, logger, boshdirector.NewAsyncTaskReporter())
 	if err != nil {
-		logger.Printf("failed to recreate deployment %q: %s", deploymentName, err)
+		loggerfactory.WithLevel(logger, loggerfactory.ErrorLevel).Printf("failed to recreate deployment %q: %s", deploymentName, err)
 		return 0, err
 	}
 	logger.Printf("Submitted BOSH recreate with task ID %d for deployment %q", taskID, deploymentName)
 	return taskID, nil
 }
 
+// Rollback redeploys a manifest as it was, restoring the configs it was deployed
+// with and removing those added since. The service adapter is not involved.
+func (d Deployer) Rollback(
+	ctx context.Context,
+	deploymentName string,
+	manifest []byte,
+	configs map[string]string,
+	boshContextID string,
+	logger *log.Logger,
+) (int, error) {
+	if err := d.assertNoOperationsInProgress(ctx, deploymentName, logger); err != nil {
+		return 0, err
+	}
+
+	if !d.DisableBoshConfigs {
+		deployedConfigs, err := d.getConfigMap(ctx, deploymentName, logger)
+		if err != nil {
+			return 0, fmt.Errorf("error getting configs: %s\n", err)
+		}
+		for configType, configContent := range configs {
+			if err := d.boshClient.UpdateConfig(ctx, configType, deploymentName, []byte(configContent), logger); err != nil {
+				return 0, fmt.Errorf("error updating config: %s\n", err)
+			}
+		}
+		for configType := range deployedConfigs {
+			if _, found := configs[configType]; found {
+				continue
+			}
+			if _, err := d.boshClient.DeleteConfig(ctx, configType, deploymentName, logger); err != nil {
+				return 0, fmt.Errorf("error deleting config: %s\n", err)
+			}
+		}
+	}
+
+	taskID, err := d.boshClient.Deploy(ctx, manifest, boshContextID, logger, boshdirector.NewAsyncTaskReporter())
+	if err != nil {
+		return 0, fmt.Errorf("error deploying instance: %s\n", err)
+	}
+	logger.Printf("Bosh task ID for rollback deployment %s is %d\n", deploymentName, taskID)
+
+	return taskID, nil
+}
+
+// CheckDeployable fails when an operation is in progress on the deployment or,
+// unless the check is skipped, when it has changes that were not applied.
+func (d Deployer) CheckDeployable(ctx context.Context, deploymentName, deployedPlanID string, secretsMap map[string]string, logger *log.Logger) error {
+	if err := d.assertNoOperationsInProgress(ctx, deploymentName, logger); err != nil {
+		return err
+	}
+
+	if d.SkipCheckForPendingChanges {
+		return nil
+	}
+
+	oldManifest, oldConfigs, err := d.getDeployedState(ctx, deploymentName, logger)
+	if err != nil {
+		return err
+	}
+	return d.checkForPendingChanges(ctx, deploymentName, &deployedPlanID, oldManifest, secretsMap, oldConfigs, logger)
+}
+
 func (d Deployer) Update(
+	ctx context.Context,
 	deploymentName,
 	planID string,
 	requestParams map[string]interface{},
@@ -172,25 +294,17 @@ func (d Deployer) Update(
 	uaaClientObject map[string]string,
 	logger *log.Logger,
 ) (int, []byte, map[string]any, error) {
-	if err := d.assertNoOperationsInProgress(deploymentName, logger); err != nil {
+	if err := d.assertNoOperationsInProgress(ctx, deploymentName, logger); err != nil {
 		return 0, nil, nil, err
 	}
 
-	oldManifest, err := d.getDeploymentManifest(deploymentName, logger)
+	oldManifest, oldConfigs, err := d.getDeployedState(ctx, deploymentName, logger)
 	if err != nil {
 		return 0, nil, nil, err
 	}
 
-	var oldConfigs map[string]string
-	if !d.DisableBoshConfigs {
-		oldConfigs, err = d.getConfigMap(deploymentName, logger)
-		if err != nil {
-			return 0, nil, nil, err
-		}
-	}
-
 	if !d.SkipCheckForPendingChanges {
-		if err := d.checkForPendingChanges(deploymentName, previousPlanID, oldManifest, oldSecretsMap, oldConfigs, logger); err != nil {
+		if err := d.checkForPendingChanges(ctx, deploymentName, previousPlanID, oldManifest, oldSecretsMap, oldConfigs, logger); err != nil {
 			return 0, nil, nil, err
 		}
 	}
@@ -206,11 +320,58 @@ func (d Deployer) Update(
 		UAAClient:       uaaClientObject,
 	}
 
-	return d.doDeploy(generateManifestProperties, "update", boshContextID, logger)
+	return d.doDeploy(ctx, generateManifestProperties, "update", boshContextID, logger)
 }
 
-func (d Deployer) getDeploymentManifest(deploymentName string, logger *log.Logger) ([]byte, error) {
-	oldManifest, found, err := d.boshClient.GetDeployment(deploymentName, logger)
+// PreviewUpdate generates the manifest Update would deploy and returns how it
+// differs from the deployed manifest, without deploying anything. Pending
+// changes are not an error here: they are part of the difference.
+func (d Deployer) PreviewUpdate(
+	ctx context.Context,
+	deploymentName,
+	planID string,
+	requestParams map[string]interface{},
+	previousPlanID *string,
+	oldSecretsMap map[string]string,
+	uaaClientObject map[string]string,
+	logger *log.Logger,
+) (broker.ManifestDiff, error) {
+	oldManifest, oldConfigs, err := d.getDeployedState(ctx, deploymentName, logger)
+	if err != nil {
+		return broker.ManifestDiff{}, err
+	}
+
+	return d.preview(ctx, GenerateManifestProperties{
+		DeploymentName:  deploymentName,
+		PlanID:          planID,
+		RequestParams:   requestParams,
+		OldManifest:     oldManifest,
+		PreviousPlanID:  previousPlanID,
+		SecretsMap:      oldSecretsMap,
+		PreviousConfigs: oldConfigs,
+		UAAClient:       uaaClientObject,
+	}, logger)
+}
+
+func (d Deployer) getDeployedState(ctx context.Context, deploymentName string, logger *log.Logger) ([]byte, map[string]string, error) {
+	oldManifest, err := d.getDeploymentManifest(ctx, deploymentName, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var oldConfigs map[string]string
+	if !d.DisableBoshConfigs {
+		oldConfigs, err = d.getConfigMap(ctx, deploymentName, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return oldManifest, oldConfigs, nil
+}
+
+func (d Deployer) getDeploymentManifest(ctx context.Context, deploymentName string, logger *log.Logger) ([]byte, error) {
+	oldManifest, found, err := d.boshClient.GetDeployment(ctx, deploymentName, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -222,22 +383,26 @@ func (d Deployer) getDeploymentManifest(deploymentName string, logger *log.Logge
 	return oldManifest, nil
 }
 
-func (d Deployer) getConfigMap(deploymentName string, logger *log.Logger) (map[string]string, error) {
-	boshConfigs, err := d.boshClient.GetConfigs(deploymentName, logger)
+func (d Deployer) getConfigMap(ctx context.Context, deploymentName string, logger *log.Logger) (map[string]string, error) {
+	boshConfigs, err := d.boshClient.GetConfigs(ctx, deploymentName, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	configs := map[string]string{}
 	for _, config := range boshConfigs {
+		switch config.Type {
+		case broker.InstanceMetadataConfigType, broker.InstanceSnapshotConfigType, broker.QuotaReservationConfigType, broker.OrphanMarkerConfigType, broker.BindingsConfigType:
+			continue
+		}
 		configs[config.Type] = config.Content
 	}
 
 	return configs, nil
 }
 
-func (d Deployer) assertNoOperationsInProgress(deploymentName string, logger *log.Logger) error {
-	tasksInProgress, err := d.boshClient.GetTasksInProgress(deploymentName, logger)
+func (d Deployer) assertNoOperationsInProgress(ctx context.Context, deploymentName string, logger *log.Logger) error {
+	tasksInProgress, err := d.boshClient.GetTasksInProgress(ctx, deploymentName, logger)
 	if err != nil {
 		return broker.NewServiceError(fmt.Errorf("error getting tasks for deployment %s: %s\n", deploymentName, err))
 	}
@@ -251,6 +416,7 @@ func (d Deployer) assertNoOperationsInProgress(deploymentName string, logger *lo
 }
 
 func (d Deployer) checkForPendingChanges(
+	ctx context.Context,
 	deploymentName string,
 	previousPlanID *string,
 	rawOldManifest RawBoshManifest,
@@ -259,6 +425,7 @@ func (d Deployer) checkForPendingChanges(
 	logger *log.Logger,
 ) error {
 	regeneratedManifestContent, err := d.manifestGenerator.GenerateManifest(
+		ctx,
 		GenerateManifestProperties{
 			DeploymentName:  deploymentName,
 			PlanID:          *previousPlanID,
@@ -287,8 +454,54 @@ func (d Deployer) checkForPendingChanges(
 	return nil
 }
 
-func (d Deployer) doDeploy(generateManifestProperties GenerateManifestProperties, operationType, boshContextID string, logger *log.Logger) (int, []byte, map[string]any, error) {
-	generateManifestOutput, err := d.manifestGenerator.GenerateManifest(generateManifestProperties, logger)
+// preview resolves the ODB managed secret references to the paths they would be
+// stored at, so that they compare equal to the deployed manifest, but does not
+// store the secrets.
+func (d Deployer) preview(ctx context.Context, generateManifestProperties GenerateManifestProperties, logger *log.Logger) (broker.ManifestDiff, error) {
+	generateManifestOutput, err := d.manifestGenerator.GenerateManifest(ctx, generateManifestProperties, logger)
+	if err != nil {
+		return broker.ManifestDiff{}, err
+	}
+	manifest := generateManifestOutput.Manifest
+
+	if d.bulkSetter != nil && !reflect.ValueOf(d.bulkSetter).IsNil() {
+		secrets := d.odbSecrets.GenerateSecretPaths(generateManifestProperties.DeploymentName, manifest, generateManifestOutput.ODBManagedSecrets)
+		manifest = d.odbSecrets.ReplaceODBRefs(manifest, secrets)
+	}
+
+	return DiffManifests(generateManifestProperties.OldManifest, []byte(manifest), previewSecretValues(generateManifestProperties.SecretsMap, generateManifestOutput.ODBManagedSecrets))
+}
+
+// previewSecretValues lists the secrets a generated manifest may contain: those
+// resolved from CredHub for the adapter and those the adapter generated.
+func previewSecretValues(secretsMap map[string]string, odbManagedSecrets serviceadapter.ODBManagedSecrets) []string {
+	var values []string
+	for _, value := range secretsMap {
+		values = append(values, value)
+	}
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			values = append(values, v)
+		case map[string]interface{}:
+			for _, value := range v {
+				collect(value)
+			}
+		case []interface{}:
+			for _, value := range v {
+				collect(value)
+			}
+		}
+	}
+	for _, value := range odbManagedSecrets {
+		collect(value)
+	}
+	return values
+}
+
+func (d Deployer) doDeploy(ctx context.Context, generateManifestProperties GenerateManifestProperties, operationType, boshContextID string, logger *log.Logger) (int, []byte, map[string]any, error) {
+	generateManifestOutput, err := d.manifestGenerator.GenerateManifest(ctx, generateManifestProperties, logger)
 	if err != nil {
 		return 0, nil, nil, err
 	}
@@ -296,7 +509,10 @@ func (d Deployer) doDeploy(generateManifestProperties GenerateManifestProperties
 
 	if d.bulkSetter != nil && !reflect.ValueOf(d.bulkSetter).IsNil() {
 		secrets := d.odbSecrets.GenerateSecretPaths(generateManifestProperties.DeploymentName, manifest, generateManifestOutput.ODBManagedSecrets)
-		if err = d.bulkSetter.BulkSet(secrets); err != nil {
+		_, span := tracing.Start(ctx, "credhub.bulk-set")
+		err = d.bulkSetter.BulkSet(ctx, secrets)
+		span.End(err)
+		if err != nil {
 			return 0, nil, nil, err
 		}
 		manifest = d.odbSecrets.ReplaceODBRefs(generateManifestOutput.Manifest, secrets)
@@ -307,14 +523,14 @@ func (d Deployer) doDeploy(generateManifestProperties GenerateManifestProperties
 	}
 	if !d.DisableBoshConfigs {
 		for configType, configContent := range generateManifestOutput.Configs {
-			err := d.boshClient.UpdateConfig(configType, generateManifestProperties.DeploymentName, []byte(configContent), logger)
+			err := d.boshClient.UpdateConfig(ctx, configType, generateManifestProperties.DeploymentName, []byte(configContent), logger)
 			if err != nil {
 				return 0, nil, nil, fmt.Errorf("error updating config: %s\n", err)
 			}
 		}
 	}
 
-	boshTaskID, err := d.boshClient.Deploy([]byte(manifest), boshContextID, logger, boshdirector.NewAsyncTaskReporter())
+	boshTaskID, err := d.boshClient.Deploy(ctx, []byte(manifest), boshContextID, logger, boshdirector.NewAsyncTaskReporter())
 	if err != nil {
 		return 0, nil, nil, fmt.Errorf("error deploying instance: %s\n", err)
 	}
@@ -7,6 +7,7 @@
 package task_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -101,7 +102,7 @@ var _ = Describe("Deployer", func() {
 
 	Describe("Create", func() {
 		JustBeforeEach(func() {
-			returnedTaskID, deployedManifest, _, deployError = deployer.Create(
+			returnedTaskID, deployedManifest, _, deployError = deployer.Create(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -137,7 +138,7 @@ var _ = Describe("Deployer", func() {
 			It("applies the configs to bosh", func() {
 				Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
 
-				configType, configName, configContent, _ := boshClient.UpdateConfigArgsForCall(0)
+				_, configType, configName, configContent, _ := boshClient.UpdateConfigArgsForCall(0)
 				Expect(configType).To(Equal("config-type"))
 				Expect(configName).To(Equal(deploymentName))
 				Expect(configContent).To(Equal([]byte("config-content")))
@@ -204,17 +205,17 @@ var _ = Describe("Deployer", func() {
 
 				By("calling bulkset")
 				Expect(bulkSetter.BulkSetCallCount()).To(Equal(1))
-				actualManifestSecrets := bulkSetter.BulkSetArgsForCall(0)
+				_, actualManifestSecrets := bulkSetter.BulkSetArgsForCall(0)
 				Expect(actualManifestSecrets).To(Equal(manifestSecrets))
 
 				By("substituting the odb_secret marker with the credhub path")
-				deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+				_, deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
 				Expect(string(deployedManifest)).To(Equal(manifestWithPaths))
 			})
 
 			It("errors when fail to store the secret", func() {
 				bulkSetter.BulkSetReturns(errors.New("what is this?"))
-				_, _, _, deployError = deployer.Create(deploymentName, planID, requestParams, boshContextID, uaaClientMap, logger)
+				_, _, _, deployError = deployer.Create(context.Background(), deploymentName, planID, requestParams, boshContextID, uaaClientMap, logger)
 				Expect(deployError).To(MatchError(ContainSubstring("what is this?")))
 			})
 
@@ -225,7 +226,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("doesn't error", func() {
-					_, _, _, deployError = deployer.Create(deploymentName, planID, requestParams, boshContextID, uaaClientMap, logger)
+					_, _, _, deployError = deployer.Create(context.Background(), deploymentName, planID, requestParams, boshContextID, uaaClientMap, logger)
 					Expect(deployError).ToNot(HaveOccurred())
 
 					Expect(bulkSetter.BulkSetCallCount()).To(Equal(0))
@@ -235,7 +236,7 @@ var _ = Describe("Deployer", func() {
 
 		It("pass the context to the service adapter", func() {
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(1))
-			generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 			Expect(generateManifestProps.DeploymentName).To(Equal("some-deployment-name"))
 			Expect(generateManifestProps.PlanID).To(Equal("some-plan-id"))
 			Expect(generateManifestProps.RequestParams).To(Equal(requestParams))
@@ -255,7 +256,7 @@ var _ = Describe("Deployer", func() {
 
 			It("checks tasks for the deployment", func() {
 				Expect(boshClient.GetTasksInProgressCallCount()).To(Equal(1))
-				actualDeploymentName, _ := boshClient.GetTasksInProgressArgsForCall(0)
+				_, actualDeploymentName, _ := boshClient.GetTasksInProgressArgsForCall(0)
 				Expect(actualDeploymentName).To(Equal(deploymentName))
 			})
 
@@ -265,12 +266,12 @@ var _ = Describe("Deployer", func() {
 
 			It("Creates a bosh deployment using provided manifest", func() {
 				Expect(boshClient.DeployCallCount()).To(Equal(1))
-				deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+				_, deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
 				Expect(string(deployedManifest)).To(Equal(generatedManifest))
 			})
 
 			It("returns the labels from the service adapter", func() {
-				returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Create(
+				returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Create(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -294,7 +295,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("returns nil labels gracefully", func() {
-					returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Create(
+					returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Create(context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -321,7 +322,7 @@ var _ = Describe("Deployer", func() {
 
 				It("invokes boshdirector's Create with context ID", func() {
 					Expect(boshClient.DeployCallCount()).To(Equal(1))
-					_, actualBoshContextID, _, _ := boshClient.DeployArgsForCall(0)
+					_, _, actualBoshContextID, _, _ := boshClient.DeployArgsForCall(0)
 					Expect(actualBoshContextID).To(Equal(boshContextID))
 				})
 			})
@@ -407,7 +408,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("starts upgrading successfully", func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -427,30 +428,30 @@ var _ = Describe("Deployer", func() {
 
 				By("checking that the deployment exists")
 				Expect(boshClient.GetDeploymentCallCount()).To(Equal(1))
-				actualDeploymentName, _ := boshClient.GetDeploymentArgsForCall(0)
+				_, actualDeploymentName, _ := boshClient.GetDeploymentArgsForCall(0)
 				Expect(actualDeploymentName).To(Equal(deploymentName))
 
 				By("checking incomplete tasks for the deployment")
 				Expect(boshClient.GetTasksInProgressCallCount()).To(Equal(1))
-				actualDeploymentName, _ = boshClient.GetTasksInProgressArgsForCall(0)
+				_, actualDeploymentName, _ = boshClient.GetTasksInProgressArgsForCall(0)
 				Expect(actualDeploymentName).To(Equal(deploymentName))
 
 				By("Creating a bosh deployment using generated manifest")
 				Expect(boshClient.DeployCallCount()).To(Equal(1))
-				deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+				_, deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
 				Expect(string(deployedManifest)).To(Equal(generatedManifest))
 
 				By("logging the bosh task ID returned by the director")
 				Expect(logBuffer.String()).To(ContainSubstring(fmt.Sprintf("Bosh task ID for upgrade deployment %s is %d", deploymentName, boshTaskID)))
 
 				By("passing the right arguments to generate manifest")
-				generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 				Expect(generateManifestProps.UAAClient).To(Equal(uaaClientMap))
 				Expect(generateManifestProps.RequestParams).To(Equal(requestParams))
 			})
 
 			It("returns the labels from the service adapter", func() {
-				returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Upgrade(
+				returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -471,7 +472,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("invokes boshdirector's Create with context ID", func() {
-					returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+					returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 						deploymentName,
 						plan,
 						requestParams,
@@ -481,7 +482,7 @@ var _ = Describe("Deployer", func() {
 					)
 
 					Expect(boshClient.DeployCallCount()).To(Equal(1))
-					_, actualBoshContextID, _, _ := boshClient.DeployArgsForCall(0)
+					_, _, actualBoshContextID, _, _ := boshClient.DeployArgsForCall(0)
 					Expect(actualBoshContextID).To(Equal(boshContextID))
 				})
 			})
@@ -493,7 +494,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("returns a deployment not found error", func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -513,7 +514,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("returns a deployment not found error", func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -528,7 +529,7 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("returns the bosh task ID and new manifest", func() {
-			returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+			returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 				deploymentName,
 				plan,
 				requestParams,
@@ -549,7 +550,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("fails because deployment is still in progress", func() {
-					_, _, _, deployError = deployer.Upgrade(
+					_, _, _, deployError = deployer.Upgrade(context.Background(),
 						deploymentName,
 						plan,
 						requestParams,
@@ -568,7 +569,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("wraps the error", func() {
-					_, _, _, deployError = deployer.Upgrade(
+					_, _, _, deployError = deployer.Upgrade(context.Background(),
 						deploymentName,
 						plan,
 						requestParams,
@@ -587,7 +588,7 @@ var _ = Describe("Deployer", func() {
 				boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{}, nil)
 				boshClient.DeployReturns(0, errors.New("error deploying"))
 
-				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -604,7 +605,7 @@ var _ = Describe("Deployer", func() {
 			It("sends the old configs to service adapter", func() {
 				boshClient.GetConfigsReturns(boshConfigs, nil)
 
-				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -613,7 +614,27 @@ var _ = Describe("Deployer", func() {
 					logger,
 				)
 
-				generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				Expect(generateManifestProps.PreviousConfigs).To(Equal(configsMap))
+			})
+
+			It("does not send the broker's instance metadata to the service adapter", func() {
+				boshClient.GetConfigsReturns(append(boshConfigs, boshdirector.BoshConfig{
+					Type:    broker.InstanceMetadataConfigType,
+					Name:    deploymentName,
+					Content: `{"plan_id":"some-plan"}`,
+				}), nil)
+
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
+					deploymentName,
+					plan,
+					requestParams,
+					boshContextID,
+					uaaClientMap,
+					logger,
+				)
+
+				_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 				Expect(generateManifestProps.PreviousConfigs).To(Equal(configsMap))
 			})
 		})
@@ -622,7 +643,7 @@ var _ = Describe("Deployer", func() {
 			It("wraps the error", func() {
 				boshClient.GetConfigsReturns(nil, errors.New("some-error"))
 
-				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -640,7 +661,7 @@ var _ = Describe("Deployer", func() {
 				deployer.DisableBoshConfigs = true
 				boshClient.DeployReturns(boshTaskID, nil)
 
-				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Upgrade(context.Background(),
 					deploymentName,
 					plan,
 					requestParams,
@@ -657,6 +678,148 @@ var _ = Describe("Deployer", func() {
 		})
 	})
 
+	Describe("RotateSecrets", func() {
+		BeforeEach(func() {
+			oldManifest = []byte("---\nname: a-manifest")
+			boshClient.GetDeploymentReturns(oldManifest, true, nil)
+			boshClient.GetConfigsReturns(boshConfigs, nil)
+			boshClient.DeployReturns(boshTaskID, nil)
+		})
+
+		It("redeploys with the secrets map it is given and stores the new ODB managed secrets", func() {
+			secrets := []broker.ManifestSecret{{Name: "foo", Value: "new-value", Path: "/odb/some-path/foo"}}
+			odbSecrets.GenerateSecretPathsReturns(secrets)
+
+			returnedTaskID, _, _, deployError = deployer.RotateSecrets(context.Background(), deploymentName, plan, requestParams, boshContextID, secretsMap, uaaClientMap, logger)
+
+			Expect(deployError).NotTo(HaveOccurred())
+			Expect(returnedTaskID).To(Equal(boshTaskID))
+
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+			Expect(generateManifestProps.SecretsMap).To(Equal(secretsMap))
+			Expect(generateManifestProps.OldManifest).To(Equal(oldManifest))
+			Expect(generateManifestProps.PreviousPlanID).To(Equal(&plan.ID))
+			Expect(generateManifestProps.PreviousConfigs).To(Equal(configsMap))
+
+			Expect(bulkSetter.BulkSetCallCount()).To(Equal(1))
+			_, actualSecrets := bulkSetter.BulkSetArgsForCall(0)
+			Expect(actualSecrets).To(Equal(secrets))
+			Expect(boshClient.DeployCallCount()).To(Equal(1))
+			Expect(logBuffer.String()).To(ContainSubstring(fmt.Sprintf("Bosh task ID for rotate-secrets deployment %s is %d", deploymentName, boshTaskID)))
+		})
+
+		It("fails when there is an operation in progress", func() {
+			boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{{State: boshdirector.TaskProcessing}}, nil)
+
+			_, _, _, deployError = deployer.RotateSecrets(context.Background(), deploymentName, plan, requestParams, boshContextID, secretsMap, uaaClientMap, logger)
+
+			Expect(deployError).To(BeAssignableToTypeOf(broker.TaskInProgressError{}))
+			Expect(boshClient.DeployCallCount()).To(BeZero())
+		})
+	})
+
+	Describe("PreviewUpgrade", func() {
+		BeforeEach(func() {
+			oldManifest = []byte("---\nname: a-manifest\nreleases:\n- name: redis\n  version: 1.0.0")
+			boshClient.GetDeploymentReturns(oldManifest, true, nil)
+			boshClient.GetConfigsReturns(boshConfigs, nil)
+			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{
+				Manifest: "---\nname: a-manifest\nreleases:\n- name: redis\n  version: 2.0.0",
+				Configs:  map[string]string{"some-config-type": "new-config-content"},
+			}, nil)
+		})
+
+		It("returns the difference from the deployed manifest without deploying it", func() {
+			diff, err := deployer.PreviewUpgrade(context.Background(), deploymentName, plan, requestParams, uaaClientMap, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Releases).To(Equal([]broker.ManifestChange{
+				{Path: "/releases/name=redis/version", Before: "1.0.0", After: "2.0.0"},
+			}))
+
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+			Expect(generateManifestProps.OldManifest).To(Equal(oldManifest))
+			Expect(generateManifestProps.PreviousPlanID).To(Equal(&plan.ID))
+			Expect(generateManifestProps.PreviousConfigs).To(Equal(configsMap))
+			Expect(generateManifestProps.UAAClient).To(Equal(uaaClientMap))
+
+			Expect(boshClient.DeployCallCount()).To(BeZero())
+			Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+			Expect(bulkSetter.BulkSetCallCount()).To(BeZero())
+		})
+
+		It("resolves the ODB managed secrets to their paths before comparing", func() {
+			secrets := []broker.ManifestSecret{{Name: "foo", Path: "/odb/some-path/foo"}}
+			odbSecrets.GenerateSecretPathsReturns(secrets)
+
+			_, err := deployer.PreviewUpgrade(context.Background(), deploymentName, plan, requestParams, uaaClientMap, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(odbSecrets.ReplaceODBRefsCallCount()).To(Equal(1))
+			_, replacedSecrets := odbSecrets.ReplaceODBRefsArgsForCall(0)
+			Expect(replacedSecrets).To(Equal(secrets))
+		})
+
+		It("does not check for operations in progress", func() {
+			boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{{State: boshdirector.TaskProcessing}}, nil)
+
+			_, err := deployer.PreviewUpgrade(context.Background(), deploymentName, plan, requestParams, uaaClientMap, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(boshClient.GetTasksInProgressCallCount()).To(BeZero())
+		})
+
+		It("returns an error when the deployment cannot be found", func() {
+			boshClient.GetDeploymentReturns(nil, false, nil)
+
+			_, err := deployer.PreviewUpgrade(context.Background(), deploymentName, plan, requestParams, uaaClientMap, logger)
+
+			Expect(err).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+		})
+	})
+
+	Describe("PreviewUpdate", func() {
+		BeforeEach(func() {
+			oldManifest = []byte("---\nname: a-manifest\ninstance_groups:\n- name: redis\n  instances: 1")
+			previousPlanID = stringPointer(existingPlanID)
+			boshClient.GetDeploymentReturns(oldManifest, true, nil)
+			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{
+				Manifest: "---\nname: a-manifest\ninstance_groups:\n- name: redis\n  instances: 3",
+			}, nil)
+		})
+
+		It("generates the manifest once and returns the difference without deploying it", func() {
+			diff, err := deployer.PreviewUpdate(context.Background(), deploymentName, secondPlanID, requestParams, previousPlanID, secretsMap, uaaClientMap, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.InstanceGroups).To(Equal([]broker.ManifestChange{
+				{Path: "/instance_groups/name=redis/instances", Before: 1, After: 3},
+			}))
+
+			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(1))
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+			Expect(generateManifestProps.PlanID).To(Equal(secondPlanID))
+			Expect(generateManifestProps.PreviousPlanID).To(Equal(previousPlanID))
+			Expect(generateManifestProps.SecretsMap).To(Equal(secretsMap))
+			Expect(generateManifestProps.RequestParams).To(Equal(requestParams))
+
+			Expect(boshClient.DeployCallCount()).To(BeZero())
+		})
+
+		It("redacts the secrets resolved for the adapter from the difference", func() {
+			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{
+				Manifest: "---\nname: a-manifest\ninstance_groups:\n- name: redis\n  instances: 1\n  properties:\n    auth: p4ssw0rd",
+			}, nil)
+
+			diff, err := deployer.PreviewUpdate(context.Background(), deploymentName, secondPlanID, requestParams, previousPlanID, secretsMap, uaaClientMap, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Properties).To(Equal([]broker.ManifestChange{
+				{Path: "/instance_groups/name=redis/properties/auth", After: broker.RedactedValue},
+			}))
+		})
+	})
+
 	Describe("Update", func() {
 		BeforeEach(func() {
 			oldManifest = []byte("---\nname: a-manifest\nupdate:\n canaries: 5\n max_in_flight: 1")
@@ -678,7 +841,7 @@ var _ = Describe("Deployer", func() {
 			for k, v := range params {
 				copyParams[k] = v
 			}
-			_, _, _, err := deployer.Update(
+			_, _, _, err := deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				params,
@@ -691,13 +854,13 @@ var _ = Describe("Deployer", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-			generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(1)
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(1)
 			Expect(generateManifestProps.RequestParams).To(Equal(copyParams))
 		})
 
 		Context("passing secret map", func() {
 			It("manifest regeneration is passed the secrets map", func() {
-				_, _, _, err := deployer.Update(
+				_, _, _, err := deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					nil,
@@ -711,7 +874,7 @@ var _ = Describe("Deployer", func() {
 
 				Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
 				for i := 0; i < 2; i++ {
-					generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(i)
+					_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(i)
 					Expect(generateManifestProps.SecretsMap).To(Equal(secretsMap), fmt.Sprintf("call %d", i+1))
 				}
 			})
@@ -722,7 +885,7 @@ var _ = Describe("Deployer", func() {
 				manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{}, errors.New("manifest fail"))
 			})
 			It("wraps the error", func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -741,7 +904,7 @@ var _ = Describe("Deployer", func() {
 			Context("and manifest generation succeeds", func() {
 				BeforeEach(func() {
 					requestParams = map[string]interface{}{"foo": "bar"}
-					manifestGenerator.GenerateManifestStub = func(
+					manifestGenerator.GenerateManifestStub = func(_ context.Context,
 						generateManifestProps task.GenerateManifestProperties,
 						_ *log.Logger,
 					) (serviceadapter.MarshalledGenerateManifest, error) {
@@ -759,7 +922,7 @@ var _ = Describe("Deployer", func() {
 
 					boshClient.DeployReturns(42, nil)
 
-					returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+					returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -774,32 +937,32 @@ var _ = Describe("Deployer", func() {
 				It("deploys successfully", func() {
 					By("checking incomplete tasks for the deployment")
 					Expect(boshClient.GetTasksInProgressCallCount()).To(Equal(1))
-					actualDeploymentName, _ := boshClient.GetTasksInProgressArgsForCall(0)
+					_, actualDeploymentName, _ := boshClient.GetTasksInProgressArgsForCall(0)
 					Expect(actualDeploymentName).To(Equal(deploymentName))
 
 					Expect(boshClient.GetDeploymentCallCount()).To(Equal(1))
-					actualDeploymentName, _ = boshClient.GetDeploymentArgsForCall(0)
+					_, actualDeploymentName, _ = boshClient.GetDeploymentArgsForCall(0)
 					Expect(actualDeploymentName).To(Equal(deploymentName))
 
 					Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
 
-					generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+					_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 					Expect(generateManifestProps.RequestParams).To(BeEmpty())
 					Expect(generateManifestProps.UAAClient).To(BeEmpty())
 
-					generateManifestProps, _ = manifestGenerator.GenerateManifestArgsForCall(1)
+					_, generateManifestProps, _ = manifestGenerator.GenerateManifestArgsForCall(1)
 					Expect(generateManifestProps.RequestParams).To(Equal(requestParams))
 					Expect(generateManifestProps.UAAClient).To(Equal(uaaClientMap))
 
 					Expect(boshClient.DeployCallCount()).To(Equal(1))
-					deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
+					_, deployedManifest, _, _, _ := boshClient.DeployArgsForCall(0)
 					Expect(string(deployedManifest)).To(Equal(string(generatedManifest)))
 
 					Expect(returnedTaskID).To(Equal(boshTaskID))
 				})
 
 				It("returns the labels from the service adapter", func() {
-					returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Update(
+					returnedTaskID, deployedManifest, actualLabels, deployError := deployer.Update(context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -820,7 +983,7 @@ var _ = Describe("Deployer", func() {
 					It("deploys successfully", func() {
 						requestParams = map[string]interface{}{}
 
-						returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+						returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 							deploymentName,
 							planID,
 							requestParams,
@@ -838,7 +1001,7 @@ var _ = Describe("Deployer", func() {
 
 			Context("and the manifest generator fails to generate the manifest the second time", func() {
 				BeforeEach(func() {
-					manifestGenerator.GenerateManifestStub = func(
+					manifestGenerator.GenerateManifestStub = func(_ context.Context,
 						generateManifestProps task.GenerateManifestProperties,
 						_ *log.Logger,
 					) (serviceadapter.MarshalledGenerateManifest, error) {
@@ -850,7 +1013,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("wraps the error", func() {
-					returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+					returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -873,7 +1036,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			JustBeforeEach(func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -920,7 +1083,7 @@ var _ = Describe("Deployer", func() {
 			odbSecrets.GenerateSecretPathsReturns(manifestSecrets)
 			odbSecrets.ReplaceODBRefsReturns(manifestWithInterpolatedSecrets)
 
-			_, deployedManifest, _, deployError = deployer.Update(
+			_, deployedManifest, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -940,7 +1103,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("returns a deployment not found error", func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -963,7 +1126,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("fails because deployment is still in progress", func() {
-					_, _, _, deployError = deployer.Update(
+					_, _, _, deployError = deployer.Update(context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -984,7 +1147,7 @@ var _ = Describe("Deployer", func() {
 				})
 
 				It("wraps the error", func() {
-					_, _, _, deployError = deployer.Update(
+					_, _, _, deployError = deployer.Update(context.Background(),
 						deploymentName,
 						planID,
 						requestParams,
@@ -1006,7 +1169,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("returns a deployment not found error", func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -1028,7 +1191,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			JustBeforeEach(func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -1041,7 +1204,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("sends the old configs to service adapter", func() {
-				generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 				Expect(generateManifestProps.PreviousConfigs).To(Equal(configsMap))
 			})
 		})
@@ -1052,7 +1215,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			JustBeforeEach(func() {
-				returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+				returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -1076,7 +1239,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("doesn't call UpdateConfig or GetConfigs", func() {
-				returnedTaskID, _, _, deployError = deployer.Update(
+				returnedTaskID, _, _, deployError = deployer.Update(context.Background(),
 					deploymentName,
 					planID,
 					requestParams,
@@ -1104,7 +1267,7 @@ var _ = Describe("Deployer", func() {
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 			boshClient.DeployReturns(42, nil)
 
-			returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+			returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1118,10 +1281,10 @@ var _ = Describe("Deployer", func() {
 			Expect(deployError).To(BeNil())
 
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-			generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(1)
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(1)
 			Expect(generateManifestProps.RequestParams).To(Equal(requestParams))
 
-			manifestToDeploy, _, _, _ := boshClient.DeployArgsForCall(0)
+			_, manifestToDeploy, _, _, _ := boshClient.DeployArgsForCall(0)
 			Expect(string(manifestToDeploy)).To(Equal(string(generatedManifest)))
 		})
 
@@ -1151,7 +1314,7 @@ instance_groups:
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 			boshClient.DeployReturns(42, nil)
 
-			returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+			returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1165,10 +1328,10 @@ instance_groups:
 			Expect(deployError).To(BeNil())
 
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-			generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(1)
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(1)
 			Expect(generateManifestProps.RequestParams).To(Equal(requestParams))
 
-			manifestToDeploy, _, _, _ := boshClient.DeployArgsForCall(0)
+			_, manifestToDeploy, _, _, _ := boshClient.DeployArgsForCall(0)
 			Expect(string(manifestToDeploy)).To(Equal(string(generatedManifest)))
 		})
 
@@ -1186,7 +1349,7 @@ tags:
 `)
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
-			_, _, _, deployError = deployer.Update(
+			_, _, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1216,7 +1379,7 @@ features:
 `)
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
-			_, _, _, deployError = deployer.Update(
+			_, _, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1246,7 +1409,7 @@ features:
 `)
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
-			_, _, _, deployError = deployer.Update(
+			_, _, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1284,7 +1447,7 @@ instance_groups:
 `)
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 
-			_, _, _, deployError = deployer.Update(
+			_, _, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1312,7 +1475,7 @@ instance_groups:
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifest)}, nil)
 			boshClient.DeployReturns(42, nil)
 
-			returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+			returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1339,7 +1502,7 @@ instance_groups:
 			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(generatedManifestWithInvalidYAML)}, nil)
 			boshClient.DeployReturns(42, nil)
 
-			returnedTaskID, deployedManifest, _, deployError = deployer.Update(
+			returnedTaskID, deployedManifest, _, deployError = deployer.Update(context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -1355,6 +1518,128 @@ instance_groups:
 		})
 	})
 
+	Describe("Rollback", func() {
+		BeforeEach(func() {
+			boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{}, nil)
+			boshClient.DeployReturns(boshTaskID, nil)
+		})
+
+		It("applies the configs and deploys the manifest", func() {
+			returnedTaskID, deployError = deployer.Rollback(context.Background(), deploymentName, []byte("name: known-good"), configsMap, "some-context-id", logger)
+
+			Expect(deployError).NotTo(HaveOccurred())
+			Expect(returnedTaskID).To(Equal(boshTaskID))
+
+			Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+			_, configType, configName, configContent, _ := boshClient.UpdateConfigArgsForCall(0)
+			Expect(configType).To(Equal("some-config-type"))
+			Expect(configName).To(Equal(deploymentName))
+			Expect(string(configContent)).To(Equal("some-config-content"))
+
+			Expect(boshClient.DeployCallCount()).To(Equal(1))
+			_, manifest, contextID, _, _ := boshClient.DeployArgsForCall(0)
+			Expect(string(manifest)).To(Equal("name: known-good"))
+			Expect(contextID).To(Equal("some-context-id"))
+			Expect(manifestGenerator.GenerateManifestCallCount()).To(BeZero())
+		})
+
+		It("removes the configs added since the deployment", func() {
+			boshClient.GetConfigsReturns([]boshdirector.BoshConfig{
+				{Type: "some-config-type", Content: "newer-content"},
+				{Type: "added-config-type", Content: "added-content"},
+				{Type: broker.InstanceMetadataConfigType, Content: "{}"},
+			}, nil)
+
+			_, deployError = deployer.Rollback(context.Background(), deploymentName, []byte("name: known-good"), configsMap, "", logger)
+
+			Expect(deployError).NotTo(HaveOccurred())
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+			_, configType, configName, _ := boshClient.DeleteConfigArgsForCall(0)
+			Expect(configType).To(Equal("added-config-type"))
+			Expect(configName).To(Equal(deploymentName))
+		})
+
+		It("does not apply configs when BOSH configs are disabled", func() {
+			deployer.DisableBoshConfigs = true
+
+			_, deployError = deployer.Rollback(context.Background(), deploymentName, []byte("name: known-good"), configsMap, "", logger)
+
+			Expect(deployError).NotTo(HaveOccurred())
+			Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+			Expect(boshClient.DeployCallCount()).To(Equal(1))
+		})
+
+		It("fails when an operation is in progress", func() {
+			boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{boshdirector.BoshTask{ID: 42}}, nil)
+
+			_, deployError = deployer.Rollback(context.Background(), deploymentName, []byte("name: known-good"), configsMap, "", logger)
+
+			Expect(deployError).To(BeAssignableToTypeOf(broker.TaskInProgressError{}))
+			Expect(boshClient.DeployCallCount()).To(BeZero())
+		})
+
+		It("does not deploy when a config cannot be applied", func() {
+			boshClient.UpdateConfigReturns(errors.New("config failed"))
+
+			_, deployError = deployer.Rollback(context.Background(), deploymentName, []byte("name: known-good"), configsMap, "", logger)
+
+			Expect(deployError).To(MatchError(ContainSubstring("config failed")))
+			Expect(boshClient.DeployCallCount()).To(BeZero())
+		})
+
+		It("returns an error when the deploy fails", func() {
+			boshClient.DeployReturns(0, errors.New("deploy failed"))
+
+			_, deployError = deployer.Rollback(context.Background(), deploymentName, []byte("name: known-good"), configsMap, "", logger)
+
+			Expect(deployError).To(MatchError(ContainSubstring("deploy failed")))
+		})
+	})
+
+	Describe("CheckDeployable", func() {
+		BeforeEach(func() {
+			oldManifest = []byte("---\nname: a-manifest")
+			boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{}, nil)
+			boshClient.GetDeploymentReturns(oldManifest, true, nil)
+			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: string(oldManifest)}, nil)
+		})
+
+		It("succeeds when the deployment has no pending changes", func() {
+			err := deployer.CheckDeployable(context.Background(), deploymentName, existingPlanID, secretsMap, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			_, generateManifestProps, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+			Expect(generateManifestProps.PlanID).To(Equal(existingPlanID))
+			Expect(generateManifestProps.SecretsMap).To(Equal(secretsMap))
+			Expect(boshClient.DeployCallCount()).To(BeZero())
+		})
+
+		It("fails when an operation is in progress", func() {
+			boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{boshdirector.BoshTask{ID: 42}}, nil)
+
+			err := deployer.CheckDeployable(context.Background(), deploymentName, existingPlanID, secretsMap, logger)
+
+			Expect(err).To(BeAssignableToTypeOf(broker.TaskInProgressError{}))
+		})
+
+		It("fails when the deployment has pending changes", func() {
+			manifestGenerator.GenerateManifestReturns(serviceadapter.MarshalledGenerateManifest{Manifest: "---\nname: another-manifest"}, nil)
+
+			err := deployer.CheckDeployable(context.Background(), deploymentName, existingPlanID, secretsMap, logger)
+
+			Expect(err).To(BeAssignableToTypeOf(broker.PendingChangesNotAppliedError{}))
+		})
+
+		It("does not check for pending changes when configured not to", func() {
+			deployer.SkipCheckForPendingChanges = true
+
+			err := deployer.CheckDeployable(context.Background(), deploymentName, existingPlanID, secretsMap, logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifestGenerator.GenerateManifestCallCount()).To(BeZero())
+		})
+	})
+
 	Describe("Recreate", func() {
 		var err error
 
@@ -1362,7 +1647,7 @@ instance_groups:
 			boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{}, nil)
 			boshClient.RecreateReturns(42, nil)
 
-			returnedTaskID, err = deployer.Recreate(deploymentName, planID, boshContextID, logger)
+			returnedTaskID, err = deployer.Recreate(context.Background(), deploymentName, planID, boshContextID, logger)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(boshClient.RecreateCallCount()).To(Equal(1), "recreate was not called once")
@@ -1376,7 +1661,7 @@ instance_groups:
 				})
 
 				It("fails because deployment is still in progress", func() {
-					_, deployError = deployer.Recreate(deploymentName, planID, boshContextID, logger)
+					_, deployError = deployer.Recreate(context.Background(), deploymentName, planID, boshContextID, logger)
 
 					Expect(deployError).To(BeAssignableToTypeOf(broker.TaskInProgressError{}))
 				})
@@ -1388,7 +1673,7 @@ instance_groups:
 				})
 
 				It("wraps the error", func() {
-					_, deployError = deployer.Recreate(deploymentName, planID, boshContextID, logger)
+					_, deployError = deployer.Recreate(context.Background(), deploymentName, planID, boshContextID, logger)
 
 					Expect(deployError).To(MatchError(fmt.Sprintf("error getting tasks for deployment %s: connection error\n", deploymentName)))
 				})
@@ -1400,7 +1685,7 @@ instance_groups:
 				boshClient.GetTasksInProgressReturns(boshdirector.BoshTasks{}, nil)
 				boshClient.RecreateReturns(0, errors.New("zork"))
 
-				_, err = deployer.Recreate(deploymentName, planID, boshContextID, logger)
+				_, err = deployer.Recreate(context.Background(), deploymentName, planID, boshContextID, logger)
 			})
 
 			It("wraps the error", func() {
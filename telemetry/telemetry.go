@@ -7,6 +7,7 @@ import (
 
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	. "github.com/pivotal-cf/on-demand-service-broker/service"
 )
 
@@ -85,7 +86,7 @@ func NewTelemetryLogger(logger *log.Logger, serviceOffering config.ServiceOfferi
 func (t *TelemetryLogger) LogInstances(instanceLister InstanceLister, item, operation string) {
 	allInstances, err := instanceLister.Instances(nil)
 	if err != nil {
-		t.logger.Printf("Failed to query list of instances for telemetry (cause: %s). Skipping total instances log.", err)
+		loggerfactory.WithLevel(t.logger, loggerfactory.ErrorLevel).Printf("Failed to query list of instances for telemetry (cause: %s). Skipping total instances log.", err)
 	} else {
 		event := Event{Item: item, Operation: operation}
 		t.logTotalInstances(allInstances, event)
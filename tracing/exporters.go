@@ -0,0 +1,308 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+// NewExporter creates the exporter configured for the broker. It returns nil
+// when tracing is not enabled.
+func NewExporter(conf config.Tracing, serviceName string, logger *log.Logger) (Exporter, error) {
+	switch conf.Exporter {
+	case "":
+		return nil, nil
+	case config.TracingExporterStdout:
+		return NewWriterExporter(os.Stdout), nil
+	case config.TracingExporterFile:
+		file, err := os.OpenFile(conf.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("error opening trace file: %s", err)
+		}
+		return NewWriterExporter(file), nil
+	case config.TracingExporterOTLP:
+		return NewOTLPExporter(conf.OTLPEndpoint, serviceName, &http.Client{Timeout: 10 * time.Second}, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", conf.Exporter)
+	}
+}
+
+// WriterExporter writes each span as a line of JSON.
+type WriterExporter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewWriterExporter(out io.Writer) *WriterExporter {
+	return &WriterExporter{out: out}
+}
+
+type spanLine struct {
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	Name         string                 `json:"name"`
+	StartTime    string                 `json:"start_time"`
+	EndTime      string                 `json:"end_time"`
+	DurationMS   int64                  `json:"duration_ms"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+func (e *WriterExporter) ExportSpan(span *Span) {
+	line, err := json.Marshal(spanLine{
+		TraceID:      span.TraceID,
+		SpanID:       span.SpanID,
+		ParentSpanID: span.ParentSpanID,
+		Name:         span.Name,
+		StartTime:    span.StartTime.UTC().Format(time.RFC3339Nano),
+		EndTime:      span.EndTime.UTC().Format(time.RFC3339Nano),
+		DurationMS:   span.Duration().Milliseconds(),
+		Attributes:   span.Attributes,
+		Error:        span.Error,
+	})
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.out.Write(append(line, '\n'))
+}
+
+// OTLPExporter sends spans to an OpenTelemetry collector with the OTLP/HTTP
+// JSON protocol. Spans are sent in batches, so that ending a span does not
+// wait for the collector.
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+	logger      *log.Logger
+
+	mu      sync.Mutex
+	pending []*Span
+	flushes chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+const (
+	otlpBatchSize     = 256
+	otlpFlushInterval = 5 * time.Second
+)
+
+func NewOTLPExporter(endpoint, serviceName string, client *http.Client, logger *log.Logger) *OTLPExporter {
+	e := &OTLPExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		serviceName: serviceName,
+		client:      client,
+		logger:      logger,
+		flushes:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+func (e *OTLPExporter) ExportSpan(span *Span) {
+	e.mu.Lock()
+	e.pending = append(e.pending, span)
+	full := len(e.pending) >= otlpBatchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flushes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Shutdown sends the spans not yet sent and stops the exporter.
+func (e *OTLPExporter) Shutdown() {
+	close(e.done)
+	<-e.stopped
+}
+
+func (e *OTLPExporter) run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.flush()
+		case <-e.flushes:
+			e.flush()
+		case <-e.done:
+			e.flush()
+			return
+		}
+	}
+}
+
+func (e *OTLPExporter) flush() {
+	e.mu.Lock()
+	spans := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	if err := e.send(spans); err != nil {
+		loggerfactory.WithLevel(e.logger, loggerfactory.ErrorLevel).Printf("error exporting %d spans: %s\n", len(spans), err)
+	}
+}
+
+func (e *OTLPExporter) send(spans []*Span) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	res, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("collector %s responded with status %d", e.url, res.StatusCode)
+	}
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusCodeUnset  = 0
+	otlpStatusCodeError  = 2
+)
+
+func (e *OTLPExporter) request(spans []*Span) otlpRequest {
+	var otlpSpans []otlpSpan
+	for _, span := range spans {
+		status := otlpStatus{Code: otlpStatusCodeUnset}
+		if span.Error != "" {
+			status = otlpStatus{Code: otlpStatusCodeError, Message: span.Error}
+		}
+
+		otlpSpans = append(otlpSpans, otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            status,
+		})
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: otlpAttributes(map[string]interface{}{"service.name": e.serviceName})},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/pivotal-cf/on-demand-service-broker/tracing"},
+			Spans: otlpSpans,
+		}},
+	}}}
+}
+
+func otlpAttributes(attributes map[string]interface{}) []otlpAttribute {
+	var keys []string
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result []otlpAttribute
+	for _, key := range keys {
+		var value otlpAnyValue
+		switch v := attributes[key].(type) {
+		case int:
+			s := strconv.Itoa(v)
+			value.IntValue = &s
+		case bool:
+			value.BoolValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+		result = append(result, otlpAttribute{Key: key, Value: value})
+	}
+	return result
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package tracing_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+)
+
+var _ = Describe("Exporters", func() {
+	var span *tracing.Span
+
+	BeforeEach(func() {
+		start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		span = &tracing.Span{
+			Name:         "bosh.deploy",
+			TraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:       "00f067aa0ba902b7",
+			ParentSpanID: "b7ad6b7169203331",
+			Sampled:      true,
+			StartTime:    start,
+			EndTime:      start.Add(1500 * time.Millisecond),
+			Attributes:   map[string]interface{}{"bosh_task_id": 42},
+			Error:        "oops",
+		}
+	})
+
+	Describe("WriterExporter", func() {
+		It("writes each span as a line of JSON", func() {
+			out := &bytes.Buffer{}
+			tracing.NewWriterExporter(out).ExportSpan(span)
+
+			Expect(out.String()).To(HaveSuffix("}\n"))
+			Expect(out.String()).To(MatchJSON(`{
+				"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+				"span_id": "00f067aa0ba902b7",
+				"parent_span_id": "b7ad6b7169203331",
+				"name": "bosh.deploy",
+				"start_time": "2024-03-01T12:00:00Z",
+				"end_time": "2024-03-01T12:00:01.5Z",
+				"duration_ms": 1500,
+				"attributes": {"bosh_task_id": 42},
+				"error": "oops"
+			}`))
+		})
+	})
+
+	Describe("OTLPExporter", func() {
+		var (
+			server   *httptest.Server
+			mu       sync.Mutex
+			requests [][]byte
+			status   int
+			logs     *gbytes.Buffer
+		)
+
+		received := func() [][]byte {
+			mu.Lock()
+			defer mu.Unlock()
+			return requests
+		}
+
+		BeforeEach(func() {
+			requests = nil
+			status = http.StatusOK
+			logs = gbytes.NewBuffer()
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/v1/traces"))
+				Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+				body, err := io.ReadAll(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				mu.Lock()
+				requests = append(requests, body)
+				mu.Unlock()
+				w.WriteHeader(status)
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("sends the spans not yet sent when it shuts down", func() {
+			exporter := tracing.NewOTLPExporter(server.URL+"/", "on-demand-service-broker", server.Client(), log.New(logs, "", 0))
+			exporter.ExportSpan(span)
+			exporter.Shutdown()
+
+			Expect(received()).To(HaveLen(1))
+			Expect(received()[0]).To(MatchJSON(`{
+				"resourceSpans": [{
+					"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "on-demand-service-broker"}}]},
+					"scopeSpans": [{
+						"scope": {"name": "github.com/pivotal-cf/on-demand-service-broker/tracing"},
+						"spans": [{
+							"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+							"spanId": "00f067aa0ba902b7",
+							"parentSpanId": "b7ad6b7169203331",
+							"name": "bosh.deploy",
+							"kind": 1,
+							"startTimeUnixNano": "1709294400000000000",
+							"endTimeUnixNano": "1709294401500000000",
+							"attributes": [{"key": "bosh_task_id", "value": {"intValue": "42"}}],
+							"status": {"code": 2, "message": "oops"}
+						}]
+					}]
+				}]
+			}`))
+		})
+
+		It("does not send anything when there are no spans", func() {
+			exporter := tracing.NewOTLPExporter(server.URL, "on-demand-service-broker", server.Client(), log.New(logs, "", 0))
+			exporter.Shutdown()
+
+			Expect(received()).To(BeEmpty())
+		})
+
+		It("logs when the collector rejects the spans", func() {
+			status = http.StatusBadRequest
+
+			exporter := tracing.NewOTLPExporter(server.URL, "on-demand-service-broker", server.Client(), log.New(logs, "", 0))
+			exporter.ExportSpan(span)
+			exporter.Shutdown()
+
+			Expect(logs).To(gbytes.Say("error exporting 1 spans: collector .* responded with status 400"))
+		})
+	})
+
+	Describe("NewExporter", func() {
+		It("is disabled when no exporter is configured", func() {
+			exporter, err := tracing.NewExporter(config.Tracing{}, "some-name", log.New(io.Discard, "", 0))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exporter).To(BeNil())
+		})
+
+		It("writes spans to a file", func() {
+			path := GinkgoT().TempDir() + "/traces.json"
+
+			exporter, err := tracing.NewExporter(config.Tracing{Exporter: "file", File: path}, "some-name", log.New(io.Discard, "", 0))
+			Expect(err).NotTo(HaveOccurred())
+			exporter.ExportSpan(span)
+
+			contents, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			var line map[string]interface{}
+			Expect(json.Unmarshal(contents, &line)).To(Succeed())
+			Expect(line).To(HaveKeyWithValue("name", "bosh.deploy"))
+		})
+
+		It("fails for an unknown exporter", func() {
+			_, err := tracing.NewExporter(config.Tracing{Exporter: "zipkin"}, "some-name", log.New(io.Discard, "", 0))
+
+			Expect(err).To(MatchError(`unknown trace exporter "zipkin"`))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header.
+const TraceparentHeader = "traceparent"
+
+// TraceparentEnv is the environment variable that carries the traceparent to
+// processes the broker executes, such as the service adapter.
+const TraceparentEnv = "TRACEPARENT"
+
+// Extract returns ctx with the span context of the traceparent header, so that
+// spans started from it continue the caller's trace. An invalid header is
+// ignored.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	spanContext, ok := ParseTraceparent(header.Get(TraceparentHeader))
+	if !ok {
+		return ctx
+	}
+	return ContextWithSpanContext(ctx, spanContext)
+}
+
+// Inject sets the traceparent header to the span context in ctx.
+func Inject(ctx context.Context, header http.Header) {
+	if spanContext := SpanContextFromContext(ctx); spanContext.IsValid() {
+		header.Set(TraceparentHeader, Traceparent(spanContext))
+	}
+}
+
+// InjectEnv returns env with the TRACEPARENT variable set to the span context
+// in ctx.
+func InjectEnv(ctx context.Context, env []string) []string {
+	if spanContext := SpanContextFromContext(ctx); spanContext.IsValid() {
+		return append(env, TraceparentEnv+"="+Traceparent(spanContext))
+	}
+	return env
+}
+
+func Traceparent(spanContext SpanContext) string {
+	flags := "00"
+	if spanContext.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", spanContext.TraceID, spanContext.SpanID, flags)
+}
+
+// ParseTraceparent parses a version 00 traceparent header value.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return SpanContext{}, false
+	}
+
+	traceID, spanID, flags := parts[1], parts[2], parts[3]
+	if !isHex(traceID, 16) || !isHex(spanID, 8) || !isHex(flags, 1) {
+		return SpanContext{}, false
+	}
+	if traceID == strings.Repeat("0", 32) || spanID == strings.Repeat("0", 16) {
+		return SpanContext{}, false
+	}
+
+	flagBits, _ := hex.DecodeString(flags)
+	return SpanContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flagBits[0]&1 == 1,
+	}, true
+}
+
+func isHex(s string, bytes int) bool {
+	if len(s) != bytes*2 || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+)
+
+var _ = Describe("Propagation", func() {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	It("continues the trace of the traceparent header", func() {
+		header := http.Header{}
+		header.Set("traceparent", "00-"+traceID+"-"+spanID+"-01")
+
+		ctx := tracing.Extract(context.Background(), header)
+		_, span := tracing.Start(ctx, "some-span")
+
+		Expect(span.TraceID).To(Equal(traceID))
+		Expect(span.ParentSpanID).To(Equal(spanID))
+		Expect(span.Sampled).To(BeTrue())
+	})
+
+	It("sets the traceparent header of the span in the context", func() {
+		ctx, span := tracing.Start(context.Background(), "some-span")
+		header := http.Header{}
+
+		tracing.Inject(ctx, header)
+
+		Expect(header.Get("traceparent")).To(Equal("00-" + span.TraceID + "-" + span.SpanID + "-01"))
+	})
+
+	It("sets the TRACEPARENT variable of the span in the context", func() {
+		ctx, span := tracing.Start(context.Background(), "some-span")
+
+		env := tracing.InjectEnv(ctx, []string{"PATH=/bin"})
+
+		Expect(env).To(Equal([]string{"PATH=/bin", "TRACEPARENT=00-" + span.TraceID + "-" + span.SpanID + "-01"}))
+	})
+
+	It("leaves the environment alone when there is no span", func() {
+		Expect(tracing.InjectEnv(context.Background(), []string{"PATH=/bin"})).To(Equal([]string{"PATH=/bin"}))
+	})
+
+	DescribeTable("parsing traceparent headers",
+		func(value string, valid, sampled bool) {
+			spanContext, ok := tracing.ParseTraceparent(value)
+
+			Expect(ok).To(Equal(valid))
+			if valid {
+				Expect(spanContext).To(Equal(tracing.SpanContext{TraceID: traceID, SpanID: spanID, Sampled: sampled}))
+			}
+		},
+		Entry("sampled", "00-"+traceID+"-"+spanID+"-01", true, true),
+		Entry("not sampled", "00-"+traceID+"-"+spanID+"-00", true, false),
+		Entry("empty", "", false, false),
+		Entry("unknown version", "01-"+traceID+"-"+spanID+"-01", false, false),
+		Entry("short trace ID", "00-4bf92f35-"+spanID+"-01", false, false),
+		Entry("upper case", "00-4BF92F3577B34DA6A3CE929D0E0E4736-"+spanID+"-01", false, false),
+		Entry("all zero trace ID", "00-00000000000000000000000000000000-"+spanID+"-01", false, false),
+		Entry("all zero span ID", "00-"+traceID+"-0000000000000000-01", false, false),
+	)
+
+	It("ignores an invalid header", func() {
+		header := http.Header{}
+		header.Set("traceparent", "not-a-traceparent")
+
+		ctx := tracing.Extract(context.Background(), header)
+
+		Expect(tracing.SpanContextFromContext(ctx).IsValid()).To(BeFalse())
+	})
+})
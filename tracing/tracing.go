@@ -0,0 +1,169 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+// Package tracing records spans around the work done for a request, in the
+// shape of OpenTelemetry spans, and exports them once they end.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+type contextKey int
+
+const spanContextKey contextKey = 0
+
+// SpanContext identifies a span, and the trace it is part of, across process
+// boundaries.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+func (s SpanContext) IsValid() bool {
+	return s.TraceID != "" && s.SpanID != ""
+}
+
+// Span is a timed piece of work. A span is exported when it ends.
+type Span struct {
+	Name         string
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Sampled      bool
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+	Error        string
+
+	mu sync.Mutex
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+//counterfeiter:generate -o fakes/fake_exporter.go . Exporter
+type Exporter interface {
+	ExportSpan(span *Span)
+}
+
+var (
+	exporterMu sync.RWMutex
+	exporter   Exporter
+)
+
+// SetExporter sets where ended spans are sent. Spans are still created and
+// propagated when no exporter is set, but are discarded when they end.
+func SetExporter(e Exporter) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+	exporter = e
+}
+
+func currentExporter() Exporter {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+	return exporter
+}
+
+// Start starts a span as a child of the span in ctx. When ctx holds no span, a
+// new trace is started.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	span := &Span{
+		Name:       name,
+		Sampled:    true,
+		StartTime:  time.Now(),
+		Attributes: map[string]interface{}{},
+		SpanID:     newID(8),
+	}
+
+	if parent := SpanContextFromContext(ctx); parent.IsValid() {
+		span.TraceID = parent.TraceID
+		span.ParentSpanID = parent.SpanID
+		span.Sampled = parent.Sampled
+	} else {
+		span.TraceID = newID(16)
+	}
+
+	return ContextWithSpanContext(ctx, span.SpanContext()), span
+}
+
+func (s *Span) SpanContext() SpanContext {
+	return SpanContext{TraceID: s.TraceID, SpanID: s.SpanID, Sampled: s.Sampled}
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// End records the outcome of the span and exports it. Ending a span more than
+// once has no effect.
+func (s *Span) End(err error) {
+	s.mu.Lock()
+	if !s.EndTime.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.EndTime = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	ended := s.snapshot()
+	s.mu.Unlock()
+
+	if e := currentExporter(); e != nil && ended.Sampled {
+		e.ExportSpan(ended)
+	}
+}
+
+// snapshot copies the span for exporting, so that exporters can read it while
+// attributes are still being set on the span. It must be called with mu held.
+func (s *Span) snapshot() *Span {
+	attributes := make(map[string]interface{}, len(s.Attributes))
+	for key, value := range s.Attributes {
+		attributes[key] = value
+	}
+	return &Span{
+		Name:         s.Name,
+		TraceID:      s.TraceID,
+		SpanID:       s.SpanID,
+		ParentSpanID: s.ParentSpanID,
+		Sampled:      s.Sampled,
+		StartTime:    s.StartTime,
+		EndTime:      s.EndTime,
+		Attributes:   attributes,
+		Error:        s.Error,
+	}
+}
+
+func (s *Span) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+func ContextWithSpanContext(ctx context.Context, spanContext SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey, spanContext)
+}
+
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	spanContext, _ := ctx.Value(spanContextKey).(SpanContext)
+	return spanContext
+}
+
+func newID(bytes int) string {
+	id := make([]byte, bytes)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/pivotal-cf/on-demand-service-broker/tracing"
+	"github.com/pivotal-cf/on-demand-service-broker/tracing/fakes"
+)
+
+var _ = Describe("Tracing", func() {
+	var exporter *fakes.FakeExporter
+
+	BeforeEach(func() {
+		exporter = new(fakes.FakeExporter)
+		tracing.SetExporter(exporter)
+	})
+
+	AfterEach(func() {
+		tracing.SetExporter(nil)
+	})
+
+	It("starts a new trace when there is no parent span", func() {
+		ctx, span := tracing.Start(context.Background(), "some-span")
+
+		Expect(span.TraceID).To(MatchRegexp(`^[0-9a-f]{32}$`))
+		Expect(span.SpanID).To(MatchRegexp(`^[0-9a-f]{16}$`))
+		Expect(span.ParentSpanID).To(BeEmpty())
+		Expect(tracing.SpanContextFromContext(ctx)).To(Equal(span.SpanContext()))
+	})
+
+	It("starts child spans in the same trace", func() {
+		ctx, parent := tracing.Start(context.Background(), "parent")
+		_, child := tracing.Start(ctx, "child")
+
+		Expect(child.TraceID).To(Equal(parent.TraceID))
+		Expect(child.ParentSpanID).To(Equal(parent.SpanID))
+		Expect(child.SpanID).NotTo(Equal(parent.SpanID))
+	})
+
+	It("exports a span once when it ends", func() {
+		_, span := tracing.Start(context.Background(), "some-span")
+		span.SetAttribute("bosh_task_id", 42)
+
+		span.End(errors.New("oops"))
+		span.End(nil)
+
+		Expect(exporter.ExportSpanCallCount()).To(Equal(1))
+		exported := exporter.ExportSpanArgsForCall(0)
+		Expect(exported.Name).To(Equal("some-span"))
+		Expect(exported.Error).To(Equal("oops"))
+		Expect(exported.Attributes).To(HaveKeyWithValue("bosh_task_id", 42))
+		Expect(exported.EndTime).NotTo(BeTemporally("<", exported.StartTime))
+	})
+
+	It("does not export spans of traces the caller did not sample", func() {
+		ctx := tracing.ContextWithSpanContext(context.Background(), tracing.SpanContext{
+			TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:  "00f067aa0ba902b7",
+		})
+		_, span := tracing.Start(ctx, "some-span")
+		span.End(nil)
+
+		Expect(exporter.ExportSpanCallCount()).To(BeZero())
+	})
+
+	It("discards spans when there is no exporter", func() {
+		tracing.SetExporter(nil)
+
+		_, span := tracing.Start(context.Background(), "some-span")
+
+		Expect(func() { span.End(nil) }).NotTo(Panic())
+	})
+
+	It("exports a copy of the span that setting attributes afterwards does not change", func() {
+		_, span := tracing.Start(context.Background(), "some-span")
+		span.SetAttribute("bosh_task_id", 42)
+		span.End(nil)
+
+		span.SetAttribute("bosh_task_id", 43)
+
+		exported := exporter.ExportSpanArgsForCall(0)
+		Expect(exported).NotTo(BeIdenticalTo(span))
+		Expect(exported.Attributes).To(HaveKeyWithValue("bosh_task_id", 42))
+	})
+})